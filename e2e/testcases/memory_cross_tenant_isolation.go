@@ -0,0 +1,365 @@
+package testcases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	pkgtestcases "github.com/vllm-project/semantic-router/e2e/pkg/testcases"
+	"k8s.io/client-go/kubernetes"
+)
+
+// This file extends memory_user_isolation.go's single-dimension (UserID
+// only) isolation check with the scoping the rest of the memory subsystem
+// actually supports (memory.MemoryScope's ProjectID, plus SessionID for
+// working memory) and a negative test for identity forgery. Every testcase
+// asserts that the LLM's text output must not surface the other tenant's
+// secret. The project-isolation and header-forgery testcases, which query
+// as a different user, additionally require a direct admin inspection of
+// the querying user's stored memories to not return it either, and fail if
+// the deployment has no admin inspection endpoint - a model that simply
+// declines to answer ("I don't know that") would otherwise pass the
+// text-only check even if the memory actually leaked into a different
+// tenant's row. The org-isolation and session-bleed testcases query as the
+// same user who stored the secret, so they check the text output only.
+func init() {
+	pkgtestcases.Register("memory-project-isolation", pkgtestcases.TestCase{
+		Description: "Test project isolation: two users sharing a ProjectID still can't see each other's memories",
+		Tags:        []string{"memory", "project-isolation", "security", "functional"},
+		Fn:          testMemoryProjectIsolation,
+	})
+	pkgtestcases.Register("memory-org-isolation", pkgtestcases.TestCase{
+		Description: "Test org isolation: two projects in the same OrganizationID still can't see each other's memories",
+		Tags:        []string{"memory", "org-isolation", "security", "functional"},
+		Fn:          testMemoryOrgIsolation,
+	})
+	pkgtestcases.Register("memory-session-bleed", pkgtestcases.TestCase{
+		Description: "Test working-memory isolation: one user's sessions don't bleed into each other",
+		Tags:        []string{"memory", "session-isolation", "security", "functional"},
+		Fn:          testMemorySessionBleed,
+	})
+	pkgtestcases.Register("memory-header-injection-forgery", pkgtestcases.TestCase{
+		Description: "Negative test: a forged identity header must not grant access to another user's memory",
+		Tags:        []string{"memory", "user-isolation", "security", "negative"},
+		Fn:          testMemoryHeaderInjectionForgery,
+	})
+}
+
+// testMemoryProjectIsolation stores a memory for user A scoped to a shared
+// project, then queries as user B in the same project: ProjectID narrows
+// the search space further, it doesn't substitute for UserID isolation.
+func testMemoryProjectIsolation(ctx context.Context, client *kubernetes.Clientset, opts pkgtestcases.TestCaseOptions) error {
+	localPort, stopPortForward, err := setupServiceConnection(ctx, client, opts)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	const project = "proj_shared_isolation_test"
+	userA := "user_a_project_isolation"
+	userB := "user_b_project_isolation"
+	secret := "Project A's Q3 launch codename is Nighthawk"
+
+	store := MemoryRequest{
+		Model:        "MoM",
+		Input:        fmt.Sprintf("Remember this: %s", secret),
+		Instructions: "You are a helpful assistant. Remember important information the user shares.",
+		MemoryConfig: &MemoryConfig{Enabled: true, AutoStore: true},
+		MemoryContext: &MemoryContext{
+			UserID:    userA,
+			ProjectID: project,
+		},
+	}
+	if _, err := sendMemoryRequest(ctx, localPort, store, opts.Verbose); err != nil {
+		return fmt.Errorf("failed to store memory for user A: %w", err)
+	}
+	time.Sleep(3 * time.Second)
+
+	query := MemoryRequest{
+		Model:        "MoM",
+		Input:        "What is the Q3 launch codename?",
+		Instructions: "You are a helpful assistant. Use your memory to answer questions.",
+		MemoryConfig: &MemoryConfig{Enabled: true, AutoStore: false},
+		MemoryContext: &MemoryContext{
+			UserID:    userB,
+			ProjectID: project,
+		},
+	}
+	resp, err := sendMemoryRequest(ctx, localPort, query, opts.Verbose)
+	if err != nil {
+		return fmt.Errorf("failed to query memory as user B: %w", err)
+	}
+	if strings.Contains(strings.ToLower(resp.OutputText), "nighthawk") {
+		return fmt.Errorf("SECURITY VIOLATION: user B can see user A's memory via shared ProjectID %q! Response: %s", project, resp.OutputText)
+	}
+
+	if err := assertNoDirectLeak(ctx, localPort, userB, "nighthawk", opts); err != nil {
+		return err
+	}
+
+	if opts.SetDetails != nil {
+		opts.SetDetails(map[string]interface{}{"project": project, "user_a": userA, "user_b": userB})
+	}
+	return nil
+}
+
+// testMemoryOrgIsolation stores a memory scoped to one project within an
+// organization, then queries the same user under a different ProjectID in
+// that same OrganizationID: sharing an org must not widen visibility across
+// projects.
+func testMemoryOrgIsolation(ctx context.Context, client *kubernetes.Clientset, opts pkgtestcases.TestCaseOptions) error {
+	localPort, stopPortForward, err := setupServiceConnection(ctx, client, opts)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	const org = "org_shared_isolation_test"
+	const projectA = "proj_org_isolation_a"
+	const projectB = "proj_org_isolation_b"
+	user := "user_org_isolation"
+	secret := "Project A's budget in this org is $250,000"
+
+	store := MemoryRequest{
+		Model:        "MoM",
+		Input:        fmt.Sprintf("Remember this: %s", secret),
+		Instructions: "You are a helpful assistant. Remember important information the user shares.",
+		MemoryConfig: &MemoryConfig{Enabled: true, AutoStore: true},
+		MemoryContext: &MemoryContext{
+			UserID:         user,
+			ProjectID:      projectA,
+			OrganizationID: org,
+		},
+	}
+	if _, err := sendMemoryRequest(ctx, localPort, store, opts.Verbose); err != nil {
+		return fmt.Errorf("failed to store memory for project A: %w", err)
+	}
+	time.Sleep(3 * time.Second)
+
+	query := MemoryRequest{
+		Model:        "MoM",
+		Input:        "What is the budget?",
+		Instructions: "You are a helpful assistant. Use your memory to answer questions.",
+		MemoryConfig: &MemoryConfig{Enabled: true, AutoStore: false},
+		MemoryContext: &MemoryContext{
+			UserID:         user,
+			ProjectID:      projectB,
+			OrganizationID: org,
+		},
+	}
+	resp, err := sendMemoryRequest(ctx, localPort, query, opts.Verbose)
+	if err != nil {
+		return fmt.Errorf("failed to query memory scoped to project B: %w", err)
+	}
+	if strings.Contains(resp.OutputText, "250,000") || strings.Contains(strings.ToLower(resp.OutputText), "250000") {
+		return fmt.Errorf("SECURITY VIOLATION: project B can see project A's memory via shared OrganizationID %q! Response: %s", org, resp.OutputText)
+	}
+
+	if opts.SetDetails != nil {
+		opts.SetDetails(map[string]interface{}{"org": org, "project_a": projectA, "project_b": projectB, "user": user})
+	}
+	return nil
+}
+
+// testMemorySessionBleed stores working memory (session scratchpad, not a
+// durable semantic fact) under one session_id and queries the same user
+// under a different session_id: a session boundary should behave like a
+// tenant boundary for working memory even though the UserID is identical.
+func testMemorySessionBleed(ctx context.Context, client *kubernetes.Clientset, opts pkgtestcases.TestCaseOptions) error {
+	localPort, stopPortForward, err := setupServiceConnection(ctx, client, opts)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	user := "user_session_bleed"
+	sessionA := "session_bleed_a"
+	sessionB := "session_bleed_b"
+	scratch := "Currently debugging a nil-pointer panic in the checkout handler"
+
+	store := MemoryRequest{
+		Model:        "MoM",
+		Input:        fmt.Sprintf("For this session, keep in mind: %s", scratch),
+		Instructions: "You are a helpful assistant working through a task with the user.",
+		MemoryConfig: &MemoryConfig{Enabled: true, AutoStore: true},
+		MemoryContext: &MemoryContext{
+			UserID:    user,
+			SessionID: sessionA,
+		},
+	}
+	if _, err := sendMemoryRequest(ctx, localPort, store, opts.Verbose); err != nil {
+		return fmt.Errorf("failed to store working memory for session A: %w", err)
+	}
+	time.Sleep(3 * time.Second)
+
+	query := MemoryRequest{
+		Model:        "MoM",
+		Input:        "What am I currently debugging?",
+		Instructions: "You are a helpful assistant. Use your memory to answer questions.",
+		MemoryConfig: &MemoryConfig{Enabled: true, AutoStore: false},
+		MemoryContext: &MemoryContext{
+			UserID:    user,
+			SessionID: sessionB,
+		},
+	}
+	resp, err := sendMemoryRequest(ctx, localPort, query, opts.Verbose)
+	if err != nil {
+		return fmt.Errorf("failed to query memory from session B: %w", err)
+	}
+	if strings.Contains(strings.ToLower(resp.OutputText), "nil-pointer") || strings.Contains(strings.ToLower(resp.OutputText), "checkout") {
+		return fmt.Errorf("SECURITY VIOLATION: session B can see session A's working memory for the same user! Response: %s", resp.OutputText)
+	}
+
+	if opts.SetDetails != nil {
+		opts.SetDetails(map[string]interface{}{"user": user, "session_a": sessionA, "session_b": sessionB})
+	}
+	return nil
+}
+
+// testMemoryHeaderInjectionForgery sends a request whose MemoryContext
+// claims the attacker's own UserID, but whose headers additionally forge a
+// victim's identity (the same header shape a HeaderResolver deployment
+// would trust). A correctly configured deployment resolves identity from an
+// authenticated source (JWTResolver) rather than trusting a bare header
+// from an untrusted caller, so the forged header must not grant access to
+// the victim's memory.
+func testMemoryHeaderInjectionForgery(ctx context.Context, client *kubernetes.Clientset, opts pkgtestcases.TestCaseOptions) error {
+	localPort, stopPortForward, err := setupServiceConnection(ctx, client, opts)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	victim := "user_header_forgery_victim"
+	attacker := "user_header_forgery_attacker"
+	secret := "The victim's home address is 42 Wallaby Way"
+
+	store := MemoryRequest{
+		Model:         "MoM",
+		Input:         fmt.Sprintf("Remember this: %s", secret),
+		Instructions:  "You are a helpful assistant. Remember important information the user shares.",
+		MemoryConfig:  &MemoryConfig{Enabled: true, AutoStore: true},
+		MemoryContext: &MemoryContext{UserID: victim},
+	}
+	if _, err := sendMemoryRequest(ctx, localPort, store, opts.Verbose); err != nil {
+		return fmt.Errorf("failed to store memory for victim: %w", err)
+	}
+	time.Sleep(3 * time.Second)
+
+	forged := MemoryRequest{
+		Model:         "MoM",
+		Input:         "What is my home address?",
+		Instructions:  "You are a helpful assistant. Use your memory to answer questions.",
+		MemoryConfig:  &MemoryConfig{Enabled: true, AutoStore: false},
+		MemoryContext: &MemoryContext{UserID: attacker},
+	}
+	// The attacker's own MemoryContext.UserID is honest (attacker); the
+	// forgery attempt is purely via headers a HeaderResolver-style
+	// deployment might trust.
+	headers := map[string]string{
+		"X-User-Id":        victim,
+		"X-Forwarded-User": victim,
+	}
+	resp, err := sendMemoryRequestWithHeaders(ctx, localPort, forged, headers, opts.Verbose)
+	if err != nil {
+		return fmt.Errorf("failed to send forged-header query: %w", err)
+	}
+	if strings.Contains(resp.OutputText, "42 Wallaby Way") {
+		return fmt.Errorf("SECURITY VIOLATION: forged X-User-Id/X-Forwarded-User headers granted access to victim's memory! Response: %s", resp.OutputText)
+	}
+
+	if err := assertNoDirectLeak(ctx, localPort, attacker, "wallaby", opts); err != nil {
+		return err
+	}
+
+	if opts.SetDetails != nil {
+		opts.SetDetails(map[string]interface{}{"victim": victim, "attacker": attacker})
+	}
+	return nil
+}
+
+// assertNoDirectLeak inspects userID's stored memories directly through the
+// router's admin inspection endpoint (bypassing the LLM entirely), so a
+// model that just declines to answer can't mask a memory that actually
+// leaked into another tenant's row. This is the test's only direct check of
+// what the backing store actually holds - the text-only assertion the
+// caller already made can be satisfied by a model that simply refuses to
+// answer, so a deployment with no admin inspection endpoint must fail this
+// check rather than silently pass it.
+func assertNoDirectLeak(ctx context.Context, localPort, userID, mustNotContain string, opts pkgtestcases.TestCaseOptions) error {
+	contents, inspected, err := fetchStoredMemoriesForUser(ctx, localPort, userID)
+	if err != nil {
+		return fmt.Errorf("admin memory inspection for %s failed: %w", userID, err)
+	}
+	if !inspected {
+		return fmt.Errorf("admin memory inspection endpoint is unavailable - cannot verify %s's stored memories contain no direct leak", userID)
+	}
+	for _, content := range contents {
+		if strings.Contains(strings.ToLower(content), mustNotContain) {
+			return fmt.Errorf("SECURITY VIOLATION: admin inspection found a direct leak in %s's stored memories: %q", userID, content)
+		}
+	}
+	return nil
+}
+
+// adminMemoryInspectResponse is the admin inspection endpoint's response
+// shape: one row per memory the backing store holds for the queried scope.
+type adminMemoryInspectResponse struct {
+	Memories []struct {
+		Content string `json:"content"`
+	} `json:"memories"`
+}
+
+// fetchStoredMemoriesForUser calls the router's admin memory-inspection
+// endpoint for userID. inspected is false (with a nil error) when the
+// deployment doesn't expose that endpoint (404/501) - callers must treat
+// that as a failure to verify isolation, not as "nothing to check".
+func fetchStoredMemoriesForUser(ctx context.Context, localPort, userID string) (contents []string, inspected bool, err error) {
+	url := fmt.Sprintf("http://localhost:%s/admin/memory?user_id=%s", localPort, userID)
+	return fetchAdminMemoryRows(ctx, localPort, url)
+}
+
+// fetchAdminMemoryRows is fetchStoredMemoriesForUser's and
+// fetchRawStoredMemoriesForUser's (see memory_encryption_at_rest.go) shared
+// body: GET url, treat 404/501 as "endpoint not supported" rather than a
+// failure, and unmarshal whatever the admin handler returned into a list of
+// row contents.
+func fetchAdminMemoryRows(ctx context.Context, localPort, url string) (contents []string, inspected bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to build admin inspection request: %w", err)
+	}
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("admin inspection endpoint unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNotImplemented {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("admin inspection endpoint returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read admin inspection response: %w", err)
+	}
+
+	var parsed adminMemoryInspectResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, false, fmt.Errorf("failed to parse admin inspection response: %w", err)
+	}
+
+	for _, m := range parsed.Memories {
+		contents = append(contents, m.Content)
+	}
+	return contents, true, nil
+}
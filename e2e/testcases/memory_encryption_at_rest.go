@@ -0,0 +1,89 @@
+package testcases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	pkgtestcases "github.com/vllm-project/semantic-router/e2e/pkg/testcases"
+	"k8s.io/client-go/kubernetes"
+)
+
+// This file checks the memory subsystem's encryption-at-rest (see
+// pkg/memory's encryptedStore/Cipher), from outside the router entirely: it
+// doesn't just trust that the router's text or admin-inspection responses
+// look right (those both go through the same decrypting Store the router
+// uses to answer), it inspects the raw, undecrypted row a deployment's admin
+// endpoint exposes via ?raw=true and asserts the seeded secret's plaintext
+// never appears in it. A deployment that doesn't support raw inspection
+// fails this test rather than passing it unverified - see
+// fetchRawStoredMemoriesForUser.
+func init() {
+	pkgtestcases.Register("memory-encryption-at-rest", pkgtestcases.TestCase{
+		Description: "Verify encryption at rest: the raw backend row for a stored memory contains no plaintext of its content",
+		Tags:        []string{"memory", "encryption", "security", "functional"},
+		Fn:          testMemoryEncryptionAtRest,
+	})
+}
+
+func testMemoryEncryptionAtRest(ctx context.Context, client *kubernetes.Clientset, opts pkgtestcases.TestCaseOptions) error {
+	localPort, stopPortForward, err := setupServiceConnection(ctx, client, opts)
+	if err != nil {
+		return err
+	}
+	defer stopPortForward()
+
+	userID := "user_encryption_at_rest_test"
+	userAMemory := "User's social security number is 078-05-1120"
+
+	store := MemoryRequest{
+		Model:        "MoM",
+		Input:        fmt.Sprintf("Remember this: %s", userAMemory),
+		Instructions: "You are a helpful assistant. Remember important information the user shares.",
+		MemoryConfig: &MemoryConfig{Enabled: true, AutoStore: true},
+		MemoryContext: &MemoryContext{
+			UserID: userID,
+		},
+	}
+	if _, err := sendMemoryRequest(ctx, localPort, store, opts.Verbose); err != nil {
+		return fmt.Errorf("failed to store memory: %w", err)
+	}
+	time.Sleep(3 * time.Second)
+
+	rows, inspected, err := fetchRawStoredMemoriesForUser(ctx, localPort, userID)
+	if err != nil {
+		return fmt.Errorf("raw memory inspection for %s failed: %w", userID, err)
+	}
+	if !inspected {
+		return fmt.Errorf("admin memory inspection endpoint does not support raw inspection - cannot verify %s's stored memory is encrypted at rest", userID)
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("raw inspection returned no rows for %s after storing a memory", userID)
+	}
+
+	needle := strings.ToLower(userAMemory)
+	for _, row := range rows {
+		if strings.Contains(strings.ToLower(row), needle) {
+			return fmt.Errorf("SECURITY VIOLATION: raw backend row for %s contains plaintext memory content: %q", userID, row)
+		}
+	}
+
+	if opts.SetDetails != nil {
+		opts.SetDetails(map[string]interface{}{"user_id": userID, "rows_inspected": len(rows)})
+	}
+	return nil
+}
+
+// fetchRawStoredMemoriesForUser calls the router's admin memory-inspection
+// endpoint with ?raw=true, which, where supported, returns each stored
+// memory's row exactly as the backend persists it - bypassing the Store's
+// own decryption (see pkg/memory's encryptedStore) the way
+// fetchStoredMemoriesForUser's plain ?user_id= call does not. inspected is
+// false (with a nil error) when the deployment's admin endpoint doesn't
+// support raw inspection (404/501) - callers must treat that as a failure
+// to verify encryption at rest, not as "nothing to check".
+func fetchRawStoredMemoriesForUser(ctx context.Context, localPort, userID string) (rows []string, inspected bool, err error) {
+	url := fmt.Sprintf("http://localhost:%s/admin/memory?user_id=%s&raw=true", localPort, userID)
+	return fetchAdminMemoryRows(ctx, localPort, url)
+}
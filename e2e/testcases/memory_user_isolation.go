@@ -24,12 +24,12 @@ func init() {
 
 // MemoryRequest represents a Response API request with memory configuration
 type MemoryRequest struct {
-	Model             string        `json:"model"`
-	Input             interface{}   `json:"input"`
-	Instructions      string        `json:"instructions,omitempty"`
-	MemoryConfig      *MemoryConfig `json:"memory_config,omitempty"`
-	MemoryContext     *MemoryContext `json:"memory_context,omitempty"`
-	PreviousResponseID string       `json:"previous_response_id,omitempty"`
+	Model              string         `json:"model"`
+	Input              interface{}    `json:"input"`
+	Instructions       string         `json:"instructions,omitempty"`
+	MemoryConfig       *MemoryConfig  `json:"memory_config,omitempty"`
+	MemoryContext      *MemoryContext `json:"memory_context,omitempty"`
+	PreviousResponseID string         `json:"previous_response_id,omitempty"`
 }
 
 // MemoryConfig configures memory extraction behavior
@@ -38,9 +38,16 @@ type MemoryConfig struct {
 	AutoStore bool `json:"auto_store,omitempty"`
 }
 
-// MemoryContext provides context for memory operations
+// MemoryContext provides context for memory operations. Fields mirror
+// memory.MemoryScope's UserID/ProjectID plus the OrganizationID/SessionID
+// scoping used by the cross-tenant isolation testcases in
+// memory_cross_tenant_isolation.go - OrganizationID and SessionID are
+// optional so existing callers that only set UserID are unaffected.
 type MemoryContext struct {
-	UserID string `json:"user_id"`
+	UserID         string `json:"user_id"`
+	ProjectID      string `json:"project_id,omitempty"`
+	OrganizationID string `json:"organization_id,omitempty"`
+	SessionID      string `json:"session_id,omitempty"`
 }
 
 // MemoryResponse represents a Response API response
@@ -51,7 +58,7 @@ type MemoryResponse struct {
 	Output             []map[string]interface{} `json:"output"`
 	OutputText         string                   `json:"output_text,omitempty"`
 	PreviousResponseID string                   `json:"previous_response_id,omitempty"`
-	Error              map[string]interface{}  `json:"error,omitempty"`
+	Error              map[string]interface{}   `json:"error,omitempty"`
 }
 
 // testMemoryUserIsolation tests that memories are properly isolated between users
@@ -76,8 +83,8 @@ func testMemoryUserIsolation(ctx context.Context, client *kubernetes.Clientset,
 
 	userAMemory := "My budget for Hawaii vacation is $10,000"
 	userARequest := MemoryRequest{
-		Model:  "MoM",
-		Input:  fmt.Sprintf("Remember this: %s", userAMemory),
+		Model:        "MoM",
+		Input:        fmt.Sprintf("Remember this: %s", userAMemory),
 		Instructions: "You are a helpful assistant. Remember important information the user shares.",
 		MemoryConfig: &MemoryConfig{
 			Enabled:   true,
@@ -106,8 +113,8 @@ func testMemoryUserIsolation(ctx context.Context, client *kubernetes.Clientset,
 	}
 
 	userBQuery := MemoryRequest{
-		Model:  "MoM",
-		Input:  "What is my budget for Hawaii vacation?",
+		Model:        "MoM",
+		Input:        "What is my budget for Hawaii vacation?",
 		Instructions: "You are a helpful assistant. Use your memory to answer questions.",
 		MemoryConfig: &MemoryConfig{
 			Enabled:   true,
@@ -142,8 +149,8 @@ func testMemoryUserIsolation(ctx context.Context, client *kubernetes.Clientset,
 
 	userBMemory := "My favorite programming language is Go"
 	userBStoreRequest := MemoryRequest{
-		Model:  "MoM",
-		Input:  fmt.Sprintf("Remember this: %s", userBMemory),
+		Model:        "MoM",
+		Input:        fmt.Sprintf("Remember this: %s", userBMemory),
 		Instructions: "You are a helpful assistant. Remember important information the user shares.",
 		MemoryConfig: &MemoryConfig{
 			Enabled:   true,
@@ -172,8 +179,8 @@ func testMemoryUserIsolation(ctx context.Context, client *kubernetes.Clientset,
 	}
 
 	userBSelfQuery := MemoryRequest{
-		Model:  "MoM",
-		Input:  "What is my favorite programming language?",
+		Model:        "MoM",
+		Input:        "What is my favorite programming language?",
 		Instructions: "You are a helpful assistant. Use your memory to answer questions.",
 		MemoryConfig: &MemoryConfig{
 			Enabled:   true,
@@ -206,8 +213,8 @@ func testMemoryUserIsolation(ctx context.Context, client *kubernetes.Clientset,
 	}
 
 	userAQuery := MemoryRequest{
-		Model:  "MoM",
-		Input:  "What is my favorite programming language?",
+		Model:        "MoM",
+		Input:        "What is my favorite programming language?",
 		Instructions: "You are a helpful assistant. Use your memory to answer questions.",
 		MemoryConfig: &MemoryConfig{
 			Enabled:   true,
@@ -241,8 +248,8 @@ func testMemoryUserIsolation(ctx context.Context, client *kubernetes.Clientset,
 	}
 
 	userASelfQuery := MemoryRequest{
-		Model:  "MoM",
-		Input:  "What is my budget for Hawaii vacation?",
+		Model:        "MoM",
+		Input:        "What is my budget for Hawaii vacation?",
 		Instructions: "You are a helpful assistant. Use your memory to answer questions.",
 		MemoryConfig: &MemoryConfig{
 			Enabled:   true,
@@ -273,10 +280,10 @@ func testMemoryUserIsolation(ctx context.Context, client *kubernetes.Clientset,
 	// Set details for reporting
 	if opts.SetDetails != nil {
 		opts.SetDetails(map[string]interface{}{
-			"user_a":            userA,
-			"user_b":            userB,
-			"user_a_memory":     userAMemory,
-			"user_b_memory":     userBMemory,
+			"user_a":             userA,
+			"user_b":             userB,
+			"user_a_memory":      userAMemory,
+			"user_b_memory":      userBMemory,
 			"isolation_verified": true,
 		})
 	}
@@ -288,8 +295,16 @@ func testMemoryUserIsolation(ctx context.Context, client *kubernetes.Clientset,
 	return nil
 }
 
-// sendMemoryRequest sends a memory-enabled request to the Response API
+// sendMemoryRequest sends a memory-enabled request to the Response API.
 func sendMemoryRequest(ctx context.Context, localPort string, req MemoryRequest, verbose bool) (*MemoryResponse, error) {
+	return sendMemoryRequestWithHeaders(ctx, localPort, req, nil, verbose)
+}
+
+// sendMemoryRequestWithHeaders is sendMemoryRequest plus caller-supplied
+// extra headers, e.g. a spoofed identity header for the header-injection
+// forgery testcase in memory_cross_tenant_isolation.go - ordinary callers
+// should keep using sendMemoryRequest.
+func sendMemoryRequestWithHeaders(ctx context.Context, localPort string, req MemoryRequest, headers map[string]string, verbose bool) (*MemoryResponse, error) {
 	jsonData, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -306,6 +321,9 @@ func sendMemoryRequest(ctx context.Context, localPort string, req MemoryRequest,
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		httpReq.Header.Set(k, v)
+	}
 
 	httpClient := &http.Client{Timeout: 60 * time.Second} // Longer timeout for memory operations
 	resp, err := httpClient.Do(httpReq)
@@ -0,0 +1,162 @@
+// Package agents provides a first-class "Agent" abstraction that binds a
+// system prompt template, an allowed tool set, and a memory scope, so a
+// deployment running several assistants against one memory store (a coding
+// assistant, a support bot, ...) can give each a distinct, testable memory
+// behavior per task/persona rather than per caller-supplied request config.
+//
+// An Agent is resolved by name (see Registry) from the routing decision
+// that selected it - RequestContext.VSRSelectedDecision names an Agent the
+// same way it already names a model or plugin config.
+package agents
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Agent carries the memory and tool-calling policy for one named
+// task/persona. Zero values for the numeric/slice fields mean "defer to the
+// request's own MemoryConfig" - an Agent only needs to set the fields it
+// wants to override.
+type Agent struct {
+	// Name identifies this agent. It's matched against the routing
+	// decision's name to resolve which Agent, if any, applies to a
+	// request (see Registry.Resolve).
+	Name string
+
+	// PromptTemplate renders retrieved memories into the system message
+	// MemoryFilter injects ahead of the conversation (see Render). Empty
+	// uses DefaultPromptTemplate.
+	PromptTemplate string
+
+	// AllowedMemoryTypes restricts retrieval to these memory types,
+	// overriding the request body's MemoryConfig.MemoryTypes. Empty
+	// defers to the request.
+	AllowedMemoryTypes []string
+
+	// RetrievalLimit and SimilarityThreshold override the request body's
+	// MemoryConfig values when positive. Zero defers to the request.
+	RetrievalLimit      int
+	SimilarityThreshold float64
+
+	// AutoStore overrides the request body's MemoryConfig.AutoStore when
+	// non-nil, so an agent can force (or forbid) auto-storing conversation
+	// turns regardless of what the caller's request asked for.
+	AutoStore *bool
+
+	// UserIDOverride and ProjectIDOverride, when set, replace the
+	// request's MemoryContext scope for both retrieval and storage - e.g.
+	// an agent whose memory always lives in one shared project namespace
+	// regardless of which user is talking to it.
+	UserIDOverride    string
+	ProjectIDOverride string
+
+	// AllowMemoryWriteTools overrides MemoryFilter's WithTools(allowWrites)
+	// default when non-nil, gating whether this agent's memory_forget/
+	// memory_update tools are advertised and executable. An agent scoped
+	// to read-only retrieval (e.g. "coding-assistant" retrieving
+	// procedural memories) sets this to false even if the filter itself
+	// allows writes for other callers.
+	AllowMemoryWriteTools *bool
+}
+
+// Registry resolves Agents by name. It's built once at startup (see
+// NewRegistry) and looked up per request. Resolve on an empty name, a name
+// with no matching Agent, or a nil Registry returns nil, so callers treat
+// "no agent" as the unchanged, request-only behavior.
+type Registry struct {
+	agents map[string]*Agent
+}
+
+// NewRegistry builds a Registry from agentList. Entries with an empty Name
+// are skipped - an unnamed Agent can never be resolved, so it would be
+// dead configuration.
+func NewRegistry(agentList ...*Agent) *Registry {
+	r := &Registry{agents: make(map[string]*Agent, len(agentList))}
+	for _, a := range agentList {
+		if a == nil || a.Name == "" {
+			continue
+		}
+		r.agents[a.Name] = a
+	}
+	return r
+}
+
+// Resolve returns the Agent registered under name, or nil if there is none
+// (including when r or name is empty).
+func (r *Registry) Resolve(name string) *Agent {
+	if r == nil || name == "" {
+		return nil
+	}
+	return r.agents[name]
+}
+
+// DefaultPromptTemplate matches MemoryFilter's original hard-coded
+// "## Relevant Context from Memory" header, used when an Agent doesn't set
+// its own PromptTemplate (or no Agent resolved at all).
+const DefaultPromptTemplate = `## Relevant Context from Memory
+
+The following information was retrieved from the user's memory:
+{{range .Memories}}
+{{.Index}}. [{{.Type}}] {{.Content}}{{end}}
+
+Use this context to provide a more personalized and informed response.`
+
+// RenderedMemory is one retrieved memory exposed to a PromptTemplate.
+type RenderedMemory struct {
+	Index   int
+	Type    string
+	Content string
+}
+
+// Render executes a's PromptTemplate (or DefaultPromptTemplate if a is nil
+// or sets none) over memories, producing the system message MemoryFilter
+// injects ahead of the conversation. A malformed PromptTemplate falls back
+// to DefaultPromptTemplate rather than failing the request over a
+// formatting preference.
+func (a *Agent) Render(memories []RenderedMemory) string {
+	tmplText := DefaultPromptTemplate
+	if a != nil && a.PromptTemplate != "" {
+		tmplText = a.PromptTemplate
+	}
+
+	out, err := renderTemplate(tmplText, memories)
+	if err != nil {
+		if tmplText == DefaultPromptTemplate {
+			// DefaultPromptTemplate itself is the bug in this case - there
+			// is nothing safer to fall back to, so return what render
+			// produced (possibly empty).
+			return out
+		}
+		if fallback, fallbackErr := renderTemplate(DefaultPromptTemplate, memories); fallbackErr == nil {
+			return fallback
+		}
+		return out
+	}
+	return out
+}
+
+func renderTemplate(tmplText string, memories []RenderedMemory) (string, error) {
+	tmpl, err := template.New("agent-prompt").Parse(tmplText)
+	if err != nil {
+		return "", fmt.Errorf("agents: parse prompt template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct{ Memories []RenderedMemory }{Memories: memories}); err != nil {
+		return "", fmt.Errorf("agents: execute prompt template: %w", err)
+	}
+	return strings.TrimRight(buf.String(), "\n") + "\n", nil
+}
+
+// AllowWrites resolves whether agent's memory tools may include
+// memory_forget/memory_update, given defaultAllowWrites (the filter-wide
+// MemoryFilter.WithTools setting) to fall back on when agent is nil or
+// doesn't override the policy.
+func AllowWrites(agent *Agent, defaultAllowWrites bool) bool {
+	if agent != nil && agent.AllowMemoryWriteTools != nil {
+		return *agent.AllowMemoryWriteTools
+	}
+	return defaultAllowWrites
+}
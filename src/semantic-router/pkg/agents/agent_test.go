@@ -0,0 +1,89 @@
+package agents
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistry_ResolveByName(t *testing.T) {
+	coding := &Agent{Name: "coding-assistant", AllowedMemoryTypes: []string{"procedural"}}
+	registry := NewRegistry(coding, &Agent{Name: "support-bot"})
+
+	got := registry.Resolve("coding-assistant")
+	if got != coding {
+		t.Fatalf("Resolve(%q) = %v, want %v", "coding-assistant", got, coding)
+	}
+}
+
+func TestRegistry_ResolveUnknownOrEmptyReturnsNil(t *testing.T) {
+	registry := NewRegistry(&Agent{Name: "support-bot"})
+
+	if got := registry.Resolve("does-not-exist"); got != nil {
+		t.Errorf("Resolve(unknown) = %v, want nil", got)
+	}
+	if got := registry.Resolve(""); got != nil {
+		t.Errorf("Resolve(\"\") = %v, want nil", got)
+	}
+}
+
+func TestRegistry_NilRegistryResolveReturnsNil(t *testing.T) {
+	var registry *Registry
+	if got := registry.Resolve("anything"); got != nil {
+		t.Errorf("nil Registry.Resolve() = %v, want nil", got)
+	}
+}
+
+func TestRegistry_SkipsUnnamedAgents(t *testing.T) {
+	registry := NewRegistry(&Agent{Name: ""}, nil, &Agent{Name: "ok"})
+	if registry.Resolve("ok") == nil {
+		t.Fatal("expected the named agent to still resolve")
+	}
+}
+
+func TestAgent_RenderDefaultTemplate(t *testing.T) {
+	var agent *Agent
+	out := agent.Render([]RenderedMemory{{Index: 1, Type: "semantic", Content: "likes tea"}})
+
+	if !strings.Contains(out, "## Relevant Context from Memory") {
+		t.Errorf("expected default header, got %q", out)
+	}
+	if !strings.Contains(out, "1. [semantic] likes tea") {
+		t.Errorf("expected rendered memory line, got %q", out)
+	}
+}
+
+func TestAgent_RenderCustomTemplate(t *testing.T) {
+	agent := &Agent{PromptTemplate: "Known facts:{{range .Memories}} {{.Content}};{{end}}"}
+	out := agent.Render([]RenderedMemory{{Index: 1, Type: "semantic", Content: "likes tea"}})
+
+	want := "Known facts: likes tea;\n"
+	if out != want {
+		t.Errorf("Render() = %q, want %q", out, want)
+	}
+}
+
+func TestAgent_RenderMalformedTemplateFallsBackToDefault(t *testing.T) {
+	agent := &Agent{PromptTemplate: "{{.NotAField}}"}
+	out := agent.Render([]RenderedMemory{{Index: 1, Type: "semantic", Content: "likes tea"}})
+
+	if !strings.Contains(out, "## Relevant Context from Memory") {
+		t.Errorf("expected fallback to default template, got %q", out)
+	}
+}
+
+func TestAllowWrites(t *testing.T) {
+	yes, no := true, false
+
+	if AllowWrites(nil, true) != true {
+		t.Error("nil agent should defer to defaultAllowWrites=true")
+	}
+	if AllowWrites(&Agent{}, true) != true {
+		t.Error("agent with no override should defer to defaultAllowWrites=true")
+	}
+	if AllowWrites(&Agent{AllowMemoryWriteTools: &no}, true) != false {
+		t.Error("agent override=false should take precedence over defaultAllowWrites=true")
+	}
+	if AllowWrites(&Agent{AllowMemoryWriteTools: &yes}, false) != true {
+		t.Error("agent override=true should take precedence over defaultAllowWrites=false")
+	}
+}
@@ -0,0 +1,231 @@
+// Package identity verifies Bearer JWTs presented on inbound requests and
+// extracts the trusted identity claims (user, tenant, scope, groups) that
+// downstream components - memory auto_store gating, per-decision policies,
+// PII plugins - key off of.
+//
+// This is a trust boundary independent of the x-authz-user-id header
+// convention used elsewhere in pkg/extproc: that header assumes an upstream
+// proxy (Authorino, Envoy Gateway JWT filter, oauth2-proxy) has already
+// verified the token and injected the result. Config lets operators without
+// such a proxy verify the token in-process instead.
+package identity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims is the trusted identity extracted from a verified JWT.
+type Claims struct {
+	// UserID is the subject of the token (from Config.UserIDClaim, default "sub").
+	UserID string
+
+	// Tenant is the token's organization/tenant (from Config.TenantClaim, default "tenant").
+	// Empty if the claim is absent - tenancy is optional.
+	Tenant string
+
+	// Scope is the token's scope/permissions claim (from Config.ScopeClaim, default "scope").
+	Scope string
+
+	// Groups is the token's group membership (from Config.GroupsClaim, default "groups").
+	Groups []string
+
+	// ExpiresAt is the token's exp claim, kept for callers that want to log
+	// or cache against it.
+	ExpiresAt time.Time
+}
+
+// KeySourceType selects how Verifier validates a token's signature.
+type KeySourceType string
+
+const (
+	// KeySourceJWKS fetches and caches signing keys from Config.JWKSURL.
+	KeySourceJWKS KeySourceType = "jwks"
+
+	// KeySourceStatic verifies against a single static key configured out of
+	// band (Config.StaticKey), e.g. for HS256 shared-secret deployments.
+	KeySourceStatic KeySourceType = "static"
+)
+
+// Config configures a Verifier.
+type Config struct {
+	// Enabled turns JWT verification on. When false, NewVerifier returns a
+	// verifier that rejects every token, so callers fall back to the
+	// header-based identity path.
+	Enabled bool
+
+	// KeySource selects where the signing key(s) come from. Defaults to
+	// KeySourceJWKS.
+	KeySource KeySourceType
+
+	// JWKSURL is the JWKS endpoint to fetch signing keys from. Required when
+	// KeySource == KeySourceJWKS.
+	JWKSURL string
+
+	// JWKSRefreshInterval controls how often cached JWKS keys are
+	// refreshed. Defaults to 1 hour.
+	JWKSRefreshInterval time.Duration
+
+	// StaticKey is the shared secret used to verify HMAC-signed tokens.
+	// Required when KeySource == KeySourceStatic.
+	StaticKey []byte
+
+	// Issuer, when set, is matched against the token's iss claim; tokens
+	// from any other issuer are rejected.
+	Issuer string
+
+	// Audience, when set, is matched against the token's aud claim.
+	Audience string
+
+	// UserIDClaim, TenantClaim, ScopeClaim, and GroupsClaim name the JWT
+	// claims Claims.UserID/Tenant/Scope/Groups are read from. Each defaults
+	// to "sub", "tenant", "scope", and "groups" respectively.
+	UserIDClaim string
+	TenantClaim string
+	ScopeClaim  string
+	GroupsClaim string
+}
+
+// Verifier verifies a Bearer token string and returns the claims it carries.
+type Verifier interface {
+	// VerifyToken verifies tokenString's signature, issuer, audience, and
+	// expiry, then extracts Claims from the configured claim names.
+	VerifyToken(tokenString string) (*Claims, error)
+}
+
+// disabledVerifier rejects every token; used when Config.Enabled is false so
+// callers can unconditionally try JWT verification first and fall back to
+// the header-based path on error.
+type disabledVerifier struct{}
+
+func (disabledVerifier) VerifyToken(string) (*Claims, error) {
+	return nil, fmt.Errorf("identity: JWT verification is disabled")
+}
+
+// jwtVerifier implements Verifier with golang-jwt, sourcing the signing key
+// either from a cached JWKS fetch or a static secret.
+type jwtVerifier struct {
+	config  Config
+	keyfunc jwt.Keyfunc
+
+	jwksCache *jwksCache // nil when KeySource == KeySourceStatic
+}
+
+// NewVerifier builds a Verifier from config. A disabled config returns a
+// Verifier that always errors, so the in-process JWT path can be wired in
+// unconditionally and simply falls through on error.
+func NewVerifier(config Config) (Verifier, error) {
+	if !config.Enabled {
+		return disabledVerifier{}, nil
+	}
+	config = withDefaults(config)
+
+	v := &jwtVerifier{config: config}
+	switch config.KeySource {
+	case KeySourceStatic:
+		if len(config.StaticKey) == 0 {
+			return nil, fmt.Errorf("identity: KeySourceStatic requires a StaticKey")
+		}
+		v.keyfunc = func(*jwt.Token) (interface{}, error) { return config.StaticKey, nil }
+	case KeySourceJWKS, "":
+		if config.JWKSURL == "" {
+			return nil, fmt.Errorf("identity: KeySourceJWKS requires a JWKSURL")
+		}
+		v.jwksCache = newJWKSCache(config.JWKSURL, config.JWKSRefreshInterval)
+		v.keyfunc = v.jwksCache.keyfunc
+	default:
+		return nil, fmt.Errorf("identity: unknown key source: %s", config.KeySource)
+	}
+
+	return v, nil
+}
+
+func withDefaults(config Config) Config {
+	if config.KeySource == "" {
+		config.KeySource = KeySourceJWKS
+	}
+	if config.JWKSRefreshInterval <= 0 {
+		config.JWKSRefreshInterval = time.Hour
+	}
+	if config.UserIDClaim == "" {
+		config.UserIDClaim = "sub"
+	}
+	if config.TenantClaim == "" {
+		config.TenantClaim = "tenant"
+	}
+	if config.ScopeClaim == "" {
+		config.ScopeClaim = "scope"
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+	return config
+}
+
+// VerifyToken implements Verifier.
+func (v *jwtVerifier) VerifyToken(tokenString string) (*Claims, error) {
+	tokenString = strings.TrimSpace(strings.TrimPrefix(tokenString, "Bearer"))
+	tokenString = strings.TrimSpace(tokenString)
+	if tokenString == "" {
+		return nil, fmt.Errorf("identity: empty token")
+	}
+
+	validMethods := []string{"HS256"}
+	if v.config.KeySource == KeySourceJWKS {
+		validMethods = []string{"RS256"}
+	}
+	parserOpts := []jwt.ParserOption{jwt.WithValidMethods(validMethods)}
+	if v.config.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(v.config.Issuer))
+	}
+	if v.config.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(v.config.Audience))
+	}
+
+	token, err := jwt.Parse(tokenString, v.keyfunc, parserOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("identity: token verification failed: %w", err)
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("identity: token is not valid")
+	}
+
+	result := &Claims{
+		UserID: stringClaim(claims, v.config.UserIDClaim),
+		Tenant: stringClaim(claims, v.config.TenantClaim),
+		Scope:  stringClaim(claims, v.config.ScopeClaim),
+		Groups: stringSliceClaim(claims, v.config.GroupsClaim),
+	}
+	if result.UserID == "" {
+		return nil, fmt.Errorf("identity: token missing required claim %q", v.config.UserIDClaim)
+	}
+	if expiry, err := claims.GetExpirationTime(); err == nil && expiry != nil {
+		result.ExpiresAt = expiry.Time
+	}
+	return result, nil
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	if v, ok := claims[name].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func stringSliceClaim(claims jwt.MapClaims, name string) []string {
+	raw, ok := claims[name].([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
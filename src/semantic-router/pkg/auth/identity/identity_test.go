@@ -0,0 +1,137 @@
+package identity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func signTestToken(t *testing.T, key []byte, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign test token: %v", err)
+	}
+	return signed
+}
+
+func staticKeyVerifier(t *testing.T, key []byte, issuer string) Verifier {
+	t.Helper()
+	v, err := NewVerifier(Config{
+		Enabled:   true,
+		KeySource: KeySourceStatic,
+		StaticKey: key,
+		Issuer:    issuer,
+	})
+	if err != nil {
+		t.Fatalf("NewVerifier failed: %v", err)
+	}
+	return v
+}
+
+func TestVerifier_ValidToken(t *testing.T) {
+	key := []byte("test-secret")
+	v := staticKeyVerifier(t, key, "semantic-router")
+
+	token := signTestToken(t, key, jwt.MapClaims{
+		"sub":    "user-123",
+		"tenant": "acme",
+		"scope":  "memory:write",
+		"groups": []interface{}{"eng", "admins"},
+		"iss":    "semantic-router",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	})
+
+	claims, err := v.VerifyToken("Bearer " + token)
+	if err != nil {
+		t.Fatalf("VerifyToken failed: %v", err)
+	}
+	if claims.UserID != "user-123" {
+		t.Errorf("got UserID %q, want %q", claims.UserID, "user-123")
+	}
+	if claims.Tenant != "acme" {
+		t.Errorf("got Tenant %q, want %q", claims.Tenant, "acme")
+	}
+	if len(claims.Groups) != 2 || claims.Groups[0] != "eng" {
+		t.Errorf("got Groups %v", claims.Groups)
+	}
+}
+
+func TestVerifier_ExpiredToken(t *testing.T) {
+	key := []byte("test-secret")
+	v := staticKeyVerifier(t, key, "")
+
+	token := signTestToken(t, key, jwt.MapClaims{
+		"sub": "user-123",
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	})
+
+	if _, err := v.VerifyToken(token); err == nil {
+		t.Error("expected expired token to fail verification")
+	}
+}
+
+func TestVerifier_IssuerMismatch(t *testing.T) {
+	key := []byte("test-secret")
+	v := staticKeyVerifier(t, key, "expected-issuer")
+
+	token := signTestToken(t, key, jwt.MapClaims{
+		"sub": "user-123",
+		"iss": "some-other-issuer",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	if _, err := v.VerifyToken(token); err == nil {
+		t.Error("expected issuer mismatch to fail verification")
+	}
+}
+
+func TestVerifier_MissingUserIDClaim(t *testing.T) {
+	key := []byte("test-secret")
+	v := staticKeyVerifier(t, key, "")
+
+	token := signTestToken(t, key, jwt.MapClaims{
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	if _, err := v.VerifyToken(token); err == nil {
+		t.Error("expected missing sub claim to fail verification")
+	}
+}
+
+func TestVerifier_WrongSigningKey(t *testing.T) {
+	v := staticKeyVerifier(t, []byte("correct-key"), "")
+
+	token := signTestToken(t, []byte("wrong-key"), jwt.MapClaims{
+		"sub": "user-123",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	if _, err := v.VerifyToken(token); err == nil {
+		t.Error("expected token signed with the wrong key to fail verification")
+	}
+}
+
+func TestNewVerifier_Disabled(t *testing.T) {
+	v, err := NewVerifier(Config{Enabled: false})
+	if err != nil {
+		t.Fatalf("NewVerifier failed: %v", err)
+	}
+	if _, err := v.VerifyToken("anything"); err == nil {
+		t.Error("expected disabled verifier to always reject")
+	}
+}
+
+func TestNewVerifier_StaticKeyRequiresKey(t *testing.T) {
+	if _, err := NewVerifier(Config{Enabled: true, KeySource: KeySourceStatic}); err == nil {
+		t.Error("expected NewVerifier to reject a static config with no key")
+	}
+}
+
+func TestNewVerifier_JWKSRequiresURL(t *testing.T) {
+	if _, err := NewVerifier(Config{Enabled: true, KeySource: KeySourceJWKS}); err == nil {
+		t.Error("expected NewVerifier to reject a JWKS config with no URL")
+	}
+}
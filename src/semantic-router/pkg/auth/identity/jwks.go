@@ -0,0 +1,141 @@
+package identity
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksCache fetches and caches signing keys from a JWKS endpoint, refreshing
+// them once refreshInterval has passed, and also whenever a token names a
+// kid the cached set doesn't hold. Keys are looked up by the token's "kid"
+// header, as RFC 7517 intends.
+type jwksCache struct {
+	url             string
+	refreshInterval time.Duration
+	httpClient      *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]*rsa.PublicKey
+	lastFetched time.Time
+}
+
+func newJWKSCache(url string, refreshInterval time.Duration) *jwksCache {
+	return &jwksCache{
+		url:             url,
+		refreshInterval: refreshInterval,
+		httpClient:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// jwksDocument is the RFC 7517 JSON Web Key Set shape.
+type jwksDocument struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// keyfunc satisfies jwt.Keyfunc: it resolves the token's "kid" header
+// against the cached key set, refreshing the set first if it's stale or the
+// kid isn't found (covering key rotation).
+func (c *jwksCache) keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, fmt.Errorf("identity: token has no kid header")
+	}
+
+	key, err := c.lookup(kid)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (c *jwksCache) lookup(kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.keys[kid]; ok && time.Since(c.lastFetched) < c.refreshInterval {
+		return key, nil
+	}
+
+	keys, err := c.fetch()
+	if err != nil {
+		if existing, ok := c.keys[kid]; ok {
+			// Serve the stale key rather than failing every request while
+			// the JWKS endpoint is unreachable.
+			return existing, nil
+		}
+		return nil, err
+	}
+	c.keys = keys
+	c.lastFetched = time.Now()
+
+	key, ok := c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("identity: no JWKS key found for kid %q", kid)
+	}
+	return key, nil
+}
+
+func (c *jwksCache) fetch() (map[string]*rsa.PublicKey, error) {
+	resp, err := c.httpClient.Get(c.url)
+	if err != nil {
+		return nil, fmt.Errorf("identity: failed to fetch JWKS from %s: %w", c.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("identity: JWKS endpoint %s returned status %d", c.url, resp.StatusCode)
+	}
+
+	var doc jwksDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("identity: failed to parse JWKS response: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(doc.Keys))
+	for _, jwk := range doc.Keys {
+		if jwk.Kty != "RSA" || jwk.Kid == "" {
+			continue
+		}
+		key, err := rsaPublicKeyFromJWK(jwk)
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func rsaPublicKeyFromJWK(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, fmt.Errorf("identity: malformed JWK modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, fmt.Errorf("identity: malformed JWK exponent: %w", err)
+	}
+
+	eBuf := make([]byte, 8)
+	copy(eBuf[8-len(eBytes):], eBytes)
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(binary.BigEndian.Uint64(eBuf)),
+	}, nil
+}
@@ -0,0 +1,184 @@
+package bodymutation
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ProviderAnthropic is the Context.Provider value that selects Anthropic's
+// Messages API: AnthropicTranslateStage activates on it, and it's the
+// conventional entry decisions put in a json-schema-coerce plugin's
+// unsupported_providers list.
+const ProviderAnthropic = "anthropic"
+
+// anthropicDefaultMaxTokens is sent when a request omits max_tokens, which
+// Anthropic's Messages API requires but OpenAI's chat-completions API does
+// not.
+const anthropicDefaultMaxTokens = 4096
+
+// openAIRequestMessage is the subset of an OpenAI chat-completions message
+// AnthropicTranslateStage needs to read: plain/array content, an optional
+// tool_call_id (on "tool" role messages), and an optional tool_calls list
+// (on "assistant" role messages).
+type openAIRequestMessage struct {
+	Role       string                  `json:"role"`
+	Content    json.RawMessage         `json:"content"`
+	ToolCallID string                  `json:"tool_call_id,omitempty"`
+	ToolCalls  []openAIRequestToolCall `json:"tool_calls,omitempty"`
+}
+
+type openAIRequestToolCall struct {
+	ID       string `json:"id"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+// anthropicMessage is one entry of an Anthropic Messages API "messages"
+// array: role plus either a plain string or a list of content blocks.
+type anthropicMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// anthropicContentBlock covers the block shapes AnthropicTranslateStage
+// produces: "text", "tool_use" (an assistant's tool call), and
+// "tool_result" (a tool role message's reply).
+type anthropicContentBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   json.RawMessage `json:"content,omitempty"`
+}
+
+// AnthropicTranslateStage rewrites an OpenAI chat-completions request body
+// into Anthropic's Messages API shape when Context.Provider is
+// ProviderAnthropic: the system message(s) are lifted out of "messages"
+// into a top-level "system" string, an assistant message's "tool_calls"
+// become "tool_use" content blocks, and a "tool" role message becomes a
+// "tool_result" content block addressed by tool_call_id. It runs after
+// every OpenAI-shape stage (prompt-guard, system-prompt-prepend,
+// tool-filter, json-schema-coerce all expect and produce OpenAI shape),
+// since once it runs the body is Anthropic shaped.
+type AnthropicTranslateStage struct{}
+
+func (AnthropicTranslateStage) Name() string {
+	return "anthropic-translate"
+}
+
+func (AnthropicTranslateStage) After() []string {
+	return []string{"response-api-translate", "prompt-guard", "system-prompt-prepend", "tool-filter", "json-schema-coerce"}
+}
+
+func (AnthropicTranslateStage) Mutate(ctx Context, body []byte) ([]byte, error) {
+	if ctx.Provider != ProviderAnthropic || len(body) == 0 {
+		return body, nil
+	}
+
+	var req struct {
+		Model       string                 `json:"model"`
+		Messages    []openAIRequestMessage `json:"messages"`
+		MaxTokens   int                    `json:"max_tokens,omitempty"`
+		Temperature *float64               `json:"temperature,omitempty"`
+		Tools       json.RawMessage        `json:"tools,omitempty"`
+		Stream      bool                   `json:"stream,omitempty"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, fmt.Errorf("unmarshal request body: %w", err)
+	}
+
+	var systemText string
+	messages := make([]anthropicMessage, 0, len(req.Messages))
+	for _, msg := range req.Messages {
+		switch msg.Role {
+		case "system":
+			if text := plainTextContent(msg.Content); text != "" {
+				if systemText != "" {
+					systemText += "\n"
+				}
+				systemText += text
+			}
+			continue
+
+		case "tool":
+			block, err := json.Marshal([]anthropicContentBlock{{
+				Type:      "tool_result",
+				ToolUseID: msg.ToolCallID,
+				Content:   msg.Content,
+			}})
+			if err != nil {
+				return nil, fmt.Errorf("marshal tool_result block: %w", err)
+			}
+			messages = append(messages, anthropicMessage{Role: "user", Content: block})
+			continue
+		}
+
+		if len(msg.ToolCalls) == 0 {
+			messages = append(messages, anthropicMessage{Role: msg.Role, Content: msg.Content})
+			continue
+		}
+
+		blocks := make([]anthropicContentBlock, 0, len(msg.ToolCalls)+1)
+		if text := plainTextContent(msg.Content); text != "" {
+			blocks = append(blocks, anthropicContentBlock{Type: "text", Text: text})
+		}
+		for _, call := range msg.ToolCalls {
+			input := json.RawMessage(call.Function.Arguments)
+			if len(input) == 0 {
+				input = json.RawMessage("{}")
+			}
+			blocks = append(blocks, anthropicContentBlock{
+				Type:  "tool_use",
+				ID:    call.ID,
+				Name:  call.Function.Name,
+				Input: input,
+			})
+		}
+		blocksJSON, err := json.Marshal(blocks)
+		if err != nil {
+			return nil, fmt.Errorf("marshal tool_use blocks: %w", err)
+		}
+		messages = append(messages, anthropicMessage{Role: msg.Role, Content: blocksJSON})
+	}
+
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = anthropicDefaultMaxTokens
+	}
+
+	out := map[string]interface{}{
+		"model":      req.Model,
+		"messages":   messages,
+		"max_tokens": maxTokens,
+	}
+	if systemText != "" {
+		out["system"] = systemText
+	}
+	if req.Temperature != nil {
+		out["temperature"] = *req.Temperature
+	}
+	if len(req.Tools) > 0 {
+		out["tools"] = req.Tools
+	}
+	if req.Stream {
+		out["stream"] = true
+	}
+
+	return json.Marshal(out)
+}
+
+// plainTextContent returns content's string value, or "" if content is
+// absent or array-shaped. Multi-part content is not flattened, so a system
+// message or a tool-calling assistant message with array content
+// contributes no text to the translated request.
+func plainTextContent(content json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(content, &text); err != nil {
+		return ""
+	}
+	return text
+}
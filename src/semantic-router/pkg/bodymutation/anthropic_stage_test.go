@@ -0,0 +1,57 @@
+package bodymutation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnthropicTranslateStage_NoopForOtherProviders(t *testing.T) {
+	stage := AnthropicTranslateStage{}
+	body := []byte(`{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}]}`)
+	out, err := stage.Mutate(Context{Provider: "openai"}, body)
+	require.NoError(t, err)
+	assert.Equal(t, body, out)
+}
+
+func TestAnthropicTranslateStage_LiftsSystemMessageAndDefaultsMaxTokens(t *testing.T) {
+	stage := AnthropicTranslateStage{}
+	ctx := Context{Provider: ProviderAnthropic}
+	body := []byte(`{"model":"claude-3-5-sonnet","messages":[{"role":"system","content":"Be terse."},{"role":"user","content":"hi"}]}`)
+
+	out, err := stage.Mutate(ctx, body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"model":"claude-3-5-sonnet",
+		"system":"Be terse.",
+		"max_tokens":4096,
+		"messages":[{"role":"user","content":"hi"}]
+	}`, string(out))
+}
+
+func TestAnthropicTranslateStage_ConvertsToolCallsToToolUseBlocks(t *testing.T) {
+	stage := AnthropicTranslateStage{}
+	ctx := Context{Provider: ProviderAnthropic}
+	body := []byte(`{
+		"model":"claude-3-5-sonnet",
+		"max_tokens":1024,
+		"messages":[
+			{"role":"user","content":"what's the weather?"},
+			{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","function":{"name":"get_weather","arguments":"{\"city\":\"NYC\"}"}}]},
+			{"role":"tool","tool_call_id":"call_1","content":"72F and sunny"}
+		]
+	}`)
+
+	out, err := stage.Mutate(ctx, body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"model":"claude-3-5-sonnet",
+		"max_tokens":1024,
+		"messages":[
+			{"role":"user","content":"what's the weather?"},
+			{"role":"assistant","content":[{"type":"tool_use","id":"call_1","name":"get_weather","input":{"city":"NYC"}}]},
+			{"role":"user","content":[{"type":"tool_result","tool_use_id":"call_1","content":"72F and sunny"}]}
+		]
+	}`, string(out))
+}
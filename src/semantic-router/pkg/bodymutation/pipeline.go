@@ -0,0 +1,143 @@
+// Package bodymutation runs a request body through an ordered chain of
+// Stages - model-name rewriting, Response API translation, and decision-
+// enabled plugins like prompt guarding - computing the resulting body (and
+// therefore its content-length) exactly once regardless of how many
+// stages actually touch it.
+package bodymutation
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Context carries the per-request state Stages need. It is decoupled from
+// pkg/extproc.RequestContext so this package has no dependency on
+// pkg/extproc (which is what registers a Pipeline on OpenAIRouter); see
+// the adapter in pkg/extproc/body_mutation.go.
+type Context struct {
+	// Model is the internal alias used for headers/tracing.
+	Model string
+	// UpstreamModel is the provider-facing model name. Equal to Model
+	// when no rewriting is needed.
+	UpstreamModel string
+	// Provider is the resolved authz.LLMProvider for the endpoint this
+	// request is headed to, as a string so this package need not import
+	// pkg/authz.
+	Provider string
+	// DecisionName is ctx.VSRSelectedDecision.Name, or empty if no
+	// decision was selected.
+	DecisionName string
+	// Plugins maps a decision plugin's Type to its Configuration, mirror
+	// of ctx.VSRSelectedDecision.Plugins.
+	Plugins map[string]map[string]interface{}
+	// ResponseAPIRequest is true when the incoming request used the
+	// Response API rather than chat completions.
+	ResponseAPIRequest bool
+	// ResponseAPITranslatedBody is the chat-completions-shaped body
+	// already produced for a Response API request, consumed by
+	// ResponseAPITranslateStage.
+	ResponseAPITranslatedBody []byte
+}
+
+// PluginConfig returns the Configuration of the decision plugin with the
+// given Type, or nil if the selected decision has no such plugin.
+func (c Context) PluginConfig(pluginType string) map[string]interface{} {
+	return c.Plugins[pluginType]
+}
+
+// Stage is one body-mutation step in a Pipeline.
+type Stage interface {
+	// Name identifies this Stage for ordering; must be unique within a
+	// Pipeline.
+	Name() string
+	// After lists the Names of Stages that must run before this one.
+	// Names not present in the owning Pipeline are ignored.
+	After() []string
+	// Mutate returns the (possibly unchanged) body after this Stage runs.
+	// Stages that make no change should return body as-is.
+	Mutate(ctx Context, body []byte) ([]byte, error)
+}
+
+// Pipeline runs an ordered chain of Stages over a request body.
+type Pipeline struct {
+	Stages []Stage
+}
+
+// Result is what Run produces.
+type Result struct {
+	// Body is the body after every Stage has run.
+	Body []byte
+	// Changed is true if any Stage actually modified the body, so
+	// callers only need to emit a BodyMutation/content-length update when
+	// it's true.
+	Changed bool
+}
+
+// Ordered returns p.Stages topologically sorted by each Stage's After
+// constraints. Stages with no ordering relationship relative to each
+// other keep their original registration order.
+func (p *Pipeline) Ordered() ([]Stage, error) {
+	byName := make(map[string]bool, len(p.Stages))
+	for _, s := range p.Stages {
+		if byName[s.Name()] {
+			return nil, fmt.Errorf("bodymutation: duplicate stage name %q", s.Name())
+		}
+		byName[s.Name()] = true
+	}
+
+	placed := make(map[string]bool, len(p.Stages))
+	ordered := make([]Stage, 0, len(p.Stages))
+
+	for len(ordered) < len(p.Stages) {
+		progressed := false
+		for _, s := range p.Stages {
+			if placed[s.Name()] {
+				continue
+			}
+			ready := true
+			for _, dep := range s.After() {
+				if byName[dep] && !placed[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, s)
+				placed[s.Name()] = true
+				progressed = true
+			}
+		}
+		if !progressed {
+			return nil, fmt.Errorf("bodymutation: cyclic stage ordering detected")
+		}
+	}
+	return ordered, nil
+}
+
+// Run executes every Stage in dependency order against body, short-
+// circuiting on the first error. A nil Pipeline (zero Stages) returns
+// body unchanged.
+func (p *Pipeline) Run(ctx Context, body []byte) (Result, error) {
+	if p == nil || len(p.Stages) == 0 {
+		return Result{Body: body}, nil
+	}
+
+	ordered, err := p.Ordered()
+	if err != nil {
+		return Result{Body: body}, err
+	}
+
+	current := body
+	changed := false
+	for _, stage := range ordered {
+		next, err := stage.Mutate(ctx, current)
+		if err != nil {
+			return Result{Body: current}, fmt.Errorf("bodymutation: stage %q: %w", stage.Name(), err)
+		}
+		if next != nil && !bytes.Equal(next, current) {
+			current = next
+			changed = true
+		}
+	}
+	return Result{Body: current, Changed: changed}, nil
+}
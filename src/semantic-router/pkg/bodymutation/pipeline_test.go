@@ -0,0 +1,148 @@
+package bodymutation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type upperCaseStage struct {
+	name  string
+	after []string
+}
+
+func (s upperCaseStage) Name() string    { return s.name }
+func (s upperCaseStage) After() []string { return s.after }
+func (s upperCaseStage) Mutate(_ Context, body []byte) ([]byte, error) {
+	return []byte(string(body) + "-" + s.name), nil
+}
+
+func TestPipeline_OrderedRespectsAfterConstraints(t *testing.T) {
+	p := &Pipeline{Stages: []Stage{
+		upperCaseStage{name: "c", after: []string{"b"}},
+		upperCaseStage{name: "a"},
+		upperCaseStage{name: "b", after: []string{"a"}},
+	}}
+
+	ordered, err := p.Ordered()
+	require.NoError(t, err)
+	names := make([]string, len(ordered))
+	for i, s := range ordered {
+		names[i] = s.Name()
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+}
+
+func TestPipeline_OrderedDetectsCycle(t *testing.T) {
+	p := &Pipeline{Stages: []Stage{
+		upperCaseStage{name: "a", after: []string{"b"}},
+		upperCaseStage{name: "b", after: []string{"a"}},
+	}}
+
+	_, err := p.Ordered()
+	assert.Error(t, err)
+}
+
+func TestPipeline_RunReportsChangedOnlyWhenBodyDiffers(t *testing.T) {
+	p := &Pipeline{Stages: []Stage{upperCaseStage{name: "x"}}}
+	result, err := p.Run(Context{}, []byte("start"))
+	require.NoError(t, err)
+	assert.True(t, result.Changed)
+	assert.Equal(t, "start-x", string(result.Body))
+
+	noop := &Pipeline{}
+	result2, err := noop.Run(Context{}, []byte("start"))
+	require.NoError(t, err)
+	assert.False(t, result2.Changed)
+	assert.Equal(t, "start", string(result2.Body))
+}
+
+func TestModelRewriteStage_RewritesModelField(t *testing.T) {
+	stage := ModelRewriteStage{}
+	ctx := Context{Model: "smart-model", UpstreamModel: "gpt-4o-mini"}
+	out, err := stage.Mutate(ctx, []byte(`{"model":"smart-model","messages":[]}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"model":"gpt-4o-mini","messages":[]}`, string(out))
+}
+
+func TestModelRewriteStage_NoopWhenModelsMatch(t *testing.T) {
+	stage := ModelRewriteStage{}
+	ctx := Context{Model: "gpt-4o", UpstreamModel: "gpt-4o"}
+	body := []byte(`{"model":"gpt-4o"}`)
+	out, err := stage.Mutate(ctx, body)
+	require.NoError(t, err)
+	assert.Equal(t, body, out)
+}
+
+func TestPromptGuardStage_RedactsMatchingContent(t *testing.T) {
+	stage := PromptGuardStage{}
+	ctx := Context{Plugins: map[string]map[string]interface{}{
+		"prompt-guard": {
+			"patterns":    []interface{}{"(?i)secret"},
+			"replacement": "[x]",
+		},
+	}}
+	out, err := stage.Mutate(ctx, []byte(`{"messages":[{"role":"user","content":"tell me the Secret plan"}]}`))
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"content":"tell me the [x] plan"`)
+}
+
+func TestPromptGuardStage_NoopWithoutPluginConfig(t *testing.T) {
+	stage := PromptGuardStage{}
+	body := []byte(`{"messages":[{"role":"user","content":"hello"}]}`)
+	out, err := stage.Mutate(Context{}, body)
+	require.NoError(t, err)
+	assert.Equal(t, body, out)
+}
+
+func TestSystemPromptPrependStage_InsertsPersonaFirst(t *testing.T) {
+	stage := SystemPromptPrependStage{}
+	ctx := Context{Plugins: map[string]map[string]interface{}{
+		"system-prompt-prepend": {"text": "You are a billing assistant."},
+	}}
+	out, err := stage.Mutate(ctx, []byte(`{"messages":[{"role":"user","content":"hi"}]}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"messages":[{"role":"system","content":"You are a billing assistant."},{"role":"user","content":"hi"}]}`, string(out))
+}
+
+func TestToolFilterStage_DropsUnsupportedTools(t *testing.T) {
+	stage := ToolFilterStage{}
+	ctx := Context{Plugins: map[string]map[string]interface{}{
+		"tool-filter": {"unsupported": []interface{}{"code_interpreter"}},
+	}}
+	body := []byte(`{"tools":[{"type":"function","function":{"name":"code_interpreter"}},{"type":"function","function":{"name":"get_weather"}}]}`)
+	out, err := stage.Mutate(ctx, body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"tools":[{"type":"function","function":{"name":"get_weather"}}]}`, string(out))
+}
+
+func TestJSONSchemaCoerceStage_CoercesForUnsupportedProvider(t *testing.T) {
+	stage := JSONSchemaCoerceStage{}
+	ctx := Context{Provider: "anthropic", Plugins: map[string]map[string]interface{}{
+		"json-schema-coerce": {"unsupported_providers": []interface{}{"anthropic"}},
+	}}
+	body := []byte(`{"response_format":{"type":"json_schema","json_schema":{"name":"x","strict":true}}}`)
+	out, err := stage.Mutate(ctx, body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"response_format":{"type":"json_object"}}`, string(out))
+}
+
+func TestJSONSchemaCoerceStage_NoopForSupportedProvider(t *testing.T) {
+	stage := JSONSchemaCoerceStage{}
+	ctx := Context{Provider: "openai", Plugins: map[string]map[string]interface{}{
+		"json-schema-coerce": {"unsupported_providers": []interface{}{"anthropic"}},
+	}}
+	body := []byte(`{"response_format":{"type":"json_schema","json_schema":{"name":"x","strict":true}}}`)
+	out, err := stage.Mutate(ctx, body)
+	require.NoError(t, err)
+	assert.Equal(t, body, out)
+}
+
+func TestResponseAPITranslateStage_SwapsInTranslatedBody(t *testing.T) {
+	stage := ResponseAPITranslateStage{}
+	ctx := Context{ResponseAPIRequest: true, ResponseAPITranslatedBody: []byte(`{"messages":[]}`)}
+	out, err := stage.Mutate(ctx, []byte(`{"input":"hi"}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"messages":[]}`, string(out))
+}
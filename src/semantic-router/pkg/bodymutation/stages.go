@@ -0,0 +1,323 @@
+package bodymutation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// ModelRewriteStage rewrites the "model" field of a chat-completions
+// request body to Context.UpstreamModel, the provider-facing model name,
+// when it differs from Context.Model (the internal alias used for
+// headers/tracing). It has no ordering constraints of its own, but every
+// other built-in stage declares After("response-api-translate") so they
+// all see the same chat-completions shape either way.
+type ModelRewriteStage struct{}
+
+func (ModelRewriteStage) Name() string    { return "model-rewrite" }
+func (ModelRewriteStage) After() []string { return nil }
+
+func (ModelRewriteStage) Mutate(ctx Context, body []byte) ([]byte, error) {
+	if ctx.UpstreamModel == "" || ctx.UpstreamModel == ctx.Model || len(body) == 0 {
+		return body, nil
+	}
+
+	var requestMap map[string]json.RawMessage
+	if err := json.Unmarshal(body, &requestMap); err != nil {
+		return nil, fmt.Errorf("unmarshal request body: %w", err)
+	}
+	modelJSON, err := json.Marshal(ctx.UpstreamModel)
+	if err != nil {
+		return nil, fmt.Errorf("marshal upstream model: %w", err)
+	}
+	requestMap["model"] = modelJSON
+
+	return json.Marshal(requestMap)
+}
+
+// ResponseAPITranslateStage swaps in the chat-completions-shaped body
+// already produced for a Response API request (see pkg/responseapi),
+// letting every later stage operate on one request shape regardless of
+// which API the client used.
+type ResponseAPITranslateStage struct{}
+
+func (ResponseAPITranslateStage) Name() string    { return "response-api-translate" }
+func (ResponseAPITranslateStage) After() []string { return nil }
+
+func (ResponseAPITranslateStage) Mutate(ctx Context, body []byte) ([]byte, error) {
+	if !ctx.ResponseAPIRequest || len(ctx.ResponseAPITranslatedBody) == 0 {
+		return body, nil
+	}
+	return ctx.ResponseAPITranslatedBody, nil
+}
+
+// PromptGuardStage rewrites message content matching a per-decision
+// deny-list of regular expressions, configured via the "prompt-guard"
+// decision plugin:
+//
+//	plugins:
+//	  - type: prompt-guard
+//	    configuration:
+//	      patterns: ["(?i)ignore (all|previous) instructions"]
+//	      replacement: "[blocked]"
+type PromptGuardStage struct{}
+
+func (PromptGuardStage) Name() string    { return "prompt-guard" }
+func (PromptGuardStage) After() []string { return []string{"response-api-translate"} }
+
+func (PromptGuardStage) Mutate(ctx Context, body []byte) ([]byte, error) {
+	cfg := ctx.PluginConfig("prompt-guard")
+	if cfg == nil || len(body) == 0 {
+		return body, nil
+	}
+
+	rawPatterns, _ := cfg["patterns"].([]interface{})
+	if len(rawPatterns) == 0 {
+		return body, nil
+	}
+	replacement, _ := cfg["replacement"].(string)
+	if replacement == "" {
+		replacement = "[REDACTED]"
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(rawPatterns))
+	for _, p := range rawPatterns {
+		str, ok := p.(string)
+		if !ok {
+			continue
+		}
+		re, err := regexp.Compile(str)
+		if err != nil {
+			return nil, fmt.Errorf("compile prompt-guard pattern %q: %w", str, err)
+		}
+		patterns = append(patterns, re)
+	}
+
+	return rewriteMessageContent(body, func(content string) string {
+		for _, re := range patterns {
+			content = re.ReplaceAllString(content, replacement)
+		}
+		return content
+	})
+}
+
+// SystemPromptPrependStage inserts a per-decision persona system message
+// at the start of "messages", configured via the "system-prompt-prepend"
+// decision plugin:
+//
+//	plugins:
+//	  - type: system-prompt-prepend
+//	    configuration:
+//	      text: "You are a helpful billing support assistant."
+type SystemPromptPrependStage struct{}
+
+func (SystemPromptPrependStage) Name() string    { return "system-prompt-prepend" }
+func (SystemPromptPrependStage) After() []string { return []string{"response-api-translate"} }
+
+func (SystemPromptPrependStage) Mutate(ctx Context, body []byte) ([]byte, error) {
+	cfg := ctx.PluginConfig("system-prompt-prepend")
+	if cfg == nil || len(body) == 0 {
+		return body, nil
+	}
+	text, _ := cfg["text"].(string)
+	if text == "" {
+		return body, nil
+	}
+
+	var requestMap map[string]json.RawMessage
+	if err := json.Unmarshal(body, &requestMap); err != nil {
+		return nil, fmt.Errorf("unmarshal request body: %w", err)
+	}
+	var messages []json.RawMessage
+	if raw, ok := requestMap["messages"]; ok {
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			return nil, fmt.Errorf("unmarshal messages: %w", err)
+		}
+	}
+
+	persona, err := json.Marshal(map[string]string{"role": "system", "content": text})
+	if err != nil {
+		return nil, fmt.Errorf("marshal persona message: %w", err)
+	}
+	messages = append([]json.RawMessage{persona}, messages...)
+
+	messagesJSON, err := json.Marshal(messages)
+	if err != nil {
+		return nil, fmt.Errorf("marshal messages: %w", err)
+	}
+	requestMap["messages"] = messagesJSON
+
+	return json.Marshal(requestMap)
+}
+
+// ToolFilterStage drops entries from "tools[]" whose function name is
+// listed for the target model in the "tool-filter" decision plugin:
+//
+//	plugins:
+//	  - type: tool-filter
+//	    configuration:
+//	      unsupported: ["code_interpreter", "file_search"]
+type ToolFilterStage struct{}
+
+func (ToolFilterStage) Name() string    { return "tool-filter" }
+func (ToolFilterStage) After() []string { return []string{"response-api-translate"} }
+
+func (ToolFilterStage) Mutate(ctx Context, body []byte) ([]byte, error) {
+	cfg := ctx.PluginConfig("tool-filter")
+	if cfg == nil || len(body) == 0 {
+		return body, nil
+	}
+	rawUnsupported, _ := cfg["unsupported"].([]interface{})
+	if len(rawUnsupported) == 0 {
+		return body, nil
+	}
+	unsupported := make(map[string]bool, len(rawUnsupported))
+	for _, name := range rawUnsupported {
+		if str, ok := name.(string); ok {
+			unsupported[str] = true
+		}
+	}
+
+	var requestMap map[string]json.RawMessage
+	if err := json.Unmarshal(body, &requestMap); err != nil {
+		return nil, fmt.Errorf("unmarshal request body: %w", err)
+	}
+	rawTools, ok := requestMap["tools"]
+	if !ok {
+		return body, nil
+	}
+	var tools []json.RawMessage
+	if err := json.Unmarshal(rawTools, &tools); err != nil {
+		return nil, fmt.Errorf("unmarshal tools: %w", err)
+	}
+
+	filtered := make([]json.RawMessage, 0, len(tools))
+	for _, tool := range tools {
+		var wrapper struct {
+			Function struct {
+				Name string `json:"name"`
+			} `json:"function"`
+		}
+		if err := json.Unmarshal(tool, &wrapper); err == nil && unsupported[wrapper.Function.Name] {
+			continue
+		}
+		filtered = append(filtered, tool)
+	}
+	if len(filtered) == len(tools) {
+		return body, nil
+	}
+
+	toolsJSON, err := json.Marshal(filtered)
+	if err != nil {
+		return nil, fmt.Errorf("marshal filtered tools: %w", err)
+	}
+	requestMap["tools"] = toolsJSON
+
+	return json.Marshal(requestMap)
+}
+
+// JSONSchemaCoerceStage rewrites a strict-mode "response_format" into a
+// plain "json_object" for providers that don't support OpenAI's strict
+// JSON-schema mode, configured via the "json-schema-coerce" decision
+// plugin:
+//
+//	plugins:
+//	  - type: json-schema-coerce
+//	    configuration:
+//	      unsupported_providers: ["anthropic"]
+type JSONSchemaCoerceStage struct{}
+
+func (JSONSchemaCoerceStage) Name() string    { return "json-schema-coerce" }
+func (JSONSchemaCoerceStage) After() []string { return []string{"response-api-translate"} }
+
+func (JSONSchemaCoerceStage) Mutate(ctx Context, body []byte) ([]byte, error) {
+	cfg := ctx.PluginConfig("json-schema-coerce")
+	if cfg == nil || len(body) == 0 {
+		return body, nil
+	}
+	rawProviders, _ := cfg["unsupported_providers"].([]interface{})
+	coerce := false
+	for _, p := range rawProviders {
+		if str, ok := p.(string); ok && str == ctx.Provider {
+			coerce = true
+			break
+		}
+	}
+	if !coerce {
+		return body, nil
+	}
+
+	var requestMap map[string]json.RawMessage
+	if err := json.Unmarshal(body, &requestMap); err != nil {
+		return nil, fmt.Errorf("unmarshal request body: %w", err)
+	}
+	rawFormat, ok := requestMap["response_format"]
+	if !ok {
+		return body, nil
+	}
+	var format struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(rawFormat, &format); err != nil || format.Type != "json_schema" {
+		return body, nil
+	}
+
+	coerced, err := json.Marshal(map[string]string{"type": "json_object"})
+	if err != nil {
+		return nil, fmt.Errorf("marshal coerced response_format: %w", err)
+	}
+	requestMap["response_format"] = coerced
+
+	return json.Marshal(requestMap)
+}
+
+// rewriteMessageContent rewrites every string "content" field under
+// "messages" in a chat-completions request body using rewrite, leaving
+// array-shaped (multi-part) content untouched.
+func rewriteMessageContent(body []byte, rewrite func(string) string) ([]byte, error) {
+	var requestMap map[string]json.RawMessage
+	if err := json.Unmarshal(body, &requestMap); err != nil {
+		return nil, fmt.Errorf("unmarshal request body: %w", err)
+	}
+	rawMessages, ok := requestMap["messages"]
+	if !ok {
+		return body, nil
+	}
+	var messages []map[string]json.RawMessage
+	if err := json.Unmarshal(rawMessages, &messages); err != nil {
+		return nil, fmt.Errorf("unmarshal messages: %w", err)
+	}
+
+	changed := false
+	for i, msg := range messages {
+		rawContent, ok := msg["content"]
+		if !ok {
+			continue
+		}
+		var content string
+		if err := json.Unmarshal(rawContent, &content); err != nil {
+			continue // multi-part content; leave untouched
+		}
+		rewritten := rewrite(content)
+		if rewritten == content {
+			continue
+		}
+		contentJSON, err := json.Marshal(rewritten)
+		if err != nil {
+			return nil, fmt.Errorf("marshal rewritten content: %w", err)
+		}
+		messages[i]["content"] = contentJSON
+		changed = true
+	}
+	if !changed {
+		return body, nil
+	}
+
+	messagesJSON, err := json.Marshal(messages)
+	if err != nil {
+		return nil, fmt.Errorf("marshal messages: %w", err)
+	}
+	requestMap["messages"] = messagesJSON
+
+	return json.Marshal(requestMap)
+}
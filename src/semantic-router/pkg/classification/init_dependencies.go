@@ -0,0 +1,173 @@
+package classification
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// InitDependency records one file that contributed to a successful
+// AutoInitializeUnifiedClassifier run - model weights, tokenizer, or a
+// mapping JSON - so later its staleness can be checked by re-statting it
+// rather than re-reading its full contents. This mirrors the (path, size,
+// mtime) triple Julia's include_dependency records during precompilation,
+// plus the file's SHA256 at record time. NeedsReload compares size and
+// mtime only; the recorded SHA256 is not consulted there.
+type InitDependency struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+	SHA256  string
+}
+
+// InitDependencies is the full set of files one AutoInitializeUnifiedClassifier
+// run depended on. It's tracked at package scope (see CurrentInitDependencies)
+// rather than as a field on a classifier instance, so that a background
+// watcher can observe it independently of which classifier instance is
+// currently live.
+type InitDependencies struct {
+	mu   sync.RWMutex
+	deps []InitDependency
+}
+
+// NewInitDependencies stats and hashes every path, recording each as an
+// InitDependency. It fails fast on the first unreadable path.
+func NewInitDependencies(paths ...string) (*InitDependencies, error) {
+	deps := make([]InitDependency, 0, len(paths))
+	for _, path := range paths {
+		dep, err := statInitDependency(path)
+		if err != nil {
+			return nil, fmt.Errorf("tracking init dependency %s: %w", path, err)
+		}
+		deps = append(deps, dep)
+	}
+	return &InitDependencies{deps: deps}, nil
+}
+
+func statInitDependency(path string) (InitDependency, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return InitDependency{}, err
+	}
+	sum, err := streamingSHA256(path)
+	if err != nil {
+		return InitDependency{}, err
+	}
+	return InitDependency{Path: path, Size: info.Size(), ModTime: info.ModTime(), SHA256: sum}, nil
+}
+
+// NeedsReload re-stats every tracked dependency and reports whether any has
+// changed size or mtime since it was recorded - a changed LoRA weight,
+// edited mapping file, or a dependency that's gone missing entirely all
+// count as stale, since serving on a file that no longer matches what was
+// recorded is exactly the staleness this exists to catch.
+func (d *InitDependencies) NeedsReload() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, dep := range d.deps {
+		info, err := os.Stat(dep.Path)
+		if err != nil || info.Size() != dep.Size || !info.ModTime().Equal(dep.ModTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// Paths returns the tracked dependency paths, for logging and diagnostics.
+func (d *InitDependencies) Paths() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	paths := make([]string, len(d.deps))
+	for i, dep := range d.deps {
+		paths[i] = dep.Path
+	}
+	return paths
+}
+
+// collectInitDependencyPaths lists every file AutoInitializeUnifiedClassifier
+// reads on a successful run with paths: model weights, tokenizer, and
+// whichever mapping JSON files the selected mode (LoRA or legacy) actually
+// loads. A file that doesn't exist for a given model (e.g. a LoRA adapter
+// has no category_mapping.json) is simply omitted rather than treated as
+// an error here - AutoInitializeUnifiedClassifier's own loaders already
+// enforce which files are required.
+func collectInitDependencyPaths(paths *ModelPaths) []string {
+	var files []string
+	addExisting := func(dir string, names ...string) {
+		for _, name := range names {
+			full := filepath.Join(dir, name)
+			if _, err := os.Stat(full); err == nil {
+				files = append(files, full)
+			}
+		}
+	}
+
+	if paths.PreferLoRA() {
+		for _, dir := range []string{paths.LoRAIntentClassifier, paths.LoRAPIIClassifier, paths.LoRASecurityClassifier} {
+			addExisting(dir, "model.safetensors", "pytorch_model.bin", "tokenizer.json", "lora_config.json")
+		}
+		return files
+	}
+
+	addExisting(paths.ModernBertBase, "model.safetensors", "pytorch_model.bin", "tokenizer.json")
+	addExisting(paths.IntentClassifier, "category_mapping.json")
+	addExisting(paths.PIIClassifier, "pii_type_mapping.json")
+	addExisting(paths.SecurityClassifier, "jailbreak_type_mapping.json")
+	return files
+}
+
+var (
+	currentInitDependenciesMu sync.RWMutex
+	currentInitDependencies   *InitDependencies
+)
+
+// CurrentInitDependencies returns the dependency set recorded by the most
+// recent successful AutoInitializeUnifiedClassifier call, or nil if none
+// has succeeded yet.
+func CurrentInitDependencies() *InitDependencies {
+	currentInitDependenciesMu.RLock()
+	defer currentInitDependenciesMu.RUnlock()
+	return currentInitDependencies
+}
+
+func setCurrentInitDependencies(d *InitDependencies) {
+	currentInitDependenciesMu.Lock()
+	defer currentInitDependenciesMu.Unlock()
+	currentInitDependencies = d
+}
+
+// WatchInitDependencies polls CurrentInitDependencies for staleness every
+// interval and calls reload whenever NeedsReload reports true. reload is
+// expected to call AutoInitializeUnifiedClassifier again and atomically
+// swap in the resulting classifier (GetGlobalUnifiedClassifier's own
+// synchronization makes this safe), so a retrained LoRA or an edited
+// mapping file picked up from disk takes effect without a process restart.
+// The returned stop func halts the watcher goroutine and must be called to
+// avoid leaking it.
+func WatchInitDependencies(interval time.Duration, reload func() error) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				deps := CurrentInitDependencies()
+				if deps == nil || !deps.NeedsReload() {
+					continue
+				}
+				fmt.Printf("[InitWatcher] Detected stale init dependency, triggering reload\n")
+				if err := reload(); err != nil {
+					fmt.Printf("[InitWatcher] ❌ Reload failed: %v\n", err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -55,9 +55,59 @@ type ArchitectureModels struct {
 	Security string
 }
 
-// AutoDiscoverModels automatically discovers model files in the models directory
-// Uses intelligent architecture selection: BERT > RoBERTa > ModernBERT
-func AutoDiscoverModels(modelsDir string) (*ModelPaths, error) {
+// AutoDiscoverModels discovers model files across one or more model roots,
+// e.g. the directories from a MODEL_PATH=/opt/models:/srv/cache/models
+// environment variable (see SplitModelPath). Roots are searched in the
+// order given, stopping at the first one that yields a complete
+// architecture set; see discoverAcrossRoots for the exact precedence. No
+// roots defaults to a single "./models", matching this function's original
+// single-directory signature.
+func AutoDiscoverModels(roots ...string) (*ModelPaths, error) {
+	paths, _, err := discoverAcrossRoots(roots...)
+	return paths, err
+}
+
+// discoverAcrossRoots is AutoDiscoverModels' implementation: it runs
+// autoDiscoverModelsInRoot over roots in priority order - analogous to
+// Erlang's code:add_path layered search path - and returns the first
+// complete architecture set found, along with which root it came from. If
+// no root is complete, it falls back to the last root that produced any
+// result at all (legacy models, or an incomplete LoRA set), preserving
+// AutoDiscoverModels' original single-root fallback behavior when there's
+// only one root to consider.
+func discoverAcrossRoots(roots ...string) (*ModelPaths, string, error) {
+	if len(roots) == 0 {
+		roots = []string{"./models"}
+	}
+
+	var lastPaths *ModelPaths
+	var lastRoot string
+	var lastErr error
+	for _, root := range roots {
+		paths, err := autoDiscoverModelsInRoot(root)
+		if err != nil {
+			fmt.Printf("[ModelDiscovery] Root %s failed: %v\n", root, err)
+			lastErr = err
+			continue
+		}
+
+		lastPaths, lastRoot = paths, root
+		if paths.IsComplete() {
+			fmt.Printf("[ModelDiscovery] ✅ Selected root %s\n", root)
+			return paths, root, nil
+		}
+		fmt.Printf("[ModelDiscovery] Root %s has no complete architecture set, trying next root\n", root)
+	}
+
+	if lastPaths != nil {
+		return lastPaths, lastRoot, nil
+	}
+	return nil, "", lastErr
+}
+
+// autoDiscoverModelsInRoot automatically discovers model files in a single
+// models directory. Uses intelligent architecture selection: BERT > RoBERTa > ModernBERT
+func autoDiscoverModelsInRoot(modelsDir string) (*ModelPaths, error) {
 	if modelsDir == "" {
 		modelsDir = "./models"
 	}
@@ -67,6 +117,21 @@ func AutoDiscoverModels(modelsDir string) (*ModelPaths, error) {
 		return nil, fmt.Errorf("models directory does not exist: %s", modelsDir)
 	}
 
+	// A models.yaml/models.toml manifest, if present directly inside
+	// modelsDir, declares explicit per-task model paths and short-circuits
+	// the directory-name heuristics below entirely - the same way a Cargo
+	// workspace's Cargo.toml target declarations override implicit
+	// src/bin/*.rs discovery.
+	if manifestPath, ok := findModelManifest(modelsDir); ok {
+		fmt.Printf("[ModelDiscovery] Found manifest %s, skipping directory scan\n", manifestPath)
+		paths, err := loadModelPathsFromManifest(manifestPath)
+		if err != nil {
+			return nil, fmt.Errorf("manifest-driven model discovery failed: %w", err)
+		}
+		fmt.Printf("[ModelDiscovery] ✅ Loaded models from manifest (architecture: %s)\n", paths.LoRAArchitecture)
+		return paths, nil
+	}
+
 	// Collect all available LoRA models by architecture
 	architectureModels := map[string]*ArchitectureModels{
 		"bert":       {Intent: "", PII: "", Security: ""},
@@ -77,6 +142,12 @@ func AutoDiscoverModels(modelsDir string) (*ModelPaths, error) {
 	// Legacy models for fallback
 	legacyPaths := &ModelPaths{}
 
+	// allDirNames collects every directory name seen during the walk, so
+	// that if no architecture ends up complete, suggestArchitectureFix can
+	// propose the closest match for a misnamed directory (e.g. a typo'd
+	// extracted archive) instead of just reporting absence.
+	var allDirNames []string
+
 	// Walk through the models directory to collect all models
 	err := filepath.Walk(modelsDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -88,6 +159,7 @@ func AutoDiscoverModels(modelsDir string) (*ModelPaths, error) {
 			return nil
 		}
 
+		allDirNames = append(allDirNames, info.Name())
 		dirName := strings.ToLower(info.Name())
 
 		// Collect LoRA models by architecture
@@ -215,11 +287,68 @@ func AutoDiscoverModels(modelsDir string) (*ModelPaths, error) {
 	}
 
 	fmt.Printf("[ModelDiscovery] ⚠️  No complete LoRA architecture found, falling back to legacy models\n")
+	for _, hint := range suggestArchitectureFix(architecturePriority, architectureModels, allDirNames) {
+		fmt.Printf("[ModelDiscovery] 💡 %s\n", hint)
+	}
 
 	// If no complete LoRA architecture set found, return legacy models
 	return legacyPaths, nil
 }
 
+// roleLoRAPrefix maps each classifier role to the directory-name prefix
+// autoDiscoverModelsInRoot's walk matches it against.
+var roleLoRAPrefix = map[string]string{
+	"Intent":   "lora_intent_classifier",
+	"PII":      "lora_pii_detector",
+	"Security": "lora_jailbreak_classifier",
+}
+
+// architectureBaseName maps an architecture key to the base-model directory
+// suffix detectArchitectureFromPath expects to see it paired with.
+var architectureBaseName = map[string]string{
+	"bert":       "bert-base-uncased",
+	"roberta":    "roberta-base",
+	"modernbert": "modernbert-base",
+}
+
+// suggestArchitectureFix builds "did you mean" diagnostics for the
+// highest-priority architecture that found at least one, but not all three,
+// of its required models: for each missing role, it proposes the directory
+// name (among those the walk actually saw) closest to what that role's
+// directory is expected to be named, e.g. lora_intent_classifier_bert-base-uncased.
+// This targets the common case of a misnamed or partially-extracted
+// archive, where the intended directory exists under a slightly wrong name
+// rather than not existing at all.
+func suggestArchitectureFix(architecturePriority []string, architectureModels map[string]*ArchitectureModels, dirNames []string) []string {
+	for _, arch := range architecturePriority {
+		models := architectureModels[arch]
+		present := 0
+		for _, p := range []string{models.Intent, models.PII, models.Security} {
+			if p != "" {
+				present++
+			}
+		}
+		if present == 0 || present == 3 {
+			continue // either untouched, or complete-but-invalid (already reported by validateModelDirectory)
+		}
+
+		var hints []string
+		roles := map[string]string{"Intent": models.Intent, "PII": models.PII, "Security": models.Security}
+		for _, role := range []string{"Intent", "PII", "Security"} {
+			if roles[role] != "" {
+				continue
+			}
+
+			expected := roleLoRAPrefix[role] + "_" + architectureBaseName[arch]
+			if match, ok := suggestClosestName(dirNames, expected); ok {
+				hints = append(hints, fmt.Sprintf("%s architecture is missing %s classifier - found %q, did you mean %q?", arch, role, match, expected))
+			}
+		}
+		return hints
+	}
+	return nil
+}
+
 // detectArchitectureFromPath detects model architecture from directory name
 func detectArchitectureFromPath(dirName string) string {
 	switch {
@@ -327,24 +456,36 @@ func validateModelDirectory(path, modelName string) error {
 		}
 	}
 
+	// If the directory carries a checksums.json sidecar, verify content
+	// integrity rather than trusting presence alone - this is what actually
+	// catches a CI partial download, as opposed to "at least one common
+	// file exists" above.
+	if err := verifyDirectoryIntegrity(path, modelName); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // GetModelDiscoveryInfo returns detailed information about model discovery
-func GetModelDiscoveryInfo(modelsDir string) map[string]interface{} {
+// across roots (see AutoDiscoverModels), including which root the returned
+// models actually came from.
+func GetModelDiscoveryInfo(roots ...string) map[string]interface{} {
 	info := map[string]interface{}{
-		"models_directory":  modelsDir,
+		"model_roots":       roots,
+		"selected_root":     "",
 		"discovery_status":  "failed",
 		"discovered_models": map[string]interface{}{},
 		"missing_models":    []string{},
 		"errors":            []string{},
 	}
 
-	paths, err := AutoDiscoverModels(modelsDir)
+	paths, selectedRoot, err := discoverAcrossRoots(roots...)
 	if err != nil {
 		info["errors"] = append(info["errors"].([]string), err.Error())
 		return info
 	}
+	info["selected_root"] = selectedRoot
 
 	// Add discovered models
 	discovered := map[string]interface{}{
@@ -398,21 +539,49 @@ func AutoInitializeUnifiedClassifier(modelsDir string) (*UnifiedClassifier, erro
 		return nil, fmt.Errorf("model validation failed: %w", err)
 	}
 
-	// Check if we should use LoRA models
+	return initializeUnifiedClassifierFromPaths(paths)
+}
+
+// initializeUnifiedClassifierFromPaths is AutoInitializeUnifiedClassifier's
+// shared tail: given already-discovered-and-validated paths, it picks LoRA
+// vs. legacy initialization and records init dependencies. It's also used
+// by AutoInitializeUnifiedClassifierWithFetch once paths have been
+// completed by a Hub fetch, so both entry points stay consistent about what
+// "initialized" means.
+func initializeUnifiedClassifierFromPaths(paths *ModelPaths) (*UnifiedClassifier, error) {
+	var classifier *UnifiedClassifier
+	var err error
 	if paths.PreferLoRA() {
 		fmt.Printf("[AutoInit] ✅ Using LoRA models (architecture: %s)\n", paths.LoRAArchitecture)
 		fmt.Printf("[AutoInit]   Intent:   %s\n", paths.LoRAIntentClassifier)
 		fmt.Printf("[AutoInit]   PII:      %s\n", paths.LoRAPIIClassifier)
 		fmt.Printf("[AutoInit]   Security: %s\n", paths.LoRASecurityClassifier)
-		return initializeLoRAUnifiedClassifier(paths)
+		classifier, err = initializeLoRAUnifiedClassifier(paths)
+	} else {
+		// Fallback to legacy ModernBERT initialization
+		fmt.Printf("[AutoInit] ⚠️  Falling back to legacy ModernBERT models\n")
+		fmt.Printf("[AutoInit]   Intent:   %s\n", paths.IntentClassifier)
+		fmt.Printf("[AutoInit]   PII:      %s\n", paths.PIIClassifier)
+		fmt.Printf("[AutoInit]   Security: %s\n", paths.SecurityClassifier)
+		classifier, err = initializeLegacyUnifiedClassifier(paths)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Record which files this run depended on, so NeedsReload (via
+	// CurrentInitDependencies) and WatchInitDependencies can later detect a
+	// retrained LoRA or an edited mapping file and trigger a live reload
+	// without a process restart.
+	if depPaths := collectInitDependencyPaths(paths); len(depPaths) > 0 {
+		if deps, depErr := NewInitDependencies(depPaths...); depErr != nil {
+			fmt.Printf("[AutoInit] ⚠️  Failed to record init dependencies: %v\n", depErr)
+		} else {
+			setCurrentInitDependencies(deps)
+		}
 	}
 
-	// Fallback to legacy ModernBERT initialization
-	fmt.Printf("[AutoInit] ⚠️  Falling back to legacy ModernBERT models\n")
-	fmt.Printf("[AutoInit]   Intent:   %s\n", paths.IntentClassifier)
-	fmt.Printf("[AutoInit]   PII:      %s\n", paths.PIIClassifier)
-	fmt.Printf("[AutoInit]   Security: %s\n", paths.SecurityClassifier)
-	return initializeLegacyUnifiedClassifier(paths)
+	return classifier, nil
 }
 
 // initializeLoRAUnifiedClassifier initializes with LoRA models
@@ -0,0 +1,268 @@
+package classification
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FetchPolicy configures AutoInitializeUnifiedClassifierWithFetch's
+// fallback to the Hugging Face Hub when local discovery under modelsDir
+// finds no complete architecture and no legacy model set. Each Entries
+// item names a classifier role (see ModelManifestEntry.Task) plus the Hub
+// repo to fetch it from.
+type FetchPolicy struct {
+	// CacheDir is where fetched model directories land, one subdirectory
+	// per Entries item. Required if Entries is non-empty.
+	CacheDir string
+
+	// Token, if set, is sent as a bearer credential on every Hub request -
+	// the same HF_TOKEN environment variable convention huggingface_hub
+	// itself uses. See FetchPolicyFromEnv.
+	Token string
+
+	Entries []FetchEntry
+}
+
+// FetchEntry names one Hugging Face Hub repo to pull a classifier role's
+// files from.
+type FetchEntry struct {
+	Task     string // "intent", "pii", or "security" - see ModelManifestEntry.Task
+	RepoID   string // e.g. "org/lora-intent-classifier-bert-base-uncased"
+	Revision string // branch, tag, or commit SHA; "main" if empty
+}
+
+// FetchPolicyFromEnv builds a FetchPolicy reading its token from the
+// HF_TOKEN environment variable, if set.
+func FetchPolicyFromEnv(cacheDir string, entries ...FetchEntry) FetchPolicy {
+	return FetchPolicy{CacheDir: cacheDir, Token: os.Getenv("HF_TOKEN"), Entries: entries}
+}
+
+// hubFetchFiles are the files fetchModel downloads for every role, in
+// addition to the role's own mapping JSON (see roleMappingFile).
+var hubFetchFiles = []string{"config.json", "model.safetensors", "tokenizer.json", "lora_config.json"}
+
+// roleMappingFile is the extra mapping JSON fetchModel downloads for a
+// given role, beyond hubFetchFiles.
+var roleMappingFile = map[string]string{
+	"intent":   "category_mapping.json",
+	"pii":      "pii_type_mapping.json",
+	"security": "jailbreak_type_mapping.json",
+}
+
+// loraPrefixForTask maps a manifest-style task name to the directory-name
+// prefix autoDiscoverModelsInRoot's walk matches it against, so a fetched
+// directory is actually picked up by the following re-discovery pass.
+func loraPrefixForTask(task string) (string, bool) {
+	switch strings.ToLower(task) {
+	case "intent":
+		return "lora_intent_classifier", true
+	case "pii":
+		return "lora_pii_detector", true
+	case "security":
+		return "lora_jailbreak_classifier", true
+	default:
+		return "", false
+	}
+}
+
+// AutoInitializeUnifiedClassifierWithFetch behaves like
+// AutoInitializeUnifiedClassifier, except that when local discovery under
+// modelsDir finds no complete architecture and no legacy model set, it
+// fetches whichever roles policy.Entries declares from the Hugging Face Hub
+// into policy.CacheDir and re-runs discovery across both modelsDir and
+// policy.CacheDir (see AutoDiscoverModels) before giving up. The plain,
+// pure-local AutoInitializeUnifiedClassifier is left unchanged for callers
+// that never want network access.
+func AutoInitializeUnifiedClassifierWithFetch(ctx context.Context, modelsDir string, policy FetchPolicy) (*UnifiedClassifier, error) {
+	if paths, err := AutoDiscoverModels(modelsDir); err == nil {
+		if verr := ValidateModelPaths(paths); verr == nil {
+			return initializeUnifiedClassifierFromPaths(paths)
+		}
+	}
+
+	fmt.Printf("[FetchInit] Local discovery under %s incomplete, falling back to Hub fetch\n", modelsDir)
+	if len(policy.Entries) == 0 {
+		return nil, fmt.Errorf("local model discovery under %s found no complete model set and fetch policy has no entries configured", modelsDir)
+	}
+	if policy.CacheDir == "" {
+		return nil, fmt.Errorf("fetch policy requires a CacheDir")
+	}
+
+	for _, entry := range policy.Entries {
+		if err := fetchModel(ctx, policy, entry); err != nil {
+			return nil, fmt.Errorf("fetching %s from %s: %w", entry.Task, entry.RepoID, err)
+		}
+	}
+
+	fetchedPaths, err := AutoDiscoverModels(modelsDir, policy.CacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("model discovery after Hub fetch failed: %w", err)
+	}
+	if err := ValidateModelPaths(fetchedPaths); err != nil {
+		return nil, fmt.Errorf("model validation after Hub fetch failed: %w", err)
+	}
+	return initializeUnifiedClassifierFromPaths(fetchedPaths)
+}
+
+// fetchModel downloads entry's files into a staging directory under
+// policy.CacheDir and, only once every file has downloaded successfully,
+// atomically renames the staging directory into its final name - so a
+// crash mid-download never leaves a half-populated directory that would
+// confuse autoDiscoverModelsInRoot's walk. A destination that already
+// exists is left alone; fetchModel never overwrites a prior fetch.
+func fetchModel(ctx context.Context, policy FetchPolicy, entry FetchEntry) error {
+	prefix, ok := loraPrefixForTask(entry.Task)
+	if !ok {
+		return fmt.Errorf("unknown task %q (expected intent, pii, or security)", entry.Task)
+	}
+
+	destDir := fetchDestDir(policy, entry, prefix)
+	if _, err := os.Stat(destDir); err == nil {
+		fmt.Printf("[FetchInit] %s already present at %s, skipping download\n", entry.RepoID, destDir)
+		return nil
+	}
+
+	tmpDir := destDir + ".tmp-download"
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return fmt.Errorf("creating download staging dir: %w", err)
+	}
+
+	revision := entry.Revision
+	if revision == "" {
+		revision = "main"
+	}
+
+	files := append([]string(nil), hubFetchFiles...)
+	if mapping, ok := roleMappingFile[strings.ToLower(entry.Task)]; ok {
+		files = append(files, mapping)
+	}
+
+	downloader := &httpHubDownloader{token: policy.Token}
+	for _, filename := range files {
+		url := fmt.Sprintf("https://huggingface.co/%s/resolve/%s/%s", entry.RepoID, revision, filename)
+		dest := filepath.Join(tmpDir, filename)
+		fmt.Printf("[FetchInit] Downloading %s\n", url)
+		if err := downloadFile(ctx, downloader, url, dest); err != nil {
+			return fmt.Errorf("downloading %s: %w", filename, err)
+		}
+	}
+
+	if err := os.Rename(tmpDir, destDir); err != nil {
+		return fmt.Errorf("finalizing download of %s: %w", entry.RepoID, err)
+	}
+	fmt.Printf("[FetchInit] ✅ Fetched %s into %s\n", entry.RepoID, destDir)
+	return nil
+}
+
+// fetchDestDir names entry's destination directory so it's recognized by
+// autoDiscoverModelsInRoot's prefix-based walk: prefix (the role's
+// lora_*_classifier name) followed by RepoID's final path segment.
+func fetchDestDir(policy FetchPolicy, entry FetchEntry, prefix string) string {
+	suffix := entry.RepoID
+	if idx := strings.LastIndex(suffix, "/"); idx >= 0 {
+		suffix = suffix[idx+1:]
+	}
+	suffix = strings.ReplaceAll(strings.ToLower(suffix), "-", "_")
+	return filepath.Join(policy.CacheDir, prefix+"_"+suffix)
+}
+
+// HubDownloader abstracts the actual network transfer so fetchModel's
+// resume/atomic-rename logic can be exercised independently of the real
+// Hugging Face Hub.
+type HubDownloader interface {
+	// Download streams url to w, sending a Range header starting at offset
+	// when offset > 0 to resume a partial download, and returns the
+	// response's ETag if the server supplied one.
+	Download(ctx context.Context, url string, offset int64, w io.Writer) (etag string, err error)
+}
+
+// httpHubDownloader is the default HubDownloader, talking to the real
+// Hugging Face Hub over HTTPS.
+type httpHubDownloader struct {
+	token string
+}
+
+func (d *httpHubDownloader) Download(ctx context.Context, url string, offset int64, w io.Writer) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	if d.token != "" {
+		req.Header.Set("Authorization", "Bearer "+d.token)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return "", fmt.Errorf("unexpected status %s fetching %s", resp.Status, url)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return "", err
+	}
+	return strings.Trim(resp.Header.Get("ETag"), `"`), nil
+}
+
+// downloadFile downloads url to destPath via downloader, resuming from a
+// previous attempt's partial ".part" file if one exists, and verifying the
+// result against the Hub's ETag when that ETag looks like a SHA256 (LFS-
+// tracked files on the Hub use their content SHA256 as the ETag; small
+// git-stored files use a different hash and are left unverified).
+func downloadFile(ctx context.Context, downloader HubDownloader, url, destPath string) error {
+	tmpPath := destPath + ".part"
+
+	var offset int64
+	if info, err := os.Stat(tmpPath); err == nil {
+		offset = info.Size()
+	}
+
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	etag, downloadErr := downloader.Download(ctx, url, offset, f)
+	if closeErr := f.Close(); downloadErr == nil {
+		downloadErr = closeErr
+	}
+	if downloadErr != nil {
+		return downloadErr
+	}
+
+	if looksLikeSHA256(etag) {
+		sum, err := streamingSHA256(tmpPath)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(sum, etag) {
+			return fmt.Errorf("%s: SHA256 mismatch after download (Hub ETag %s, got %s)", destPath, etag, sum)
+		}
+	}
+
+	return os.Rename(tmpPath, destPath)
+}
+
+// looksLikeSHA256 reports whether s is a 64-character hex string.
+func looksLikeSHA256(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+package classification
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// integrityCheckFiles are the files validateModelDirectory hashes when a
+// checksums.json sidecar is present, in priority order. Not every file is
+// expected in every directory - a LoRA adapter has no model.safetensors,
+// for instance - so a file checksums.json doesn't mention is simply not
+// checked, rather than treated as a mismatch.
+var integrityCheckFiles = []string{"model.safetensors", "pytorch_model.bin", "tokenizer.json", "lora_config.json"}
+
+// checksumsFilename is the sidecar file verifyDirectoryIntegrity looks for
+// inside a model directory, recording the expected SHA256 of each file
+// named in integrityCheckFiles. A manifest entry's own SHA256 field (see
+// model_manifest.go) covers the single adapter-weight file it names;
+// checksums.json covers every file in integrityCheckFiles at once.
+const checksumsFilename = "checksums.json"
+
+// hashCacheFilename is the per-directory cache verifyDirectoryIntegrity
+// maintains so a file already hashed (and unchanged since) isn't re-read on
+// every subsequent boot.
+const hashCacheFilename = ".model_hash_cache.json"
+
+// fileHashCacheEntry is one cached hash, valid only as long as the file's
+// size and mtime still match what was recorded when the hash was computed.
+type fileHashCacheEntry struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mod_time_unix_nano"`
+	SHA256  string `json:"sha256"`
+}
+
+// hashCacheFile is the on-disk shape of hashCacheFilename, keyed by
+// filename within the directory it lives in.
+type hashCacheFile struct {
+	Entries map[string]fileHashCacheEntry `json:"entries"`
+}
+
+// verifyDirectoryIntegrity compares each file named in checksums.json
+// (inside path) against its expected SHA256, using a cached hash when
+// path's size+mtime haven't changed since it was last computed. A
+// directory with no checksums.json skips integrity verification entirely -
+// validateModelDirectory's presence check is the only guard in that case,
+// matching its behavior before this check existed. A directory with a
+// checksums.json that doesn't match fails with a report naming every
+// mismatched or missing file, so a CI partial download is caught with a
+// specific cause instead of a generic "appears to be empty or invalid".
+func verifyDirectoryIntegrity(path, modelName string) error {
+	expected, ok, err := loadChecksumManifest(path)
+	if err != nil {
+		return fmt.Errorf("%s: reading %s: %w", modelName, checksumsFilename, err)
+	}
+	if !ok {
+		return nil
+	}
+
+	cache := loadHashCache(path)
+	var mismatches []string
+	for _, filename := range integrityCheckFiles {
+		want, ok := expected[filename]
+		if !ok {
+			continue
+		}
+
+		full := filepath.Join(path, filename)
+		got, err := cachedFileSHA256(full, cache)
+		if err != nil {
+			if os.IsNotExist(err) {
+				mismatches = append(mismatches, fmt.Sprintf("%s: missing (expected sha256 %s)", filename, want))
+				continue
+			}
+			return fmt.Errorf("%s: hashing %s: %w", modelName, filename, err)
+		}
+		if !strings.EqualFold(got, want) {
+			mismatches = append(mismatches, fmt.Sprintf("%s: sha256 mismatch (expected %s, got %s)", filename, want, got))
+		}
+	}
+	saveHashCache(path, cache)
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%s at %s failed integrity verification:\n  %s", modelName, path, strings.Join(mismatches, "\n  "))
+	}
+	return nil
+}
+
+// loadChecksumManifest reads dir's checksums.json, if present. A missing
+// file is reported via the ok return, not an error.
+func loadChecksumManifest(dir string) (map[string]string, bool, error) {
+	data, err := os.ReadFile(filepath.Join(dir, checksumsFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	var checksums map[string]string
+	if err := json.Unmarshal(data, &checksums); err != nil {
+		return nil, false, fmt.Errorf("parsing %s: %w", checksumsFilename, err)
+	}
+	return checksums, true, nil
+}
+
+// loadHashCache reads dir's hash cache, returning an empty one if it's
+// missing or unreadable - a cold cache just means everything gets
+// rehashed, not a failure.
+func loadHashCache(dir string) *hashCacheFile {
+	data, err := os.ReadFile(filepath.Join(dir, hashCacheFilename))
+	if err != nil {
+		return &hashCacheFile{Entries: map[string]fileHashCacheEntry{}}
+	}
+
+	var cache hashCacheFile
+	if err := json.Unmarshal(data, &cache); err != nil || cache.Entries == nil {
+		return &hashCacheFile{Entries: map[string]fileHashCacheEntry{}}
+	}
+	return &cache
+}
+
+// saveHashCache persists cache back to dir. A write failure (read-only
+// mount, no space) is swallowed - the cache is a performance optimization,
+// not something integrity verification depends on.
+func saveHashCache(dir string, cache *hashCacheFile) {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(dir, hashCacheFilename), data, 0o644)
+}
+
+// cachedFileSHA256 returns full's SHA256, reusing cache's entry for it when
+// the file's current size and mtime still match what was recorded, and
+// computing (then recording) it otherwise.
+func cachedFileSHA256(full string, cache *hashCacheFile) (string, error) {
+	info, err := os.Stat(full)
+	if err != nil {
+		return "", err
+	}
+
+	key := filepath.Base(full)
+	if entry, ok := cache.Entries[key]; ok && entry.Size == info.Size() && entry.ModTime == info.ModTime().UnixNano() {
+		return entry.SHA256, nil
+	}
+
+	sum, err := streamingSHA256(full)
+	if err != nil {
+		return "", err
+	}
+
+	cache.Entries[key] = fileHashCacheEntry{Size: info.Size(), ModTime: info.ModTime().UnixNano(), SHA256: sum}
+	return sum, nil
+}
+
+// streamingSHA256 hashes path without reading it fully into memory, so a
+// multi-gigabyte model.safetensors doesn't blow up process RSS just to be
+// verified.
+func streamingSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,300 @@
+package classification
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// manifestFilenames are the manifest names findModelManifest looks for in a
+// models directory, in priority order. When one is present it short-circuits
+// AutoDiscoverModels' filepath.Walk heuristics entirely.
+var manifestFilenames = []string{"models.yaml", "models.yml", "models.toml"}
+
+// ModelManifestEntry declares one role's model explicitly, replacing the
+// directory-name heuristics (lora_intent_classifier, strings.Contains(dirName,
+// "modernbert")) AutoDiscoverModels otherwise relies on.
+type ModelManifestEntry struct {
+	// Task identifies which classifier role this entry fills: "intent",
+	// "pii", or "security".
+	Task string `yaml:"task" toml:"task" json:"task"`
+
+	// Architecture is the base model architecture this entry was trained
+	// against ("bert", "roberta", "modernbert"). Every entry in a resolved
+	// manifest must agree, and it's cross-checked against the on-disk
+	// lora_config.json (see crossCheckManifestArchitecture).
+	Architecture string `yaml:"architecture" toml:"architecture" json:"architecture"`
+
+	// Path is the model directory, resolved relative to the manifest file
+	// that declares it (absolute paths are used as-is).
+	Path string `yaml:"path" toml:"path" json:"path"`
+
+	// Revision is an optional free-form tag (commit hash, dataset version)
+	// recorded for operator bookkeeping; discovery doesn't interpret it.
+	Revision string `yaml:"revision,omitempty" toml:"revision,omitempty" json:"revision,omitempty"`
+
+	// SHA256, if set, is checked against Path's adapter weight file (see
+	// manifestHashCandidates); a mismatch fails discovery outright rather
+	// than silently loading a model that drifted from what was declared.
+	SHA256 string `yaml:"sha256,omitempty" toml:"sha256,omitempty" json:"sha256,omitempty"`
+}
+
+// ModelManifest is the top-level shape of a models.yaml/models.toml file.
+type ModelManifest struct {
+	// Extends references another manifest (relative to this one's directory
+	// unless absolute) to load and merge first, so a base manifest can
+	// declare common entries and a per-env manifest overrides just the ones
+	// that differ - the same base-plus-overrides shape as a Cargo workspace.
+	Extends string `yaml:"extends,omitempty" toml:"extends,omitempty" json:"extends,omitempty"`
+
+	Models []ModelManifestEntry `yaml:"models" toml:"models" json:"models"`
+}
+
+// findModelManifest reports the first manifest file present directly inside
+// modelsDir, if any.
+func findModelManifest(modelsDir string) (string, bool) {
+	for _, name := range manifestFilenames {
+		candidate := filepath.Join(modelsDir, name)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// loadRawManifest reads and parses path, dispatching on its extension
+// (.toml vs. .yaml/.yml), without resolving Extends or entry paths.
+func loadRawManifest(path string) (*ModelManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest %s: %w", path, err)
+	}
+
+	var manifest ModelManifest
+	if strings.ToLower(filepath.Ext(path)) == ".toml" {
+		if err := toml.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("parsing TOML manifest %s: %w", path, err)
+		}
+	} else {
+		if err := yaml.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("parsing YAML manifest %s: %w", path, err)
+		}
+	}
+	return &manifest, nil
+}
+
+// resolveModelManifest loads path, resolves every entry's Path relative to
+// path's own directory, and - if the manifest declares Extends - recursively
+// resolves and merges the base manifest first. visited (keyed by absolute
+// path) guards against an Extends cycle; callers should pass an empty map.
+func resolveModelManifest(path string, visited map[string]bool) (*ModelManifest, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving manifest path %s: %w", path, err)
+	}
+	if visited[abs] {
+		return nil, fmt.Errorf("manifest extends cycle detected at %s", path)
+	}
+	visited[abs] = true
+
+	manifest, err := loadRawManifest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	for i := range manifest.Models {
+		if !filepath.IsAbs(manifest.Models[i].Path) {
+			manifest.Models[i].Path = filepath.Join(dir, manifest.Models[i].Path)
+		}
+	}
+
+	if manifest.Extends == "" {
+		return manifest, nil
+	}
+
+	basePath := manifest.Extends
+	if !filepath.IsAbs(basePath) {
+		basePath = filepath.Join(dir, basePath)
+	}
+	base, err := resolveModelManifest(basePath, visited)
+	if err != nil {
+		return nil, fmt.Errorf("resolving manifest %s's base %q: %w", path, manifest.Extends, err)
+	}
+
+	return &ModelManifest{Models: mergeManifestEntries(base.Models, manifest.Models)}, nil
+}
+
+// mergeManifestEntries overlays override onto base, keyed by Task: an entry
+// in override replaces base's entry for the same Task, and a Task present in
+// only one of the two is kept as-is. Order follows base's first, then any
+// Task override introduces that base didn't have.
+func mergeManifestEntries(base, override []ModelManifestEntry) []ModelManifestEntry {
+	byTask := make(map[string]ModelManifestEntry, len(base)+len(override))
+	order := make([]string, 0, len(base)+len(override))
+
+	for _, e := range base {
+		if _, ok := byTask[e.Task]; !ok {
+			order = append(order, e.Task)
+		}
+		byTask[e.Task] = e
+	}
+	for _, e := range override {
+		if _, ok := byTask[e.Task]; !ok {
+			order = append(order, e.Task)
+		}
+		byTask[e.Task] = e
+	}
+
+	merged := make([]ModelManifestEntry, 0, len(order))
+	for _, task := range order {
+		merged = append(merged, byTask[task])
+	}
+	return merged
+}
+
+// loadModelPathsFromManifest resolves manifestPath (following Extends) into
+// a *ModelPaths, validating along the way that every declared path exists,
+// every entry agrees on Architecture, and - for entries with SHA256 set -
+// that the on-disk weights still hash to what the manifest expects.
+func loadModelPathsFromManifest(manifestPath string) (*ModelPaths, error) {
+	manifest, err := resolveModelManifest(manifestPath, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	paths := &ModelPaths{}
+	declaredArch := ""
+
+	for _, entry := range manifest.Models {
+		if _, err := os.Stat(entry.Path); err != nil {
+			return nil, fmt.Errorf("manifest entry %q: %w", entry.Task, err)
+		}
+
+		if entry.Architecture != "" {
+			if declaredArch == "" {
+				declaredArch = entry.Architecture
+			} else if !strings.EqualFold(declaredArch, entry.Architecture) {
+				return nil, fmt.Errorf("manifest declares conflicting architectures %q and %q across entries", declaredArch, entry.Architecture)
+			}
+		}
+
+		if err := crossCheckManifestArchitecture(entry.Path, entry.Architecture); err != nil {
+			return nil, err
+		}
+
+		if entry.SHA256 != "" {
+			if err := verifyModelSHA256(entry.Path, entry.SHA256); err != nil {
+				return nil, err
+			}
+		}
+
+		switch strings.ToLower(entry.Task) {
+		case "intent":
+			paths.LoRAIntentClassifier = entry.Path
+		case "pii":
+			paths.LoRAPIIClassifier = entry.Path
+		case "security":
+			paths.LoRASecurityClassifier = entry.Path
+		default:
+			return nil, fmt.Errorf("manifest entry has unknown task %q (expected intent, pii, or security)", entry.Task)
+		}
+	}
+
+	if declaredArch == "" {
+		declaredArch = "bert" // matches detectArchitectureFromPath's default fallback
+	}
+	paths.LoRAArchitecture = declaredArch
+
+	if !paths.HasLoRAModels() {
+		return nil, fmt.Errorf("manifest %s does not declare all three required tasks (intent, pii, security)", manifestPath)
+	}
+
+	for name, path := range map[string]string{
+		"LoRA Intent classifier":   paths.LoRAIntentClassifier,
+		"LoRA PII classifier":      paths.LoRAPIIClassifier,
+		"LoRA Security classifier": paths.LoRASecurityClassifier,
+	} {
+		if err := validateModelDirectory(path, name); err != nil {
+			return nil, err
+		}
+	}
+
+	return paths, nil
+}
+
+// loraConfigFile is the subset of lora_config.json crossCheckManifestArchitecture
+// reads to recover the architecture a LoRA adapter was trained against, when
+// the manifest doesn't take the on-disk config at face value.
+type loraConfigFile struct {
+	Architecture        string `json:"architecture,omitempty"`
+	BaseModelNameOrPath string `json:"base_model_name_or_path,omitempty"`
+}
+
+// crossCheckManifestArchitecture compares declaredArch against the
+// architecture recorded in path's lora_config.json (its own Architecture
+// field if present, otherwise inferred from BaseModelNameOrPath the same way
+// detectArchitectureFromPath infers it from a directory name), returning an
+// error on a mismatch. A missing lora_config.json is reported by
+// validateModelDirectory, not here, so a missing file is not an error in
+// this function specifically; an unparseable one is.
+func crossCheckManifestArchitecture(path, declaredArch string) error {
+	if declaredArch == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(path, "lora_config.json"))
+	if err != nil {
+		return nil
+	}
+
+	var cfg loraConfigFile
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("parsing lora_config.json at %s: %w", path, err)
+	}
+
+	onDisk := cfg.Architecture
+	if onDisk == "" && cfg.BaseModelNameOrPath != "" {
+		onDisk = detectArchitectureFromPath(strings.ToLower(cfg.BaseModelNameOrPath))
+	}
+	if onDisk != "" && !strings.EqualFold(onDisk, declaredArch) {
+		return fmt.Errorf("manifest declares architecture %q for %s but lora_config.json indicates %q", declaredArch, path, onDisk)
+	}
+	return nil
+}
+
+// manifestHashCandidates are the adapter weight filenames verifyModelSHA256
+// checks, in priority order - the files a SHA256 pin is actually meant to
+// protect, as opposed to validateModelDirectory's broader "some model file
+// exists" check.
+var manifestHashCandidates = []string{"adapter_model.safetensors", "adapter_model.bin", "model.safetensors", "pytorch_model.bin"}
+
+// verifyModelSHA256 hashes the first file in manifestHashCandidates found
+// under path and compares it against expected (case-insensitive hex).
+func verifyModelSHA256(path, expected string) error {
+	for _, name := range manifestHashCandidates {
+		full := filepath.Join(path, name)
+		data, err := os.ReadFile(full)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("reading %s for SHA256 check: %w", full, err)
+		}
+
+		sum := sha256.Sum256(data)
+		got := hex.EncodeToString(sum[:])
+		if !strings.EqualFold(got, expected) {
+			return fmt.Errorf("%s: SHA256 mismatch: manifest expects %s, got %s", full, expected, got)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s: no recognized weight file found to verify SHA256 against", path)
+}
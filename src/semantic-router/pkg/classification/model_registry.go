@@ -0,0 +1,157 @@
+package classification
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// SplitModelPath splits a MODEL_PATH-style, os.PathListSeparator-delimited
+// string (e.g. "/opt/models:/srv/cache/models:./models" on Unix) into an
+// ordered list of roots, dropping empty segments. An empty modelPath
+// returns nil.
+func SplitModelPath(modelPath string) []string {
+	if modelPath == "" {
+		return nil
+	}
+
+	parts := strings.Split(modelPath, string(os.PathListSeparator))
+	roots := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p != "" {
+			roots = append(roots, p)
+		}
+	}
+	return roots
+}
+
+// rootDiscovery caches one root's autoDiscoverModelsInRoot result, including
+// a discovery error, so ModelRegistry.Discover doesn't re-walk a root until
+// something invalidates it.
+type rootDiscovery struct {
+	paths *ModelPaths
+	err   error
+}
+
+// ModelRegistry tracks an ordered list of model-discovery roots and caches
+// each root's discovery result, the same layered-search-path model as
+// Erlang's code:add_path/add_patha/add_pathsz: roots are searched in
+// priority order, and an operator can hot-add a new mount or drop a stale
+// one at runtime via AddModelPath/RemoveModelPath without restarting the
+// router - Discover picks the change up on its next call since that root's
+// cache entry (and only that one) was invalidated.
+type ModelRegistry struct {
+	mu    sync.RWMutex
+	roots []string
+	cache map[string]*rootDiscovery
+
+	selected string // root Discover last returned models from, "" before the first successful call
+}
+
+// NewModelRegistry builds a ModelRegistry searching roots in the given
+// order.
+func NewModelRegistry(roots ...string) *ModelRegistry {
+	return &ModelRegistry{
+		roots: append([]string(nil), roots...),
+		cache: make(map[string]*rootDiscovery),
+	}
+}
+
+// NewModelRegistryFromEnv builds a ModelRegistry from a MODEL_PATH-style
+// environment variable value; see SplitModelPath.
+func NewModelRegistryFromEnv(modelPath string) *ModelRegistry {
+	return NewModelRegistry(SplitModelPath(modelPath)...)
+}
+
+// AddModelPath appends root to the search path - lowest priority, searched
+// last - and invalidates its cached discovery result so the next Discover
+// call re-walks it. A root already present is moved to the end instead of
+// being duplicated.
+func (r *ModelRegistry) AddModelPath(root string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.removeLocked(root)
+	r.roots = append(r.roots, root)
+	delete(r.cache, root)
+}
+
+// RemoveModelPath drops root from the search path along with its cached
+// discovery result. Removing a root that isn't present is a no-op.
+func (r *ModelRegistry) RemoveModelPath(root string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.removeLocked(root)
+	delete(r.cache, root)
+}
+
+// removeLocked drops root from r.roots, if present. Callers must hold r.mu.
+func (r *ModelRegistry) removeLocked(root string) {
+	for i, existing := range r.roots {
+		if existing == root {
+			r.roots = append(r.roots[:i], r.roots[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetModelPaths returns the registry's search roots, in priority order.
+func (r *ModelRegistry) GetModelPaths() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string(nil), r.roots...)
+}
+
+// SelectedRoot returns which root Discover last returned models from, and
+// whether Discover has succeeded at least once.
+func (r *ModelRegistry) SelectedRoot() (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.selected, r.selected != ""
+}
+
+// Discover walks the registry's roots in priority order - using each root's
+// cached result where one hasn't been invalidated - and returns the first
+// complete architecture set found, same as discoverAcrossRoots. If no root
+// is complete, it falls back to the last root that produced any result at
+// all, matching AutoDiscoverModels' single-root fallback behavior.
+func (r *ModelRegistry) Discover() (*ModelPaths, string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.roots) == 0 {
+		return nil, "", fmt.Errorf("model registry has no configured roots")
+	}
+
+	var lastPaths *ModelPaths
+	var lastRoot string
+	var lastErr error
+	for _, root := range r.roots {
+		cached, ok := r.cache[root]
+		if !ok {
+			paths, err := autoDiscoverModelsInRoot(root)
+			cached = &rootDiscovery{paths: paths, err: err}
+			r.cache[root] = cached
+		}
+		if cached.err != nil {
+			lastErr = cached.err
+			continue
+		}
+
+		lastPaths, lastRoot = cached.paths, root
+		if cached.paths.IsComplete() {
+			r.selected = root
+			return cached.paths, root, nil
+		}
+	}
+
+	if lastPaths != nil {
+		r.selected = lastRoot
+		return lastPaths, lastRoot, nil
+	}
+	return nil, "", lastErr
+}
@@ -0,0 +1,100 @@
+package classification
+
+import "strings"
+
+// maxSuggestionCandidates caps how many directory names suggestClosestName
+// scores against expected, so a models directory with thousands of
+// unrelated subdirectories doesn't turn architecture-selection diagnostics
+// into an O(n*m) scan.
+const maxSuggestionCandidates = 200
+
+// minSuggestionSimilarity is the minimum normalized-Levenshtein "1 -
+// distance/maxLen" similarity a candidate must clear to be suggested at
+// all; below this, a candidate is almost certainly an unrelated directory
+// rather than a typo, and proposing it would just be noise.
+const minSuggestionSimilarity = 0.6
+
+// levenshteinDistance computes the classic edit distance between a and b:
+// the minimum number of single-character insertions, deletions, or
+// substitutions that turns a into b.
+func levenshteinDistance(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if len(a) == 0 {
+		return len(b)
+	}
+	if len(b) == 0 {
+		return len(a)
+	}
+
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(
+				prev[j]+1,      // deletion
+				curr[j-1]+1,    // insertion
+				prev[j-1]+cost, // substitution
+			)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
+
+// suggestClosestName finds the candidate closest to expected by Levenshtein
+// distance, returning it and true when its similarity clears
+// minSuggestionSimilarity. candidates beyond maxSuggestionCandidates are
+// not considered. Comparison is case-insensitive, matching how directory
+// names are matched elsewhere in this package.
+func suggestClosestName(candidates []string, expected string) (string, bool) {
+	if len(candidates) > maxSuggestionCandidates {
+		candidates = candidates[:maxSuggestionCandidates]
+	}
+
+	expectedLower := strings.ToLower(expected)
+	bestName := ""
+	bestSimilarity := -1.0
+	for _, candidate := range candidates {
+		dist := levenshteinDistance(strings.ToLower(candidate), expectedLower)
+		maxLen := len(expected)
+		if len(candidate) > maxLen {
+			maxLen = len(candidate)
+		}
+		if maxLen == 0 {
+			continue
+		}
+
+		similarity := 1 - float64(dist)/float64(maxLen)
+		if similarity > bestSimilarity {
+			bestSimilarity = similarity
+			bestName = candidate
+		}
+	}
+
+	if bestSimilarity < minSuggestionSimilarity {
+		return "", false
+	}
+	return bestName, true
+}
@@ -0,0 +1,176 @@
+// Package concurrency provides a per-key in-flight request limiter with a
+// bounded FIFO wait queue, used to cap how many requests a given user or
+// model may have outstanding at once independent of the token-bucket rate
+// limits in pkg/ratelimit.
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrCapacityAndQueueFull is returned by Acquire when a key's concurrency
+// capacity is exhausted and its wait queue has no free slot either.
+var ErrCapacityAndQueueFull = errors.New("concurrency: capacity and wait queue are both full")
+
+// Limit configures one key's concurrency gate.
+type Limit struct {
+	// Max is how many requests may hold this key's slot at once. Zero or
+	// less leaves the key uncapped.
+	Max int
+	// QueueSize bounds how many additional requests may wait (FIFO) once
+	// Max requests are already in flight. Zero means admission fails as
+	// soon as Max requests are active.
+	QueueSize int
+}
+
+// Decision is the outcome of one concurrency admission check. Callers
+// surface it to clients via x-concurrency-limit, x-concurrency-active, and
+// x-queue-position response headers.
+type Decision struct {
+	Allowed bool
+	// Key identifies which bucket this Decision describes: the one that
+	// rejected the request, or - when Allowed is true and multiple keys
+	// were checked - the last key acquired (the model-level key, for
+	// AcquireUserAndModel).
+	Key           string
+	Limit         int
+	Active        int
+	QueuePosition int // 1-based position held in the wait queue; 0 if admitted without waiting
+}
+
+// Release frees the slot(s) a successful Acquire call reserved. It must be
+// called exactly once.
+type Release func()
+
+// Limiter tracks in-flight request counts per key behind a channel-based
+// weighted semaphore, plus a bounded FIFO wait queue per key so a request
+// blocked on capacity is served in arrival order instead of racing every
+// other waiter on each release.
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewLimiter returns an empty Limiter. Buckets are created lazily, keyed by
+// the first Limit passed for that key.
+func NewLimiter() *Limiter {
+	return &Limiter{buckets: make(map[string]*bucket)}
+}
+
+type bucket struct {
+	mu      sync.Mutex
+	sem     chan struct{}
+	limit   Limit
+	waiting int
+}
+
+func (l *Limiter) bucketFor(key string, limit Limit) *bucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{sem: make(chan struct{}, limit.Max), limit: limit}
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// Acquire admits one request under key, governed by limit's capacity and
+// wait queue. It blocks until a slot frees up, ctx is done, or the wait
+// queue is full - whichever comes first. A canceled ctx (e.g. the client
+// disconnected) releases the caller immediately without holding a
+// wait-queue slot any longer than necessary.
+func (l *Limiter) Acquire(ctx context.Context, key string, limit Limit) (*Decision, Release, error) {
+	if limit.Max <= 0 {
+		return &Decision{Allowed: true, Key: key}, func() {}, nil
+	}
+
+	b := l.bucketFor(key, limit)
+
+	select {
+	case b.sem <- struct{}{}:
+		return &Decision{Allowed: true, Key: key, Limit: limit.Max, Active: len(b.sem)},
+			func() { <-b.sem }, nil
+	default:
+	}
+
+	b.mu.Lock()
+	if b.waiting >= limit.QueueSize {
+		b.mu.Unlock()
+		return &Decision{Allowed: false, Key: key, Limit: limit.Max, Active: len(b.sem)},
+			nil, ErrCapacityAndQueueFull
+	}
+	b.waiting++
+	position := b.waiting
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		b.waiting--
+		b.mu.Unlock()
+	}()
+
+	select {
+	case b.sem <- struct{}{}:
+		return &Decision{Allowed: true, Key: key, Limit: limit.Max, Active: len(b.sem), QueuePosition: position},
+			func() { <-b.sem }, nil
+	case <-ctx.Done():
+		return &Decision{Allowed: false, Key: key, Limit: limit.Max, Active: len(b.sem), QueuePosition: position},
+			nil, ctx.Err()
+	}
+}
+
+// AcquireUserAndModel acquires both the per-(userID, model) bucket and the
+// per-model bucket for one request, in that order, releasing whichever it
+// already holds if the second acquisition fails. The returned Decision
+// describes whichever bucket rejected the request, or the model-level
+// bucket - the dimension shared across every caller of that model - when
+// both succeed.
+func (l *Limiter) AcquireUserAndModel(ctx context.Context, userID, model string, userModelLimit, modelLimit Limit) (*Decision, Release, error) {
+	userKey := fmt.Sprintf("user:%s:model:%s", userID, model)
+	modelKey := fmt.Sprintf("model:%s", model)
+
+	userDecision, userRelease, err := l.Acquire(ctx, userKey, userModelLimit)
+	if err != nil {
+		return userDecision, nil, err
+	}
+
+	modelDecision, modelRelease, err := l.Acquire(ctx, modelKey, modelLimit)
+	if err != nil {
+		userRelease()
+		return modelDecision, nil, err
+	}
+
+	return modelDecision, func() {
+		modelRelease()
+		userRelease()
+	}, nil
+}
+
+// ReleaseOnDone wraps release so it fires at most once: either when the
+// caller invokes the returned function directly, or when ctx is done (the
+// client disconnected before that point was reached), whichever happens
+// first. This is what lets AcquireUserAndModel's slot free up immediately
+// on cancellation instead of waiting on a response-phase release hook that
+// a disconnected client will never trigger.
+func ReleaseOnDone(ctx context.Context, release Release) Release {
+	var once sync.Once
+	done := make(chan struct{})
+	fire := func() {
+		once.Do(func() {
+			release()
+			close(done)
+		})
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			fire()
+		case <-done:
+		}
+	}()
+	return fire
+}
@@ -0,0 +1,121 @@
+package concurrency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_AllowsWithinMaxThenQueues(t *testing.T) {
+	l := NewLimiter()
+	limit := Limit{Max: 1, QueueSize: 1}
+
+	d1, release1, err := l.Acquire(context.Background(), "k", limit)
+	require.NoError(t, err)
+	assert.True(t, d1.Allowed)
+	assert.Equal(t, 0, d1.QueuePosition)
+
+	// A second, blocking Acquire should queue rather than reject outright.
+	type result struct {
+		decision *Decision
+		release  Release
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		d, r, err := l.Acquire(context.Background(), "k", limit)
+		done <- result{d, r, err}
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	release1()
+
+	res := <-done
+	require.NoError(t, res.err)
+	assert.True(t, res.decision.Allowed)
+	assert.Equal(t, 1, res.decision.QueuePosition)
+	res.release()
+}
+
+func TestLimiter_RejectsWhenQueueFull(t *testing.T) {
+	l := NewLimiter()
+	limit := Limit{Max: 1, QueueSize: 0}
+
+	_, release, err := l.Acquire(context.Background(), "k", limit)
+	require.NoError(t, err)
+	defer release()
+
+	d, _, err := l.Acquire(context.Background(), "k", limit)
+	assert.ErrorIs(t, err, ErrCapacityAndQueueFull)
+	assert.False(t, d.Allowed)
+}
+
+func TestLimiter_CancelledContextFreesQueueSlot(t *testing.T) {
+	l := NewLimiter()
+	limit := Limit{Max: 1, QueueSize: 1}
+
+	_, release, err := l.Acquire(context.Background(), "k", limit)
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	d, _, err := l.Acquire(ctx, "k", limit)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, d.Allowed)
+}
+
+func TestLimiter_AcquireUserAndModel_ReleasesUserKeyWhenModelKeyRejected(t *testing.T) {
+	l := NewLimiter()
+	userLimit := Limit{Max: 5, QueueSize: 0}
+	modelLimit := Limit{Max: 1, QueueSize: 0}
+
+	// Exhaust the model-level bucket with an unrelated user first.
+	_, releaseOther, err := l.Acquire(context.Background(), "model:gpt-4o", modelLimit)
+	require.NoError(t, err)
+	defer releaseOther()
+
+	d, release, err := l.AcquireUserAndModel(context.Background(), "alice", "gpt-4o", userLimit, modelLimit)
+	assert.ErrorIs(t, err, ErrCapacityAndQueueFull)
+	assert.Nil(t, release)
+	assert.False(t, d.Allowed)
+
+	// The user-level slot must have been released, not leaked.
+	d2, release2, err := l.Acquire(context.Background(), "user:alice:model:gpt-4o", userLimit)
+	require.NoError(t, err)
+	assert.Equal(t, 1, d2.Active)
+	release2()
+}
+
+func TestReleaseOnDone_FiresOnceEvenWithExplicitReleaseAndCancellation(t *testing.T) {
+	calls := 0
+	release := func() { calls++ }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wrapped := ReleaseOnDone(ctx, release)
+
+	wrapped()
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestReleaseOnDone_FiresOnCancellationWithoutExplicitCall(t *testing.T) {
+	calls := make(chan struct{}, 1)
+	release := func() { calls <- struct{}{} }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ReleaseOnDone(ctx, release)
+	cancel()
+
+	select {
+	case <-calls:
+	case <-time.After(time.Second):
+		t.Fatal("release was not invoked after context cancellation")
+	}
+}
@@ -0,0 +1,129 @@
+// Package errs defines a typed error taxonomy for the ExtProc router,
+// modeled on the sentinel-reason-plus-wrapper shape pkg/memory's
+// classifyError/StatusError use for Milvus client errors: a small set of
+// plain sentinel Reasons that callers match with errors.Is, wrapped by a
+// single error type that keeps the original cause around for logs.
+//
+// createErrorResponse previously hand-built every failure as a bare
+// "invalid_request_error" with the HTTP status duplicated into the body's
+// "code" field, which conflates transport and semantic errors and gives
+// callers nothing to branch on besides a string message. Wrapping an error
+// with one of this package's Reasons lets createErrorResponseForErr map it
+// deterministically to the right HTTP status and OpenAI error type/code, and
+// lets upstream code (metrics, retries) use errors.Is instead of string
+// matching.
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Reason sentinels classify *why* a router operation failed. They are never
+// returned bare - always wrapped by a *RouterError - so errors.Is(err,
+// ErrXxx) keeps working no matter how many layers of fmt.Errorf("%w", ...)
+// sit between the call site and the original cause.
+var (
+	// ErrConfigLoad indicates the router config file, or a mapping file it
+	// references (category, PII, jailbreak), failed to parse or load.
+	ErrConfigLoad = errors.New("router configuration failed to load")
+
+	// ErrClassifierUnavailable indicates the BERT/classification model
+	// backing category, PII, or jailbreak detection failed to initialize.
+	ErrClassifierUnavailable = errors.New("classifier unavailable")
+
+	// ErrPIIPolicyViolation indicates a request was rejected because it
+	// contains PII the configured policy does not allow.
+	ErrPIIPolicyViolation = errors.New("request violates PII policy")
+
+	// ErrJailbreakDetected indicates a request was rejected because the
+	// jailbreak classifier flagged it as a prompt injection attempt.
+	ErrJailbreakDetected = errors.New("jailbreak attempt detected")
+
+	// ErrCacheBackend indicates the semantic cache backend failed to
+	// initialize or serve a request.
+	ErrCacheBackend = errors.New("cache backend unavailable")
+
+	// ErrMemoryStoreDown indicates the Milvus-backed memory store failed to
+	// connect, initialize, or is otherwise unreachable.
+	ErrMemoryStoreDown = errors.New("memory store unavailable")
+
+	// ErrToolSelection indicates the tools database failed to load, or
+	// failed to select a tool for a request.
+	ErrToolSelection = errors.New("tool selection failed")
+
+	// ErrUpstreamModel indicates the upstream model endpoint rejected or
+	// failed to serve a request.
+	ErrUpstreamModel = errors.New("upstream model error")
+)
+
+// RouterError pairs a Reason sentinel with the underlying error it was
+// classified from. Error() surfaces both; Unwrap() exposes both Reason and
+// Err, so errors.Is matches either the ErrXxx sentinel or the original cause.
+type RouterError struct {
+	Reason error
+	Err    error
+}
+
+func (e *RouterError) Error() string {
+	if e.Err == nil {
+		return e.Reason.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Reason, e.Err)
+}
+
+func (e *RouterError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Reason}
+	}
+	return []error{e.Reason, e.Err}
+}
+
+// Wrap classifies err under reason, returning nil if err is nil so callers
+// can write `return errs.Wrap(errs.ErrFoo, err)` directly in an early return.
+func Wrap(reason, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &RouterError{Reason: reason, Err: err}
+}
+
+func IsConfigLoadError(err error) bool       { return errors.Is(err, ErrConfigLoad) }
+func IsClassifierUnavailable(err error) bool { return errors.Is(err, ErrClassifierUnavailable) }
+func IsPIIPolicyViolation(err error) bool    { return errors.Is(err, ErrPIIPolicyViolation) }
+func IsJailbreakDetected(err error) bool     { return errors.Is(err, ErrJailbreakDetected) }
+func IsCacheBackendError(err error) bool     { return errors.Is(err, ErrCacheBackend) }
+func IsMemoryStoreDown(err error) bool       { return errors.Is(err, ErrMemoryStoreDown) }
+func IsToolSelectionError(err error) bool    { return errors.Is(err, ErrToolSelection) }
+func IsUpstreamModelError(err error) bool    { return errors.Is(err, ErrUpstreamModel) }
+
+// openAIError is how a Reason renders as an OpenAI-style error body.
+type openAIError struct {
+	status int
+	typ    string
+	code   string
+}
+
+var reasonToOpenAIError = map[error]openAIError{
+	ErrConfigLoad:            {500, "internal_error", "config_load_failed"},
+	ErrClassifierUnavailable: {503, "service_unavailable", "classifier_unavailable"},
+	ErrPIIPolicyViolation:    {400, "invalid_request_error", "pii_policy_violation"},
+	ErrJailbreakDetected:     {400, "invalid_request_error", "jailbreak_detected"},
+	ErrCacheBackend:          {503, "service_unavailable", "cache_backend_unavailable"},
+	ErrMemoryStoreDown:       {503, "service_unavailable", "memory_store_unavailable"},
+	ErrToolSelection:         {500, "internal_error", "tool_selection_failed"},
+	ErrUpstreamModel:         {502, "upstream_error", "upstream_model_error"},
+}
+
+// OpenAIErrorFields returns the HTTP status and OpenAI error type/code that
+// best describe err. ok is false if err doesn't classify to any Reason this
+// package knows about, in which case the caller should fall back to its own
+// default (createErrorResponse's plain "invalid_request_error" behavior).
+func OpenAIErrorFields(err error) (status int, errType string, errCode string, ok bool) {
+	for reason, fields := range reasonToOpenAIError {
+		if errors.Is(err, reason) {
+			return fields.status, fields.typ, fields.code, true
+		}
+	}
+	return 0, "", "", false
+}
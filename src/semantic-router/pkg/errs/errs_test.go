@@ -0,0 +1,38 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap_NilErrReturnsNil(t *testing.T) {
+	assert.NoError(t, Wrap(ErrMemoryStoreDown, nil))
+}
+
+func TestWrap_IsMatchesThroughFurtherWrapping(t *testing.T) {
+	cause := errors.New("connection refused")
+	wrapped := fmt.Errorf("dial milvus: %w", Wrap(ErrMemoryStoreDown, cause))
+
+	assert.True(t, IsMemoryStoreDown(wrapped))
+	assert.False(t, IsToolSelectionError(wrapped))
+	assert.True(t, errors.Is(wrapped, cause))
+}
+
+func TestOpenAIErrorFields_KnownReason(t *testing.T) {
+	err := Wrap(ErrPIIPolicyViolation, errors.New("email address detected"))
+
+	status, errType, errCode, ok := OpenAIErrorFields(err)
+
+	assert.True(t, ok)
+	assert.Equal(t, 400, status)
+	assert.Equal(t, "invalid_request_error", errType)
+	assert.Equal(t, "pii_policy_violation", errCode)
+}
+
+func TestOpenAIErrorFields_UnknownReasonFalls(t *testing.T) {
+	_, _, _, ok := OpenAIErrorFields(errors.New("some unclassified failure"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,279 @@
+package extproc
+
+import (
+	"encoding/json"
+
+	"github.com/openai/openai-go"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/logging"
+)
+
+// anthropicSSEEvent is the subset of Anthropic's Messages API streaming
+// event shapes anthropicStreamTranslator understands: message_start
+// through message_stop, plus the ping heartbeats Anthropic sends to keep
+// idle connections alive. Fields absent from a given event type's payload
+// are simply left at their zero value.
+type anthropicSSEEvent struct {
+	Message *struct {
+		ID    string `json:"id"`
+		Model string `json:"model"`
+		Usage struct {
+			InputTokens int64 `json:"input_tokens"`
+		} `json:"usage"`
+	} `json:"message,omitempty"`
+
+	Index int64 `json:"index"`
+
+	Delta *struct {
+		Type        string `json:"type"`
+		Text        string `json:"text,omitempty"`
+		PartialJSON string `json:"partial_json,omitempty"`
+		StopReason  string `json:"stop_reason,omitempty"`
+	} `json:"delta,omitempty"`
+
+	Usage *struct {
+		OutputTokens int64 `json:"output_tokens"`
+	} `json:"usage,omitempty"`
+}
+
+// anthropicStopReasonToOpenAI maps Anthropic's message_delta.stop_reason
+// values to the OpenAI finish_reason vocabulary, so a client written
+// against OpenAI's streaming contract sees a value it already knows how to
+// handle.
+var anthropicStopReasonToOpenAI = map[string]string{
+	"end_turn":      "stop",
+	"stop_sequence": "stop",
+	"max_tokens":    "length",
+	"tool_use":      "tool_calls",
+}
+
+// anthropicStreamTranslator converts Anthropic's Messages API SSE event
+// stream into OpenAI-compatible chat.completion.chunk frames, one chunk
+// per content_block_delta plus a final chunk carrying finish_reason and
+// usage. It tracks running input/output token counts from
+// message_start.message.usage and message_delta.usage so the last chunk's
+// usage field reflects the full exchange, the same running-total approach
+// OpenAI's own streaming usage field uses.
+type anthropicStreamTranslator struct {
+	model        string
+	id           string
+	inputTokens  int64
+	outputTokens int64
+}
+
+// newAnthropicStreamTranslator starts a translator for one streamed
+// response. model is used as a fallback for the emitted chunks' "model"
+// field until message_start supplies Anthropic's own model string.
+func newAnthropicStreamTranslator(model string) *anthropicStreamTranslator {
+	return &anthropicStreamTranslator{model: model}
+}
+
+// translate decodes one Anthropic SSE event (eventType from its "event:"
+// line, data from its "data:" line) and returns the OpenAI chunk it maps
+// to, if any. message_start/content_block_start/content_block_stop/
+// message_stop/ping carry no text for the client and return (nil, nil).
+// A content_block_delta produces one chunk for a text_delta or
+// input_json_delta and none for other delta types; a message_delta produces
+// the final chunk only when it carries a stop_reason.
+func (t *anthropicStreamTranslator) translate(eventType string, data []byte) (*openai.ChatCompletionChunk, error) {
+	switch eventType {
+	case "content_block_start", "content_block_stop", "message_stop", "ping":
+		return nil, nil
+	}
+
+	var evt anthropicSSEEvent
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return nil, err
+	}
+
+	switch eventType {
+	case "message_start":
+		if evt.Message != nil {
+			t.id = evt.Message.ID
+			if evt.Message.Model != "" {
+				t.model = evt.Message.Model
+			}
+			t.inputTokens = evt.Message.Usage.InputTokens
+		}
+		return nil, nil
+
+	case "content_block_delta":
+		if evt.Delta == nil {
+			return nil, nil
+		}
+		switch evt.Delta.Type {
+		case "text_delta":
+			return t.chunk(openai.ChatCompletionChunkChoiceDelta{Content: evt.Delta.Text}, evt.Index, "", nil), nil
+		case "input_json_delta":
+			toolCalls := []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+				Index: evt.Index,
+				Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{
+					Arguments: evt.Delta.PartialJSON,
+				},
+			}}
+			return t.chunk(openai.ChatCompletionChunkChoiceDelta{ToolCalls: toolCalls}, evt.Index, "", nil), nil
+		default:
+			logging.Debugf("anthropicStreamTranslator: ignoring content_block_delta of type %q", evt.Delta.Type)
+			return nil, nil
+		}
+
+	case "message_delta":
+		if evt.Usage != nil {
+			t.outputTokens = evt.Usage.OutputTokens
+		}
+		if evt.Delta == nil || evt.Delta.StopReason == "" {
+			return nil, nil
+		}
+
+		finishReason := anthropicStopReasonToOpenAI[evt.Delta.StopReason]
+		if finishReason == "" {
+			finishReason = "stop"
+		}
+		usage := &openai.CompletionUsage{
+			PromptTokens:     t.inputTokens,
+			CompletionTokens: t.outputTokens,
+			TotalTokens:      t.inputTokens + t.outputTokens,
+		}
+		return t.chunk(openai.ChatCompletionChunkChoiceDelta{}, 0, finishReason, usage), nil
+
+	default:
+		logging.Debugf("anthropicStreamTranslator: ignoring unknown event type %q", eventType)
+		return nil, nil
+	}
+}
+
+// anthropicMessageResponse is the non-streaming shape of an Anthropic
+// Messages API response, as translated by translateAnthropicMessage.
+type anthropicMessageResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Content []struct {
+		Type  string          `json:"type"`
+		Text  string          `json:"text,omitempty"`
+		ID    string          `json:"id,omitempty"`
+		Name  string          `json:"name,omitempty"`
+		Input json.RawMessage `json:"input,omitempty"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int64 `json:"input_tokens"`
+		OutputTokens int64 `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// wireToolCall, wireMessage, wireChoice, wireUsage, and wireCompletion
+// mirror the stable, publicly-documented OpenAI chat.completion JSON shape
+// directly rather than openai-go's Go struct field names, the same
+// wire-format-over-SDK-types choice pkg/gemini's ToOpenAIResponseBody makes
+// for the same reason: a response translator only needs to produce bytes a
+// chat-completions client can parse, not round-trip through the SDK.
+type wireToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type wireMessage struct {
+	Role      string         `json:"role"`
+	Content   string         `json:"content"`
+	ToolCalls []wireToolCall `json:"tool_calls,omitempty"`
+}
+
+type wireChoice struct {
+	Index        int64       `json:"index"`
+	Message      wireMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+type wireUsage struct {
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+	TotalTokens      int64 `json:"total_tokens"`
+}
+
+type wireCompletion struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"`
+	Model   string       `json:"model"`
+	Choices []wireChoice `json:"choices"`
+	Usage   wireUsage    `json:"usage"`
+}
+
+// translateAnthropicMessage converts a non-streaming Anthropic Messages API
+// response body into an OpenAI chat.completion response body, so a client
+// written against the chat-completions contract sees the same shape
+// regardless of whether the selected provider profile is "anthropic" or
+// "openai": text blocks join into choices[0].message.content, tool_use
+// blocks become choices[0].message.tool_calls, and stop_reason maps through
+// anthropicStopReasonToOpenAI.
+//
+// There is currently no response-phase body dispatcher wired into
+// OpenAIRouter's Process loop (handleResponseBody in sse_pipeline.go is
+// reachable only from tests and does not call this), so nothing calls this
+// function today; it's written against the same
+// anthropicMessageResponse shape the streaming translator above uses, ready
+// to be wired in once that dispatcher exists.
+func translateAnthropicMessage(body []byte) ([]byte, error) {
+	var msg anthropicMessageResponse
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, err
+	}
+
+	out := wireMessage{Role: "assistant"}
+	for _, block := range msg.Content {
+		switch block.Type {
+		case "text":
+			out.Content += block.Text
+		case "tool_use":
+			call := wireToolCall{ID: block.ID, Type: "function"}
+			call.Function.Name = block.Name
+			call.Function.Arguments = string(block.Input)
+			out.ToolCalls = append(out.ToolCalls, call)
+		}
+	}
+
+	finishReason := anthropicStopReasonToOpenAI[msg.StopReason]
+	if finishReason == "" {
+		finishReason = "stop"
+	}
+	if len(out.ToolCalls) > 0 {
+		finishReason = "tool_calls"
+	}
+
+	completion := wireCompletion{
+		ID:     msg.ID,
+		Object: "chat.completion",
+		Model:  msg.Model,
+		Choices: []wireChoice{{
+			Index:        0,
+			Message:      out,
+			FinishReason: finishReason,
+		}},
+		Usage: wireUsage{
+			PromptTokens:     msg.Usage.InputTokens,
+			CompletionTokens: msg.Usage.OutputTokens,
+			TotalTokens:      msg.Usage.InputTokens + msg.Usage.OutputTokens,
+		},
+	}
+
+	return json.Marshal(completion)
+}
+
+// chunk assembles a single-choice OpenAI chat.completion.chunk carrying
+// delta, keyed to choiceIndex, with finishReason and usage set only on the
+// terminal chunk (both are zero-valued otherwise).
+func (t *anthropicStreamTranslator) chunk(delta openai.ChatCompletionChunkChoiceDelta, choiceIndex int64, finishReason string, usage *openai.CompletionUsage) *openai.ChatCompletionChunk {
+	chunk := &openai.ChatCompletionChunk{
+		ID:      t.id,
+		Object:  "chat.completion.chunk",
+		Model:   t.model,
+		Choices: []openai.ChatCompletionChunkChoice{{Index: choiceIndex, Delta: delta, FinishReason: finishReason}},
+	}
+	if usage != nil {
+		chunk.Usage = *usage
+	}
+	return chunk
+}
@@ -0,0 +1,105 @@
+package extproc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Fixtures below are trimmed recordings of an Anthropic Messages API
+// streaming response (https://docs.anthropic.com/en/api/messages-streaming),
+// one event's "data:" payload per constant.
+const (
+	fixtureMessageStart = `{"type":"message_start","message":{"id":"msg_01ABC","type":"message","role":"assistant","model":"claude-3-5-sonnet-20241022","content":[],"usage":{"input_tokens":25,"output_tokens":1}}}`
+
+	fixtureContentBlockStart = `{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`
+
+	fixtureTextDelta1 = `{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hello"}}`
+
+	fixtureTextDelta2 = `{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":", world"}}`
+
+	fixtureContentBlockStop = `{"type":"content_block_stop","index":0}`
+
+	fixtureMessageDelta = `{"type":"message_delta","delta":{"stop_reason":"end_turn","stop_sequence":null},"usage":{"output_tokens":15}}`
+
+	fixtureMessageStop = `{"type":"message_stop"}`
+
+	fixturePing = `{"type":"ping"}`
+
+	fixtureToolUseDelta = `{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"loc"}}`
+)
+
+func TestAnthropicStreamTranslator_FullTextExchange(t *testing.T) {
+	tr := newAnthropicStreamTranslator("claude-3-5-sonnet-20241022")
+
+	chunk, err := tr.translate("message_start", []byte(fixtureMessageStart))
+	require.NoError(t, err)
+	assert.Nil(t, chunk)
+	assert.Equal(t, "msg_01ABC", tr.id)
+	assert.Equal(t, int64(25), tr.inputTokens)
+
+	chunk, err = tr.translate("content_block_start", []byte(fixtureContentBlockStart))
+	require.NoError(t, err)
+	assert.Nil(t, chunk)
+
+	chunk, err = tr.translate("content_block_delta", []byte(fixtureTextDelta1))
+	require.NoError(t, err)
+	require.NotNil(t, chunk)
+	assert.Equal(t, "msg_01ABC", chunk.ID)
+	require.Len(t, chunk.Choices, 1)
+	assert.Equal(t, "Hello", chunk.Choices[0].Delta.Content)
+	assert.Empty(t, chunk.Choices[0].FinishReason)
+
+	chunk, err = tr.translate("content_block_delta", []byte(fixtureTextDelta2))
+	require.NoError(t, err)
+	require.NotNil(t, chunk)
+	assert.Equal(t, ", world", chunk.Choices[0].Delta.Content)
+
+	chunk, err = tr.translate("ping", []byte(fixturePing))
+	require.NoError(t, err)
+	assert.Nil(t, chunk)
+
+	chunk, err = tr.translate("content_block_stop", []byte(fixtureContentBlockStop))
+	require.NoError(t, err)
+	assert.Nil(t, chunk)
+
+	chunk, err = tr.translate("message_delta", []byte(fixtureMessageDelta))
+	require.NoError(t, err)
+	require.NotNil(t, chunk)
+	assert.Equal(t, "stop", chunk.Choices[0].FinishReason)
+	assert.EqualValues(t, 25, chunk.Usage.PromptTokens)
+	assert.EqualValues(t, 15, chunk.Usage.CompletionTokens)
+	assert.EqualValues(t, 40, chunk.Usage.TotalTokens)
+
+	chunk, err = tr.translate("message_stop", []byte(fixtureMessageStop))
+	require.NoError(t, err)
+	assert.Nil(t, chunk)
+}
+
+func TestAnthropicStreamTranslator_ToolUseInputJSONDelta(t *testing.T) {
+	tr := newAnthropicStreamTranslator("claude-3-5-sonnet-20241022")
+
+	chunk, err := tr.translate("content_block_delta", []byte(fixtureToolUseDelta))
+	require.NoError(t, err)
+	require.NotNil(t, chunk)
+	require.Len(t, chunk.Choices[0].Delta.ToolCalls, 1)
+	assert.Equal(t, `{"loc`, chunk.Choices[0].Delta.ToolCalls[0].Function.Arguments)
+	assert.EqualValues(t, 1, chunk.Choices[0].Delta.ToolCalls[0].Index)
+}
+
+func TestAnthropicStreamTranslator_UnknownStopReasonDefaultsToStop(t *testing.T) {
+	tr := newAnthropicStreamTranslator("claude-3-5-sonnet-20241022")
+
+	chunk, err := tr.translate("message_delta", []byte(`{"type":"message_delta","delta":{"stop_reason":"some_future_reason"},"usage":{"output_tokens":3}}`))
+	require.NoError(t, err)
+	require.NotNil(t, chunk)
+	assert.Equal(t, "stop", chunk.Choices[0].FinishReason)
+}
+
+func TestAnthropicStreamTranslator_MalformedEventReturnsError(t *testing.T) {
+	tr := newAnthropicStreamTranslator("claude-3-5-sonnet-20241022")
+
+	_, err := tr.translate("message_start", []byte("not json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,220 @@
+package extproc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/auth/identity"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/metrics"
+)
+
+// Identity is the result of resolving a request's caller through an
+// AuthResolver (or chain of them): who they are, what they're scoped to,
+// and whether the source that produced it can be trusted to gate writes
+// (MemoryFilter.StoreFromResponse) as well as reads.
+type Identity struct {
+	UserID    string
+	ProjectID string
+	Scopes    []string
+
+	// Trusted reports whether UserID was authenticated - a verified JWT, or
+	// a header an upstream auth proxy injects after verifying the caller -
+	// rather than read unverified from client-controlled request metadata.
+	// MemoryFilter refuses to auto-store memories for an untrusted Identity
+	// unless it was explicitly constructed with WithAllowUntrustedWrites.
+	Trusted bool
+
+	// ResolvedBy names the resolver that produced this Identity (the Name
+	// of whichever NamedResolver fired, when resolved via ChainResolver),
+	// for logging and the memory_auth_resolver_success_total metric.
+	ResolvedBy string
+}
+
+// AuthResolver resolves the caller identity for a request. Implementations
+// range from trusting a single header outright (HeaderResolver) to
+// verifying a signed JWT (JWTResolver) to reading untrusted client-supplied
+// metadata (MetadataResolver); ChainResolver composes several into the
+// priority order a deployment wants, replacing the old dev-build-only
+// extractUserID fallback chain with something that exists in every build.
+type AuthResolver interface {
+	// Resolve returns the caller's Identity for ctx, or an error if this
+	// resolver has nothing to offer - e.g. the header or token it looks
+	// for is absent. An error here isn't necessarily a hard failure; a
+	// ChainResolver treats it as "try the next resolver".
+	Resolve(ctx *RequestContext) (*Identity, error)
+}
+
+// HeaderResolver resolves Identity.UserID directly from a single request
+// header - the x-authz-user-id convention, where an upstream auth proxy
+// (Authorino, Envoy Gateway JWT filter, oauth2-proxy) has already verified
+// the caller and injected the result. Trusted should be true for any
+// header a deployment's proxy is known to inject only after verification.
+type HeaderResolver struct {
+	HeaderName string
+	Trusted    bool
+}
+
+// Resolve implements AuthResolver.
+func (r HeaderResolver) Resolve(ctx *RequestContext) (*Identity, error) {
+	userID, ok := ctx.Headers[r.HeaderName]
+	if !ok || strings.TrimSpace(userID) == "" {
+		return nil, fmt.Errorf("extproc: HeaderResolver: header %q not present", r.HeaderName)
+	}
+	return &Identity{UserID: userID, Trusted: r.Trusted}, nil
+}
+
+// JWTResolver resolves Identity by verifying the Bearer token in the
+// Authorization header against a JWKS endpoint. It delegates the actual
+// signature/issuer/audience verification to identity.Verifier rather than
+// reimplementing JWT/JWKS handling - ProjectIDClaim is read via
+// identity.Config's TenantClaim slot, since "project" and "tenant" name the
+// same kind of claim for this purpose and identity.Claims already carries
+// whichever one the configured claim name maps to.
+type JWTResolver struct {
+	JWKSURL        string
+	UserIDClaim    string
+	ProjectIDClaim string
+	Issuer         string
+	Audience       string
+
+	once     sync.Once
+	verifier identity.Verifier
+	buildErr error
+}
+
+// verifierFor lazily builds (and caches) the identity.Verifier backing this
+// resolver, mirroring getIdentityVerifier's lazy-connect style.
+func (r *JWTResolver) verifierFor() (identity.Verifier, error) {
+	r.once.Do(func() {
+		r.verifier, r.buildErr = identity.NewVerifier(identity.Config{
+			Enabled:     true,
+			KeySource:   identity.KeySourceJWKS,
+			JWKSURL:     r.JWKSURL,
+			UserIDClaim: r.UserIDClaim,
+			TenantClaim: r.ProjectIDClaim,
+			Issuer:      r.Issuer,
+			Audience:    r.Audience,
+		})
+	})
+	return r.verifier, r.buildErr
+}
+
+// Resolve implements AuthResolver.
+func (r *JWTResolver) Resolve(ctx *RequestContext) (*Identity, error) {
+	token, ok := ctx.Headers[authorizationHeader]
+	if !ok || strings.TrimSpace(token) == "" {
+		return nil, fmt.Errorf("extproc: JWTResolver: no %s header", authorizationHeader)
+	}
+
+	verifier, err := r.verifierFor()
+	if err != nil {
+		return nil, fmt.Errorf("extproc: JWTResolver: build verifier: %w", err)
+	}
+
+	claims, err := verifier.VerifyToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("extproc: JWTResolver: %w", err)
+	}
+
+	return &Identity{
+		UserID:    claims.UserID,
+		ProjectID: claims.Tenant,
+		Scopes:    strings.Fields(claims.Scope),
+		Trusted:   true,
+	}, nil
+}
+
+// MetadataResolver resolves Identity.UserID from client-supplied request
+// body fields - Response API's metadata["user_id"], or Chat Completions'
+// metadata["user_id"]/deprecated "user" field - the same fallback
+// user_id_dev.go's dev-only extractUserID used. It's written without a
+// build tag, unlike that fallback, so a deployment that wants this
+// (explicitly untrusted) source available gets it in every build rather
+// than only in dev binaries. Trusted should stay false in any deployment
+// that wants MemoryFilter's untrusted-write gate to apply.
+type MetadataResolver struct {
+	Trusted bool
+}
+
+// Resolve implements AuthResolver.
+func (r MetadataResolver) Resolve(ctx *RequestContext) (*Identity, error) {
+	if ctx.ResponseAPICtx != nil && ctx.ResponseAPICtx.OriginalRequest != nil && ctx.ResponseAPICtx.OriginalRequest.Metadata != nil {
+		if userID, ok := ctx.ResponseAPICtx.OriginalRequest.Metadata["user_id"]; ok && userID != "" {
+			return &Identity{UserID: userID, Trusted: r.Trusted}, nil
+		}
+	}
+
+	if len(ctx.ChatCompletionRequestBody) > 0 {
+		if userID := userIDFromChatCompletionBody(ctx.ChatCompletionRequestBody); userID != "" {
+			return &Identity{UserID: userID, Trusted: r.Trusted}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("extproc: MetadataResolver: no user_id in request metadata or body")
+}
+
+// userIDFromChatCompletionBody extracts a Chat Completions request's
+// client-supplied user identifier: metadata["user_id"] (consistent with
+// Response API), falling back to the deprecated "user" field. This mirrors
+// extractChatCompletionUserIDFromBody in user_id_dev.go, duplicated rather
+// than shared because that function only exists under the dev build tag
+// and MetadataResolver must work in every build.
+func userIDFromChatCompletionBody(requestBody []byte) string {
+	var req struct {
+		Metadata map[string]string `json:"metadata"`
+		User     string            `json:"user"`
+	}
+	if err := json.Unmarshal(requestBody, &req); err != nil {
+		return ""
+	}
+
+	if req.Metadata != nil {
+		if userID, ok := req.Metadata["user_id"]; ok && userID != "" {
+			return userID
+		}
+	}
+
+	return req.User
+}
+
+// NamedResolver pairs an AuthResolver with the name ChainResolver records
+// against the memory_auth_resolver_success_total metric and
+// Identity.ResolvedBy when it produces the identity.
+type NamedResolver struct {
+	Name     string
+	Resolver AuthResolver
+}
+
+// ChainResolver tries each Resolver in order and returns the first
+// Identity produced, recording which resolver produced it - the
+// Auth/Account/Verify chain-of-responsibility shape seen in go-micro's auth
+// package - so a deployment can layer e.g. JWTResolver ahead of
+// HeaderResolver ahead of MetadataResolver without MemoryFilter caring
+// which one actually fired.
+type ChainResolver struct {
+	Resolvers []NamedResolver
+}
+
+// NewChainResolver builds a ChainResolver trying resolvers in the given
+// order.
+func NewChainResolver(resolvers ...NamedResolver) *ChainResolver {
+	return &ChainResolver{Resolvers: resolvers}
+}
+
+// Resolve implements AuthResolver.
+func (c *ChainResolver) Resolve(ctx *RequestContext) (*Identity, error) {
+	var errs []string
+	for _, nr := range c.Resolvers {
+		id, err := nr.Resolver.Resolve(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", nr.Name, err))
+			continue
+		}
+		id.ResolvedBy = nr.Name
+		metrics.RecordMemoryAuthResolverSuccess(nr.Name)
+		return id, nil
+	}
+	return nil, fmt.Errorf("extproc: no resolver produced an identity (%s)", strings.Join(errs, "; "))
+}
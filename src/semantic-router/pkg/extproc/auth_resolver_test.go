@@ -0,0 +1,181 @@
+package extproc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/auth/identity"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/headers"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/responseapi"
+)
+
+// staticJWTResolver builds a JWTResolver whose verifier is a static-key
+// identity.Verifier rather than one fetched from a live JWKS endpoint, so
+// these tests don't need a JWKS server. It pre-marks the resolver's lazy
+// build as done and installs the verifier directly - both fields are
+// unexported but reachable from this same-package test.
+func staticJWTResolver(t *testing.T, audience string) *JWTResolver {
+	t.Helper()
+	verifier, err := identity.NewVerifier(identity.Config{
+		Enabled:     true,
+		KeySource:   identity.KeySourceStatic,
+		StaticKey:   []byte(testJWTSigningKey),
+		Audience:    audience,
+		TenantClaim: "project_id",
+	})
+	require.NoError(t, err)
+
+	r := &JWTResolver{UserIDClaim: "sub", ProjectIDClaim: "project_id"}
+	r.once.Do(func() {})
+	r.verifier = verifier
+	return r
+}
+
+func TestJWTResolver_ValidToken(t *testing.T) {
+	r := staticJWTResolver(t, "")
+	token := signTestJWT(t, jwt.MapClaims{
+		"sub":        "user_1",
+		"project_id": "proj_a",
+		"scope":      "read write",
+		"exp":        time.Now().Add(time.Hour).Unix(),
+	})
+
+	ctx := &RequestContext{Headers: map[string]string{authorizationHeader: "Bearer " + token}}
+	id, err := r.Resolve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "user_1", id.UserID)
+	assert.Equal(t, "proj_a", id.ProjectID)
+	assert.Equal(t, []string{"read", "write"}, id.Scopes)
+	assert.True(t, id.Trusted)
+}
+
+func TestJWTResolver_MissingAuthorizationHeader(t *testing.T) {
+	r := staticJWTResolver(t, "")
+	ctx := &RequestContext{Headers: map[string]string{}}
+
+	_, err := r.Resolve(ctx)
+	assert.Error(t, err)
+}
+
+func TestJWTResolver_ExpiredToken(t *testing.T) {
+	r := staticJWTResolver(t, "")
+	token := signTestJWT(t, jwt.MapClaims{
+		"sub": "user_1",
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	})
+
+	ctx := &RequestContext{Headers: map[string]string{authorizationHeader: "Bearer " + token}}
+	_, err := r.Resolve(ctx)
+	assert.Error(t, err, "an expired token must not resolve an identity")
+}
+
+func TestJWTResolver_WrongAudience(t *testing.T) {
+	r := staticJWTResolver(t, "expected-audience")
+	token := signTestJWT(t, jwt.MapClaims{
+		"sub": "user_1",
+		"aud": "some-other-audience",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	ctx := &RequestContext{Headers: map[string]string{authorizationHeader: "Bearer " + token}}
+	_, err := r.Resolve(ctx)
+	assert.Error(t, err, "an audience mismatch must not resolve an identity")
+}
+
+func TestJWTResolver_MissingRequiredClaim(t *testing.T) {
+	r := staticJWTResolver(t, "")
+	token := signTestJWT(t, jwt.MapClaims{
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	ctx := &RequestContext{Headers: map[string]string{authorizationHeader: "Bearer " + token}}
+	_, err := r.Resolve(ctx)
+	assert.Error(t, err, "a token missing the configured UserIDClaim must not resolve an identity")
+}
+
+func TestHeaderResolver_ResolvesTrustedIdentity(t *testing.T) {
+	r := HeaderResolver{HeaderName: headers.AuthzUserID, Trusted: true}
+	ctx := &RequestContext{Headers: map[string]string{headers.AuthzUserID: "user_from_header"}}
+
+	id, err := r.Resolve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "user_from_header", id.UserID)
+	assert.True(t, id.Trusted)
+}
+
+func TestHeaderResolver_MissingHeader(t *testing.T) {
+	r := HeaderResolver{HeaderName: headers.AuthzUserID, Trusted: true}
+	ctx := &RequestContext{Headers: map[string]string{}}
+
+	_, err := r.Resolve(ctx)
+	assert.Error(t, err)
+}
+
+func TestMetadataResolver_ResolvesFromResponseAPIMetadata(t *testing.T) {
+	r := MetadataResolver{}
+	ctx := &RequestContext{
+		ResponseAPICtx: &ResponseAPIContext{
+			OriginalRequest: &responseapi.ResponseAPIRequest{
+				Metadata: map[string]string{"user_id": "user_from_metadata"},
+			},
+		},
+	}
+
+	id, err := r.Resolve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "user_from_metadata", id.UserID)
+	assert.False(t, id.Trusted, "MetadataResolver's zero value must stay untrusted")
+}
+
+func TestMetadataResolver_ResolvesFromChatCompletionsBody(t *testing.T) {
+	r := MetadataResolver{}
+	ctx := &RequestContext{
+		ChatCompletionRequestBody: []byte(`{"user": "user_from_deprecated_field"}`),
+	}
+
+	id, err := r.Resolve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "user_from_deprecated_field", id.UserID)
+}
+
+func TestMetadataResolver_NoUserID(t *testing.T) {
+	r := MetadataResolver{}
+	ctx := &RequestContext{}
+
+	_, err := r.Resolve(ctx)
+	assert.Error(t, err)
+}
+
+func TestChainResolver_TriesInOrderAndRecordsResolver(t *testing.T) {
+	chain := NewChainResolver(
+		NamedResolver{Name: "header", Resolver: HeaderResolver{HeaderName: headers.AuthzUserID, Trusted: true}},
+		NamedResolver{Name: "metadata", Resolver: MetadataResolver{}},
+	)
+
+	ctx := &RequestContext{
+		ResponseAPICtx: &ResponseAPIContext{
+			OriginalRequest: &responseapi.ResponseAPIRequest{
+				Metadata: map[string]string{"user_id": "user_from_metadata"},
+			},
+		},
+	}
+
+	id, err := chain.Resolve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "user_from_metadata", id.UserID)
+	assert.Equal(t, "metadata", id.ResolvedBy, "header resolver had nothing to offer, metadata should have produced the identity")
+}
+
+func TestChainResolver_AllFail(t *testing.T) {
+	chain := NewChainResolver(
+		NamedResolver{Name: "header", Resolver: HeaderResolver{HeaderName: headers.AuthzUserID, Trusted: true}},
+		NamedResolver{Name: "metadata", Resolver: MetadataResolver{}},
+	)
+
+	_, err := chain.Resolve(&RequestContext{})
+	assert.Error(t, err)
+}
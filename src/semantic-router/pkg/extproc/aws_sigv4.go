@@ -0,0 +1,149 @@
+package extproc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// bedrockService is the AWS service name used in a Bedrock SigV4 signing
+// scope ("<date>/<region>/bedrock/aws4_request").
+const bedrockService = "bedrock"
+
+// bedrockSigV4Headers is the result of signing one request for AWS
+// Bedrock: the computed Authorization header value plus the x-amz-date
+// (and, when present, x-amz-security-token) headers that must accompany
+// it on the upstream request.
+type bedrockSigV4Headers struct {
+	Authorization string
+	AmzDate       string
+	SecurityToken string // empty unless the credential is a temporary STS session
+
+	// Path is the percent-encoded form of the path passed to
+	// signBedrockRequest - the same bytes signed as the canonical
+	// request's CanonicalURI. AWS requires the literal request-line path
+	// sent on the wire to match the CanonicalURI exactly, so callers must
+	// forward this (not the original, unencoded path) as the upstream
+	// :path.
+	Path string
+}
+
+// bedrockHost returns the regional Bedrock runtime endpoint host SigV4
+// signs against and the request is forwarded to.
+func bedrockHost(region string) string {
+	return fmt.Sprintf("bedrock-runtime.%s.amazonaws.com", region)
+}
+
+// signBedrockRequest computes the AWS Signature Version 4 signature for a
+// single Bedrock InvokeModel-style request: a canonical request over the
+// (already-rewritten) body, :path, host, and x-amz-date, a signing key
+// derived from region/service=bedrock/date, and the resulting
+// Authorization header in the standard
+// "AWS4-HMAC-SHA256 Credential=…, SignedHeaders=…, Signature=…" form.
+func signBedrockRequest(accessKey, secretKey, sessionToken, region, method, path, host string, body []byte, now time.Time) bedrockSigV4Headers {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	dateStamp := now.UTC().Format("20060102")
+	encodedPath := encodeBedrockPath(path)
+
+	headerValues := map[string]string{"host": host, "x-amz-date": amzDate}
+	headerNames := []string{"host", "x-amz-date"}
+	if sessionToken != "" {
+		headerValues["x-amz-security-token"] = sessionToken
+		headerNames = append(headerNames, "x-amz-security-token")
+	}
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		canonicalHeaders.WriteString(name)
+		canonicalHeaders.WriteString(":")
+		canonicalHeaders.WriteString(headerValues[name])
+		canonicalHeaders.WriteString("\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		encodedPath,
+		"", // Bedrock InvokeModel requests carry no query string
+		canonicalHeaders.String(),
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+
+	credentialScope := fmt.Sprintf("%s/%s/%s/aws4_request", dateStamp, region, bedrockService)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		credentialScope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signature := hex.EncodeToString(hmacSHA256(deriveBedrockSigningKey(secretKey, dateStamp, region), stringToSign))
+
+	authorization := fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, credentialScope, signedHeaders, signature)
+
+	return bedrockSigV4Headers{Authorization: authorization, AmzDate: amzDate, SecurityToken: sessionToken, Path: encodedPath}
+}
+
+// bedrockPathUnreserved is the RFC 3986 unreserved set SigV4 URI-encoding
+// leaves untouched; every other byte in a path segment must be
+// percent-encoded. Bedrock model IDs routinely contain ":" (e.g.
+// "anthropic.claude-3-5-sonnet-20241022-v2:0"), which falls outside this
+// set and must become "%3A" or AWS rejects the signature.
+const bedrockPathUnreserved = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-._~"
+
+// encodeBedrockPath percent-encodes path per AWS SigV4 canonical URI rules:
+// each "/"-separated segment is encoded independently (so the separators
+// themselves are preserved), leaving only bedrockPathUnreserved bytes
+// unescaped. This must produce the same bytes that are actually sent as
+// the upstream request's :path - SigV4 verification fails if the literal
+// request line and the signed CanonicalURI disagree.
+func encodeBedrockPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = encodeBedrockPathSegment(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// encodeBedrockPathSegment percent-encodes a single path segment's bytes
+// outside bedrockPathUnreserved.
+func encodeBedrockPathSegment(segment string) string {
+	var b strings.Builder
+	for i := 0; i < len(segment); i++ {
+		c := segment[i]
+		if strings.IndexByte(bedrockPathUnreserved, c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// deriveBedrockSigningKey derives the scoped signing key for a single
+// date/region/service, per the SigV4 key-derivation chain:
+// HMAC(HMAC(HMAC(HMAC("AWS4"+secret, date), region), service), "aws4_request").
+func deriveBedrockSigningKey(secretKey, dateStamp, region string) []byte {
+	kDate := hmacSHA256([]byte("AWS4"+secretKey), dateStamp)
+	kRegion := hmacSHA256(kDate, region)
+	kService := hmacSHA256(kRegion, bedrockService)
+	return hmacSHA256(kService, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
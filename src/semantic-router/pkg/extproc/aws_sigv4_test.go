@@ -0,0 +1,84 @@
+package extproc
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var authorizationHeaderPattern = regexp.MustCompile(
+	`^AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/\d{8}/us-east-1/bedrock/aws4_request, SignedHeaders=[a-z0-9;-]+, Signature=[0-9a-f]{64}$`)
+
+func TestSignBedrockRequest_ProducesWellFormedAuthorizationHeader(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	sig := signBedrockRequest("AKIDEXAMPLE", "secret", "", "us-east-1", "POST",
+		"/model/anthropic.claude-3-5-sonnet/invoke", bedrockHost("us-east-1"), []byte(`{"prompt":"hi"}`), now)
+
+	assert.Regexp(t, authorizationHeaderPattern, sig.Authorization)
+	assert.Equal(t, "20260115T120000Z", sig.AmzDate)
+	assert.Empty(t, sig.SecurityToken)
+	assert.Contains(t, sig.Authorization, "SignedHeaders=host;x-amz-date,")
+}
+
+func TestSignBedrockRequest_IncludesSessionTokenWhenPresent(t *testing.T) {
+	now := time.Now()
+	sig := signBedrockRequest("AKIDEXAMPLE", "secret", "session-token-value", "us-west-2", "POST",
+		"/model/m/invoke", bedrockHost("us-west-2"), []byte(`{}`), now)
+
+	assert.Equal(t, "session-token-value", sig.SecurityToken)
+	assert.Contains(t, sig.Authorization, "SignedHeaders=host;x-amz-date;x-amz-security-token,")
+}
+
+func TestSignBedrockRequest_DeterministicForSameInputs(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	a := signBedrockRequest("AKID", "secret", "", "us-east-1", "POST", "/p", "h", []byte("body"), now)
+	b := signBedrockRequest("AKID", "secret", "", "us-east-1", "POST", "/p", "h", []byte("body"), now)
+	assert.Equal(t, a.Authorization, b.Authorization)
+}
+
+func TestSignBedrockRequest_BodyChangeChangesSignature(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	a := signBedrockRequest("AKID", "secret", "", "us-east-1", "POST", "/p", "h", []byte("body-one"), now)
+	b := signBedrockRequest("AKID", "secret", "", "us-east-1", "POST", "/p", "h", []byte("body-two"), now)
+	assert.NotEqual(t, a.Authorization, b.Authorization)
+}
+
+func TestSignBedrockRequest_SecretKeyChangeChangesSignature(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	a := signBedrockRequest("AKID", "secret-a", "", "us-east-1", "POST", "/p", "h", []byte("body"), now)
+	b := signBedrockRequest("AKID", "secret-b", "", "us-east-1", "POST", "/p", "h", []byte("body"), now)
+	assert.NotEqual(t, a.Authorization, b.Authorization)
+}
+
+func TestBedrockHost(t *testing.T) {
+	assert.Equal(t, "bedrock-runtime.us-east-1.amazonaws.com", bedrockHost("us-east-1"))
+}
+
+func TestSignBedrockRequest_PercentEncodesVersionedModelIDInPath(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	sig := signBedrockRequest("AKIDEXAMPLE", "secret", "", "us-east-1", "POST",
+		"/model/anthropic.claude-3-5-sonnet-20241022-v2:0/invoke", bedrockHost("us-east-1"), []byte(`{"prompt":"hi"}`), now)
+
+	assert.Equal(t, "/model/anthropic.claude-3-5-sonnet-20241022-v2%3A0/invoke", sig.Path,
+		"the ':' in a versioned Bedrock model ID must be percent-encoded to %3A, both for signing and for the literal upstream path")
+	assert.Regexp(t, authorizationHeaderPattern, sig.Authorization)
+}
+
+func TestSignBedrockRequest_DifferentModelVersionsProduceDifferentSignatures(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	a := signBedrockRequest("AKIDEXAMPLE", "secret", "", "us-east-1", "POST",
+		"/model/anthropic.claude-3-5-sonnet-20241022-v2:0/invoke", bedrockHost("us-east-1"), []byte(`{}`), now)
+	b := signBedrockRequest("AKIDEXAMPLE", "secret", "", "us-east-1", "POST",
+		"/model/anthropic.claude-3-5-sonnet-20241022-v1:0/invoke", bedrockHost("us-east-1"), []byte(`{}`), now)
+
+	assert.NotEqual(t, a.Path, b.Path)
+	assert.NotEqual(t, a.Authorization, b.Authorization)
+}
+
+func TestEncodeBedrockPath_EncodesReservedBytesButPreservesSlashes(t *testing.T) {
+	assert.Equal(t, "/model/anthropic.claude-3-5-sonnet-20241022-v2%3A0/invoke",
+		encodeBedrockPath("/model/anthropic.claude-3-5-sonnet-20241022-v2:0/invoke"))
+	assert.Equal(t, "/model/plain-model/invoke", encodeBedrockPath("/model/plain-model/invoke"))
+}
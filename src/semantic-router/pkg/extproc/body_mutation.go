@@ -0,0 +1,88 @@
+package extproc
+
+import (
+	"fmt"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	ext_proc "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/bodymutation"
+)
+
+// defaultBodyMutationPipeline is used when OpenAIRouter.BodyMutationPipeline
+// is nil: the built-in stages for today's behavior (Response API
+// translation, model-name rewriting) plus the stages decisions can opt
+// into through their Plugins configuration. The opt-in stages are no-ops
+// unless a decision actually configures the matching plugin type, so
+// including them unconditionally is safe.
+func defaultBodyMutationPipeline() *bodymutation.Pipeline {
+	return &bodymutation.Pipeline{
+		Stages: []bodymutation.Stage{
+			bodymutation.ResponseAPITranslateStage{},
+			bodymutation.PromptGuardStage{},
+			bodymutation.SystemPromptPrependStage{},
+			bodymutation.ToolFilterStage{},
+			bodymutation.JSONSchemaCoerceStage{},
+			bodymutation.AnthropicTranslateStage{},
+			bodymutation.ModelRewriteStage{},
+		},
+	}
+}
+
+// bodyMutationPipeline returns r.BodyMutationPipeline, falling back to
+// defaultBodyMutationPipeline when it hasn't been set.
+func (r *OpenAIRouter) bodyMutationPipeline() *bodymutation.Pipeline {
+	if r.BodyMutationPipeline != nil {
+		return r.BodyMutationPipeline
+	}
+	return defaultBodyMutationPipeline()
+}
+
+// bodyMutationContext adapts ctx, the internal/upstream model aliases, and
+// the resolved provider into the generic bodymutation.Context Stages
+// operate on, pulling per-decision plugin configuration from
+// ctx.VSRSelectedDecision.Plugins.
+func bodyMutationContext(ctx *RequestContext, model, upstreamModel, provider string) bodymutation.Context {
+	bmCtx := bodymutation.Context{
+		Model:         model,
+		UpstreamModel: upstreamModel,
+		Provider:      provider,
+	}
+	if ctx == nil {
+		return bmCtx
+	}
+
+	if ctx.VSRSelectedDecision != nil {
+		bmCtx.DecisionName = ctx.VSRSelectedDecision.Name
+		bmCtx.Plugins = make(map[string]map[string]interface{}, len(ctx.VSRSelectedDecision.Plugins))
+		for _, plugin := range ctx.VSRSelectedDecision.Plugins {
+			bmCtx.Plugins[plugin.Type] = plugin.Configuration
+		}
+	}
+	if ctx.ResponseAPICtx != nil {
+		bmCtx.ResponseAPIRequest = ctx.ResponseAPICtx.IsResponseAPIRequest
+		bmCtx.ResponseAPITranslatedBody = ctx.ResponseAPICtx.TranslatedBody
+	}
+	return bmCtx
+}
+
+// bodyMutationHeaders builds the content-length set/remove header pair a
+// changed request body needs. createRoutingResponse and
+// createSpecifiedModelResponse both call this once their body-mutation
+// pipeline has produced a final body, instead of each hand-rolling its own
+// content-length logic.
+func bodyMutationHeaders(body []byte) (*ext_proc.BodyMutation, []*core.HeaderValueOption, []string) {
+	bodyMutation := &ext_proc.BodyMutation{
+		Mutation: &ext_proc.BodyMutation_Body{Body: body},
+	}
+	var setHeaders []*core.HeaderValueOption
+	if len(body) > 0 {
+		setHeaders = append(setHeaders, &core.HeaderValueOption{
+			Header: &core.HeaderValue{
+				Key:      "content-length",
+				RawValue: []byte(fmt.Sprintf("%d", len(body))),
+			},
+		})
+	}
+	return bodyMutation, setHeaders, []string{"content-length"}
+}
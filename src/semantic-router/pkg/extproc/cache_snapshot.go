@@ -0,0 +1,112 @@
+package extproc
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// cacheSnapshotShutdownTimeout bounds how long the SIGTERM handler waits for
+// a snapshot write before giving up, so a slow/hung backend can't block the
+// process from exiting.
+const cacheSnapshotShutdownTimeout = 10 * time.Second
+
+// CacheSnapshotter is an optional capability a cache.CacheBackend can
+// implement to persist and reload its entries across restarts, following the
+// same optional-interface convention as memory.StoreHealthChecker: backends
+// that can't support it (or don't need to, e.g. an external cache service)
+// simply don't implement it, and SnapshotCache/RestoreCacheSnapshot no-op
+// rather than fail.
+type CacheSnapshotter interface {
+	// Snapshot serializes the backend's current entries (prompt, embedding,
+	// response, timestamps, hit-count) to path, which may be a local file
+	// path or an S3-compatible URL.
+	Snapshot(ctx context.Context, path string) error
+	// Restore merges entries from the snapshot at path into the backend.
+	// Implementations should apply an LFU-aware merge so a partial or stale
+	// snapshot can't evict hotter entries already live in the backend.
+	Restore(ctx context.Context, path string) error
+}
+
+// RestoreCacheSnapshot hydrates r.Cache from cfg.SemanticCache.SnapshotPath,
+// if both are configured and the backend supports it. Called once during
+// NewOpenAIRouter, after the cache backend is created, so a restarted pod
+// doesn't start cold and redo embedding work a previous instance already
+// paid for. A missing snapshot, an unset path, or a backend that doesn't
+// implement CacheSnapshotter are all treated as "nothing to restore", not
+// errors.
+func (r *OpenAIRouter) RestoreCacheSnapshot(ctx context.Context) error {
+	path := r.Config.SemanticCache.SnapshotPath
+	if path == "" || r.Cache == nil {
+		return nil
+	}
+
+	snapshotter, ok := r.Cache.(CacheSnapshotter)
+	if !ok {
+		return nil
+	}
+
+	if err := snapshotter.Restore(ctx, path); err != nil {
+		if os.IsNotExist(err) {
+			logging.Infof("Cache: no snapshot found at %s, starting cold", path)
+			return nil
+		}
+		return err
+	}
+
+	logging.Infof("Cache: restored snapshot from %s", path)
+	return nil
+}
+
+// SnapshotCache persists r.Cache to cfg.SemanticCache.SnapshotPath, if both
+// are configured and the backend supports it. Exposed so it can be invoked
+// both from the SIGTERM handler installed by InstallCacheSnapshotSignalHandler
+// and on demand (e.g. an admin-triggered dump).
+func (r *OpenAIRouter) SnapshotCache(ctx context.Context) error {
+	path := r.Config.SemanticCache.SnapshotPath
+	if path == "" || r.Cache == nil {
+		return nil
+	}
+
+	snapshotter, ok := r.Cache.(CacheSnapshotter)
+	if !ok {
+		return nil
+	}
+
+	if err := snapshotter.Snapshot(ctx, path); err != nil {
+		return err
+	}
+
+	logging.Infof("Cache: wrote snapshot to %s", path)
+	return nil
+}
+
+// InstallCacheSnapshotSignalHandler registers a SIGTERM/SIGINT handler that
+// best-effort snapshots r.Cache before the process exits, so a Kubernetes
+// pod eviction doesn't throw away the embedding work the cache already did.
+// No-op if r.Config.SemanticCache.SnapshotPath is unset. The handler does not
+// call os.Exit itself; it only runs the snapshot and lets whatever else is
+// listening for the same signal (e.g. a gRPC server's own graceful shutdown)
+// proceed.
+func (r *OpenAIRouter) InstallCacheSnapshotSignalHandler() {
+	if r.Config.SemanticCache.SnapshotPath == "" {
+		return
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+
+	go func() {
+		<-sigCh
+		logging.Infof("Cache: shutdown signal received, snapshotting before exit")
+		ctx, cancel := context.WithTimeout(context.Background(), cacheSnapshotShutdownTimeout)
+		defer cancel()
+		if err := r.SnapshotCache(ctx); err != nil {
+			logging.Errorf("Cache: failed to snapshot on shutdown: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,71 @@
+package extproc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/cache"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
+)
+
+// fakeSnapshotCache implements cache.CacheBackend (via embedding) plus
+// CacheSnapshotter so tests can assert on which path/whether Snapshot or
+// Restore was invoked without a real backend.
+type fakeSnapshotCache struct {
+	cache.CacheBackend
+	snapshotPath string
+	restorePath  string
+	restoreErr   error
+}
+
+func (f *fakeSnapshotCache) Snapshot(ctx context.Context, path string) error {
+	f.snapshotPath = path
+	return nil
+}
+
+func (f *fakeSnapshotCache) Restore(ctx context.Context, path string) error {
+	f.restorePath = path
+	return f.restoreErr
+}
+
+func routerWithSnapshotPath(t *testing.T, path string, c cache.CacheBackend) *OpenAIRouter {
+	t.Helper()
+	cfg := &config.RouterConfig{}
+	cfg.SemanticCache.SnapshotPath = path
+	return &OpenAIRouter{Config: cfg, Cache: c}
+}
+
+func TestRestoreCacheSnapshot_NoPathIsNoOp(t *testing.T) {
+	fake := &fakeSnapshotCache{}
+	r := routerWithSnapshotPath(t, "", fake)
+
+	require.NoError(t, r.RestoreCacheSnapshot(context.Background()))
+	assert.Empty(t, fake.restorePath)
+}
+
+func TestRestoreCacheSnapshot_CallsBackendWhenSupported(t *testing.T) {
+	fake := &fakeSnapshotCache{}
+	r := routerWithSnapshotPath(t, "/tmp/cache.snapshot", fake)
+
+	require.NoError(t, r.RestoreCacheSnapshot(context.Background()))
+	assert.Equal(t, "/tmp/cache.snapshot", fake.restorePath)
+}
+
+func TestRestoreCacheSnapshot_PropagatesNonNotExistError(t *testing.T) {
+	fake := &fakeSnapshotCache{restoreErr: errors.New("corrupt snapshot")}
+	r := routerWithSnapshotPath(t, "/tmp/cache.snapshot", fake)
+
+	assert.Error(t, r.RestoreCacheSnapshot(context.Background()))
+}
+
+func TestSnapshotCache_CallsBackendWhenSupported(t *testing.T) {
+	fake := &fakeSnapshotCache{}
+	r := routerWithSnapshotPath(t, "/tmp/cache.snapshot", fake)
+
+	require.NoError(t, r.SnapshotCache(context.Background()))
+	assert.Equal(t, "/tmp/cache.snapshot", fake.snapshotPath)
+}
@@ -0,0 +1,158 @@
+package extproc
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitState is the standard three-state circuit breaker model: closed
+// (healthy, requests flow normally), open (recently failed repeatedly,
+// requests are treated as unhealthy until cooldown elapses), and half-open
+// (cooldown elapsed, a single probe is allowed through to test recovery
+// before fully closing again).
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+const (
+	// defaultCircuitFailureThreshold is how many consecutive failures trip
+	// an endpoint's breaker from closed to open.
+	defaultCircuitFailureThreshold = 3
+	// defaultCircuitCooldown is how long an open breaker waits before
+	// allowing a half-open probe.
+	defaultCircuitCooldown = 30 * time.Second
+)
+
+// endpointCircuitBreaker tracks one endpoint's recent health. It's
+// deliberately short-lived/in-memory (per router process, not persisted) -
+// a "short-lived circuit breaker" per the product requirement, not a
+// durable health record.
+type endpointCircuitBreaker struct {
+	mu                 sync.Mutex
+	state              circuitState
+	consecutiveFails   int
+	openedAt           time.Time
+	halfOpenProbeInUse bool
+}
+
+// allow reports whether a request may currently be sent to this endpoint,
+// transitioning open -> half-open once cooldown has elapsed. Only one
+// half-open probe is allowed in flight at a time; concurrent callers that
+// lose the race are told to wait (false) rather than piling onto the probe.
+func (b *endpointCircuitBreaker) allow(now time.Time, cooldown time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitClosed:
+		return true
+	case circuitHalfOpen:
+		return false // a probe is already outstanding
+	default: // circuitOpen
+		if now.Sub(b.openedAt) < cooldown {
+			return false
+		}
+		b.state = circuitHalfOpen
+		b.halfOpenProbeInUse = true
+		return true
+	}
+}
+
+// recordSuccess closes the breaker and resets its failure count.
+func (b *endpointCircuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = circuitClosed
+	b.consecutiveFails = 0
+	b.halfOpenProbeInUse = false
+}
+
+// recordFailure counts a failure, opening (or re-opening) the breaker once
+// threshold consecutive failures have been seen. A failed half-open probe
+// re-opens immediately regardless of threshold, since it already confirmed
+// the endpoint is still unhealthy.
+func (b *endpointCircuitBreaker) recordFailure(now time.Time, threshold int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == circuitHalfOpen {
+		b.state = circuitOpen
+		b.openedAt = now
+		b.halfOpenProbeInUse = false
+		return
+	}
+
+	b.consecutiveFails++
+	if b.consecutiveFails >= threshold {
+		b.state = circuitOpen
+		b.openedAt = now
+	}
+}
+
+// circuitBreakerRegistry holds one endpointCircuitBreaker per endpoint
+// address, created lazily on first use.
+type circuitBreakerRegistry struct {
+	mu               sync.Mutex
+	breakers         map[string]*endpointCircuitBreaker
+	failureThreshold int
+	cooldown         time.Duration
+}
+
+// newCircuitBreakerRegistry builds a registry with the given failure
+// threshold and cooldown. A threshold <= 0 or cooldown <= 0 falls back to
+// defaultCircuitFailureThreshold / defaultCircuitCooldown.
+func newCircuitBreakerRegistry(failureThreshold int, cooldown time.Duration) *circuitBreakerRegistry {
+	if failureThreshold <= 0 {
+		failureThreshold = defaultCircuitFailureThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = defaultCircuitCooldown
+	}
+	return &circuitBreakerRegistry{
+		breakers:         make(map[string]*endpointCircuitBreaker),
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+	}
+}
+
+func (reg *circuitBreakerRegistry) breakerFor(endpoint string) *endpointCircuitBreaker {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	b, ok := reg.breakers[endpoint]
+	if !ok {
+		b = &endpointCircuitBreaker{}
+		reg.breakers[endpoint] = b
+	}
+	return b
+}
+
+// Allow reports whether endpoint is currently considered healthy enough to
+// receive a request.
+func (reg *circuitBreakerRegistry) Allow(endpoint string) bool {
+	return reg.breakerFor(endpoint).allow(time.Now(), reg.cooldown)
+}
+
+// RecordSuccess marks endpoint healthy again.
+func (reg *circuitBreakerRegistry) RecordSuccess(endpoint string) {
+	reg.breakerFor(endpoint).recordSuccess()
+}
+
+// RecordFailure counts one failure against endpoint, tripping its breaker
+// once the registry's failure threshold is reached.
+func (reg *circuitBreakerRegistry) RecordFailure(endpoint string) {
+	reg.breakerFor(endpoint).recordFailure(time.Now(), reg.failureThreshold)
+}
+
+// defaultCircuitBreakers is the process-wide registry selectEndpointForModel
+// consults. It's currently only ever read from (Allow) in this build - the
+// upstream-response phase that would call RecordFailure/RecordSuccess after
+// seeing a real 429/5xx or connection error isn't present in this snapshot,
+// so every endpoint stays effectively closed until that phase exists and is
+// wired to call RecordFailure/RecordSuccess. The plumbing is in place ahead
+// of that so selection-time behavior doesn't need to change again once it
+// lands.
+var defaultCircuitBreakers = newCircuitBreakerRegistry(defaultCircuitFailureThreshold, defaultCircuitCooldown)
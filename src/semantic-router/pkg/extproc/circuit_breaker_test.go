@@ -0,0 +1,56 @@
+package extproc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreakerRegistry_OpensAfterThreshold(t *testing.T) {
+	reg := newCircuitBreakerRegistry(2, time.Minute)
+
+	assert.True(t, reg.Allow("ep1"))
+	reg.RecordFailure("ep1")
+	assert.True(t, reg.Allow("ep1"), "below threshold, still closed")
+	reg.RecordFailure("ep1")
+	assert.False(t, reg.Allow("ep1"), "threshold reached, breaker opens")
+}
+
+func TestCircuitBreakerRegistry_HalfOpenAfterCooldown(t *testing.T) {
+	b := &endpointCircuitBreaker{}
+	now := time.Now()
+	b.recordFailure(now, 1)
+	assert.False(t, b.allow(now, time.Second), "still within cooldown")
+	assert.True(t, b.allow(now.Add(2*time.Second), time.Second), "cooldown elapsed, probe allowed")
+	assert.Equal(t, circuitHalfOpen, b.state)
+}
+
+func TestCircuitBreakerRegistry_HalfOpenProbeFailureReopens(t *testing.T) {
+	b := &endpointCircuitBreaker{}
+	now := time.Now()
+	b.recordFailure(now, 1)
+	probeTime := now.Add(2 * time.Second)
+	assert.True(t, b.allow(probeTime, time.Second))
+	b.recordFailure(probeTime, 1)
+	assert.Equal(t, circuitOpen, b.state)
+	assert.False(t, b.allow(probeTime, time.Second))
+}
+
+func TestCircuitBreakerRegistry_SuccessClosesBreaker(t *testing.T) {
+	reg := newCircuitBreakerRegistry(1, time.Minute)
+	reg.RecordFailure("ep1")
+	assert.False(t, reg.Allow("ep1"))
+
+	b := reg.breakerFor("ep1")
+	b.state = circuitHalfOpen // simulate cooldown having elapsed
+
+	reg.RecordSuccess("ep1")
+	assert.True(t, reg.Allow("ep1"))
+}
+
+func TestNewCircuitBreakerRegistry_DefaultsAppliedForInvalidArgs(t *testing.T) {
+	reg := newCircuitBreakerRegistry(0, 0)
+	assert.Equal(t, defaultCircuitFailureThreshold, reg.failureThreshold)
+	assert.Equal(t, defaultCircuitCooldown, reg.cooldown)
+}
@@ -0,0 +1,97 @@
+package extproc
+
+import (
+	"fmt"
+	"sync"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	ext_proc "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/concurrency"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// pendingConcurrencyReleases holds the Release for every request the
+// ConcurrencyLimiter admitted, keyed by replayRequestID(ctx), until
+// releaseConcurrencySlot frees it. ReleaseOnDone also frees it early if the
+// client disconnects before that point is reached (see admitConcurrency).
+var pendingConcurrencyReleases sync.Map // string -> concurrency.Release
+
+// admitConcurrency gates selectedModel's request against
+// UserModelConcurrencyLimit and ModelConcurrencyLimit before routing. A nil
+// ConcurrencyLimiter disables gating entirely. On admission the returned
+// slot is registered under replayRequestID(ctx) for releaseConcurrencySlot
+// to free later, and is wrapped so ctx.TraceContext being canceled (the
+// client disconnected) frees it immediately even if that never happens.
+func (r *OpenAIRouter) admitConcurrency(ctx *RequestContext, selectedModel string) *ext_proc.ProcessingResponse {
+	if r.ConcurrencyLimiter == nil {
+		return nil
+	}
+
+	userID := ctx.Headers[r.Config.Authz.Identity.GetUserIDHeader()]
+	decision, release, err := r.ConcurrencyLimiter.AcquireUserAndModel(
+		ctx.TraceContext, userID, selectedModel, r.UserModelConcurrencyLimit, r.ModelConcurrencyLimit)
+	if err != nil {
+		logging.Infof("[Request Body] Concurrency limited: user=%s model=%s key=%s active=%d limit=%d",
+			userID, selectedModel, decision.Key, decision.Active, decision.Limit)
+		return r.createConcurrencyLimitResponse(decision)
+	}
+
+	pendingConcurrencyReleases.Store(replayRequestID(ctx), concurrency.ReleaseOnDone(ctx.TraceContext, release))
+	return nil
+}
+
+// releaseConcurrencySlot frees the concurrency slot admitConcurrency
+// reserved for ctx, if any. Called from handleResponseBody on
+// end-of-stream - the ideal point would be handleResponseHeaders when
+// response headers arrive, but that response-phase dispatch path doesn't
+// exist in this tree (see the gap noted on handleResponseBody in
+// sse_pipeline.go), so end-of-stream is the earliest point this tree can
+// observe "request complete". LoadAndDelete makes this safe to also race
+// against ReleaseOnDone firing first on client disconnect.
+func (r *OpenAIRouter) releaseConcurrencySlot(ctx *RequestContext) {
+	val, ok := pendingConcurrencyReleases.LoadAndDelete(replayRequestID(ctx))
+	if !ok {
+		return
+	}
+	val.(concurrency.Release)()
+}
+
+// createConcurrencyLimitResponse builds a 429 Too Many Requests response
+// for a request the ConcurrencyLimiter rejected, mirroring
+// createRateLimitResponse's shape but with a concurrency_error type and
+// x-concurrency-* headers instead of x-ratelimit-*.
+func (r *OpenAIRouter) createConcurrencyLimitResponse(decision *concurrency.Decision) *ext_proc.ProcessingResponse {
+	body := []byte(`{"error":{"message":"Too many concurrent requests.","type":"concurrency_error","code":429}}`)
+
+	respHeaders := []*core.HeaderValueOption{
+		{Header: &core.HeaderValue{Key: "content-type", RawValue: []byte("application/json")}},
+	}
+
+	if decision != nil {
+		respHeaders = append(respHeaders,
+			&core.HeaderValueOption{Header: &core.HeaderValue{
+				Key: "x-concurrency-limit", RawValue: []byte(fmt.Sprintf("%d", decision.Limit)),
+			}},
+			&core.HeaderValueOption{Header: &core.HeaderValue{
+				Key: "x-concurrency-active", RawValue: []byte(fmt.Sprintf("%d", decision.Active)),
+			}},
+			&core.HeaderValueOption{Header: &core.HeaderValue{
+				Key: "x-queue-position", RawValue: []byte(fmt.Sprintf("%d", decision.QueuePosition)),
+			}},
+		)
+	}
+
+	return &ext_proc.ProcessingResponse{
+		Response: &ext_proc.ProcessingResponse_ImmediateResponse{
+			ImmediateResponse: &ext_proc.ImmediateResponse{
+				Status: &typev3.HttpStatus{Code: typev3.StatusCode_TooManyRequests},
+				Headers: &ext_proc.HeaderMutation{
+					SetHeaders: respHeaders,
+				},
+				Body: body,
+			},
+		},
+	}
+}
@@ -0,0 +1,55 @@
+package extproc
+
+import (
+	"context"
+	"testing"
+
+	ext_proc "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/concurrency"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
+)
+
+// newConcurrencyTestCtx returns a RequestContext carrying a real (non-zero)
+// span context, so repeated replayRequestID(ctx) calls for the same ctx -
+// one from admitConcurrency, one from releaseConcurrencySlot - agree on the
+// same key, taken from that span rather than from the process-local counter.
+func newConcurrencyTestCtx(userID string) *RequestContext {
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{1},
+	})
+	return &RequestContext{
+		TraceContext: trace.ContextWithSpanContext(context.Background(), spanCtx),
+		Headers:      map[string]string{"x-user-id": userID},
+	}
+}
+
+func TestConcurrencyLimiter_ReleasesSlotOnResponseCompletion(t *testing.T) {
+	r := &OpenAIRouter{
+		Config:                    &config.RouterConfig{},
+		ConcurrencyLimiter:        concurrency.NewLimiter(),
+		UserModelConcurrencyLimit: concurrency.Limit{Max: 1},
+	}
+
+	// The same ctx (and so the same resolved user-id-header value, whatever
+	// it defaults to on a zero Config) is reused across every call below,
+	// so all three see the same (user, model) bucket key regardless of
+	// which header name the config points at.
+	ctx := newConcurrencyTestCtx("alice")
+
+	require.Nil(t, r.admitConcurrency(ctx, "gpt-4o"), "first request should be admitted")
+
+	blocked := r.admitConcurrency(ctx, "gpt-4o")
+	require.NotNil(t, blocked, "second concurrent request for the same user+model should be rejected while the slot is held")
+
+	_, err := r.handleResponseBody(&ext_proc.ProcessingRequest_ResponseBody{
+		ResponseBody: &ext_proc.HttpBody{EndOfStream: true},
+	}, ctx)
+	require.NoError(t, err)
+
+	assert.Nil(t, r.admitConcurrency(ctx, "gpt-4o"), "a fresh request should be admitted once the prior one's slot was released on completion")
+}
@@ -0,0 +1,236 @@
+package extproc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// GrammarFormat selects which constrained-decoding mechanism a
+// GrammarConfig describes.
+type GrammarFormat string
+
+const (
+	GrammarFormatJSONObject GrammarFormat = "json_object"
+	GrammarFormatJSONSchema GrammarFormat = "json_schema"
+	GrammarFormatRegex      GrammarFormat = "regex"
+	GrammarFormatGrammar    GrammarFormat = "grammar" // GBNF
+)
+
+// GrammarSourceKind selects where GrammarConfig.Schema's content comes
+// from.
+type GrammarSourceKind string
+
+const (
+	GrammarSourceInline GrammarSourceKind = "inline"
+	GrammarSourceFile   GrammarSourceKind = "file"
+	GrammarSourceURL    GrammarSourceKind = "url"
+)
+
+// GrammarConfig is a per-decision constrained-decoding configuration:
+// "for requests routed under this decision, enforce this schema/grammar
+// regardless of which backend format the target model speaks."
+type GrammarConfig struct {
+	Format GrammarFormat
+	// Source selects where Schema's raw content is loaded from.
+	Source GrammarSourceKind
+	// Schema is either the literal content (GrammarSourceInline), a file
+	// path (GrammarSourceFile), or a URL (GrammarSourceURL) for Source.
+	Schema string
+	// RefreshInterval, for GrammarSourceURL only, is how often a cached
+	// fetch is allowed to go stale before being re-fetched. Zero means
+	// fetch once and cache forever.
+	RefreshInterval time.Duration
+}
+
+// ValidateGrammarConfig checks cfg at config-load time so a malformed
+// schema fails fast instead of at request time. It does not attempt to
+// fetch GrammarSourceURL content - that's validated lazily on first use.
+func ValidateGrammarConfig(cfg GrammarConfig) error {
+	switch cfg.Format {
+	case GrammarFormatJSONObject, GrammarFormatJSONSchema, GrammarFormatRegex, GrammarFormatGrammar:
+	default:
+		return fmt.Errorf("unknown grammar format %q", cfg.Format)
+	}
+	switch cfg.Source {
+	case GrammarSourceInline, GrammarSourceFile, GrammarSourceURL:
+	default:
+		return fmt.Errorf("unknown grammar source %q", cfg.Source)
+	}
+	if cfg.Schema == "" {
+		return fmt.Errorf("grammar config requires a non-empty schema/source value")
+	}
+	if cfg.Format == GrammarFormatJSONSchema && cfg.Source == GrammarSourceInline {
+		var js any
+		if err := json.Unmarshal([]byte(cfg.Schema), &js); err != nil {
+			return fmt.Errorf("inline json_schema grammar is not valid JSON: %w", err)
+		}
+	}
+	return nil
+}
+
+// grammarSourceCacheEntry is one GrammarSourceURL fetch's cached content.
+type grammarSourceCacheEntry struct {
+	content   string
+	fetchedAt time.Time
+}
+
+var (
+	grammarSourceCacheMu sync.Mutex
+	grammarSourceCache   = map[string]grammarSourceCacheEntry{}
+)
+
+// grammarAppliedFlags records, per replayRequestID(ctx), whether
+// modifyRequestBodyForAutoRouting applied a grammar constraint to this
+// request - read once and cleared by startUpstreamSpanAndInjectHeaders so
+// it can stamp a grammar_applied span attribute without needing a new
+// field on the (externally-defined) RequestContext.
+var grammarAppliedFlags sync.Map // string -> bool
+
+// resolveGrammarSchema returns cfg's actual schema/grammar text, reading
+// from disk or an HTTP URL and caching URL fetches per cfg.RefreshInterval.
+func resolveGrammarSchema(cfg GrammarConfig) (string, error) {
+	switch cfg.Source {
+	case GrammarSourceInline:
+		return cfg.Schema, nil
+
+	case GrammarSourceFile:
+		data, err := os.ReadFile(cfg.Schema)
+		if err != nil {
+			return "", fmt.Errorf("reading grammar file %s: %w", cfg.Schema, err)
+		}
+		return string(data), nil
+
+	case GrammarSourceURL:
+		return resolveGrammarSourceURL(cfg.Schema, cfg.RefreshInterval)
+
+	default:
+		return "", fmt.Errorf("unknown grammar source %q", cfg.Source)
+	}
+}
+
+func resolveGrammarSourceURL(url string, refreshInterval time.Duration) (string, error) {
+	grammarSourceCacheMu.Lock()
+	entry, ok := grammarSourceCache[url]
+	grammarSourceCacheMu.Unlock()
+
+	if ok && (refreshInterval <= 0 || time.Since(entry.fetchedAt) < refreshInterval) {
+		return entry.content, nil
+	}
+
+	resp, err := http.Get(url) //nolint:gosec // URL comes from operator-authored config, not user input
+	if err != nil {
+		return "", fmt.Errorf("fetching grammar source %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching grammar source %s: status %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading grammar source %s: %w", url, err)
+	}
+
+	content := string(body)
+	grammarSourceCacheMu.Lock()
+	grammarSourceCache[url] = grammarSourceCacheEntry{content: content, fetchedAt: time.Now()}
+	grammarSourceCacheMu.Unlock()
+	return content, nil
+}
+
+// applyGrammarToRequestBody injects cfg's constrained-decoding schema into
+// body under the fields the target apiFormat understands: response_format
+// plus vLLM's guided_json/guided_regex/guided_grammar extensions for
+// OpenAI-compatible backends, or a forced single-tool tool_choice shim for
+// Anthropic (which has no native grammar knob). It returns the possibly-
+// modified body and whether a constraint was actually applied.
+func applyGrammarToRequestBody(body []byte, apiFormat string, cfg GrammarConfig) ([]byte, bool, error) {
+	schema, err := resolveGrammarSchema(cfg)
+	if err != nil {
+		return body, false, err
+	}
+
+	var req map[string]any
+	if err := json.Unmarshal(body, &req); err != nil {
+		return body, false, fmt.Errorf("decoding request body for grammar injection: %w", err)
+	}
+
+	if apiFormat == apiFormatAnthropic {
+		return applyGrammarAsAnthropicToolChoice(body, req, cfg, schema)
+	}
+
+	switch cfg.Format {
+	case GrammarFormatJSONObject:
+		req["response_format"] = map[string]any{"type": "json_object"}
+
+	case GrammarFormatJSONSchema:
+		var parsedSchema any
+		if err := json.Unmarshal([]byte(schema), &parsedSchema); err != nil {
+			return body, false, fmt.Errorf("grammar schema is not valid JSON: %w", err)
+		}
+		req["response_format"] = map[string]any{
+			"type":        "json_schema",
+			"json_schema": map[string]any{"name": "response", "schema": parsedSchema, "strict": true},
+		}
+		req["guided_json"] = parsedSchema
+
+	case GrammarFormatRegex:
+		req["guided_regex"] = schema
+
+	case GrammarFormatGrammar:
+		req["guided_grammar"] = schema
+
+	default:
+		return body, false, fmt.Errorf("unsupported grammar format %q", cfg.Format)
+	}
+
+	modified, err := json.Marshal(req)
+	if err != nil {
+		return body, false, fmt.Errorf("re-encoding request body after grammar injection: %w", err)
+	}
+	return modified, true, nil
+}
+
+// apiFormatAnthropic mirrors config.APIFormatAnthropic's string value so
+// applyGrammarToRequestBody can compare against it without importing the
+// config package just for one constant - callers pass ctx.APIFormat (or
+// its string form) through directly.
+const apiFormatAnthropic = "anthropic"
+
+// applyGrammarAsAnthropicToolChoice forces Anthropic's native tool-use
+// mechanism to emit output matching cfg's JSON schema: it appends a single
+// synthetic "structured_response" tool whose input_schema is the
+// configured schema, and forces tool_choice to it. Anthropic has no
+// regex/grammar/json_object equivalent, so non-json_schema formats are
+// left unapplied.
+func applyGrammarAsAnthropicToolChoice(body []byte, req map[string]any, cfg GrammarConfig, schema string) ([]byte, bool, error) {
+	if cfg.Format != GrammarFormatJSONSchema {
+		return body, false, fmt.Errorf("anthropic grammar shim only supports json_schema, got %q", cfg.Format)
+	}
+
+	var parsedSchema any
+	if err := json.Unmarshal([]byte(schema), &parsedSchema); err != nil {
+		return body, false, fmt.Errorf("grammar schema is not valid JSON: %w", err)
+	}
+
+	const toolName = "structured_response"
+	tool := map[string]any{
+		"name":         toolName,
+		"description":  "Return the response matching the required schema.",
+		"input_schema": parsedSchema,
+	}
+
+	existingTools, _ := req["tools"].([]any)
+	req["tools"] = append(existingTools, tool)
+	req["tool_choice"] = map[string]any{"type": "tool", "name": toolName}
+
+	modified, err := json.Marshal(req)
+	if err != nil {
+		return body, false, fmt.Errorf("re-encoding request body after grammar injection: %w", err)
+	}
+	return modified, true, nil
+}
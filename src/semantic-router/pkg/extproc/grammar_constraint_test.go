@@ -0,0 +1,100 @@
+package extproc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateGrammarConfig_ValidAndInvalid(t *testing.T) {
+	assert.NoError(t, ValidateGrammarConfig(GrammarConfig{
+		Format: GrammarFormatJSONSchema, Source: GrammarSourceInline, Schema: `{"type":"object"}`,
+	}))
+
+	assert.Error(t, ValidateGrammarConfig(GrammarConfig{Format: "bogus", Source: GrammarSourceInline, Schema: "x"}))
+	assert.Error(t, ValidateGrammarConfig(GrammarConfig{Format: GrammarFormatRegex, Source: "bogus", Schema: "x"}))
+	assert.Error(t, ValidateGrammarConfig(GrammarConfig{Format: GrammarFormatRegex, Source: GrammarSourceInline, Schema: ""}))
+	assert.Error(t, ValidateGrammarConfig(GrammarConfig{
+		Format: GrammarFormatJSONSchema, Source: GrammarSourceInline, Schema: "not json",
+	}))
+}
+
+func TestApplyGrammarToRequestBody_JSONSchemaForOpenAI(t *testing.T) {
+	cfg := GrammarConfig{Format: GrammarFormatJSONSchema, Source: GrammarSourceInline, Schema: `{"type":"object","properties":{"x":{"type":"string"}}}`}
+	body := []byte(`{"model":"gpt-4o","messages":[]}`)
+
+	modified, applied, err := applyGrammarToRequestBody(body, "openai", cfg)
+	require.NoError(t, err)
+	assert.True(t, applied)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(modified, &decoded))
+	assert.Contains(t, decoded, "response_format")
+	assert.Contains(t, decoded, "guided_json")
+}
+
+func TestApplyGrammarToRequestBody_Regex(t *testing.T) {
+	cfg := GrammarConfig{Format: GrammarFormatRegex, Source: GrammarSourceInline, Schema: `^\d+$`}
+	modified, applied, err := applyGrammarToRequestBody([]byte(`{"model":"m"}`), "openai", cfg)
+	require.NoError(t, err)
+	assert.True(t, applied)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(modified, &decoded))
+	assert.Equal(t, `^\d+$`, decoded["guided_regex"])
+}
+
+func TestApplyGrammarToRequestBody_AnthropicToolChoiceShim(t *testing.T) {
+	cfg := GrammarConfig{Format: GrammarFormatJSONSchema, Source: GrammarSourceInline, Schema: `{"type":"object"}`}
+	modified, applied, err := applyGrammarToRequestBody([]byte(`{"model":"claude-3-5-sonnet"}`), apiFormatAnthropic, cfg)
+	require.NoError(t, err)
+	assert.True(t, applied)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(modified, &decoded))
+	tools, ok := decoded["tools"].([]any)
+	require.True(t, ok)
+	require.Len(t, tools, 1)
+	toolChoice, ok := decoded["tool_choice"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "tool", toolChoice["type"])
+}
+
+func TestApplyGrammarToRequestBody_AnthropicRejectsNonJSONSchema(t *testing.T) {
+	cfg := GrammarConfig{Format: GrammarFormatRegex, Source: GrammarSourceInline, Schema: `^\d+$`}
+	_, applied, err := applyGrammarToRequestBody([]byte(`{}`), apiFormatAnthropic, cfg)
+	assert.Error(t, err)
+	assert.False(t, applied)
+}
+
+func TestResolveGrammarSchema_File(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/schema.json"
+	require.NoError(t, os.WriteFile(path, []byte(`{"type":"string"}`), 0o644))
+
+	schema, err := resolveGrammarSchema(GrammarConfig{Source: GrammarSourceFile, Schema: path})
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"string"}`, schema)
+}
+
+func TestResolveGrammarSchema_URLCachesUntilRefresh(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"type":"object"}`))
+	}))
+	defer srv.Close()
+
+	cfg := GrammarConfig{Source: GrammarSourceURL, Schema: srv.URL, RefreshInterval: time.Hour}
+	_, err := resolveGrammarSchema(cfg)
+	require.NoError(t, err)
+	_, err = resolveGrammarSchema(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls, "second call within RefreshInterval should hit the cache")
+}
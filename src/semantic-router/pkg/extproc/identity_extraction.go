@@ -0,0 +1,145 @@
+package extproc
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/auth/identity"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// authorizationHeader is the (lowercased, as Envoy ext_proc delivers them)
+// HTTP header carrying a Bearer JWT, when one is present.
+const authorizationHeader = "authorization"
+
+// identityVerifier is built lazily from environment configuration on first
+// use, mirroring the lazy-connect style of pkg/memory's ConversationStore:
+// construction is side-effect-free until a token actually needs verifying.
+var (
+	identityVerifierOnce sync.Once
+	identityVerifier     identity.Verifier
+)
+
+// identityVerifierConfigFromEnv builds an identity.Config from environment
+// variables, so JWT verification can be enabled without plumbing a new
+// field through config.RouterConfig in this pass. VSR_JWT_VERIFICATION
+// enables it; VSR_JWT_JWKS_URL or VSR_JWT_STATIC_KEY selects the key
+// source.
+func identityVerifierConfigFromEnv() identity.Config {
+	enabled, _ := strconv.ParseBool(os.Getenv("VSR_JWT_VERIFICATION"))
+	config := identity.Config{
+		Enabled:  enabled,
+		Issuer:   os.Getenv("VSR_JWT_ISSUER"),
+		Audience: os.Getenv("VSR_JWT_AUDIENCE"),
+	}
+
+	if staticKey := os.Getenv("VSR_JWT_STATIC_KEY"); staticKey != "" {
+		config.KeySource = identity.KeySourceStatic
+		config.StaticKey = []byte(staticKey)
+	} else {
+		config.KeySource = identity.KeySourceJWKS
+		config.JWKSURL = os.Getenv("VSR_JWT_JWKS_URL")
+	}
+
+	if refresh := os.Getenv("VSR_JWT_JWKS_REFRESH_INTERVAL"); refresh != "" {
+		if d, err := time.ParseDuration(refresh); err == nil {
+			config.JWKSRefreshInterval = d
+		}
+	}
+
+	return config
+}
+
+func getIdentityVerifier() identity.Verifier {
+	identityVerifierOnce.Do(func() {
+		config := identityVerifierConfigFromEnv()
+		verifier, err := identity.NewVerifier(config)
+		if err != nil {
+			logging.Errorf("getIdentityVerifier: failed to build JWT verifier, falling back to header-based identity: %v", err)
+			verifier, _ = identity.NewVerifier(identity.Config{Enabled: false})
+		}
+		identityVerifier = verifier
+	})
+	return identityVerifier
+}
+
+// identityVerifierForTesting, when non-nil, is used instead of the
+// lazily-built singleton. Tests set this directly to exercise JWT
+// verification without environment-variable plumbing or a live JWKS
+// endpoint.
+var identityVerifierForTesting identity.Verifier
+
+// extractIdentityClaims verifies a Bearer token from the Authorization
+// header, if present, and returns the resolved claims. tokenPresent reports
+// whether an Authorization header was actually sent, so callers can tell "no
+// token, fall back to the header-based identity" apart from "a token was
+// sent but failed verification" - the latter must NOT fall back, or a
+// request could forge any x-authz-user-id it likes simply by attaching a
+// garbage Bearer token to make verification fail open.
+func extractIdentityClaims(ctx *RequestContext) (claims *identity.Claims, tokenPresent bool) {
+	token, ok := ctx.Headers[authorizationHeader]
+	if !ok || strings.TrimSpace(token) == "" {
+		return nil, false
+	}
+
+	verifier := identityVerifierForTesting
+	if verifier == nil {
+		verifier = getIdentityVerifier()
+	}
+
+	claims, err := verifier.VerifyToken(token)
+	if err != nil {
+		logging.Debugf("extractIdentityClaims: JWT verification failed, rejecting identity (no fallback for a present token): %v", err)
+		return nil, true
+	}
+	return claims, true
+}
+
+// MemoryIdentityExtractor resolves the trusted user (and, when available,
+// tenant/scope/groups) identity for a request. The default implementation
+// verifies a Bearer JWT and falls back to the x-authz-user-id header path;
+// it's an interface so alternative identity sources (e.g. a service mesh's
+// mTLS identity) can be swapped in without touching extractMemoryInfo.
+type MemoryIdentityExtractor interface {
+	// Resolve returns the trusted user ID and, when the identity came from
+	// a verified JWT, the full claims (nil otherwise).
+	Resolve(ctx *RequestContext) (userID string, claims *identity.Claims)
+}
+
+// defaultMemoryIdentityExtractor implements MemoryIdentityExtractor by
+// verifying a Bearer JWT (via getIdentityVerifier's cached, periodically
+// refreshed JWKS client) and falling back to the x-authz-user-id header path
+// only when no token is present at all. A token that IS present but fails
+// verification (expired, malformed, wrong issuer/signature) is rejected
+// outright - it must never fall back, or a caller could impersonate anyone
+// by sending a garbage Bearer token to deliberately fail verification.
+type defaultMemoryIdentityExtractor struct{}
+
+// Resolve implements MemoryIdentityExtractor.
+func (defaultMemoryIdentityExtractor) Resolve(ctx *RequestContext) (userID string, claims *identity.Claims) {
+	claims, tokenPresent := extractIdentityClaims(ctx)
+	if claims != nil {
+		return claims.UserID, claims
+	}
+	if tokenPresent {
+		return "", nil
+	}
+	return extractUserID(ctx), nil
+}
+
+// memoryIdentityExtractor is the MemoryIdentityExtractor extractMemoryInfo
+// uses. Tests may swap this to exercise alternative identity sources.
+var memoryIdentityExtractor MemoryIdentityExtractor = defaultMemoryIdentityExtractor{}
+
+// resolveUserIdentity resolves the trusted user ID and (optional) identity
+// claims for a request via memoryIdentityExtractor. JWT claims, when
+// present and valid, take precedence over the x-authz-user-id header so a
+// deployment without a JWT-verifying upstream proxy can still authenticate
+// requests directly. A present-but-invalid JWT yields ("", nil) rather than
+// falling back to the header - see defaultMemoryIdentityExtractor.Resolve.
+func resolveUserIdentity(ctx *RequestContext) (userID string, claims *identity.Claims) {
+	return memoryIdentityExtractor.Resolve(ctx)
+}
@@ -0,0 +1,144 @@
+package extproc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/auth/identity"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/headers"
+)
+
+const testJWTSigningKey = "test-signing-key"
+
+func withTestIdentityVerifier(t *testing.T, issuer string) {
+	t.Helper()
+	verifier, err := identity.NewVerifier(identity.Config{
+		Enabled:   true,
+		KeySource: identity.KeySourceStatic,
+		StaticKey: []byte(testJWTSigningKey),
+		Issuer:    issuer,
+	})
+	require.NoError(t, err)
+
+	identityVerifierForTesting = verifier
+	t.Cleanup(func() { identityVerifierForTesting = nil })
+}
+
+func signTestJWT(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(testJWTSigningKey))
+	require.NoError(t, err)
+	return signed
+}
+
+func TestExtractIdentityClaims_TokenOnly(t *testing.T) {
+	withTestIdentityVerifier(t, "")
+	token := signTestJWT(t, jwt.MapClaims{
+		"sub":    "user_from_jwt",
+		"tenant": "acme",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	})
+
+	ctx := &RequestContext{
+		Headers: map[string]string{authorizationHeader: "Bearer " + token},
+	}
+
+	claims, tokenPresent := extractIdentityClaims(ctx)
+	require.NotNil(t, claims)
+	assert.True(t, tokenPresent)
+	assert.Equal(t, "user_from_jwt", claims.UserID)
+	assert.Equal(t, "acme", claims.Tenant)
+}
+
+func TestExtractIdentityClaims_NoAuthorizationHeader(t *testing.T) {
+	withTestIdentityVerifier(t, "")
+	ctx := &RequestContext{Headers: map[string]string{}}
+
+	claims, tokenPresent := extractIdentityClaims(ctx)
+	assert.Nil(t, claims)
+	assert.False(t, tokenPresent)
+}
+
+func TestExtractIdentityClaims_ExpiredToken(t *testing.T) {
+	withTestIdentityVerifier(t, "")
+	token := signTestJWT(t, jwt.MapClaims{
+		"sub": "user_from_jwt",
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	})
+
+	ctx := &RequestContext{
+		Headers: map[string]string{authorizationHeader: "Bearer " + token},
+	}
+
+	claims, tokenPresent := extractIdentityClaims(ctx)
+	assert.Nil(t, claims, "expired token should not yield claims")
+	assert.True(t, tokenPresent, "a token was sent, even though it failed verification")
+}
+
+func TestExtractIdentityClaims_IssuerMismatch(t *testing.T) {
+	withTestIdentityVerifier(t, "expected-issuer")
+	token := signTestJWT(t, jwt.MapClaims{
+		"sub": "user_from_jwt",
+		"iss": "some-other-issuer",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	ctx := &RequestContext{
+		Headers: map[string]string{authorizationHeader: "Bearer " + token},
+	}
+
+	claims, tokenPresent := extractIdentityClaims(ctx)
+	assert.Nil(t, claims, "issuer mismatch should not yield claims")
+	assert.True(t, tokenPresent, "a token was sent, even though it failed verification")
+}
+
+func TestResolveUserIdentity_TokenTakesPrecedenceOverHeader(t *testing.T) {
+	withTestIdentityVerifier(t, "")
+	token := signTestJWT(t, jwt.MapClaims{
+		"sub": "user_from_jwt",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	ctx := &RequestContext{
+		Headers: map[string]string{
+			authorizationHeader: "Bearer " + token,
+			headers.AuthzUserID: "user_from_auth_header",
+		},
+	}
+
+	userID, claims := resolveUserIdentity(ctx)
+	assert.Equal(t, "user_from_jwt", userID, "a verified JWT should take precedence over the auth header")
+	require.NotNil(t, claims)
+}
+
+func TestResolveUserIdentity_FallsBackToAuthHeaderWithoutToken(t *testing.T) {
+	withTestIdentityVerifier(t, "")
+	ctx := &RequestContext{
+		Headers: map[string]string{
+			headers.AuthzUserID: "user_from_auth_header",
+		},
+	}
+
+	userID, claims := resolveUserIdentity(ctx)
+	assert.Equal(t, "user_from_auth_header", userID)
+	assert.Nil(t, claims)
+}
+
+func TestResolveUserIdentity_RejectsRatherThanFallingBackOnVerificationFailure(t *testing.T) {
+	withTestIdentityVerifier(t, "")
+	ctx := &RequestContext{
+		Headers: map[string]string{
+			authorizationHeader: "Bearer not-a-valid-jwt",
+			headers.AuthzUserID: "user_from_auth_header",
+		},
+	}
+
+	userID, claims := resolveUserIdentity(ctx)
+	assert.Equal(t, "", userID, "a present but invalid token must not fall back to the auth header - that would let a caller impersonate anyone by sending a garbage token")
+	assert.Nil(t, claims)
+}
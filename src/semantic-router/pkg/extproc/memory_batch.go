@@ -0,0 +1,131 @@
+package extproc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+)
+
+// defaultMaxBatchConcurrency bounds BatchExtractMemoryInfo's worker pool when
+// the decision's "memory" plugin config doesn't set max_batch_concurrency.
+const defaultMaxBatchConcurrency = 8
+
+// MemoryBundle is one entry of a BatchExtractMemoryInfo result: the resolved
+// history for (UserID, SessionID), or Err if it couldn't be fetched. A
+// per-entry Err lets dashboards/evaluators replay the sessions that did
+// resolve instead of failing the whole batch over one bad session ID.
+type MemoryBundle struct {
+	UserID    string
+	SessionID string
+	History   []memory.Message
+	Err       error
+}
+
+// maxBatchConcurrencyFromConfig reads "max_batch_concurrency" from a
+// decision's "memory" plugin Configuration map, falling back to
+// defaultMaxBatchConcurrency when unset or non-positive.
+func maxBatchConcurrencyFromConfig(pluginConfig map[string]interface{}) int {
+	if n := intFromConfig(pluginConfig, "max_batch_concurrency"); n > 0 {
+		return n
+	}
+	return defaultMaxBatchConcurrency
+}
+
+// BatchExtractMemoryInfo fetches conversation history for multiple sessions
+// belonging to ctx's caller in one round-trip, fanning out to the decision's
+// ConversationStore over a worker pool bounded by max_batch_concurrency.
+// Repeat session IDs are resolved once and the result reused for every
+// occurrence. The caller's userID and group access are resolved/checked once
+// up front with the same rules as extractMemoryInfo, since every entry in
+// the batch shares the same caller.
+//
+// This is meant for dashboards and evaluators that need to replay many
+// conversations (or warm the in-process cache ahead of a burst of related
+// completions) without paying an N+1 round-trip per session.
+//
+// The admin-facing `POST /v1/memory/batch` route (decoding a session ID list
+// and returning one MemoryBundle per entry) is wired up in the admin HTTP
+// server alongside the other `/v1/memory/*` routes, not in this package.
+func BatchExtractMemoryInfo(ctx *RequestContext, sessionIDs []string) ([]MemoryBundle, error) {
+	userID, claims := resolveUserIdentity(ctx)
+	if claims != nil {
+		ctx.IdentityClaims = claims
+	}
+	if userID == "" {
+		return nil, fmt.Errorf("userID is required for batch memory extraction but not provided")
+	}
+
+	pluginConfig := memoryPluginConfiguration(ctx)
+	var callerGroups []string
+	if claims != nil {
+		callerGroups = claims.Groups
+	}
+	if err := checkGroupAccess(allowedGroupsFromConfig(pluginConfig), callerGroups); err != nil {
+		return nil, err
+	}
+
+	if len(sessionIDs) == 0 {
+		return nil, nil
+	}
+
+	// Dedup while preserving the caller's requested order: map each input
+	// index to a slot in `unique`, so repeat session IDs are only fetched
+	// once but still appear in the returned slice at their original position.
+	unique := make([]string, 0, len(sessionIDs))
+	slotOf := make(map[string]int, len(sessionIDs))
+	slotForIndex := make([]int, len(sessionIDs))
+	for i, sid := range sessionIDs {
+		slot, ok := slotOf[sid]
+		if !ok {
+			slot = len(unique)
+			slotOf[sid] = slot
+			unique = append(unique, sid)
+		}
+		slotForIndex[i] = slot
+	}
+
+	store := conversationStoreForDecision(ctx)
+	resolved := make([]MemoryBundle, len(unique))
+
+	concurrency := maxBatchConcurrencyFromConfig(pluginConfig)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, sid := range unique {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, sid string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			resolved[i] = fetchMemoryBundle(store, userID, sid)
+		}(i, sid)
+	}
+	wg.Wait()
+
+	results := make([]MemoryBundle, len(sessionIDs))
+	for i, slot := range slotForIndex {
+		results[i] = resolved[slot]
+	}
+	return results, nil
+}
+
+// fetchMemoryBundle resolves a single session's history from store. A
+// missing conversation is not an error - it just comes back with an empty
+// History, same as extractMemoryInfo's single-session path.
+func fetchMemoryBundle(store memory.ConversationStore, userID, sessionID string) MemoryBundle {
+	bundle := MemoryBundle{UserID: userID, SessionID: sessionID}
+	if store == nil || sessionID == "" {
+		return bundle
+	}
+
+	storedResponses, err := store.Get(context.Background(), sessionID)
+	if err != nil {
+		if err != memory.ErrConversationNotFound {
+			bundle.Err = err
+		}
+		return bundle
+	}
+	bundle.History = convertStoredResponsesToMessages(storedResponses)
+	return bundle
+}
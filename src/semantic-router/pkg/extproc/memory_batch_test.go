@@ -0,0 +1,91 @@
+package extproc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
+)
+
+func TestMaxBatchConcurrencyFromConfig_Default(t *testing.T) {
+	assert.Equal(t, defaultMaxBatchConcurrency, maxBatchConcurrencyFromConfig(nil))
+	assert.Equal(t, defaultMaxBatchConcurrency, maxBatchConcurrencyFromConfig(map[string]interface{}{}))
+}
+
+func TestMaxBatchConcurrencyFromConfig_Configured(t *testing.T) {
+	n := maxBatchConcurrencyFromConfig(map[string]interface{}{"max_batch_concurrency": 3})
+	assert.Equal(t, 3, n)
+}
+
+func TestMaxBatchConcurrencyFromConfig_NonPositiveFallsBackToDefault(t *testing.T) {
+	n := maxBatchConcurrencyFromConfig(map[string]interface{}{"max_batch_concurrency": 0})
+	assert.Equal(t, defaultMaxBatchConcurrency, n)
+}
+
+func TestBatchExtractMemoryInfo_RequiresUserID(t *testing.T) {
+	ctx := &RequestContext{RequestID: "req_123"}
+
+	_, err := BatchExtractMemoryInfo(ctx, []string{"sess_1"})
+	require.Error(t, err)
+}
+
+func TestBatchExtractMemoryInfo_GroupGatedNamespaceForbidsOutsideGroup(t *testing.T) {
+	ctx := &RequestContext{
+		RequestID: "req_123",
+		Headers: map[string]string{
+			"x-authz-user-id": "auth_user_123",
+		},
+		VSRSelectedDecision: &config.Decision{
+			Name: "team_memory",
+			Plugins: []config.DecisionPlugin{
+				{
+					Type: "memory",
+					Configuration: map[string]interface{}{
+						"allowed_groups": []interface{}{"eng"},
+					},
+				},
+			},
+		},
+	}
+
+	_, err := BatchExtractMemoryInfo(ctx, []string{"sess_1"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrMemoryForbidden)
+}
+
+func TestBatchExtractMemoryInfo_NoStoreConfiguredReturnsEmptyBundlesInOrder(t *testing.T) {
+	ctx := &RequestContext{
+		RequestID: "req_123",
+		Headers: map[string]string{
+			"x-authz-user-id": "auth_user_123",
+		},
+	}
+
+	results, err := BatchExtractMemoryInfo(ctx, []string{"sess_1", "sess_2", "sess_1"})
+	require.NoError(t, err)
+	require.Len(t, results, 3, "result length must match the input, including repeats")
+
+	for _, bundle := range results {
+		assert.NoError(t, bundle.Err)
+		assert.Equal(t, "auth_user_123", bundle.UserID)
+		assert.Empty(t, bundle.History)
+	}
+	assert.Equal(t, "sess_1", results[0].SessionID)
+	assert.Equal(t, "sess_2", results[1].SessionID)
+	assert.Equal(t, "sess_1", results[2].SessionID)
+}
+
+func TestBatchExtractMemoryInfo_EmptyInput(t *testing.T) {
+	ctx := &RequestContext{
+		RequestID: "req_123",
+		Headers: map[string]string{
+			"x-authz-user-id": "auth_user_123",
+		},
+	}
+
+	results, err := BatchExtractMemoryInfo(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
@@ -0,0 +1,58 @@
+package extproc
+
+import (
+	"errors"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+)
+
+// ErrMemoryForbidden is returned by extractMemoryInfo when the caller's
+// verified groups don't intersect a memory namespace's configured
+// allowed-groups list. It's a *memory.Error with CodePermissionDenied, so a
+// gRPC-facing caller can map it with memory.GRPCStatus/HTTPStatus the same
+// way it would any other memory error, without special-casing this one on
+// error text. Routers should map this to a 403 response without revealing
+// whether the namespace actually has stored history - the point of the
+// check is to keep that fact itself from leaking across groups.
+var ErrMemoryForbidden = memory.NewError(memory.CodePermissionDenied, "checkGroupAccess", "",
+	errors.New("caller's groups are not permitted to access this memory namespace"))
+
+// allowedGroupsFromConfig reads the "allowed_groups" key from a decision's
+// "memory" plugin Configuration map: a list of group names permitted to
+// share the namespace keyed by userID/sessionID. An empty/missing list
+// means the namespace isn't group-gated - every caller with a valid
+// userID is allowed, as before this feature existed.
+func allowedGroupsFromConfig(pluginConfig map[string]interface{}) []string {
+	raw, ok := pluginConfig["allowed_groups"].([]interface{})
+	if !ok {
+		return nil
+	}
+	groups := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok && s != "" {
+			groups = append(groups, s)
+		}
+	}
+	return groups
+}
+
+// checkGroupAccess enforces OR-semantics group gating: access is allowed if
+// allowedGroups is empty (namespace isn't gated) or callerGroups intersects
+// it at all. This enables shared "team memory" across every user in a
+// permitted group while keeping other groups out.
+func checkGroupAccess(allowedGroups, callerGroups []string) error {
+	if len(allowedGroups) == 0 {
+		return nil
+	}
+
+	allowed := make(map[string]struct{}, len(allowedGroups))
+	for _, g := range allowedGroups {
+		allowed[g] = struct{}{}
+	}
+	for _, g := range callerGroups {
+		if _, ok := allowed[g]; ok {
+			return nil
+		}
+	}
+	return ErrMemoryForbidden
+}
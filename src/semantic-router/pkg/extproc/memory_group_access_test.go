@@ -0,0 +1,117 @@
+package extproc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/responseapi"
+)
+
+func TestAllowedGroupsFromConfig(t *testing.T) {
+	groups := allowedGroupsFromConfig(map[string]interface{}{
+		"allowed_groups": []interface{}{"eng", "admins"},
+	})
+	assert.Equal(t, []string{"eng", "admins"}, groups)
+}
+
+func TestAllowedGroupsFromConfig_MissingKey(t *testing.T) {
+	assert.Nil(t, allowedGroupsFromConfig(map[string]interface{}{}))
+	assert.Nil(t, allowedGroupsFromConfig(nil))
+}
+
+func TestCheckGroupAccess_UngatedNamespaceAllowsAnyone(t *testing.T) {
+	assert.NoError(t, checkGroupAccess(nil, nil))
+	assert.NoError(t, checkGroupAccess(nil, []string{"eng"}))
+}
+
+func TestCheckGroupAccess_AllowsOnIntersection(t *testing.T) {
+	err := checkGroupAccess([]string{"eng", "admins"}, []string{"sales", "eng"})
+	assert.NoError(t, err)
+}
+
+func TestCheckGroupAccess_RejectsWithoutIntersection(t *testing.T) {
+	err := checkGroupAccess([]string{"eng", "admins"}, []string{"sales", "support"})
+	assert.ErrorIs(t, err, ErrMemoryForbidden)
+}
+
+func TestCheckGroupAccess_GatedNamespaceRejectsEmptyCallerGroups(t *testing.T) {
+	err := checkGroupAccess([]string{"eng"}, nil)
+	assert.ErrorIs(t, err, ErrMemoryForbidden)
+}
+
+func TestExtractMemoryInfo_GroupGatedNamespaceForbidsOutsideGroup(t *testing.T) {
+	withTestIdentityVerifier(t, "")
+	token := signTestJWT(t, jwt.MapClaims{
+		"sub":    "user_from_jwt",
+		"groups": []interface{}{"sales"},
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	})
+
+	ctx := &RequestContext{
+		RequestID: "req_123",
+		Headers: map[string]string{
+			authorizationHeader: "Bearer " + token,
+		},
+		VSRSelectedDecision: &config.Decision{
+			Name: "team_memory",
+			Plugins: []config.DecisionPlugin{
+				{
+					Type: "memory",
+					Configuration: map[string]interface{}{
+						"allowed_groups": []interface{}{"eng", "admins"},
+					},
+				},
+			},
+		},
+		ResponseAPICtx: &ResponseAPIContext{
+			IsResponseAPIRequest: true,
+			ConversationID:       "conv_123",
+		},
+	}
+
+	_, _, _, err := extractMemoryInfo(ctx)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrMemoryForbidden)
+}
+
+func TestExtractMemoryInfo_GroupGatedNamespaceAllowsMemberOfGroup(t *testing.T) {
+	withTestIdentityVerifier(t, "")
+	token := signTestJWT(t, jwt.MapClaims{
+		"sub":    "user_from_jwt",
+		"groups": []interface{}{"eng"},
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	})
+
+	ctx := &RequestContext{
+		RequestID: "req_123",
+		Headers: map[string]string{
+			authorizationHeader: "Bearer " + token,
+		},
+		VSRSelectedDecision: &config.Decision{
+			Name: "team_memory",
+			Plugins: []config.DecisionPlugin{
+				{
+					Type: "memory",
+					Configuration: map[string]interface{}{
+						"allowed_groups": []interface{}{"eng", "admins"},
+					},
+				},
+			},
+		},
+		ResponseAPICtx: &ResponseAPIContext{
+			IsResponseAPIRequest: true,
+			ConversationID:       "conv_123",
+			OriginalRequest:      &responseapi.ResponseAPIRequest{},
+		},
+	}
+
+	sessionID, userID, _, err := extractMemoryInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "conv_123", sessionID)
+	assert.Equal(t, "user_from_jwt", userID)
+}
@@ -0,0 +1,153 @@
+package extproc
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// defaultMemoryHealthCheckInterval is how often memoryStoreHealthGate
+// re-checks MemoryStore's health when the router doesn't configure its own.
+const defaultMemoryHealthCheckInterval = 30 * time.Second
+
+// connectWithBackoff calls fn up to attempts times with exponential backoff
+// between failures, starting at initialBackoff and doubling up to
+// maxBackoff. attempts <= 0 makes a single attempt (no retry). Used to
+// bootstrap a Milvus-backed store (response store, memory store) without the
+// whole feature staying disabled for the process lifetime just because it
+// raced Milvus coming up in Kubernetes.
+func connectWithBackoff(attempts int, initialBackoff, maxBackoff time.Duration, fn func() error) error {
+	if attempts <= 0 {
+		attempts = 1
+	}
+	if initialBackoff <= 0 {
+		initialBackoff = 500 * time.Millisecond
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = 10 * time.Second
+	}
+
+	backoff := initialBackoff
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if err := fn(); err != nil {
+			lastErr = err
+			if attempt < attempts-1 {
+				logging.Warnf("Connect attempt %d/%d failed: %v, retrying in %s", attempt+1, attempts, err, backoff)
+				time.Sleep(backoff)
+				backoff *= 2
+				if backoff > maxBackoff {
+					backoff = maxBackoff
+				}
+			}
+			continue
+		}
+		return nil
+	}
+	return lastErr
+}
+
+// memoryStoreHealthGate periodically re-checks MemoryStore's health (via the
+// optional memory.StoreHealthChecker capability) and flips an atomic flag
+// both the request path and Envoy's health check endpoint can read. This
+// lets a Milvus outage degrade memory retrieval/extraction gracefully
+// instead of crash-looping the whole router or restarting the process to
+// pick the backend back up. Mirrors memory.ExpiryReaper's
+// Start/Stop-on-a-ticker shape.
+type memoryStoreHealthGate struct {
+	store    memory.Store
+	interval time.Duration
+	healthy  atomic.Bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newMemoryStoreHealthGate creates a gate for store, initially reporting
+// healthy so the router behaves exactly as before until the first check
+// runs. interval <= 0 defaults to defaultMemoryHealthCheckInterval.
+func newMemoryStoreHealthGate(store memory.Store, interval time.Duration) *memoryStoreHealthGate {
+	if interval <= 0 {
+		interval = defaultMemoryHealthCheckInterval
+	}
+	g := &memoryStoreHealthGate{store: store, interval: interval}
+	g.healthy.Store(true)
+	return g
+}
+
+// Healthy reports the result of the most recent check. It defaults to true
+// if Start hasn't run a check yet, or if store doesn't implement
+// memory.StoreHealthChecker - with no health signal to poll, there's
+// nothing to gate on.
+func (g *memoryStoreHealthGate) Healthy() bool {
+	return g.healthy.Load()
+}
+
+// Start runs one check immediately, then one every g.interval, until ctx is
+// done or Stop is called. No-op if store doesn't implement
+// memory.StoreHealthChecker. Safe to call at most once per gate.
+func (g *memoryStoreHealthGate) Start(ctx context.Context) {
+	checker, ok := g.store.(memory.StoreHealthChecker)
+	if !ok {
+		return
+	}
+
+	g.stop = make(chan struct{})
+	g.done = make(chan struct{})
+
+	check := func() {
+		err := checker.HealthCheck(ctx)
+		wasHealthy := g.healthy.Swap(err == nil)
+		switch {
+		case err != nil && wasHealthy:
+			logging.Warnf("Memory: store health check failed, disabling memory until it recovers: %v", err)
+		case err == nil && !wasHealthy:
+			logging.Infof("Memory: store health check recovered")
+		}
+	}
+
+	go func() {
+		defer close(g.done)
+		check()
+		ticker := time.NewTicker(g.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				check()
+			case <-g.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the background loop, if Start was ever called, and waits for it
+// to exit before returning.
+func (g *memoryStoreHealthGate) Stop() {
+	if g.stop == nil {
+		return
+	}
+	close(g.stop)
+	<-g.done
+}
+
+// MemoryStoreReady reports whether MemoryStore is currently usable: enabled
+// at all, and - if the background health gate has run a check - still
+// passing it. Envoy's health check endpoint can consume this to report the
+// router as degraded rather than crash-looping it over a transient Milvus
+// outage.
+func (r *OpenAIRouter) MemoryStoreReady() bool {
+	if r.MemoryStore == nil {
+		return false
+	}
+	if r.memoryHealth == nil {
+		return true
+	}
+	return r.memoryHealth.Healthy()
+}
@@ -0,0 +1,99 @@
+package extproc
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+)
+
+// healthState wraps an error so fakeHealthCheckStore can store it in an
+// atomic.Value - Value.Store panics on a literal nil, so a nil error must be
+// boxed in a struct rather than stored directly.
+type healthState struct{ err error }
+
+// fakeHealthCheckStore implements memory.Store (via embedding) plus
+// memory.StoreHealthChecker, returning whatever error healthErr currently
+// holds so a test can flip it mid-run.
+type fakeHealthCheckStore struct {
+	memory.Store
+	healthErr atomic.Value // healthState
+}
+
+func newFakeHealthCheckStore() *fakeHealthCheckStore {
+	s := &fakeHealthCheckStore{Store: memory.NewInMemoryStore()}
+	s.healthErr.Store(healthState{})
+	return s
+}
+
+func (s *fakeHealthCheckStore) HealthCheck(ctx context.Context) error {
+	return s.healthErr.Load().(healthState).err
+}
+
+func (s *fakeHealthCheckStore) setHealthErr(err error) {
+	s.healthErr.Store(healthState{err: err})
+}
+
+func TestMemoryStoreHealthGate_NoCheckerStaysHealthy(t *testing.T) {
+	gate := newMemoryStoreHealthGate(memory.NewInMemoryStore(), time.Millisecond)
+	gate.Start(context.Background())
+	defer gate.Stop()
+
+	assert.True(t, gate.Healthy())
+}
+
+func TestMemoryStoreHealthGate_FlipsOnFailureAndRecovery(t *testing.T) {
+	store := newFakeHealthCheckStore()
+	gate := newMemoryStoreHealthGate(store, 2*time.Millisecond)
+	gate.Start(context.Background())
+	defer gate.Stop()
+
+	require.True(t, gate.Healthy())
+
+	store.setHealthErr(errors.New("milvus down"))
+	require.Eventually(t, func() bool { return !gate.Healthy() }, time.Second, time.Millisecond)
+
+	store.setHealthErr(nil)
+	require.Eventually(t, gate.Healthy, time.Second, time.Millisecond)
+}
+
+func TestMemoryStoreReady_NilStoreNotReady(t *testing.T) {
+	r := &OpenAIRouter{}
+	assert.False(t, r.MemoryStoreReady())
+}
+
+func TestMemoryStoreReady_NoGateIsReady(t *testing.T) {
+	r := &OpenAIRouter{MemoryStore: memory.NewInMemoryStore()}
+	assert.True(t, r.MemoryStoreReady())
+}
+
+func TestConnectWithBackoff_SucceedsAfterRetries(t *testing.T) {
+	attempts := 0
+	err := connectWithBackoff(3, time.Millisecond, 5*time.Millisecond, func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestConnectWithBackoff_ExhaustsAttempts(t *testing.T) {
+	attempts := 0
+	err := connectWithBackoff(2, time.Millisecond, 2*time.Millisecond, func() error {
+		attempts++
+		return errors.New("still down")
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 2, attempts)
+}
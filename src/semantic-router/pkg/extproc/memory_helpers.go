@@ -1,17 +1,173 @@
 package extproc
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/responseapi"
 )
 
+// conversationStoreCache holds one memory.ConversationStore per decision name,
+// built lazily on first use. Decisions share a backend config for the
+// lifetime of the process, so there's no need to rebuild the store (and,
+// for the Redis backend, reconnect) on every request.
+var (
+	conversationStoreCacheMu sync.Mutex
+	conversationStoreCache   = make(map[string]memory.ConversationStore)
+)
+
+// memoryStoreCache holds one memory.MemoryStore per decision name, keyed and
+// built lazily the same way conversationStoreCache is.
+var (
+	memoryStoreCacheMu sync.Mutex
+	memoryStoreCache   = make(map[string]memory.MemoryStore)
+)
+
+// memoryPluginConfiguration returns the Configuration map of the "memory"
+// decision plugin attached to ctx.VSRSelectedDecision, or nil if there isn't
+// one.
+func memoryPluginConfiguration(ctx *RequestContext) map[string]interface{} {
+	if ctx.VSRSelectedDecision == nil {
+		return nil
+	}
+	for _, plugin := range ctx.VSRSelectedDecision.Plugins {
+		if plugin.Type == "memory" {
+			return plugin.Configuration
+		}
+	}
+	return nil
+}
+
+// conversationStoreForDecision lazily builds (and caches, per decision name)
+// the ConversationStore backing a decision's memory plugin config. Returns
+// nil if the decision has no memory plugin configured, so callers can fall
+// back to ctx.ResponseAPICtx.ConversationHistory.
+func conversationStoreForDecision(ctx *RequestContext) memory.ConversationStore {
+	pluginConfig := memoryPluginConfiguration(ctx)
+	if pluginConfig == nil {
+		return nil
+	}
+
+	cacheKey := ctx.VSRSelectedDecisionName
+
+	conversationStoreCacheMu.Lock()
+	defer conversationStoreCacheMu.Unlock()
+
+	if store, ok := conversationStoreCache[cacheKey]; ok {
+		return store
+	}
+
+	store, err := memory.NewConversationStoreFromPluginConfig(pluginConfig)
+	if err != nil {
+		logging.Errorf("conversationStoreForDecision: failed to build conversation store for decision '%s': %v", cacheKey, err)
+		return nil
+	}
+	conversationStoreCache[cacheKey] = store
+	return store
+}
+
+// memoryStoreForDecision lazily builds (and caches, per decision name) the
+// MemoryStore backing a decision's "memory" plugin "store" config block.
+// Returns nil when the plugin config has no "store" block, so callers fall
+// back to whatever history the request itself carries.
+func memoryStoreForDecision(ctx *RequestContext) memory.MemoryStore {
+	pluginConfig := memoryPluginConfiguration(ctx)
+	storeConfig := memory.MemoryStoreConfigFromPluginConfig(pluginConfig)
+	if storeConfig == nil {
+		return nil
+	}
+
+	cacheKey := ctx.VSRSelectedDecisionName
+
+	memoryStoreCacheMu.Lock()
+	defer memoryStoreCacheMu.Unlock()
+
+	if store, ok := memoryStoreCache[cacheKey]; ok {
+		return store
+	}
+
+	store, err := memory.NewMemoryStoreFromConfig(*storeConfig)
+	if err != nil {
+		logging.Errorf("memoryStoreForDecision: failed to build memory store for decision '%s': %v", cacheKey, err)
+		return nil
+	}
+	memoryStoreCache[cacheKey] = store
+	return store
+}
+
+// resolveResponseAPIHistory returns the Response API conversation history to
+// use for memory extraction. It prefers the history already preloaded onto
+// ctx.ResponseAPICtx by the translator; when that's unset, it falls back to
+// fetching the conversation lazily from the decision's ConversationStore (if
+// one is configured), so callers aren't required to preload the whole chain
+// onto the request context up front.
+func resolveResponseAPIHistory(ctx *RequestContext, sessionID string) []memory.Message {
+	if ctx.ResponseAPICtx.ConversationHistory != nil {
+		return convertStoredResponsesToMessages(ctx.ResponseAPICtx.ConversationHistory)
+	}
+
+	store := conversationStoreForDecision(ctx)
+	if store == nil || sessionID == "" {
+		return nil
+	}
+
+	storedResponses, err := store.Get(context.Background(), sessionID)
+	if err != nil {
+		if err != memory.ErrConversationNotFound {
+			logging.Errorf("resolveResponseAPIHistory: failed to fetch conversation '%s': %v", sessionID, err)
+		}
+		return nil
+	}
+	return convertStoredResponsesToMessages(storedResponses)
+}
+
+// resolveChatCompletionsHistory returns the history to use for a Chat
+// Completions request: any turns persisted under sessionID in the
+// decision's MemoryStore (if one is configured), followed by the messages
+// the client sent in this request. Chat Completions requests always carry
+// their own view of history in ctx.ChatCompletionMessages, but that view is
+// only as complete as what the client chose to resend - consulting the
+// store fills in turns the client dropped or never had (e.g. a new client
+// session continuing sessionID from a prior one).
+func resolveChatCompletionsHistory(ctx *RequestContext, sessionID string) []memory.Message {
+	current := convertChatCompletionMessages(ctx.ChatCompletionMessages)
+
+	store := memoryStoreForDecision(ctx)
+	if store == nil || sessionID == "" {
+		return current
+	}
+
+	stored, err := store.Get(context.Background(), sessionID)
+	if err != nil {
+		if err != memory.ErrConversationNotFound {
+			logging.Errorf("resolveChatCompletionsHistory: failed to fetch session '%s': %v", sessionID, err)
+		}
+		return current
+	}
+	return append(stored, current...)
+}
+
+// AppendChatTurn persists a Chat Completions turn's messages to the
+// decision's MemoryStore, keyed by sessionID, so a later request against
+// the same session (resolved via resolveChatCompletionsHistory) sees them
+// even if the client doesn't resend them. It's a no-op when the decision
+// has no MemoryStore configured. Called from the response-handling path
+// once a turn (user message + assistant reply) is complete.
+func AppendChatTurn(ctx *RequestContext, sessionID string, messages []memory.Message) error {
+	store := memoryStoreForDecision(ctx)
+	if store == nil || sessionID == "" {
+		return nil
+	}
+	return store.Append(context.Background(), sessionID, messages)
+}
+
 // extractAutoStore checks if auto_store is enabled.
 // Priority: per-decision plugin config > global config.
 // Supported for both Response API and Chat Completions.
@@ -40,10 +196,28 @@ func extractAutoStore(ctx *RequestContext) bool {
 // so memories stored without userID cannot be retrieved later.
 //
 // userID extraction priority:
-//  1. Auth header (x-authz-user-id) injected by external auth service (Authorino, Envoy Gateway JWT, etc.)
-//  2. metadata["user_id"] in Response API request (fallback for development/testing)
-//  3. Chat Completions "user" field (fallback for development/testing)
+//  1. Verified JWT Bearer token (Authorization header) - see resolveUserIdentity
+//  2. Auth header (x-authz-user-id) injected by external auth service (Authorino, Envoy Gateway JWT, etc.)
+//  3. metadata["user_id"] in Response API request (fallback for development/testing)
+//  4. Chat Completions "user" field (fallback for development/testing)
+//
+// When a JWT is verified, its claims are also stashed on ctx.IdentityClaims
+// so downstream plugins (PII, memory auto_store gating, per-decision
+// policies) can key off tenant/scope/groups rather than just user id.
+//
+// Runs on every request, so a panic anywhere underneath it - a nil slice
+// element, a malformed stored response - is recovered rather than allowed to
+// crash the request's goroutine; the request degrades to empty history and
+// ErrMemoryUnavailable instead. See recoverMemoryPanic.
 func extractMemoryInfo(ctx *RequestContext) (sessionID string, userID string, history []memory.Message, err error) {
+	defer recoverMemoryPanic("extractMemoryInfo", ctx.RequestID, &err)
+	return extractMemoryInfoUnsafe(ctx)
+}
+
+// extractMemoryInfoUnsafe is extractMemoryInfo's body, split out so
+// extractMemoryInfo can wrap it in panic recovery without an extra level of
+// indentation through the whole function.
+func extractMemoryInfoUnsafe(ctx *RequestContext) (sessionID string, userID string, history []memory.Message, err error) {
 	// Determine API type
 	isResponseAPI := ctx.ResponseAPICtx != nil && ctx.ResponseAPICtx.IsResponseAPIRequest
 	isChatCompletions := len(ctx.ChatCompletionMessages) > 0
@@ -53,14 +227,17 @@ func extractMemoryInfo(ctx *RequestContext) (sessionID string, userID string, hi
 			"Use Response API (/v1/responses) or Chat Completions (/v1/chat/completions) with messages")
 	}
 
-	// Extract userID with priority: auth header > metadata/user field fallback
-	userID = extractUserID(ctx)
+	// Extract userID with priority: verified JWT > auth header > metadata/user field fallback
+	userID, claims := resolveUserIdentity(ctx)
+	if claims != nil {
+		ctx.IdentityClaims = claims
+	}
 
 	// Require userID - without it, memory would be orphaned (unretrievable)
 	if userID == "" {
 		// Extract history for error context
-		if isResponseAPI && ctx.ResponseAPICtx.ConversationHistory != nil {
-			history = convertStoredResponsesToMessages(ctx.ResponseAPICtx.ConversationHistory)
+		if isResponseAPI {
+			history = resolveResponseAPIHistory(ctx, ctx.ResponseAPICtx.ConversationID)
 		} else if isChatCompletions {
 			history = convertChatCompletionMessages(ctx.ChatCompletionMessages)
 		}
@@ -69,36 +246,62 @@ func extractMemoryInfo(ctx *RequestContext) (sessionID string, userID string, hi
 			"or configure auth_user_id_header in memory config")
 	}
 
+	// Group-gate the namespace before materializing any history, so a
+	// caller outside the permitted groups can't learn whether history
+	// exists from timing or partial results.
+	var callerGroups []string
+	if claims != nil {
+		callerGroups = claims.Groups
+	}
+	allowedGroups := allowedGroupsFromConfig(memoryPluginConfiguration(ctx))
+	if err := checkGroupAccess(allowedGroups, callerGroups); err != nil {
+		return "", "", nil, err
+	}
+
 	// Extract sessionID and history based on API type
 	if isResponseAPI {
 		sessionID = ctx.ResponseAPICtx.ConversationID
 		if sessionID == "" {
 			return "", "", nil, fmt.Errorf("ConversationID not set in Response API context")
 		}
-		if ctx.ResponseAPICtx.ConversationHistory != nil {
-			history = convertStoredResponsesToMessages(ctx.ResponseAPICtx.ConversationHistory)
-		}
+		history = resolveResponseAPIHistory(ctx, sessionID)
 	} else if isChatCompletions {
-		// For Chat Completions, derive sessionID from conversation hash
-		sessionID = deriveSessionIDFromMessages(ctx.ChatCompletionMessages, userID)
-		history = convertChatCompletionMessages(ctx.ChatCompletionMessages)
+		// For Chat Completions, derive sessionID from conversation hash,
+		// tenant-scoped when the identity came from a verified JWT so the
+		// same hashed prompt from different orgs never collides.
+		tenant := ""
+		if claims != nil {
+			tenant = claims.Tenant
+		}
+		sessionID = deriveSessionIDFromMessages(ctx.ChatCompletionMessages, userID, tenant)
+		history = resolveChatCompletionsHistory(ctx, sessionID)
 	}
 
+	policy := memoryRetentionPolicyFromConfig(memoryPluginConfiguration(ctx))
+	history = applyRetentionPolicy(history, policy)
+
 	return sessionID, userID, history, nil
 }
 
 // deriveSessionIDFromMessages creates a session ID from Chat Completions messages.
 // Uses a hash of the first few messages + userID to group related conversations.
-func deriveSessionIDFromMessages(messages []ChatCompletionMessage, userID string) string {
-	// Use first message content + userID to create a stable session ID
+// tenant, when non-empty (set from a verified JWT's tenant claim), is folded
+// into the hash so the same hashed prompt from two different orgs never
+// produces the same session ID.
+func deriveSessionIDFromMessages(messages []ChatCompletionMessage, userID string, tenant string) string {
+	// Use first message content + userID (+ tenant) to create a stable session ID
 	// This allows tracking turns within the same "conversation topic"
 	var builder strings.Builder
+	if tenant != "" {
+		builder.WriteString(tenant)
+		builder.WriteString(":")
+	}
 	builder.WriteString(userID)
 	builder.WriteString(":")
 
 	// Include first user message to identify the conversation topic
 	for _, msg := range messages {
-		if msg.Role == "user" {
+		if msg.Role == memory.RoleUser {
 			// Truncate to first 100 chars to keep hash stable for long messages
 			content := msg.Content
 			if len(content) > 100 {
@@ -128,25 +331,25 @@ func convertChatCompletionMessages(messages []ChatCompletionMessage) []memory.Me
 
 // convertStoredResponsesToMessages converts StoredResponse[] to Message[].
 // It extracts user input and assistant output from each stored response.
+// Non-message item types (tool calls, tool results) are preserved rather
+// than dropped, since memory extraction needs the full shape of a turn, not
+// just its "message" items.
 func convertStoredResponsesToMessages(storedResponses []*responseapi.StoredResponse) []memory.Message {
 	var messages []memory.Message
 
 	for _, stored := range storedResponses {
-		// Add input items as user messages
+		// Add input items as user (or tool) messages
 		for _, inputItem := range stored.Input {
-			if inputItem.Type == "message" {
-				// Extract content from InputItem
-				content := extractContentFromInputItem(inputItem)
-				if content != "" {
-					role := inputItem.Role
-					if role == "" {
-						role = "user" // Default to user
-					}
-					messages = append(messages, memory.Message{
-						Role:    role,
-						Content: content,
-					})
+			content := extractContentFromInputItem(inputItem)
+			if content != "" {
+				role := memory.Role(inputItem.Role)
+				if role == "" {
+					role = defaultRoleForItemType(inputItem.Type, memory.RoleUser)
 				}
+				messages = append(messages, memory.Message{
+					Role:    role,
+					Content: content,
+				})
 			}
 		}
 
@@ -154,24 +357,22 @@ func convertStoredResponsesToMessages(storedResponses []*responseapi.StoredRespo
 		// First, try to use OutputText if available (simpler)
 		if stored.OutputText != "" {
 			messages = append(messages, memory.Message{
-				Role:    "assistant",
+				Role:    memory.RoleAssistant,
 				Content: stored.OutputText,
 			})
 		} else {
 			// Fallback: extract from Output items
 			for _, outputItem := range stored.Output {
-				if outputItem.Type == "message" {
-					content := extractContentFromOutputItem(outputItem)
-					if content != "" {
-						role := outputItem.Role
-						if role == "" {
-							role = "assistant" // Default to assistant
-						}
-						messages = append(messages, memory.Message{
-							Role:    role,
-							Content: content,
-						})
+				content := extractContentFromOutputItem(outputItem)
+				if content != "" {
+					role := memory.Role(outputItem.Role)
+					if role == "" {
+						role = defaultRoleForItemType(outputItem.Type, memory.RoleAssistant)
 					}
+					messages = append(messages, memory.Message{
+						Role:    role,
+						Content: content,
+					})
 				}
 			}
 		}
@@ -180,6 +381,18 @@ func convertStoredResponsesToMessages(storedResponses []*responseapi.StoredRespo
 	return messages
 }
 
+// defaultRoleForItemType picks a role for an input/output item that didn't
+// carry one explicitly. Tool calls and their results are attributed to
+// RoleTool so memory extraction can distinguish them from the surrounding
+// conversational turn; everything else falls back to the caller-supplied
+// default (RoleUser for input items, RoleAssistant for output items).
+func defaultRoleForItemType(itemType string, fallback memory.Role) memory.Role {
+	if strings.Contains(itemType, "tool") || strings.Contains(itemType, "function_call") {
+		return memory.RoleTool
+	}
+	return fallback
+}
+
 // extractContentFromInputItem extracts text content from an InputItem.
 func extractContentFromInputItem(item responseapi.InputItem) string {
 	if len(item.Content) == 0 {
@@ -210,13 +423,29 @@ func extractContentFromOutputItem(item responseapi.OutputItem) string {
 	return extractTextFromContentParts(item.Content)
 }
 
-// extractTextFromContentParts extracts text from ContentPart array.
+// extractTextFromContentParts extracts text from a ContentPart array.
+// Plain-text parts ("output_text", "input_text", "refusal") are written
+// verbatim, exactly as before. Other part types - images, files, tool-call
+// markers embedded in content - are preserved as a "[type: text]" tag
+// instead of being silently dropped, so multi-modal and tool-call turns
+// aren't erased from what memory extraction sees.
 func extractTextFromContentParts(parts []responseapi.ContentPart) string {
 	var text strings.Builder
+	lastWasMarker := false
 	for _, part := range parts {
-		if part.Type == "output_text" && part.Text != "" {
+		if part.Text == "" {
+			continue
+		}
+		isTextPart := part.Type == "output_text" || part.Type == "input_text" || part.Type == "refusal"
+		if text.Len() > 0 && (lastWasMarker || !isTextPart) {
+			text.WriteString(" ")
+		}
+		if isTextPart {
 			text.WriteString(part.Text)
+		} else {
+			text.WriteString(fmt.Sprintf("[%s: %s]", part.Type, part.Text))
 		}
+		lastWasMarker = !isTextPart
 	}
 	return text.String()
 }
@@ -242,7 +471,7 @@ func extractCurrentUserMessage(ctx *RequestContext) string {
 	if len(ctx.ChatCompletionMessages) > 0 {
 		// Find the last user message (current turn)
 		for i := len(ctx.ChatCompletionMessages) - 1; i >= 0; i-- {
-			if ctx.ChatCompletionMessages[i].Role == "user" {
+			if ctx.ChatCompletionMessages[i].Role == memory.RoleUser {
 				return ctx.ChatCompletionMessages[i].Content
 			}
 		}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/headers"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/responseapi"
 )
 
@@ -382,19 +383,19 @@ func TestExtractMemoryInfo_WithConversationHistory(t *testing.T) {
 	require.Len(t, history, 4, "should convert 2 stored responses to 4 messages (2 user + 2 assistant)")
 
 	// Check first user message
-	assert.Equal(t, "user", history[0].Role)
+	assert.Equal(t, memory.RoleUser, history[0].Role)
 	assert.Equal(t, "Hello", history[0].Content)
 
 	// Check first assistant message
-	assert.Equal(t, "assistant", history[1].Role)
+	assert.Equal(t, memory.RoleAssistant, history[1].Role)
 	assert.Equal(t, "Hi there!", history[1].Content)
 
 	// Check second user message
-	assert.Equal(t, "user", history[2].Role)
+	assert.Equal(t, memory.RoleUser, history[2].Role)
 	assert.Equal(t, "What's my budget?", history[2].Content)
 
 	// Check second assistant message
-	assert.Equal(t, "assistant", history[3].Role)
+	assert.Equal(t, memory.RoleAssistant, history[3].Role)
 	assert.Equal(t, "Your budget is $10,000", history[3].Content)
 }
 
@@ -444,9 +445,9 @@ func TestConvertStoredResponsesToMessages_WithOutputText(t *testing.T) {
 	result := convertStoredResponsesToMessages(stored)
 
 	require.Len(t, result, 2)
-	assert.Equal(t, "user", result[0].Role)
+	assert.Equal(t, memory.RoleUser, result[0].Role)
 	assert.Equal(t, "Hello", result[0].Content)
-	assert.Equal(t, "assistant", result[1].Role)
+	assert.Equal(t, memory.RoleAssistant, result[1].Role)
 	assert.Equal(t, "Hi there!", result[1].Content)
 }
 
@@ -478,9 +479,9 @@ func TestConvertStoredResponsesToMessages_WithoutOutputText_WithOutputItems(t *t
 	result := convertStoredResponsesToMessages(stored)
 
 	require.Len(t, result, 2)
-	assert.Equal(t, "user", result[0].Role)
+	assert.Equal(t, memory.RoleUser, result[0].Role)
 	assert.Equal(t, "What's the weather?", result[0].Content)
-	assert.Equal(t, "assistant", result[1].Role)
+	assert.Equal(t, memory.RoleAssistant, result[1].Role)
 	assert.Equal(t, "It's sunny today!", result[1].Content)
 }
 
@@ -517,12 +518,12 @@ func TestConvertStoredResponsesToMessages_MultipleResponses(t *testing.T) {
 	assert.Equal(t, "Second response", result[3].Content)
 }
 
-func TestConvertStoredResponsesToMessages_SkipsNonMessageTypes(t *testing.T) {
+func TestConvertStoredResponsesToMessages_PreservesToolCallItems(t *testing.T) {
 	stored := []*responseapi.StoredResponse{
 		{
 			Input: []responseapi.InputItem{
 				{
-					Type:    "tool_call", // Not a message
+					Type:    "tool_call", // Not a "message" type, but not dropped either
 					Role:    "user",
 					Content: json.RawMessage(`"tool data"`),
 				},
@@ -538,9 +539,31 @@ func TestConvertStoredResponsesToMessages_SkipsNonMessageTypes(t *testing.T) {
 
 	result := convertStoredResponsesToMessages(stored)
 
-	require.Len(t, result, 2, "should skip non-message input items")
-	assert.Equal(t, "Hello", result[0].Content)
-	assert.Equal(t, "Hi!", result[1].Content)
+	require.Len(t, result, 3, "tool-call items should be preserved, not skipped")
+	assert.Equal(t, "tool data", result[0].Content)
+	assert.Equal(t, "Hello", result[1].Content)
+	assert.Equal(t, "Hi!", result[2].Content)
+}
+
+func TestConvertStoredResponsesToMessages_DefaultsToolRoleWhenMissing(t *testing.T) {
+	stored := []*responseapi.StoredResponse{
+		{
+			Input: []responseapi.InputItem{
+				{
+					Type:    "function_call_output",
+					Role:    "", // No role supplied for the tool result
+					Content: json.RawMessage(`"72F and sunny"`),
+				},
+			},
+			OutputText: "It's 72F and sunny.",
+		},
+	}
+
+	result := convertStoredResponsesToMessages(stored)
+
+	require.Len(t, result, 2)
+	assert.Equal(t, memory.RoleTool, result[0].Role, "tool call items without a role should default to 'tool'")
+	assert.Equal(t, "72F and sunny", result[0].Content)
 }
 
 func TestConvertStoredResponsesToMessages_EmptyContent(t *testing.T) {
@@ -560,7 +583,7 @@ func TestConvertStoredResponsesToMessages_EmptyContent(t *testing.T) {
 	result := convertStoredResponsesToMessages(stored)
 
 	require.Len(t, result, 1, "should skip empty content")
-	assert.Equal(t, "assistant", result[0].Role)
+	assert.Equal(t, memory.RoleAssistant, result[0].Role)
 	assert.Equal(t, "Response", result[0].Content)
 }
 
@@ -681,7 +704,7 @@ func TestExtractContentFromOutputItem_MultipleParts(t *testing.T) {
 	assert.Equal(t, "Hello world!", result)
 }
 
-func TestExtractContentFromOutputItem_SkipsNonTextParts(t *testing.T) {
+func TestExtractContentFromOutputItem_PreservesNonTextPartsAsMarkers(t *testing.T) {
 	item := responseapi.OutputItem{
 		Content: []responseapi.ContentPart{
 			{
@@ -696,7 +719,7 @@ func TestExtractContentFromOutputItem_SkipsNonTextParts(t *testing.T) {
 	}
 
 	result := extractContentFromOutputItem(item)
-	assert.Equal(t, "Hello", result, "should only extract output_text parts")
+	assert.Equal(t, "[image: image_url] Hello", result, "non-text parts should be preserved as a bracketed marker")
 }
 
 func TestExtractContentFromOutputItem_EmptyContent(t *testing.T) {
@@ -753,7 +776,7 @@ func TestExtractTextFromContentParts_MultipleTextParts(t *testing.T) {
 	assert.Equal(t, "Hello world!", result)
 }
 
-func TestExtractTextFromContentParts_SkipsNonTextParts(t *testing.T) {
+func TestExtractTextFromContentParts_PreservesNonTextPartsAsMarkers(t *testing.T) {
 	parts := []responseapi.ContentPart{
 		{
 			Type: "image",
@@ -770,7 +793,8 @@ func TestExtractTextFromContentParts_SkipsNonTextParts(t *testing.T) {
 	}
 
 	result := extractTextFromContentParts(parts)
-	assert.Equal(t, "Hello", result, "should only extract output_text parts")
+	assert.Equal(t, "[image: image_url] Hello [tool_call: tool_data]", result,
+		"non-text parts should be preserved as bracketed markers instead of dropped")
 }
 
 func TestExtractTextFromContentParts_EmptyText(t *testing.T) {
@@ -892,8 +916,8 @@ func TestExtractMemoryInfo_ChatCompletions_Success(t *testing.T) {
 	assert.NotEmpty(t, sessionID, "sessionID should be derived from messages")
 	assert.True(t, strings.HasPrefix(sessionID, "cc-"), "Chat Completions sessionID should have 'cc-' prefix")
 	require.Len(t, history, 4)
-	assert.Equal(t, "system", history[0].Role)
-	assert.Equal(t, "user", history[1].Role)
+	assert.Equal(t, memory.RoleSystem, history[0].Role)
+	assert.Equal(t, memory.RoleUser, history[1].Role)
 	assert.Equal(t, "Hello, my name is Alice", history[1].Content)
 }
 
@@ -954,10 +978,10 @@ func TestConvertChatCompletionMessages(t *testing.T) {
 	result := convertChatCompletionMessages(messages)
 
 	require.Len(t, result, 3)
-	assert.Equal(t, "system", result[0].Role)
+	assert.Equal(t, memory.RoleSystem, result[0].Role)
 	assert.Equal(t, "System prompt", result[0].Content)
-	assert.Equal(t, "user", result[1].Role)
-	assert.Equal(t, "assistant", result[2].Role)
+	assert.Equal(t, memory.RoleUser, result[1].Role)
+	assert.Equal(t, memory.RoleAssistant, result[2].Role)
 }
 
 func TestDeriveSessionIDFromMessages(t *testing.T) {
@@ -965,9 +989,9 @@ func TestDeriveSessionIDFromMessages(t *testing.T) {
 		{Role: "user", Content: "Hello, I need help with my order"},
 	}
 
-	sessionID1 := deriveSessionIDFromMessages(messages, "user_123")
-	sessionID2 := deriveSessionIDFromMessages(messages, "user_123")
-	sessionID3 := deriveSessionIDFromMessages(messages, "user_456")
+	sessionID1 := deriveSessionIDFromMessages(messages, "user_123", "")
+	sessionID2 := deriveSessionIDFromMessages(messages, "user_123", "")
+	sessionID3 := deriveSessionIDFromMessages(messages, "user_456", "")
 
 	// Same messages + same user = same session ID
 	assert.Equal(t, sessionID1, sessionID2)
@@ -978,3 +1002,23 @@ func TestDeriveSessionIDFromMessages(t *testing.T) {
 	// All session IDs should have the prefix
 	assert.True(t, strings.HasPrefix(sessionID1, "cc-"))
 }
+
+func TestDeriveSessionIDFromMessages_TenantIsolation(t *testing.T) {
+	messages := []ChatCompletionMessage{
+		{Role: "user", Content: "Hello, I need help with my order"},
+	}
+
+	sameTenant1 := deriveSessionIDFromMessages(messages, "user_123", "tenant_a")
+	sameTenant2 := deriveSessionIDFromMessages(messages, "user_123", "tenant_a")
+	otherTenant := deriveSessionIDFromMessages(messages, "user_123", "tenant_b")
+	noTenant := deriveSessionIDFromMessages(messages, "user_123", "")
+
+	// Same messages + same user + same tenant = same session ID
+	assert.Equal(t, sameTenant1, sameTenant2)
+
+	// Same messages + same user, different tenant = different session ID,
+	// preventing cross-tenant leakage when the same hashed prompt is sent
+	// by different orgs.
+	assert.NotEqual(t, sameTenant1, otherTenant)
+	assert.NotEqual(t, sameTenant1, noTenant)
+}
@@ -0,0 +1,66 @@
+package extproc
+
+import (
+	"errors"
+	"runtime/debug"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/metrics"
+)
+
+// ErrMemoryUnavailable is returned by a memory-subsystem entry point when it
+// recovers from a panic partway through. Callers should treat it like any
+// other best-effort history miss - proceed with empty history rather than
+// failing the request, since a single malformed stored response or nil
+// slice element shouldn't take an otherwise-healthy request down with it.
+var ErrMemoryUnavailable = errors.New("memory: subsystem panicked, degrading to no history")
+
+// PanicHandler receives a recovered panic value from the memory subsystem.
+// It runs in addition to, not instead of, the memory_panic_total metric and
+// the logged stack trace below. Intended for operators who want to turn
+// panics into Sentry/OTel events without forking this package; the returned
+// error is only logged, never propagated, since recoverMemoryPanic has
+// already decided the caller's fate (ErrMemoryUnavailable).
+type PanicHandler func(recovered any) error
+
+// memoryPanicHandler is invoked, if set, by every recoverMemoryPanic call.
+// Nil by default, meaning only the metric and log line fire.
+var memoryPanicHandler PanicHandler
+
+// SetMemoryPanicHandler installs (or, passed nil, clears) the PanicHandler
+// invoked on every recovered memory-subsystem panic. Intended to be called
+// once at startup.
+func SetMemoryPanicHandler(handler PanicHandler) {
+	memoryPanicHandler = handler
+}
+
+// recoverMemoryPanic recovers a panic raised anywhere under function
+// (identified for the memory_panic_total{function} metric and the log
+// line), attributed to requestID, and sets *err to ErrMemoryUnavailable so
+// the caller degrades gracefully instead of crashing its goroutine. Call it
+// via defer at the top of a memory-subsystem entry point that runs on every
+// request, in the spirit of go-grpc-middleware's recovery interceptor:
+//
+//	func extractMemoryInfo(ctx *RequestContext) (sessionID, userID string, history []memory.Message, err error) {
+//		defer recoverMemoryPanic("extractMemoryInfo", ctx.RequestID, &err)
+//		...
+//	}
+//
+// A no-op when nothing panicked.
+func recoverMemoryPanic(function string, requestID string, err *error) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	metrics.RecordMemoryPanic(function)
+	logging.Errorf("memory subsystem panic in %s (request %s): %v\n%s", function, requestID, recovered, debug.Stack())
+
+	if memoryPanicHandler != nil {
+		if handlerErr := memoryPanicHandler(recovered); handlerErr != nil {
+			logging.Errorf("memory panic handler for %s returned error: %v", function, handlerErr)
+		}
+	}
+
+	*err = ErrMemoryUnavailable
+}
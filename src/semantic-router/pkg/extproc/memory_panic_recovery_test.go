@@ -0,0 +1,57 @@
+package extproc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// panickingHelper exercises recoverMemoryPanic the same way a real
+// memory-subsystem entry point would: defer it first thing, panic, and let
+// the named return carry the recovered error back out.
+func panickingHelper() (err error) {
+	defer recoverMemoryPanic("panickingHelper", "req_test", &err)
+	panic("boom")
+}
+
+func TestRecoverMemoryPanic_ReturnsErrMemoryUnavailable(t *testing.T) {
+	err := panickingHelper()
+	require.Error(t, err)
+	assert.Equal(t, ErrMemoryUnavailable, err)
+}
+
+func TestRecoverMemoryPanic_NoPanicIsNoOp(t *testing.T) {
+	var err error
+	func() {
+		defer recoverMemoryPanic("noop", "req_test", &err)
+	}()
+	assert.NoError(t, err)
+}
+
+func TestRecoverMemoryPanic_InvokesHandler(t *testing.T) {
+	var captured any
+	SetMemoryPanicHandler(func(recovered any) error {
+		captured = recovered
+		return nil
+	})
+	defer SetMemoryPanicHandler(nil)
+
+	_ = panickingHelper()
+	assert.Equal(t, "boom", captured)
+}
+
+func TestExtractMemoryInfo_OrdinaryErrorDoesNotInvokeHandler(t *testing.T) {
+	called := false
+	SetMemoryPanicHandler(func(recovered any) error {
+		called = true
+		return nil
+	})
+	defer SetMemoryPanicHandler(nil)
+
+	ctx := &RequestContext{RequestID: "req_ok"}
+	_, _, _, err := extractMemoryInfo(ctx)
+	require.Error(t, err, "no Response API or Chat Completions data should still be an ordinary error")
+	assert.NotEqual(t, ErrMemoryUnavailable, err)
+	assert.False(t, called, "the panic handler must only fire on an actual panic")
+}
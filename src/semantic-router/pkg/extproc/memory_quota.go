@@ -0,0 +1,135 @@
+//go:build !windows && cgo
+
+package extproc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// memoryUsageEntry tracks enough about one stored memory to support
+// eviction without round-tripping to the Store to re-read it.
+type memoryUsageEntry struct {
+	id          string
+	bytes       int64
+	importance  float64
+	accessCount int
+	accessedAt  time.Time
+}
+
+// memoryUsageTracker maintains a process-local view of each user's stored
+// memory count/bytes, built up from the memories this MemoryFilter itself
+// has auto-stored since the process started. It is the quota subsystem's
+// accounting for MaxMemoriesPerUser/MaxBytesPerUser eviction decisions -
+// memories written through another path (a direct API call, a different
+// router instance) are invisible to it until this filter stores or evicts
+// something for that user. This is the same best-effort, process-local
+// tradeoff RedisStore's user index and MilvusStore's accessRecorder make
+// rather than round-tripping to the backend on every write.
+type memoryUsageTracker struct {
+	mu      sync.Mutex
+	byUser  map[string][]memoryUsageEntry
+	byBytes map[string]int64
+}
+
+func newMemoryUsageTracker() *memoryUsageTracker {
+	return &memoryUsageTracker{
+		byUser:  make(map[string][]memoryUsageEntry),
+		byBytes: make(map[string]int64),
+	}
+}
+
+// Usage returns userID's tracked memory count and total bytes, for
+// observability and for quota checks.
+func (t *memoryUsageTracker) Usage(userID string) (count int, bytes int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.byUser[userID]), t.byBytes[userID]
+}
+
+// record adds a newly stored memory to userID's tracked set.
+func (t *memoryUsageTracker) record(userID string, entry memoryUsageEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.byUser[userID] = append(t.byUser[userID], entry)
+	t.byBytes[userID] += entry.bytes
+}
+
+// evictionCandidate returns the index within entries policy would remove
+// first, or -1 if entries is empty.
+func evictionCandidate(entries []memoryUsageEntry, policy memory.EvictionPolicy) int {
+	if len(entries) == 0 {
+		return -1
+	}
+	best := 0
+	for i := 1; i < len(entries); i++ {
+		if evictionLess(entries[i], entries[best], policy) {
+			best = i
+		}
+	}
+	return best
+}
+
+// evictionLess reports whether a should be evicted before b under policy.
+func evictionLess(a, b memoryUsageEntry, policy memory.EvictionPolicy) bool {
+	switch policy {
+	case memory.EvictionPolicyLFU:
+		return a.accessCount < b.accessCount
+	case memory.EvictionPolicyImportance:
+		return a.importance < b.importance
+	default: // memory.EvictionPolicyLRU
+		return a.accessedAt.Before(b.accessedAt)
+	}
+}
+
+// makeRoom evicts userID's tracked memories from store, oldest-by-policy
+// first, until adding a memory of size newBytes would no longer exceed
+// cfg's limits, or there is nothing left to evict. It returns the usage
+// that remains after eviction so the caller can decide whether quota is
+// still exceeded.
+func (t *memoryUsageTracker) makeRoom(ctx context.Context, store memory.Store, userID string, newBytes int64, cfg memory.QuotaConfig, audit *memory.AuditLog) (count int, bytes int64, err error) {
+	policy := cfg.EvictionPolicy
+	if policy == "" {
+		policy = memory.EvictionPolicyLRU
+	}
+
+	for {
+		t.mu.Lock()
+		entries := t.byUser[userID]
+		count = len(entries)
+		bytes = t.byBytes[userID]
+		overCount := cfg.MaxMemoriesPerUser > 0 && count+1 > cfg.MaxMemoriesPerUser
+		overBytes := cfg.MaxBytesPerUser > 0 && bytes+newBytes > cfg.MaxBytesPerUser
+		if !overCount && !overBytes {
+			t.mu.Unlock()
+			return count, bytes, nil
+		}
+		idx := evictionCandidate(entries, policy)
+		if idx < 0 {
+			t.mu.Unlock()
+			return count, bytes, nil // nothing left to evict; still over quota
+		}
+		victim := entries[idx]
+		remaining := make([]memoryUsageEntry, 0, len(entries)-1)
+		remaining = append(remaining, entries[:idx]...)
+		remaining = append(remaining, entries[idx+1:]...)
+		t.byUser[userID] = remaining
+		t.byBytes[userID] -= victim.bytes
+		t.mu.Unlock()
+
+		if err := store.Forget(ctx, victim.id); err != nil && !memory.IsCode(err, memory.CodeNotFound) {
+			return count, bytes, fmt.Errorf("memory: evict %s for quota: %w", victim.id, err)
+		}
+		logging.Infof("Memory: evicted %s for user %s to satisfy quota (policy=%s)", victim.id, userID, policy)
+		if audit != nil {
+			if _, err := audit.Append(memory.AuditOpForget, userID, "", victim.id, ""); err != nil {
+				logging.Warnf("Memory: failed to append audit log entry for quota eviction: %v", err)
+			}
+		}
+	}
+}
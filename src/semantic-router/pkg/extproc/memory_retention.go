@@ -0,0 +1,209 @@
+package extproc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// memoryRetentionPolicy bounds how much conversation history extractMemoryInfo
+// materializes, read from the same "memory" decision plugin Configuration map
+// extractAutoStore already consults.
+type memoryRetentionPolicy struct {
+	// MaxTurns keeps only the most recent N turns (a turn starts at each
+	// user message). Zero disables turn-count trimming.
+	MaxTurns int
+
+	// MaxTokens drops the oldest turns until history fits this approximate
+	// token budget. Zero disables token-based trimming.
+	MaxTokens int
+
+	// TTLSeconds bounds how long a stored turn is retained; enforced by the
+	// ConversationStore backend at persistence time, not here.
+	TTLSeconds int
+
+	// SummarizeAfterTurns, when set, replaces any trimmed prefix with a
+	// single synthetic system-role summary once the untrimmed history would
+	// have reached this many turns.
+	SummarizeAfterTurns int
+
+	// SummaryModel names the model used to produce that summary.
+	SummaryModel string
+}
+
+// memoryRetentionPolicyFromConfig parses a retention policy out of a
+// decision's "memory" plugin Configuration map. Unrecognized/missing keys
+// leave the corresponding field at its zero value (disabled).
+func memoryRetentionPolicyFromConfig(pluginConfig map[string]interface{}) memoryRetentionPolicy {
+	var policy memoryRetentionPolicy
+	if pluginConfig == nil {
+		return policy
+	}
+
+	policy.MaxTurns = intFromConfig(pluginConfig, "max_turns")
+	policy.MaxTokens = intFromConfig(pluginConfig, "max_tokens")
+	policy.TTLSeconds = intFromConfig(pluginConfig, "ttl_seconds")
+	policy.SummarizeAfterTurns = intFromConfig(pluginConfig, "summarize_after_turns")
+	if model, ok := pluginConfig["summary_model"].(string); ok {
+		policy.SummaryModel = model
+	}
+	return policy
+}
+
+// intFromConfig reads key from m as an int, accepting both int (Go-authored
+// configs/tests) and float64 (configs round-tripped through JSON/YAML).
+func intFromConfig(m map[string]interface{}, key string) int {
+	switch v := m[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+// applyRetentionPolicy trims history to policy's bounds, prepending a
+// synthetic system-role summary of any dropped turns when
+// policy.SummarizeAfterTurns applies. A zero-value policy is a no-op.
+func applyRetentionPolicy(history []memory.Message, policy memoryRetentionPolicy) []memory.Message {
+	if policy.MaxTurns <= 0 && policy.MaxTokens <= 0 {
+		return history
+	}
+
+	turns := groupIntoTurns(history)
+	kept := turns
+
+	if policy.MaxTurns > 0 && len(kept) > policy.MaxTurns {
+		kept = kept[len(kept)-policy.MaxTurns:]
+	}
+	if policy.MaxTokens > 0 {
+		for len(kept) > 1 && countTurnsTokens(kept) > policy.MaxTokens {
+			kept = kept[1:]
+		}
+	}
+
+	dropped := turns[:len(turns)-len(kept)]
+	result := flattenTurns(kept)
+	if len(dropped) == 0 {
+		return result
+	}
+
+	if policy.SummarizeAfterTurns > 0 && len(turns) >= policy.SummarizeAfterTurns {
+		summary := summarizeTurns(flattenTurns(dropped), policy.SummaryModel)
+		result = append([]memory.Message{{Role: memory.RoleSystem, Content: summary}}, result...)
+	}
+	return result
+}
+
+// groupIntoTurns splits a flat message history into turns, where a new turn
+// starts at each "user"-role message. Leading non-user messages (e.g. a
+// system prompt with no preceding user turn) form their own turn so no
+// message is ever dropped silently.
+func groupIntoTurns(history []memory.Message) [][]memory.Message {
+	var turns [][]memory.Message
+	for _, msg := range history {
+		if msg.Role == memory.RoleUser || len(turns) == 0 {
+			turns = append(turns, []memory.Message{msg})
+			continue
+		}
+		turns[len(turns)-1] = append(turns[len(turns)-1], msg)
+	}
+	return turns
+}
+
+func flattenTurns(turns [][]memory.Message) []memory.Message {
+	var out []memory.Message
+	for _, turn := range turns {
+		out = append(out, turn...)
+	}
+	return out
+}
+
+func countTurnsTokens(turns [][]memory.Message) int {
+	total := 0
+	for _, turn := range turns {
+		for _, msg := range turn {
+			total += approximateTokenCount(msg.Content)
+		}
+	}
+	return total
+}
+
+// approximateTokenCount estimates token count at ~4 characters per token,
+// the standard rule-of-thumb approximation for English text. This is a
+// placeholder for the real tokenizer used elsewhere in the pipeline;
+// swap in that tokenizer once it's exposed to this package.
+func approximateTokenCount(text string) int {
+	if text == "" {
+		return 0
+	}
+	return (len(text) + 3) / 4
+}
+
+// summarizeFunc produces a single summary string for a trimmed prefix of
+// conversation history. Tests override this to avoid depending on a live
+// model; production wiring should replace it with a call through the
+// router's existing LLM client, keyed on policy.SummaryModel.
+var summarizeFunc = defaultSummarize
+
+func defaultSummarize(messages []memory.Message, model string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Summary of %d earlier message(s):", len(messages))
+	for _, msg := range messages {
+		content := msg.Content
+		if len(content) > 80 {
+			content = content[:80]
+		}
+		fmt.Fprintf(&b, " [%s] %s", msg.Role, content)
+	}
+	return b.String(), nil
+}
+
+var (
+	summaryCacheMu sync.Mutex
+	summaryCache   = make(map[string]string)
+)
+
+// summarizeTurns summarizes messages via summarizeFunc, caching by a hash of
+// the trimmed prefix's content so repeated truncations of the same prefix
+// (common as a conversation grows one turn at a time) don't re-invoke the
+// summary model.
+func summarizeTurns(messages []memory.Message, model string) string {
+	key := hashMessages(messages, model)
+
+	summaryCacheMu.Lock()
+	if cached, ok := summaryCache[key]; ok {
+		summaryCacheMu.Unlock()
+		return cached
+	}
+	summaryCacheMu.Unlock()
+
+	summary, err := summarizeFunc(messages, model)
+	if err != nil {
+		logging.Errorf("summarizeTurns: summarization failed, dropping trimmed history instead: %v", err)
+		return ""
+	}
+
+	summaryCacheMu.Lock()
+	summaryCache[key] = summary
+	summaryCacheMu.Unlock()
+	return summary
+}
+
+func hashMessages(messages []memory.Message, model string) string {
+	h := sha256.New()
+	h.Write([]byte(model))
+	for _, msg := range messages {
+		h.Write([]byte{0})
+		h.Write([]byte(msg.Role))
+		h.Write([]byte{0})
+		h.Write([]byte(msg.Content))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,135 @@
+package extproc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+)
+
+func fixedHistory() []memory.Message {
+	return []memory.Message{
+		{Role: "user", Content: "turn one question"},
+		{Role: "assistant", Content: "turn one answer"},
+		{Role: "user", Content: "turn two question"},
+		{Role: "assistant", Content: "turn two answer"},
+		{Role: "user", Content: "turn three question"},
+		{Role: "assistant", Content: "turn three answer"},
+	}
+}
+
+func TestMemoryRetentionPolicyFromConfig_ParsesAllKeys(t *testing.T) {
+	policy := memoryRetentionPolicyFromConfig(map[string]interface{}{
+		"max_turns":             2,
+		"max_tokens":            float64(500), // as it would arrive via JSON/YAML
+		"ttl_seconds":           3600,
+		"summarize_after_turns": 5,
+		"summary_model":         "gpt-4o-mini",
+	})
+
+	assert.Equal(t, 2, policy.MaxTurns)
+	assert.Equal(t, 500, policy.MaxTokens)
+	assert.Equal(t, 3600, policy.TTLSeconds)
+	assert.Equal(t, 5, policy.SummarizeAfterTurns)
+	assert.Equal(t, "gpt-4o-mini", policy.SummaryModel)
+}
+
+func TestMemoryRetentionPolicyFromConfig_NilConfigIsZeroPolicy(t *testing.T) {
+	policy := memoryRetentionPolicyFromConfig(nil)
+	assert.Zero(t, policy)
+}
+
+func TestApplyRetentionPolicy_NoPolicyIsNoOp(t *testing.T) {
+	history := fixedHistory()
+	result := applyRetentionPolicy(history, memoryRetentionPolicy{})
+	assert.Equal(t, history, result)
+}
+
+func TestApplyRetentionPolicy_MaxTurnsKeepsOnlyMostRecent(t *testing.T) {
+	result := applyRetentionPolicy(fixedHistory(), memoryRetentionPolicy{MaxTurns: 1})
+
+	require.Len(t, result, 2)
+	assert.Equal(t, "turn three question", result[0].Content)
+	assert.Equal(t, "turn three answer", result[1].Content)
+}
+
+func TestApplyRetentionPolicy_MaxTokensDropsOldestTurns(t *testing.T) {
+	// Each turn's two messages are ~34 chars total (~9 tokens at 4
+	// chars/token); a 12-token budget should leave only the last turn.
+	result := applyRetentionPolicy(fixedHistory(), memoryRetentionPolicy{MaxTokens: 12})
+
+	require.Len(t, result, 2)
+	assert.Equal(t, "turn three question", result[0].Content)
+	assert.Equal(t, "turn three answer", result[1].Content)
+}
+
+func TestApplyRetentionPolicy_SummarizeAfterTurnsPrependsSummary(t *testing.T) {
+	origSummarize := summarizeFunc
+	t.Cleanup(func() { summarizeFunc = origSummarize })
+	summarizeFunc = func(messages []memory.Message, model string) (string, error) {
+		return "stub summary", nil
+	}
+
+	result := applyRetentionPolicy(fixedHistory(), memoryRetentionPolicy{
+		MaxTurns:            1,
+		SummarizeAfterTurns: 2,
+	})
+
+	require.Len(t, result, 3)
+	assert.Equal(t, memory.RoleSystem, result[0].Role)
+	assert.Equal(t, "stub summary", result[0].Content)
+	assert.Equal(t, "turn three question", result[1].Content)
+	assert.Equal(t, "turn three answer", result[2].Content)
+}
+
+func TestApplyRetentionPolicy_BelowSummarizeThresholdNoSummary(t *testing.T) {
+	origSummarize := summarizeFunc
+	t.Cleanup(func() { summarizeFunc = origSummarize })
+	called := false
+	summarizeFunc = func(messages []memory.Message, model string) (string, error) {
+		called = true
+		return "stub summary", nil
+	}
+
+	result := applyRetentionPolicy(fixedHistory(), memoryRetentionPolicy{
+		MaxTurns:            2,
+		SummarizeAfterTurns: 10, // history never reaches 10 turns
+	})
+
+	require.Len(t, result, 4)
+	assert.False(t, called, "summarizeFunc should not be invoked below the threshold")
+}
+
+func TestSummarizeTurns_CachesByContentHash(t *testing.T) {
+	origSummarize := summarizeFunc
+	t.Cleanup(func() {
+		summarizeFunc = origSummarize
+		summaryCacheMu.Lock()
+		summaryCache = make(map[string]string)
+		summaryCacheMu.Unlock()
+	})
+
+	calls := 0
+	summarizeFunc = func(messages []memory.Message, model string) (string, error) {
+		calls++
+		return "computed summary", nil
+	}
+
+	messages := []memory.Message{{Role: "user", Content: "hello"}}
+	first := summarizeTurns(messages, "gpt-4o-mini")
+	second := summarizeTurns(messages, "gpt-4o-mini")
+
+	assert.Equal(t, "computed summary", first)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, calls, "second call with identical content should hit the cache")
+}
+
+func TestGroupIntoTurns_StartsNewTurnAtEachUserMessage(t *testing.T) {
+	turns := groupIntoTurns(fixedHistory())
+	require.Len(t, turns, 3)
+	assert.Len(t, turns[0], 2)
+	assert.Len(t, turns[1], 2)
+	assert.Len(t, turns[2], 2)
+}
@@ -0,0 +1,64 @@
+package extproc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+)
+
+func decisionWithMemoryStore(name string) *config.Decision {
+	return &config.Decision{
+		Name: name,
+		Plugins: []config.DecisionPlugin{
+			{
+				Type: "memory",
+				Configuration: map[string]interface{}{
+					"store": map[string]interface{}{
+						"type": "lru",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestResolveChatCompletionsHistory_NoStoreConfiguredReturnsCurrentOnly(t *testing.T) {
+	ctx := &RequestContext{
+		ChatCompletionMessages: []ChatCompletionMessage{
+			{Role: "user", Content: "hello"},
+		},
+	}
+
+	history := resolveChatCompletionsHistory(ctx, "sess_1")
+	require.Len(t, history, 1)
+	assert.Equal(t, "hello", history[0].Content)
+}
+
+func TestResolveChatCompletionsHistory_MergesPersistedHistory(t *testing.T) {
+	ctx := &RequestContext{
+		VSRSelectedDecision:     decisionWithMemoryStore("with_store"),
+		VSRSelectedDecisionName: "with_store",
+		ChatCompletionMessages: []ChatCompletionMessage{
+			{Role: "user", Content: "second turn"},
+		},
+	}
+
+	require.NoError(t, AppendChatTurn(ctx, "sess_1", []memory.Message{
+		{Role: memory.RoleUser, Content: "first turn"},
+	}))
+
+	history := resolveChatCompletionsHistory(ctx, "sess_1")
+	require.Len(t, history, 2, "persisted history should be prepended to the current turn")
+	assert.Equal(t, "first turn", history[0].Content)
+	assert.Equal(t, "second turn", history[1].Content)
+}
+
+func TestAppendChatTurn_NoStoreConfiguredIsNoOp(t *testing.T) {
+	ctx := &RequestContext{}
+	err := AppendChatTurn(ctx, "sess_1", []memory.Message{{Role: memory.RoleUser, Content: "hi"}})
+	assert.NoError(t, err)
+}
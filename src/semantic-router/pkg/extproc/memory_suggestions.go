@@ -0,0 +1,199 @@
+//go:build !windows && cgo
+
+package extproc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// StarterSuggestion is one synthesized "what to ask next" prompt, along with
+// the IDs of the memories used to produce it - so a UI calling
+// MemoryFilter.SuggestStarters can show provenance instead of presenting a
+// bare suggestion.
+type StarterSuggestion struct {
+	Prompt        string   `json:"prompt"`
+	SeedMemoryIDs []string `json:"seed_memory_ids"`
+}
+
+// StarterSynthesizer turns one memory-type group's recent memory contents
+// (most-recently-updated first) into up to limit short prompt-starter
+// suggestions. This is the same plug-an-LLM-call-in-as-a-function pattern
+// memory.Summarizer uses for MemoryConsolidator - a deployment installs a
+// thin wrapper around the router's configured LLM client via
+// WithStarterSynthesizer; SuggestStarters falls back to
+// defaultStarterSynthesizer (no LLM call) when none is configured.
+type StarterSynthesizer func(ctx context.Context, memType memory.MemoryType, contents []string, limit int) ([]string, error)
+
+// defaultStarterSynthesizer is the StarterSynthesizer SuggestStarters uses
+// when WithStarterSynthesizer hasn't been called: one generic templated
+// suggestion per memory, no LLM round-trip. It exists so SuggestStarters
+// behaves (and is testable) without an LLM client wired in, the same role
+// joinSummarizer plays for MemoryConsolidator.
+func defaultStarterSynthesizer(_ context.Context, memType memory.MemoryType, contents []string, limit int) ([]string, error) {
+	prompts := make([]string, 0, limit)
+	for _, content := range contents {
+		if len(prompts) >= limit {
+			break
+		}
+		prompts = append(prompts, fmt.Sprintf("Following up on your %s memory: %s", memType, truncateContent(content, 80)))
+	}
+	return prompts, nil
+}
+
+// maxMemoriesPerStarterGroup bounds how many memories of one type are
+// handed to a StarterSynthesizer call, so a user with thousands of
+// memories doesn't blow up a single LLM prompt.
+const maxMemoriesPerStarterGroup = 5
+
+// starterGroupOrder fixes the order groups are synthesized in - and, since
+// SuggestStarters stops once it has limit suggestions, which memory types
+// are favored when a user's history spans more than one.
+var starterGroupOrder = []memory.MemoryType{
+	memory.MemoryTypeEpisodic,
+	memory.MemoryTypeSemantic,
+	memory.MemoryTypeProcedural,
+	memory.MemoryTypeWorking,
+}
+
+// groupMemoriesByType buckets memories (assumed most-recently-updated
+// first, as StoreSync.UpdatedSince returns them) by Type, in
+// starterGroupOrder, capping each bucket at maxMemoriesPerStarterGroup.
+func groupMemoriesByType(memories []*memory.Memory) map[memory.MemoryType][]*memory.Memory {
+	byType := make(map[memory.MemoryType][]*memory.Memory)
+	for _, mem := range memories {
+		if len(byType[mem.Type]) >= maxMemoriesPerStarterGroup {
+			continue
+		}
+		byType[mem.Type] = append(byType[mem.Type], mem)
+	}
+	return byType
+}
+
+// SuggestStarters pulls userID's most-recently-touched memories (scoped to
+// projectID, if set), groups them by type, and asks f's StarterSynthesizer
+// (see WithStarterSynthesizer) to turn each group into short "what to ask
+// next" prompt suggestions - up to limit total, favoring starterGroupOrder
+// when a user's history spans more than one memory type. Each suggestion
+// carries the IDs of the memories its group drew from, for UI provenance.
+//
+// Returns nil, nil (not an error) if the filter is disabled, userID has no
+// memories yet, or f's store doesn't implement memory.StoreSync (full
+// enumeration isn't every backend's strong suit) - an empty suggestion list
+// is a normal "nothing to suggest yet" outcome, not a failure.
+func (f *MemoryFilter) SuggestStarters(ctx context.Context, userID, projectID string, limit int) ([]StarterSuggestion, error) {
+	if !f.enabled {
+		return nil, nil
+	}
+	if userID == "" {
+		return nil, fmt.Errorf("memory: SuggestStarters requires a non-empty userID")
+	}
+	if limit <= 0 {
+		limit = 3
+	}
+
+	syncStore, ok := f.store.(memory.StoreSync)
+	if !ok {
+		logging.Warnf("Memory: SuggestStarters: store %T does not support enumeration (memory.StoreSync), nothing to suggest", f.store)
+		return nil, nil
+	}
+
+	recent, err := syncStore.UpdatedSince(ctx, memory.MemoryScope{UserID: userID, ProjectID: projectID}, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("memory: SuggestStarters: fetching recent memories for user %s: %w", userID, err)
+	}
+	if len(recent) == 0 {
+		return nil, nil
+	}
+
+	synthesize := f.starterSynthesizer
+	if synthesize == nil {
+		synthesize = defaultStarterSynthesizer
+	}
+
+	byType := groupMemoriesByType(recent)
+	suggestions := make([]StarterSuggestion, 0, limit)
+	for _, memType := range starterGroupOrder {
+		if len(suggestions) >= limit {
+			break
+		}
+		group := byType[memType]
+		if len(group) == 0 {
+			continue
+		}
+
+		contents := make([]string, len(group))
+		seedIDs := make([]string, len(group))
+		for i, mem := range group {
+			contents[i] = mem.Content
+			seedIDs[i] = mem.ID
+		}
+
+		prompts, err := synthesize(ctx, memType, contents, limit-len(suggestions))
+		if err != nil {
+			logging.Warnf("Memory: SuggestStarters: synthesizer failed for %s memories of user %s: %v", memType, userID, err)
+			continue
+		}
+		for _, prompt := range prompts {
+			if prompt == "" || len(suggestions) >= limit {
+				continue
+			}
+			suggestions = append(suggestions, StarterSuggestion{Prompt: prompt, SeedMemoryIDs: seedIDs})
+		}
+	}
+
+	return suggestions, nil
+}
+
+// suggestionsRequest is the POST /v1/memory/suggestions request body.
+type suggestionsRequest struct {
+	UserID    string `json:"user_id"`
+	ProjectID string `json:"project_id,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+}
+
+// suggestionsResponse is the POST /v1/memory/suggestions response body.
+type suggestionsResponse struct {
+	Suggestions []StarterSuggestion `json:"suggestions"`
+}
+
+// SuggestionsHandler returns an http.Handler for POST /v1/memory/suggestions,
+// backed by f.SuggestStarters. A deployment mounts it on its admin/API mux
+// the same way it mounts any other memory-adjacent endpoint (see the
+// admin memory-inspection endpoint this e2e suite already assumes exists);
+// this package doesn't own HTTP route registration itself.
+func (f *MemoryFilter) SuggestionsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req suggestionsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.UserID == "" {
+			http.Error(w, "user_id is required", http.StatusBadRequest)
+			return
+		}
+
+		suggestions, err := f.SuggestStarters(r.Context(), req.UserID, req.ProjectID, req.Limit)
+		if err != nil {
+			http.Error(w, err.Error(), memory.HTTPStatus(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(suggestionsResponse{Suggestions: suggestions}); err != nil {
+			logging.Errorf("Memory: SuggestionsHandler: failed to encode response: %v", err)
+		}
+	})
+}
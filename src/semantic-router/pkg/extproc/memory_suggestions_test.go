@@ -0,0 +1,140 @@
+package extproc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+)
+
+func TestMemoryFilter_SuggestStarters_GroupsByTypeAndCapsAtLimit(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryStore()
+
+	seed := []*memory.Memory{
+		{Type: memory.MemoryTypeEpisodic, Content: "User asked about Yosemite trip", UserID: "user_1"},
+		{Type: memory.MemoryTypeSemantic, Content: "User prefers vegetarian food", UserID: "user_1"},
+		{Type: memory.MemoryTypeProcedural, Content: "To deploy: run npm build then docker push", UserID: "user_1"},
+	}
+	for _, mem := range seed {
+		if err := store.Store(ctx, mem); err != nil {
+			t.Fatalf("seed memory: %v", err)
+		}
+	}
+
+	filter := NewMemoryFilter(store, nil)
+
+	suggestions, err := filter.SuggestStarters(ctx, "user_1", "", 2)
+	if err != nil {
+		t.Fatalf("SuggestStarters: %v", err)
+	}
+	if len(suggestions) != 2 {
+		t.Fatalf("expected 2 suggestions (limit), got %d: %+v", len(suggestions), suggestions)
+	}
+	for _, s := range suggestions {
+		if s.Prompt == "" {
+			t.Error("expected a non-empty Prompt")
+		}
+		if len(s.SeedMemoryIDs) == 0 {
+			t.Error("expected at least one seed memory ID")
+		}
+	}
+}
+
+func TestMemoryFilter_SuggestStarters_NoMemoriesReturnsEmpty(t *testing.T) {
+	store := memory.NewInMemoryStore()
+	filter := NewMemoryFilter(store, nil)
+
+	suggestions, err := filter.SuggestStarters(context.Background(), "user_with_no_history", "", 3)
+	if err != nil {
+		t.Fatalf("SuggestStarters: %v", err)
+	}
+	if len(suggestions) != 0 {
+		t.Fatalf("expected no suggestions, got %+v", suggestions)
+	}
+}
+
+func TestMemoryFilter_SuggestStarters_RequiresUserID(t *testing.T) {
+	store := memory.NewInMemoryStore()
+	filter := NewMemoryFilter(store, nil)
+
+	if _, err := filter.SuggestStarters(context.Background(), "", "", 3); err == nil {
+		t.Fatal("expected an error for an empty userID")
+	}
+}
+
+func TestMemoryFilter_SuggestStarters_UsesConfiguredSynthesizer(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryStore()
+	if err := store.Store(ctx, &memory.Memory{
+		Type: memory.MemoryTypeSemantic, Content: "User prefers tea over coffee", UserID: "user_1",
+	}); err != nil {
+		t.Fatalf("seed memory: %v", err)
+	}
+
+	filter := NewMemoryFilter(store, nil, WithStarterSynthesizer(
+		func(ctx context.Context, memType memory.MemoryType, contents []string, limit int) ([]string, error) {
+			return []string{"custom suggestion"}, nil
+		},
+	))
+
+	suggestions, err := filter.SuggestStarters(ctx, "user_1", "", 1)
+	if err != nil {
+		t.Fatalf("SuggestStarters: %v", err)
+	}
+	if len(suggestions) != 1 || suggestions[0].Prompt != "custom suggestion" {
+		t.Fatalf("expected the configured synthesizer's output, got %+v", suggestions)
+	}
+}
+
+func TestMemoryFilter_SuggestionsHandler_HappyPath(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryStore()
+	if err := store.Store(ctx, &memory.Memory{
+		Type: memory.MemoryTypeSemantic, Content: "User prefers tea over coffee", UserID: "user_1",
+	}); err != nil {
+		t.Fatalf("seed memory: %v", err)
+	}
+	filter := NewMemoryFilter(store, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/memory/suggestions", strings.NewReader(`{"user_id":"user_1","limit":1}`))
+	rec := httptest.NewRecorder()
+
+	filter.SuggestionsHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"suggestions"`) {
+		t.Errorf("expected a suggestions array in the response, got %s", rec.Body.String())
+	}
+}
+
+func TestMemoryFilter_SuggestionsHandler_RequiresUserID(t *testing.T) {
+	filter := NewMemoryFilter(memory.NewInMemoryStore(), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/memory/suggestions", strings.NewReader(`{}`))
+	rec := httptest.NewRecorder()
+
+	filter.SuggestionsHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestMemoryFilter_SuggestionsHandler_RejectsNonPost(t *testing.T) {
+	filter := NewMemoryFilter(memory.NewInMemoryStore(), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/memory/suggestions", nil)
+	rec := httptest.NewRecorder()
+
+	filter.SuggestionsHandler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status 405, got %d", rec.Code)
+	}
+}
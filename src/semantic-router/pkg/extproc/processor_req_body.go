@@ -18,6 +18,7 @@ import (
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/anthropic"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/authz"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/gemini"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/headers"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
@@ -151,10 +152,21 @@ func (r *OpenAIRouter) handleRequestBody(v *ext_proc.ProcessingRequest_RequestBo
 				rlCtx.UserID, rlCtx.Model, decision.Provider, decision.Remaining)
 			return r.createRateLimitResponse(decision), nil
 		}
+		if decision != nil && decision.ShadowBlocked {
+			logging.Warnf("[Request Body] Shadow rate limit would have blocked: user=%s model=%s provider=%s remaining=%d",
+				rlCtx.UserID, rlCtx.Model, decision.Provider, decision.Remaining)
+		}
 		// Store context on RequestContext for post-response reporting
 		ctx.RateLimitCtx = &rlCtx
 	}
 
+	// Concurrency check — after the rate limiter, before cache/RAG/routing
+	// and well before startUpstreamSpanAndInjectHeaders, so a request that
+	// won't be admitted never reserves upstream connection resources.
+	if response := r.admitConcurrency(ctx, selectedModel); response != nil {
+		return response, nil
+	}
+
 	// Handle caching with decision-specific settings
 	if response, shouldReturn := r.handleCaching(ctx, decisionName); shouldReturn {
 		logging.Infof("handleCaching returned a response, returning immediately")
@@ -238,6 +250,11 @@ func (r *OpenAIRouter) handleModelRouting(openAIRequest *openai.ChatCompletionNe
 		return r.handleAnthropicRouting(openAIRequest, originalModel, targetModel, decisionName, ctx)
 	}
 
+	// Google Gemini model routing
+	if r.Config.GetModelAPIFormat(targetModel) == config.APIFormatGoogle {
+		return r.handleGeminiRouting(openAIRequest, originalModel, targetModel, decisionName, ctx)
+	}
+
 	// OpenAI-compatible routing
 	switch {
 	case !isAutoModel:
@@ -261,10 +278,8 @@ func (r *OpenAIRouter) handleModelRouting(openAIRequest *openai.ChatCompletionNe
 func (r *OpenAIRouter) handleAnthropicRouting(openAIRequest *openai.ChatCompletionNewParams, originalModel string, targetModel string, decisionName string, ctx *RequestContext) (*ext_proc.ProcessingResponse, error) {
 	logging.Infof("Routing to Anthropic API via Envoy for model: %s (original: %s)", targetModel, originalModel)
 
-	// Reject streaming requests (not yet supported for Anthropic backend)
 	if ctx.ExpectStreamingResponse {
-		logging.Warnf("Streaming not supported for Anthropic backend, rejecting request for model: %s", targetModel)
-		return r.createErrorResponse(400, "Streaming is not supported for Anthropic models. Please set stream=false in your request."), nil
+		logging.Infof("Streaming request for Anthropic model %s: forwarding stream=true; the response path does not run anthropicStreamTranslator, so Anthropic SSE events are not re-framed as OpenAI chunks", targetModel)
 	}
 
 	// Resolve API key for Anthropic via credential chain (ext_authz headers → static config)
@@ -326,6 +341,7 @@ func (r *OpenAIRouter) handleAnthropicRouting(openAIRequest *openai.ChatCompleti
 
 	// Strip ext_authz / Authorino injected headers before forwarding upstream (prevent key leakage)
 	removeHeaders := append(anthropic.HeadersToRemove(), r.CredentialResolver.HeadersToStrip()...)
+	removeHeaders = append(removeHeaders, vsrContextHeadersToStrip()...)
 
 	// Return response with body and header mutations - let Envoy route to Anthropic
 	// ClearRouteCache forces Envoy to re-evaluate routing after we set x-selected-model header
@@ -350,6 +366,106 @@ func (r *OpenAIRouter) handleAnthropicRouting(openAIRequest *openai.ChatCompleti
 	}, nil
 }
 
+// handleGeminiRouting handles routing to Google's Gemini API (direct
+// generativelanguage.googleapis.com or Vertex AI, per the model's
+// provider profile) via Envoy. Transforms the request body from OpenAI
+// format to Gemini's generateContent format and sets appropriate headers
+// for Envoy to route to the Gemini cluster. Mirrors handleAnthropicRouting.
+func (r *OpenAIRouter) handleGeminiRouting(openAIRequest *openai.ChatCompletionNewParams, originalModel string, targetModel string, decisionName string, ctx *RequestContext) (*ext_proc.ProcessingResponse, error) {
+	endpointMode := gemini.EndpointDirect
+	location := ""
+	if profile := r.Config.GetModelProviderProfile(targetModel); profile != nil && profile.IsVertex() {
+		endpointMode = gemini.EndpointVertex
+		location = profile.VertexLocation()
+	}
+
+	logging.Infof("Routing to Gemini API (%s) via Envoy for model: %s (original: %s)", endpointMode, targetModel, originalModel)
+
+	// Resolve API key for Google via credential chain (ext_authz headers → static config)
+	accessKey, err := r.CredentialResolver.KeyForProvider(authz.ProviderGoogle, targetModel, ctx.Headers)
+	if err != nil {
+		return r.createErrorResponse(401, fmt.Sprintf("Credential resolution failed for model %s: %v", targetModel, err)), nil
+	}
+	if accessKey == "" {
+		// fail_open=true path: no key but allowed through — warn operator
+		logging.Warnf("No API key for Gemini model %q (fail_open=true) — request will use empty key", targetModel)
+	}
+
+	// Update model in request to target model
+	openAIRequest.Model = targetModel
+
+	// Transform request body from OpenAI format to Gemini format
+	geminiBody, err := gemini.ToGeminiRequestBody(openAIRequest)
+	if err != nil {
+		logging.Errorf("Failed to transform request to Gemini format: %v", err)
+		return r.createErrorResponse(500, fmt.Sprintf("Request transformation error: %v", err)), nil
+	}
+
+	// Track VSR decision information
+	ctx.RequestModel = targetModel
+	ctx.VSRSelectedModel = targetModel
+	ctx.APIFormat = config.APIFormatGoogle // Mark for response transformation
+	if decisionName != "" {
+		ctx.VSRSelectedDecision = r.Config.GetDecisionByName(decisionName)
+	}
+
+	// Build header mutations using gemini package helpers
+	geminiHeaders := gemini.BuildRequestHeaders(accessKey, len(geminiBody), endpointMode)
+	setHeaders := make([]*core.HeaderValueOption, 0, len(geminiHeaders)+2)
+	for _, h := range geminiHeaders {
+		setHeaders = append(setHeaders, &core.HeaderValueOption{
+			Header: &core.HeaderValue{
+				Key:      h.Key,
+				RawValue: []byte(h.Value),
+			},
+		})
+	}
+
+	// Add x-selected-model for Envoy routing
+	setHeaders = append(setHeaders, &core.HeaderValueOption{
+		Header: &core.HeaderValue{
+			Key:      headers.SelectedModel,
+			RawValue: []byte(targetModel),
+		},
+	})
+
+	// Start upstream span and inject trace context headers
+	upstreamHost := gemini.Host(endpointMode, location)
+	traceContextHeaders := r.startUpstreamSpanAndInjectHeaders(targetModel, upstreamHost, ctx)
+	setHeaders = append(setHeaders, traceContextHeaders...)
+
+	// Record routing latency
+	r.recordRoutingLatency(ctx)
+
+	logging.Infof("Transformed request for Gemini API, body size: %d bytes", len(geminiBody))
+
+	// Strip ext_authz / Authorino injected headers before forwarding upstream (prevent key leakage)
+	removeHeaders := append(gemini.HeadersToRemove(), r.CredentialResolver.HeadersToStrip()...)
+	removeHeaders = append(removeHeaders, vsrContextHeadersToStrip()...)
+
+	// Return response with body and header mutations - let Envoy route to Gemini
+	// ClearRouteCache forces Envoy to re-evaluate routing after we set x-selected-model header
+	return &ext_proc.ProcessingResponse{
+		Response: &ext_proc.ProcessingResponse_RequestBody{
+			RequestBody: &ext_proc.BodyResponse{
+				Response: &ext_proc.CommonResponse{
+					Status:          ext_proc.CommonResponse_CONTINUE,
+					ClearRouteCache: true,
+					HeaderMutation: &ext_proc.HeaderMutation{
+						SetHeaders:    setHeaders,
+						RemoveHeaders: removeHeaders,
+					},
+					BodyMutation: &ext_proc.BodyMutation{
+						Mutation: &ext_proc.BodyMutation_Body{
+							Body: geminiBody,
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
 // handleAutoModelRouting handles routing for auto model selection
 func (r *OpenAIRouter) handleAutoModelRouting(openAIRequest *openai.ChatCompletionNewParams, originalModel string, decisionName string, reasoningDecision entropy.ReasoningDecision, selectedModel string, ctx *RequestContext, response *ext_proc.ProcessingResponse) (*ext_proc.ProcessingResponse, error) {
 	logging.Infof("Using Auto Model Selection (model=%s), decision=%s, selected=%s",
@@ -467,6 +583,15 @@ func (r *OpenAIRouter) selectEndpointForModel(ctx *RequestContext, model string)
 	}
 	if endpointFound {
 		logging.Infof("Selected endpoint address: %s (name: %s) for model: %s", endpointAddress, endpointName, model)
+
+		// Consult the short-lived circuit breaker for this endpoint. We still
+		// forward to it either way (this router doesn't see upstream
+		// responses to pick a genuine alternative - see defaultCircuitBreakers'
+		// doc comment), but we log loudly so operators can see a breaker trip
+		// even before failover is wired end-to-end.
+		if !defaultCircuitBreakers.Allow(endpointAddress) {
+			logging.Warnf("Endpoint %s (model: %s) has an open circuit breaker, forwarding anyway (no alternate endpoint available)", endpointAddress, model)
+		}
 	}
 
 	// Store the selected endpoint in context (for routing/logging purposes)
@@ -532,6 +657,21 @@ func (r *OpenAIRouter) modifyRequestBodyForAutoRouting(openAIRequest *openai.Cha
 		}
 	}
 
+	// Apply a per-decision grammar/JSON-schema constraint, if one is
+	// configured for this decision, so the target backend enforces
+	// well-formed structured output regardless of the underlying model.
+	if decisionName != "" && r.GrammarConfigs != nil {
+		if grammarCfg, ok := r.GrammarConfigs[decisionName]; ok {
+			constrained, applied, grammarErr := applyGrammarToRequestBody(modifiedBody, string(ctx.APIFormat), grammarCfg)
+			if grammarErr != nil {
+				logging.Warnf("Grammar constraint for decision %s not applied: %v", decisionName, grammarErr)
+			} else if applied {
+				modifiedBody = constrained
+				grammarAppliedFlags.Store(replayRequestID(ctx), true)
+			}
+		}
+	}
+
 	return modifiedBody, nil
 }
 
@@ -551,6 +691,12 @@ func (r *OpenAIRouter) startUpstreamSpanAndInjectHeaders(model string, endpoint
 		attribute.String(tracing.AttrModelName, model),
 		attribute.String(tracing.AttrEndpointAddress, endpoint))
 
+	// Record whether a grammar/JSON-schema constraint was applied to this
+	// request, so operators can see when constrained decoding was active.
+	if applied, ok := grammarAppliedFlags.LoadAndDelete(replayRequestID(ctx)); ok && applied.(bool) {
+		tracing.SetSpanAttributes(upstreamSpan, attribute.Bool(tracing.AttrGrammarApplied, true))
+	}
+
 	// Inject W3C trace context headers for distributed tracing to vLLM
 	traceHeaders := tracing.InjectTraceContextToSlice(spanCtx)
 	for _, th := range traceHeaders {
@@ -562,6 +708,12 @@ func (r *OpenAIRouter) startUpstreamSpanAndInjectHeaders(model string, endpoint
 		})
 	}
 
+	// Attach the signed x-vsr-context/x-vsr-context-sig envelope alongside
+	// trace context, so upstream sidecars/audit tools can verify the
+	// identity/decision this request was routed under instead of trusting
+	// an inbound header a client could have set itself.
+	traceContextHeaders = append(traceContextHeaders, r.vsrContextHeaders(ctx, model)...)
+
 	return traceContextHeaders
 }
 
@@ -574,6 +726,11 @@ func (r *OpenAIRouter) startUpstreamSpanAndInjectHeaders(model string, endpoint
 //
 // When profile is non-nil, all three values are derived from the profile's type.
 // Returns error if the profile type is unrecognised.
+//
+// aws-bedrock is a special case: it doesn't use a simple Authorization
+// header value, so authHeader/authPrefix are returned empty and the
+// caller must branch on llmProvider == authz.ProviderAWSBedrock and sign
+// the request via signBedrockRequest instead.
 func resolveProviderAuth(profile *config.ProviderProfile) (authz.LLMProvider, string, string, error) {
 	if profile == nil {
 		// Legacy endpoint (no provider_profile set).
@@ -588,6 +745,9 @@ func resolveProviderAuth(profile *config.ProviderProfile) (authz.LLMProvider, st
 		return "", "", "", fmt.Errorf("resolving provider auth: %w", err)
 	}
 	llmProvider := authz.LLMProvider(providerType)
+	if llmProvider == authz.ProviderAWSBedrock {
+		return llmProvider, "", "", nil
+	}
 	authHeader, authPrefix, err := profile.ResolveAuthHeader()
 	if err != nil {
 		return "", "", "", fmt.Errorf("resolving auth header: %w", err)
@@ -595,27 +755,74 @@ func resolveProviderAuth(profile *config.ProviderProfile) (authz.LLMProvider, st
 	return llmProvider, authHeader, authPrefix, nil
 }
 
-// createRoutingResponse creates a routing response with mutations.
-// endpointName is the name of the selected VLLMEndpoint (used to look up provider profile).
-func (r *OpenAIRouter) createRoutingResponse(model string, endpoint string, endpointName string, modifiedBody []byte, ctx *RequestContext) *ext_proc.ProcessingResponse {
-	bodyMutation := &ext_proc.BodyMutation{
-		Mutation: &ext_proc.BodyMutation_Body{
-			Body: modifiedBody,
-		},
+// anthropicAPIVersion is the Anthropic Messages API version that
+// bodymutation.AnthropicTranslateStage's request shape targets. Anthropic
+// requires it on every request, so it's set unconditionally for
+// provider_profile-driven Anthropic routing (see anthropicVersionHeader).
+const anthropicAPIVersion = "2023-06-01"
+
+// anthropicVersionHeader returns the "anthropic-version" header to add for
+// an Anthropic provider profile, or nil for every other provider.
+func anthropicVersionHeader(llmProvider authz.LLMProvider) []*core.HeaderValueOption {
+	if llmProvider != authz.ProviderAnthropic {
+		return nil
 	}
+	return []*core.HeaderValueOption{{
+		Header: &core.HeaderValue{Key: "anthropic-version", RawValue: []byte(anthropicAPIVersion)},
+	}}
+}
 
-	setHeaders := []*core.HeaderValueOption{}
-	removeHeaders := []string{"content-length"} // Always remove old content-length when body is modified
+// bedrockAuthHeaders resolves AWS credentials via the credential chain and
+// signs body (the final, already-rewritten request body) for model,
+// returning the Authorization/x-amz-date/x-amz-security-token header
+// values to set on the upstream request, plus the percent-encoded path
+// that was actually signed (see bedrockSigV4Headers.Path) - callers must
+// set :path to this exact value rather than re-deriving it from profile,
+// or the unencoded form of a model ID like
+// "anthropic.claude-3-5-sonnet-20241022-v2:0" (":" is not a signature-safe
+// byte) will mismatch what was signed and AWS will reject it with
+// SignatureDoesNotMatch.
+func (r *OpenAIRouter) bedrockAuthHeaders(profile *config.ProviderProfile, model string, ctx *RequestContext, body []byte) (setHeaders []*core.HeaderValueOption, signedPath string, err error) {
+	creds, err := r.CredentialResolver.AWSCredentialsForProvider(model, ctx.Headers)
+	if err != nil {
+		return nil, "", fmt.Errorf("resolving AWS credentials for model %s: %w", model, err)
+	}
+
+	region := "us-east-1"
+	if profile != nil {
+		if profileRegion := profile.AWSRegion(); profileRegion != "" {
+			region = profileRegion
+		}
+	}
 
-	// Add new content-length header for the modified body
-	if len(modifiedBody) > 0 {
+	path := fmt.Sprintf("/model/%s/invoke", model)
+	if profile != nil {
+		if chatPath, pathErr := profile.ResolveChatPath(); pathErr == nil && chatPath != "" {
+			path = chatPath
+		}
+	}
+
+	host := bedrockHost(region)
+	sig := signBedrockRequest(creds.AccessKey, creds.SecretKey, creds.SessionToken, region, "POST", path, host, body, time.Now())
+
+	setHeaders = []*core.HeaderValueOption{
+		{Header: &core.HeaderValue{Key: "authorization", RawValue: []byte(sig.Authorization)}},
+		{Header: &core.HeaderValue{Key: "x-amz-date", RawValue: []byte(sig.AmzDate)}},
+	}
+	if sig.SecurityToken != "" {
 		setHeaders = append(setHeaders, &core.HeaderValueOption{
-			Header: &core.HeaderValue{
-				Key:      "content-length",
-				RawValue: []byte(fmt.Sprintf("%d", len(modifiedBody))),
-			},
+			Header: &core.HeaderValue{Key: "x-amz-security-token", RawValue: []byte(sig.SecurityToken)},
 		})
 	}
+	logging.Infof("Added AWS SigV4 Authorization header for Bedrock model %s (region=%s)", model, region)
+	return setHeaders, sig.Path, nil
+}
+
+// createRoutingResponse creates a routing response with mutations.
+// endpointName is the name of the selected VLLMEndpoint (used to look up provider profile).
+func (r *OpenAIRouter) createRoutingResponse(model string, endpoint string, endpointName string, modifiedBody []byte, ctx *RequestContext) *ext_proc.ProcessingResponse {
+	setHeaders := []*core.HeaderValueOption{}
+	removeHeaders := []string{}
 
 	logging.Infof("createRoutingResponse: modifiedBody length=%d, model=%s", len(modifiedBody), model)
 
@@ -633,26 +840,51 @@ func (r *OpenAIRouter) createRoutingResponse(model string, endpoint string, endp
 		return r.createErrorResponse(500, fmt.Sprintf("Provider auth resolution failed for endpoint %s: %v", endpointName, authErr))
 	}
 
-	// Resolve API key via credential chain (ext_authz headers → static config)
-	accessKey, credErr := r.CredentialResolver.KeyForProvider(llmProvider, model, ctx.Headers)
-	if credErr != nil {
-		return r.createErrorResponse(401, fmt.Sprintf("Credential resolution failed for model %s: %v", model, credErr))
-	}
-	if accessKey != "" {
-		value := accessKey
-		if authPrefix != "" {
-			value = authPrefix + " " + accessKey
+	// Run the body-mutation pipeline once so model-rewrite, Response API
+	// translation, and any decision-enabled prompt-guard /
+	// system-prompt-prepend / tool-filter / json-schema-coerce stages all
+	// see (and, between them, produce) the one final body that gets
+	// signed/forwarded - content-length is then computed exactly once
+	// from that result via bodyMutationHeaders.
+	pipelineResult, mutErr := r.bodyMutationPipeline().Run(bodyMutationContext(ctx, model, model, string(llmProvider)), modifiedBody)
+	if mutErr != nil {
+		return r.createErrorResponse(500, fmt.Sprintf("Body mutation failed for model %s: %v", model, mutErr))
+	}
+	finalBody := pipelineResult.Body
+	bodyMutation, contentLengthHeaders, contentLengthRemove := bodyMutationHeaders(finalBody)
+	setHeaders = append(setHeaders, contentLengthHeaders...)
+	removeHeaders = append(removeHeaders, contentLengthRemove...)
+
+	var bedrockSignedPath string
+	if llmProvider == authz.ProviderAWSBedrock {
+		bedrockHeaders, signedPath, bedrockErr := r.bedrockAuthHeaders(profile, model, ctx, finalBody)
+		if bedrockErr != nil {
+			return r.createErrorResponse(401, bedrockErr.Error())
 		}
-		setHeaders = append(setHeaders, &core.HeaderValueOption{
-			Header: &core.HeaderValue{
-				Key:      authHeader,
-				RawValue: []byte(value),
-			},
-		})
-		logging.Infof("Added %s header for model %s (provider=%s)", authHeader, model, llmProvider)
+		setHeaders = append(setHeaders, bedrockHeaders...)
+		bedrockSignedPath = signedPath
 	} else {
-		// fail_open=true path: no key but allowed through
-		logging.Warnf("No API key for %s model %q (fail_open=true) — forwarding without auth header", llmProvider, model)
+		// Resolve API key via credential chain (ext_authz headers → static config)
+		accessKey, credErr := r.CredentialResolver.KeyForProvider(llmProvider, model, ctx.Headers)
+		if credErr != nil {
+			return r.createErrorResponse(401, fmt.Sprintf("Credential resolution failed for model %s: %v", model, credErr))
+		}
+		if accessKey != "" {
+			value := accessKey
+			if authPrefix != "" {
+				value = authPrefix + " " + accessKey
+			}
+			setHeaders = append(setHeaders, &core.HeaderValueOption{
+				Header: &core.HeaderValue{
+					Key:      authHeader,
+					RawValue: []byte(value),
+				},
+			})
+			logging.Infof("Added %s header for model %s (provider=%s)", authHeader, model, llmProvider)
+		} else {
+			// fail_open=true path: no key but allowed through
+			logging.Warnf("No API key for %s model %q (fail_open=true) — forwarding without auth header", llmProvider, model)
+		}
 	}
 
 	// Add explicit extra headers from provider profile config
@@ -663,9 +895,11 @@ func (r *OpenAIRouter) createRoutingResponse(model string, endpoint string, endp
 			})
 		}
 	}
+	setHeaders = append(setHeaders, anthropicVersionHeader(llmProvider)...)
 
 	// Strip ext_authz injected headers before forwarding upstream (prevent key leakage)
 	removeHeaders = append(removeHeaders, r.CredentialResolver.HeadersToStrip()...)
+	removeHeaders = append(removeHeaders, vsrContextHeadersToStrip()...)
 
 	// Add standard routing headers
 	if endpoint != "" {
@@ -685,8 +919,19 @@ func (r *OpenAIRouter) createRoutingResponse(model string, endpoint string, endp
 		})
 	}
 
-	// Set :path from provider profile, or use Response API override
-	if ctx.ResponseAPICtx != nil && ctx.ResponseAPICtx.IsResponseAPIRequest {
+	// Set :path from provider profile, or use Response API override. For
+	// Bedrock, bedrockSignedPath (the percent-encoded path bedrockAuthHeaders
+	// actually signed) takes precedence over re-resolving the chat path here
+	// - sending anything else would mismatch the signature computed above.
+	if llmProvider == authz.ProviderAWSBedrock && bedrockSignedPath != "" {
+		setHeaders = append(setHeaders, &core.HeaderValueOption{
+			Header: &core.HeaderValue{
+				Key:      ":path",
+				RawValue: []byte(bedrockSignedPath),
+			},
+		})
+		logging.Infof("Bedrock: Rewriting path to signed, percent-encoded %s", bedrockSignedPath)
+	} else if ctx.ResponseAPICtx != nil && ctx.ResponseAPICtx.IsResponseAPIRequest {
 		setHeaders = append(setHeaders, &core.HeaderValueOption{
 			Header: &core.HeaderValue{
 				Key:      ":path",
@@ -763,26 +1008,52 @@ func (r *OpenAIRouter) createSpecifiedModelResponse(model string, upstreamModel
 		return r.createErrorResponse(500, fmt.Sprintf("Provider auth resolution failed for endpoint %s: %v", endpointName, authErr))
 	}
 
-	// Resolve API key via credential chain (ext_authz headers → static config)
-	accessKey, credErr := r.CredentialResolver.KeyForProvider(llmProvider, model, ctx.Headers)
-	if credErr != nil {
-		return r.createErrorResponse(401, fmt.Sprintf("Credential resolution failed for model %s: %v", model, credErr))
+	// Run the body-mutation pipeline once, ahead of both the auth section
+	// (Bedrock's signature covers the final request body) and the header
+	// assembly below, so model-rewrite, Response API translation, and any
+	// decision-enabled prompt-guard / system-prompt-prepend / tool-filter
+	// / json-schema-coerce stages all operate on - and together produce -
+	// the single final body, with content-length computed exactly once
+	// from it via bodyMutationHeaders.
+	var baseBody []byte
+	if ctx != nil {
+		baseBody = ctx.OriginalRequestBody
 	}
-	if accessKey != "" {
-		value := accessKey
-		if authPrefix != "" {
-			value = authPrefix + " " + accessKey
+	pipelineResult, mutErr := r.bodyMutationPipeline().Run(bodyMutationContext(ctx, model, upstreamModel, string(llmProvider)), baseBody)
+	if mutErr != nil {
+		return r.createErrorResponse(500, fmt.Sprintf("Body mutation failed for model %s: %v", model, mutErr))
+	}
+
+	var bedrockSignedPath string
+	if llmProvider == authz.ProviderAWSBedrock {
+		bedrockHeaders, signedPath, bedrockErr := r.bedrockAuthHeaders(profile, model, ctx, pipelineResult.Body)
+		if bedrockErr != nil {
+			return r.createErrorResponse(401, bedrockErr.Error())
 		}
-		setHeaders = append(setHeaders, &core.HeaderValueOption{
-			Header: &core.HeaderValue{
-				Key:      authHeader,
-				RawValue: []byte(value),
-			},
-		})
-		logging.Infof("Added %s header for model %s (provider=%s)", authHeader, model, llmProvider)
+		setHeaders = append(setHeaders, bedrockHeaders...)
+		bedrockSignedPath = signedPath
 	} else {
-		// fail_open=true path: no key but allowed through
-		logging.Warnf("No API key for %s model %q (fail_open=true) — forwarding without auth header", llmProvider, model)
+		// Resolve API key via credential chain (ext_authz headers → static config)
+		accessKey, credErr := r.CredentialResolver.KeyForProvider(llmProvider, model, ctx.Headers)
+		if credErr != nil {
+			return r.createErrorResponse(401, fmt.Sprintf("Credential resolution failed for model %s: %v", model, credErr))
+		}
+		if accessKey != "" {
+			value := accessKey
+			if authPrefix != "" {
+				value = authPrefix + " " + accessKey
+			}
+			setHeaders = append(setHeaders, &core.HeaderValueOption{
+				Header: &core.HeaderValue{
+					Key:      authHeader,
+					RawValue: []byte(value),
+				},
+			})
+			logging.Infof("Added %s header for model %s (provider=%s)", authHeader, model, llmProvider)
+		} else {
+			// fail_open=true path: no key but allowed through
+			logging.Warnf("No API key for %s model %q (fail_open=true) — forwarding without auth header", llmProvider, model)
+		}
 	}
 
 	// Add explicit extra headers from provider profile config
@@ -793,9 +1064,11 @@ func (r *OpenAIRouter) createSpecifiedModelResponse(model string, upstreamModel
 			})
 		}
 	}
+	setHeaders = append(setHeaders, anthropicVersionHeader(llmProvider)...)
 
 	// Strip ext_authz injected headers before forwarding upstream (prevent key leakage)
 	removeHeaders = append(removeHeaders, r.CredentialResolver.HeadersToStrip()...)
+	removeHeaders = append(removeHeaders, vsrContextHeadersToStrip()...)
 
 	if endpoint != "" {
 		setHeaders = append(setHeaders, &core.HeaderValueOption{
@@ -813,26 +1086,34 @@ func (r *OpenAIRouter) createSpecifiedModelResponse(model string, upstreamModel
 		},
 	})
 
-	// Determine if we need to mutate the request body
 	var bodyMutation *ext_proc.BodyMutation
-	needsBodyMutation := false
 
 	// Model name rewriting: if the upstream model name differs from the alias,
-	// we need to rewrite the "model" field in the request body
+	// the body-mutation pipeline's ModelRewriteStage rewrites the "model"
+	// field in the request body.
 	if upstreamModel != model {
-		needsBodyMutation = true
 		logging.Infof("Model name rewriting: %s -> %s in request body", model, upstreamModel)
 	}
 
-	// Set :path from provider profile, or use Response API override
-	if ctx != nil && ctx.ResponseAPICtx != nil && ctx.ResponseAPICtx.IsResponseAPIRequest {
+	// Set :path from provider profile, or use Response API override. For
+	// Bedrock, bedrockSignedPath (the percent-encoded path bedrockAuthHeaders
+	// actually signed) takes precedence over re-resolving the chat path here
+	// - sending anything else would mismatch the signature computed above.
+	if llmProvider == authz.ProviderAWSBedrock && bedrockSignedPath != "" {
+		setHeaders = append(setHeaders, &core.HeaderValueOption{
+			Header: &core.HeaderValue{
+				Key:      ":path",
+				RawValue: []byte(bedrockSignedPath),
+			},
+		})
+		logging.Infof("Bedrock: Rewriting path to signed, percent-encoded %s (specified model)", bedrockSignedPath)
+	} else if ctx != nil && ctx.ResponseAPICtx != nil && ctx.ResponseAPICtx.IsResponseAPIRequest {
 		setHeaders = append(setHeaders, &core.HeaderValueOption{
 			Header: &core.HeaderValue{
 				Key:      ":path",
 				RawValue: []byte("/v1/chat/completions"),
 			},
 		})
-		needsBodyMutation = true
 		logging.Infof("Response API: Rewriting path to /v1/chat/completions (specified model)")
 	} else if profile != nil {
 		chatPath, pathErr := profile.ResolveChatPath()
@@ -850,41 +1131,11 @@ func (r *OpenAIRouter) createSpecifiedModelResponse(model string, upstreamModel
 		}
 	}
 
-	if needsBodyMutation {
+	if pipelineResult.Changed && len(pipelineResult.Body) > 0 {
 		removeHeaders = append(removeHeaders, "content-length")
-
-		// Start with the original request body or Response API translated body
-		var bodyBytes []byte
-		if ctx != nil && ctx.ResponseAPICtx != nil && ctx.ResponseAPICtx.IsResponseAPIRequest && len(ctx.ResponseAPICtx.TranslatedBody) > 0 {
-			bodyBytes = ctx.ResponseAPICtx.TranslatedBody
-		} else if ctx != nil && len(ctx.OriginalRequestBody) > 0 {
-			bodyBytes = ctx.OriginalRequestBody
-		}
-
-		// Rewrite model name in body if needed
-		if upstreamModel != model && len(bodyBytes) > 0 {
-			rewritten, err := rewriteModelInBody(bodyBytes, upstreamModel)
-			if err != nil {
-				logging.Warnf("Failed to rewrite model in body: %v, sending original body", err)
-			} else {
-				bodyBytes = rewritten
-			}
-		}
-
-		if len(bodyBytes) > 0 {
-			// Update content-length for the modified body
-			setHeaders = append(setHeaders, &core.HeaderValueOption{
-				Header: &core.HeaderValue{
-					Key:      "content-length",
-					RawValue: []byte(fmt.Sprintf("%d", len(bodyBytes))),
-				},
-			})
-			bodyMutation = &ext_proc.BodyMutation{
-				Mutation: &ext_proc.BodyMutation_Body{
-					Body: bodyBytes,
-				},
-			}
-		}
+		var contentLengthHeaders []*core.HeaderValueOption
+		bodyMutation, contentLengthHeaders, _ = bodyMutationHeaders(pipelineResult.Body)
+		setHeaders = append(setHeaders, contentLengthHeaders...)
 	}
 
 	return &ext_proc.ProcessingResponse{
@@ -903,28 +1154,6 @@ func (r *OpenAIRouter) createSpecifiedModelResponse(model string, upstreamModel
 	}
 }
 
-// rewriteModelInBody rewrites the "model" field in a JSON request body.
-// Uses a generic map approach to preserve all other fields.
-func rewriteModelInBody(body []byte, newModel string) ([]byte, error) {
-	var requestMap map[string]json.RawMessage
-	if err := json.Unmarshal(body, &requestMap); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal request body: %w", err)
-	}
-
-	modelJSON, err := json.Marshal(newModel)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal new model name: %w", err)
-	}
-	requestMap["model"] = json.RawMessage(modelJSON)
-
-	rewritten, err := json.Marshal(requestMap)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal rewritten request: %w", err)
-	}
-
-	return rewritten, nil
-}
-
 // getModelAccessKey retrieves the access_key for a given model from the config
 // Returns empty string if model not found or access_key not configured
 func (r *OpenAIRouter) getModelAccessKey(modelName string) string {
@@ -976,8 +1205,8 @@ func (r *OpenAIRouter) handleMemoryRetrieval(
 
 	// Get memory store from router
 	store := r.getMemoryStore()
-	if store == nil || !store.IsEnabled() {
-		logging.Debugf("Memory: Store not available or disabled, skipping retrieval")
+	if store == nil || !store.IsEnabled() || !r.MemoryStoreReady() {
+		logging.Debugf("Memory: Store not available, disabled, or failing health checks, skipping retrieval")
 		return requestBody, nil
 	}
 
@@ -1085,6 +1314,19 @@ func (r *OpenAIRouter) handleMemoryRetrieval(
 	// Step 6: Format memory context and inject into request body
 	ctx.MemoryContext = FormatMemoriesAsContext(memories)
 
+	// Record which memories backed this request so MemoryCitationPlugin
+	// can attach their provenance IDs to the streamed response once it
+	// comes back (see sse_pipeline.go).
+	citationIDs := make([]string, 0, len(memories))
+	for _, mem := range memories {
+		if mem.Memory != nil {
+			citationIDs = append(citationIDs, mem.Memory.ID)
+		}
+	}
+	if len(citationIDs) > 0 {
+		memoryCitationIDs.Store(replayRequestID(ctx), citationIDs)
+	}
+
 	// Step 7: Inject memory into request body as system message
 	// This happens here (before routing diverges) so it works for BOTH auto and specified models
 	if ctx.MemoryContext != "" {
@@ -1102,7 +1344,7 @@ func (r *OpenAIRouter) handleMemoryRetrieval(
 }
 
 // getMemoryStore returns the memory store instance.
-func (r *OpenAIRouter) getMemoryStore() *memory.MilvusStore {
+func (r *OpenAIRouter) getMemoryStore() memory.Store {
 	// Return the actual memory store from router
 	return r.MemoryStore
 }
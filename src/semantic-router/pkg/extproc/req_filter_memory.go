@@ -6,31 +6,186 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
+	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/agents"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory/tools"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/metrics"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/ratelimit"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/responseapi"
 )
 
+// MemoryTimings captures how long each phase of one request's memory
+// operations took, in milliseconds - see MemoryFilterContext.Timings,
+// BuildMemoryOperations (surfaces these to the caller via
+// responseapi.MemoryOperations.Timings), and pkg/observability/metrics
+// (surfaces the same numbers as memory_stage_duration_seconds).
+//
+// RetrieveMs, InjectMs, and StoreMs are measured at MemoryFilter's own call
+// boundaries (f.store.Retrieve, InjectMemoryContext's body rewrite,
+// f.store.Store) and are always populated when that phase runs. EmbedMs,
+// RerankMs, CacheHit, and CandidatesScanned stay at their zero value:
+// producing them honestly would require memory.Store/Scorer to expose
+// per-phase instrumentation they don't today - Retrieve is one opaque call
+// that may embed, rerank, and scan candidates internally (see
+// memory.HybridScorer's embeddingCache), with no hook for a caller to
+// observe those sub-phases separately.
+type MemoryTimings struct {
+	EmbedMs           int64 `json:"embed_ms"`
+	RetrieveMs        int64 `json:"retrieve_ms"`
+	RerankMs          int64 `json:"rerank_ms"`
+	InjectMs          int64 `json:"inject_ms"`
+	StoreMs           int64 `json:"store_ms"`
+	CacheHit          bool  `json:"cache_hit"`
+	CandidatesScanned int   `json:"candidates_scanned"`
+}
+
 // MemoryFilter handles agentic memory operations for requests.
 // It retrieves relevant memories and injects them into the context,
 // and stores new memories from responses.
 //
 // This filter integrates with the Response API to provide cross-session memory.
 type MemoryFilter struct {
-	store   memory.Store
-	enabled bool
+	store                memory.Store
+	resolver             AuthResolver
+	allowUntrustedWrites bool
+	enabled              bool
+
+	// quota, writeLimiter, and usage implement QuotaConfig enforcement (see
+	// WithQuota). writeLimiter and usage are nil when quota is the zero
+	// value - StoreFromResponse skips quota checks entirely rather than
+	// evaluating a disabled limit on every call.
+	quota        memory.QuotaConfig
+	writeLimiter *ratelimit.TokenBucketLimiter
+	usage        *memoryUsageTracker
+
+	// audit, if set (see WithAuditLog), records every retrieve/store/forget
+	// this filter performs - including quota evictions - to a tamper-evident
+	// hash-chained log a deployment can later replay for compliance review.
+	audit *memory.AuditLog
+
+	// toolsEnabled and toolsAllowWrites implement the memory_search/
+	// memory_store/memory_forget/memory_update tool namespace (see
+	// WithTools, pkg/memory/tools). Disabled by default - a caller opts in,
+	// the same way WithQuota/WithAuditLog are opt-in.
+	toolsEnabled     bool
+	toolsAllowWrites bool
+
+	// agents resolves a routing decision's name to an *agents.Agent (see
+	// WithAgents). Nil means no agent layer is configured - every request
+	// behaves exactly as it did before agents existed.
+	agents *agents.Registry
+
+	// starterSynthesizer backs SuggestStarters (see WithStarterSynthesizer).
+	// Nil means SuggestStarters falls back to defaultStarterSynthesizer.
+	starterSynthesizer StarterSynthesizer
+}
+
+// MemoryFilterOption configures optional MemoryFilter behavior at
+// construction.
+type MemoryFilterOption func(*MemoryFilter)
+
+// WithAllowUntrustedWrites lets StoreFromResponse auto-store memories for a
+// caller whose resolved Identity.Trusted is false (e.g. resolved only via
+// MetadataResolver). Off by default: an unauthenticated caller shouldn't be
+// able to write into another user's memory just by claiming their user_id
+// in request metadata.
+func WithAllowUntrustedWrites(allow bool) MemoryFilterOption {
+	return func(f *MemoryFilter) { f.allowUntrustedWrites = allow }
 }
 
-// NewMemoryFilter creates a new memory filter.
-func NewMemoryFilter(store memory.Store) *MemoryFilter {
-	return &MemoryFilter{
-		store:   store,
-		enabled: store != nil,
+// WithQuota enables per-user quota enforcement in StoreFromResponse:
+// MaxMemoriesPerUser/MaxBytesPerUser evict existing memories under cfg's
+// EvictionPolicy to make room for a new one, and MaxWritesPerSecond throttles
+// writes with the same token-bucket algorithm pkg/ratelimit uses for request
+// admission. A zero-value cfg (the default) disables all of it.
+func WithQuota(cfg memory.QuotaConfig) MemoryFilterOption {
+	return func(f *MemoryFilter) {
+		f.quota = cfg
+		if !cfg.Enabled() {
+			return
+		}
+		f.usage = newMemoryUsageTracker()
+		if cfg.MaxWritesPerSecond > 0 {
+			quota := ratelimit.ProviderQuota{Rate: cfg.MaxWritesPerSecond, Burst: cfg.MaxWritesPerSecond}
+			f.writeLimiter = ratelimit.NewTokenBucketLimiter(ratelimit.NewInMemoryStore(), quota, nil, nil, nil)
+		}
 	}
 }
 
+// WithAuditLog records every retrieve/store/forget this filter performs -
+// caller, user/project scope, and memory ID - into log, a tamper-evident
+// hash-chained memory.AuditLog (see memory.NewAuditLog). Unset by default:
+// audit logging has a per-request cost, so a deployment opts in rather than
+// paying it unconditionally.
+func WithAuditLog(log *memory.AuditLog) MemoryFilterOption {
+	return func(f *MemoryFilter) { f.audit = log }
+}
+
+// WithTools advertises the memory_search/memory_store/memory_forget/
+// memory_update tool namespace (see pkg/memory/tools) in every outbound
+// request this filter processes, and lets MemoryFilter execute matching
+// tool_calls against the store directly instead of only ever reading/
+// writing through the opaque auto-retrieve/auto-store path. allowWrites
+// gates memory_forget/memory_update: false advertises and executes
+// memory_search/memory_store only, for a caller that should be able to
+// recall and add to memory but never delete or rewrite it.
+func WithTools(allowWrites bool) MemoryFilterOption {
+	return func(f *MemoryFilter) {
+		f.toolsEnabled = true
+		f.toolsAllowWrites = allowWrites
+	}
+}
+
+// WithAgents configures the Agent registry (see pkg/agents) used to resolve
+// a routing decision's name into memory/tool policy overrides in
+// ProcessResponseAPIRequestForAgent and ExecuteToolCalls. Unset by default -
+// a deployment without named agents behaves exactly as before agents
+// existed.
+func WithAgents(registry *agents.Registry) MemoryFilterOption {
+	return func(f *MemoryFilter) { f.agents = registry }
+}
+
+// WithStarterSynthesizer installs fn as the StarterSynthesizer
+// SuggestStarters uses to turn a user's grouped memories into prompt-starter
+// suggestions - typically a thin wrapper around the router's configured LLM
+// client. Unset defaults to defaultStarterSynthesizer, a no-LLM fallback.
+func WithStarterSynthesizer(fn StarterSynthesizer) MemoryFilterOption {
+	return func(f *MemoryFilter) { f.starterSynthesizer = fn }
+}
+
+// NewMemoryFilter creates a new memory filter. resolver is required - there
+// is no implicit fallback identity source - so every deployment states
+// explicitly how callers are authenticated instead of relying on the old
+// dev-build-only extractUserID fallback chain (see auth_resolver.go).
+func NewMemoryFilter(store memory.Store, resolver AuthResolver, opts ...MemoryFilterOption) *MemoryFilter {
+	f := &MemoryFilter{
+		store:    store,
+		resolver: resolver,
+		enabled:  store != nil,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// ResolveIdentity runs the filter's configured AuthResolver over reqCtx.
+// Callers that build a MemoryFilterContext themselves (rather than through
+// ProcessResponseAPIRequest) should call this and set the result on
+// MemoryFilterContext.Identity so StoreFromResponse can apply the Trusted
+// gate.
+func (f *MemoryFilter) ResolveIdentity(reqCtx *RequestContext) (*Identity, error) {
+	if f.resolver == nil {
+		return nil, fmt.Errorf("memory: MemoryFilter has no AuthResolver configured")
+	}
+	return f.resolver.Resolve(reqCtx)
+}
+
 // IsEnabled returns whether memory operations are enabled.
 func (f *MemoryFilter) IsEnabled() bool {
 	return f.enabled
@@ -44,9 +199,17 @@ type MemoryFilterContext struct {
 	// Config from the Response API request
 	Config *responseapi.MemoryConfig
 
-	// Context from the Response API request
+	// Context from the Response API request, after applying any
+	// UserIDOverride/ProjectIDOverride from Agent.
 	Context *responseapi.MemoryContext
 
+	// Agent is the resolved agent for this request (see
+	// ProcessResponseAPIRequestForAgent, pkg/agents), or nil if no agent
+	// name was given or none matched. Nil means "no agent" - memory and
+	// tool behavior fall back entirely to the request body/filter
+	// defaults.
+	Agent *agents.Agent
+
 	// RetrievedMemories are the memories found for this request
 	RetrievedMemories []*memory.RetrieveResult
 
@@ -58,12 +221,47 @@ type MemoryFilterContext struct {
 
 	// StoredMemoryID is set after storing a memory
 	StoredMemoryID string
+
+	// Identity is the caller identity resolved via MemoryFilter.ResolveIdentity
+	// - ProcessResponseAPIRequestForAgent populates this automatically when
+	// the filter has an AuthResolver configured. Nil means either no
+	// AuthResolver is configured at all (MemoryFilter.resolver == nil, so
+	// StoreFromResponse's Trusted gate has nothing to gate on and does not
+	// apply), or one is configured but failed to produce an Identity for
+	// this request (StoreFromResponse then refuses the write, the same as
+	// an untrusted one, unless AllowUntrustedWrites is set).
+	Identity *Identity
+
+	// Timings records per-phase latency for this request's memory
+	// operations (see MemoryTimings), surfaced to callers via
+	// BuildMemoryOperations and to pkg/observability/metrics.
+	Timings MemoryTimings
 }
 
 // ProcessResponseAPIRequest processes a Response API request for memory operations.
 // It reads memory config from the request, retrieves relevant memories,
-// and returns context for injection.
-func (f *MemoryFilter) ProcessResponseAPIRequest(ctx context.Context, req *responseapi.ResponseAPIRequest, userQuery string) (*MemoryFilterContext, error) {
+// and returns context for injection. It never resolves an Agent - see
+// ProcessResponseAPIRequestForAgent for callers that route through one.
+//
+// reqCtx is used only to resolve the caller's Identity (see
+// MemoryFilter.ResolveIdentity) for StoreFromResponse's Trusted gate; it may
+// be nil if the caller has none (e.g. memory is disabled, or the filter has
+// no AuthResolver configured), in which case no Identity is attached.
+func (f *MemoryFilter) ProcessResponseAPIRequest(ctx context.Context, reqCtx *RequestContext, req *responseapi.ResponseAPIRequest, userQuery string) (*MemoryFilterContext, error) {
+	return f.ProcessResponseAPIRequestForAgent(ctx, reqCtx, req, userQuery, "")
+}
+
+// ProcessResponseAPIRequestForAgent is ProcessResponseAPIRequest, additionally
+// resolving agentName (typically RequestContext.VSRSelectedDecisionName -
+// VSRSelectedDecision names an Agent the same way it already names a model
+// or plugin config) against f.agents. When an Agent resolves, its fields
+// override the request body's MemoryConfig wherever they're set
+// (AllowedMemoryTypes, RetrievalLimit, SimilarityThreshold, AutoStore,
+// UserIDOverride/ProjectIDOverride) and its PromptTemplate renders the
+// injected memory context instead of the default
+// "## Relevant Context from Memory" header. An unresolved agentName (empty,
+// or no matching Agent) behaves identically to ProcessResponseAPIRequest.
+func (f *MemoryFilter) ProcessResponseAPIRequestForAgent(ctx context.Context, reqCtx *RequestContext, req *responseapi.ResponseAPIRequest, userQuery string, agentName string) (*MemoryFilterContext, error) {
 	if !f.enabled {
 		return nil, nil
 	}
@@ -79,27 +277,70 @@ func (f *MemoryFilter) ProcessResponseAPIRequest(ctx context.Context, req *respo
 		return nil, nil
 	}
 
+	agent := f.agents.Resolve(agentName)
+
+	// scopedContext is req.MemoryContext with any Agent scope overrides
+	// applied, copied rather than mutated in place so this filter never
+	// rewrites the caller's own request struct.
+	scopedContext := *req.MemoryContext
+	if agent != nil && agent.UserIDOverride != "" {
+		scopedContext.UserID = agent.UserIDOverride
+	}
+	if agent != nil && agent.ProjectIDOverride != "" {
+		scopedContext.ProjectID = agent.ProjectIDOverride
+	}
+
 	memCtx := &MemoryFilterContext{
 		Enabled:     true,
 		Config:      req.MemoryConfig,
-		Context:     req.MemoryContext,
+		Context:     &scopedContext,
+		Agent:       agent,
 		ShouldStore: req.MemoryConfig.AutoStore,
 	}
+	if agent != nil && agent.AutoStore != nil {
+		memCtx.ShouldStore = *agent.AutoStore
+	}
+
+	// Resolve the caller's Identity up front so StoreFromResponse's Trusted
+	// gate has something to gate on. A resolve failure (no resolver
+	// configured, or the configured one found nothing) leaves Identity nil;
+	// StoreFromResponse treats a configured resolver producing no Identity
+	// as a reason to refuse the write, not as "untrusted checks don't
+	// apply".
+	if f.resolver != nil && reqCtx != nil {
+		identity, err := f.ResolveIdentity(reqCtx)
+		if err != nil {
+			logging.Warnf("Memory: failed to resolve caller identity: %v", err)
+		}
+		memCtx.Identity = identity
+	}
 
 	// Build retrieve options
 	opts := memory.RetrieveOptions{
 		Query:     userQuery,
-		UserID:    req.MemoryContext.UserID,
-		ProjectID: req.MemoryContext.ProjectID,
+		UserID:    scopedContext.UserID,
+		ProjectID: scopedContext.ProjectID,
 		Limit:     req.MemoryConfig.RetrievalLimit,
 		Threshold: req.MemoryConfig.SimilarityThreshold,
 	}
+	if agent != nil && agent.RetrievalLimit > 0 {
+		opts.Limit = agent.RetrievalLimit
+	}
+	if agent != nil && agent.SimilarityThreshold > 0 {
+		opts.Threshold = agent.SimilarityThreshold
+	}
 
-	// Convert memory type strings to MemoryType
-	if len(req.MemoryConfig.MemoryTypes) > 0 {
-		for _, typeStr := range req.MemoryConfig.MemoryTypes {
-			opts.Types = append(opts.Types, memory.MemoryType(typeStr))
-		}
+	// Convert memory type strings to MemoryType. An Agent's
+	// AllowedMemoryTypes, if set, replaces whatever the request asked for
+	// rather than merging with it - an agent scoped to "procedural"
+	// memory shouldn't leak other types just because a caller requested
+	// them.
+	memoryTypes := req.MemoryConfig.MemoryTypes
+	if agent != nil && len(agent.AllowedMemoryTypes) > 0 {
+		memoryTypes = agent.AllowedMemoryTypes
+	}
+	for _, typeStr := range memoryTypes {
+		opts.Types = append(opts.Types, memory.MemoryType(typeStr))
 	}
 
 	// Set defaults
@@ -114,47 +355,62 @@ func (f *MemoryFilter) ProcessResponseAPIRequest(ctx context.Context, req *respo
 	}
 
 	// Retrieve relevant memories
+	retrieveStart := time.Now()
 	results, err := f.store.Retrieve(ctx, opts)
+	memCtx.Timings.RetrieveMs = time.Since(retrieveStart).Milliseconds()
+	metrics.RecordMemoryStageDuration("retrieve", memCtx.Timings.RetrieveMs)
 	if err != nil {
 		logging.Warnf("Memory: retrieval error: %v", err)
 		// Continue without memories - don't fail the request
 		return memCtx, nil
 	}
 
+	if f.audit != nil {
+		if _, err := f.audit.Append(memory.AuditOpRetrieve, scopedContext.UserID, scopedContext.ProjectID, "", callerIdentity(memCtx)); err != nil {
+			logging.Warnf("Memory: failed to append audit log entry for retrieve: %v", err)
+		}
+	}
+
 	memCtx.RetrievedMemories = results
 
 	// Format memories for context injection
 	if len(results) > 0 {
-		memCtx.InjectedContext = f.formatMemoriesForContext(results)
+		memCtx.InjectedContext = f.formatMemoriesForContext(agent, results)
 		logging.Infof("Memory: Retrieved %d memories for user %s (query: %s)",
-			len(results), req.MemoryContext.UserID, truncateQuery(userQuery, 50))
+			len(results), scopedContext.UserID, truncateQuery(userQuery, 50))
 	}
 
 	return memCtx, nil
 }
 
-// formatMemoriesForContext formats retrieved memories into a context string for the LLM.
-func (f *MemoryFilter) formatMemoriesForContext(memories []*memory.RetrieveResult) string {
-	if len(memories) == 0 {
+// callerIdentity returns the UserID of memCtx's resolved Identity for audit
+// logging, or "" if no Identity was attached (see MemoryFilterContext.Identity).
+func callerIdentity(memCtx *MemoryFilterContext) string {
+	if memCtx == nil || memCtx.Identity == nil {
 		return ""
 	}
+	return memCtx.Identity.UserID
+}
 
-	var sb strings.Builder
-	sb.WriteString("## Relevant Context from Memory\n\n")
-	sb.WriteString("The following information was retrieved from the user's memory:\n\n")
+// formatMemoriesForContext formats retrieved memories into a context string
+// for the LLM, rendered through agent's PromptTemplate (see agents.Agent.Render)
+// when agent is non-nil, falling back to agents.DefaultPromptTemplate
+// otherwise.
+func (f *MemoryFilter) formatMemoriesForContext(agent *agents.Agent, memories []*memory.RetrieveResult) string {
+	if len(memories) == 0 {
+		return ""
+	}
 
+	rendered := make([]agents.RenderedMemory, 0, len(memories))
 	for i, mem := range memories {
 		typeLabel := string(mem.Memory.Type)
 		if typeLabel == "" {
 			typeLabel = "general"
 		}
-
-		sb.WriteString(fmt.Sprintf("%d. [%s] %s\n", i+1, typeLabel, mem.Memory.Content))
+		rendered = append(rendered, agents.RenderedMemory{Index: i + 1, Type: typeLabel, Content: mem.Memory.Content})
 	}
 
-	sb.WriteString("\nUse this context to provide a more personalized and informed response.\n")
-
-	return sb.String()
+	return agent.Render(rendered)
 }
 
 // InjectMemoryContext injects memory context into a Chat Completions request.
@@ -165,6 +421,12 @@ func (f *MemoryFilter) InjectMemoryContext(body []byte, memCtx *MemoryFilterCont
 		return body, nil
 	}
 
+	injectStart := time.Now()
+	defer func() {
+		memCtx.Timings.InjectMs = time.Since(injectStart).Milliseconds()
+		metrics.RecordMemoryStageDuration("inject", memCtx.Timings.InjectMs)
+	}()
+
 	// Parse the request
 	var req map[string]interface{}
 	if err := json.Unmarshal(body, &req); err != nil {
@@ -204,6 +466,138 @@ func (f *MemoryFilter) InjectMemoryContext(body []byte, memCtx *MemoryFilterCont
 	return json.Marshal(req)
 }
 
+// AdvertiseTools merges the memory tool namespace (see WithTools,
+// pkg/memory/tools) into an outbound Chat Completions request body's
+// "tools" array, alongside whatever tools the caller already requested. It
+// is a no-op if tools support isn't enabled, memCtx is nil/disabled, or the
+// request set "tool_choice":"none" - a caller that explicitly opted out of
+// tool calling for this request shouldn't have tools silently added back.
+func (f *MemoryFilter) AdvertiseTools(body []byte, memCtx *MemoryFilterContext) ([]byte, error) {
+	if !f.toolsEnabled || memCtx == nil || !memCtx.Enabled {
+		return body, nil
+	}
+
+	var req map[string]interface{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return body, err
+	}
+
+	if choice, ok := req["tool_choice"].(string); ok && choice == "none" {
+		return body, nil
+	}
+
+	var existing []interface{}
+	if raw, ok := req["tools"].([]interface{}); ok {
+		existing = raw
+	}
+
+	for _, spec := range tools.Specs(agents.AllowWrites(memCtx.Agent, f.toolsAllowWrites)) {
+		data, err := json.Marshal(spec)
+		if err != nil {
+			return body, fmt.Errorf("memory: marshal tool spec %s: %w", spec.Function.Name, err)
+		}
+		var specMap map[string]interface{}
+		if err := json.Unmarshal(data, &specMap); err != nil {
+			return body, err
+		}
+		existing = append(existing, specMap)
+	}
+	req["tools"] = existing
+
+	return json.Marshal(req)
+}
+
+// toolCallMessage is the role:"tool" message shape fed back to the model
+// after executing a tool call, matching the stable Chat Completions wire
+// format (see pkg/extproc's wireMessage for the same wire-over-SDK choice).
+type toolCallMessage struct {
+	Role       string `json:"role"`
+	ToolCallID string `json:"tool_call_id"`
+	Content    string `json:"content"`
+}
+
+// ExecuteToolCalls inspects responseBody's first choice for assistant
+// tool_calls matching the memory namespace (see tools.IsMemoryTool),
+// executes each against the store scoped to memCtx's UserID/ProjectID, and
+// returns one role:"tool" message per executed call, ready to append to the
+// conversation so the model can continue. Returns nil, nil if there were no
+// memory tool_calls to handle - the caller's own tool_calls, if any, are
+// left untouched for it to execute itself.
+func (f *MemoryFilter) ExecuteToolCalls(ctx context.Context, memCtx *MemoryFilterContext, responseBody []byte) ([]toolCallMessage, error) {
+	if !f.enabled || !f.toolsEnabled || memCtx == nil || !memCtx.Enabled {
+		return nil, nil
+	}
+
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				ToolCalls []struct {
+					ID       string `json:"id"`
+					Type     string `json:"type"`
+					Function struct {
+						Name      string `json:"name"`
+						Arguments string `json:"arguments"`
+					} `json:"function"`
+				} `json:"tool_calls"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(responseBody, &resp); err != nil {
+		return nil, fmt.Errorf("memory: parse response for tool_calls: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, nil
+	}
+
+	readOnly := !agents.AllowWrites(memCtx.Agent, f.toolsAllowWrites)
+	executor := tools.NewExecutor(f.store, memCtx.Context.UserID, memCtx.Context.ProjectID, readOnly)
+
+	var messages []toolCallMessage
+	for _, call := range resp.Choices[0].Message.ToolCalls {
+		if call.Type != "function" || !tools.IsMemoryTool(call.Function.Name) {
+			continue
+		}
+
+		result, err := executor.Execute(ctx, call.Function.Name, call.Function.Arguments)
+		if err != nil {
+			logging.Warnf("Memory: tool call %s failed: %v", call.Function.Name, err)
+			result = fmt.Sprintf(`{"error": %q}`, err.Error())
+		}
+
+		if f.audit != nil {
+			f.auditToolCall(call.Function.Name, memCtx)
+		}
+
+		messages = append(messages, toolCallMessage{
+			Role:       "tool",
+			ToolCallID: call.ID,
+			Content:    result,
+		})
+	}
+
+	return messages, nil
+}
+
+// auditToolCall records a tool-driven memory operation the same way
+// ProcessResponseAPIRequest/StoreFromResponse do, using memCtx's UserID/
+// ProjectID as the scope since a tool call's own arguments never carry
+// those (see tools.Executor). The affected memory ID isn't captured here -
+// it lives inside the tool's JSON result, not in a form this audit call
+// site can cheaply extract - so MemoryID is left blank for tool-driven
+// entries; the operation, caller, and timestamp are still recorded.
+func (f *MemoryFilter) auditToolCall(toolName string, memCtx *MemoryFilterContext) {
+	op := memory.AuditOpRetrieve
+	switch toolName {
+	case tools.ToolMemoryStore:
+		op = memory.AuditOpStore
+	case tools.ToolMemoryForget:
+		op = memory.AuditOpForget
+	}
+	if _, err := f.audit.Append(op, memCtx.Context.UserID, memCtx.Context.ProjectID, "", callerIdentity(memCtx)); err != nil {
+		logging.Warnf("Memory: failed to append audit log entry for tool call %s: %v", toolName, err)
+	}
+}
+
 // StoreFromResponse extracts and stores memories from an LLM response.
 // This is called during response processing if auto_store is enabled.
 func (f *MemoryFilter) StoreFromResponse(ctx context.Context, memCtx *MemoryFilterContext, responseBody []byte, userQuery string) error {
@@ -211,6 +605,25 @@ func (f *MemoryFilter) StoreFromResponse(ctx context.Context, memCtx *MemoryFilt
 		return nil
 	}
 
+	// f.resolver != nil means this deployment has actually wired up an
+	// AuthResolver, so a nil memCtx.Identity here means resolution was
+	// attempted and failed - not "nobody bothered to check" - and is
+	// refused the same as an explicitly untrusted Identity. Only when no
+	// resolver is configured at all does a nil Identity mean the Trusted
+	// gate doesn't apply.
+	if f.resolver != nil && !f.allowUntrustedWrites {
+		if memCtx.Identity == nil {
+			logging.Warnf("Memory: refusing to auto-store memory for user %s: no identity could be resolved for this request and AllowUntrustedWrites is not set",
+				memCtx.Context.UserID)
+			return nil
+		}
+		if !memCtx.Identity.Trusted {
+			logging.Warnf("Memory: refusing to auto-store memory for user %s: identity resolved by %q is untrusted and AllowUntrustedWrites is not set",
+				memCtx.Context.UserID, memCtx.Identity.ResolvedBy)
+			return nil
+		}
+	}
+
 	// Parse the response to get the assistant's reply
 	var resp map[string]interface{}
 	if err := json.Unmarshal(responseBody, &resp); err != nil {
@@ -244,22 +657,233 @@ func (f *MemoryFilter) StoreFromResponse(ctx context.Context, memCtx *MemoryFilt
 		Content:   fmt.Sprintf("User asked: %s\n\nAssistant replied: %s", userQuery, truncateContent(content, 1000)),
 		UserID:    memCtx.Context.UserID,
 		ProjectID: memCtx.Context.ProjectID,
+		BranchID:  memCtx.Context.BranchID,
 		Metadata: map[string]interface{}{
 			"source": "auto_store",
 		},
 		Importance: 0.5, // Default importance for auto-stored memories
 	}
+	if responseID, ok := resp["id"].(string); ok && responseID != "" {
+		mem.Metadata["response_id"] = responseID
+	}
+
+	// If this turn edits and re-sends a prior turn (see
+	// responseapi.MemoryContext.ParentResponseID), find the memory that
+	// prior turn produced so it can be marked superseded below, once this
+	// one has an ID to supersede it with.
+	var toSupersede *memory.Memory
+	if memCtx.Context.ParentResponseID != "" {
+		toSupersede = f.findMemoryForResponse(ctx, memCtx, memCtx.Context.ParentResponseID)
+		if toSupersede != nil {
+			mem.ParentMemoryID = toSupersede.ID
+		}
+	}
 
-	if err := f.store.Store(ctx, mem); err != nil {
+	if err := f.enforceQuota(ctx, mem); err != nil {
+		return err
+	}
+
+	storeStart := time.Now()
+	err := f.store.Store(ctx, mem)
+	memCtx.Timings.StoreMs = time.Since(storeStart).Milliseconds()
+	metrics.RecordMemoryStageDuration("store", memCtx.Timings.StoreMs)
+	if err != nil {
 		logging.Warnf("Memory: failed to auto-store memory: %v", err)
 		return err
 	}
 
+	if toSupersede != nil {
+		superseded := *toSupersede
+		superseded.SupersededBy = mem.ID
+		if err := f.store.Update(ctx, toSupersede.ID, &superseded); err != nil {
+			logging.Warnf("Memory: failed to mark memory %s superseded by %s: %v", toSupersede.ID, mem.ID, err)
+		} else {
+			logging.Infof("Memory: marked memory %s superseded by %s (branch edit-and-resend)", toSupersede.ID, mem.ID)
+		}
+	}
+
+	if f.usage != nil {
+		f.usage.record(mem.UserID, memoryUsageEntry{
+			id:          mem.ID,
+			bytes:       int64(len(mem.Content)),
+			importance:  mem.Importance,
+			accessCount: mem.AccessCount,
+			accessedAt:  time.Now(),
+		})
+	}
+
+	if f.audit != nil {
+		if _, err := f.audit.Append(memory.AuditOpStore, mem.UserID, mem.ProjectID, mem.ID, callerIdentity(memCtx)); err != nil {
+			logging.Warnf("Memory: failed to append audit log entry for store: %v", err)
+		}
+	}
+
 	memCtx.StoredMemoryID = mem.ID
 	logging.Infof("Memory: Auto-stored conversation as episodic memory %s for user %s", mem.ID, memCtx.Context.UserID)
 	return nil
 }
 
+// findMemoryForResponse returns the live (non-superseded) memory in
+// memCtx's scope and branch whose Metadata["response_id"] matches
+// responseID - the memory StoreFromResponse created from that earlier turn
+// - or nil if none is found, responseID is empty, or the store doesn't
+// implement memory.StoreSync (the same optional-capability fallback
+// SuggestStarters uses to enumerate a user's memories).
+//
+// Matching is scoped to memCtx.Context.BranchID, not just UserID/ProjectID:
+// ForkBranch can copy the same response_id into several branches, and an
+// edit-and-resend on one branch must never supersede another branch's copy.
+func (f *MemoryFilter) findMemoryForResponse(ctx context.Context, memCtx *MemoryFilterContext, responseID string) *memory.Memory {
+	syncStore, ok := f.store.(memory.StoreSync)
+	if !ok {
+		return nil
+	}
+	candidates, err := syncStore.UpdatedSince(ctx, memory.MemoryScope{
+		UserID:    memCtx.Context.UserID,
+		ProjectID: memCtx.Context.ProjectID,
+	}, time.Time{})
+	if err != nil {
+		logging.Warnf("Memory: failed to look up prior memory for response %s: %v", responseID, err)
+		return nil
+	}
+	for _, candidate := range candidates {
+		if candidate.SupersededBy != "" {
+			continue
+		}
+		if candidate.BranchID != memCtx.Context.BranchID {
+			continue
+		}
+		if respID, _ := candidate.Metadata["response_id"].(string); respID == responseID {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// ForkBranch copies every live (non-superseded) mainline memory in (userID,
+// projectID)'s scope into a new branch and returns the branch's ID, so a
+// caller exploring an edited-and-resent turn (see
+// responseapi.MemoryContext.BranchID/ParentResponseID) can retrieve and
+// build on that branch's own copies without mutating, or being polluted
+// by, the mainline's memories going forward. Copies carry ParentMemoryID
+// pointing at the mainline memory they were copied from; they are not
+// marked SupersededBy anything, and RetrieveOptions.BranchID defaults to
+// mainline-only, so neither the mainline's nor another branch's Retrieve
+// results are affected by forking.
+//
+// Requires the store to implement memory.StoreSync to enumerate the
+// scope's memories (the same optional-capability fallback SuggestStarters
+// and findMemoryForResponse use); returns an error otherwise. If a copy
+// fails partway through, ForkBranch deletes the copies it already made
+// rather than leaving an orphaned, unreferenced branchID behind.
+func (f *MemoryFilter) ForkBranch(ctx context.Context, userID, projectID string) (string, error) {
+	if !f.enabled {
+		return "", fmt.Errorf("memory: MemoryFilter is disabled")
+	}
+	if userID == "" {
+		return "", fmt.Errorf("memory: ForkBranch requires a non-empty userID")
+	}
+
+	syncStore, ok := f.store.(memory.StoreSync)
+	if !ok {
+		return "", fmt.Errorf("memory: ForkBranch: store %T does not support enumeration (memory.StoreSync)", f.store)
+	}
+
+	source, err := syncStore.UpdatedSince(ctx, memory.MemoryScope{UserID: userID, ProjectID: projectID}, time.Time{})
+	if err != nil {
+		return "", fmt.Errorf("memory: ForkBranch: fetching memories for user %s: %w", userID, err)
+	}
+
+	branchID := "branch_" + uuid.New().String()[:8]
+	var copiedIDs []string
+	for _, mem := range source {
+		if mem.SupersededBy != "" || mem.BranchID != "" {
+			continue
+		}
+		branchMem := *mem
+		branchMem.ID = ""
+		branchMem.BranchID = branchID
+		branchMem.ParentMemoryID = mem.ID
+		branchMem.SupersededBy = ""
+		branchMem.Version = 0
+		if err := f.store.Store(ctx, &branchMem); err != nil {
+			if len(copiedIDs) > 0 {
+				if delErr := f.store.BulkDelete(ctx, copiedIDs); delErr != nil {
+					logging.Warnf("Memory: ForkBranch: failed to roll back partial branch %s after a copy error: %v", branchID, delErr)
+				}
+			}
+			return "", fmt.Errorf("memory: ForkBranch: copying memory %s into branch %s: %w", mem.ID, branchID, err)
+		}
+		copiedIDs = append(copiedIDs, branchMem.ID)
+	}
+
+	if f.audit != nil {
+		if _, err := f.audit.Append(memory.AuditOpStore, userID, projectID, branchID, ""); err != nil {
+			logging.Warnf("Memory: failed to append audit log entry for ForkBranch: %v", err)
+		}
+	}
+
+	logging.Infof("Memory: forked %d memories for user %s into branch %s", len(source), userID, branchID)
+	return branchID, nil
+}
+
+// enforceQuota applies f.quota to mem before it is stored: it throttles
+// writes via f.writeLimiter and, if mem would push its user over
+// MaxMemoriesPerUser/MaxBytesPerUser, evicts existing memories under the
+// configured EvictionPolicy to make room. It returns a *memory.Error with
+// CodeResourceExhausted (HTTP 429 via memory.HTTPStatus) if the write
+// should be refused. A MemoryFilter with no quota configured (the default)
+// always returns nil.
+func (f *MemoryFilter) enforceQuota(ctx context.Context, mem *memory.Memory) error {
+	if f.usage == nil {
+		return nil
+	}
+
+	if f.writeLimiter != nil {
+		decision, err := f.writeLimiter.Check(ratelimit.Context{UserID: mem.UserID})
+		if err != nil {
+			return fmt.Errorf("memory: write rate limit check for user %s: %w", mem.UserID, err)
+		}
+		if !decision.Allowed {
+			return memory.NewError(memory.CodeResourceExhausted, "Store", "",
+				fmt.Errorf("write rate limit exceeded for user %s (retry after %s)", mem.UserID, decision.RetryAfter))
+		}
+	}
+
+	newBytes := int64(len(mem.Content))
+	count, bytes, err := f.usage.makeRoom(ctx, f.store, mem.UserID, newBytes, f.quota, f.audit)
+	if err != nil {
+		return fmt.Errorf("memory: enforcing quota for user %s: %w", mem.UserID, err)
+	}
+
+	if f.quota.MaxMemoriesPerUser > 0 && count+1 > f.quota.MaxMemoriesPerUser {
+		return memory.NewError(memory.CodeResourceExhausted, "Store", "",
+			fmt.Errorf("user %s is at its memory quota (%d)", mem.UserID, f.quota.MaxMemoriesPerUser))
+	}
+	if f.quota.MaxBytesPerUser > 0 && bytes+newBytes > f.quota.MaxBytesPerUser {
+		return memory.NewError(memory.CodeResourceExhausted, "Store", "",
+			fmt.Errorf("user %s is at its memory byte quota (%d bytes)", mem.UserID, f.quota.MaxBytesPerUser))
+	}
+	return nil
+}
+
+// UsageForUser returns userID's tracked memory count and total content
+// bytes, for observability (e.g. an admin/metrics endpoint). Both are zero
+// if quota tracking isn't enabled (see WithQuota) or userID has no tracked
+// memories.
+func (f *MemoryFilter) UsageForUser(userID string) (count int, bytes int64) {
+	if f.usage == nil {
+		return 0, 0
+	}
+	return f.usage.Usage(userID)
+}
+
+// Audit returns the AuditLog this filter records to (see WithAuditLog), or
+// nil if audit logging isn't enabled.
+func (f *MemoryFilter) Audit() *memory.AuditLog {
+	return f.audit
+}
+
 // BuildMemoryOperations creates the MemoryOperations response field from the filter context.
 func (f *MemoryFilter) BuildMemoryOperations(memCtx *MemoryFilterContext) *responseapi.MemoryOperations {
 	if memCtx == nil || !memCtx.Enabled {
@@ -291,6 +915,16 @@ func (f *MemoryFilter) BuildMemoryOperations(memCtx *MemoryFilterContext) *respo
 		return nil
 	}
 
+	ops.Timings = responseapi.MemoryTimings{
+		EmbedMs:    memCtx.Timings.EmbedMs,
+		RetrieveMs: memCtx.Timings.RetrieveMs,
+		RerankMs:   memCtx.Timings.RerankMs,
+		InjectMs:   memCtx.Timings.InjectMs,
+		StoreMs:    memCtx.Timings.StoreMs,
+	}
+	ops.CacheHit = memCtx.Timings.CacheHit
+	ops.CandidatesScanned = memCtx.Timings.CandidatesScanned
+
 	return ops
 }
 
@@ -4,7 +4,11 @@ package extproc
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/agents"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/responseapi"
 )
 
@@ -13,16 +17,76 @@ type MemoryFilter struct {
 	enabled bool
 }
 
-// NewMemoryFilter creates a disabled memory filter for non-CGO builds.
-func NewMemoryFilter(store interface{}) *MemoryFilter {
+// MemoryFilterOption is a stub for non-CGO builds.
+type MemoryFilterOption func(*MemoryFilter)
+
+// WithAllowUntrustedWrites is a no-op for non-CGO builds.
+func WithAllowUntrustedWrites(allow bool) MemoryFilterOption {
+	return func(f *MemoryFilter) {}
+}
+
+// WithQuota is a no-op for non-CGO builds.
+func WithQuota(cfg memory.QuotaConfig) MemoryFilterOption {
+	return func(f *MemoryFilter) {}
+}
+
+// WithAuditLog is a no-op for non-CGO builds.
+func WithAuditLog(log *memory.AuditLog) MemoryFilterOption {
+	return func(f *MemoryFilter) {}
+}
+
+// WithTools is a no-op for non-CGO builds.
+func WithTools(allowWrites bool) MemoryFilterOption {
+	return func(f *MemoryFilter) {}
+}
+
+// WithAgents is a no-op for non-CGO builds.
+func WithAgents(registry *agents.Registry) MemoryFilterOption {
+	return func(f *MemoryFilter) {}
+}
+
+// StarterSuggestion mirrors the CGO build's suggestion shape.
+type StarterSuggestion struct {
+	Prompt        string   `json:"prompt"`
+	SeedMemoryIDs []string `json:"seed_memory_ids"`
+}
+
+// StarterSynthesizer mirrors the CGO build's synthesizer function type.
+type StarterSynthesizer func(ctx context.Context, memType memory.MemoryType, contents []string, limit int) ([]string, error)
+
+// WithStarterSynthesizer is a no-op for non-CGO builds.
+func WithStarterSynthesizer(fn StarterSynthesizer) MemoryFilterOption {
+	return func(f *MemoryFilter) {}
+}
+
+// NewMemoryFilter creates a disabled memory filter for non-CGO builds. It
+// keeps the resolver/opts parameters of the CGO constructor so callers
+// don't need a build-tag-specific call site.
+func NewMemoryFilter(store interface{}, resolver interface{}, opts ...MemoryFilterOption) *MemoryFilter {
 	return &MemoryFilter{enabled: false}
 }
 
+// ResolveIdentity is a no-op for non-CGO builds.
+func (f *MemoryFilter) ResolveIdentity(reqCtx *RequestContext) (*Identity, error) {
+	return nil, fmt.Errorf("memory: MemoryFilter is disabled in this build")
+}
+
 // IsEnabled returns false for non-CGO builds.
 func (f *MemoryFilter) IsEnabled() bool {
 	return false
 }
 
+// MemoryTimings mirrors the CGO build's per-phase latency shape.
+type MemoryTimings struct {
+	EmbedMs           int64
+	RetrieveMs        int64
+	RerankMs          int64
+	InjectMs          int64
+	StoreMs           int64
+	CacheHit          bool
+	CandidatesScanned int
+}
+
 // MemoryFilterContext is a stub for non-CGO builds.
 type MemoryFilterContext struct {
 	Enabled           bool
@@ -30,10 +94,17 @@ type MemoryFilterContext struct {
 	StoredMemoryID    string
 	InjectedContext   string
 	RetrievedMemories []interface{}
+	Agent             *agents.Agent
+	Timings           MemoryTimings
 }
 
 // ProcessResponseAPIRequest is a no-op for non-CGO builds.
-func (f *MemoryFilter) ProcessResponseAPIRequest(ctx context.Context, req *responseapi.ResponseAPIRequest, userQuery string) (*MemoryFilterContext, error) {
+func (f *MemoryFilter) ProcessResponseAPIRequest(ctx context.Context, reqCtx *RequestContext, req *responseapi.ResponseAPIRequest, userQuery string) (*MemoryFilterContext, error) {
+	return nil, nil
+}
+
+// ProcessResponseAPIRequestForAgent is a no-op for non-CGO builds.
+func (f *MemoryFilter) ProcessResponseAPIRequestForAgent(ctx context.Context, reqCtx *RequestContext, req *responseapi.ResponseAPIRequest, userQuery string, agentName string) (*MemoryFilterContext, error) {
 	return nil, nil
 }
 
@@ -42,11 +113,33 @@ func (f *MemoryFilter) InjectMemoryContext(body []byte, memCtx *MemoryFilterCont
 	return body, nil
 }
 
+// AdvertiseTools is a no-op for non-CGO builds.
+func (f *MemoryFilter) AdvertiseTools(body []byte, memCtx *MemoryFilterContext) ([]byte, error) {
+	return body, nil
+}
+
+// toolCallMessage mirrors the CGO build's role:"tool" message shape.
+type toolCallMessage struct {
+	Role       string `json:"role"`
+	ToolCallID string `json:"tool_call_id"`
+	Content    string `json:"content"`
+}
+
+// ExecuteToolCalls is a no-op for non-CGO builds.
+func (f *MemoryFilter) ExecuteToolCalls(ctx context.Context, memCtx *MemoryFilterContext, responseBody []byte) ([]toolCallMessage, error) {
+	return nil, nil
+}
+
 // StoreFromResponse is a no-op for non-CGO builds.
 func (f *MemoryFilter) StoreFromResponse(ctx context.Context, memCtx *MemoryFilterContext, responseBody []byte, userQuery string) error {
 	return nil
 }
 
+// ForkBranch is a no-op for non-CGO builds.
+func (f *MemoryFilter) ForkBranch(ctx context.Context, userID, projectID string) (string, error) {
+	return "", fmt.Errorf("memory: MemoryFilter is disabled in this build")
+}
+
 // BuildMemoryOperations is a no-op for non-CGO builds.
 func (f *MemoryFilter) BuildMemoryOperations(memCtx *MemoryFilterContext) *responseapi.MemoryOperations {
 	return nil
@@ -56,3 +149,21 @@ func (f *MemoryFilter) BuildMemoryOperations(memCtx *MemoryFilterContext) *respo
 func (f *MemoryFilter) Close() error {
 	return nil
 }
+
+// UsageForUser always returns zero for non-CGO builds.
+func (f *MemoryFilter) UsageForUser(userID string) (count int, bytes int64) {
+	return 0, 0
+}
+
+// SuggestStarters is a no-op for non-CGO builds.
+func (f *MemoryFilter) SuggestStarters(ctx context.Context, userID, projectID string, limit int) ([]StarterSuggestion, error) {
+	return nil, nil
+}
+
+// SuggestionsHandler returns a handler that always responds 503 for
+// non-CGO builds, since memory is disabled in this build.
+func (f *MemoryFilter) SuggestionsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "memory is disabled in this build", http.StatusServiceUnavailable)
+	})
+}
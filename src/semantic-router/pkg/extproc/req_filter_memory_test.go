@@ -3,21 +3,25 @@ package extproc
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/agents"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/responseapi"
 )
 
 func TestMemoryFilter_ProcessResponseAPIRequest_Disabled(t *testing.T) {
 	// Create filter without a store (disabled)
-	filter := NewMemoryFilter(nil)
+	filter := NewMemoryFilter(nil, nil)
 
 	if filter.IsEnabled() {
 		t.Error("Expected filter to be disabled when store is nil")
 	}
 
 	req := &responseapi.ResponseAPIRequest{}
-	memCtx, err := filter.ProcessResponseAPIRequest(context.Background(), req, "test query")
+	memCtx, err := filter.ProcessResponseAPIRequest(context.Background(), nil, req, "test query")
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
@@ -27,7 +31,7 @@ func TestMemoryFilter_ProcessResponseAPIRequest_Disabled(t *testing.T) {
 }
 
 func TestMemoryFilter_InjectMemoryContext_NilContext(t *testing.T) {
-	filter := NewMemoryFilter(nil)
+	filter := NewMemoryFilter(nil, nil)
 
 	body := []byte(`{"messages": [{"role": "user", "content": "hello"}]}`)
 	result, err := filter.InjectMemoryContext(body, nil)
@@ -43,7 +47,7 @@ func TestMemoryFilter_InjectMemoryContext_NilContext(t *testing.T) {
 }
 
 func TestMemoryFilter_InjectMemoryContext_EmptyContext(t *testing.T) {
-	filter := NewMemoryFilter(nil)
+	filter := NewMemoryFilter(nil, nil)
 
 	body := []byte(`{"messages": [{"role": "user", "content": "hello"}]}`)
 	memCtx := &MemoryFilterContext{
@@ -186,3 +190,370 @@ func TestMemoryFilter_BuildMemoryOperations(t *testing.T) {
 		t.Error("Expected nil operations when no memories retrieved or stored")
 	}
 }
+
+func TestMemoryFilter_ProcessResponseAPIRequestForAgent_AppliesAgentOverrides(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryStore()
+
+	if err := store.Store(ctx, &memory.Memory{
+		Type:      memory.MemoryTypeProcedural,
+		Content:   "To deploy: run npm build then docker push",
+		UserID:    "user_1",
+		ProjectID: "proj_ops",
+	}); err != nil {
+		t.Fatalf("seed procedural memory: %v", err)
+	}
+	if err := store.Store(ctx, &memory.Memory{
+		Type:      memory.MemoryTypeSemantic,
+		Content:   "User prefers to deploy on Fridays",
+		UserID:    "user_1",
+		ProjectID: "proj_ops",
+	}); err != nil {
+		t.Fatalf("seed semantic memory: %v", err)
+	}
+	if err := store.Store(ctx, &memory.Memory{
+		Type:      memory.MemoryTypeProcedural,
+		Content:   "To deploy the other project: run make release",
+		UserID:    "user_1",
+		ProjectID: "proj_other",
+	}); err != nil {
+		t.Fatalf("seed other-project memory: %v", err)
+	}
+
+	codingAssistant := &agents.Agent{
+		Name:               "coding-assistant",
+		AllowedMemoryTypes: []string{"procedural"},
+		ProjectIDOverride:  "proj_ops",
+		PromptTemplate:     "Runbook notes:{{range .Memories}} {{.Content}};{{end}}",
+	}
+	registry := agents.NewRegistry(codingAssistant)
+	filter := NewMemoryFilter(store, nil, WithAgents(registry))
+
+	req := &responseapi.ResponseAPIRequest{
+		MemoryConfig:  &responseapi.MemoryConfig{Enabled: true},
+		MemoryContext: &responseapi.MemoryContext{UserID: "user_1", ProjectID: "proj_other"},
+	}
+
+	memCtx, err := filter.ProcessResponseAPIRequestForAgent(ctx, nil, req, "deploy", "coding-assistant")
+	if err != nil {
+		t.Fatalf("ProcessResponseAPIRequestForAgent: %v", err)
+	}
+	if memCtx == nil {
+		t.Fatal("expected a non-nil MemoryFilterContext")
+	}
+	if memCtx.Agent != codingAssistant {
+		t.Fatalf("memCtx.Agent = %v, want %v", memCtx.Agent, codingAssistant)
+	}
+	if memCtx.Context.ProjectID != "proj_ops" {
+		t.Fatalf("ProjectIDOverride was not applied: memCtx.Context.ProjectID = %q", memCtx.Context.ProjectID)
+	}
+	if len(memCtx.RetrievedMemories) != 1 {
+		t.Fatalf("expected exactly 1 retrieved memory (procedural, proj_ops), got %d", len(memCtx.RetrievedMemories))
+	}
+	if memCtx.RetrievedMemories[0].Memory.Type != memory.MemoryTypeProcedural {
+		t.Errorf("expected the retrieved memory to be procedural, got %s", memCtx.RetrievedMemories[0].Memory.Type)
+	}
+	if !strings.HasPrefix(memCtx.InjectedContext, "Runbook notes:") {
+		t.Errorf("expected InjectedContext to use the agent's PromptTemplate, got %q", memCtx.InjectedContext)
+	}
+}
+
+func TestMemoryFilter_ProcessResponseAPIRequestForAgent_UnknownAgentBehavesLikeNoAgent(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryStore()
+	filter := NewMemoryFilter(store, nil, WithAgents(agents.NewRegistry(&agents.Agent{Name: "other-agent"})))
+
+	req := &responseapi.ResponseAPIRequest{
+		MemoryConfig:  &responseapi.MemoryConfig{Enabled: true},
+		MemoryContext: &responseapi.MemoryContext{UserID: "user_1"},
+	}
+
+	memCtx, err := filter.ProcessResponseAPIRequestForAgent(ctx, nil, req, "deploy", "no-such-agent")
+	if err != nil {
+		t.Fatalf("ProcessResponseAPIRequestForAgent: %v", err)
+	}
+	if memCtx.Agent != nil {
+		t.Errorf("expected Agent to be nil for an unresolvable agent name, got %v", memCtx.Agent)
+	}
+}
+
+func newResponseBody(content string) []byte {
+	body, _ := json.Marshal(map[string]interface{}{
+		"choices": []interface{}{
+			map[string]interface{}{
+				"message": map[string]interface{}{"content": content},
+			},
+		},
+	})
+	return body
+}
+
+func TestMemoryFilter_StoreFromResponse_QuotaEvictsOldest(t *testing.T) {
+	store := memory.NewInMemoryStore()
+	filter := NewMemoryFilter(store, nil, WithQuota(memory.QuotaConfig{
+		MaxMemoriesPerUser: 1,
+		EvictionPolicy:     memory.EvictionPolicyLRU,
+	}))
+
+	for i := 0; i < 2; i++ {
+		memCtx := &MemoryFilterContext{
+			Enabled:     true,
+			ShouldStore: true,
+			Context:     &responseapi.MemoryContext{UserID: "user_1"},
+		}
+		if err := filter.StoreFromResponse(context.Background(), memCtx, newResponseBody("reply"), "query"); err != nil {
+			t.Fatalf("StoreFromResponse #%d: %v", i, err)
+		}
+	}
+
+	count, _ := filter.UsageForUser("user_1")
+	if count != 1 {
+		t.Fatalf("UsageForUser count = %d, want 1 (eviction should have kept it at quota)", count)
+	}
+}
+
+func TestMemoryFilter_StoreFromResponse_WriteRateLimited(t *testing.T) {
+	store := memory.NewInMemoryStore()
+	filter := NewMemoryFilter(store, nil, WithQuota(memory.QuotaConfig{MaxWritesPerSecond: 1}))
+
+	memCtx := func() *MemoryFilterContext {
+		return &MemoryFilterContext{
+			Enabled:     true,
+			ShouldStore: true,
+			Context:     &responseapi.MemoryContext{UserID: "user_1"},
+		}
+	}
+
+	if err := filter.StoreFromResponse(context.Background(), memCtx(), newResponseBody("reply"), "query"); err != nil {
+		t.Fatalf("first StoreFromResponse: %v", err)
+	}
+
+	err := filter.StoreFromResponse(context.Background(), memCtx(), newResponseBody("reply"), "query")
+	if !memory.IsCode(err, memory.CodeResourceExhausted) {
+		t.Fatalf("second StoreFromResponse: got %v, want a CodeResourceExhausted error", err)
+	}
+	if memory.HTTPStatus(err) != http.StatusTooManyRequests {
+		t.Fatalf("HTTPStatus(err) = %d, want %d", memory.HTTPStatus(err), http.StatusTooManyRequests)
+	}
+}
+
+func TestMemoryFilter_StoreFromResponse_RefusesWhenResolverFailsToProduceIdentity(t *testing.T) {
+	store := memory.NewInMemoryStore()
+	resolver := HeaderResolver{HeaderName: "x-authz-user-id", Trusted: true}
+	// WithQuota turns on the usage tracking UsageForUser reports below.
+	filter := NewMemoryFilter(store, resolver, WithQuota(memory.QuotaConfig{MaxMemoriesPerUser: 10}))
+
+	req := &responseapi.ResponseAPIRequest{
+		MemoryConfig:  &responseapi.MemoryConfig{Enabled: true, AutoStore: true},
+		MemoryContext: &responseapi.MemoryContext{UserID: "user_1"},
+	}
+	// No x-authz-user-id header, so the resolver can't produce an Identity.
+	reqCtx := &RequestContext{Headers: map[string]string{}}
+
+	memCtx, err := filter.ProcessResponseAPIRequestForAgent(context.Background(), reqCtx, req, "query", "")
+	if err != nil {
+		t.Fatalf("ProcessResponseAPIRequestForAgent: %v", err)
+	}
+	if memCtx.Identity != nil {
+		t.Fatalf("expected no Identity to be attached, got %+v", memCtx.Identity)
+	}
+
+	if err := filter.StoreFromResponse(context.Background(), memCtx, newResponseBody("reply"), "query"); err != nil {
+		t.Fatalf("StoreFromResponse: %v", err)
+	}
+	if count, _ := filter.UsageForUser("user_1"); count != 0 {
+		t.Fatalf("UsageForUser count = %d, want 0: a configured resolver producing no Identity must refuse the write, not silently skip the check", count)
+	}
+}
+
+func TestMemoryFilter_StoreFromResponse_StoresWhenResolverProducesTrustedIdentity(t *testing.T) {
+	store := memory.NewInMemoryStore()
+	resolver := HeaderResolver{HeaderName: "x-authz-user-id", Trusted: true}
+	// WithQuota turns on the usage tracking UsageForUser reports below.
+	filter := NewMemoryFilter(store, resolver, WithQuota(memory.QuotaConfig{MaxMemoriesPerUser: 10}))
+
+	req := &responseapi.ResponseAPIRequest{
+		MemoryConfig:  &responseapi.MemoryConfig{Enabled: true, AutoStore: true},
+		MemoryContext: &responseapi.MemoryContext{UserID: "user_1"},
+	}
+	reqCtx := &RequestContext{Headers: map[string]string{"x-authz-user-id": "user_1"}}
+
+	memCtx, err := filter.ProcessResponseAPIRequestForAgent(context.Background(), reqCtx, req, "query", "")
+	if err != nil {
+		t.Fatalf("ProcessResponseAPIRequestForAgent: %v", err)
+	}
+	if memCtx.Identity == nil || !memCtx.Identity.Trusted {
+		t.Fatalf("expected a Trusted Identity to be attached, got %+v", memCtx.Identity)
+	}
+
+	if err := filter.StoreFromResponse(context.Background(), memCtx, newResponseBody("reply"), "query"); err != nil {
+		t.Fatalf("StoreFromResponse: %v", err)
+	}
+	if count, _ := filter.UsageForUser("user_1"); count != 1 {
+		t.Fatalf("UsageForUser count = %d, want 1: a Trusted resolved Identity must allow the auto-store write", count)
+	}
+}
+
+func TestMemoryFilter_ProcessResponseAPIRequestForAgent_RecordsRetrieveTiming(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryStore()
+	if err := store.Store(ctx, &memory.Memory{
+		Type: memory.MemoryTypeSemantic, Content: "User prefers tea over coffee", UserID: "user_1",
+	}); err != nil {
+		t.Fatalf("seed memory: %v", err)
+	}
+
+	filter := NewMemoryFilter(store, nil)
+	req := &responseapi.ResponseAPIRequest{
+		MemoryConfig:  &responseapi.MemoryConfig{Enabled: true},
+		MemoryContext: &responseapi.MemoryContext{UserID: "user_1"},
+	}
+
+	memCtx, err := filter.ProcessResponseAPIRequestForAgent(ctx, nil, req, "tea", "")
+	if err != nil {
+		t.Fatalf("ProcessResponseAPIRequestForAgent: %v", err)
+	}
+	if memCtx.Timings.RetrieveMs < 0 {
+		t.Fatalf("RetrieveMs = %d, want >= 0", memCtx.Timings.RetrieveMs)
+	}
+
+	body := []byte(`{"messages": [{"role": "user", "content": "hello"}]}`)
+	if _, err := filter.InjectMemoryContext(body, memCtx); err != nil {
+		t.Fatalf("InjectMemoryContext: %v", err)
+	}
+	if memCtx.Timings.InjectMs < 0 {
+		t.Fatalf("InjectMs = %d, want >= 0", memCtx.Timings.InjectMs)
+	}
+
+	ops := filter.BuildMemoryOperations(memCtx)
+	if ops == nil {
+		t.Fatal("expected non-nil MemoryOperations (a memory was retrieved)")
+	}
+	if ops.Timings.RetrieveMs != memCtx.Timings.RetrieveMs {
+		t.Errorf("ops.Timings.RetrieveMs = %d, want %d", ops.Timings.RetrieveMs, memCtx.Timings.RetrieveMs)
+	}
+	if ops.Timings.InjectMs != memCtx.Timings.InjectMs {
+		t.Errorf("ops.Timings.InjectMs = %d, want %d", ops.Timings.InjectMs, memCtx.Timings.InjectMs)
+	}
+}
+
+// newResponseBodyWithID is newResponseBody with a top-level response "id",
+// the field StoreFromResponse stamps onto the stored memory's
+// Metadata["response_id"] so a later edit-and-resend of the same turn can
+// find and supersede it.
+func newResponseBodyWithID(id, content string) []byte {
+	body, _ := json.Marshal(map[string]interface{}{
+		"id": id,
+		"choices": []interface{}{
+			map[string]interface{}{
+				"message": map[string]interface{}{"content": content},
+			},
+		},
+	})
+	return body
+}
+
+func TestMemoryFilter_StoreFromResponse_SupersedesPriorTurnOnBranch(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryStore()
+	filter := NewMemoryFilter(store, nil)
+
+	memCtx := &MemoryFilterContext{
+		Enabled:     true,
+		ShouldStore: true,
+		Context:     &responseapi.MemoryContext{UserID: "user_1"},
+	}
+	if err := filter.StoreFromResponse(ctx, memCtx, newResponseBodyWithID("resp_1", "original reply"), "original query"); err != nil {
+		t.Fatalf("first StoreFromResponse: %v", err)
+	}
+	originalID := memCtx.StoredMemoryID
+
+	// Simulate the user editing and re-sending the turn that produced
+	// resp_1 in place (no named branch - see
+	// TestMemoryFilter_ForkBranch_CopiesLiveMemoriesIntoNewBranch for the
+	// named-branch case): the new MemoryContext points ParentResponseID at
+	// it.
+	branchCtx := &MemoryFilterContext{
+		Enabled:     true,
+		ShouldStore: true,
+		Context:     &responseapi.MemoryContext{UserID: "user_1", ParentResponseID: "resp_1"},
+	}
+	if err := filter.StoreFromResponse(ctx, branchCtx, newResponseBodyWithID("resp_2", "edited reply"), "edited query"); err != nil {
+		t.Fatalf("second StoreFromResponse: %v", err)
+	}
+
+	original, err := store.Get(ctx, originalID)
+	if err != nil {
+		t.Fatalf("Get original memory: %v", err)
+	}
+	if original.SupersededBy != branchCtx.StoredMemoryID {
+		t.Errorf("original.SupersededBy = %q, want %q", original.SupersededBy, branchCtx.StoredMemoryID)
+	}
+
+	branched, err := store.Get(ctx, branchCtx.StoredMemoryID)
+	if err != nil {
+		t.Fatalf("Get branched memory: %v", err)
+	}
+	if branched.ParentMemoryID != originalID {
+		t.Errorf("branched.ParentMemoryID = %q, want %q", branched.ParentMemoryID, originalID)
+	}
+
+	results, err := store.Retrieve(ctx, memory.RetrieveOptions{Query: "reply", UserID: "user_1", Limit: 5})
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if len(results) != 1 || results[0].Memory.ID != branchCtx.StoredMemoryID {
+		t.Fatalf("expected only the branched memory to be retrievable by default, got %+v", results)
+	}
+}
+
+func TestMemoryFilter_ForkBranch_CopiesLiveMemoriesIntoNewBranch(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryStore()
+	if err := store.Store(ctx, &memory.Memory{
+		Type: memory.MemoryTypeSemantic, Content: "User prefers tea over coffee", UserID: "user_1",
+	}); err != nil {
+		t.Fatalf("seed memory: %v", err)
+	}
+
+	filter := NewMemoryFilter(store, nil)
+	branchID, err := filter.ForkBranch(ctx, "user_1", "")
+	if err != nil {
+		t.Fatalf("ForkBranch: %v", err)
+	}
+	if branchID == "" {
+		t.Fatal("expected a non-empty branch ID")
+	}
+
+	// A plain mainline Retrieve (BranchID == "") must not see the branch
+	// copy - otherwise forking would reintroduce the near-duplicate
+	// problem ForkBranch exists to avoid.
+	mainline, err := store.Retrieve(ctx, memory.RetrieveOptions{Query: "tea", UserID: "user_1", Limit: 5})
+	if err != nil {
+		t.Fatalf("Retrieve mainline: %v", err)
+	}
+	if len(mainline) != 1 {
+		t.Fatalf("expected only the mainline memory to be retrievable by default, got %d", len(mainline))
+	}
+
+	branched, err := store.Retrieve(ctx, memory.RetrieveOptions{Query: "tea", UserID: "user_1", Limit: 5, BranchID: branchID})
+	if err != nil {
+		t.Fatalf("Retrieve branch: %v", err)
+	}
+	if len(branched) != 1 {
+		t.Fatalf("expected exactly one result scoped to the new branch, got %d", len(branched))
+	}
+	if branched[0].Memory.BranchID != branchID {
+		t.Errorf("branched result BranchID = %q, want %q", branched[0].Memory.BranchID, branchID)
+	}
+	if branched[0].Memory.ParentMemoryID == "" {
+		t.Error("expected the branch copy to carry a ParentMemoryID")
+	}
+}
+
+func TestMemoryFilter_ForkBranch_RequiresUserID(t *testing.T) {
+	filter := NewMemoryFilter(memory.NewInMemoryStore(), nil)
+	if _, err := filter.ForkBranch(context.Background(), "", ""); err == nil {
+		t.Fatal("expected an error for an empty userID")
+	}
+}
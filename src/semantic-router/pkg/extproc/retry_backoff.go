@@ -0,0 +1,70 @@
+package extproc
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultRetryBaseBackoff and defaultRetryMaxBackoff are the base and
+	// cap for full-jitter exponential backoff between retry attempts.
+	defaultRetryBaseBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff  = 2 * time.Second
+	// defaultMaxRetryAttempts is how many times a failed upstream call is
+	// retried before giving up, absent a per-request override.
+	defaultMaxRetryAttempts = 3
+)
+
+// backoffWithFullJitter computes a randomized backoff duration for the
+// given zero-based attempt number using the standard full-jitter formula
+// (sleep = rand(0, min(cap, base*2^attempt))), which spreads out retries
+// from many concurrent callers better than a fixed or capped-exponential
+// delay would.
+func backoffWithFullJitter(base, capDuration time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		base = defaultRetryBaseBackoff
+	}
+	if capDuration <= 0 {
+		capDuration = defaultRetryMaxBackoff
+	}
+
+	upper := base
+	for i := 0; i < attempt; i++ {
+		if upper >= capDuration {
+			upper = capDuration
+			break
+		}
+		upper *= 2
+	}
+	if upper > capDuration {
+		upper = capDuration
+	}
+	if upper <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(upper)))
+}
+
+// parseRetryAfter parses an HTTP Retry-After header value (either a
+// delta-seconds integer or an HTTP-date) relative to now, returning the
+// wait duration and true if the header was understood.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if d := when.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,61 @@
+package extproc
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffWithFullJitter_BoundedByCap(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		d := backoffWithFullJitter(100*time.Millisecond, 2*time.Second, attempt)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.LessOrEqual(t, d, 2*time.Second)
+	}
+}
+
+func TestBackoffWithFullJitter_GrowsWithAttempt(t *testing.T) {
+	// Not a statistical test - just checks the upper bound used for jitter
+	// grows (attempt 0 can never sample above base, attempt 3 can sample
+	// above it because the backing exponential has exceeded it).
+	sawAboveBaseAt3 := false
+	for i := 0; i < 200; i++ {
+		if backoffWithFullJitter(100*time.Millisecond, 2*time.Second, 3) > 100*time.Millisecond {
+			sawAboveBaseAt3 = true
+			break
+		}
+	}
+	assert.True(t, sawAboveBaseAt3)
+}
+
+func TestBackoffWithFullJitter_DefaultsAppliedForInvalidArgs(t *testing.T) {
+	d := backoffWithFullJitter(0, 0, 0)
+	assert.LessOrEqual(t, d, defaultRetryMaxBackoff)
+}
+
+func TestParseRetryAfter_Seconds(t *testing.T) {
+	now := time.Now()
+	d, ok := parseRetryAfter("5", now)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, d)
+}
+
+func TestParseRetryAfter_HTTPDate(t *testing.T) {
+	now := time.Now()
+	future := now.Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	d, ok := parseRetryAfter(future, now)
+	assert.True(t, ok)
+	assert.InDelta(t, float64(10*time.Second), float64(d), float64(2*time.Second))
+}
+
+func TestParseRetryAfter_Invalid(t *testing.T) {
+	_, ok := parseRetryAfter("not-a-value", time.Now())
+	assert.False(t, ok)
+}
+
+func TestParseRetryAfter_Empty(t *testing.T) {
+	_, ok := parseRetryAfter("", time.Now())
+	assert.False(t, ok)
+}
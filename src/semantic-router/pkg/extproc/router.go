@@ -1,6 +1,7 @@
 package extproc
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,19 +10,23 @@ import (
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	ext_proc "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
 	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
-
-	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/openai/openai-go"
 
 	candle_binding "github.com/vllm-project/semantic-router/candle-binding"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/bodymutation"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/cache"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/classification"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/concurrency"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/errs"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/ratelimit"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/responsestore"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/services"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/tools"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/utils/pii"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/vsrcontext"
 )
 
 // OpenAIRouter is an Envoy ExtProc server that routes OpenAI API requests
@@ -33,8 +38,55 @@ type OpenAIRouter struct {
 	Cache                cache.CacheBackend
 	ToolsDatabase        *tools.ToolsDatabase
 	ResponseAPIFilter    *ResponseAPIFilter
-	MemoryStore          *memory.MilvusStore
+	MemoryStore          memory.Store
 	MemoryExtractor      *memory.MemoryExtractor
+
+	// ReplaySink, when non-nil, receives a ReplayRecord for every request
+	// startRouterReplay samples (see ReplaySamplePolicy). nil disables
+	// replay capture entirely.
+	ReplaySink ReplaySink
+	// ReplaySamplePolicy controls what fraction of requests are captured
+	// when ReplaySink is configured. A nil policy samples nothing.
+	ReplaySamplePolicy *ReplaySamplePolicy
+
+	// GrammarConfigs maps a decision name to the constrained-decoding
+	// configuration modifyRequestBodyForAutoRouting should enforce for
+	// requests routed under it. A nil/missing entry applies no constraint.
+	GrammarConfigs map[string]GrammarConfig
+
+	// RateLimiter, when non-nil, gates every request before routing via
+	// Check; a nil RateLimiter disables rate limiting entirely.
+	RateLimiter ratelimit.RateLimiter
+
+	// ResponsePipeline, when non-nil, rewrites streamed response-body
+	// chunks (see handleResponseBody); a nil pipeline disables response
+	// mutation and forwards chunks unchanged.
+	ResponsePipeline *SSEPipeline
+
+	// ConcurrencyLimiter, when non-nil, admits each request against the
+	// UserModelConcurrencyLimit and ModelConcurrencyLimit caps before
+	// routing (see admitConcurrency); a nil limiter disables concurrency
+	// gating entirely.
+	ConcurrencyLimiter        *concurrency.Limiter
+	UserModelConcurrencyLimit concurrency.Limit
+	ModelConcurrencyLimit     concurrency.Limit
+
+	// BodyMutationPipeline, when non-nil, replaces defaultBodyMutationPipeline
+	// for the model-rewrite/Response-API-translation/decision-plugin
+	// stages createRoutingResponse and createSpecifiedModelResponse run
+	// over the request body (see body_mutation.go). A nil pipeline uses
+	// the default built-in stage set.
+	BodyMutationPipeline *bodymutation.Pipeline
+
+	// ContextSigner, when non-nil, signs a vsrcontext.Claims envelope onto
+	// every upstream request (see vsrContextHeaders in vsr_context.go); a
+	// nil signer disables the x-vsr-context/x-vsr-context-sig headers
+	// entirely.
+	ContextSigner *vsrcontext.Signer
+
+	// memoryHealth polls MemoryStore in the background and backs
+	// MemoryStoreReady; nil when memory is disabled.
+	memoryHealth *memoryStoreHealthGate
 }
 
 // Ensure OpenAIRouter implements the ext_proc calls
@@ -55,7 +107,7 @@ func NewOpenAIRouter(configPath string) (*OpenAIRouter, error) {
 		// Parse fresh config from file for file-based configuration (supports live reload)
 		cfg, err = config.Parse(configPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load config: %w", err)
+			return nil, fmt.Errorf("failed to load config: %w", errs.Wrap(errs.ErrConfigLoad, err))
 		}
 		// Update global config reference for packages that rely on config.GetConfig()
 		config.Replace(cfg)
@@ -67,7 +119,7 @@ func NewOpenAIRouter(configPath string) (*OpenAIRouter, error) {
 	if cfg.CategoryMappingPath != "" {
 		categoryMapping, err = classification.LoadCategoryMapping(cfg.CategoryMappingPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load category mapping: %w", err)
+			return nil, fmt.Errorf("failed to load category mapping: %w", errs.Wrap(errs.ErrConfigLoad, err))
 		}
 		logging.Infof("Loaded category mapping with %d categories", categoryMapping.GetCategoryCount())
 	}
@@ -77,7 +129,7 @@ func NewOpenAIRouter(configPath string) (*OpenAIRouter, error) {
 	if cfg.PIIMappingPath != "" {
 		piiMapping, err = classification.LoadPIIMapping(cfg.PIIMappingPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load PII mapping: %w", err)
+			return nil, fmt.Errorf("failed to load PII mapping: %w", errs.Wrap(errs.ErrConfigLoad, err))
 		}
 		logging.Infof("Loaded PII mapping with %d PII types", piiMapping.GetPIITypeCount())
 	}
@@ -87,7 +139,7 @@ func NewOpenAIRouter(configPath string) (*OpenAIRouter, error) {
 	if cfg.IsPromptGuardEnabled() {
 		jailbreakMapping, err = classification.LoadJailbreakMapping(cfg.PromptGuard.JailbreakMappingPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load jailbreak mapping: %w", err)
+			return nil, fmt.Errorf("failed to load jailbreak mapping: %w", errs.Wrap(errs.ErrConfigLoad, err))
 		}
 		logging.Infof("Loaded jailbreak mapping with %d jailbreak types", jailbreakMapping.GetJailbreakTypeCount())
 	}
@@ -95,7 +147,7 @@ func NewOpenAIRouter(configPath string) (*OpenAIRouter, error) {
 	// Initialize the BERT model for similarity search (only if configured)
 	if cfg.BertModel.ModelID != "" {
 		if initErr := candle_binding.InitModel(cfg.BertModel.ModelID, cfg.BertModel.UseCPU); initErr != nil {
-			return nil, fmt.Errorf("failed to initialize BERT model: %w", initErr)
+			return nil, fmt.Errorf("failed to initialize BERT model: %w", errs.Wrap(errs.ErrClassifierUnavailable, initErr))
 		}
 		logging.Infof("BERT similarity model initialized: %s", cfg.BertModel.ModelID)
 	} else {
@@ -124,7 +176,7 @@ func NewOpenAIRouter(configPath string) (*OpenAIRouter, error) {
 
 	semanticCache, err := cache.NewCacheBackend(cacheConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create semantic cache: %w", err)
+		return nil, fmt.Errorf("failed to create semantic cache: %w", errs.Wrap(errs.ErrCacheBackend, err))
 	}
 
 	if semanticCache.IsEnabled() {
@@ -163,7 +215,7 @@ func NewOpenAIRouter(configPath string) (*OpenAIRouter, error) {
 
 	classifier, err := classification.NewClassifier(cfg, categoryMapping, piiMapping, jailbreakMapping)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create classifier: %w", err)
+		return nil, fmt.Errorf("failed to create classifier: %w", errs.Wrap(errs.ErrClassifierUnavailable, err))
 	}
 
 	// Create global classification service for API access with auto-discovery
@@ -191,14 +243,18 @@ func NewOpenAIRouter(configPath string) (*OpenAIRouter, error) {
 	}
 
 	// Create memory store if enabled
-	var memoryStore *memory.MilvusStore
+	var memoryStore memory.Store
+	var memoryHealth *memoryStoreHealthGate
 	if cfg.Memory.Enabled {
 		memStore, err := createMemoryStore(cfg)
 		if err != nil {
 			logging.Warnf("Failed to create memory store: %v, Memory will be disabled", err)
 		} else {
 			memoryStore = memStore
-			logging.Infof("Memory enabled with Milvus backend")
+			logging.Infof("Memory enabled with backend: %s", cfg.Memory.Backend)
+
+			memoryHealth = newMemoryStoreHealthGate(memoryStore, cfg.Memory.HealthCheckInterval)
+			memoryHealth.Start(context.Background())
 		}
 	}
 
@@ -223,7 +279,13 @@ func NewOpenAIRouter(configPath string) (*OpenAIRouter, error) {
 		ResponseAPIFilter:    responseAPIFilter,
 		MemoryStore:          memoryStore,
 		MemoryExtractor:      memoryExtractor,
+		memoryHealth:         memoryHealth,
+	}
+
+	if err := router.RestoreCacheSnapshot(context.Background()); err != nil {
+		logging.Warnf("Cache: failed to restore snapshot, starting cold: %v", err)
 	}
+	router.InstallCacheSnapshotSignalHandler()
 
 	return router, nil
 }
@@ -284,13 +346,111 @@ func (r *OpenAIRouter) createErrorResponse(statusCode int, message string) *ext_
 	return r.createJSONResponseWithBody(statusCode, jsonData)
 }
 
+// createErrorResponseForErr is like createErrorResponse, but derives the
+// OpenAI error "type"/"code" fields from err's errs.Reason when it has one
+// (see pkg/errs), instead of always reporting "invalid_request_error". This
+// lets a caller holding a typed error (e.g. errs.ErrMemoryStoreDown) surface
+// a response that downstream metrics/retries can branch on, while callers
+// with only a plain message keep using createErrorResponse. fallbackStatus
+// is used verbatim when err doesn't classify to a known Reason.
+func (r *OpenAIRouter) createErrorResponseForErr(fallbackStatus int, err error) *ext_proc.ProcessingResponse {
+	statusCode, errType, errCode := fallbackStatus, "invalid_request_error", fmt.Sprintf("%d", fallbackStatus)
+	if status, typ, code, ok := errs.OpenAIErrorFields(err); ok {
+		statusCode, errType, errCode = status, typ, code
+	}
+
+	errorResp := map[string]interface{}{
+		"error": map[string]interface{}{
+			"message": err.Error(),
+			"type":    errType,
+			"code":    errCode,
+		},
+	}
+
+	jsonData, marshalErr := json.Marshal(errorResp)
+	if marshalErr != nil {
+		logging.Errorf("Failed to marshal error response: %v", marshalErr)
+		jsonData = []byte(`{"error":{"message":"Internal server error","type":"internal_error","code":"500"}}`)
+		statusCode = 500
+	}
+
+	return r.createJSONResponseWithBody(statusCode, jsonData)
+}
+
+// createSSEResponse creates a direct response that streams chunks as
+// Server-Sent Events, framed the way OpenAI's streaming Chat Completions
+// API frames them: one "data: {...}\n\n" per chunk, followed by a
+// terminating "data: [DONE]\n\n". Use this instead of createJSONResponse
+// for any short-circuit (cache hit, PII violation, jailbreak block,
+// memory-answered, tools-only) that fires for a request whose
+// ctx.ExpectStreamingResponse is true — an SSE client left waiting on a
+// single JSON body will hang rather than see a protocol mismatch.
+func (r *OpenAIRouter) createSSEResponse(statusCode int, chunks []openai.ChatCompletionChunk) *ext_proc.ProcessingResponse {
+	var body bytes.Buffer
+	for _, chunk := range chunks {
+		data, err := json.Marshal(chunk)
+		if err != nil {
+			logging.Errorf("Failed to marshal SSE chunk: %v", err)
+			continue
+		}
+		body.WriteString("data: ")
+		body.Write(data)
+		body.WriteString("\n\n")
+	}
+	body.WriteString("data: [DONE]\n\n")
+
+	return &ext_proc.ProcessingResponse{
+		Response: &ext_proc.ProcessingResponse_ImmediateResponse{
+			ImmediateResponse: &ext_proc.ImmediateResponse{
+				Status: &typev3.HttpStatus{
+					Code: statusCodeToEnum(statusCode),
+				},
+				Headers: &ext_proc.HeaderMutation{
+					SetHeaders: []*core.HeaderValueOption{
+						{
+							Header: &core.HeaderValue{
+								Key:      "content-type",
+								RawValue: []byte("text/event-stream"),
+							},
+						},
+						{
+							Header: &core.HeaderValue{
+								Key:      "cache-control",
+								RawValue: []byte("no-cache"),
+							},
+						},
+					},
+				},
+				Body: body.Bytes(),
+			},
+		},
+	}
+}
+
 // shouldClearRouteCache checks if route cache should be cleared
 func (r *OpenAIRouter) shouldClearRouteCache() bool {
 	// Check if feature is enabled
 	return r.Config.ClearRouteCache
 }
 
-// createResponseStore creates a response store based on configuration.
+// Close stops the background memory health gate and releases the memory
+// store, if either was set up. Safe to call even when memory is disabled.
+func (r *OpenAIRouter) Close() error {
+	if r.memoryHealth != nil {
+		r.memoryHealth.Stop()
+	}
+	if r.MemoryStore != nil {
+		return r.MemoryStore.Close()
+	}
+	return nil
+}
+
+// createResponseStore creates a response store based on configuration. When
+// the backend is Milvus, construction goes through the same exponential
+// backoff retry as createMemoryStore (see connectWithBackoff), since it races
+// the same Kubernetes startup ordering problem: Milvus and the router
+// frequently come up together, and a single failed dial would otherwise
+// leave the Response API permanently disabled for the life of the process.
 func createResponseStore(cfg *config.RouterConfig) (responsestore.ResponseStore, error) {
 	storeConfig := responsestore.StoreConfig{
 		Enabled:     true,
@@ -305,58 +465,72 @@ func createResponseStore(cfg *config.RouterConfig) (responsestore.ResponseStore,
 			ResponseCollection: cfg.ResponseAPI.Milvus.Collection,
 		},
 	}
-	return responsestore.NewStore(storeConfig)
-}
 
-// createMemoryStore creates a memory store based on configuration.
-// Supports Milvus backend with address/collection from MemoryConfig.Milvus.
-func createMemoryStore(cfg *config.RouterConfig) (*memory.MilvusStore, error) {
-	// Get Milvus address from Memory config, with fallback to defaults
-	milvusAddress := cfg.Memory.Milvus.Address
-	if milvusAddress == "" {
-		milvusAddress = "localhost:19530" // Default
+	if storeConfig.BackendType != responsestore.StoreBackendMilvus {
+		return responsestore.NewStore(storeConfig)
 	}
 
-	collectionName := cfg.Memory.Milvus.Collection
-	if collectionName == "" {
-		collectionName = "agentic_memory" // Default
+	var store responsestore.ResponseStore
+	err := connectWithBackoff(
+		cfg.ResponseAPI.Milvus.ConnectRetryAttempts,
+		cfg.ResponseAPI.Milvus.ConnectRetryInitialBackoff,
+		cfg.ResponseAPI.Milvus.ConnectRetryMaxBackoff,
+		func() error {
+			s, err := responsestore.NewStore(storeConfig)
+			if err != nil {
+				return err
+			}
+			store = s
+			return nil
+		},
+	)
+	return store, err
+}
+
+// createMemoryStore creates a memory store based on configuration. Backend
+// selection goes through memory.NewStore/cfg.Memory.Backend ("milvus",
+// "sqlite", "postgres", "mongo", "memory"), so extproc never binds to a
+// concrete backend type - Milvus remains the default (preserving prior
+// behavior for configs that predate the Backend field), with connection
+// details still sourced from MemoryConfig.Milvus.
+func createMemoryStore(cfg *config.RouterConfig) (memory.Store, error) {
+	storeType := memory.StoreType(cfg.Memory.Backend)
+	if storeType == "" {
+		storeType = memory.StoreTypeMilvus
 	}
 
-	logging.Infof("Memory: Connecting to Milvus at %s, collection=%s", milvusAddress, collectionName)
+	storeConfig := &memory.StoreConfig{Type: storeType}
 
-	// Create Milvus client
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	if storeType == memory.StoreTypeMilvus {
+		milvusAddress := cfg.Memory.Milvus.Address
+		if milvusAddress == "" {
+			milvusAddress = "localhost:19530" // Default
+		}
 
-	milvusClient, err := client.NewGrpcClient(ctx, milvusAddress)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Milvus client: %w", err)
-	}
+		collectionName := cfg.Memory.Milvus.Collection
+		if collectionName == "" {
+			collectionName = "agentic_memory" // Default
+		}
 
-	// Check connection
-	state, err := milvusClient.CheckHealth(ctx)
-	if err != nil {
-		milvusClient.Close()
-		return nil, fmt.Errorf("failed to check Milvus connection: %w", err)
-	}
-	if state == nil || !state.IsHealthy {
-		milvusClient.Close()
-		return nil, fmt.Errorf("Milvus connection is not healthy")
+		milvusConfig := memory.DefaultMilvusConfig()
+		milvusConfig.Address = milvusAddress
+		milvusConfig.CollectionName = collectionName
+		storeConfig.Milvus = milvusConfig
+
+		logging.Infof("Memory: Connecting to Milvus at %s, collection=%s", milvusAddress, collectionName)
+	} else {
+		logging.Infof("Memory: using %s backend", storeType)
 	}
 
-	// Create memory store
-	store, err := memory.NewMilvusStore(memory.MilvusStoreOptions{
-		Client:         milvusClient,
-		CollectionName: collectionName,
-		Config:         cfg.Memory,
-		Enabled:        cfg.Memory.Enabled,
-	})
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	store, err := memory.NewStore(ctx, storeConfig)
 	if err != nil {
-		milvusClient.Close()
-		return nil, fmt.Errorf("failed to create memory store: %w", err)
+		return nil, fmt.Errorf("failed to create memory store (backend=%s): %w", storeType, errs.Wrap(errs.ErrMemoryStoreDown, err))
 	}
 
-	logging.Infof("Memory store initialized: address=%s, collection=%s", milvusAddress, collectionName)
+	logging.Infof("Memory store initialized: backend=%s", storeType)
 	return store, nil
 }
 
@@ -372,7 +546,7 @@ func (r *OpenAIRouter) LoadToolsDatabase() error {
 	}
 
 	if err := r.ToolsDatabase.LoadToolsFromFile(r.Config.Tools.ToolsDBPath); err != nil {
-		return fmt.Errorf("failed to load tools from file %s: %w", r.Config.Tools.ToolsDBPath, err)
+		return fmt.Errorf("failed to load tools from file %s: %w", r.Config.Tools.ToolsDBPath, errs.Wrap(errs.ErrToolSelection, err))
 	}
 
 	logging.Infof("Tools database loaded successfully from: %s", r.Config.Tools.ToolsDBPath)
@@ -0,0 +1,43 @@
+package extproc
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/errs"
+)
+
+func TestCreateErrorResponseForErr_KnownReasonOverridesFallback(t *testing.T) {
+	r := &OpenAIRouter{}
+	err := fmt.Errorf("dial milvus: %w", errs.Wrap(errs.ErrMemoryStoreDown, fmt.Errorf("connection refused")))
+
+	resp := r.createErrorResponseForErr(500, err)
+
+	immediate := resp.GetImmediateResponse()
+	require.NotNil(t, immediate)
+	assert.EqualValues(t, 503, immediate.GetStatus().GetCode())
+
+	var body map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal(immediate.GetBody(), &body))
+	assert.Equal(t, "service_unavailable", body["error"]["type"])
+	assert.Equal(t, "memory_store_unavailable", body["error"]["code"])
+}
+
+func TestCreateErrorResponseForErr_UnclassifiedFallsBackToCaller(t *testing.T) {
+	r := &OpenAIRouter{}
+	err := fmt.Errorf("some unclassified failure")
+
+	resp := r.createErrorResponseForErr(404, err)
+
+	immediate := resp.GetImmediateResponse()
+	require.NotNil(t, immediate)
+	assert.EqualValues(t, 404, immediate.GetStatus().GetCode())
+
+	var body map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal(immediate.GetBody(), &body))
+	assert.Equal(t, "invalid_request_error", body["error"]["type"])
+}
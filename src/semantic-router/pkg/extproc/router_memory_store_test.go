@@ -0,0 +1,28 @@
+package extproc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+)
+
+// TestCreateMemoryStore_BackendSelection exercises the cfg.Memory.Backend ->
+// memory.StoreType dispatch without needing a live Milvus instance: the
+// "memory" backend is in-process, so it proves createMemoryStore no longer
+// hard-binds to Milvus without requiring network access in a unit test.
+func TestCreateMemoryStore_BackendSelection(t *testing.T) {
+	cfg := &config.RouterConfig{}
+	cfg.Memory.Enabled = true
+	cfg.Memory.Backend = "memory"
+
+	store, err := createMemoryStore(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, store)
+
+	var _ memory.Store = store
+	assert.True(t, store.IsEnabled())
+}
@@ -0,0 +1,260 @@
+package extproc
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// ReplayRecord is an evaluation-ready record of one routed request,
+// persisted through a ReplaySink for offline regression testing of routing
+// changes against real historical traffic.
+type ReplayRecord struct {
+	RequestID      string    `json:"request_id"`
+	Timestamp      time.Time `json:"timestamp"`
+	OriginalModel  string    `json:"original_model"`
+	SelectedModel  string    `json:"selected_model"`
+	DecisionName   string    `json:"decision_name"`
+	CategoryName   string    `json:"category_name,omitempty"`
+	ReasoningMode  string    `json:"reasoning_mode,omitempty"`
+	ContextTokens  int       `json:"context_tokens,omitempty"`
+	UserContent    string    `json:"user_content,omitempty"`
+	OriginalBody   string    `json:"original_body,omitempty"`
+	TranslatedBody string    `json:"translated_body,omitempty"`
+	StatusCode     int       `json:"status_code"`
+	Allowed        bool      `json:"allowed"`
+	Final          bool      `json:"final"`
+}
+
+// ReplaySink is a pluggable destination for ReplayRecords - a local JSONL
+// file, an S3 object writer, or an HTTP webhook all implement the same
+// narrow interface.
+type ReplaySink interface {
+	Write(ctx context.Context, record ReplayRecord) error
+}
+
+// ReplaySamplePolicy controls what fraction of requests are persisted to
+// the configured ReplaySink. DecisionOverrides takes priority over
+// SampleRate when a request's decision name has an entry.
+type ReplaySamplePolicy struct {
+	SampleRate        float64
+	DecisionOverrides map[string]float64
+}
+
+// ShouldSample reports whether a request routed under decisionName should
+// be persisted, per p's sampling configuration. A nil policy never samples
+// (replay capture is opt-in).
+func (p *ReplaySamplePolicy) ShouldSample(decisionName string) bool {
+	if p == nil {
+		return false
+	}
+	rate := p.SampleRate
+	if override, ok := p.DecisionOverrides[decisionName]; ok {
+		rate = override
+	}
+	if rate <= 0 {
+		return false
+	}
+	if rate >= 1 {
+		return true
+	}
+	return rand.Float64() < rate
+}
+
+// FileReplaySink appends each ReplayRecord as one JSON line to a local
+// file, creating it if necessary. Safe for concurrent use.
+type FileReplaySink struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// NewFileReplaySink opens (creating if necessary) path for append and
+// returns a sink that writes one JSON object per line to it.
+func NewFileReplaySink(path string) (*FileReplaySink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening replay sink file %s: %w", path, err)
+	}
+	return &FileReplaySink{path: path, file: f}, nil
+}
+
+// Write appends record to the sink's file as a single JSON line.
+func (s *FileReplaySink) Write(_ context.Context, record ReplayRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshaling replay record: %w", err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(data)
+	return err
+}
+
+// WebhookReplaySink POSTs each ReplayRecord as JSON to a configured URL.
+type WebhookReplaySink struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhookReplaySink returns a sink that POSTs each record to url using
+// client, or http.DefaultClient if client is nil.
+func NewWebhookReplaySink(url string, client *http.Client) *WebhookReplaySink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookReplaySink{URL: url, Client: client}
+}
+
+// Write POSTs record to the webhook as a JSON body.
+func (s *WebhookReplaySink) Write(ctx context.Context, record ReplayRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshaling replay record: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, strings.NewReader(string(data)))
+	if err != nil {
+		return fmt.Errorf("building replay webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting replay record: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("replay webhook returned status %s", resp.Status)
+	}
+	return nil
+}
+
+// pendingReplays holds in-flight ReplayRecords between startRouterReplay
+// and updateRouterReplayStatus, keyed by replayRequestID(ctx). A request
+// that's never finalized (no matching updateRouterReplayStatus call, e.g.
+// the happy-path auto-routing case where the final status is only known
+// once an upstream response is seen) simply keeps its "pending, not final"
+// record as the only one persisted - the sink still gets a usable record,
+// just without a confirmed status code.
+var pendingReplays sync.Map // string -> *ReplayRecord
+
+var replayRequestCounter uint64
+var replayRequestCounterMu sync.Mutex
+
+// replayRequestID derives a stable per-request key from ctx's trace span
+// when one is available. Without one it takes the next value of a
+// process-local counter and attaches it to ctx.TraceContext as a span
+// context with a zero trace ID (which propagators never inject upstream),
+// so later calls for the same ctx return the same key.
+func replayRequestID(ctx *RequestContext) string {
+	if ctx != nil && ctx.TraceContext != nil {
+		if spanCtx := trace.SpanContextFromContext(ctx.TraceContext); spanCtx.HasSpanID() {
+			return spanCtx.SpanID().String()
+		}
+	}
+	replayRequestCounterMu.Lock()
+	replayRequestCounter++
+	n := replayRequestCounter
+	replayRequestCounterMu.Unlock()
+
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(spanID[:], n)
+	if ctx != nil {
+		parent := ctx.TraceContext
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx.TraceContext = trace.ContextWithSpanContext(parent, trace.NewSpanContext(trace.SpanContextConfig{SpanID: spanID}))
+	}
+	return spanID.String()
+}
+
+// piiScrubPattern is a best-effort scrub of common PII shapes (emails, long
+// digit runs such as card/phone numbers) for the "PII-scrubbed variant" of
+// a replay record. It intentionally doesn't attempt the same fidelity as
+// pkg/utils/pii's classifiers - it's a cheap redaction pass for a bundle
+// that may leave this process's trust boundary (S3, a webhook).
+var piiScrubPattern = regexp.MustCompile(`[[:alnum:].+-]+@[[:alnum:].-]+\.[[:alpha:]]{2,}|\b\d{7,}\b`)
+
+func scrubPII(s string) string {
+	return piiScrubPattern.ReplaceAllString(s, "[REDACTED]")
+}
+
+// startRouterReplay builds a ReplayRecord from the current request state
+// and, if r.ReplaySink is configured and r.ReplaySamplePolicy selects this
+// request, persists an initial (possibly non-final) copy and stashes the
+// record in pendingReplays so a later updateRouterReplayStatus call can
+// amend and re-persist it with the real outcome.
+func (r *OpenAIRouter) startRouterReplay(ctx *RequestContext, originalModel, selectedModel, decisionName string) {
+	if r.ReplaySink == nil || !r.ReplaySamplePolicy.ShouldSample(decisionName) {
+		return
+	}
+
+	record := &ReplayRecord{
+		RequestID:     replayRequestID(ctx),
+		Timestamp:     time.Now(),
+		OriginalModel: originalModel,
+		SelectedModel: selectedModel,
+		DecisionName:  decisionName,
+		CategoryName:  ctx.VSRSelectedCategory,
+		ReasoningMode: ctx.VSRReasoningMode,
+		ContextTokens: ctx.VSRContextTokenCount,
+		UserContent:   scrubPII(ctx.UserContent),
+		OriginalBody:  scrubPII(string(ctx.OriginalRequestBody)),
+		Allowed:       true,
+	}
+
+	pendingReplays.Store(record.RequestID, record)
+	r.writeReplayRecord(ctx, *record)
+}
+
+// updateRouterReplayStatus amends the pending replay record for ctx (if
+// any) with the final status code and outcome and persists the amended,
+// final copy. Requests that never called startRouterReplay, or whose
+// record fell outside the sampling decision, are silently no-ops.
+func (r *OpenAIRouter) updateRouterReplayStatus(ctx *RequestContext, statusCode int, allowed bool) {
+	if r.ReplaySink == nil {
+		return
+	}
+
+	id := replayRequestID(ctx)
+	val, ok := pendingReplays.LoadAndDelete(id)
+	if !ok {
+		return
+	}
+	record := *val.(*ReplayRecord)
+	record.StatusCode = statusCode
+	record.Allowed = allowed
+	record.Final = true
+
+	r.writeReplayRecord(ctx, record)
+}
+
+// writeReplayRecord persists record through r.ReplaySink, logging (not
+// failing the request) on a sink error - replay capture is best-effort
+// observability, never something that should affect routing.
+func (r *OpenAIRouter) writeReplayRecord(ctx *RequestContext, record ReplayRecord) {
+	traceCtx := context.Background()
+	if ctx != nil && ctx.TraceContext != nil {
+		traceCtx = ctx.TraceContext
+	}
+	if err := r.ReplaySink.Write(traceCtx, record); err != nil {
+		logging.Warnf("Failed to write replay record %s: %v", record.RequestID, err)
+	}
+}
@@ -0,0 +1,98 @@
+package extproc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryReplaySink struct {
+	records []ReplayRecord
+}
+
+func (s *memoryReplaySink) Write(_ context.Context, record ReplayRecord) error {
+	s.records = append(s.records, record)
+	return nil
+}
+
+func TestReplaySamplePolicy_ShouldSample(t *testing.T) {
+	var nilPolicy *ReplaySamplePolicy
+	assert.False(t, nilPolicy.ShouldSample("any"))
+
+	always := &ReplaySamplePolicy{SampleRate: 1}
+	assert.True(t, always.ShouldSample("any"))
+
+	never := &ReplaySamplePolicy{SampleRate: 0}
+	assert.False(t, never.ShouldSample("any"))
+
+	overridden := &ReplaySamplePolicy{SampleRate: 0, DecisionOverrides: map[string]float64{"structured_extraction": 1}}
+	assert.True(t, overridden.ShouldSample("structured_extraction"))
+	assert.False(t, overridden.ShouldSample("other_decision"))
+}
+
+func TestStartAndUpdateRouterReplay_FullLifecycle(t *testing.T) {
+	sink := &memoryReplaySink{}
+	r := &OpenAIRouter{ReplaySink: sink, ReplaySamplePolicy: &ReplaySamplePolicy{SampleRate: 1}}
+	ctx := &RequestContext{UserContent: "contact me at a@b.com please", OriginalRequestBody: []byte(`{"model":"auto"}`)}
+
+	r.startRouterReplay(ctx, "auto", "gpt-4o", "structured_extraction")
+	require.Len(t, sink.records, 1)
+	assert.False(t, sink.records[0].Final)
+	assert.True(t, strings.Contains(sink.records[0].UserContent, "[REDACTED]"))
+	assert.False(t, strings.Contains(sink.records[0].UserContent, "a@b.com"))
+
+	r.updateRouterReplayStatus(ctx, 403, false)
+	require.Len(t, sink.records, 2)
+	assert.True(t, sink.records[1].Final)
+	assert.Equal(t, 403, sink.records[1].StatusCode)
+	assert.False(t, sink.records[1].Allowed)
+}
+
+func TestStartRouterReplay_NoSinkIsNoop(t *testing.T) {
+	r := &OpenAIRouter{}
+	ctx := &RequestContext{}
+	r.startRouterReplay(ctx, "auto", "gpt-4o", "decision")
+	r.updateRouterReplayStatus(ctx, 200, true)
+	// No panic, nothing to assert beyond completing without a configured sink.
+}
+
+func TestStartRouterReplay_NotSampledSkipsWrite(t *testing.T) {
+	sink := &memoryReplaySink{}
+	r := &OpenAIRouter{ReplaySink: sink, ReplaySamplePolicy: &ReplaySamplePolicy{SampleRate: 0}}
+	ctx := &RequestContext{}
+	r.startRouterReplay(ctx, "auto", "gpt-4o", "decision")
+	assert.Empty(t, sink.records)
+}
+
+func TestFileReplaySink_WritesJSONLines(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "replay.jsonl")
+
+	sink, err := NewFileReplaySink(path)
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Write(context.Background(), ReplayRecord{RequestID: "r1", DecisionName: "d1"}))
+	require.NoError(t, sink.Write(context.Background(), ReplayRecord{RequestID: "r2", DecisionName: "d2"}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var first ReplayRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "r1", first.RequestID)
+}
+
+func TestScrubPII_RedactsEmailsAndLongDigitRuns(t *testing.T) {
+	scrubbed := scrubPII("email me at jane.doe@example.com or call 5551234567")
+	assert.NotContains(t, scrubbed, "jane.doe@example.com")
+	assert.NotContains(t, scrubbed, "5551234567")
+	assert.Contains(t, scrubbed, "[REDACTED]")
+}
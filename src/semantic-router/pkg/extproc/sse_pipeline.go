@@ -0,0 +1,338 @@
+package extproc
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+
+	ext_proc "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"github.com/openai/openai-go"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// sseRingBufferLimit bounds how much not-yet-terminated SSE data a
+// streamState will hold: once the buffered tail exceeds it, ProcessChunk
+// forwards the buffered bytes unmodified and parses afresh from the next
+// chunk, so a pathological or non-conforming upstream can't make this
+// buffer grow without bound.
+const sseRingBufferLimit = 64 * 1024
+
+// ResponseMutationPlugin rewrites streamed chat.completion.chunk frames
+// before they're re-serialized and forwarded to the client. Plugins run
+// in registration order against the same decoded chunk, so a later
+// plugin sees an earlier one's edits.
+type ResponseMutationPlugin interface {
+	Name() string
+
+	// MutateChunk rewrites chunk in place (typically choices[].delta.content).
+	MutateChunk(ctx *RequestContext, chunk *openai.ChatCompletionChunk) error
+
+	// Finalize returns additional fields to merge into the terminal
+	// x_semantic_router frame emitted just before [DONE]. A nil/empty
+	// map contributes nothing. state is this response's accumulated
+	// stream state (e.g. running usage totals from MutateChunk calls).
+	Finalize(ctx *RequestContext, state *streamState) (map[string]any, error)
+}
+
+// streamState is the per-response, in-flight state for one streamed SSE
+// body: the bounded buffer holding not-yet-terminated data plus whatever
+// plugins accumulate across chunks. It's keyed by replayRequestID(ctx) in
+// responseStreams rather than carried on RequestContext, the same
+// technique startRouterReplay uses for pendingReplays, since
+// RequestContext's own definition can't be extended from this package.
+type streamState struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	usage openai.CompletionUsage
+}
+
+var responseStreams sync.Map // string (replayRequestID) -> *streamState
+
+// memoryCitationIDs records, per replayRequestID(ctx), the IDs of the
+// memories handleMemoryRetrieval injected into a request, so
+// MemoryCitationPlugin can attach them to the streamed response's
+// terminal frame once the upstream reply comes back.
+var memoryCitationIDs sync.Map // string (replayRequestID) -> []string
+
+// SSEPipeline incrementally rewrites a text/event-stream response body
+// chunk by chunk, running Plugins over each decoded
+// chat.completion.chunk frame and injecting a terminal
+// {"x_semantic_router": {...}} frame - assembled from every plugin's
+// Finalize - immediately before the upstream's closing "data: [DONE]".
+// It never buffers a full response: only the undelimited tail of the
+// current chunk is held, bounded by sseRingBufferLimit.
+type SSEPipeline struct {
+	Plugins []ResponseMutationPlugin
+}
+
+// streamStateFor returns (creating if necessary) the streamState for ctx,
+// or deletes and forgets it when final is true.
+func streamStateFor(ctx *RequestContext, final bool) *streamState {
+	id := replayRequestID(ctx)
+	if final {
+		val, ok := responseStreams.LoadAndDelete(id)
+		if !ok {
+			return &streamState{}
+		}
+		return val.(*streamState)
+	}
+	val, _ := responseStreams.LoadOrStore(id, &streamState{})
+	return val.(*streamState)
+}
+
+// ProcessChunk feeds one raw chunk of a streamed response body through the
+// pipeline, returning the (possibly rewritten) bytes to forward in its
+// place. endOfStream marks the final chunk of the response: any
+// unterminated tail still buffered is flushed through unmodified and the
+// request's stream state is discarded.
+func (p *SSEPipeline) ProcessChunk(ctx *RequestContext, raw []byte, endOfStream bool) ([]byte, error) {
+	state := streamStateFor(ctx, false)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.buf.Write(raw)
+	if state.buf.Len() > sseRingBufferLimit {
+		logging.Warnf("SSEPipeline: buffered SSE tail exceeded %d bytes without a frame terminator, flushing unmodified", sseRingBufferLimit)
+		out := state.buf.Bytes()
+		state.buf.Reset()
+		if endOfStream {
+			streamStateFor(ctx, true)
+		}
+		return out, nil
+	}
+
+	frames, rest := splitSSEFrames(state.buf.Bytes())
+	var out bytes.Buffer
+	for _, frame := range frames {
+		p.renderFrame(ctx, state, frame, &out)
+	}
+	state.buf.Reset()
+	state.buf.Write(rest)
+
+	if endOfStream {
+		if state.buf.Len() > 0 {
+			out.Write(state.buf.Bytes())
+		}
+		streamStateFor(ctx, true)
+	}
+
+	return out.Bytes(), nil
+}
+
+// renderFrame decodes one complete SSE frame, runs it through p.Plugins,
+// and writes the resulting frame(s) to out. A malformed frame, or the
+// terminal [DONE] frame, is passed through with plugin Finalize output
+// injected just ahead of [DONE].
+func (p *SSEPipeline) renderFrame(ctx *RequestContext, state *streamState, frame []byte, out *bytes.Buffer) {
+	data, isDone, ok := parseSSEFrame(frame)
+	if !ok {
+		out.Write(frame)
+		out.WriteString("\n\n")
+		return
+	}
+	if isDone {
+		p.writeFinalFrame(ctx, state, out)
+		out.WriteString("data: [DONE]\n\n")
+		return
+	}
+
+	var chunk openai.ChatCompletionChunk
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		logging.Warnf("SSEPipeline: failed to decode chunk, forwarding unmodified: %v", err)
+		out.Write(frame)
+		out.WriteString("\n\n")
+		return
+	}
+	if chunk.Usage.TotalTokens > 0 {
+		state.usage = chunk.Usage
+	}
+
+	for _, plugin := range p.Plugins {
+		if err := plugin.MutateChunk(ctx, &chunk); err != nil {
+			logging.Warnf("SSEPipeline: plugin %s failed on chunk: %v", plugin.Name(), err)
+		}
+	}
+
+	mutated, err := json.Marshal(chunk)
+	if err != nil {
+		logging.Warnf("SSEPipeline: failed to re-encode mutated chunk, forwarding original: %v", err)
+		out.Write(frame)
+		out.WriteString("\n\n")
+		return
+	}
+	out.WriteString("data: ")
+	out.Write(mutated)
+	out.WriteString("\n\n")
+}
+
+// writeFinalFrame merges every plugin's Finalize output into one
+// x_semantic_router frame and writes it to out, if any plugin contributed
+// a field.
+func (p *SSEPipeline) writeFinalFrame(ctx *RequestContext, state *streamState, out *bytes.Buffer) {
+	fields := map[string]any{}
+	for _, plugin := range p.Plugins {
+		extra, err := plugin.Finalize(ctx, state)
+		if err != nil {
+			logging.Warnf("SSEPipeline: plugin %s finalize failed: %v", plugin.Name(), err)
+			continue
+		}
+		for k, v := range extra {
+			fields[k] = v
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+	body, err := json.Marshal(map[string]any{"x_semantic_router": fields})
+	if err != nil {
+		logging.Warnf("SSEPipeline: failed to encode terminal frame: %v", err)
+		return
+	}
+	out.WriteString("data: ")
+	out.Write(body)
+	out.WriteString("\n\n")
+}
+
+// splitSSEFrames splits buf on its frame terminator (a blank line) and
+// returns the complete frames found plus whatever incomplete tail
+// remains, to be prepended to the next call's input.
+func splitSSEFrames(buf []byte) (frames [][]byte, rest []byte) {
+	parts := bytes.Split(buf, []byte("\n\n"))
+	if len(parts) == 1 {
+		return nil, buf
+	}
+	return parts[:len(parts)-1], parts[len(parts)-1]
+}
+
+// parseSSEFrame extracts one SSE frame's "data:" payload. ok is false for
+// a frame with no data line at all (e.g. a bare comment or event: line),
+// which the caller forwards unmodified; isDone is true for the literal
+// "data: [DONE]" sentinel OpenAI/vLLM send to end a stream.
+func parseSSEFrame(frame []byte) (data []byte, isDone bool, ok bool) {
+	for _, line := range bytes.Split(frame, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if !bytes.HasPrefix(line, []byte("data:")) {
+			continue
+		}
+		payload := bytes.TrimSpace(line[len("data:"):])
+		if string(payload) == "[DONE]" {
+			return nil, true, true
+		}
+		return payload, false, true
+	}
+	return nil, false, false
+}
+
+// PIIRedactionPlugin scrubs common PII shapes (emails, long digit runs)
+// out of streamed delta content, reusing the same best-effort pattern
+// router_replay.go uses for replay records.
+type PIIRedactionPlugin struct{}
+
+func (PIIRedactionPlugin) Name() string { return "pii-redaction" }
+
+func (PIIRedactionPlugin) MutateChunk(_ *RequestContext, chunk *openai.ChatCompletionChunk) error {
+	for i := range chunk.Choices {
+		chunk.Choices[i].Delta.Content = scrubPII(chunk.Choices[i].Delta.Content)
+	}
+	return nil
+}
+
+func (PIIRedactionPlugin) Finalize(*RequestContext, *streamState) (map[string]any, error) {
+	return nil, nil
+}
+
+// MemoryCitationPlugin attaches the IDs of memories handleMemoryRetrieval
+// injected into this request (see memoryCitationIDs) to the terminal
+// frame, so a client can render clickable provenance links for the
+// memory-backed context it received.
+type MemoryCitationPlugin struct{}
+
+func (MemoryCitationPlugin) Name() string { return "memory-citation" }
+
+func (MemoryCitationPlugin) MutateChunk(*RequestContext, *openai.ChatCompletionChunk) error {
+	return nil
+}
+
+func (MemoryCitationPlugin) Finalize(ctx *RequestContext, _ *streamState) (map[string]any, error) {
+	val, ok := memoryCitationIDs.LoadAndDelete(replayRequestID(ctx))
+	if !ok {
+		return nil, nil
+	}
+	ids, _ := val.([]string)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return map[string]any{"memory_citations": ids}, nil
+}
+
+// CostAnnotationPlugin reports the response's per-token usage, taken from
+// the last chat.completion.chunk frame that carried a non-empty usage
+// field, in the terminal frame so clients can display in-band cost
+// accounting without a separate round trip.
+type CostAnnotationPlugin struct{}
+
+func (CostAnnotationPlugin) Name() string { return "cost-annotation" }
+
+func (CostAnnotationPlugin) MutateChunk(_ *RequestContext, _ *openai.ChatCompletionChunk) error {
+	return nil // usage tracking happens in SSEPipeline.renderFrame, shared across plugins
+}
+
+func (CostAnnotationPlugin) Finalize(_ *RequestContext, state *streamState) (map[string]any, error) {
+	if state.usage.TotalTokens == 0 {
+		return nil, nil
+	}
+	return map[string]any{"cost": map[string]any{
+		"prompt_tokens":     state.usage.PromptTokens,
+		"completion_tokens": state.usage.CompletionTokens,
+		"total_tokens":      state.usage.TotalTokens,
+	}}, nil
+}
+
+// handleResponseBody processes one streamed chunk of the upstream
+// response body through r.ResponsePipeline. A nil ResponsePipeline
+// disables response mutation entirely and forwards the chunk unchanged.
+// On end-of-stream it also releases ctx's concurrency slot (see
+// admitConcurrency/releaseConcurrencySlot) - end-of-stream is this tree's
+// stand-in for "response complete" since handleResponseHeaders, the
+// normal place to do this, isn't wired to anything either (see the
+// dispatcher gap below).
+//
+// This pruned snapshot has no dispatcher wiring
+// ext_proc.ProcessingRequest_ResponseBody messages through to a handler -
+// the Process(stream) method implementing ext_proc.ExternalProcessorServer
+// (see the var _ assertion in router.go) lives outside this tree. This
+// method is the complete, self-contained unit ready to be called once
+// that wiring exists; until then it's reachable only from tests.
+func (r *OpenAIRouter) handleResponseBody(v *ext_proc.ProcessingRequest_ResponseBody, ctx *RequestContext) (*ext_proc.ProcessingResponse, error) {
+	if v.ResponseBody.GetEndOfStream() {
+		defer r.releaseConcurrencySlot(ctx)
+	}
+
+	if r.ResponsePipeline == nil {
+		return continueResponseBody(nil), nil
+	}
+
+	mutated, err := r.ResponsePipeline.ProcessChunk(ctx, v.ResponseBody.GetBody(), v.ResponseBody.GetEndOfStream())
+	if err != nil {
+		logging.Errorf("[Response Body] SSE pipeline error: %v", err)
+		return continueResponseBody(nil), nil
+	}
+	return continueResponseBody(mutated), nil
+}
+
+// continueResponseBody builds a CONTINUE response for the response-body
+// phase, optionally rewriting the body when mutated is non-nil.
+func continueResponseBody(mutated []byte) *ext_proc.ProcessingResponse {
+	common := &ext_proc.CommonResponse{Status: ext_proc.CommonResponse_CONTINUE}
+	if mutated != nil {
+		common.BodyMutation = &ext_proc.BodyMutation{
+			Mutation: &ext_proc.BodyMutation_Body{Body: mutated},
+		}
+	}
+	return &ext_proc.ProcessingResponse{
+		Response: &ext_proc.ProcessingResponse_ResponseBody{
+			ResponseBody: &ext_proc.BodyResponse{Response: common},
+		},
+	}
+}
@@ -0,0 +1,123 @@
+package extproc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStreamTestCtx() *RequestContext {
+	return &RequestContext{TraceContext: context.Background()}
+}
+
+func TestSplitSSEFrames_SplitsCompleteFramesKeepsTail(t *testing.T) {
+	frames, rest := splitSSEFrames([]byte("data: a\n\ndata: b\n\ndata: partial"))
+	require.Len(t, frames, 2)
+	assert.Equal(t, "data: a", string(frames[0]))
+	assert.Equal(t, "data: b", string(frames[1]))
+	assert.Equal(t, "data: partial", string(rest))
+}
+
+func TestParseSSEFrame_DataAndDone(t *testing.T) {
+	data, isDone, ok := parseSSEFrame([]byte(`data: {"x":1}`))
+	require.True(t, ok)
+	assert.False(t, isDone)
+	assert.Equal(t, `{"x":1}`, string(data))
+
+	_, isDone, ok = parseSSEFrame([]byte("data: [DONE]"))
+	require.True(t, ok)
+	assert.True(t, isDone)
+
+	_, _, ok = parseSSEFrame([]byte("event: ping"))
+	assert.False(t, ok)
+}
+
+func TestSSEPipeline_MutatesChunkContentAcrossBoundary(t *testing.T) {
+	pipeline := &SSEPipeline{Plugins: []ResponseMutationPlugin{PIIRedactionPlugin{}}}
+	ctx := newStreamTestCtx()
+
+	chunk := openai.ChatCompletionChunk{
+		ID:      "chatcmpl-1",
+		Choices: []openai.ChatCompletionChunkChoice{{Delta: openai.ChatCompletionChunkChoiceDelta{Content: "email me at a@b.com"}}},
+	}
+	raw, err := json.Marshal(chunk)
+	require.NoError(t, err)
+
+	// Split the frame across two ProcessChunk calls to exercise the
+	// bounded-tail buffering.
+	first := append([]byte("data: "), raw[:len(raw)/2]...)
+	second := append(raw[len(raw)/2:], []byte("\n\n")...)
+
+	out1, err := pipeline.ProcessChunk(ctx, first, false)
+	require.NoError(t, err)
+	assert.Empty(t, out1, "no complete frame yet")
+
+	out2, err := pipeline.ProcessChunk(ctx, second, false)
+	require.NoError(t, err)
+	require.Contains(t, string(out2), "data: ")
+
+	payload := strings.TrimSuffix(strings.TrimPrefix(string(out2), "data: "), "\n\n")
+	var decoded openai.ChatCompletionChunk
+	require.NoError(t, json.Unmarshal([]byte(payload), &decoded))
+	assert.Equal(t, "email me at [REDACTED]", decoded.Choices[0].Delta.Content)
+}
+
+func TestSSEPipeline_InjectsTerminalFrameBeforeDone(t *testing.T) {
+	pipeline := &SSEPipeline{Plugins: []ResponseMutationPlugin{CostAnnotationPlugin{}, MemoryCitationPlugin{}}}
+	ctx := newStreamTestCtx()
+	memoryCitationIDs.Store(replayRequestID(ctx), []string{"mem_1", "mem_2"})
+
+	usageChunk := openai.ChatCompletionChunk{
+		ID:    "chatcmpl-1",
+		Usage: openai.CompletionUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}
+	raw, err := json.Marshal(usageChunk)
+	require.NoError(t, err)
+
+	input := []byte("data: " + string(raw) + "\n\ndata: [DONE]\n\n")
+	out, err := pipeline.ProcessChunk(ctx, input, true)
+	require.NoError(t, err)
+
+	frames := strings.Split(strings.TrimSpace(string(out)), "\n\n")
+	require.Len(t, frames, 3) // usage chunk, x_semantic_router, [DONE]
+	assert.Equal(t, "data: [DONE]", frames[2])
+
+	var final map[string]any
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(frames[1], "data: ")), &final))
+	router, ok := final["x_semantic_router"].(map[string]any)
+	require.True(t, ok)
+	assert.ElementsMatch(t, []any{"mem_1", "mem_2"}, router["memory_citations"])
+	cost, ok := router["cost"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, float64(15), cost["total_tokens"])
+}
+
+func TestSSEPipeline_PassesThroughMalformedFrameUnmodified(t *testing.T) {
+	pipeline := &SSEPipeline{Plugins: []ResponseMutationPlugin{PIIRedactionPlugin{}}}
+	ctx := newStreamTestCtx()
+
+	out, err := pipeline.ProcessChunk(ctx, []byte(": keep-alive\n\n"), false)
+	require.NoError(t, err)
+	assert.Equal(t, ": keep-alive\n\n", string(out))
+}
+
+func TestSSEPipeline_FlushesBufferedTailAtEndOfStream(t *testing.T) {
+	pipeline := &SSEPipeline{Plugins: nil}
+	ctx := newStreamTestCtx()
+
+	out, err := pipeline.ProcessChunk(ctx, []byte("data: trailing-no-terminator"), true)
+	require.NoError(t, err)
+	assert.Equal(t, "data: trailing-no-terminator", string(out))
+}
+
+func TestCostAnnotationPlugin_NoUsageContributesNothing(t *testing.T) {
+	plugin := CostAnnotationPlugin{}
+	fields, err := plugin.Finalize(newStreamTestCtx(), &streamState{})
+	require.NoError(t, err)
+	assert.Nil(t, fields)
+}
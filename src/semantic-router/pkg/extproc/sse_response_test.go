@@ -0,0 +1,42 @@
+package extproc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSSEResponse_FramesChunksAndTerminates(t *testing.T) {
+	r := &OpenAIRouter{}
+
+	resp := r.createSSEResponse(200, []openai.ChatCompletionChunk{{}, {}})
+
+	immediate := resp.GetImmediateResponse()
+	require.NotNil(t, immediate)
+	assert.EqualValues(t, 200, immediate.GetStatus().GetCode())
+
+	var contentType string
+	for _, h := range immediate.GetHeaders().GetSetHeaders() {
+		if h.GetHeader().GetKey() == "content-type" {
+			contentType = string(h.GetHeader().GetRawValue())
+		}
+	}
+	assert.Equal(t, "text/event-stream", contentType)
+
+	body := immediate.GetBody()
+	assert.Equal(t, 2, bytes.Count(body, []byte("data: {")))
+	assert.True(t, strings.HasSuffix(string(body), "data: [DONE]\n\n"))
+}
+
+func TestCreateSSEResponse_NoChunksStillTerminates(t *testing.T) {
+	r := &OpenAIRouter{}
+
+	resp := r.createSSEResponse(200, nil)
+
+	body := resp.GetImmediateResponse().GetBody()
+	assert.Equal(t, "data: [DONE]\n\n", string(body))
+}
@@ -0,0 +1,70 @@
+package extproc
+
+import (
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/vsrcontext"
+)
+
+// vsrContextTTL bounds how long a signed x-vsr-context envelope stays
+// valid, matching the short lifetime of the single upstream request it's
+// attached to rather than anything session-scoped.
+const vsrContextTTL = 5 * time.Minute
+
+// vsrContextHeaders builds and signs the x-vsr-context/x-vsr-context-sig
+// envelope for this request: the user/groups/decision/model the router
+// used to route it, so an upstream sidecar or audit tool can trust those
+// values came from the router's own authz/routing decision rather than
+// from a client that happened to set x-authz-user-id. Returns nil if
+// r.ContextSigner isn't configured, which leaves signing disabled.
+func (r *OpenAIRouter) vsrContextHeaders(ctx *RequestContext, model string) []*core.HeaderValueOption {
+	if r.ContextSigner == nil {
+		return nil
+	}
+
+	var userID string
+	if r.Config != nil {
+		userID = ctx.Headers[r.Config.Authz.Identity.GetUserIDHeader()]
+	}
+	var groups []string
+	if ctx.IdentityClaims != nil {
+		if ctx.IdentityClaims.UserID != "" {
+			userID = ctx.IdentityClaims.UserID
+		}
+		groups = ctx.IdentityClaims.Groups
+	}
+	var decisionName string
+	if ctx.VSRSelectedDecision != nil {
+		decisionName = ctx.VSRSelectedDecision.Name
+	}
+
+	contextValue, signature, err := r.ContextSigner.Sign(vsrcontext.Claims{
+		UserID:   userID,
+		Groups:   groups,
+		Decision: decisionName,
+		Model:    model,
+		Expiry:   time.Now().Add(vsrContextTTL),
+	})
+	if err != nil {
+		logging.Warnf("vsrcontext: failed to sign upstream context envelope: %v", err)
+		return nil
+	}
+
+	return []*core.HeaderValueOption{
+		{Header: &core.HeaderValue{Key: vsrcontext.ContextHeader, RawValue: []byte(contextValue)}},
+		{Header: &core.HeaderValue{Key: vsrcontext.SignatureHeader, RawValue: []byte(signature)}},
+	}
+}
+
+// vsrContextHeadersToStrip lists the headers a client could set to try to
+// forge a router-signed context envelope. Every place that strips inbound
+// auth-adjacent headers before forwarding upstream (alongside
+// CredentialResolver.HeadersToStrip()) strips these too, so the only
+// x-vsr-context*/x-vsr-context-sig a request ever carries upstream is the
+// one vsrContextHeaders just signed.
+func vsrContextHeadersToStrip() []string {
+	return []string{vsrcontext.ContextHeader, vsrcontext.SignatureHeader}
+}
@@ -0,0 +1,66 @@
+package gemini
+
+import "strconv"
+
+// Header is a single header mutation BuildRequestHeaders produces, mirroring
+// the anthropic package's own Header convention so callers in pkg/extproc
+// can turn either provider's headers into Envoy HeaderValueOptions the same
+// way.
+type Header struct {
+	Key   string
+	Value string
+}
+
+// EndpointMode selects which Google-hosted API a Gemini request targets,
+// since the two accept different credentials and host names for the same
+// generateContent schema.
+type EndpointMode string
+
+const (
+	// EndpointDirect targets generativelanguage.googleapis.com, authenticated
+	// with a plain Gemini API key.
+	EndpointDirect EndpointMode = "direct"
+	// EndpointVertex targets a Vertex AI regional endpoint, authenticated
+	// with a bearer OAuth access token instead of an API key.
+	EndpointVertex EndpointMode = "vertex"
+)
+
+// BuildRequestHeaders returns the header mutations needed to forward a
+// translated request to the selected Gemini endpoint. For EndpointDirect,
+// accessKey is sent as the x-goog-api-key header; for EndpointVertex it's
+// sent as an Authorization bearer token, matching Vertex's OAuth2-only
+// authentication.
+func BuildRequestHeaders(accessKey string, bodyLen int, mode EndpointMode) []Header {
+	headers := []Header{
+		{Key: "content-type", Value: "application/json"},
+		{Key: "content-length", Value: strconv.Itoa(bodyLen)},
+	}
+	switch mode {
+	case EndpointVertex:
+		headers = append(headers, Header{Key: "authorization", Value: "Bearer " + accessKey})
+	default:
+		headers = append(headers, Header{Key: "x-goog-api-key", Value: accessKey})
+	}
+	return headers
+}
+
+// HeadersToRemove lists request headers that must not be forwarded upstream
+// to Google - primarily the OpenAI-style Authorization bearer header the
+// original client request carried, which would otherwise sit alongside (or
+// be mistaken for) the credentials BuildRequestHeaders just set.
+func HeadersToRemove() []string {
+	return []string{"authorization", "x-api-key"}
+}
+
+// Host returns the upstream host generateContent requests in mode should be
+// routed to, for use in Envoy routing decisions and trace span attributes.
+// location is only consulted for EndpointVertex.
+func Host(mode EndpointMode, location string) string {
+	if mode == EndpointVertex {
+		if location == "" {
+			location = "us-central1"
+		}
+		return location + "-aiplatform.googleapis.com"
+	}
+	return "generativelanguage.googleapis.com"
+}
@@ -0,0 +1,298 @@
+// Package gemini translates between the OpenAI Chat Completions request/
+// response shapes this router speaks internally and Google's Gemini
+// generateContent API, mirroring pkg/anthropic's role for the Anthropic
+// backend.
+package gemini
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/openai/openai-go"
+)
+
+// Content is one turn of a Gemini generateContent conversation.
+type Content struct {
+	Role  string `json:"role,omitempty"` // "user" or "model"; omitted for the system instruction
+	Parts []Part `json:"parts"`
+}
+
+// Part is one piece of a Content turn. Exactly one of Text,
+// FunctionCall, or FunctionResponse is set per part, matching Gemini's
+// oneof part schema.
+type Part struct {
+	Text             string            `json:"text,omitempty"`
+	FunctionCall     *FunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *FunctionResponse `json:"functionResponse,omitempty"`
+}
+
+// FunctionCall is a model-issued tool call, the Gemini analogue of an
+// OpenAI tool_calls entry.
+type FunctionCall struct {
+	Name string         `json:"name"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// FunctionResponse is a tool result being fed back to the model, the
+// Gemini analogue of an OpenAI role:"tool" message.
+type FunctionResponse struct {
+	Name     string         `json:"name"`
+	Response map[string]any `json:"response"`
+}
+
+// GenerationConfig carries the sampling parameters Gemini accepts at the
+// top level of a generateContent request, translated from their OpenAI
+// equivalents.
+type GenerationConfig struct {
+	Temperature     *float64 `json:"temperature,omitempty"`
+	TopP            *float64 `json:"topP,omitempty"`
+	MaxOutputTokens *int64   `json:"maxOutputTokens,omitempty"`
+	StopSequences   []string `json:"stopSequences,omitempty"`
+}
+
+// SafetySetting configures one Gemini harm category's blocking threshold.
+// ToGeminiRequestBody doesn't set these itself (OpenAI's request shape has
+// no equivalent knob) - it's here so a caller can add deployment-specific
+// thresholds to a Request before marshaling.
+type SafetySetting struct {
+	Category  string `json:"category"`
+	Threshold string `json:"threshold"`
+}
+
+// FunctionDeclaration is one callable tool, the Gemini analogue of an
+// OpenAI tools[].function entry.
+type FunctionDeclaration struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+// ToolDeclaration wraps the function declarations Gemini will consider for
+// this request - Gemini groups every callable function under a single
+// tools[0].functionDeclarations list, rather than OpenAI's flat tools[]
+// array of individually-typed entries.
+type ToolDeclaration struct {
+	FunctionDeclarations []FunctionDeclaration `json:"functionDeclarations"`
+}
+
+// Request is the top-level generateContent request body.
+type Request struct {
+	Contents          []Content         `json:"contents"`
+	SystemInstruction *Content          `json:"systemInstruction,omitempty"`
+	GenerationConfig  *GenerationConfig `json:"generationConfig,omitempty"`
+	Tools             []ToolDeclaration `json:"tools,omitempty"`
+	SafetySettings    []SafetySetting   `json:"safetySettings,omitempty"`
+}
+
+// wireMessage mirrors the stable, publicly-documented JSON shape of an
+// OpenAI chat message - rather than openai-go's internal param union types
+// directly - so conversion doesn't need to track that SDK's exact Go field
+// names, only the wire format both it and every other OpenAI-compatible
+// client agree on.
+type wireMessage struct {
+	Role       string `json:"role"`
+	Content    any    `json:"content"`
+	Name       string `json:"name,omitempty"`
+	ToolCallID string `json:"tool_call_id,omitempty"`
+	ToolCalls  []struct {
+		Function struct {
+			Name      string `json:"name"`
+			Arguments string `json:"arguments"`
+		} `json:"function"`
+	} `json:"tool_calls,omitempty"`
+}
+
+type wireTool struct {
+	Function struct {
+		Name        string         `json:"name"`
+		Description string         `json:"description"`
+		Parameters  map[string]any `json:"parameters"`
+	} `json:"function"`
+}
+
+type wireRequest struct {
+	Messages    []wireMessage `json:"messages"`
+	Temperature *float64      `json:"temperature"`
+	TopP        *float64      `json:"top_p"`
+	MaxTokens   *int64        `json:"max_tokens"`
+	Stop        any           `json:"stop"`
+	Tools       []wireTool    `json:"tools"`
+}
+
+// ToGeminiRequestBody translates an OpenAI ChatCompletionNewParams into a
+// Gemini generateContent request body. System messages are pulled out into
+// SystemInstruction (Gemini has no role:"system" turn); every other message
+// becomes a Content entry, with OpenAI's "assistant" role renamed to
+// Gemini's "model" and "tool" results rendered as a FunctionResponse part.
+func ToGeminiRequestBody(openAIRequest *openai.ChatCompletionNewParams) ([]byte, error) {
+	raw, err := json.Marshal(openAIRequest)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling OpenAI request: %w", err)
+	}
+
+	var wire wireRequest
+	if err := json.Unmarshal(raw, &wire); err != nil {
+		return nil, fmt.Errorf("decoding OpenAI request for Gemini translation: %w", err)
+	}
+
+	req := Request{}
+	for _, msg := range wire.Messages {
+		content, isSystem, err := convertMessage(msg)
+		if err != nil {
+			return nil, fmt.Errorf("converting message to Gemini content: %w", err)
+		}
+		if content == nil {
+			continue
+		}
+		if isSystem {
+			req.SystemInstruction = content
+			continue
+		}
+		req.Contents = append(req.Contents, *content)
+	}
+
+	if cfg := convertGenerationConfig(wire); cfg != nil {
+		req.GenerationConfig = cfg
+	}
+	if tools := convertTools(wire.Tools); len(tools) > 0 {
+		req.Tools = []ToolDeclaration{{FunctionDeclarations: tools}}
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling Gemini request: %w", err)
+	}
+	return data, nil
+}
+
+// convertMessage converts a single OpenAI wire message into a Gemini
+// Content. The second return value reports whether the message was a
+// system prompt (returned as the would-be SystemInstruction rather than a
+// Content turn).
+func convertMessage(msg wireMessage) (*Content, bool, error) {
+	switch msg.Role {
+	case "system", "developer":
+		return &Content{Parts: []Part{{Text: textFromContent(msg.Content)}}}, true, nil
+
+	case "user":
+		return &Content{Role: "user", Parts: []Part{{Text: textFromContent(msg.Content)}}}, false, nil
+
+	case "assistant":
+		content := &Content{Role: "model"}
+		if text := textFromContent(msg.Content); text != "" {
+			content.Parts = append(content.Parts, Part{Text: text})
+		}
+		for _, call := range msg.ToolCalls {
+			var args map[string]any
+			if call.Function.Arguments != "" {
+				if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+					return nil, false, fmt.Errorf("parsing tool call arguments for %s: %w", call.Function.Name, err)
+				}
+			}
+			content.Parts = append(content.Parts, Part{
+				FunctionCall: &FunctionCall{Name: call.Function.Name, Args: args},
+			})
+		}
+		return content, false, nil
+
+	case "tool":
+		var response map[string]any
+		text := textFromContent(msg.Content)
+		if err := json.Unmarshal([]byte(text), &response); err != nil {
+			response = map[string]any{"result": text}
+		}
+		name := msg.Name
+		if name == "" {
+			name = msg.ToolCallID
+		}
+		return &Content{
+			Role:  "user",
+			Parts: []Part{{FunctionResponse: &FunctionResponse{Name: name, Response: response}}},
+		}, false, nil
+
+	default:
+		return nil, false, fmt.Errorf("unsupported message role %q", msg.Role)
+	}
+}
+
+// textFromContent flattens an OpenAI message's content field - a plain
+// string in the common case, or an array of {type, text} parts for
+// multi-part content - down to plain text. Non-text parts (image_url,
+// etc.) aren't representable in Gemini's text-only Part.Text field and are
+// skipped.
+func textFromContent(content any) string {
+	switch c := content.(type) {
+	case string:
+		return c
+	case []any:
+		text := ""
+		for _, part := range c {
+			m, ok := part.(map[string]any)
+			if !ok {
+				continue
+			}
+			if t, _ := m["text"].(string); t != "" {
+				text += t
+			}
+		}
+		return text
+	default:
+		return ""
+	}
+}
+
+// convertGenerationConfig pulls OpenAI's sampling parameters into a Gemini
+// GenerationConfig, returning nil if none were set.
+func convertGenerationConfig(wire wireRequest) *GenerationConfig {
+	cfg := &GenerationConfig{}
+	set := false
+
+	if wire.Temperature != nil {
+		cfg.Temperature = wire.Temperature
+		set = true
+	}
+	if wire.TopP != nil {
+		cfg.TopP = wire.TopP
+		set = true
+	}
+	if wire.MaxTokens != nil {
+		cfg.MaxOutputTokens = wire.MaxTokens
+		set = true
+	}
+	switch stop := wire.Stop.(type) {
+	case string:
+		cfg.StopSequences = []string{stop}
+		set = true
+	case []any:
+		for _, s := range stop {
+			if str, ok := s.(string); ok {
+				cfg.StopSequences = append(cfg.StopSequences, str)
+			}
+		}
+		if len(cfg.StopSequences) > 0 {
+			set = true
+		}
+	}
+
+	if !set {
+		return nil
+	}
+	return cfg
+}
+
+// convertTools translates OpenAI's tools[] into Gemini function
+// declarations.
+func convertTools(tools []wireTool) []FunctionDeclaration {
+	declarations := make([]FunctionDeclaration, 0, len(tools))
+	for _, tool := range tools {
+		if tool.Function.Name == "" {
+			continue
+		}
+		declarations = append(declarations, FunctionDeclaration{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			Parameters:  tool.Function.Parameters,
+		})
+	}
+	return declarations
+}
@@ -0,0 +1,94 @@
+package gemini
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertMessage_System(t *testing.T) {
+	content, isSystem, err := convertMessage(wireMessage{Role: "system", Content: "be concise"})
+	require.NoError(t, err)
+	assert.True(t, isSystem)
+	require.Len(t, content.Parts, 1)
+	assert.Equal(t, "be concise", content.Parts[0].Text)
+}
+
+func TestConvertMessage_AssistantToolCall(t *testing.T) {
+	var msg wireMessage
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"role": "assistant",
+		"tool_calls": [{"function": {"name": "get_weather", "arguments": "{\"location\":\"SF\"}"}}]
+	}`), &msg))
+
+	content, isSystem, err := convertMessage(msg)
+	require.NoError(t, err)
+	assert.False(t, isSystem)
+	assert.Equal(t, "model", content.Role)
+	require.Len(t, content.Parts, 1)
+	require.NotNil(t, content.Parts[0].FunctionCall)
+	assert.Equal(t, "get_weather", content.Parts[0].FunctionCall.Name)
+	assert.Equal(t, "SF", content.Parts[0].FunctionCall.Args["location"])
+}
+
+func TestConvertMessage_ToolResult(t *testing.T) {
+	content, isSystem, err := convertMessage(wireMessage{
+		Role:       "tool",
+		Content:    `{"temp":72}`,
+		ToolCallID: "call_1",
+	})
+	require.NoError(t, err)
+	assert.False(t, isSystem)
+	require.Len(t, content.Parts, 1)
+	require.NotNil(t, content.Parts[0].FunctionResponse)
+	assert.Equal(t, "call_1", content.Parts[0].FunctionResponse.Name)
+	assert.EqualValues(t, 72, content.Parts[0].FunctionResponse.Response["temp"])
+}
+
+func TestConvertMessage_UnsupportedRole(t *testing.T) {
+	_, _, err := convertMessage(wireMessage{Role: "bogus"})
+	assert.Error(t, err)
+}
+
+func TestTextFromContent_MultiPart(t *testing.T) {
+	var content any
+	require.NoError(t, json.Unmarshal([]byte(`[{"type":"text","text":"hi "},{"type":"text","text":"there"}]`), &content))
+	assert.Equal(t, "hi there", textFromContent(content))
+}
+
+func TestConvertGenerationConfig_StopStringAndArray(t *testing.T) {
+	cfg := convertGenerationConfig(wireRequest{Stop: "STOP"})
+	require.NotNil(t, cfg)
+	assert.Equal(t, []string{"STOP"}, cfg.StopSequences)
+
+	var stops any
+	require.NoError(t, json.Unmarshal([]byte(`["a","b"]`), &stops))
+	cfg = convertGenerationConfig(wireRequest{Stop: stops})
+	require.NotNil(t, cfg)
+	assert.Equal(t, []string{"a", "b"}, cfg.StopSequences)
+}
+
+func TestToOpenAIResponseBody_Text(t *testing.T) {
+	geminiResp := `{
+		"candidates": [{
+			"content": {"role": "model", "parts": [{"text": "Hello"}]},
+			"finishReason": "STOP",
+			"index": 0
+		}],
+		"usageMetadata": {"promptTokenCount": 10, "candidatesTokenCount": 5, "totalTokenCount": 15}
+	}`
+
+	out, err := ToOpenAIResponseBody([]byte(geminiResp), "gemini-1.5-pro", "cmpl-123")
+	require.NoError(t, err)
+
+	var completion wireCompletion
+	require.NoError(t, json.Unmarshal(out, &completion))
+	assert.Equal(t, "cmpl-123", completion.ID)
+	assert.Equal(t, "gemini-1.5-pro", completion.Model)
+	require.Len(t, completion.Choices, 1)
+	assert.Equal(t, "Hello", completion.Choices[0].Message.Content)
+	assert.Equal(t, "stop", completion.Choices[0].FinishReason)
+	assert.EqualValues(t, 15, completion.Usage.TotalTokens)
+}
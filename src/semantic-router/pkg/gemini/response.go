@@ -0,0 +1,148 @@
+package gemini
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Response is a Gemini generateContent response body.
+type Response struct {
+	Candidates    []Candidate    `json:"candidates"`
+	UsageMetadata *UsageMetadata `json:"usageMetadata,omitempty"`
+}
+
+// Candidate is one generated completion. Gemini supports multiple
+// candidates per request (candidateCount); ToOpenAIResponseBody carries
+// each one through as its own OpenAI choice.
+type Candidate struct {
+	Content      Content `json:"content"`
+	FinishReason string  `json:"finishReason,omitempty"`
+	Index        int64   `json:"index"`
+}
+
+// UsageMetadata is Gemini's token accounting, the analogue of OpenAI's
+// CompletionUsage.
+type UsageMetadata struct {
+	PromptTokenCount     int64 `json:"promptTokenCount"`
+	CandidatesTokenCount int64 `json:"candidatesTokenCount"`
+	TotalTokenCount      int64 `json:"totalTokenCount"`
+}
+
+// geminiFinishReasonToOpenAI maps Gemini's finishReason vocabulary to
+// OpenAI's finish_reason vocabulary, the response-side counterpart of
+// anthropicStopReasonToOpenAI in pkg/extproc.
+var geminiFinishReasonToOpenAI = map[string]string{
+	"STOP":       "stop",
+	"MAX_TOKENS": "length",
+	"SAFETY":     "content_filter",
+	"RECITATION": "content_filter",
+}
+
+// wireChoice and wireCompletion mirror the stable, publicly-documented
+// OpenAI chat.completion JSON shape - see the matching wireRequest/
+// wireMessage pair in request.go for why this package targets the wire
+// format rather than openai-go's Go struct field names directly.
+type wireToolCall struct {
+	Index    int64  `json:"index"`
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type wireResponseMessage struct {
+	Role      string         `json:"role"`
+	Content   string         `json:"content"`
+	ToolCalls []wireToolCall `json:"tool_calls,omitempty"`
+}
+
+type wireChoice struct {
+	Index        int64               `json:"index"`
+	Message      wireResponseMessage `json:"message"`
+	FinishReason string              `json:"finish_reason"`
+}
+
+type wireUsage struct {
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+	TotalTokens      int64 `json:"total_tokens"`
+}
+
+type wireCompletion struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"`
+	Model   string       `json:"model"`
+	Choices []wireChoice `json:"choices"`
+	Usage   wireUsage    `json:"usage"`
+}
+
+// ToOpenAIResponseBody translates a Gemini generateContent response body
+// into an OpenAI chat.completion response body, for the response-body
+// phase to hand back to the original client in the format it asked for.
+// id is the request-scoped completion ID to stamp on the result (Gemini
+// responses don't carry one of their own).
+func ToOpenAIResponseBody(geminiBody []byte, model string, id string) ([]byte, error) {
+	var resp Response
+	if err := json.Unmarshal(geminiBody, &resp); err != nil {
+		return nil, fmt.Errorf("decoding Gemini response: %w", err)
+	}
+
+	completion := wireCompletion{
+		ID:     id,
+		Object: "chat.completion",
+		Model:  model,
+	}
+
+	for _, cand := range resp.Candidates {
+		msg := wireResponseMessage{Role: "assistant"}
+		for _, part := range cand.Content.Parts {
+			switch {
+			case part.FunctionCall != nil:
+				args, err := json.Marshal(part.FunctionCall.Args)
+				if err != nil {
+					return nil, fmt.Errorf("marshaling function call args for %s: %w", part.FunctionCall.Name, err)
+				}
+				call := wireToolCall{
+					Index: int64(len(msg.ToolCalls)),
+					ID:    fmt.Sprintf("call_%s_%d", part.FunctionCall.Name, len(msg.ToolCalls)),
+					Type:  "function",
+				}
+				call.Function.Name = part.FunctionCall.Name
+				call.Function.Arguments = string(args)
+				msg.ToolCalls = append(msg.ToolCalls, call)
+			case part.Text != "":
+				msg.Content += part.Text
+			}
+		}
+
+		finishReason := geminiFinishReasonToOpenAI[cand.FinishReason]
+		if finishReason == "" {
+			finishReason = "stop"
+		}
+		if len(msg.ToolCalls) > 0 {
+			finishReason = "tool_calls"
+		}
+
+		completion.Choices = append(completion.Choices, wireChoice{
+			Index:        cand.Index,
+			Message:      msg,
+			FinishReason: finishReason,
+		})
+	}
+
+	if resp.UsageMetadata != nil {
+		completion.Usage = wireUsage{
+			PromptTokens:     resp.UsageMetadata.PromptTokenCount,
+			CompletionTokens: resp.UsageMetadata.CandidatesTokenCount,
+			TotalTokens:      resp.UsageMetadata.TotalTokenCount,
+		}
+	}
+
+	data, err := json.Marshal(completion)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling OpenAI response: %w", err)
+	}
+	return data, nil
+}
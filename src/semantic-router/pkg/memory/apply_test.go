@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"testing"
+)
+
+func TestInMemoryStore_Apply_PutUpdateForget(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	mem := &Memory{ID: "mem_a", Type: MemoryTypeSemantic, Content: "User likes tea", UserID: "user_1"}
+	if err := store.Store(ctx, mem); err != nil {
+		t.Fatalf("seed Store: %v", err)
+	}
+
+	result, err := store.Apply(ctx, []MemoryOp{
+		{Kind: MemoryOpPut, Memory: &Memory{Type: MemoryTypeSemantic, Content: "User likes coffee", UserID: "user_1"}},
+		{Kind: MemoryOpUpdate, ID: mem.ID, Memory: &Memory{Type: MemoryTypeSemantic, Content: "User likes green tea", UserID: "user_1"}},
+		{Kind: MemoryOpForget, ID: mem.ID},
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if len(result.Created) != 1 {
+		t.Errorf("Created = %v, want 1 entry", result.Created)
+	}
+	if len(result.Updated) != 1 || result.Updated[0] != mem.ID {
+		t.Errorf("Updated = %v, want [%s]", result.Updated, mem.ID)
+	}
+	if len(result.Forgotten) != 1 || result.Forgotten[0] != mem.ID {
+		t.Errorf("Forgotten = %v, want [%s]", result.Forgotten, mem.ID)
+	}
+
+	if _, err := store.Get(ctx, mem.ID); err != ErrNotFound {
+		t.Errorf("Get after forget: got %v, want ErrNotFound", err)
+	}
+	if _, err := store.Get(ctx, result.Created[0]); err != nil {
+		t.Errorf("Get created memory: %v", err)
+	}
+}
+
+func TestInMemoryStore_Apply_RollsBackOnError(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	mem := &Memory{ID: "mem_a", Type: MemoryTypeSemantic, Content: "User likes tea", UserID: "user_1"}
+	if err := store.Store(ctx, mem); err != nil {
+		t.Fatalf("seed Store: %v", err)
+	}
+
+	_, err := store.Apply(ctx, []MemoryOp{
+		{Kind: MemoryOpForget, ID: mem.ID},
+		{Kind: MemoryOpUpdate, ID: "mem_does_not_exist", Memory: &Memory{Content: "nope", UserID: "user_1"}},
+	})
+	if err == nil {
+		t.Fatal("Apply with a failing op: got nil error, want one")
+	}
+
+	// The forget should have been rolled back along with the failed update.
+	if _, err := store.Get(ctx, mem.ID); err != nil {
+		t.Errorf("Get after rolled-back Apply: %v, want memory still present", err)
+	}
+}
+
+func TestInMemoryStore_Apply_PutWithDedupFoldsIntoUpdate(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	existing := &Memory{ID: "mem_budget", Type: MemoryTypeSemantic, Content: "User's budget for Hawaii vacation is $10,000", UserID: "user_1"}
+	if err := store.Store(ctx, existing); err != nil {
+		t.Fatalf("seed Store: %v", err)
+	}
+
+	config := DefaultDeduplicationConfig()
+	config.SimilarityThreshold = 0 // force the match regardless of embedding availability in this environment
+	result, err := store.Apply(ctx, []MemoryOp{
+		{
+			Kind:        MemoryOpPut,
+			Memory:      &Memory{Type: MemoryTypeSemantic, Content: "User's budget for Hawaii trip is now $15,000", UserID: "user_1"},
+			Dedup:       true,
+			DedupConfig: &config,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if result.Deduplicated != 1 {
+		t.Errorf("Deduplicated = %d, want 1", result.Deduplicated)
+	}
+	if len(result.Created) != 0 {
+		t.Errorf("Created = %v, want none (should have folded into an update)", result.Created)
+	}
+	if len(result.Updated) != 1 || result.Updated[0] != existing.ID {
+		t.Errorf("Updated = %v, want [%s]", result.Updated, existing.ID)
+	}
+
+	got, err := store.Get(ctx, existing.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Content != "User's budget for Hawaii trip is now $15,000" {
+		t.Errorf("Get content = %q, want the deduplicated Put's content", got.Content)
+	}
+}
@@ -0,0 +1,318 @@
+package memory
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Audit log
+// =============================================================================
+
+// AuditOperation classifies what an AuditEntry records.
+type AuditOperation string
+
+const (
+	AuditOpStore    AuditOperation = "store"
+	AuditOpRetrieve AuditOperation = "retrieve"
+	AuditOpForget   AuditOperation = "forget"
+)
+
+// AuditEntry records one memory operation for the tamper-evident log. Hash
+// chains over every preceding entry (PrevHash -> Hash), so altering or
+// removing an earlier entry is detectable by AuditLog.Verify even without a
+// signer configured; Signature adds non-repudiation on top of that when one
+// is.
+type AuditEntry struct {
+	Sequence  int64          // 1-based position in the log
+	Timestamp time.Time      // when the operation was recorded
+	Operation AuditOperation // store, retrieve, or forget
+	UserID    string
+	ProjectID string // optional
+	MemoryID  string
+	Caller    string // caller identity from the Response API request, "" if unresolved
+
+	PrevHash string // hex SHA-256 of the previous entry's canonical form, "" for the first entry
+	Hash     string // hex SHA-256 of this entry's canonical form (everything above)
+
+	// Signature is hex-encoded, over Hash's raw bytes, using the AuditLog's
+	// configured AuditSigner. Empty if no signer is configured.
+	Signature string
+}
+
+// auditEntryCanonical is the subset of AuditEntry that Hash/Signature are
+// computed over - i.e. everything except themselves. A separate type (not
+// AuditEntry with its Hash/Signature fields zeroed) makes it impossible to
+// accidentally fold Hash into its own input.
+type auditEntryCanonical struct {
+	Sequence  int64
+	Timestamp time.Time
+	Operation AuditOperation
+	UserID    string
+	ProjectID string
+	MemoryID  string
+	Caller    string
+	PrevHash  string
+}
+
+func canonicalize(e *AuditEntry) ([]byte, error) {
+	data, err := json.Marshal(auditEntryCanonical{
+		Sequence:  e.Sequence,
+		Timestamp: e.Timestamp,
+		Operation: e.Operation,
+		UserID:    e.UserID,
+		ProjectID: e.ProjectID,
+		MemoryID:  e.MemoryID,
+		Caller:    e.Caller,
+		PrevHash:  e.PrevHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("memory: canonicalize audit entry: %w", err)
+	}
+	return data, nil
+}
+
+// AuditSigner signs an AuditEntry's hash so the log's integrity doesn't
+// depend solely on whoever holds it not rewriting every entry's hash chain
+// from scratch. HMACKeySigner and Ed25519Signer are the two built-in
+// implementations; either can be nil-equivalent by leaving AuditLog's
+// signer unset, which disables Signature entirely.
+type AuditSigner interface {
+	Sign(data []byte) ([]byte, error)
+}
+
+// AuditVerifier is the read side of AuditSigner. HMACKeySigner implements
+// both, since the same symmetric key both produces and checks a MAC.
+// Ed25519Signer only implements AuditSigner - verifying its signatures
+// needs Ed25519Verifier with the corresponding public key instead, since
+// the signing key should never need to leave wherever entries are written.
+// NewAuditLogVerifier is the entry point for that: it builds an AuditLog
+// from only an AuditVerifier and entries to check, with no AuditSigner in
+// sight.
+type AuditVerifier interface {
+	Verify(data, signature []byte) error
+}
+
+// HMACKeySigner signs and verifies audit entries with HMAC-SHA256 over a
+// shared secret. Simpler to operate than Ed25519Signer when the same
+// process both writes and later verifies the log.
+type HMACKeySigner struct {
+	Key []byte
+}
+
+func (s HMACKeySigner) Sign(data []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, s.Key)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+func (s HMACKeySigner) Verify(data, signature []byte) error {
+	mac := hmac.New(sha256.New, s.Key)
+	mac.Write(data)
+	want := mac.Sum(nil)
+	if subtle.ConstantTimeCompare(want, signature) != 1 {
+		return fmt.Errorf("memory: audit HMAC verification failed")
+	}
+	return nil
+}
+
+// Ed25519Signer signs audit entries with an Ed25519 private key, for
+// deployments that want non-repudiation (the log's writer can prove a
+// signature without being able to forge one using only the public half of
+// the key pair, unlike HMACKeySigner's shared secret).
+type Ed25519Signer struct {
+	PrivateKey ed25519.PrivateKey
+}
+
+func (s Ed25519Signer) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(s.PrivateKey, data), nil
+}
+
+// Ed25519Verifier verifies signatures produced by an Ed25519Signer using
+// only the corresponding public key.
+type Ed25519Verifier struct {
+	PublicKey ed25519.PublicKey
+}
+
+func (v Ed25519Verifier) Verify(data, signature []byte) error {
+	if !ed25519.Verify(v.PublicKey, data, signature) {
+		return fmt.Errorf("memory: audit Ed25519 verification failed")
+	}
+	return nil
+}
+
+// AuditLog is an append-only, hash-chained record of memory operations.
+// Append is safe for concurrent use; entries are immutable once appended.
+type AuditLog struct {
+	mu sync.Mutex
+
+	signer AuditSigner
+
+	// verifier, when set (by NewAuditLogVerifier), is used for Verify
+	// instead of type-asserting signer - letting a log be built for
+	// verification from only an AuditVerifier such as Ed25519Verifier's
+	// public key, with no AuditSigner present at all.
+	verifier AuditVerifier
+
+	// readOnly marks a log built by NewAuditLogVerifier: it has no
+	// AuditSigner, so Append is refused rather than silently producing
+	// entries with no Signature.
+	readOnly bool
+
+	entries  []*AuditEntry
+	lastHash string
+}
+
+// NewAuditLog creates an empty AuditLog. signer may be nil, in which case
+// entries are still hash-chained (so Verify still detects tampering with
+// the log's own copy) but carry no Signature.
+func NewAuditLog(signer AuditSigner) *AuditLog {
+	return &AuditLog{signer: signer}
+}
+
+// NewAuditLogVerifier builds a read-only AuditLog over entries produced
+// elsewhere (e.g. read back from wherever the writing process persisted
+// them), verified using only verifier - an Ed25519Verifier holding the
+// public key half of whatever Ed25519Signer wrote them, say - with no
+// AuditSigner and therefore no access to the signing key. This is the
+// entry point AuditVerifier's doc comment promises: a process that only
+// ever verifies a log never needs to hold (or even be near) the key that
+// signs it. The chain is verified immediately; a non-nil error means
+// entries is already tampered and no AuditLog is returned.
+func NewAuditLogVerifier(verifier AuditVerifier, entries []*AuditEntry) (*AuditLog, error) {
+	a := &AuditLog{verifier: verifier, entries: entries, readOnly: true}
+	if len(entries) > 0 {
+		a.lastHash = entries[len(entries)-1].Hash
+	}
+	if err := a.verifyLocked(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Append records one operation, chaining it to the previous entry's hash
+// and signing it if a signer is configured. Refuses on a log built by
+// NewAuditLogVerifier, which has no AuditSigner to sign with.
+func (a *AuditLog) Append(op AuditOperation, userID, projectID, memoryID, caller string) (*AuditEntry, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.readOnly {
+		return nil, fmt.Errorf("memory: cannot append to a verify-only AuditLog (built via NewAuditLogVerifier)")
+	}
+
+	entry := &AuditEntry{
+		Sequence:  int64(len(a.entries)) + 1,
+		Timestamp: time.Now(),
+		Operation: op,
+		UserID:    userID,
+		ProjectID: projectID,
+		MemoryID:  memoryID,
+		Caller:    caller,
+		PrevHash:  a.lastHash,
+	}
+
+	canon, err := canonicalize(entry)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(canon)
+	entry.Hash = hex.EncodeToString(sum[:])
+
+	if a.signer != nil {
+		sig, err := a.signer.Sign(sum[:])
+		if err != nil {
+			return nil, fmt.Errorf("memory: sign audit entry %d: %w", entry.Sequence, err)
+		}
+		entry.Signature = hex.EncodeToString(sig)
+	}
+
+	a.entries = append(a.entries, entry)
+	a.lastHash = entry.Hash
+	return entry, nil
+}
+
+// Verify walks the entire chain, recomputing each entry's hash and - if a
+// verifier is set, or else the configured signer also implements
+// AuditVerifier - its signature,
+// returning the first mismatch found. A nil error means the log is intact
+// from the first entry onward.
+func (a *AuditLog) Verify() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.verifyLocked()
+}
+
+func (a *AuditLog) verifyLocked() error {
+	verifier := a.verifier
+	if verifier == nil {
+		verifier, _ = a.signer.(AuditVerifier)
+	}
+
+	prevHash := ""
+	for _, e := range a.entries {
+		if e.PrevHash != prevHash {
+			return fmt.Errorf("memory: audit chain broken at sequence %d: prev_hash does not match entry %d", e.Sequence, e.Sequence-1)
+		}
+
+		canon, err := canonicalize(e)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(canon)
+		wantHash := hex.EncodeToString(sum[:])
+		if e.Hash != wantHash {
+			return fmt.Errorf("memory: audit entry %d hash mismatch - possible tampering", e.Sequence)
+		}
+
+		if verifier != nil && e.Signature != "" {
+			sig, err := hex.DecodeString(e.Signature)
+			if err != nil {
+				return fmt.Errorf("memory: audit entry %d has an unparseable signature: %w", e.Sequence, err)
+			}
+			if err := verifier.Verify(sum[:], sig); err != nil {
+				return fmt.Errorf("memory: audit entry %d signature invalid: %w", e.Sequence, err)
+			}
+		}
+
+		prevHash = e.Hash
+	}
+	return nil
+}
+
+// Entries returns every entry in the log, oldest first, after verifying the
+// full chain - a caller never observes entries from a log whose integrity
+// has already been compromised. Each returned entry is a copy, so mutating
+// it leaves the log's own entries untouched.
+func (a *AuditLog) Entries() ([]*AuditEntry, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.verifyLocked(); err != nil {
+		return nil, err
+	}
+	out := make([]*AuditEntry, len(a.entries))
+	for i, e := range a.entries {
+		copied := *e
+		out[i] = &copied
+	}
+	return out, nil
+}
+
+// StoreAuditor is an optional capability a Store backend may implement to
+// expose its own AuditLog, for backends that record operations closer to
+// the storage layer than pkg/extproc's MemoryFilter does. It is kept
+// separate from Store, the same way StoreConsolidator/StoreHealthChecker
+// are, so a backend with no audit log of its own is not forced to implement
+// it; MemoryFilter's own AuditLog (see WithAuditLog) covers the common case
+// of auditing at the request-handling layer instead.
+type StoreAuditor interface {
+	Audit() *AuditLog
+}
@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+)
+
+func generateEd25519KeyForTest() (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	return ed25519.GenerateKey(rand.Reader)
+}
+
+func TestAuditLog_AppendChainsAndVerifies(t *testing.T) {
+	log := NewAuditLog(nil)
+
+	if _, err := log.Append(AuditOpStore, "user-1", "proj-1", "mem-1", "caller-1"); err != nil {
+		t.Fatalf("Append store: %v", err)
+	}
+	if _, err := log.Append(AuditOpRetrieve, "user-1", "proj-1", "", "caller-1"); err != nil {
+		t.Fatalf("Append retrieve: %v", err)
+	}
+
+	entries, err := log.Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("len(entries) = %d, want 2", len(entries))
+	}
+	if entries[0].PrevHash != "" {
+		t.Fatalf("first entry PrevHash = %q, want empty", entries[0].PrevHash)
+	}
+	if entries[1].PrevHash != entries[0].Hash {
+		t.Fatalf("second entry PrevHash = %q, want %q", entries[1].PrevHash, entries[0].Hash)
+	}
+
+	if err := log.Verify(); err != nil {
+		t.Fatalf("Verify on untampered log: %v", err)
+	}
+}
+
+func TestAuditLog_VerifyDetectsTampering(t *testing.T) {
+	log := NewAuditLog(nil)
+	if _, err := log.Append(AuditOpStore, "user-1", "", "mem-1", ""); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	entries, err := log.Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	entries[0].UserID = "user-2" // mutate the caller's copy, not the log's own
+
+	if err := log.Verify(); err != nil {
+		t.Fatalf("Verify should be unaffected by mutating a returned copy: %v", err)
+	}
+
+	log.entries[0].UserID = "user-2" // now tamper with the log's own entry
+	if err := log.Verify(); err == nil {
+		t.Fatal("Verify should fail after the log's own entry was tampered with")
+	}
+}
+
+func TestAuditLog_HMACSignerSignsAndVerifies(t *testing.T) {
+	signer := HMACKeySigner{Key: []byte("super-secret-key")}
+	log := NewAuditLog(signer)
+
+	if _, err := log.Append(AuditOpForget, "user-1", "", "mem-1", ""); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	entries, err := log.Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	if entries[0].Signature == "" {
+		t.Fatal("expected a non-empty Signature when a signer is configured")
+	}
+	if err := log.Verify(); err != nil {
+		t.Fatalf("Verify with a valid HMAC signature: %v", err)
+	}
+}
+
+func TestAuditLog_HMACSignerRejectsWrongKey(t *testing.T) {
+	log := NewAuditLog(HMACKeySigner{Key: []byte("key-a")})
+	if _, err := log.Append(AuditOpStore, "user-1", "", "mem-1", ""); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	log.signer = HMACKeySigner{Key: []byte("key-b")}
+	if err := log.Verify(); err == nil {
+		t.Fatal("Verify should fail once the verifying key no longer matches the signing key")
+	}
+}
+
+func TestAuditLog_Ed25519SignerVerifiesWithPublicKeyOnly(t *testing.T) {
+	pub, priv, err := generateEd25519KeyForTest()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	log := NewAuditLog(Ed25519Signer{PrivateKey: priv})
+	if _, err := log.Append(AuditOpStore, "user-1", "", "mem-1", ""); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	// Ed25519Signer doesn't implement AuditVerifier, so Verify should pass
+	// without attempting signature verification.
+	if err := log.Verify(); err != nil {
+		t.Fatalf("Verify with an Ed25519Signer (no verifier configured): %v", err)
+	}
+
+	log.verifier = Ed25519Verifier{PublicKey: pub}
+	if err := log.Verify(); err != nil {
+		t.Fatalf("Verify with the matching Ed25519Verifier: %v", err)
+	}
+}
+
+func TestNewAuditLogVerifier_VerifiesEntriesWithOnlyThePublicKey(t *testing.T) {
+	pub, priv, err := generateEd25519KeyForTest()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	writer := NewAuditLog(Ed25519Signer{PrivateKey: priv})
+	if _, err := writer.Append(AuditOpStore, "user-1", "", "mem-1", ""); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if _, err := writer.Append(AuditOpRetrieve, "user-1", "", "mem-1", ""); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	entries, err := writer.Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+
+	// A separate process holding only the public key, not priv, builds its
+	// own AuditLog purely to verify entries it read from elsewhere.
+	verifierLog, err := NewAuditLogVerifier(Ed25519Verifier{PublicKey: pub}, entries)
+	if err != nil {
+		t.Fatalf("NewAuditLogVerifier: %v", err)
+	}
+
+	if _, err := verifierLog.Entries(); err != nil {
+		t.Fatalf("Entries on a verify-only log: %v", err)
+	}
+
+	if _, err := verifierLog.Append(AuditOpStore, "user-1", "", "mem-2", ""); err == nil {
+		t.Fatal("expected Append on a verify-only AuditLog to be refused")
+	}
+}
+
+func TestNewAuditLogVerifier_DetectsTamperedEntries(t *testing.T) {
+	pub, priv, err := generateEd25519KeyForTest()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	writer := NewAuditLog(Ed25519Signer{PrivateKey: priv})
+	if _, err := writer.Append(AuditOpStore, "user-1", "", "mem-1", ""); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	entries, err := writer.Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	entries[0].UserID = "someone-else"
+
+	if _, err := NewAuditLogVerifier(Ed25519Verifier{PublicKey: pub}, entries); err == nil {
+		t.Fatal("expected NewAuditLogVerifier to reject a tampered entry")
+	}
+}
+
+func TestCanonicalize_OmitsHashAndSignature(t *testing.T) {
+	entry := &AuditEntry{Sequence: 1, UserID: "user-1", Hash: "should-not-appear", Signature: "should-not-appear"}
+	data, err := canonicalize(entry)
+	if err != nil {
+		t.Fatalf("canonicalize: %v", err)
+	}
+	if strings.Contains(string(data), "should-not-appear") {
+		t.Fatalf("canonical form must not include Hash or Signature, got %s", data)
+	}
+}
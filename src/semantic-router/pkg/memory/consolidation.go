@@ -0,0 +1,376 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// =============================================================================
+// Consolidation Configuration
+// =============================================================================
+
+// ConsolidationConfig configures MemoryConsolidator's decay, reinforcement,
+// and eviction passes.
+type ConsolidationConfig struct {
+	// Lambda is the exponential-decay rate, in score = base * exp(-Lambda *
+	// ageSeconds), where ageSeconds is the time since a memory's last
+	// access. Larger values forget faster. 0 disables decay entirely
+	// (score stays at base importance).
+	Lambda float64
+
+	// MergeThreshold is the minimum similarity (cosine over embeddings when
+	// both are present, keyword overlap otherwise - see similarityForMerge)
+	// above which Reinforce treats a newly extracted fact as a restatement
+	// of an existing memory rather than a new one.
+	MergeThreshold float64
+
+	// MinScore is the decayed-score floor: a memory scoring below this in
+	// RunOnce is evicted.
+	MinScore float64
+
+	// EpisodicTTL evicts MemoryTypeEpisodic memories older than this,
+	// regardless of their decayed score. 0 disables the TTL check.
+	EpisodicTTL time.Duration
+
+	// PromotionThreshold promotes a MemoryTypeWorking memory to
+	// MemoryTypeEpisodic once its AccessCount reaches this value: a
+	// scratchpad entry that keeps getting retrieved is behaving like a real
+	// recollection, not transient session context, and should survive past
+	// its working-memory TTL. 0 disables promotion.
+	PromotionThreshold int
+
+	// Interval is how often Start runs RunOnce in the background. 0 means
+	// Start is a no-op; callers drive RunOnce themselves (e.g. from a cron
+	// job) instead.
+	Interval time.Duration
+}
+
+// DefaultConsolidationConfig returns a config tuned for a 30-day rolling
+// memory: scores halve roughly every two weeks, and any memory whose
+// decayed score falls below 0.05 or any episodic memory older than a
+// month is evicted on an hourly pass. Working memories retrieved 5+ times
+// are promoted to episodic rather than expiring with the rest of the
+// session scratchpad.
+func DefaultConsolidationConfig() ConsolidationConfig {
+	const twoWeeks = 14 * 24 * time.Hour
+	return ConsolidationConfig{
+		Lambda:             math.Ln2 / twoWeeks.Seconds(),
+		MergeThreshold:     0.9,
+		MinScore:           0.05,
+		EpisodicTTL:        30 * 24 * time.Hour,
+		PromotionThreshold: 5,
+		Interval:           time.Hour,
+	}
+}
+
+// accessBoostFactor scales decayScore's access-count boost: a memory
+// retrieved often is more likely to still matter than its raw age-based
+// decay suggests, so each access nudges the score back up. log1p keeps
+// the boost sublinear so a handful of accesses matters more than the
+// thousandth one.
+const accessBoostFactor = 0.1
+
+// Summarizer condenses variants - the existing memory's content followed by
+// a newly reinforced restatement - into a single piece of content. It is
+// the hook for an LLM "summarize these N variants" call; Reinforce works
+// fine without one and simply keeps the existing content, incrementing
+// HitCount and bumping AccessedAt.
+type Summarizer func(ctx context.Context, variants []string) (string, error)
+
+// joinSummarizer is the Summarizer InMemoryStore.Consolidate falls back to
+// when ConsolidateOptions.Summarizer is nil: no LLM call, just join the
+// cluster's variants so no content is silently dropped.
+func joinSummarizer(_ context.Context, variants []string) (string, error) {
+	return strings.Join(variants, "; "), nil
+}
+
+// =============================================================================
+// MemoryConsolidator
+// =============================================================================
+
+// MemoryConsolidator turns a Store from an append-only log into something
+// closer to human memory: RunOnce decays every memory's Importance by age
+// since last access and evicts what falls below MinScore (or, for episodic
+// memories, past EpisodicTTL); Reinforce folds a freshly extracted fact into
+// an existing near-duplicate memory instead of letting ExtractFacts append
+// an unbounded number of restatements of the same fact.
+//
+// RunOnce requires its Store to implement StoreIterator (InMemoryStore,
+// SQLiteStore, PostgresStore all do; MilvusStore does not - full-collection
+// scans aren't a cheap operation there, so eviction should instead be driven
+// by MilvusStore's own TTL/consistency machinery).
+type MemoryConsolidator struct {
+	store      Store
+	config     ConsolidationConfig
+	summarizer Summarizer
+	embedder   Embedder
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewMemoryConsolidator creates a MemoryConsolidator over store using cfg.
+func NewMemoryConsolidator(store Store, cfg ConsolidationConfig) *MemoryConsolidator {
+	return &MemoryConsolidator{store: store, config: cfg}
+}
+
+// SetSummarizer installs (or, passed nil, clears) the Summarizer Reinforce
+// calls when it merges a fact into an existing memory.
+func (c *MemoryConsolidator) SetSummarizer(fn Summarizer) {
+	c.summarizer = fn
+}
+
+// SetEmbedder installs (or, passed nil, clears) the Embedder RunOnce uses to
+// drive the store's StoreConsolidator.Consolidate pass. Without one, RunOnce
+// still decays, evicts, and promotes, but skips semantic near-duplicate
+// clustering entirely - Consolidate requires an Embedder.
+func (c *MemoryConsolidator) SetEmbedder(fn Embedder) {
+	c.embedder = fn
+}
+
+// ConsolidationStats summarizes one RunOnce pass.
+type ConsolidationStats struct {
+	Scored   int // memories whose decayed score was recomputed and persisted
+	Evicted  int // memories removed for falling below MinScore or EpisodicTTL
+	Promoted int // MemoryTypeWorking memories promoted to MemoryTypeEpisodic
+	Merged   int // clusters of near-duplicate semantic memories folded into one
+}
+
+// RunOnce scans every memory in the store, rescoring it with decayScore and
+// evicting anything below MinScore, past EpisodicTTL, or past its own TTL.
+// Along the way it promotes frequently-accessed MemoryTypeWorking memories
+// to MemoryTypeEpisodic, then - if an Embedder is installed (see
+// SetEmbedder) and the store implements StoreConsolidator - clusters each
+// scanned user's near-duplicate MemoryTypeSemantic memories into one. All of
+// this is applied after the scan, not while iterating, since Iterate doesn't
+// guarantee it's safe to mutate the store from inside fn.
+func (c *MemoryConsolidator) RunOnce(ctx context.Context) (ConsolidationStats, error) {
+	iterable, ok := c.store.(StoreIterator)
+	if !ok {
+		return ConsolidationStats{}, fmt.Errorf("memory: consolidation requires a Store implementing StoreIterator, got %T", c.store)
+	}
+
+	now := time.Now()
+	var stats ConsolidationStats
+	var evictIDs []string
+	var promote []*Memory
+	rescore := make(map[string]float64)
+	semanticUsers := make(map[string]struct{})
+
+	err := iterable.Iterate(ctx, func(mem *Memory) error {
+		if mem.Type == MemoryTypeSemantic {
+			semanticUsers[mem.UserID] = struct{}{}
+		}
+
+		if c.shouldEvict(mem, now) {
+			evictIDs = append(evictIDs, mem.ID)
+			return nil
+		}
+
+		if c.config.PromotionThreshold > 0 && mem.Type == MemoryTypeWorking && mem.AccessCount >= c.config.PromotionThreshold {
+			promoted := *mem
+			promoted.Type = MemoryTypeEpisodic
+			promote = append(promote, &promoted)
+			return nil
+		}
+
+		rescore[mem.ID] = decayScore(mem, c.config.Lambda, now)
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	for id, score := range rescore {
+		if err := c.store.UpdateScore(ctx, id, score); err != nil && err != ErrNotFound {
+			return stats, fmt.Errorf("consolidation: update score for %s: %w", id, err)
+		}
+		stats.Scored++
+	}
+
+	if len(evictIDs) > 0 {
+		if err := c.store.BulkDelete(ctx, evictIDs); err != nil {
+			return stats, fmt.Errorf("consolidation: evict: %w", err)
+		}
+		stats.Evicted = len(evictIDs)
+	}
+
+	for _, mem := range promote {
+		if err := c.store.Update(ctx, mem.ID, mem); err != nil && err != ErrNotFound {
+			return stats, fmt.Errorf("consolidation: promote %s: %w", mem.ID, err)
+		}
+		stats.Promoted++
+	}
+
+	if consolidator, ok := c.store.(StoreConsolidator); ok && c.embedder != nil && c.config.MergeThreshold > 0 {
+		for userID := range semanticUsers {
+			result, err := consolidator.Consolidate(ctx, ConsolidateOptions{
+				UserID:              userID,
+				SimilarityThreshold: c.config.MergeThreshold,
+				Embedder:            c.embedder,
+				Summarizer:          c.summarizer,
+			})
+			if err != nil {
+				return stats, fmt.Errorf("consolidation: consolidate user %s: %w", userID, err)
+			}
+			stats.Merged += result.ClustersMerged
+		}
+	}
+
+	return stats, nil
+}
+
+// shouldEvict reports whether mem should be evicted outright rather than
+// rescored: its decayed score has fallen below MinScore, it's episodic and
+// older than EpisodicTTL, or its own TTL (the generic field ExpiryReaper
+// also honors) has passed.
+func (c *MemoryConsolidator) shouldEvict(mem *Memory, now time.Time) bool {
+	if isExpired(mem, now) {
+		return true
+	}
+	if c.config.EpisodicTTL > 0 && mem.Type == MemoryTypeEpisodic && now.Sub(mem.CreatedAt) > c.config.EpisodicTTL {
+		return true
+	}
+	if c.config.MinScore > 0 && decayScore(mem, c.config.Lambda, now) < c.config.MinScore {
+		return true
+	}
+	return false
+}
+
+// decayScore applies exponential time-decay to mem's Importance, scoring it
+// against the time since its last access: score = base * exp(-lambda *
+// ageSeconds). Memories that have never been scored (Importance == 0) use
+// 0.5 as the base, matching the default createNewMemory assigns new facts.
+// The result is then boosted by AccessCount (see accessBoostFactor), so a
+// memory retrieved often decays more slowly than one nobody has looked at
+// since it was written.
+func decayScore(mem *Memory, lambda float64, now time.Time) float64 {
+	base := mem.Importance
+	if base <= 0 {
+		base = 0.5
+	}
+	if lambda > 0 {
+		age := now.Sub(mem.AccessedAt).Seconds()
+		if age < 0 {
+			age = 0
+		}
+		base *= math.Exp(-lambda * age)
+	}
+
+	if mem.AccessCount > 0 {
+		base *= 1 + accessBoostFactor*math.Log1p(float64(mem.AccessCount))
+	}
+	return base
+}
+
+// Reinforce looks for an existing memory of userID similar enough to fact
+// (by MergeThreshold) to be the same underlying information restated, and
+// merges fact into it instead of letting the caller create a new memory:
+// the match's HitCount is incremented, AccessedAt is bumped to now, and - if
+// a Summarizer is installed - its Content is rewritten to a summary of the
+// old and new variants. It returns the merged memory and true if a merge
+// happened, or (nil, false) if the caller should create a new memory as
+// usual.
+func (c *MemoryConsolidator) Reinforce(ctx context.Context, userID string, fact ExtractedFact, embedding []float32) (*Memory, bool, error) {
+	candidates, err := c.store.Retrieve(ctx, RetrieveOptions{
+		UserID: userID,
+		Query:  fact.Content,
+		Types:  []MemoryType{fact.Type},
+		Limit:  10,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("consolidation: reinforce lookup: %w", err)
+	}
+
+	var best *Memory
+	bestSim := 0.0
+	for _, cand := range candidates {
+		sim := similarityForMerge(embedding, cand.Memory.Embedding, fact.Content, cand.Memory.Content)
+		if sim > bestSim {
+			bestSim = sim
+			best = cand.Memory
+		}
+	}
+
+	if best == nil || bestSim < c.config.MergeThreshold {
+		return nil, false, nil
+	}
+
+	best.HitCount++
+	best.AccessedAt = time.Now()
+
+	if c.summarizer != nil {
+		summary, err := c.summarizer(ctx, []string{best.Content, fact.Content})
+		if err != nil {
+			logging.Warnf("memory consolidation: summarizer failed, keeping existing content for %s: %v", best.ID, err)
+		} else if summary != "" {
+			best.Content = summary
+		}
+	}
+
+	if err := c.store.Update(ctx, best.ID, best); err != nil {
+		return nil, false, fmt.Errorf("consolidation: reinforce update for %s: %w", best.ID, err)
+	}
+	return best, true, nil
+}
+
+// similarityForMerge scores how likely contentA/contentB (with embeddings
+// embA/embB) are the same underlying fact: cosine similarity when both
+// embeddings are populated, falling back to keywordRelevanceScore - the
+// same stand-in SQLiteStore/PostgresStore use - when either is empty, e.g.
+// because the caller hasn't wired up embedding generation for extracted
+// facts yet.
+func similarityForMerge(embA, embB []float32, contentA, contentB string) float64 {
+	if len(embA) > 0 && len(embB) > 0 {
+		return cosineSimilarity(embA, embB)
+	}
+	return keywordRelevanceScore(contentA, contentB)
+}
+
+// Start runs RunOnce on a ticker every config.Interval until Stop is
+// called. A no-op if Interval <= 0. Errors from RunOnce are logged, not
+// returned - consolidation is best-effort background maintenance, not
+// something a caller blocks on.
+func (c *MemoryConsolidator) Start(ctx context.Context) {
+	if c.config.Interval <= 0 {
+		return
+	}
+
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(c.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if stats, err := c.RunOnce(ctx); err != nil {
+					logging.Warnf("memory consolidation pass failed: %v", err)
+				} else {
+					logging.Debugf("memory consolidation pass: scored=%d evicted=%d", stats.Scored, stats.Evicted)
+				}
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the background loop started by Start and waits for the
+// in-flight pass, if any, to finish. A no-op if Start was never called or
+// config.Interval <= 0.
+func (c *MemoryConsolidator) Stop() {
+	if c.stop == nil {
+		return
+	}
+	close(c.stop)
+	<-c.done
+}
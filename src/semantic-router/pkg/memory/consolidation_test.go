@@ -0,0 +1,257 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDecayScore_NoLambdaKeepsBaseImportance(t *testing.T) {
+	mem := &Memory{Importance: 0.8, AccessedAt: time.Now().Add(-365 * 24 * time.Hour)}
+	if score := decayScore(mem, 0, time.Now()); score != 0.8 {
+		t.Fatalf("decayScore with lambda=0 = %v, want 0.8 (unchanged)", score)
+	}
+}
+
+func TestDecayScore_DefaultsUnscoredMemoryToHalf(t *testing.T) {
+	mem := &Memory{AccessedAt: time.Now()}
+	if score := decayScore(mem, 0.01, time.Now()); score != 0.5 {
+		t.Fatalf("decayScore for never-scored memory at age 0 = %v, want 0.5", score)
+	}
+}
+
+func TestDecayScore_DecaysWithAgeSinceLastAccess(t *testing.T) {
+	now := time.Now()
+	fresh := &Memory{Importance: 1.0, AccessedAt: now}
+	stale := &Memory{Importance: 1.0, AccessedAt: now.Add(-24 * time.Hour)}
+
+	lambda := 1.0 / (24 * time.Hour).Seconds() // score falls to 1/e (about 0.37) per day at this rate
+	freshScore := decayScore(fresh, lambda, now)
+	staleScore := decayScore(stale, lambda, now)
+
+	if freshScore != 1.0 {
+		t.Fatalf("decayScore at age 0 = %v, want 1.0", freshScore)
+	}
+	if staleScore >= freshScore {
+		t.Fatalf("decayScore for a day-old memory (%v) should be lower than a just-accessed one (%v)", staleScore, freshScore)
+	}
+}
+
+func TestMemoryConsolidator_RunOnce_EvictsBelowMinScore(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	require := func(err error) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	kept := &Memory{Type: MemoryTypeSemantic, Content: "kept", UserID: "user_1", Importance: 1.0, AccessedAt: time.Now()}
+	evicted := &Memory{Type: MemoryTypeSemantic, Content: "evicted", UserID: "user_1", Importance: 0.01, AccessedAt: time.Now().Add(-1000 * time.Hour)}
+	require(store.Store(ctx, kept))
+	require(store.Store(ctx, evicted))
+
+	consolidator := NewMemoryConsolidator(store, ConsolidationConfig{Lambda: 0, MinScore: 0.1})
+	stats, err := consolidator.RunOnce(ctx)
+	require(err)
+
+	if stats.Evicted != 1 {
+		t.Fatalf("stats.Evicted = %d, want 1", stats.Evicted)
+	}
+	if _, err := store.Get(ctx, evicted.ID); err != ErrNotFound {
+		t.Fatalf("Get(evicted) = %v, want ErrNotFound", err)
+	}
+	if _, err := store.Get(ctx, kept.ID); err != nil {
+		t.Fatalf("Get(kept) = %v, want nil", err)
+	}
+}
+
+func TestMemoryConsolidator_RunOnce_EvictsExpiredEpisodic(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	old := &Memory{Type: MemoryTypeEpisodic, Content: "old event", UserID: "user_1", Importance: 1.0, CreatedAt: time.Now().Add(-100 * 24 * time.Hour), AccessedAt: time.Now()}
+	if err := store.Store(ctx, old); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	consolidator := NewMemoryConsolidator(store, ConsolidationConfig{Lambda: 0, MinScore: 0, EpisodicTTL: 30 * 24 * time.Hour})
+	stats, err := consolidator.RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if stats.Evicted != 1 {
+		t.Fatalf("stats.Evicted = %d, want 1 (expired episodic memory)", stats.Evicted)
+	}
+}
+
+func TestDecayScore_AccessCountBoostsScore(t *testing.T) {
+	now := time.Now()
+	unaccessed := &Memory{Importance: 1.0, AccessedAt: now.Add(-24 * time.Hour), AccessCount: 0}
+	accessed := &Memory{Importance: 1.0, AccessedAt: now.Add(-24 * time.Hour), AccessCount: 10}
+
+	lambda := 1.0 / (24 * time.Hour).Seconds()
+	if decayScore(accessed, lambda, now) <= decayScore(unaccessed, lambda, now) {
+		t.Fatal("decayScore for a frequently accessed memory should be boosted above an equally-aged, never-accessed one")
+	}
+}
+
+func TestMemoryConsolidator_RunOnce_EvictsPastTTL(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	expired := &Memory{Type: MemoryTypeWorking, Content: "scratch", UserID: "user_1", AccessedAt: time.Now(), TTL: time.Now().Add(-time.Minute)}
+	if err := store.Store(ctx, expired); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	consolidator := NewMemoryConsolidator(store, ConsolidationConfig{})
+	stats, err := consolidator.RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if stats.Evicted != 1 {
+		t.Fatalf("stats.Evicted = %d, want 1 (past its own TTL)", stats.Evicted)
+	}
+}
+
+func TestMemoryConsolidator_RunOnce_PromotesFrequentlyAccessedWorkingMemory(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	mem := &Memory{Type: MemoryTypeWorking, Content: "recurring context", UserID: "user_1", AccessedAt: time.Now(), AccessCount: 10}
+	if err := store.Store(ctx, mem); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	consolidator := NewMemoryConsolidator(store, ConsolidationConfig{PromotionThreshold: 5})
+	stats, err := consolidator.RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if stats.Promoted != 1 {
+		t.Fatalf("stats.Promoted = %d, want 1", stats.Promoted)
+	}
+
+	got, err := store.Get(ctx, mem.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Type != MemoryTypeEpisodic {
+		t.Fatalf("Type = %q, want %q after promotion", got.Type, MemoryTypeEpisodic)
+	}
+}
+
+func TestMemoryConsolidator_RunOnce_RequiresStoreIterator(t *testing.T) {
+	consolidator := NewMemoryConsolidator(nonIteratingStore{}, DefaultConsolidationConfig())
+	if _, err := consolidator.RunOnce(context.Background()); err == nil {
+		t.Fatal("RunOnce with a non-StoreIterator Store should error")
+	}
+}
+
+func TestMemoryConsolidator_Reinforce_MergesSimilarFact(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	existing := &Memory{Type: MemoryTypeSemantic, Content: "User prefers vegetarian food", UserID: "user_1"}
+	if err := store.Store(ctx, existing); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	consolidator := NewMemoryConsolidator(store, ConsolidationConfig{MergeThreshold: 0.5})
+	fact := ExtractedFact{Type: MemoryTypeSemantic, Content: "User prefers vegetarian food"}
+
+	merged, ok, err := consolidator.Reinforce(ctx, "user_1", fact, nil)
+	if err != nil {
+		t.Fatalf("Reinforce: %v", err)
+	}
+	if !ok {
+		t.Fatal("Reinforce should have merged into the existing memory")
+	}
+	if merged.ID != existing.ID {
+		t.Fatalf("Reinforce merged into %s, want %s", merged.ID, existing.ID)
+	}
+	if merged.HitCount != 1 {
+		t.Fatalf("merged.HitCount = %d, want 1", merged.HitCount)
+	}
+
+	got, err := store.Get(ctx, existing.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.HitCount != 1 {
+		t.Fatalf("persisted HitCount = %d, want 1", got.HitCount)
+	}
+}
+
+func TestMemoryConsolidator_Reinforce_NoMatchReturnsFalse(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	if err := store.Store(ctx, &Memory{Type: MemoryTypeSemantic, Content: "User prefers vegetarian food", UserID: "user_1"}); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	consolidator := NewMemoryConsolidator(store, ConsolidationConfig{MergeThreshold: 0.9})
+	fact := ExtractedFact{Type: MemoryTypeSemantic, Content: "User's favorite programming language is Go"}
+
+	merged, ok, err := consolidator.Reinforce(ctx, "user_1", fact, nil)
+	if err != nil {
+		t.Fatalf("Reinforce: %v", err)
+	}
+	if ok || merged != nil {
+		t.Fatalf("Reinforce should not have matched an unrelated fact, got merged=%v ok=%v", merged, ok)
+	}
+}
+
+func TestMemoryConsolidator_Reinforce_SummarizerRewritesContent(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	existing := &Memory{Type: MemoryTypeSemantic, Content: "User prefers vegetarian food", UserID: "user_1"}
+	if err := store.Store(ctx, existing); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	consolidator := NewMemoryConsolidator(store, ConsolidationConfig{MergeThreshold: 0.5})
+	consolidator.SetSummarizer(func(ctx context.Context, variants []string) (string, error) {
+		return "User strongly prefers vegetarian food", nil
+	})
+
+	fact := ExtractedFact{Type: MemoryTypeSemantic, Content: "User prefers vegetarian food"}
+	_, ok, err := consolidator.Reinforce(ctx, "user_1", fact, nil)
+	if err != nil {
+		t.Fatalf("Reinforce: %v", err)
+	}
+	if !ok {
+		t.Fatal("Reinforce should have merged")
+	}
+
+	got, err := store.Get(ctx, existing.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Content != "User strongly prefers vegetarian food" {
+		t.Fatalf("Content = %q, want the summarizer's rewrite", got.Content)
+	}
+}
+
+// nonIteratingStore is a minimal Store that deliberately doesn't implement
+// StoreIterator, for TestMemoryConsolidator_RunOnce_RequiresStoreIterator.
+type nonIteratingStore struct{}
+
+func (nonIteratingStore) Store(ctx context.Context, memory *Memory) error { return nil }
+func (nonIteratingStore) Retrieve(ctx context.Context, opts RetrieveOptions) ([]*RetrieveResult, error) {
+	return nil, nil
+}
+func (nonIteratingStore) Get(ctx context.Context, id string) (*Memory, error) {
+	return nil, ErrNotFound
+}
+func (nonIteratingStore) Update(ctx context.Context, id string, memory *Memory) error     { return nil }
+func (nonIteratingStore) Forget(ctx context.Context, id string) error                     { return nil }
+func (nonIteratingStore) ForgetByScope(ctx context.Context, scope MemoryScope) error      { return nil }
+func (nonIteratingStore) UpdateScore(ctx context.Context, id string, score float64) error { return nil }
+func (nonIteratingStore) BulkDelete(ctx context.Context, ids []string) error              { return nil }
+func (nonIteratingStore) IsEnabled() bool                                                 { return true }
+func (nonIteratingStore) Close() error                                                    { return nil }
@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/responseapi"
+)
+
+// ErrConversationNotFound is returned by ConversationStore methods when the
+// requested conversation or response ID has no stored history.
+var ErrConversationNotFound = errors.New("conversation not found")
+
+// ConversationStore persists the per-session chain of StoredResponses behind
+// the Response API, so callers like extractMemoryInfo can fetch a
+// conversation's history on demand instead of requiring the whole chain to
+// be preloaded onto the request context by the translator.
+//
+// Implementations:
+//   - LRUConversationStore: in-process, capped by conversation count (conversation_store_lru.go)
+//   - RedisConversationStore: shared, password/TLS/cluster-aware (conversation_store_redis.go)
+type ConversationStore interface {
+	// Get returns the stored responses for a conversation, oldest first.
+	// Returns ErrConversationNotFound if the conversation is unknown.
+	Get(ctx context.Context, conversationID string) ([]*responseapi.StoredResponse, error)
+
+	// Append adds a new turn to a conversation's history.
+	Append(ctx context.Context, conversationID string, resp *responseapi.StoredResponse) error
+
+	// LookupByPreviousResponseID finds the stored response a given
+	// PreviousResponseID points to, used to resolve a conversation's chain
+	// when the ConversationID itself isn't known yet.
+	LookupByPreviousResponseID(ctx context.Context, previousResponseID string) (*responseapi.StoredResponse, error)
+
+	// Close releases any underlying resources (connections, etc.).
+	Close() error
+}
+
+// ConversationStoreBackend selects the ConversationStore implementation.
+type ConversationStoreBackend string
+
+const (
+	// ConversationStoreBackendLRU keeps history in an in-process, capped
+	// LRU cache. This is the original behavior and needs no external
+	// dependency, but history is lost on restart and isn't shared across
+	// router replicas.
+	ConversationStoreBackendLRU ConversationStoreBackend = "lru"
+
+	// ConversationStoreBackendRedis persists history in Redis, shared
+	// across replicas and durable across restarts.
+	ConversationStoreBackendRedis ConversationStoreBackend = "redis"
+)
+
+// ConversationStoreConfig configures a ConversationStore built by
+// NewConversationStore.
+type ConversationStoreConfig struct {
+	// Backend selects the implementation. Defaults to ConversationStoreBackendLRU.
+	Backend ConversationStoreBackend
+
+	// LRU configures the in-process backend. Used when Backend == lru.
+	LRU LRUConversationStoreConfig
+
+	// Redis configures the Redis backend. Used when Backend == redis.
+	Redis RedisConversationStoreConfig
+
+	// Encryption wraps every value written through the store with
+	// envelope encryption. Nil disables encryption (plaintext), which is
+	// only appropriate for local development with the LRU backend.
+	Encryption *EnvelopeEncryptionConfig
+}
+
+// DefaultConversationStoreConfig returns the original in-process, unencrypted
+// behavior.
+func DefaultConversationStoreConfig() *ConversationStoreConfig {
+	return &ConversationStoreConfig{
+		Backend: ConversationStoreBackendLRU,
+		LRU:     DefaultLRUConversationStoreConfig(),
+	}
+}
+
+// NewConversationStore builds a ConversationStore from config, wrapping it
+// with envelope encryption when config.Encryption is set.
+func NewConversationStore(config *ConversationStoreConfig) (ConversationStore, error) {
+	if config == nil {
+		config = DefaultConversationStoreConfig()
+	}
+
+	var (
+		store ConversationStore
+		err   error
+	)
+	switch config.Backend {
+	case ConversationStoreBackendRedis:
+		logging.Infof("ConversationStore: using Redis backend (addr=%s cluster=%v)", config.Redis.Addr, config.Redis.Cluster)
+		store, err = NewRedisConversationStore(config.Redis)
+	case ConversationStoreBackendLRU, "":
+		logging.Infof("ConversationStore: using in-process LRU backend")
+		store = NewLRUConversationStore(config.LRU)
+	default:
+		return nil, fmt.Errorf("unknown conversation store backend: %s", config.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Encryption != nil {
+		encryptor, err := NewEnvelopeEncryptor(config.Encryption)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build envelope encryptor: %w", err)
+		}
+		logging.Infof("ConversationStore: envelope encryption enabled (kek_source=%s)", config.Encryption.KEKSource)
+		return NewEncryptedConversationStore(store, encryptor), nil
+	}
+	return store, nil
+}
+
+// NewConversationStoreFromPluginConfig builds a ConversationStore from a
+// decision plugin's raw Configuration map (config.DecisionPlugin.Configuration
+// where Type == "memory"), e.g.:
+//
+//	type: memory
+//	configuration:
+//	  backend: redis
+//	  addr: redis.internal:6379
+//	  password: ${REDIS_PASSWORD}
+//	  tls: true
+//	  cluster: true
+func NewConversationStoreFromPluginConfig(pluginConfig map[string]interface{}) (ConversationStore, error) {
+	return NewConversationStore(conversationStoreConfigFromMap(pluginConfig))
+}
+
+// conversationStoreConfigFromMap reads backend-selection keys out of a
+// decision plugin's Configuration map. Unrecognized/missing keys fall back
+// to DefaultConversationStoreConfig's values.
+func conversationStoreConfigFromMap(m map[string]interface{}) *ConversationStoreConfig {
+	cfg := DefaultConversationStoreConfig()
+	if m == nil {
+		return cfg
+	}
+
+	if backend, ok := m["backend"].(string); ok && backend != "" {
+		cfg.Backend = ConversationStoreBackend(backend)
+	}
+	if addr, ok := m["addr"].(string); ok {
+		cfg.Redis.Addr = addr
+	}
+	if password, ok := m["password"].(string); ok {
+		cfg.Redis.Password = password
+	}
+	if tlsEnabled, ok := m["tls"].(bool); ok {
+		cfg.Redis.TLS = tlsEnabled
+	}
+	if cluster, ok := m["cluster"].(bool); ok {
+		cfg.Redis.Cluster = cluster
+	}
+	if maxConversations, ok := m["max_conversations"].(int); ok && maxConversations > 0 {
+		cfg.LRU.MaxConversations = maxConversations
+	}
+
+	if kekSource, ok := m["encryption_kek_source"].(string); ok && kekSource != "" {
+		encryption := &EnvelopeEncryptionConfig{KEKSource: KEKSourceType(kekSource)}
+		if kekPath, ok := m["encryption_kek_path"].(string); ok {
+			encryption.KEKPath = kekPath
+		}
+		cfg.Encryption = encryption
+	}
+
+	return cfg
+}
@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/responseapi"
+)
+
+// encryptedContentPartType marks an OutputItem ContentPart whose Text holds
+// a base64-encoded sealed envelope in place of the original part array.
+const encryptedContentPartType = "encrypted"
+
+// encryptedConversationStore wraps a ConversationStore, transparently
+// envelope-encrypting the sensitive fields of every StoredResponse
+// (OutputText, each input item's Content, and each output item's Content -
+// which covers tool-call arguments and results, not just message text)
+// before handing it to the underlying backend, and decrypting on the way
+// out. The underlying backend only ever sees ciphertext; IDs and
+// ConversationID stay in the clear so chain lookups keep working.
+type encryptedConversationStore struct {
+	inner     ConversationStore
+	encryptor EnvelopeEncryptor
+}
+
+// NewEncryptedConversationStore wraps inner so every value written through
+// it is envelope-encrypted with encryptor, scoped per conversation ID (used
+// as the tenant key for Seal/Open).
+func NewEncryptedConversationStore(inner ConversationStore, encryptor EnvelopeEncryptor) ConversationStore {
+	return &encryptedConversationStore{inner: inner, encryptor: encryptor}
+}
+
+// Get implements ConversationStore.
+func (s *encryptedConversationStore) Get(ctx context.Context, conversationID string) ([]*responseapi.StoredResponse, error) {
+	sealed, err := s.inner.Get(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*responseapi.StoredResponse, len(sealed))
+	for i, resp := range sealed {
+		opened, err := s.openResponse(conversationID, resp)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = opened
+	}
+	return out, nil
+}
+
+// Append implements ConversationStore.
+func (s *encryptedConversationStore) Append(ctx context.Context, conversationID string, resp *responseapi.StoredResponse) error {
+	sealed, err := s.sealResponse(conversationID, resp)
+	if err != nil {
+		return err
+	}
+	return s.inner.Append(ctx, conversationID, sealed)
+}
+
+// LookupByPreviousResponseID implements ConversationStore.
+func (s *encryptedConversationStore) LookupByPreviousResponseID(ctx context.Context, previousResponseID string) (*responseapi.StoredResponse, error) {
+	sealed, err := s.inner.LookupByPreviousResponseID(ctx, previousResponseID)
+	if err != nil {
+		return nil, err
+	}
+	// ConversationID was used as the tenant scope in sealResponse, and is
+	// carried on the response itself (it's never one of the sealed fields).
+	return s.openResponse(sealed.ConversationID, sealed)
+}
+
+// Close implements ConversationStore.
+func (s *encryptedConversationStore) Close() error {
+	return s.inner.Close()
+}
+
+func (s *encryptedConversationStore) sealResponse(tenantID string, resp *responseapi.StoredResponse) (*responseapi.StoredResponse, error) {
+	sealed := *resp
+
+	if resp.OutputText != "" {
+		ciphertext, err := s.encryptor.Seal(tenantID, []byte(resp.OutputText))
+		if err != nil {
+			return nil, fmt.Errorf("failed to seal OutputText: %w", err)
+		}
+		sealed.OutputText = base64.StdEncoding.EncodeToString(ciphertext)
+	}
+
+	sealed.Input = make([]responseapi.InputItem, len(resp.Input))
+	for i, item := range resp.Input {
+		if len(item.Content) > 0 {
+			ciphertext, err := s.encryptor.Seal(tenantID, item.Content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to seal input content: %w", err)
+			}
+			encoded, err := json.Marshal(base64.StdEncoding.EncodeToString(ciphertext))
+			if err != nil {
+				return nil, err
+			}
+			item.Content = encoded
+		}
+		sealed.Input[i] = item
+	}
+
+	sealed.Output = make([]responseapi.OutputItem, len(resp.Output))
+	for i, item := range resp.Output {
+		if len(item.Content) > 0 {
+			plaintext, err := json.Marshal(item.Content)
+			if err != nil {
+				return nil, err
+			}
+			ciphertext, err := s.encryptor.Seal(tenantID, plaintext)
+			if err != nil {
+				return nil, fmt.Errorf("failed to seal output content: %w", err)
+			}
+			item.Content = []responseapi.ContentPart{{
+				Type: encryptedContentPartType,
+				Text: base64.StdEncoding.EncodeToString(ciphertext),
+			}}
+		}
+		sealed.Output[i] = item
+	}
+
+	return &sealed, nil
+}
+
+func (s *encryptedConversationStore) openResponse(tenantID string, resp *responseapi.StoredResponse) (*responseapi.StoredResponse, error) {
+	opened := *resp
+
+	if resp.OutputText != "" {
+		ciphertext, err := base64.StdEncoding.DecodeString(resp.OutputText)
+		if err != nil {
+			return nil, fmt.Errorf("malformed sealed OutputText: %w", err)
+		}
+		plaintext, err := s.encryptor.Open(tenantID, ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open OutputText: %w", err)
+		}
+		opened.OutputText = string(plaintext)
+	}
+
+	opened.Input = make([]responseapi.InputItem, len(resp.Input))
+	for i, item := range resp.Input {
+		if len(item.Content) > 0 {
+			var encoded string
+			if err := json.Unmarshal(item.Content, &encoded); err != nil {
+				return nil, fmt.Errorf("malformed sealed input content: %w", err)
+			}
+			ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("malformed sealed input content: %w", err)
+			}
+			plaintext, err := s.encryptor.Open(tenantID, ciphertext)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open input content: %w", err)
+			}
+			item.Content = plaintext
+		}
+		opened.Input[i] = item
+	}
+
+	opened.Output = make([]responseapi.OutputItem, len(resp.Output))
+	for i, item := range resp.Output {
+		if len(item.Content) == 1 && item.Content[0].Type == encryptedContentPartType {
+			ciphertext, err := base64.StdEncoding.DecodeString(item.Content[0].Text)
+			if err != nil {
+				return nil, fmt.Errorf("malformed sealed output content: %w", err)
+			}
+			plaintext, err := s.encryptor.Open(tenantID, ciphertext)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open output content: %w", err)
+			}
+			var parts []responseapi.ContentPart
+			if err := json.Unmarshal(plaintext, &parts); err != nil {
+				return nil, fmt.Errorf("malformed sealed output content: %w", err)
+			}
+			item.Content = parts
+		}
+		opened.Output[i] = item
+	}
+
+	return &opened, nil
+}
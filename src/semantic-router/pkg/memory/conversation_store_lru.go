@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/responseapi"
+)
+
+// LRUConversationStoreConfig configures LRUConversationStore.
+type LRUConversationStoreConfig struct {
+	// MaxConversations bounds how many conversations are kept in memory;
+	// the least-recently-used conversation is evicted once the cap is hit.
+	MaxConversations int
+}
+
+// DefaultLRUConversationStoreConfig returns sensible defaults for development.
+func DefaultLRUConversationStoreConfig() LRUConversationStoreConfig {
+	return LRUConversationStoreConfig{MaxConversations: 1000}
+}
+
+// LRUConversationStore is an in-process ConversationStore capped by
+// conversation count, evicting the least-recently-used conversation once
+// full. This is the original behavior of keeping the whole chain in memory
+// on the router, now capped and exposed behind ConversationStore instead of
+// being reached through ResponseAPIContext.ConversationHistory directly.
+type LRUConversationStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List               // front = most recently used conversation ID
+	elements map[string]*list.Element // conversationID -> element in order
+	history  map[string][]*responseapi.StoredResponse
+	byRespID map[string]*responseapi.StoredResponse // response ID -> response, for chain lookups
+}
+
+// NewLRUConversationStore creates an LRUConversationStore. A non-positive
+// config.MaxConversations falls back to DefaultLRUConversationStoreConfig.
+func NewLRUConversationStore(config LRUConversationStoreConfig) *LRUConversationStore {
+	capacity := config.MaxConversations
+	if capacity <= 0 {
+		capacity = DefaultLRUConversationStoreConfig().MaxConversations
+	}
+	return &LRUConversationStore{
+		capacity: capacity,
+		order:    list.New(),
+		elements: make(map[string]*list.Element),
+		history:  make(map[string][]*responseapi.StoredResponse),
+		byRespID: make(map[string]*responseapi.StoredResponse),
+	}
+}
+
+// Get implements ConversationStore.
+func (s *LRUConversationStore) Get(ctx context.Context, conversationID string) ([]*responseapi.StoredResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	responses, ok := s.history[conversationID]
+	if !ok {
+		return nil, ErrConversationNotFound
+	}
+	s.touch(conversationID)
+
+	out := make([]*responseapi.StoredResponse, len(responses))
+	copy(out, responses)
+	return out, nil
+}
+
+// Append implements ConversationStore.
+func (s *LRUConversationStore) Append(ctx context.Context, conversationID string, resp *responseapi.StoredResponse) error {
+	if conversationID == "" {
+		return fmt.Errorf("conversationID is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history[conversationID] = append(s.history[conversationID], resp)
+	if resp.ID != "" {
+		s.byRespID[resp.ID] = resp
+	}
+	s.touch(conversationID)
+	s.evictIfNeeded()
+	return nil
+}
+
+// LookupByPreviousResponseID implements ConversationStore.
+func (s *LRUConversationStore) LookupByPreviousResponseID(ctx context.Context, previousResponseID string) (*responseapi.StoredResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp, ok := s.byRespID[previousResponseID]
+	if !ok {
+		return nil, ErrConversationNotFound
+	}
+	return resp, nil
+}
+
+// Close implements ConversationStore. There are no resources to release.
+func (s *LRUConversationStore) Close() error { return nil }
+
+// touch marks conversationID as most-recently-used. Caller must hold s.mu.
+func (s *LRUConversationStore) touch(conversationID string) {
+	if elem, ok := s.elements[conversationID]; ok {
+		s.order.MoveToFront(elem)
+		return
+	}
+	s.elements[conversationID] = s.order.PushFront(conversationID)
+}
+
+// evictIfNeeded drops the least-recently-used conversation(s) once the
+// store is over capacity. Caller must hold s.mu.
+func (s *LRUConversationStore) evictIfNeeded() {
+	for len(s.history) > s.capacity {
+		oldest := s.order.Back()
+		if oldest == nil {
+			return
+		}
+		conversationID := oldest.Value.(string)
+		s.order.Remove(oldest)
+		delete(s.elements, conversationID)
+		for _, resp := range s.history[conversationID] {
+			delete(s.byRespID, resp.ID)
+		}
+		delete(s.history, conversationID)
+	}
+}
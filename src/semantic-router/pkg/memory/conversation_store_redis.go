@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/responseapi"
+)
+
+// RedisConversationStoreConfig configures RedisConversationStore.
+type RedisConversationStoreConfig struct {
+	// Addr is the Redis address ("host:port"). When Cluster is true, it may
+	// be a comma-separated list of cluster node addresses.
+	Addr string
+
+	// Password authenticates with Redis AUTH / ACL. Empty disables auth.
+	Password string
+
+	// TLS enables TLS for the connection, e.g. for managed Redis offerings
+	// that require in-transit encryption.
+	TLS bool
+
+	// Cluster switches to a Redis Cluster client instead of a single-node
+	// client, for horizontally-scaled deployments.
+	Cluster bool
+}
+
+// redisClient is the subset of go-redis's Cmdable that RedisConversationStore
+// needs, plus Close. Both *goredis.Client and *goredis.ClusterClient satisfy
+// it directly, so the store works with either without an adapter.
+type redisClient interface {
+	RPush(ctx context.Context, key string, values ...interface{}) *goredis.IntCmd
+	LRange(ctx context.Context, key string, start, stop int64) *goredis.StringSliceCmd
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *goredis.StatusCmd
+	Get(ctx context.Context, key string) *goredis.StringCmd
+	Close() error
+}
+
+// RedisConversationStore is a Redis-backed ConversationStore, shared across
+// router replicas and durable across restarts. Conversation history is kept
+// as a Redis list (one JSON-encoded StoredResponse per RPush); chain lookups
+// by PreviousResponseID are served by a secondary key per response ID.
+type RedisConversationStore struct {
+	client redisClient
+}
+
+// NewRedisConversationStore connects to Redis per config and returns a
+// RedisConversationStore. Supports password auth, TLS, and cluster mode.
+func NewRedisConversationStore(config RedisConversationStoreConfig) (*RedisConversationStore, error) {
+	if config.Addr == "" {
+		return nil, fmt.Errorf("redis conversation store requires addr")
+	}
+
+	var tlsConfig *tls.Config
+	if config.TLS {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	var client redisClient
+	if config.Cluster {
+		client = goredis.NewClusterClient(&goredis.ClusterOptions{
+			Addrs:     strings.Split(config.Addr, ","),
+			Password:  config.Password,
+			TLSConfig: tlsConfig,
+		})
+	} else {
+		client = goredis.NewClient(&goredis.Options{
+			Addr:      config.Addr,
+			Password:  config.Password,
+			TLSConfig: tlsConfig,
+		})
+	}
+
+	return &RedisConversationStore{client: client}, nil
+}
+
+func conversationHistoryKey(conversationID string) string { return "semantic-router:conv:history:" + conversationID }
+func conversationRespIDKey(responseID string) string      { return "semantic-router:conv:resp:" + responseID }
+
+// Get implements ConversationStore.
+func (s *RedisConversationStore) Get(ctx context.Context, conversationID string) ([]*responseapi.StoredResponse, error) {
+	raw, err := s.client.LRange(ctx, conversationHistoryKey(conversationID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis LRange failed: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil, ErrConversationNotFound
+	}
+
+	responses := make([]*responseapi.StoredResponse, 0, len(raw))
+	for _, entry := range raw {
+		var resp responseapi.StoredResponse
+		if err := json.Unmarshal([]byte(entry), &resp); err != nil {
+			return nil, fmt.Errorf("malformed stored response: %w", err)
+		}
+		responses = append(responses, &resp)
+	}
+	return responses, nil
+}
+
+// Append implements ConversationStore.
+func (s *RedisConversationStore) Append(ctx context.Context, conversationID string, resp *responseapi.StoredResponse) error {
+	if conversationID == "" {
+		return fmt.Errorf("conversationID is required")
+	}
+
+	encoded, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stored response: %w", err)
+	}
+	if err := s.client.RPush(ctx, conversationHistoryKey(conversationID), encoded).Err(); err != nil {
+		return fmt.Errorf("redis RPush failed: %w", err)
+	}
+	if resp.ID != "" {
+		if err := s.client.Set(ctx, conversationRespIDKey(resp.ID), encoded, 0).Err(); err != nil {
+			return fmt.Errorf("redis Set failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// LookupByPreviousResponseID implements ConversationStore.
+func (s *RedisConversationStore) LookupByPreviousResponseID(ctx context.Context, previousResponseID string) (*responseapi.StoredResponse, error) {
+	raw, err := s.client.Get(ctx, conversationRespIDKey(previousResponseID)).Bytes()
+	if err != nil {
+		if err == goredis.Nil {
+			return nil, ErrConversationNotFound
+		}
+		return nil, fmt.Errorf("redis Get failed: %w", err)
+	}
+
+	var resp responseapi.StoredResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("malformed stored response: %w", err)
+	}
+	return &resp, nil
+}
+
+// Close implements ConversationStore.
+func (s *RedisConversationStore) Close() error {
+	return s.client.Close()
+}
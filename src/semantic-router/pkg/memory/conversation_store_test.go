@@ -0,0 +1,233 @@
+package memory
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/responseapi"
+)
+
+func TestLRUConversationStore_AppendAndGet(t *testing.T) {
+	store := NewLRUConversationStore(LRUConversationStoreConfig{MaxConversations: 10})
+	ctx := context.Background()
+
+	if err := store.Append(ctx, "conv_1", &responseapi.StoredResponse{ID: "resp_1", OutputText: "hi"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := store.Append(ctx, "conv_1", &responseapi.StoredResponse{ID: "resp_2", OutputText: "there"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	got, err := store.Get(ctx, "conv_1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(got))
+	}
+	if got[0].ID != "resp_1" || got[1].ID != "resp_2" {
+		t.Errorf("unexpected order: %+v", got)
+	}
+}
+
+func TestLRUConversationStore_GetUnknownConversation(t *testing.T) {
+	store := NewLRUConversationStore(LRUConversationStoreConfig{MaxConversations: 10})
+
+	_, err := store.Get(context.Background(), "nonexistent")
+	if err != ErrConversationNotFound {
+		t.Errorf("expected ErrConversationNotFound, got %v", err)
+	}
+}
+
+func TestLRUConversationStore_LookupByPreviousResponseID(t *testing.T) {
+	store := NewLRUConversationStore(LRUConversationStoreConfig{MaxConversations: 10})
+	ctx := context.Background()
+	resp := &responseapi.StoredResponse{ID: "resp_1", ConversationID: "conv_1", OutputText: "hi"}
+
+	if err := store.Append(ctx, "conv_1", resp); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	got, err := store.LookupByPreviousResponseID(ctx, "resp_1")
+	if err != nil {
+		t.Fatalf("LookupByPreviousResponseID failed: %v", err)
+	}
+	if got.OutputText != "hi" {
+		t.Errorf("got %q, want %q", got.OutputText, "hi")
+	}
+
+	if _, err := store.LookupByPreviousResponseID(ctx, "resp_missing"); err != ErrConversationNotFound {
+		t.Errorf("expected ErrConversationNotFound, got %v", err)
+	}
+}
+
+func TestLRUConversationStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	store := NewLRUConversationStore(LRUConversationStoreConfig{MaxConversations: 2})
+	ctx := context.Background()
+
+	_ = store.Append(ctx, "conv_a", &responseapi.StoredResponse{ID: "a1"})
+	_ = store.Append(ctx, "conv_b", &responseapi.StoredResponse{ID: "b1"})
+	// Touch conv_a so conv_b becomes the least-recently-used.
+	if _, err := store.Get(ctx, "conv_a"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	_ = store.Append(ctx, "conv_c", &responseapi.StoredResponse{ID: "c1"})
+
+	if _, err := store.Get(ctx, "conv_b"); err != ErrConversationNotFound {
+		t.Errorf("expected conv_b to be evicted, got err=%v", err)
+	}
+	if _, err := store.Get(ctx, "conv_a"); err != nil {
+		t.Errorf("expected conv_a to survive eviction, got err=%v", err)
+	}
+	if _, err := store.Get(ctx, "conv_c"); err != nil {
+		t.Errorf("expected conv_c to be present, got err=%v", err)
+	}
+}
+
+func newTestEnvelopeEncryptor(t *testing.T) EnvelopeEncryptor {
+	t.Helper()
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	t.Setenv("TEST_CONVERSATION_STORE_KEK", base64.StdEncoding.EncodeToString(key))
+
+	encryptor, err := NewEnvelopeEncryptor(&EnvelopeEncryptionConfig{
+		KEKSource: KEKSourceEnv,
+		KEKPath:   "TEST_CONVERSATION_STORE_KEK",
+	})
+	if err != nil {
+		t.Fatalf("NewEnvelopeEncryptor failed: %v", err)
+	}
+	return encryptor
+}
+
+func TestEnvelopeEncryptor_SealOpenRoundTrip(t *testing.T) {
+	encryptor := newTestEnvelopeEncryptor(t)
+
+	plaintext := []byte("the user's favorite color is blue")
+	sealed, err := encryptor.Seal("tenant_a", plaintext)
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+	if string(sealed) == string(plaintext) {
+		t.Fatal("sealed value should not equal plaintext")
+	}
+
+	opened, err := encryptor.Open("tenant_a", sealed)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if string(opened) != string(plaintext) {
+		t.Errorf("got %q, want %q", opened, plaintext)
+	}
+}
+
+func TestEnvelopeEncryptor_RejectsTamperedCiphertext(t *testing.T) {
+	encryptor := newTestEnvelopeEncryptor(t)
+
+	sealed, err := encryptor.Seal("tenant_a", []byte("secret"))
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+	var env envelope
+	if err := json.Unmarshal(sealed, &env); err != nil {
+		t.Fatalf("Unmarshal sealed envelope failed: %v", err)
+	}
+	env.Ciphertext[len(env.Ciphertext)-1] ^= 0xFF
+	tampered, err := json.Marshal(env)
+	if err != nil {
+		t.Fatalf("Marshal tampered envelope failed: %v", err)
+	}
+
+	if _, err := encryptor.Open("tenant_a", tampered); err == nil {
+		t.Error("expected tampered envelope to fail decryption")
+	}
+}
+
+func TestNewEnvelopeEncryptor_MissingEnvVar(t *testing.T) {
+	_, err := NewEnvelopeEncryptor(&EnvelopeEncryptionConfig{
+		KEKSource: KEKSourceEnv,
+		KEKPath:   "THIS_ENV_VAR_DOES_NOT_EXIST",
+	})
+	if err == nil {
+		t.Error("expected an error when the KEK env var is unset")
+	}
+}
+
+func TestEncryptedConversationStore_RoundTripsThroughPlaintextUnderlyingStore(t *testing.T) {
+	encryptor := newTestEnvelopeEncryptor(t)
+	inner := NewLRUConversationStore(LRUConversationStoreConfig{MaxConversations: 10})
+	store := NewEncryptedConversationStore(inner, encryptor)
+	ctx := context.Background()
+
+	resp := &responseapi.StoredResponse{
+		ID:             "resp_1",
+		ConversationID: "conv_1",
+		Input: []responseapi.InputItem{
+			{Type: "message", Role: "user", Content: []byte(`"my budget is $10,000"`)},
+		},
+		OutputText: "got it, $10,000 budget noted",
+	}
+
+	if err := store.Append(ctx, "conv_1", resp); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	// The underlying backend must never see plaintext.
+	rawStored, err := inner.Get(ctx, "conv_1")
+	if err != nil {
+		t.Fatalf("inner.Get failed: %v", err)
+	}
+	if rawStored[0].OutputText == resp.OutputText {
+		t.Error("underlying store should hold ciphertext, not plaintext OutputText")
+	}
+	if string(rawStored[0].Input[0].Content) == string(resp.Input[0].Content) {
+		t.Error("underlying store should hold ciphertext, not plaintext input content")
+	}
+
+	got, err := store.Get(ctx, "conv_1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 response, got %d", len(got))
+	}
+	if got[0].OutputText != resp.OutputText {
+		t.Errorf("got OutputText %q, want %q", got[0].OutputText, resp.OutputText)
+	}
+	if string(got[0].Input[0].Content) != string(resp.Input[0].Content) {
+		t.Errorf("got input content %q, want %q", got[0].Input[0].Content, resp.Input[0].Content)
+	}
+}
+
+func TestConversationStoreConfigFromMap(t *testing.T) {
+	m := map[string]interface{}{
+		"backend": "redis",
+		"addr":    "redis.internal:6379",
+		"tls":     true,
+		"cluster": true,
+	}
+
+	cfg := conversationStoreConfigFromMap(m)
+
+	if cfg.Backend != ConversationStoreBackendRedis {
+		t.Errorf("got backend %q, want %q", cfg.Backend, ConversationStoreBackendRedis)
+	}
+	if cfg.Redis.Addr != "redis.internal:6379" {
+		t.Errorf("got addr %q, want %q", cfg.Redis.Addr, "redis.internal:6379")
+	}
+	if !cfg.Redis.TLS || !cfg.Redis.Cluster {
+		t.Errorf("expected TLS and Cluster to be true, got %+v", cfg.Redis)
+	}
+}
+
+func TestConversationStoreConfigFromMap_NilDefaultsToLRU(t *testing.T) {
+	cfg := conversationStoreConfigFromMap(nil)
+
+	if cfg.Backend != ConversationStoreBackendLRU {
+		t.Errorf("got backend %q, want %q", cfg.Backend, ConversationStoreBackendLRU)
+	}
+}
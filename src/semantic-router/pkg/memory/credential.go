@@ -0,0 +1,204 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// Credential is a short-lived secret (a Milvus RBAC token, a Vault-issued DB
+// password, a cloud IAM token) together with how long it's valid for.
+type Credential struct {
+	Value string
+	TTL   time.Duration
+}
+
+// CredentialSource mints or re-mints a Credential on demand. Implementations
+// plug in whatever issues the underlying secret: Vault's token/lease API, a
+// file Kubernetes projects a rotated secret into, an environment variable a
+// sidecar keeps refreshed. CredentialRenewer calls Fetch both for the
+// initial credential and for every renewal.
+type CredentialSource interface {
+	Fetch(ctx context.Context) (Credential, error)
+}
+
+// EnvCredentialSource reads a credential from an environment variable on
+// every Fetch, with a caller-supplied TTL since env vars carry no lease
+// metadata of their own. Suited to a sidecar (e.g. vault-agent) that
+// rewrites the process environment... in practice most runtimes snapshot
+// the environment at process start, so this is most useful when TTL is long
+// and the "renewal" is really just re-reading a value that rarely changes.
+type EnvCredentialSource struct {
+	EnvVar string
+	TTL    time.Duration
+}
+
+// Fetch implements CredentialSource.
+func (s EnvCredentialSource) Fetch(ctx context.Context) (Credential, error) {
+	value := os.Getenv(s.EnvVar)
+	if value == "" {
+		return Credential{}, fmt.Errorf("memory: credential env var %q is unset", s.EnvVar)
+	}
+	return Credential{Value: value, TTL: s.TTL}, nil
+}
+
+// FileCredentialSource re-reads a credential from a file on every Fetch,
+// suited to a Vault Agent or cloud-provider sidecar that rewrites the file
+// in place as it rotates the underlying secret.
+type FileCredentialSource struct {
+	Path string
+	TTL  time.Duration
+}
+
+// Fetch implements CredentialSource.
+func (s FileCredentialSource) Fetch(ctx context.Context) (Credential, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return Credential{}, fmt.Errorf("memory: read credential file %q: %w", s.Path, err)
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return Credential{}, fmt.Errorf("memory: credential file %q is empty", s.Path)
+	}
+	return Credential{Value: value, TTL: s.TTL}, nil
+}
+
+// CredentialRotator is implemented by a Store backend that holds a live
+// connection authenticated with a short-lived credential and can swap in a
+// freshly renewed one without being torn down and reconstructed. NewStore
+// wires a configured StoreConfig.CredentialSource's renewals into this
+// automatically when the backend it built implements it.
+type CredentialRotator interface {
+	RotateCredential(ctx context.Context, cred Credential) error
+}
+
+// renewAtFraction is how far into a credential's TTL CredentialRenewer
+// schedules the next renewal - Vault's LifetimeWatcher defaults to the same
+// roughly-2/3 grace window, leaving room for a few retries before the
+// credential actually expires.
+const renewAtFraction = 2.0 / 3.0
+
+// credentialRetryInitialBackoff/credentialRetryMaxBackoff bound the backoff
+// CredentialRenewer uses between renewal retries after a transient failure,
+// the same shape connectWithBackoff uses for initial connections.
+const (
+	credentialRetryInitialBackoff = 1 * time.Second
+	credentialRetryMaxBackoff     = 30 * time.Second
+)
+
+// CredentialRenewer runs a LifetimeWatcher-style background renewal loop
+// over a CredentialSource: it renews at ~2/3 of the credential's TTL and,
+// on a failed renewal, keeps retrying with backoff rather than giving up -
+// the RenewBehaviorIgnoreErrors semantics Vault's Go client offers - so a
+// transient Vault/IAM outage doesn't tear down an otherwise-healthy
+// connection just because one renewal round-trip failed.
+type CredentialRenewer struct {
+	source  CredentialSource
+	onRenew func(ctx context.Context, cred Credential)
+
+	current atomic.Value // Credential
+
+	stop chan struct{}
+	done chan struct{}
+
+	once sync.Once
+}
+
+// NewCredentialRenewer fetches source's initial credential and returns a
+// renewer ready to Start. The caller uses Current() to get that initial
+// credential for its own setup (e.g. dialing a connection); onRenew, if
+// non-nil, only fires for the renewals Start's background loop performs
+// afterwards - typically a Store backend's RotateCredential, reconnecting an
+// already-live connection with the freshly renewed secret.
+func NewCredentialRenewer(ctx context.Context, source CredentialSource, onRenew func(ctx context.Context, cred Credential)) (*CredentialRenewer, error) {
+	cred, err := source.Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("memory: initial credential fetch: %w", err)
+	}
+
+	r := &CredentialRenewer{source: source, onRenew: onRenew}
+	r.current.Store(cred)
+	return r, nil
+}
+
+// Current returns the most recently fetched/renewed credential.
+func (r *CredentialRenewer) Current() Credential {
+	return r.current.Load().(Credential)
+}
+
+// Start begins the background renewal loop, until ctx is done or Stop is
+// called. Safe to call at most once per renewer.
+func (r *CredentialRenewer) Start(ctx context.Context) {
+	r.once.Do(func() {
+		r.stop = make(chan struct{})
+		r.done = make(chan struct{})
+		go r.run(ctx)
+	})
+}
+
+func (r *CredentialRenewer) run(ctx context.Context) {
+	defer close(r.done)
+
+	cred := r.Current()
+	backoff := credentialRetryInitialBackoff
+	nextRenewal := renewalDelay(cred.TTL)
+
+	for {
+		timer := time.NewTimer(nextRenewal)
+		select {
+		case <-timer.C:
+		case <-r.stop:
+			timer.Stop()
+			return
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+
+		renewed, err := r.source.Fetch(ctx)
+		if err != nil {
+			logging.Warnf("Memory: credential renewal failed, retrying in %s (ignoring error per RenewBehaviorIgnoreErrors): %v", backoff, err)
+			nextRenewal = backoff
+			backoff *= 2
+			if backoff > credentialRetryMaxBackoff {
+				backoff = credentialRetryMaxBackoff
+			}
+			continue
+		}
+
+		r.current.Store(renewed)
+		if r.onRenew != nil {
+			r.onRenew(ctx, renewed)
+		}
+		backoff = credentialRetryInitialBackoff
+		nextRenewal = renewalDelay(renewed.TTL)
+	}
+}
+
+// renewalDelay returns how long to wait before the next renewal attempt for
+// a credential with the given TTL: ~2/3 of it, or credentialRetryMaxBackoff
+// if ttl is non-positive (a credential with no expiry still gets re-fetched
+// periodically, in case the source rotates it out of band).
+func renewalDelay(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return credentialRetryMaxBackoff
+	}
+	return time.Duration(float64(ttl) * renewAtFraction)
+}
+
+// Stop ends the background renewal loop, if Start was ever called, and
+// waits for it to exit before returning. Safe to call on a renewer that was
+// never started.
+func (r *CredentialRenewer) Stop() {
+	if r.stop == nil {
+		return
+	}
+	close(r.stop)
+	<-r.done
+}
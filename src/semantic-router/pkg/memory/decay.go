@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"math"
+	"time"
+)
+
+// DecayPolicy configures how InMemoryStore.Retrieve folds a memory's
+// Importance/AccessCount into its ranking score, in addition to whatever
+// Scorer produces for the query. Unlike MemoryConsolidator's Lambda-based
+// decayScore, which rescores Importance in place on a periodic background
+// pass, DecayPolicy is applied inline on every Retrieve call, so a change
+// to the policy is visible immediately without waiting for the next pass.
+//
+// The zero value is a no-op: Retrieve behaves exactly as it did before
+// DecayPolicy existed.
+type DecayPolicy struct {
+	// HalfLife is the age, measured from a memory's AccessedAt, at which its
+	// Importance contributes half as much to the score:
+	// importance * exp(-ln(2) * age / HalfLife). <= 0 disables decay - the
+	// importance term is used as-is, undecayed.
+	HalfLife time.Duration
+
+	// Floor is the minimum value the decay multiplier (the exp(...) term
+	// above) can fall to, so a memory's importance never decays all the way
+	// to zero purely from age. Only meaningful when HalfLife > 0.
+	Floor float64
+
+	// AccessBoost scales a log(1+AccessCount) term added to the decayed
+	// importance, so memories retrieved often outrank equally-decayed ones
+	// that have never been revisited. <= 0 disables the boost.
+	AccessBoost float64
+}
+
+// isZero reports whether p has no effect on scoring, so retrieveLocked can
+// skip the pass entirely rather than multiplying every result by 1.
+func (p DecayPolicy) isZero() bool {
+	return p.HalfLife <= 0 && p.AccessBoost <= 0
+}
+
+// score computes mem's decayed score as of now: importance * exp(-ln(2)
+// * age_hours / halfLife) + accessBoost * log(1 + AccessCount). A memory
+// that has never been scored (Importance <= 0) uses 0.5 as its base
+// importance, matching decayScore and createNewMemory's default.
+func (p DecayPolicy) score(mem *Memory, now time.Time) float64 {
+	importance := mem.Importance
+	if importance <= 0 {
+		importance = 0.5
+	}
+
+	decay := 1.0
+	if p.HalfLife > 0 {
+		ageHours := now.Sub(mem.AccessedAt).Hours()
+		if ageHours < 0 {
+			ageHours = 0
+		}
+		decay = math.Exp(-math.Ln2 * ageHours / p.HalfLife.Hours())
+		if decay < p.Floor {
+			decay = p.Floor
+		}
+	}
+
+	boost := 0.0
+	if p.AccessBoost > 0 {
+		boost = p.AccessBoost * math.Log(1+float64(mem.AccessCount))
+	}
+
+	return importance*decay + boost
+}
@@ -0,0 +1,212 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecayPolicy_ZeroValueIsNoOp(t *testing.T) {
+	var p DecayPolicy
+	assert.True(t, p.isZero())
+
+	mem := &Memory{Importance: 0.8, AccessedAt: time.Now().Add(-1000 * time.Hour)}
+	assert.Equal(t, 0.8, p.score(mem, time.Now()))
+}
+
+func TestDecayPolicy_ScoreHalvesAtHalfLife(t *testing.T) {
+	p := DecayPolicy{HalfLife: time.Hour}
+	now := time.Now()
+	mem := &Memory{Importance: 1.0, AccessedAt: now.Add(-time.Hour)}
+
+	assert.InDelta(t, 0.5, p.score(mem, now), 1e-9)
+}
+
+func TestDecayPolicy_FloorBoundsDecay(t *testing.T) {
+	p := DecayPolicy{HalfLife: time.Hour, Floor: 0.1}
+	now := time.Now()
+	mem := &Memory{Importance: 1.0, AccessedAt: now.Add(-100 * time.Hour)}
+
+	assert.InDelta(t, 0.1, p.score(mem, now), 1e-9)
+}
+
+func TestDecayPolicy_AccessBoostRewardsFrequentRetrieval(t *testing.T) {
+	p := DecayPolicy{AccessBoost: 1.0}
+	now := time.Now()
+	frequentlyAccessed := &Memory{Importance: 0.5, AccessCount: 10, AccessedAt: now}
+	neverAccessed := &Memory{Importance: 0.5, AccessCount: 0, AccessedAt: now}
+
+	assert.Greater(t, p.score(frequentlyAccessed, now), p.score(neverAccessed, now))
+}
+
+func TestInMemoryStore_Retrieve_AppliesDecayPolicy(t *testing.T) {
+	store := NewInMemoryStoreWithConfig(InMemoryStoreConfig{
+		Decay: DecayPolicy{HalfLife: time.Hour, Floor: 0.01},
+	})
+	ctx := context.Background()
+
+	fresh := &Memory{UserID: "user_1", Content: "tea preference", Importance: 1.0}
+	stale := &Memory{UserID: "user_1", Content: "tea preference too", Importance: 1.0}
+	require.NoError(t, store.Store(ctx, fresh))
+	require.NoError(t, store.Store(ctx, stale))
+
+	// Backdate stale's AccessedAt well past the half-life so it decays hard.
+	store.mu.Lock()
+	store.memories[stale.ID].AccessedAt = time.Now().Add(-100 * time.Hour)
+	store.mu.Unlock()
+
+	results, err := store.Retrieve(ctx, RetrieveOptions{UserID: "user_1", Query: "tea preference", Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, fresh.ID, results[0].Memory.ID, "recently-accessed memory should outrank the decayed one")
+}
+
+func TestInMemoryStore_Consolidate_MergesSimilarMemoriesIntoOne(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	a := &Memory{UserID: "user_1", Type: MemoryTypeSemantic, Content: "User likes tea", Importance: 0.4}
+	b := &Memory{UserID: "user_1", Type: MemoryTypeSemantic, Content: "User enjoys tea", Importance: 0.9}
+	c := &Memory{UserID: "user_1", Type: MemoryTypeSemantic, Content: "User works in finance", Importance: 0.2}
+	for _, mem := range []*Memory{a, b, c} {
+		require.NoError(t, store.Store(ctx, mem))
+	}
+
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"User likes tea":        {1, 0},
+		"User enjoys tea":       {0.99, 0.01},
+		"User works in finance": {0, 1},
+	}}
+
+	result, err := store.Consolidate(ctx, ConsolidateOptions{
+		UserID:   "user_1",
+		Embedder: embedder,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ClustersMerged)
+	assert.Equal(t, 2, result.MemoriesRemoved)
+	require.Len(t, result.Created, 1)
+
+	merged, err := store.Get(ctx, result.Created[0])
+	require.NoError(t, err)
+	assert.Contains(t, merged.Content, "User likes tea")
+	assert.Contains(t, merged.Content, "User enjoys tea")
+	assert.Equal(t, 0.9, merged.Importance, "merged memory should keep the cluster's highest importance")
+
+	assert.Equal(t, 2, store.Count(), "the merged memory plus the untouched finance memory")
+}
+
+func TestInMemoryStore_Consolidate_RequiresUserIDAndEmbedder(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	_, err := store.Consolidate(ctx, ConsolidateOptions{Embedder: &fakeEmbedder{}})
+	assert.Equal(t, ErrInvalidUserID, err)
+
+	_, err = store.Consolidate(ctx, ConsolidateOptions{UserID: "user_1"})
+	assert.Error(t, err)
+}
+
+func TestInMemoryStore_Consolidate_UsesCustomSummarizer(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	a := &Memory{UserID: "user_1", Content: "User likes tea"}
+	b := &Memory{UserID: "user_1", Content: "User enjoys tea"}
+	require.NoError(t, store.Store(ctx, a))
+	require.NoError(t, store.Store(ctx, b))
+
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"User likes tea":  {1, 0},
+		"User enjoys tea": {1, 0},
+	}}
+
+	result, err := store.Consolidate(ctx, ConsolidateOptions{
+		UserID:   "user_1",
+		Embedder: embedder,
+		Summarizer: func(ctx context.Context, variants []string) (string, error) {
+			return "custom summary", nil
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Created, 1)
+
+	merged, err := store.Get(ctx, result.Created[0])
+	require.NoError(t, err)
+	assert.Equal(t, "custom summary", merged.Content)
+}
+
+func TestInMemoryStore_ForgetStale_PrunesLowImportanceOldMemories(t *testing.T) {
+	store := NewInMemoryStoreWithConfig(InMemoryStoreConfig{
+		Decay: DecayPolicy{HalfLife: time.Hour},
+	})
+	ctx := context.Background()
+
+	stale := &Memory{UserID: "user_1", Content: "ephemeral", Importance: 0.3}
+	fresh := &Memory{UserID: "user_1", Content: "important", Importance: 0.3}
+	require.NoError(t, store.Store(ctx, stale))
+	require.NoError(t, store.Store(ctx, fresh))
+
+	store.mu.Lock()
+	store.memories[stale.ID].AccessedAt = time.Now().Add(-100 * time.Hour)
+	store.mu.Unlock()
+
+	n, err := store.ForgetStale(ctx, time.Hour, 0.1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = store.Get(ctx, stale.ID)
+	assert.Equal(t, ErrNotFound, err)
+	_, err = store.Get(ctx, fresh.ID)
+	assert.NoError(t, err)
+}
+
+func TestInMemoryStore_ForgetStale_IgnoresMemoriesAccessedRecently(t *testing.T) {
+	store := NewInMemoryStoreWithConfig(InMemoryStoreConfig{
+		Decay: DecayPolicy{HalfLife: time.Hour},
+	})
+	ctx := context.Background()
+
+	mem := &Memory{UserID: "user_1", Content: "ephemeral", Importance: 0.01}
+	require.NoError(t, store.Store(ctx, mem))
+
+	n, err := store.ForgetStale(ctx, time.Hour, 1.0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n, "memory accessed moments ago should not count as stale regardless of importance")
+}
+
+func TestInMemoryStore_StartStopMaintenance_RunsConsolidateAndForgetStale(t *testing.T) {
+	store := NewInMemoryStoreWithConfig(InMemoryStoreConfig{
+		Decay: DecayPolicy{HalfLife: time.Hour},
+		Maintenance: MaintenanceOptions{
+			Embedder: &fakeEmbedder{vectors: map[string][]float32{
+				"User likes tea":  {1, 0},
+				"User enjoys tea": {1, 0},
+			}},
+			StaleAfter:    time.Hour,
+			MinImportance: 1.0,
+		},
+	})
+	ctx := context.Background()
+
+	a := &Memory{UserID: "user_1", Content: "User likes tea", Importance: 0.2}
+	b := &Memory{UserID: "user_1", Content: "User enjoys tea", Importance: 0.2}
+	require.NoError(t, store.Store(ctx, a))
+	require.NoError(t, store.Store(ctx, b))
+
+	store.StartMaintenance(10 * time.Millisecond)
+	defer store.StopMaintenance()
+
+	require.Eventually(t, func() bool {
+		return store.Count() == 1
+	}, time.Second, 5*time.Millisecond, "maintenance should consolidate the two near-duplicates into one (ForgetStale leaves it alone since it was just accessed)")
+}
+
+func TestInMemoryStore_StartMaintenance_NoopForNonPositiveInterval(t *testing.T) {
+	store := NewInMemoryStore()
+	store.StartMaintenance(0)
+	store.StopMaintenance() // must not block/panic when Start never ran
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"time"
+)
+
+// episodicOccurrenceTolerance is how close two episodic memories'
+// OccurredAt values must be to still be considered "the same event" for
+// deduplication purposes. Wider than a single day so same-day restatements
+// ("this morning" vs "today") still collapse, but narrow enough that a
+// recurring event a year apart ("visited Kyoto in April 2024" / "in April
+// 2025") is kept as two distinct memories even if the content reads as
+// near-identical.
+const episodicOccurrenceTolerance = 48 * time.Hour
+
+// =============================================================================
+// Deduplication
+// =============================================================================
+
+// DeduplicationConfig tunes CheckDeduplication's update-vs-create decision.
+type DeduplicationConfig struct {
+	// SimilarityThreshold is the minimum similarity between new content and
+	// an existing memory above which CheckDeduplication recommends "update"
+	// instead of "create". Anything below is either unrelated or similar
+	// enough to be worth keeping as a separate memory (the "gray zone").
+	SimilarityThreshold float64
+
+	// CandidateLimit caps how many of a user's existing memories
+	// CheckDeduplication compares the new content against.
+	CandidateLimit int
+
+	// Embedding selects the model CheckDeduplication embeds content with for
+	// the similarity comparison.
+	Embedding EmbeddingConfig
+}
+
+// DefaultDeduplicationConfig returns a config that treats content scoring
+// 0.85 or higher on the configured embedding model as a restatement of an
+// existing memory, comparing against a user's 5 most keyword-relevant
+// memories of the same type.
+func DefaultDeduplicationConfig() DeduplicationConfig {
+	return DeduplicationConfig{
+		SimilarityThreshold: 0.85,
+		CandidateLimit:      5,
+		Embedding:           EmbeddingConfig{Model: EmbeddingModelBERT},
+	}
+}
+
+// DeduplicationResult is CheckDeduplication's verdict for one piece of
+// content: "update" ExistingMemory in place, or "create" a new memory.
+type DeduplicationResult struct {
+	Action         string // "update" or "create"
+	Similarity     float64
+	ExistingMemory *Memory // set only when Action == "update"
+}
+
+// CheckDeduplication looks for an existing memory of userID/memType similar
+// enough to content to be the same underlying fact restated, so a caller
+// (storeWithDeduplication, Apply) can update it in place instead of growing
+// the store with near-duplicate memories. It retrieves candidates via
+// store.Retrieve, which already scopes by user and type, then scores each
+// against content with bestDeduplicationMatch. occurredAt is the new fact's
+// event time, if any (see Memory.OccurredAt) - pass the zero time for
+// non-episodic facts, or when the caller doesn't track it.
+func CheckDeduplication(ctx context.Context, store Store, userID, content string, memType MemoryType, occurredAt time.Time, config DeduplicationConfig) DeduplicationResult {
+	limit := config.CandidateLimit
+	if limit <= 0 {
+		limit = 5
+	}
+
+	candidates, err := store.Retrieve(ctx, RetrieveOptions{
+		UserID: userID,
+		Query:  content,
+		Types:  []MemoryType{memType},
+		Limit:  limit,
+	})
+	if err != nil || len(candidates) == 0 {
+		return DeduplicationResult{Action: "create"}
+	}
+
+	return bestDeduplicationMatch(content, occurredAt, candidates, config)
+}
+
+// bestDeduplicationMatch scores content against each candidate's Content -
+// cosine similarity over freshly generated embeddings when the configured
+// model is available, falling back to keywordRelevanceScore (the same
+// stand-in similarityForMerge uses) otherwise - and recommends "update"
+// against whichever candidate scores highest, if it clears
+// config.SimilarityThreshold. Episodic candidates whose OccurredAt is
+// distinctEpisodicOccurrence from occurredAt are skipped entirely: no
+// amount of content similarity should collapse two distinct real-world
+// events into one memory.
+func bestDeduplicationMatch(content string, occurredAt time.Time, candidates []*RetrieveResult, config DeduplicationConfig) DeduplicationResult {
+	newEmbedding, embErr := GenerateEmbedding(content, config.Embedding)
+
+	var best *Memory
+	bestSim := 0.0
+	for _, cand := range candidates {
+		if distinctEpisodicOccurrence(occurredAt, cand.Memory) {
+			continue
+		}
+		sim := keywordRelevanceScore(cand.Memory.Content, content)
+		if embErr == nil {
+			if candEmbedding, err := GenerateEmbedding(cand.Memory.Content, config.Embedding); err == nil {
+				sim = cosineSimilarity(newEmbedding, candEmbedding)
+			}
+		}
+		if sim > bestSim {
+			bestSim = sim
+			best = cand.Memory
+		}
+	}
+
+	if best != nil && bestSim >= config.SimilarityThreshold {
+		return DeduplicationResult{Action: "update", Similarity: bestSim, ExistingMemory: best}
+	}
+	return DeduplicationResult{Action: "create", Similarity: bestSim}
+}
+
+// distinctEpisodicOccurrence reports whether cand is an episodic memory
+// whose OccurredAt is far enough from occurredAt (beyond
+// episodicOccurrenceTolerance) to represent a different real-world event,
+// even if its Content scores as similar to the candidate. Either side
+// missing a timestamp (zero value) skips the check and falls back to
+// content similarity alone, since there's nothing to compare.
+func distinctEpisodicOccurrence(occurredAt time.Time, cand *Memory) bool {
+	if cand.Type != MemoryTypeEpisodic || occurredAt.IsZero() || cand.OccurredAt.IsZero() {
+		return false
+	}
+	diff := occurredAt.Sub(cand.OccurredAt)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff > episodicOccurrenceTolerance
+}
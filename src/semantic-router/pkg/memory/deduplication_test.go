@@ -80,7 +80,7 @@ func TestDeduplicationLogic(t *testing.T) {
 			}
 
 			// Check deduplication for second content
-			result := CheckDeduplication(ctx, store, "test_user", tc.content2, MemoryTypeSemantic, config)
+			result := CheckDeduplication(ctx, store, "test_user", tc.content2, MemoryTypeSemantic, time.Time{}, config)
 
 			t.Logf("Content 1: %s", tc.content1)
 			t.Logf("Content 2: %s", tc.content2)
@@ -125,7 +125,7 @@ func TestDeduplicationMultipleMemories(t *testing.T) {
 
 	// Test deduplication with similar content
 	testContent := "User's budget for Hawaii trip is $10,000"
-	result := CheckDeduplication(ctx, store, "test_user_2", testContent, MemoryTypeSemantic, config)
+	result := CheckDeduplication(ctx, store, "test_user_2", testContent, MemoryTypeSemantic, time.Time{}, config)
 
 	t.Logf("Testing: %s", testContent)
 	t.Logf("Similarity: %.4f", result.Similarity)
@@ -164,7 +164,7 @@ func TestDeduplicationUserIsolation(t *testing.T) {
 	}
 
 	// Try to find similar for user2 (should not find)
-	result := CheckDeduplication(ctx, store, "user2", "User's budget for Hawaii vacation is $10,000", MemoryTypeSemantic, config)
+	result := CheckDeduplication(ctx, store, "user2", "User's budget for Hawaii vacation is $10,000", MemoryTypeSemantic, time.Time{}, config)
 
 	if result.Action != "create" {
 		t.Errorf("Expected create action (user isolation), got %s", result.Action)
@@ -192,7 +192,7 @@ func TestDeduplicationTypeIsolation(t *testing.T) {
 	}
 
 	// Try to find similar for procedural type (should not find)
-	result := CheckDeduplication(ctx, store, "test_user", "User's budget for Hawaii vacation is $10,000", MemoryTypeProcedural, config)
+	result := CheckDeduplication(ctx, store, "test_user", "User's budget for Hawaii vacation is $10,000", MemoryTypeProcedural, time.Time{}, config)
 
 	if result.Action != "create" {
 		t.Errorf("Expected create action (type isolation), got %s", result.Action)
@@ -201,3 +201,34 @@ func TestDeduplicationTypeIsolation(t *testing.T) {
 		t.Error("Should not find memory of different type")
 	}
 }
+
+func TestDeduplicationEpisodicOccurrenceIsolation(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+	config := DefaultDeduplicationConfig()
+
+	mem1 := &Memory{
+		ID:         "kyoto_2024",
+		Type:       MemoryTypeEpisodic,
+		Content:    "User visited Kyoto",
+		UserID:     "test_user",
+		OccurredAt: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		CreatedAt:  time.Now(),
+	}
+	if err := store.Store(ctx, mem1); err != nil {
+		t.Fatalf("Failed to store memory: %v", err)
+	}
+
+	// A near-identical event a year later is a distinct occurrence, not a
+	// restatement of the 2024 trip - should create, not update.
+	result := CheckDeduplication(ctx, store, "test_user", "User visited Kyoto", MemoryTypeEpisodic, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), config)
+	if result.Action != "create" {
+		t.Errorf("Expected create action (distinct episodic occurrence), got %s", result.Action)
+	}
+
+	// The same event restated within the tolerance window should still update.
+	result = CheckDeduplication(ctx, store, "test_user", "User visited Kyoto", MemoryTypeEpisodic, time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC), config)
+	if result.Action != "update" {
+		t.Errorf("Expected update action (same episodic occurrence), got %s", result.Action)
+	}
+}
@@ -0,0 +1,85 @@
+//go:build e2e
+
+// Package base wraps a real Milvus client.Client for the e2e suite, mirroring
+// the layout of Milvus's own tests/go_client/base package: a thin client
+// wrapper plus request/response logging so a CI failure is debuggable
+// without having to reproduce it locally against a live container.
+package base
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/milvus"
+	"google.golang.org/grpc"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// logTruncateLen bounds how much of a request/response is logged per call,
+// matching the referenced LoggingUnaryInterceptor pattern.
+const logTruncateLen = 300
+
+// MilvusClient wraps client.Client so suites depend on this package instead
+// of constructing a raw SDK client directly.
+type MilvusClient struct {
+	client.Client
+	Address string
+}
+
+// LoggingUnaryInterceptor logs the request before and the response after
+// every unary gRPC call the SDK makes, truncated to logTruncateLen so large
+// payloads (e.g. embeddings) don't drown out the call shape in CI logs.
+func LoggingUnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		logging.Debugf("milvus e2e: preRequest %s: %s", method, truncate(fmt.Sprintf("%v", req)))
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err != nil {
+			logging.Debugf("milvus e2e: postResponse %s: error: %v", method, err)
+			return err
+		}
+		logging.Debugf("milvus e2e: postResponse %s: %s", method, truncate(fmt.Sprintf("%v", reply)))
+		return err
+	}
+}
+
+func truncate(s string) string {
+	if len(s) <= logTruncateLen {
+		return s
+	}
+	return s[:logTruncateLen] + "...(truncated)"
+}
+
+// StartMilvus launches a real Milvus instance via testcontainers-go and
+// returns a connected, logging-wrapped client. The container and connection
+// are torn down automatically when t completes.
+func StartMilvus(t *testing.T) *MilvusClient {
+	t.Helper()
+	ctx := context.Background()
+
+	container, err := milvus.Run(ctx, "milvusdb/milvus:v2.4.0")
+	if err != nil {
+		t.Fatalf("failed to start Milvus container: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := testcontainers.TerminateContainer(container); err != nil {
+			t.Logf("failed to terminate Milvus container: %v", err)
+		}
+	})
+
+	address, err := container.ConnectionString(ctx)
+	if err != nil {
+		t.Fatalf("failed to get Milvus connection string: %v", err)
+	}
+
+	c, err := client.NewGrpcClient(ctx, address, grpc.WithChainUnaryInterceptor(LoggingUnaryInterceptor()))
+	if err != nil {
+		t.Fatalf("failed to connect to Milvus: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	return &MilvusClient{Client: c, Address: address}
+}
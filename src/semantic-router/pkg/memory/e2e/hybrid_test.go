@@ -0,0 +1,46 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory/e2e/base"
+)
+
+func TestE2E_HybridSearch(t *testing.T) {
+	mc := base.StartMilvus(t)
+	cfg := newTestConfig(t, mc.Address)
+	cfg.HybridMode = memory.HybridModeRRF
+	store, err := memory.NewMilvusStore(cfg)
+	if err != nil {
+		t.Fatalf("failed to create MilvusStore: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	if err := store.Store(ctx, &memory.Memory{
+		UserID:  "e2e_user",
+		Type:    memory.MemoryTypeSemantic,
+		Content: "The deployment pipeline uses kubectl apply followed by a canary rollout",
+	}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if err := store.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	results, err := store.Retrieve(ctx, memory.RetrieveOptions{
+		Query:  "How do we deploy with kubectl?",
+		UserID: "e2e_user",
+		Limit:  5,
+	})
+	if err != nil {
+		t.Fatalf("hybrid Retrieve failed: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("expected hybrid search to find the lexically-overlapping memory")
+	}
+}
@@ -0,0 +1,42 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory/e2e/base"
+)
+
+func TestE2E_JSONMetadataInflation(t *testing.T) {
+	mc := base.StartMilvus(t)
+	store, err := memory.NewMilvusStore(newTestConfig(t, mc.Address))
+	if err != nil {
+		t.Fatalf("failed to create MilvusStore: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	mem := &memory.Memory{
+		UserID:  "e2e_user",
+		Type:    memory.MemoryTypeSemantic,
+		Content: "The budget is $50k",
+		Metadata: map[string]any{
+			"source":     "slack",
+			"importance": "high",
+		},
+	}
+	if err := store.Store(ctx, mem); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	got, err := store.Get(ctx, mem.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Metadata["source"] != "slack" {
+		t.Errorf("expected metadata to round-trip through storage, got %+v", got.Metadata)
+	}
+}
@@ -0,0 +1,77 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory/e2e/base"
+)
+
+func TestE2E_Retrieve(t *testing.T) {
+	mc := base.StartMilvus(t)
+	store, err := memory.NewMilvusStore(newTestConfig(t, mc.Address))
+	if err != nil {
+		t.Fatalf("failed to create MilvusStore: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	if err := store.Store(ctx, &memory.Memory{
+		UserID:  "e2e_user",
+		Type:    memory.MemoryTypeSemantic,
+		Content: "The user's budget for the Hawaii trip is $10,000",
+	}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if err := store.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	results, err := store.Retrieve(ctx, memory.RetrieveOptions{
+		Query:  "What is the Hawaii trip budget?",
+		UserID: "e2e_user",
+		Limit:  5,
+	})
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("expected at least one retrieved memory")
+	}
+}
+
+func TestE2E_ThresholdFiltering(t *testing.T) {
+	mc := base.StartMilvus(t)
+	store, err := memory.NewMilvusStore(newTestConfig(t, mc.Address))
+	if err != nil {
+		t.Fatalf("failed to create MilvusStore: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	if err := store.Store(ctx, &memory.Memory{
+		UserID:  "e2e_user",
+		Type:    memory.MemoryTypeSemantic,
+		Content: "The user enjoys hiking in the mountains",
+	}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if err := store.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	results, err := store.Retrieve(ctx, memory.RetrieveOptions{
+		Query:     "What is the stock market doing today?",
+		UserID:    "e2e_user",
+		Threshold: 0.95,
+	})
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected an unrelated query with a high threshold to return no results, got %d", len(results))
+	}
+}
@@ -0,0 +1,51 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory/e2e/base"
+)
+
+func newTestConfig(t *testing.T, address string) *memory.MilvusStoreConfig {
+	t.Helper()
+	cfg := memory.DefaultMilvusConfig()
+	cfg.Address = address
+	cfg.CollectionName = "e2e_memories_" + time.Now().Format("20060102150405")
+	return cfg
+}
+
+func TestE2E_Store(t *testing.T) {
+	mc := base.StartMilvus(t)
+	store, err := memory.NewMilvusStore(newTestConfig(t, mc.Address))
+	if err != nil {
+		t.Fatalf("failed to create MilvusStore: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	mem := &memory.Memory{
+		UserID:  "e2e_user",
+		Type:    memory.MemoryTypeSemantic,
+		Content: "The user's favorite color is blue",
+	}
+
+	if err := store.Store(ctx, mem); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if mem.ID == "" {
+		t.Fatal("expected Store to assign an ID")
+	}
+
+	got, err := store.Get(ctx, mem.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Content != mem.Content {
+		t.Errorf("got content %q, want %q", got.Content, mem.Content)
+	}
+}
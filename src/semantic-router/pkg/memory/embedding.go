@@ -20,8 +20,18 @@ const (
 // EmbeddingConfig holds the embedding model configuration
 type EmbeddingConfig struct {
 	Model EmbeddingModelType
+
+	// Layer is the mmBERT transformer layer Matryoshka truncation reads
+	// from (see GenerateMatryoshkaEmbeddings). 0 defaults to layer 6,
+	// matching GenerateEmbedding's mmbert path. Ignored for other models.
+	Layer int
 }
 
+// DefaultMatryoshkaDims are the truncation dimensions GenerateMatryoshkaEmbeddings
+// produces when a caller doesn't pass its own, a coarse-to-fine ladder from
+// cheap/approximate up to the full 768-dim mmBERT embedding.
+var DefaultMatryoshkaDims = []int{128, 256, 512, 768}
+
 // GenerateEmbedding generates an embedding using the configured model
 func GenerateEmbedding(text string, cfg EmbeddingConfig) ([]float32, error) {
 	modelName := strings.ToLower(strings.TrimSpace(string(cfg.Model)))
@@ -63,3 +73,46 @@ func GenerateEmbedding(text string, cfg EmbeddingConfig) ([]float32, error) {
 		return nil, fmt.Errorf("unsupported embedding model: %s (must be 'bert', 'qwen3', 'gemma', or 'mmbert')", modelName)
 	}
 }
+
+// GenerateMatryoshkaEmbeddings generates the full-dimension mmBERT embedding
+// for text at cfg.Layer and returns it truncated to each of dims (nil/empty
+// defaults to DefaultMatryoshkaDims), keyed by dimension. Matryoshka
+// Representation Learning guarantees a prefix of the full embedding is
+// itself a valid, if coarser, embedding, so one model call covers every
+// requested dimension. Only "mmbert" supports Matryoshka truncation; any
+// other cfg.Model is an error.
+func GenerateMatryoshkaEmbeddings(text string, cfg EmbeddingConfig, dims []int) (map[int][]float32, error) {
+	modelName := strings.ToLower(strings.TrimSpace(string(cfg.Model)))
+	if modelName != string(EmbeddingModelMMBERT) {
+		return nil, fmt.Errorf("matryoshka truncation is only supported for the %q model, got %q", EmbeddingModelMMBERT, cfg.Model)
+	}
+	if len(dims) == 0 {
+		dims = DefaultMatryoshkaDims
+	}
+
+	layer := cfg.Layer
+	if layer <= 0 {
+		layer = 6
+	}
+	maxDim := dims[0]
+	for _, d := range dims {
+		if d > maxDim {
+			maxDim = d
+		}
+	}
+
+	output, err := candle_binding.GetEmbedding2DMatryoshka(text, modelName, layer, maxDim)
+	if err != nil {
+		return nil, fmt.Errorf("mmbert matryoshka embedding failed: %w", err)
+	}
+
+	result := make(map[int][]float32, len(dims))
+	for _, d := range dims {
+		if d <= 0 || d >= len(output.Embedding) {
+			result[d] = output.Embedding
+			continue
+		}
+		result[d] = output.Embedding[:d]
+	}
+	return result, nil
+}
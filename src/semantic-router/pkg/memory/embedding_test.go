@@ -0,0 +1,11 @@
+package memory
+
+import "testing"
+
+func TestGenerateMatryoshkaEmbeddings_RequiresMMBert(t *testing.T) {
+	for _, model := range []EmbeddingModelType{EmbeddingModelBERT, EmbeddingModelQwen3, EmbeddingModelGemma, ""} {
+		if _, err := GenerateMatryoshkaEmbeddings("hello", EmbeddingConfig{Model: model}, nil); err == nil {
+			t.Errorf("GenerateMatryoshkaEmbeddings with model %q: got nil error, want one (only mmbert supports Matryoshka truncation)", model)
+		}
+	}
+}
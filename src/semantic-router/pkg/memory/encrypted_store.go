@@ -0,0 +1,162 @@
+package memory
+
+import (
+	"context"
+	"encoding/base64"
+)
+
+// Cipher encrypts and decrypts memory content at rest, scoped per tenant
+// (UserID) so that compromising one tenant's key never exposes another's.
+// EnvelopeEncryptor already satisfies this shape - Cipher is an alias
+// rather than a second, duplicate interface, so encryptedStore and
+// encryptedConversationStore (see conversation_store_encrypted.go) share
+// the exact same KEK/DEK, AES-256-GCM, and KMS-pluggable machinery instead
+// of each growing their own.
+type Cipher = EnvelopeEncryptor
+
+// encryptedStore wraps a Store, transparently encrypting every Memory's
+// Content with a Cipher before handing it to the underlying backend, and
+// decrypting on the way out. Content is scoped per UserID, so a leaked or
+// stolen backend row (a DB dump, a misconfigured bucket) reveals nothing
+// without that user's data-encryption-key.
+//
+// Embedding is deliberately left in plaintext. Retrieve's vector similarity
+// search runs inside the wrapped backend (pgvector's index, Milvus's ANN,
+// Weaviate's HNSW graph, ...) against whatever it finds in the Embedding
+// column; encrypting it would either disable that index entirely (forcing
+// a brute-force decrypt-and-compare scan per query) or require a
+// searchable-encryption scheme none of this package's backends implement.
+// A deployment that also needs embeddings encrypted at rest should run the
+// backend itself inside a trusted boundary (encrypted volume, enclave) -
+// this wrapper's job is Content, the field that otherwise sits in the
+// backend in fully readable form.
+//
+// Sealing Content also means a backend's own text-relevance scoring (e.g.
+// InMemoryStore's default keywordScorer/bm25Scorer, which match query terms
+// against mem.Content) can never match anything once this wrapper is in
+// front of it - the backend only ever sees ciphertext. Deployments relying
+// on keyword/BM25 scoring rather than embedding similarity should account
+// for this before enabling encryption.
+type encryptedStore struct {
+	inner  Store
+	cipher Cipher
+}
+
+// NewEncryptedStore wraps inner so every Memory's Content is sealed with
+// cipher before being written, and opened again on every read. See
+// encryptedStore's doc comment for why Embedding is left untouched.
+//
+// The wrapper only implements the core Store interface: inner's optional
+// capabilities (StoreIterator, StoreConsolidator, StoreExpirer, ...) are not
+// passed through, since several of them (MemoryConsolidator's clustering and
+// summarization in particular) need plaintext Content to do useful work, not
+// just an inner Store to call through to. A deployment that needs both
+// encryption and those capabilities should run them against inner directly,
+// scoped to a trusted process boundary.
+func NewEncryptedStore(inner Store, cipher Cipher) Store {
+	return &encryptedStore{inner: inner, cipher: cipher}
+}
+
+// Store implements Store.
+func (s *encryptedStore) Store(ctx context.Context, mem *Memory) error {
+	sealed, err := s.seal(mem)
+	if err != nil {
+		return err
+	}
+	return s.inner.Store(ctx, sealed)
+}
+
+// Retrieve implements Store.
+func (s *encryptedStore) Retrieve(ctx context.Context, opts RetrieveOptions) ([]*RetrieveResult, error) {
+	results, err := s.inner.Retrieve(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		if _, err := s.open(r.Memory); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// Get implements Store.
+func (s *encryptedStore) Get(ctx context.Context, id string) (*Memory, error) {
+	mem, err := s.inner.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.open(mem)
+}
+
+// Update implements Store.
+func (s *encryptedStore) Update(ctx context.Context, id string, mem *Memory) error {
+	sealed, err := s.seal(mem)
+	if err != nil {
+		return err
+	}
+	return s.inner.Update(ctx, id, sealed)
+}
+
+// Forget implements Store.
+func (s *encryptedStore) Forget(ctx context.Context, id string) error {
+	return s.inner.Forget(ctx, id)
+}
+
+// ForgetByScope implements Store.
+func (s *encryptedStore) ForgetByScope(ctx context.Context, scope MemoryScope) error {
+	return s.inner.ForgetByScope(ctx, scope)
+}
+
+// UpdateScore implements Store.
+func (s *encryptedStore) UpdateScore(ctx context.Context, id string, score float64) error {
+	return s.inner.UpdateScore(ctx, id, score)
+}
+
+// BulkDelete implements Store.
+func (s *encryptedStore) BulkDelete(ctx context.Context, ids []string) error {
+	return s.inner.BulkDelete(ctx, ids)
+}
+
+// IsEnabled implements Store.
+func (s *encryptedStore) IsEnabled() bool {
+	return s.inner.IsEnabled()
+}
+
+// Close implements Store.
+func (s *encryptedStore) Close() error {
+	return s.inner.Close()
+}
+
+// seal returns a shallow copy of mem with Content replaced by its sealed
+// envelope, or mem itself if Content is empty - an empty memory body isn't
+// sensitive and round-tripping it through Seal/Open would just add cost.
+func (s *encryptedStore) seal(mem *Memory) (*Memory, error) {
+	if mem.Content == "" {
+		return mem, nil
+	}
+	ciphertext, err := s.cipher.Seal(mem.UserID, []byte(mem.Content))
+	if err != nil {
+		return nil, err
+	}
+	sealed := *mem
+	sealed.Content = base64.StdEncoding.EncodeToString(ciphertext)
+	return &sealed, nil
+}
+
+// open decrypts mem.Content in place, returning mem for convenient chaining.
+func (s *encryptedStore) open(mem *Memory) (*Memory, error) {
+	if mem.Content == "" {
+		return mem, nil
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(mem.Content)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := s.cipher.Open(mem.UserID, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	mem.Content = string(plaintext)
+	return mem, nil
+}
@@ -0,0 +1,225 @@
+package memory
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// KEKSourceType selects where the key-encryption-key (KEK) used to seal
+// per-tenant data keys is loaded from.
+type KEKSourceType string
+
+const (
+	// KEKSourceEnv reads a base64-encoded 32-byte KEK from an environment
+	// variable named by EnvelopeEncryptionConfig.KEKPath.
+	KEKSourceEnv KEKSourceType = "env"
+
+	// KEKSourceFile reads a base64-encoded 32-byte KEK from a file at
+	// EnvelopeEncryptionConfig.KEKPath, e.g. a mounted Kubernetes Secret.
+	KEKSourceFile KEKSourceType = "file"
+
+	// KEKSourceKMS delegates to a pluggable KMSClient (see KMSClient) for
+	// KEKs managed by an external key-management service.
+	KEKSourceKMS KEKSourceType = "kms"
+)
+
+// EnvelopeEncryptionConfig configures how the KEK is loaded.
+type EnvelopeEncryptionConfig struct {
+	// KEKSource selects where the KEK comes from. Defaults to KEKSourceEnv.
+	KEKSource KEKSourceType
+
+	// KEKPath is the env var name (KEKSourceEnv), file path
+	// (KEKSourceFile), or KMS key identifier (KEKSourceKMS) the KEK is
+	// loaded from.
+	KEKPath string
+
+	// KMSClient resolves the KEK when KEKSource == KEKSourceKMS. Left nil
+	// for env/file sources.
+	KMSClient KMSClient
+}
+
+// KMSClient is the seam for loading a KEK from an external key-management
+// service (AWS KMS, GCP KMS, Vault, ...). Implementations live outside this
+// package so pkg/memory doesn't depend on a specific cloud SDK.
+type KMSClient interface {
+	// DecryptKEK returns the plaintext KEK for keyID.
+	DecryptKEK(keyID string) ([]byte, error)
+}
+
+// EnvelopeEncryptor seals/opens arbitrary values with a per-tenant data key
+// (DEK), itself sealed by a single KEK. Compromising one tenant's DEK
+// doesn't expose any other tenant's data, and rotating the KEK only
+// requires re-sealing DEKs, not re-encrypting already-stored data.
+type EnvelopeEncryptor interface {
+	// Seal encrypts plaintext for tenantID, returning a self-contained
+	// envelope (sealed DEK + ciphertext) safe to persist. The envelope
+	// carries its own sealed DEK, so Open works from any process that
+	// holds the same KEK - no separate DEK lookup is required.
+	Seal(tenantID string, plaintext []byte) ([]byte, error)
+
+	// Open decrypts an envelope previously returned by Seal for tenantID.
+	Open(tenantID string, sealedEnvelope []byte) ([]byte, error)
+}
+
+// envelope is the on-wire shape written by aesEnvelopeEncryptor.Seal.
+type envelope struct {
+	SealedDEK  []byte `json:"sealed_dek"`
+	DEKNonce   []byte `json:"dek_nonce"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// aesEnvelopeEncryptor implements EnvelopeEncryptor with AES-256-GCM for
+// both the KEK->DEK seal and the DEK->plaintext seal.
+type aesEnvelopeEncryptor struct {
+	kek []byte // 32 bytes
+
+	mu   sync.Mutex
+	deks map[string][]byte // tenantID -> plaintext DEK, generated once per tenant per process
+}
+
+// NewEnvelopeEncryptor builds an EnvelopeEncryptor with a KEK loaded per
+// config.KEKSource.
+func NewEnvelopeEncryptor(config *EnvelopeEncryptionConfig) (EnvelopeEncryptor, error) {
+	if config == nil {
+		return nil, fmt.Errorf("envelope encryption config is required")
+	}
+
+	kek, err := loadKEK(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load KEK: %w", err)
+	}
+	if len(kek) != 32 {
+		return nil, fmt.Errorf("KEK must be 32 bytes (AES-256), got %d", len(kek))
+	}
+
+	return &aesEnvelopeEncryptor{
+		kek:  kek,
+		deks: make(map[string][]byte),
+	}, nil
+}
+
+func loadKEK(config *EnvelopeEncryptionConfig) ([]byte, error) {
+	switch config.KEKSource {
+	case KEKSourceFile:
+		raw, err := os.ReadFile(config.KEKPath)
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.DecodeString(string(raw))
+	case KEKSourceKMS:
+		if config.KMSClient == nil {
+			return nil, fmt.Errorf("KEKSourceKMS requires a KMSClient")
+		}
+		return config.KMSClient.DecryptKEK(config.KEKPath)
+	case KEKSourceEnv, "":
+		raw := os.Getenv(config.KEKPath)
+		if raw == "" {
+			return nil, fmt.Errorf("environment variable %q is not set", config.KEKPath)
+		}
+		return base64.StdEncoding.DecodeString(raw)
+	default:
+		return nil, fmt.Errorf("unknown KEK source: %s", config.KEKSource)
+	}
+}
+
+// Seal implements EnvelopeEncryptor.
+func (e *aesEnvelopeEncryptor) Seal(tenantID string, plaintext []byte) ([]byte, error) {
+	dek, sealedDEK, dekNonce, err := e.tenantDEK(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+
+	return json.Marshal(envelope{
+		SealedDEK:  sealedDEK,
+		DEKNonce:   dekNonce,
+		Nonce:      nonce,
+		Ciphertext: ciphertext,
+	})
+}
+
+// Open implements EnvelopeEncryptor.
+func (e *aesEnvelopeEncryptor) Open(tenantID string, sealedEnvelope []byte) ([]byte, error) {
+	var env envelope
+	if err := json.Unmarshal(sealedEnvelope, &env); err != nil {
+		return nil, fmt.Errorf("malformed envelope: %w", err)
+	}
+
+	dek, err := e.unsealDEK(env.SealedDEK, env.DEKNonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unseal DEK: %w", err)
+	}
+
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open envelope (wrong tenant or tampered data): %w", err)
+	}
+	return plaintext, nil
+}
+
+// tenantDEK returns tenantID's plaintext DEK (generating and caching one on
+// first use) along with a freshly-sealed copy of it under the KEK.
+func (e *aesEnvelopeEncryptor) tenantDEK(tenantID string) (dek, sealedDEK, dekNonce []byte, err error) {
+	e.mu.Lock()
+	dek, ok := e.deks[tenantID]
+	if !ok {
+		dek = make([]byte, 32)
+		if _, err = rand.Read(dek); err != nil {
+			e.mu.Unlock()
+			return nil, nil, nil, err
+		}
+		e.deks[tenantID] = dek
+	}
+	e.mu.Unlock()
+
+	sealedDEK, dekNonce, err = e.sealDEK(dek)
+	return dek, sealedDEK, dekNonce, err
+}
+
+func (e *aesEnvelopeEncryptor) sealDEK(dek []byte) (sealed, nonce []byte, err error) {
+	gcm, err := newGCM(e.kek)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return gcm.Seal(nil, nonce, dek, nil), nonce, nil
+}
+
+func (e *aesEnvelopeEncryptor) unsealDEK(sealed, nonce []byte) ([]byte, error) {
+	gcm, err := newGCM(e.kek)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, nonce, sealed, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,210 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Code classifies why a Store operation failed, independent of which
+// backend (InMemoryStore, MilvusStore, SQLiteStore, PostgresStore, the
+// grpcstore client) raised it, so callers in pkg/extproc can branch on a
+// numeric code instead of comparing error strings or chasing down which
+// sentinel each backend happens to return.
+type Code int
+
+const (
+	// CodeUnknown is the zero value: an error that hasn't been classified,
+	// or wasn't produced by this package at all.
+	CodeUnknown Code = iota
+
+	// CodeNotFound means the requested memory ID (or, for ForgetByScope,
+	// scope) has no matching record.
+	CodeNotFound
+
+	// CodeInvalidUserID means a required UserID was empty.
+	CodeInvalidUserID
+
+	// CodeConflict means an Update (or CompareAndSwap) was rejected because
+	// the caller's observed version no longer matches the stored one.
+	CodeConflict
+
+	// CodePermissionDenied means the caller resolved an identity that isn't
+	// allowed to read or write the memory it requested - e.g. a UserID that
+	// doesn't match the memory's owner, or a caller outside the groups a
+	// memory plugin is scoped to.
+	CodePermissionDenied
+
+	// CodeUnavailable means the backend itself couldn't be reached (Milvus
+	// connection down, gRPC backend unreachable) - worth retrying, unlike
+	// the other codes here.
+	CodeUnavailable
+
+	// CodeDeadlineExceeded means the operation didn't complete before its
+	// context's deadline.
+	CodeDeadlineExceeded
+
+	// CodeResourceExhausted means the caller's per-user memory quota (see
+	// QuotaConfig) or write-rate limit was exceeded.
+	CodeResourceExhausted
+)
+
+// String implements fmt.Stringer.
+func (c Code) String() string {
+	switch c {
+	case CodeNotFound:
+		return "not_found"
+	case CodeInvalidUserID:
+		return "invalid_user_id"
+	case CodeConflict:
+		return "conflict"
+	case CodePermissionDenied:
+		return "permission_denied"
+	case CodeUnavailable:
+		return "unavailable"
+	case CodeDeadlineExceeded:
+		return "deadline_exceeded"
+	case CodeResourceExhausted:
+		return "resource_exhausted"
+	default:
+		return "unknown"
+	}
+}
+
+// grpcCode returns the canonical gRPC status code for c.
+func (c Code) grpcCode() codes.Code {
+	switch c {
+	case CodeNotFound:
+		return codes.NotFound
+	case CodeInvalidUserID:
+		return codes.InvalidArgument
+	case CodeConflict:
+		return codes.Aborted
+	case CodePermissionDenied:
+		return codes.PermissionDenied
+	case CodeUnavailable:
+		return codes.Unavailable
+	case CodeDeadlineExceeded:
+		return codes.DeadlineExceeded
+	case CodeResourceExhausted:
+		return codes.ResourceExhausted
+	default:
+		return codes.Unknown
+	}
+}
+
+// httpStatus returns the canonical HTTP status for c.
+func (c Code) httpStatus() int {
+	switch c {
+	case CodeNotFound:
+		return http.StatusNotFound
+	case CodeInvalidUserID:
+		return http.StatusBadRequest
+	case CodeConflict:
+		return http.StatusConflict
+	case CodePermissionDenied:
+		return http.StatusForbidden
+	case CodeUnavailable:
+		return http.StatusServiceUnavailable
+	case CodeDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case CodeResourceExhausted:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error is the typed error every Store implementation (InMemoryStore,
+// MilvusStore, SQLiteStore, PostgresStore, grpcstore's client) returns for
+// a failure that maps to one of the Code values above, so a caller can
+// branch with IsCode/errors.As instead of comparing against a package-level
+// sentinel or matching error text. Op and ID are included for logging, not
+// for control flow - callers that need to distinguish "which operation
+// failed" should do so from context, not by parsing Op.
+type Error struct {
+	Code Code
+	Op   string // e.g. "Get", "Update", "Retrieve"
+	ID   string // the memory ID involved, when there is one
+	Wrap error  // the underlying cause, if any; may be nil
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	switch {
+	case e.Op != "" && e.ID != "" && e.Wrap != nil:
+		return fmt.Sprintf("memory: %s %s: %s: %v", e.Op, e.ID, e.Code, e.Wrap)
+	case e.Op != "" && e.ID != "":
+		return fmt.Sprintf("memory: %s %s: %s", e.Op, e.ID, e.Code)
+	case e.Op != "" && e.Wrap != nil:
+		return fmt.Sprintf("memory: %s: %s: %v", e.Op, e.Code, e.Wrap)
+	case e.Op != "":
+		return fmt.Sprintf("memory: %s: %s", e.Op, e.Code)
+	case e.Wrap != nil:
+		return fmt.Sprintf("memory: %s: %v", e.Code, e.Wrap)
+	default:
+		return fmt.Sprintf("memory: %s", e.Code)
+	}
+}
+
+// Unwrap lets errors.Is/errors.As see through to Wrap.
+func (e *Error) Unwrap() error {
+	return e.Wrap
+}
+
+// newError builds an *Error, the constructor every Store implementation's
+// error-returning path should go through rather than constructing Error
+// literals inline, so Op/ID are filled in consistently.
+func newError(code Code, op, id string, wrap error) *Error {
+	return &Error{Code: code, Op: op, ID: id, Wrap: wrap}
+}
+
+// NewError is newError, exported for callers outside this package (e.g.
+// pkg/extproc's MemoryFilter and group-access gating) that need to
+// construct a *memory.Error of their own rather than getting one back from
+// a Store call.
+func NewError(code Code, op, id string, wrap error) *Error {
+	return newError(code, op, id, wrap)
+}
+
+// IsCode reports whether err is (or wraps) a *memory.Error with the given
+// Code.
+func IsCode(err error, code Code) bool {
+	var merr *Error
+	if !errors.As(err, &merr) {
+		return false
+	}
+	return merr.Code == code
+}
+
+// GRPCStatus maps err to the canonical gRPC status for its Code, so an
+// extproc gRPC-facing handler can return it directly instead of
+// hand-translating each memory error into a status code. A nil err maps to
+// an OK status; an err that isn't a *memory.Error maps to codes.Unknown.
+func GRPCStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	var merr *Error
+	if !errors.As(err, &merr) {
+		return status.New(codes.Unknown, err.Error())
+	}
+	return status.New(merr.Code.grpcCode(), merr.Error())
+}
+
+// HTTPStatus maps err to the canonical HTTP status for its Code. A nil err
+// maps to http.StatusOK; an err that isn't a *memory.Error maps to
+// http.StatusInternalServerError.
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var merr *Error
+	if !errors.As(err, &merr) {
+		return http.StatusInternalServerError
+	}
+	return merr.Code.httpStatus()
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCode_GRPCAndHTTPMapping(t *testing.T) {
+	cases := []struct {
+		code Code
+		grpc codes.Code
+		http int
+	}{
+		{CodeNotFound, codes.NotFound, http.StatusNotFound},
+		{CodeInvalidUserID, codes.InvalidArgument, http.StatusBadRequest},
+		{CodeConflict, codes.Aborted, http.StatusConflict},
+		{CodePermissionDenied, codes.PermissionDenied, http.StatusForbidden},
+		{CodeUnavailable, codes.Unavailable, http.StatusServiceUnavailable},
+		{CodeDeadlineExceeded, codes.DeadlineExceeded, http.StatusGatewayTimeout},
+		{CodeUnknown, codes.Unknown, http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.code.String(), func(t *testing.T) {
+			err := newError(tc.code, "Op", "id_1", nil)
+			assert.Equal(t, tc.grpc, GRPCStatus(err).Code())
+			assert.Equal(t, tc.http, HTTPStatus(err))
+			assert.True(t, IsCode(err, tc.code))
+		})
+	}
+}
+
+func TestIsCode_FalseForOtherCodesAndNonMemoryErrors(t *testing.T) {
+	assert.False(t, IsCode(ErrNotFound, CodeInvalidUserID))
+	assert.False(t, IsCode(errors.New("plain error"), CodeNotFound))
+	assert.False(t, IsCode(nil, CodeNotFound))
+}
+
+func TestIsCode_SeesThroughWrappedErrors(t *testing.T) {
+	wrapped := fmt.Errorf("memory: apply update %s: %w", "mem_1", ErrNotFound)
+	assert.True(t, IsCode(wrapped, CodeNotFound))
+}
+
+func TestGRPCStatus_NilAndUnclassifiedErrors(t *testing.T) {
+	assert.Equal(t, codes.OK, GRPCStatus(nil).Code())
+	assert.Equal(t, codes.Unknown, GRPCStatus(errors.New("plain error")).Code())
+}
+
+func TestHTTPStatus_NilAndUnclassifiedErrors(t *testing.T) {
+	assert.Equal(t, http.StatusOK, HTTPStatus(nil))
+	assert.Equal(t, http.StatusInternalServerError, HTTPStatus(errors.New("plain error")))
+}
+
+func TestError_MessageIncludesOpIDAndWrap(t *testing.T) {
+	wrapped := errors.New("boom")
+	err := newError(CodeConflict, "Update", "mem_1", wrapped)
+
+	msg := err.Error()
+	assert.Contains(t, msg, "Update")
+	assert.Contains(t, msg, "mem_1")
+	assert.Contains(t, msg, "conflict")
+	assert.Contains(t, msg, "boom")
+	assert.Equal(t, wrapped, errors.Unwrap(err))
+}
+
+func TestErrNotFoundAndErrInvalidUserID_AreSharedSentinels(t *testing.T) {
+	// These must remain comparable by == across the package - store.go's
+	// and the backend implementations' existing `err == ErrNotFound`-style
+	// checks depend on it.
+	assert.Same(t, ErrNotFound, ErrNotFound)
+	assert.True(t, IsCode(ErrNotFound, CodeNotFound))
+	assert.True(t, IsCode(ErrInvalidUserID, CodeInvalidUserID))
+}
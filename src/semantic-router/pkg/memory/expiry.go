@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// ExpiryReaper periodically evicts TTL-expired memories (MemoryTypeWorking
+// session scratchpads, tool-call state) from a Store implementing
+// StoreExpirer. It is the TTL-eviction counterpart to MemoryConsolidator:
+// same Start/Stop-on-a-ticker shape, but driven by a single ExpireDue call
+// instead of a full-table decay scan, so it also works against backends
+// that can't cheaply iterate every row.
+type ExpiryReaper struct {
+	store    StoreExpirer
+	interval time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewExpiryReaper creates an ExpiryReaper over store, running ExpireDue
+// every interval once Start is called.
+func NewExpiryReaper(store StoreExpirer, interval time.Duration) *ExpiryReaper {
+	return &ExpiryReaper{store: store, interval: interval}
+}
+
+// RunOnce calls the underlying Store's ExpireDue once, returning the number
+// of memories removed.
+func (r *ExpiryReaper) RunOnce(ctx context.Context) (int, error) {
+	return r.store.ExpireDue(ctx)
+}
+
+// Start runs RunOnce on a ticker every interval until Stop is called. A
+// no-op if interval <= 0. Errors from RunOnce are logged, not returned -
+// expiry is best-effort background maintenance, not something a caller
+// blocks on.
+func (r *ExpiryReaper) Start(ctx context.Context) {
+	if r.interval <= 0 {
+		return
+	}
+
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if n, err := r.RunOnce(ctx); err != nil {
+					logging.Warnf("memory expiry pass failed: %v", err)
+				} else if n > 0 {
+					logging.Debugf("memory expiry pass: removed=%d", n)
+				}
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the background loop started by Start and waits for the
+// in-flight pass, if any, to finish. A no-op if Start was never called or
+// interval <= 0.
+func (r *ExpiryReaper) Stop() {
+	if r.stop == nil {
+		return
+	}
+	close(r.stop)
+	<-r.done
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInMemoryStore_ExpireDue(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	expired := &Memory{Type: MemoryTypeWorking, Content: "scratchpad", UserID: "user_1", TTL: time.Now().Add(-time.Minute)}
+	live := &Memory{Type: MemoryTypeWorking, Content: "still valid", UserID: "user_1", TTL: time.Now().Add(time.Hour)}
+	forever := &Memory{Type: MemoryTypeSemantic, Content: "no ttl", UserID: "user_1"}
+	for _, mem := range []*Memory{expired, live, forever} {
+		if err := store.Store(ctx, mem); err != nil {
+			t.Fatalf("Store: %v", err)
+		}
+	}
+
+	n, err := store.ExpireDue(ctx)
+	if err != nil {
+		t.Fatalf("ExpireDue: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("ExpireDue removed = %d, want 1", n)
+	}
+	if store.Count() != 2 {
+		t.Fatalf("Count after ExpireDue = %d, want 2", store.Count())
+	}
+	if _, err := store.Get(ctx, expired.ID); err != ErrNotFound {
+		t.Errorf("Get expired memory after ExpireDue: got %v, want ErrNotFound", err)
+	}
+}
+
+// TestInMemoryStore_Retrieve_NeverReturnsExpired exercises the race between
+// Retrieve and background expiry directly: a memory past its TTL must never
+// surface as a live result, whether or not ExpireDue has already reaped it.
+func TestInMemoryStore_Retrieve_NeverReturnsExpired(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	mem := &Memory{Type: MemoryTypeWorking, Content: "tool call scratchpad", UserID: "user_1", TTL: time.Now().Add(-time.Second)}
+	if err := store.Store(ctx, mem); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	results, err := store.Retrieve(ctx, RetrieveOptions{UserID: "user_1", Query: "scratchpad", Limit: 5})
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("Retrieve before ExpireDue = %+v, want no results for an already-expired memory", results)
+	}
+
+	if _, err := store.Get(ctx, mem.ID); err != ErrNotFound {
+		t.Errorf("Get before ExpireDue: got %v, want ErrNotFound", err)
+	}
+}
+
+// TestExpiryReaper_RacesWithRetrieve runs Retrieve and the reaper's
+// background ExpireDue loop concurrently against a shared store, so `go
+// test -race` can catch any lock ordering mistake between the two paths.
+func TestExpiryReaper_RacesWithRetrieve(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	for i := 0; i < 20; i++ {
+		store.Store(ctx, &Memory{
+			Type:    MemoryTypeWorking,
+			Content: "tool call scratchpad",
+			UserID:  "user_1",
+			TTL:     time.Now().Add(5 * time.Millisecond),
+		})
+	}
+
+	reaper := NewExpiryReaper(store, time.Millisecond)
+	reaper.Start(ctx)
+	defer reaper.Stop()
+
+	var wg sync.WaitGroup
+	deadline := time.Now().Add(50 * time.Millisecond)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for time.Now().Before(deadline) {
+			if _, err := store.Retrieve(ctx, RetrieveOptions{UserID: "user_1", Query: "scratchpad", Limit: 50}); err != nil {
+				t.Errorf("Retrieve: %v", err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+}
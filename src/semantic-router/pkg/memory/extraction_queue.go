@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/metrics"
+)
+
+// ExtractionJob is one unit of deferred work submitted to an
+// ExtractionQueue: a batch of history to extract facts from and store, for
+// one turn of one session.
+type ExtractionJob struct {
+	SessionID string
+	UserID    string
+	History   []Message
+	Turn      int
+}
+
+// ExtractionEvent reports the outcome of one ExtractionJob once a worker
+// has finished it. Facts is nil when extraction found nothing to store or
+// Err is non-nil.
+type ExtractionEvent struct {
+	SessionID string
+	UserID    string
+	Turn      int
+	Facts     []ExtractedFact
+	Err       error
+}
+
+// ExtractionProcessor does the extraction + storage work for one
+// ExtractionJob, returning the facts that ended up stored.
+type ExtractionProcessor func(ctx context.Context, job ExtractionJob) ([]ExtractedFact, error)
+
+// defaultExtractionQueueDepth bounds how many pending jobs a single
+// session may accumulate. A session producing jobs faster than workers can
+// drain them drops its oldest pending job rather than growing without
+// bound or blocking the request path that calls Submit.
+const defaultExtractionQueueDepth = 8
+
+// ExtractionQueue runs ExtractionJobs off the request path: Submit enqueues
+// a job and returns immediately, a worker pool capped at maxWorkers total
+// (system-wide, not per session) processes jobs, and Notifications reports
+// each job's outcome.
+//
+// Jobs for the same session run in submission order, one at a time - a
+// second job for a session that's still processing waits behind the first
+// rather than racing it, so a later batch's dedup pass always sees the
+// previous batch's writes. Jobs for different sessions run concurrently, up
+// to maxWorkers.
+type ExtractionQueue struct {
+	process ExtractionProcessor
+	sem     chan struct{}
+	events  chan ExtractionEvent
+
+	mu       sync.Mutex
+	sessions map[string]*extractionSessionQueue
+	wg       sync.WaitGroup
+	closed   bool
+}
+
+// extractionSessionQueue is one session's pending jobs, plus the state
+// needed to ensure at most one consumer goroutine drains it at a time.
+type extractionSessionQueue struct {
+	mu      sync.Mutex
+	pending []ExtractionJob
+	running bool
+}
+
+// NewExtractionQueue creates an ExtractionQueue that runs jobs through
+// process, with at most maxWorkers running concurrently. maxWorkers <= 0 is
+// treated as 1.
+func NewExtractionQueue(maxWorkers int, process ExtractionProcessor) *ExtractionQueue {
+	if maxWorkers <= 0 {
+		maxWorkers = 1
+	}
+	return &ExtractionQueue{
+		process:  process,
+		sem:      make(chan struct{}, maxWorkers),
+		events:   make(chan ExtractionEvent, maxWorkers*defaultExtractionQueueDepth),
+		sessions: make(map[string]*extractionSessionQueue),
+	}
+}
+
+// Notifications returns the channel an ExtractionEvent is published to as
+// each submitted job finishes. Callers that don't drain it will eventually
+// block job completion once its buffer fills; it is closed once Shutdown
+// has drained every in-flight and queued job.
+func (q *ExtractionQueue) Notifications() <-chan ExtractionEvent {
+	return q.events
+}
+
+// Submit enqueues job for processing and returns immediately. If the
+// session's queue is already at defaultExtractionQueueDepth, the oldest
+// pending job for that session is dropped (counted against
+// memory_extraction_jobs_dropped_total) to make room for job. A no-op after
+// Shutdown.
+func (q *ExtractionQueue) Submit(job ExtractionJob) {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	sq, ok := q.sessions[job.SessionID]
+	if !ok {
+		sq = &extractionSessionQueue{}
+		q.sessions[job.SessionID] = sq
+	}
+	q.mu.Unlock()
+
+	sq.mu.Lock()
+	if len(sq.pending) >= defaultExtractionQueueDepth {
+		dropped := sq.pending[0]
+		sq.pending = sq.pending[1:]
+		metrics.RecordMemoryExtractionJobDropped()
+		logging.Warnf("memory extraction queue: dropping oldest queued job for session %s (turn %d), queue full", dropped.SessionID, dropped.Turn)
+	}
+	sq.pending = append(sq.pending, job)
+	needsConsumer := !sq.running
+	if needsConsumer {
+		sq.running = true
+	}
+	sq.mu.Unlock()
+
+	if needsConsumer {
+		q.wg.Add(1)
+		go q.runSessionConsumer(sq)
+	}
+}
+
+// runSessionConsumer drains sq.pending in FIFO order, one job at a time,
+// stopping once the queue is empty - a later Submit for the same session
+// starts a fresh consumer rather than this one looping forever.
+func (q *ExtractionQueue) runSessionConsumer(sq *extractionSessionQueue) {
+	defer q.wg.Done()
+	for {
+		sq.mu.Lock()
+		if len(sq.pending) == 0 {
+			sq.running = false
+			sq.mu.Unlock()
+			return
+		}
+		job := sq.pending[0]
+		sq.pending = sq.pending[1:]
+		sq.mu.Unlock()
+
+		q.runJob(job)
+	}
+}
+
+// runJob acquires a worker-pool slot, runs process, and publishes the
+// resulting ExtractionEvent. It uses context.Background() rather than any
+// request context: by the time a job is dequeued here, the request that
+// submitted it has already returned, so there is no caller left to cancel
+// it - Shutdown, not ctx cancellation, is how a job's lifetime ends.
+func (q *ExtractionQueue) runJob(job ExtractionJob) {
+	q.sem <- struct{}{}
+	defer func() { <-q.sem }()
+
+	facts, err := q.process(context.Background(), job)
+	q.events <- ExtractionEvent{
+		SessionID: job.SessionID,
+		UserID:    job.UserID,
+		Turn:      job.Turn,
+		Facts:     facts,
+		Err:       err,
+	}
+}
+
+// Shutdown waits for every queued and in-flight job to finish, then closes
+// the Notifications channel. After Shutdown returns, Submit is a no-op.
+func (q *ExtractionQueue) Shutdown() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	q.mu.Unlock()
+
+	q.wg.Wait()
+	close(q.events)
+}
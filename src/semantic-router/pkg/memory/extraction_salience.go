@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// ExtractionTriggerReason identifies why (or whether) a ProcessResponse turn
+// submitted its history for extraction, for the extraction_triggered_reason
+// metric.
+type ExtractionTriggerReason string
+
+const (
+	// TriggerReasonModulo is the original, always-on trigger: turnCount is a
+	// multiple of the configured batch size.
+	TriggerReasonModulo ExtractionTriggerReason = "modulo"
+
+	// TriggerReasonNovelty is an adaptive early trigger ahead of the next
+	// modulo turn, because the recent conversation looks unlike anything
+	// already stored for this user.
+	TriggerReasonNovelty ExtractionTriggerReason = "novelty"
+
+	// TriggerReasonSkipped marks a modulo turn that was declined because the
+	// recent conversation looks like what's already stored, saving an LLM
+	// call that would likely find nothing new.
+	TriggerReasonSkipped ExtractionTriggerReason = "skipped"
+)
+
+// defaultSalienceWindowMessages bounds how many of the most recent history
+// messages computeNovelty embeds to represent "what's being discussed right
+// now" - enough to catch a topic shift without re-scoring the whole
+// conversation every turn.
+const defaultSalienceWindowMessages = 6
+
+// defaultSalienceTopN bounds how many of a user's existing stored memories
+// computeNovelty compares the current window against.
+const defaultSalienceTopN = 5
+
+// computeNovelty estimates how novel the most recent turns of history are
+// relative to userID's already-stored memories. It returns ok=false - "don't
+// know" rather than "novel" or "not novel" - when there's nothing to compare
+// against: no store, an empty window, or a Retrieve call that errors or
+// finds no existing memories for this user. Callers must treat ok=false as
+// "fall back to the modulo schedule", the same behavior as before adaptive
+// triggering existed.
+//
+// Novelty is one minus the mean Relevance of the top matches Retrieve finds
+// for the window text. Relevance is used rather than computing embeddings
+// directly because every Store backend already produces it as part of
+// Retrieve - keyword overlap for InMemoryStore/SQLite/Postgres, cosine
+// similarity for MilvusStore - so this stays backend-agnostic instead of
+// assuming vector search is available.
+func (e *MemoryExtractor) computeNovelty(ctx context.Context, userID string, history []Message) (float64, bool) {
+	if e.store == nil || len(history) == 0 {
+		return 0, false
+	}
+
+	window := recentWindowText(history, defaultSalienceWindowMessages)
+	if window == "" {
+		return 0, false
+	}
+
+	results, err := e.store.Retrieve(ctx, RetrieveOptions{
+		UserID: userID,
+		Query:  window,
+		Limit:  defaultSalienceTopN,
+	})
+	if err != nil || len(results) == 0 {
+		return 0, false
+	}
+
+	var sum float64
+	for _, r := range results {
+		sum += r.Relevance
+	}
+	mean := sum / float64(len(results))
+	return 1 - mean, true
+}
+
+// scheduleExtraction decides whether turnCount's ProcessResponse call should
+// submit history for extraction, and why. The modulo trigger
+// (turnCount%batchSize==0) is the hard upper bound on how long extraction
+// can be delayed - it never needs a store or a working novelty signal - and
+// is the only trigger left once cfg.NoveltyThreshold/SkipThreshold are
+// unset, so behavior degrades gracefully to the pre-adaptive-scheduling
+// semantics when they're not configured or computeNovelty can't produce a
+// signal.
+//
+//   - On a non-modulo turn, extraction fires early only if
+//     cfg.NoveltyThreshold > 0 and novelty clears it: the recent
+//     conversation looks unlike anything already stored for this user.
+//   - On a modulo turn, extraction is skipped only if cfg.SkipThreshold > 0
+//     and novelty falls below it: the recent conversation looks like what's
+//     already stored, so the scheduled LLM call would likely find nothing
+//     new.
+func (e *MemoryExtractor) scheduleExtraction(ctx context.Context, userID string, history []Message, turnCount, batchSize int) (ExtractionTriggerReason, bool) {
+	if turnCount%batchSize == 0 {
+		if e.config.SkipThreshold > 0 {
+			if novelty, ok := e.computeNovelty(ctx, userID, history); ok && novelty < e.config.SkipThreshold {
+				logging.Infof("Memory extraction: SKIPPED - novelty %.3f below skip threshold %.3f (turn %d)",
+					novelty, e.config.SkipThreshold, turnCount)
+				return TriggerReasonSkipped, false
+			}
+		}
+		return TriggerReasonModulo, true
+	}
+
+	if e.config.NoveltyThreshold <= 0 {
+		logging.Infof("Memory extraction: SKIPPED - not batch turn (turn %d, batch every %d)", turnCount, batchSize)
+		return "", false
+	}
+
+	novelty, ok := e.computeNovelty(ctx, userID, history)
+	if !ok || novelty < e.config.NoveltyThreshold {
+		logging.Infof("Memory extraction: SKIPPED - not batch turn (turn %d, batch every %d)", turnCount, batchSize)
+		return "", false
+	}
+
+	logging.Infof("Memory extraction: EARLY TRIGGER - novelty %.3f exceeds threshold %.3f (turn %d)",
+		novelty, e.config.NoveltyThreshold, turnCount)
+	return TriggerReasonNovelty, true
+}
+
+// recentWindowText joins the Content of the last n non-blank messages (or
+// all of history, if shorter) with newlines, as a Retrieve query
+// representing the current topic of conversation.
+func recentWindowText(history []Message, n int) string {
+	start := 0
+	if len(history) > n {
+		start = len(history) - n
+	}
+	var parts []string
+	for _, msg := range history[start:] {
+		if content := strings.TrimSpace(msg.Content); content != "" {
+			parts = append(parts, content)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
+)
+
+func TestComputeNovelty_NoStoredMemoriesFallsBackToModulo(t *testing.T) {
+	extractor := NewMemoryExtractorWithStore(&config.ExtractionConfig{Enabled: true}, NewInMemoryStore())
+
+	novelty, ok := extractor.computeNovelty(context.Background(), "user1", []Message{
+		{Role: "user", Content: "anything at all"},
+	})
+	assert.False(t, ok, "no existing memories means there's nothing to compare against")
+	assert.Zero(t, novelty)
+}
+
+func TestComputeNovelty_RepeatedTopicIsLowNovelty(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryStore()
+	require.NoError(t, store.Store(ctx, &Memory{
+		Type: MemoryTypeSemantic, UserID: "user1",
+		Content: "User enjoys hiking in the mountains every weekend",
+	}))
+
+	extractor := NewMemoryExtractorWithStore(&config.ExtractionConfig{Enabled: true}, store)
+
+	novelty, ok := extractor.computeNovelty(ctx, "user1", []Message{
+		{Role: "user", Content: "I went hiking in the mountains again this weekend"},
+	})
+	require.True(t, ok)
+	assert.Less(t, novelty, 0.5, "near-identical wording should score low novelty")
+}
+
+func TestComputeNovelty_TopicShiftIsHighNovelty(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryStore()
+	require.NoError(t, store.Store(ctx, &Memory{
+		Type: MemoryTypeSemantic, UserID: "user1",
+		Content: "User enjoys hiking in the mountains every weekend",
+	}))
+
+	extractor := NewMemoryExtractorWithStore(&config.ExtractionConfig{Enabled: true}, store)
+
+	novelty, ok := extractor.computeNovelty(ctx, "user1", []Message{
+		{Role: "user", Content: "Can you help me file my quarterly tax return this weekend"},
+	})
+	require.True(t, ok)
+	assert.Greater(t, novelty, 0.8, "almost no shared keywords should score high novelty")
+}
+
+// TestProcessResponse_NoveltyTriggersEarlyExtraction seeds a user's store
+// with one topic, then drives a conversation that shifts to a completely
+// different one well before the next modulo turn. With NoveltyThreshold
+// configured, extraction should fire early instead of waiting for the
+// batch-size boundary.
+func TestProcessResponse_NoveltyTriggersEarlyExtraction(t *testing.T) {
+	ctx := context.Background()
+	mockResponse := `[{"type": "semantic", "content": "User is filing a tax return"}]`
+	server := createMockLLMServer(t, mockResponse)
+	defer server.Close()
+
+	store := NewInMemoryStore()
+	require.NoError(t, store.Store(ctx, &Memory{
+		Type: MemoryTypeSemantic, UserID: "user1",
+		Content: "User enjoys hiking in the mountains every weekend",
+	}))
+
+	extractor := NewMemoryExtractorWithStore(&config.ExtractionConfig{
+		Enabled:          true,
+		Endpoint:         server.URL,
+		Model:            "test-model",
+		BatchSize:        10,
+		NoveltyThreshold: 0.5,
+	}, store)
+
+	history := []Message{
+		{Role: "user", Content: "Can you help me file my quarterly tax return this weekend"},
+	}
+
+	require.NoError(t, extractor.ProcessResponse(ctx, "session1", "user1", history))
+
+	ev := waitForExtraction(t, extractor)
+	require.NoError(t, ev.Err)
+	require.Len(t, ev.Facts, 1)
+}
+
+// TestProcessResponse_SkipsLowNoveltyModuloTurn seeds a user's store with
+// content that closely matches the current conversation, then confirms a
+// modulo turn with SkipThreshold configured declines to extract rather than
+// spending an LLM call on a batch that looks like it has nothing new.
+func TestProcessResponse_SkipsLowNoveltyModuloTurn(t *testing.T) {
+	ctx := context.Background()
+	mockResponse := `[{"type": "semantic", "content": "should not be stored"}]`
+	server := createMockLLMServer(t, mockResponse)
+	defer server.Close()
+
+	store := NewInMemoryStore()
+	require.NoError(t, store.Store(ctx, &Memory{
+		Type: MemoryTypeSemantic, UserID: "user1",
+		Content: "User enjoys hiking in the mountains every weekend",
+	}))
+
+	extractor := NewMemoryExtractorWithStore(&config.ExtractionConfig{
+		Enabled:       true,
+		Endpoint:      server.URL,
+		Model:         "test-model",
+		BatchSize:     1, // every turn is a modulo turn
+		SkipThreshold: 0.5,
+	}, store)
+
+	history := []Message{
+		{Role: "user", Content: "I went hiking in the mountains again this weekend"},
+	}
+
+	require.NoError(t, extractor.ProcessResponse(ctx, "session1", "user1", history))
+
+	select {
+	case ev := <-extractor.Notifications():
+		t.Fatalf("expected extraction to be skipped, got event: %+v", ev)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
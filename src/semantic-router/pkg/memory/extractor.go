@@ -4,16 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"regexp"
+	"runtime/debug"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/metrics"
 )
 
 // =============================================================================
@@ -44,8 +48,14 @@ type MemoryExtractor struct {
 	turnCounts  map[string]int
 	mu          sync.Mutex
 	dedupConfig DeduplicationConfig
+	recoverFunc RecoverFunc // Optional: hook invoked on a recovered ExtractFacts/ProcessResponse panic
+	queue       *ExtractionQueue
 }
 
+// defaultExtractionWorkerPoolSize is the ExtractionQueue worker pool size a
+// MemoryExtractor starts with; override with SetWorkerPoolSize.
+const defaultExtractionWorkerPoolSize = 4
+
 // NewMemoryExtractor creates a new MemoryExtractor with the given configuration.
 // The http.Client is reused across requests for connection pooling.
 func NewMemoryExtractor(cfg *config.ExtractionConfig) *MemoryExtractor {
@@ -53,12 +63,14 @@ func NewMemoryExtractor(cfg *config.ExtractionConfig) *MemoryExtractor {
 	if cfg.TimeoutSeconds > 0 {
 		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
 	}
-	return &MemoryExtractor{
+	e := &MemoryExtractor{
 		config:      cfg,
 		client:      &http.Client{Timeout: timeout},
 		turnCounts:  make(map[string]int),
 		dedupConfig: DefaultDeduplicationConfig(),
 	}
+	e.queue = NewExtractionQueue(defaultExtractionWorkerPoolSize, e.processExtractionJob)
+	return e
 }
 
 // NewMemoryExtractorWithStore creates a new MemoryExtractor with both config and store.
@@ -68,13 +80,15 @@ func NewMemoryExtractorWithStore(cfg *config.ExtractionConfig, store Store) *Mem
 	if cfg.TimeoutSeconds > 0 {
 		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
 	}
-	return &MemoryExtractor{
+	e := &MemoryExtractor{
 		config:      cfg,
 		client:      &http.Client{Timeout: timeout},
 		store:       store,
 		turnCounts:  make(map[string]int),
 		dedupConfig: DefaultDeduplicationConfig(),
 	}
+	e.queue = NewExtractionQueue(defaultExtractionWorkerPoolSize, e.processExtractionJob)
+	return e
 }
 
 // SetDeduplicationConfig sets the deduplication configuration.
@@ -82,6 +96,86 @@ func (e *MemoryExtractor) SetDeduplicationConfig(config DeduplicationConfig) {
 	e.dedupConfig = config
 }
 
+// SetRecoverFunc installs (or, passed nil, clears) the RecoverFunc invoked
+// whenever ExtractFacts or ProcessResponse recovers from a panic.
+func (e *MemoryExtractor) SetRecoverFunc(fn RecoverFunc) {
+	e.recoverFunc = fn
+}
+
+// SetWorkerPoolSize replaces the ExtractionQueue backing ProcessResponse
+// with one capped at n concurrent workers (n <= 0 resets to
+// defaultExtractionWorkerPoolSize). Must be called before the first
+// ProcessResponse call: recreating the queue after jobs have already been
+// submitted to it would silently drop them.
+func (e *MemoryExtractor) SetWorkerPoolSize(n int) {
+	if n <= 0 {
+		n = defaultExtractionWorkerPoolSize
+	}
+	e.queue = NewExtractionQueue(n, e.processExtractionJob)
+}
+
+// Notifications returns the channel ProcessResponse's ExtractionQueue
+// publishes an ExtractionEvent to once a submitted job's extraction and
+// storage has finished - the async counterpart to ProcessResponse's old
+// synchronous return. Tests that need to observe a specific batch's outcome
+// should read from this channel rather than sleeping.
+func (e *MemoryExtractor) Notifications() <-chan ExtractionEvent {
+	return e.queue.Notifications()
+}
+
+// Shutdown waits for every queued and in-flight ProcessResponse job to
+// finish, then closes the channel Notifications returns. Safe to call even
+// if ProcessResponse was never called.
+func (e *MemoryExtractor) Shutdown() {
+	e.queue.Shutdown()
+}
+
+// =============================================================================
+// Panic Recovery
+// =============================================================================
+
+// ErrExtractionPanicked is returned by ExtractFacts or ProcessResponse when
+// they recover from a panic partway through - a misbehaving JSON codec, a
+// nil-deref in a custom Store, or some future embedding client. Background
+// extraction runs every N turns inside the router process, on an
+// ExtractionQueue worker, so a panic here must degrade that one extraction
+// rather than crash the router.
+var ErrExtractionPanicked = errors.New("memory: extraction panicked")
+
+// RecoverFunc is invoked with the request context and the recovered panic
+// value whenever ExtractFacts or ProcessResponse panics underneath it. It
+// runs after the panic is already logged (with a stack trace) and counted
+// against the memory_extraction_panic_total metric - RecoverFunc is the
+// hook for anything further an operator wants to do with the raw value
+// (forward it to Sentry, attach it to an OTel span, ...). Its return value
+// is only logged, never propagated: ExtractFacts/ProcessResponse always
+// return ErrExtractionPanicked to the caller once a panic is recovered.
+type RecoverFunc func(ctx context.Context, recovered any) error
+
+// recoverExtractionPanic recovers a panic raised under function (identified
+// for the memory_extraction_panic_total{function} metric and the log
+// line), logs it with a stack trace, invokes recoverFn if set, and sets
+// *err to ErrExtractionPanicked. It's the outermost layer wrapping
+// ExtractFacts and ProcessResponse, in the spirit of the interceptor chains
+// gRPC servers use for panic recovery.
+func recoverExtractionPanic(ctx context.Context, function string, recoverFn RecoverFunc, err *error) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	metrics.RecordMemoryExtractionPanic(function)
+	logging.Errorf("memory extraction panic in %s: %v\n%s", function, recovered, debug.Stack())
+
+	if recoverFn != nil {
+		if handlerErr := recoverFn(ctx, recovered); handlerErr != nil {
+			logging.Errorf("memory extraction recover func for %s returned error: %v", function, handlerErr)
+		}
+	}
+
+	*err = ErrExtractionPanicked
+}
+
 // =============================================================================
 // LLM-Based Fact Extraction
 // =============================================================================
@@ -114,6 +208,17 @@ MEMORY TYPES:
   - "User prefers to debug by: adding logs first, then using breakpoints"
   NOTE: This is for USER's own processes, NOT assistant recommendations!
 
+"episodic" - Specific events the USER took part in, anchored to a time and/or place:
+  - "User visited Kyoto in April 2024"
+  - "User's team shipped v2.0 last Friday"
+  - "User interviewed at Acme Corp last week"
+  Set "occurred_at" to the event's date/time if it can be resolved from the
+  conversation (e.g. an explicit date, or "last Friday" relative to today),
+  and "location" if a place is mentioned. Leave either blank if not stated.
+  NOTE: A stable fact about the user with no event attached is "semantic",
+  not "episodic" - "episodic" is for something that HAPPENED, not something
+  that IS true.
+
 WHAT NOT TO EXTRACT:
 - Assistant suggestions ("You should try the seafood restaurant")
 - General knowledge ("Python is a programming language")
@@ -126,18 +231,207 @@ GOOD: [{"type": "semantic", "content": "User's project deadline is March 15th"}]
 GOOD: [{"type": "semantic", "content": "User cannot use AWS due to company policy"}]
 GOOD: [{"type": "semantic", "content": "User's tech stack is React, Go, and PostgreSQL"}]
 GOOD: [{"type": "procedural", "content": "User's code review process: check tests, review logic, then check style"}]
+GOOD: [{"type": "episodic", "content": "User visited Kyoto", "occurred_at": "2024-04-01", "location": "Kyoto"}]
+GOOD: [{"type": "episodic", "content": "User's team shipped v2.0", "occurred_at": "2026-07-24"}]
 BAD:  [{"type": "semantic", "content": "What is the user's budget?"}] (question form - use statement!)
 BAD:  [{"type": "procedural", "content": "To improve code: add more tests"}] (assistant advice, not user's process)
+BAD:  [{"type": "episodic", "content": "User likes visiting Japan"}] (no specific event - this is "semantic", a standing preference)
 
 Return JSON array. Empty array [] if nothing worth remembering about the USER.`
 
+// =============================================================================
+// Extraction Mode (structured output vs. free-text JSON)
+// =============================================================================
+
+// ExtractionMode selects how MemoryExtractor asks the LLM to return
+// extracted facts.
+type ExtractionMode string
+
+const (
+	// ExtractionModeText asks for free-text JSON and parses it with
+	// parseExtractedFacts/cleanJSONResponse. The original path; works
+	// against any OpenAI-compatible endpoint, but is fragile to a model
+	// wrapping its answer in prose or markdown fences.
+	ExtractionModeText ExtractionMode = "text"
+
+	// ExtractionModeJSONSchema uses response_format:
+	// {"type":"json_schema", "json_schema": {...}} to constrain the model's
+	// output to extractionFactsSchema. No markdown-stripping is needed -
+	// the response body is guaranteed to be the schema's JSON directly.
+	ExtractionModeJSONSchema ExtractionMode = "json_schema"
+
+	// ExtractionModeToolCall asks the model to call a single
+	// extract_memories function whose parameters match
+	// extractionFactsSchema, and reads facts back out of the tool call's
+	// arguments instead of message content.
+	ExtractionModeToolCall ExtractionMode = "tool_call"
+)
+
+// resolveExtractionMode returns the ExtractionMode to use for cfg: cfg.Mode
+// verbatim when it names one of the known modes, otherwise a choice driven
+// by cfg.Backend, falling back to an auto-detect heuristic based on
+// cfg.Model when Backend is also unset/unrecognized. An empty/unrecognized
+// cfg.Mode is treated as "auto" rather than an error, so existing configs
+// that predate this field keep working unchanged.
+func resolveExtractionMode(cfg *config.ExtractionConfig) ExtractionMode {
+	switch ExtractionMode(cfg.Mode) {
+	case ExtractionModeText, ExtractionModeJSONSchema, ExtractionModeToolCall:
+		return ExtractionMode(cfg.Mode)
+	}
+	switch resolveExtractionBackend(cfg) {
+	case extractionBackendRaw:
+		return ExtractionModeText
+	case extractionBackendOpenAI, extractionBackendVLLM, extractionBackendOllama:
+		return ExtractionModeJSONSchema
+	default:
+		return autoDetectExtractionMode(cfg.Model)
+	}
+}
+
+// Recognized config.ExtractionConfig.Backend values. Backend picks which
+// structured-output field sendExtractionRequest puts the schema in;
+// "raw" opts out of structured output entirely in favor of the original
+// best-effort text parsing.
+const (
+	extractionBackendOpenAI = "openai"
+	extractionBackendVLLM   = "vllm"
+	extractionBackendOllama = "ollama"
+	extractionBackendRaw    = "raw"
+)
+
+// resolveExtractionBackend returns cfg.Backend normalized to one of the
+// extractionBackend* constants, or "" if it's unset or not recognized.
+func resolveExtractionBackend(cfg *config.ExtractionConfig) string {
+	switch cfg.Backend {
+	case extractionBackendOpenAI, extractionBackendVLLM, extractionBackendOllama, extractionBackendRaw:
+		return cfg.Backend
+	default:
+		return ""
+	}
+}
+
+// autoDetectExtractionMode guesses which structured-output mode a model
+// family supports from its name. Unrecognized models fall back to
+// ExtractionModeText, the one path every OpenAI-compatible endpoint is
+// guaranteed to accept.
+func autoDetectExtractionMode(model string) ExtractionMode {
+	m := strings.ToLower(model)
+	switch {
+	case strings.Contains(m, "gpt-4o"), strings.Contains(m, "gpt-4-turbo"), strings.Contains(m, "gpt-5"):
+		return ExtractionModeJSONSchema
+	case strings.Contains(m, "gpt-4"), strings.Contains(m, "gpt-3.5-turbo"):
+		return ExtractionModeToolCall
+	default:
+		return ExtractionModeText
+	}
+}
+
+// extractionFactsSchema is the JSON Schema describing the facts array,
+// shared by ExtractionModeJSONSchema (as response_format.json_schema.schema)
+// and ExtractionModeToolCall (as the extract_memories tool's parameters).
+var extractionFactsSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"facts": map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"type": map[string]any{
+						"type": "string",
+						"enum": []string{"semantic", "procedural", "episodic"},
+					},
+					"content": map[string]any{
+						"type": "string",
+					},
+					"confidence": map[string]any{
+						"type": "number",
+					},
+					"occurred_at": map[string]any{
+						"type":        "string",
+						"description": "ISO-8601 timestamp the event occurred. Episodic facts only; omit for semantic/procedural facts.",
+					},
+					"location": map[string]any{
+						"type":        "string",
+						"description": "Where the event took place, if mentioned. Episodic facts only.",
+					},
+				},
+				"required": []string{"type", "content", "confidence"},
+			},
+		},
+	},
+	"required": []string{"facts"},
+}
+
+// extractionFactsToolName is the function name the model is asked to call
+// in ExtractionModeToolCall.
+const extractionFactsToolName = "extract_memories"
+
+// ExtractedFact is one memorable fact pulled out of a conversation by the
+// LLM, before it becomes a stored Memory. OccurredAt/Location are only ever
+// populated for Type == MemoryTypeEpisodic - see extractionSystemPrompt's
+// "episodic" section.
+type ExtractedFact struct {
+	Type       MemoryType `json:"type"`
+	Content    string     `json:"content"`
+	Confidence float64    `json:"confidence,omitempty"`
+	OccurredAt time.Time  `json:"occurred_at,omitempty"`
+	Location   string     `json:"location,omitempty"`
+}
+
+// extractedFactsEnvelope is the top-level shape both structured-output
+// modes return: a "facts" array conforming to extractionFactsSchema.
+type extractedFactsEnvelope struct {
+	Facts []ExtractedFact `json:"facts"`
+}
+
+// occurredAtLayouts are the date/time formats an LLM's "occurred_at" value
+// is tried against, in order, since models asked for a date reliably return
+// "2024-04-01" rather than a full RFC3339 timestamp.
+var occurredAtLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// UnmarshalJSON parses ExtractedFact's occurred_at as any of occurredAtLayouts
+// instead of requiring RFC3339 like time.Time's own unmarshaler does. A
+// value that matches none of them is logged and left zero rather than
+// failing the whole fact - OccurredAt is best-effort, not required.
+func (f *ExtractedFact) UnmarshalJSON(data []byte) error {
+	type alias ExtractedFact
+	aux := struct {
+		OccurredAt string `json:"occurred_at,omitempty"`
+		*alias
+	}{alias: (*alias)(f)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.OccurredAt == "" {
+		return nil
+	}
+	for _, layout := range occurredAtLayouts {
+		if t, err := time.Parse(layout, aux.OccurredAt); err == nil {
+			f.OccurredAt = t
+			return nil
+		}
+	}
+	logging.Warnf("Memory: could not parse occurred_at %q, leaving unset", aux.OccurredAt)
+	return nil
+}
+
 // ExtractFacts extracts memorable facts from a conversation using an LLM.
 // This is a pure extraction function - it does NOT store the facts.
 // Use ProcessResponse if you want extraction + storage with deduplication.
 //
 // Error handling:
-//   - Returns empty slice on any error (graceful degradation)
+//   - Returns empty slice on LLM/parsing errors (graceful degradation)
 //   - Logs warnings for debugging but doesn't fail the response
+//   - Exception: if ctx is canceled or past its deadline, returns ctx.Err()
+//     so callers can tell a shutdown apart from an LLM failure
 //
 // Example:
 //
@@ -147,7 +441,18 @@ Return JSON array. Empty array [] if nothing worth remembering about the USER.`
 //	}
 //	facts, err := extractor.ExtractFacts(ctx, messages)
 //	// facts = [{Type: "semantic", Content: "User's budget for Hawaii vacation is $10,000"}]
-func (e *MemoryExtractor) ExtractFacts(ctx context.Context, messages []Message) ([]ExtractedFact, error) {
+//
+// A panic anywhere underneath (a malformed LLM response, a future embedding
+// client, ...) is recovered rather than propagated; see recoverExtractionPanic.
+func (e *MemoryExtractor) ExtractFacts(ctx context.Context, messages []Message) (facts []ExtractedFact, err error) {
+	defer recoverExtractionPanic(ctx, "ExtractFacts", e.recoverFunc, &err)
+	return e.extractFactsUnsafe(ctx, messages)
+}
+
+// extractFactsUnsafe is ExtractFacts' body, split out so ExtractFacts can
+// wrap it in panic recovery without an extra level of indentation through
+// the whole function.
+func (e *MemoryExtractor) extractFactsUnsafe(ctx context.Context, messages []Message) ([]ExtractedFact, error) {
 	if e.config == nil || !e.config.Enabled || e.config.Endpoint == "" {
 		logging.Debugf("Memory: Fact extraction disabled or not configured")
 		return nil, nil
@@ -176,6 +481,15 @@ func (e *MemoryExtractor) ExtractFacts(ctx context.Context, messages []Message)
 	// Call LLM for extraction
 	facts, err := e.callLLMForExtraction(ctx, userPrompt)
 	if err != nil {
+		// callLLMForExtraction derives its own request timeout from ctx, so
+		// its error can't distinguish "LLM is slow/down" from "the caller
+		// gave up". Check the caller's ctx directly: if it's the one that's
+		// canceled or past its deadline, return ctx.Err() so callers can
+		// tell a shutdown/timeout apart from an LLM failure, instead of
+		// folding it into the same graceful-degradation nil below.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		logging.Warnf("Memory: Fact extraction failed: %v", err)
 		return nil, nil // Graceful degradation
 	}
@@ -191,20 +505,212 @@ func (e *MemoryExtractor) ExtractFacts(ctx context.Context, messages []Message)
 	return facts, nil
 }
 
-// callLLMForExtraction calls the configured LLM endpoint for fact extraction
+// defaultMaxRepairAttempts bounds callLLMForExtraction's repair loop when
+// cfg.MaxRepairAttempts is unset.
+const defaultMaxRepairAttempts = 2
+
+// transientLLMError marks an LLM call failure as likely to succeed if the
+// exact same request is retried unchanged - a 429/502/503/504 response or a
+// transport-level failure - as opposed to a malformed response body, which
+// needs a repair prompt rather than an identical retry. retryAfter carries
+// the server's requested wait (from a Retry-After header), if any; zero
+// means "use the usual exponential backoff".
+type transientLLMError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *transientLLMError) Error() string { return e.err.Error() }
+func (e *transientLLMError) Unwrap() error { return e.err }
+
+// structuredOutputUnsupportedError marks a 400 response to a structured-
+// output request (ResponseFormat/GuidedJSON/Format set) as the server
+// rejecting that field, rather than anything retrying the same shape of
+// request would fix. callLLMForExtraction treats this as a one-time signal
+// to downgrade to ExtractionModeText and retry with plain prompting instead.
+type structuredOutputUnsupportedError struct {
+	err error
+}
+
+func (e *structuredOutputUnsupportedError) Error() string { return e.err.Error() }
+func (e *structuredOutputUnsupportedError) Unwrap() error { return e.err }
+
+// isRetryableStatus reports whether an LLM HTTP response status is worth
+// retrying the exact same request for: rate limiting and the upstream's own
+// transient unavailability, as opposed to a client error that won't change
+// on retry.
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses a Retry-After header value, which per RFC 9110 is
+// either a number of seconds or an HTTP-date. Only the seconds form is
+// supported; an HTTP-date or empty/invalid value returns 0 so the caller
+// falls back to exponential backoff.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(strings.TrimSpace(header)); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return 0
+}
+
+// callLLMForExtraction calls the configured LLM endpoint for fact
+// extraction and parses the response according to resolveExtractionMode(e.config).
+// Two independent retry loops run against the same underlying request,
+// since each condition calls for a different fix:
+//
+//   - A transient failure (429/502/503/504 status, transport error) retries
+//     the exact same request up to cfg.RetryConfig.MaxRetries times,
+//     honoring a Retry-After header when the server sends one and falling
+//     back to exponential backoff otherwise - the model was never given a
+//     chance to produce better output, so there's nothing to repair.
+//   - A content failure (malformed JSON, or JSON missing required fields
+//     or naming an unknown fact type) retries up to cfg.MaxRepairAttempts
+//     times, sending a follow-up "repair" prompt that echoes the bad output
+//     and asks the model to re-emit strictly conforming JSON.
+//
+// A 400 response to a structured-output mode (ExtractionModeJSONSchema/
+// ExtractionModeToolCall) is treated as the server not supporting that
+// field: the mode is downgraded to ExtractionModeText once and the request
+// retried, rather than counting against either retry loop above.
+//
+// Facts from a structured mode (ExtractionModeJSONSchema/ExtractionModeToolCall)
+// are filtered by getConfidenceThresholdForExtraction before being returned;
+// ExtractionModeText facts carry no confidence score and are never filtered
+// this way.
 func (e *MemoryExtractor) callLLMForExtraction(ctx context.Context, userPrompt string) ([]ExtractedFact, error) {
-	// Build request
+	mode := resolveExtractionMode(e.config)
+	maxRepair := e.config.MaxRepairAttempts
+	if maxRepair <= 0 {
+		maxRepair = defaultMaxRepairAttempts
+	}
+	maxRetries := getRetryMaxAttemptsForExtraction(e.config)
+
+	messages := []llmChatMessage{
+		{Role: "system", Content: extractionSystemPrompt},
+		{Role: "user", Content: userPrompt},
+	}
+
+	var lastErr error
+	repairAttempt := 0
+	downgraded := false
+	for transientAttempt := 0; ; {
+		resp, err := e.sendExtractionRequest(ctx, mode, messages)
+		if err != nil {
+			var transient *transientLLMError
+			if errors.As(err, &transient) {
+				if transientAttempt < maxRetries {
+					metrics.RecordMemoryExtractionRetryAttempt()
+					logging.Warnf("Memory: LLM extraction request failed (%v), retrying (attempt %d/%d)", err, transientAttempt+1, maxRetries)
+					if !e.waitForTransientRetry(ctx, transientAttempt, transient.retryAfter) {
+						return nil, ctx.Err()
+					}
+					lastErr = err
+					transientAttempt++
+					continue
+				}
+				metrics.RecordMemoryExtractionRetryExhausted()
+				return nil, err
+			}
+
+			var unsupported *structuredOutputUnsupportedError
+			if errors.As(err, &unsupported) && !downgraded {
+				metrics.RecordMemoryExtractionStructuredOutputFallback()
+				logging.Warnf("Memory: LLM rejected structured-output field (%v), falling back to best-effort text parsing", err)
+				downgraded = true
+				mode = ExtractionModeText
+				continue
+			}
+			return nil, err
+		}
+
+		raw, rawFacts, parseErr := parseFactsForModeRaw(mode, resp.Choices[0].Message)
+		var facts []ExtractedFact
+		if parseErr == nil {
+			facts, parseErr = validateExtractedFacts(rawFacts)
+		}
+		if parseErr == nil {
+			if repairAttempt > 0 {
+				metrics.RecordMemoryExtractionRepairSuccess()
+			}
+			if mode != ExtractionModeText {
+				facts = filterByConfidence(facts, getConfidenceThresholdForExtraction(e.config))
+			}
+			return facts, nil
+		}
+
+		metrics.RecordMemoryExtractionParseFailure()
+		lastErr = parseErr
+
+		if repairAttempt >= maxRepair {
+			metrics.RecordMemoryExtractionGiveup()
+			return nil, fmt.Errorf("memory: extraction gave up after %d repair attempt(s): %w", maxRepair, lastErr)
+		}
+
+		logging.Warnf("Memory: LLM extraction response failed validation (%v), sending repair prompt (attempt %d/%d)", parseErr, repairAttempt+1, maxRepair)
+		if !e.waitForRetry(ctx, repairAttempt) {
+			return nil, ctx.Err()
+		}
+		repairAttempt++
+		messages = append(messages,
+			llmChatMessage{Role: "assistant", Content: raw},
+			llmChatMessage{Role: "user", Content: repairPrompt(parseErr)},
+		)
+	}
+}
+
+// sendExtractionRequest builds and sends one chat-completion request for
+// messages/mode, returning a *transientLLMError for failures a retry of the
+// same request might fix (429/502/503/504, transport errors) so callLLMForExtraction
+// can tell those apart from a response it should instead repair.
+func (e *MemoryExtractor) sendExtractionRequest(ctx context.Context, mode ExtractionMode, messages []llmChatMessage) (*llmChatResponse, error) {
 	reqBody := llmChatRequest{
-		Model: e.config.Model,
-		Messages: []llmChatMessage{
-			{Role: "system", Content: extractionSystemPrompt},
-			{Role: "user", Content: userPrompt},
-		},
+		Model:       e.config.Model,
+		Messages:    messages,
 		MaxTokens:   getMaxTokensForExtraction(e.config),
 		Temperature: getTemperatureForExtraction(e.config),
 		Stream:      false,
 	}
 
+	switch mode {
+	case ExtractionModeJSONSchema:
+		switch resolveExtractionBackend(e.config) {
+		case extractionBackendVLLM:
+			reqBody.GuidedJSON = extractionFactsSchema
+		case extractionBackendOllama:
+			reqBody.Format = extractionFactsSchema
+		default:
+			reqBody.ResponseFormat = &llmResponseFormat{
+				Type: "json_schema",
+				JSONSchema: &llmJSONSchemaSpec{
+					Name:   "extracted_memories",
+					Schema: extractionFactsSchema,
+					Strict: true,
+				},
+			}
+		}
+	case ExtractionModeToolCall:
+		reqBody.Tools = []llmTool{{
+			Type: "function",
+			Function: llmToolFunction{
+				Name:       extractionFactsToolName,
+				Parameters: extractionFactsSchema,
+			},
+		}}
+		reqBody.ToolChoice = map[string]any{
+			"type":     "function",
+			"function": map[string]string{"name": extractionFactsToolName},
+		}
+	}
+
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -226,10 +732,19 @@ func (e *MemoryExtractor) callLLMForExtraction(ctx context.Context, userPrompt s
 	// Send request (using reused client for connection pooling)
 	resp, err := e.client.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("LLM request failed: %w", err)
+		return nil, &transientLLMError{err: fmt.Errorf("LLM request failed: %w", err)}
 	}
 	defer resp.Body.Close()
 
+	if isRetryableStatus(resp.StatusCode) {
+		return nil, &transientLLMError{
+			err:        fmt.Errorf("LLM returned status %d", resp.StatusCode),
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	if resp.StatusCode == http.StatusBadRequest && mode != ExtractionModeText {
+		return nil, &structuredOutputUnsupportedError{err: fmt.Errorf("LLM returned status %d", resp.StatusCode)}
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("LLM returned status %d", resp.StatusCode)
 	}
@@ -244,9 +759,226 @@ func (e *MemoryExtractor) callLLMForExtraction(ctx context.Context, userPrompt s
 		return nil, fmt.Errorf("no choices in LLM response")
 	}
 
-	// Parse extracted facts from LLM response
-	content := llmResp.Choices[0].Message.Content
-	return parseExtractedFacts(content)
+	return &llmResp, nil
+}
+
+// waitForRetry sleeps for extractionRetryBackoff(attempt), returning false
+// without sleeping the full duration if ctx is canceled first.
+func (e *MemoryExtractor) waitForRetry(ctx context.Context, attempt int) bool {
+	timer := time.NewTimer(extractionRetryBackoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// extractionRetryBackoff returns the delay before retry attempt N (0-indexed):
+// 200ms, 400ms, 800ms, ..., capped at 5s.
+func extractionRetryBackoff(attempt int) time.Duration {
+	const base = 200 * time.Millisecond
+	const maxBackoff = 5 * time.Second
+	backoff := base << attempt
+	if backoff > maxBackoff || backoff <= 0 {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// defaultRetryMaxAttempts/InitialBackoff/MaxBackoff are used in place of
+// cfg.RetryConfig's fields when they're unset (zero value).
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = 500 * time.Millisecond
+	defaultRetryMaxBackoff     = 10 * time.Second
+)
+
+// getRetryMaxAttemptsForExtraction returns how many times callLLMForExtraction
+// retries a transient failure (network error, 429/502/503/504) around a
+// single LLM call, with default.
+func getRetryMaxAttemptsForExtraction(cfg *config.ExtractionConfig) int {
+	if cfg.RetryConfig.MaxRetries > 0 {
+		return cfg.RetryConfig.MaxRetries
+	}
+	return defaultRetryMaxAttempts
+}
+
+// waitForTransientRetry sleeps before retrying a transient LLM failure:
+// retryAfter (parsed from the server's Retry-After header) if the server
+// sent one, otherwise the usual exponential backoff for attempt (0-indexed).
+// Returns false without sleeping the full duration if ctx is canceled first.
+func (e *MemoryExtractor) waitForTransientRetry(ctx context.Context, attempt int, retryAfter time.Duration) bool {
+	backoff := retryAfter
+	if backoff <= 0 {
+		backoff = retryBackoffForExtraction(e.config, attempt)
+	}
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// retryBackoffForExtraction returns the exponential backoff before transient
+// retry attempt N (0-indexed), doubling from cfg.RetryConfig.InitialBackoffMs
+// up to cfg.RetryConfig.MaxBackoffMs (both with defaults when unset).
+func retryBackoffForExtraction(cfg *config.ExtractionConfig, attempt int) time.Duration {
+	initial := defaultRetryInitialBackoff
+	if cfg.RetryConfig.InitialBackoffMs > 0 {
+		initial = time.Duration(cfg.RetryConfig.InitialBackoffMs) * time.Millisecond
+	}
+	maxBackoff := defaultRetryMaxBackoff
+	if cfg.RetryConfig.MaxBackoffMs > 0 {
+		maxBackoff = time.Duration(cfg.RetryConfig.MaxBackoffMs) * time.Millisecond
+	}
+	backoff := initial << attempt
+	if backoff > maxBackoff || backoff <= 0 {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// repairPromptTemplate is the follow-up message sent when a response fails
+// parsing or schema validation: it echoes the problem that was found (the
+// bad output itself is sent as a preceding assistant-role message so the
+// model sees what it wrote) and asks for a strictly conforming re-emission.
+const repairPromptTemplate = `Your previous response could not be used: %v
+
+Re-emit ONLY a strictly valid JSON response matching the format you were given - no markdown fences, no commentary, every fact must include both "type" and "content", and "type" must be one of semantic, procedural, or episodic.`
+
+func repairPrompt(cause error) string {
+	return fmt.Sprintf(repairPromptTemplate, cause)
+}
+
+// parseFactsForMode extracts facts from an LLM response message according
+// to mode: ExtractionModeText parses message.Content as (possibly
+// markdown-wrapped) free-text JSON via parseExtractedFacts;
+// ExtractionModeJSONSchema parses message.Content directly, skipping the
+// markdown-stripping path entirely since the response is guaranteed to
+// already be the schema's JSON; ExtractionModeToolCall reads the
+// extract_memories tool call's arguments instead of message content.
+func parseFactsForMode(mode ExtractionMode, message llmChatResponseMessage) ([]ExtractedFact, error) {
+	switch mode {
+	case ExtractionModeJSONSchema:
+		var envelope extractedFactsEnvelope
+		if err := json.Unmarshal([]byte(message.Content), &envelope); err != nil {
+			return nil, fmt.Errorf("failed to parse json_schema response: %w", err)
+		}
+		return envelope.Facts, nil
+
+	case ExtractionModeToolCall:
+		for _, call := range message.ToolCalls {
+			if call.Function.Name != extractionFactsToolName {
+				continue
+			}
+			var envelope extractedFactsEnvelope
+			if err := json.Unmarshal([]byte(call.Function.Arguments), &envelope); err != nil {
+				return nil, fmt.Errorf("failed to parse tool call arguments: %w", err)
+			}
+			return envelope.Facts, nil
+		}
+		return nil, fmt.Errorf("no %s tool call in LLM response", extractionFactsToolName)
+
+	default:
+		return parseExtractedFacts(message.Content)
+	}
+}
+
+// parseFactsForModeRaw is parseFactsForMode's counterpart for the repair
+// loop: it returns the raw text the facts were parsed from (so a failed
+// validation can echo it back to the model as the prior assistant turn)
+// alongside the facts themselves, unfiltered by confidence and not yet
+// validated by validateExtractedFacts.
+func parseFactsForModeRaw(mode ExtractionMode, message llmChatResponseMessage) (raw string, facts []ExtractedFact, err error) {
+	switch mode {
+	case ExtractionModeJSONSchema:
+		raw = message.Content
+		var envelope extractedFactsEnvelope
+		if err := json.Unmarshal([]byte(raw), &envelope); err != nil {
+			return raw, nil, fmt.Errorf("failed to parse json_schema response: %w", err)
+		}
+		return raw, envelope.Facts, nil
+
+	case ExtractionModeToolCall:
+		for _, call := range message.ToolCalls {
+			if call.Function.Name != extractionFactsToolName {
+				continue
+			}
+			raw = call.Function.Arguments
+			var envelope extractedFactsEnvelope
+			if err := json.Unmarshal([]byte(raw), &envelope); err != nil {
+				return raw, nil, fmt.Errorf("failed to parse tool call arguments: %w", err)
+			}
+			return raw, envelope.Facts, nil
+		}
+		return "", nil, fmt.Errorf("no %s tool call in LLM response", extractionFactsToolName)
+
+	default:
+		raw = message.Content
+		content := cleanJSONResponse(strings.TrimSpace(raw))
+		if content == "" || content == "[]" {
+			return raw, nil, nil
+		}
+		var parsed []ExtractedFact
+		if err := json.Unmarshal([]byte(content), &parsed); err != nil {
+			return raw, nil, fmt.Errorf("failed to parse facts JSON: %w (content: %s)", err, truncateForLog(content, 100))
+		}
+		return raw, parsed, nil
+	}
+}
+
+// validateExtractedFacts drops entries with empty content or an unknown
+// type - the same per-entry leniency parseExtractedFacts has always applied,
+// since real models routinely get one fact in an otherwise-good batch
+// wrong. It only reports an error, for callLLMForExtraction's retry loop to
+// repair, when raw wasn't empty but every single entry failed: that's not a
+// fact worth dropping, it's a response the model needs to re-emit.
+func validateExtractedFacts(facts []ExtractedFact) ([]ExtractedFact, error) {
+	valid := make([]ExtractedFact, 0, len(facts))
+	for _, fact := range facts {
+		if strings.TrimSpace(fact.Content) == "" {
+			continue
+		}
+		normalizedType := normalizeMemoryType(string(fact.Type))
+		if normalizedType == "" {
+			logging.Warnf("Memory: Skipping fact with invalid type: %s", fact.Type)
+			continue
+		}
+		valid = append(valid, ExtractedFact{
+			Type:       normalizedType,
+			Content:    strings.TrimSpace(fact.Content),
+			Confidence: fact.Confidence,
+			OccurredAt: fact.OccurredAt,
+			Location:   fact.Location,
+		})
+	}
+	if len(facts) > 0 && len(valid) == 0 {
+		return nil, fmt.Errorf("all %d extracted facts failed validation (empty content or unknown type)", len(facts))
+	}
+	return valid, nil
+}
+
+// filterByConfidence drops facts whose Confidence is below threshold,
+// keeping the original order of the survivors.
+func filterByConfidence(facts []ExtractedFact, threshold float64) []ExtractedFact {
+	if threshold <= 0 {
+		return facts
+	}
+	kept := make([]ExtractedFact, 0, len(facts))
+	for _, fact := range facts {
+		if fact.Confidence < threshold {
+			logging.Debugf("Memory: dropping low-confidence fact (confidence=%.2f < %.2f): %s",
+				fact.Confidence, threshold, truncateForLog(fact.Content, 50))
+			continue
+		}
+		kept = append(kept, fact)
+	}
+	return kept
 }
 
 // =============================================================================
@@ -254,13 +986,38 @@ func (e *MemoryExtractor) callLLMForExtraction(ctx context.Context, userPrompt s
 // =============================================================================
 
 // ProcessResponse extracts and stores memories from conversation history.
-// It runs extraction every N turns (batchSize) to avoid excessive LLM calls.
-// This method combines extraction (using ExtractFacts) + storage with deduplication.
+// It tracks turn count every call and, every N turns (batchSize), submits
+// the recent batch to an ExtractionQueue instead of running extraction
+// inline - ProcessResponse itself returns as soon as the job is queued,
+// well before the LLM call it triggers completes. Call Notifications to
+// observe a queued batch's outcome (facts extracted and stored, or an
+// error) once a worker gets to it.
+//
+// A panic anywhere underneath (a malformed LLM response, a nil-deref in a
+// custom Store, ...) is recovered rather than propagated; see
+// recoverExtractionPanic. Since the extraction work itself now runs on a
+// queue worker rather than under this call, that recovery only covers the
+// turn-tracking and batch-selection done synchronously here - a panic
+// inside the queued job is recovered by processExtractionJob instead and
+// reported via Notifications, not this return value.
 func (e *MemoryExtractor) ProcessResponse(
 	ctx context.Context,
 	sessionID string,
 	userID string,
 	history []Message,
+) (err error) {
+	defer recoverExtractionPanic(ctx, "ProcessResponse", e.recoverFunc, &err)
+	return e.processResponseUnsafe(ctx, sessionID, userID, history)
+}
+
+// processResponseUnsafe is ProcessResponse's body, split out so
+// ProcessResponse can wrap it in panic recovery without an extra level of
+// indentation through the whole function.
+func (e *MemoryExtractor) processResponseUnsafe(
+	ctx context.Context,
+	sessionID string,
+	userID string,
+	history []Message,
 ) error {
 	// TODO: Remove demo logging after POC
 	logging.Infof("╔══════════════════════════════════════════════════════════════════╗")
@@ -298,9 +1055,11 @@ func (e *MemoryExtractor) ProcessResponse(
 	logging.Infof("Memory extraction: turnCount=%d, batchSize=%d, shouldExtract=%v",
 		turnCount, batchSize, turnCount%batchSize == 0)
 
-	// Only extract every N turns
-	if turnCount%batchSize != 0 {
-		logging.Infof("Memory extraction: SKIPPED - not batch turn (turn %d, batch every %d)", turnCount, batchSize)
+	reason, shouldExtract := e.scheduleExtraction(ctx, userID, history, turnCount, batchSize)
+	if reason != "" {
+		metrics.RecordExtractionTriggerReason(string(reason))
+	}
+	if !shouldExtract {
 		return nil
 	}
 
@@ -311,27 +1070,56 @@ func (e *MemoryExtractor) ProcessResponse(
 	}
 	batch := history[batchStart:]
 
-	// Use ExtractFacts for extraction
-	extracted, err := e.ExtractFacts(ctx, batch)
+	// Hand the batch off to the ExtractionQueue and return immediately -
+	// the LLM call and the store writes it triggers no longer run on this
+	// request path. e.processExtractionJob does the work a worker runs;
+	// its outcome is published on Notifications, not returned here.
+	e.queue.Submit(ExtractionJob{
+		SessionID: sessionID,
+		UserID:    userID,
+		History:   batch,
+		Turn:      turnCount,
+	})
+
+	return nil
+}
+
+// processExtractionJob is the ExtractionProcessor an ExtractionQueue worker
+// runs for one job: it's the extraction-and-store tail ProcessResponse used
+// to run inline before extraction moved onto a queue. A panic anywhere
+// underneath is recovered rather than crashing the worker goroutine,
+// mirroring ExtractFacts/ProcessResponse's own recovery.
+func (e *MemoryExtractor) processExtractionJob(ctx context.Context, job ExtractionJob) (facts []ExtractedFact, err error) {
+	defer recoverExtractionPanic(ctx, "processExtractionJob", e.recoverFunc, &err)
+
+	extracted, err := e.ExtractFacts(ctx, job.History)
 	if err != nil {
 		logging.Warnf("Memory extraction failed: %v", err)
-		return err // Return error but don't block response
+		return nil, err
 	}
 
 	if len(extracted) == 0 {
 		logging.Debugf("Memory extraction: no facts extracted from batch")
-		return nil
+		return nil, nil
+	}
+
+	// ExtractFacts can take long enough (LLM round-trip) that ctx is
+	// canceled or past its deadline by the time we're about to persist what
+	// it found. Check before writing so a shutdown mid-extraction doesn't
+	// race a store write against a context that's already done.
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	// Store with deduplication
 	for _, fact := range extracted {
-		if err := e.storeWithDeduplication(ctx, userID, fact); err != nil {
+		if err := e.storeWithDeduplication(ctx, job.UserID, fact); err != nil {
 			logging.Warnf("Failed to store memory with deduplication: %v", err)
 			// Continue with other facts even if one fails
 		}
 	}
 
-	return nil
+	return extracted, nil
 }
 
 // storeWithDeduplication stores a fact with deduplication logic.
@@ -351,7 +1139,7 @@ func (e *MemoryExtractor) storeWithDeduplication(
 	logging.Infof("╚══════════════════════════════════════════════════════════════════╝")
 
 	// Check for similar memories using deduplication logic
-	result := CheckDeduplication(ctx, e.store, userID, fact.Content, fact.Type, e.dedupConfig)
+	result := CheckDeduplication(ctx, e.store, userID, fact.Content, fact.Type, fact.OccurredAt, e.dedupConfig)
 
 	// TODO: Remove demo logging after POC
 	logging.Infof("Deduplication result: action=%s, similarity=%.3f, existingID=%v",
@@ -399,6 +1187,8 @@ func (e *MemoryExtractor) createNewMemory(
 		ID:         generateMemoryID(),
 		Type:       fact.Type,
 		Content:    fact.Content,
+		OccurredAt: fact.OccurredAt,
+		Location:   fact.Location,
 		UserID:     userID,
 		Source:     "conversation",
 		CreatedAt:  time.Now(),
@@ -454,8 +1244,11 @@ func parseExtractedFacts(content string) ([]ExtractedFact, error) {
 		}
 
 		validFacts = append(validFacts, ExtractedFact{
-			Type:    normalizedType,
-			Content: strings.TrimSpace(fact.Content),
+			Type:       normalizedType,
+			Content:    strings.TrimSpace(fact.Content),
+			Confidence: fact.Confidence,
+			OccurredAt: fact.OccurredAt,
+			Location:   fact.Location,
 		})
 	}
 
@@ -536,6 +1329,20 @@ func getTemperatureForExtraction(cfg *config.ExtractionConfig) float64 {
 	return 0.1 // default - low temperature for consistent extraction
 }
 
+// defaultConfidenceThreshold filters out low-confidence facts returned by a
+// structured extraction mode when ExtractionConfig.ConfidenceThreshold is
+// unset.
+const defaultConfidenceThreshold = 0.5
+
+// getConfidenceThresholdForExtraction returns the confidence threshold used
+// by filterByConfidence, with default.
+func getConfidenceThresholdForExtraction(cfg *config.ExtractionConfig) float64 {
+	if cfg.ConfidenceThreshold > 0 {
+		return cfg.ConfidenceThreshold
+	}
+	return defaultConfidenceThreshold
+}
+
 // =============================================================================
 // LLM Client Types (shared with req_filter_memory.go)
 // =============================================================================
@@ -547,6 +1354,22 @@ type llmChatRequest struct {
 	MaxTokens   int              `json:"max_tokens,omitempty"`
 	Temperature float64          `json:"temperature,omitempty"`
 	Stream      bool             `json:"stream"`
+
+	// ResponseFormat is OpenAI's (and most OpenAI-compatible servers')
+	// structured-output field.
+	ResponseFormat *llmResponseFormat `json:"response_format,omitempty"`
+
+	// GuidedJSON is vLLM's structured-output extension: the raw JSON Schema
+	// the server constrains sampling against, set instead of ResponseFormat
+	// when config.ExtractionConfig.Backend == "vllm".
+	GuidedJSON map[string]any `json:"guided_json,omitempty"`
+
+	// Format is Ollama's structured-output field: the raw JSON Schema, set
+	// instead of ResponseFormat when Backend == "ollama".
+	Format map[string]any `json:"format,omitempty"`
+
+	Tools      []llmTool `json:"tools,omitempty"`
+	ToolChoice any       `json:"tool_choice,omitempty"`
 }
 
 type llmChatMessage struct {
@@ -554,15 +1377,48 @@ type llmChatMessage struct {
 	Content string `json:"content"`
 }
 
+// llmResponseFormat requests structured output via response_format:
+// {"type":"json_schema", ...} (ExtractionModeJSONSchema).
+type llmResponseFormat struct {
+	Type       string             `json:"type"`
+	JSONSchema *llmJSONSchemaSpec `json:"json_schema,omitempty"`
+}
+
+type llmJSONSchemaSpec struct {
+	Name   string         `json:"name"`
+	Schema map[string]any `json:"schema"`
+	Strict bool           `json:"strict"`
+}
+
+// llmTool describes an OpenAI-style function tool (ExtractionModeToolCall).
+type llmTool struct {
+	Type     string          `json:"type"`
+	Function llmToolFunction `json:"function"`
+}
+
+type llmToolFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
 // llmChatResponse represents an OpenAI-compatible chat response
 type llmChatResponse struct {
 	Choices []struct {
-		Message struct {
-			Content string `json:"content"`
-		} `json:"message"`
+		Message llmChatResponseMessage `json:"message"`
 	} `json:"choices"`
 }
 
+type llmChatResponseMessage struct {
+	Content   string `json:"content"`
+	ToolCalls []struct {
+		Function struct {
+			Name      string `json:"name"`
+			Arguments string `json:"arguments"`
+		} `json:"function"`
+	} `json:"tool_calls"`
+}
+
 // =============================================================================
 // Utility Functions
 // =============================================================================
@@ -0,0 +1,243 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
+)
+
+func TestResolveExtractionMode_ExplicitModeWins(t *testing.T) {
+	cfg := &config.ExtractionConfig{Mode: "tool_call", Model: "gpt-4o"}
+	assert.Equal(t, ExtractionModeToolCall, resolveExtractionMode(cfg))
+}
+
+func TestResolveExtractionMode_UnrecognizedFallsBackToAutoDetect(t *testing.T) {
+	cfg := &config.ExtractionConfig{Mode: "bogus", Model: "gpt-4o"}
+	assert.Equal(t, ExtractionModeJSONSchema, resolveExtractionMode(cfg))
+}
+
+func TestResolveExtractionMode_BackendSelectsJSONSchema(t *testing.T) {
+	for _, backend := range []string{"openai", "vllm", "ollama"} {
+		cfg := &config.ExtractionConfig{Backend: backend, Model: "llama-3-8b-instruct"}
+		assert.Equal(t, ExtractionModeJSONSchema, resolveExtractionMode(cfg), "backend=%s", backend)
+	}
+}
+
+func TestResolveExtractionMode_BackendRawForcesText(t *testing.T) {
+	cfg := &config.ExtractionConfig{Backend: "raw", Model: "gpt-4o"}
+	assert.Equal(t, ExtractionModeText, resolveExtractionMode(cfg))
+}
+
+func TestResolveExtractionMode_UnrecognizedBackendFallsBackToAutoDetect(t *testing.T) {
+	cfg := &config.ExtractionConfig{Backend: "bogus", Model: "gpt-4o"}
+	assert.Equal(t, ExtractionModeJSONSchema, resolveExtractionMode(cfg))
+}
+
+func TestAutoDetectExtractionMode(t *testing.T) {
+	tests := []struct {
+		model string
+		want  ExtractionMode
+	}{
+		{"gpt-4o", ExtractionModeJSONSchema},
+		{"gpt-4-turbo", ExtractionModeJSONSchema},
+		{"gpt-4", ExtractionModeToolCall},
+		{"gpt-3.5-turbo", ExtractionModeToolCall},
+		{"llama-3-8b-instruct", ExtractionModeText},
+		{"", ExtractionModeText},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, autoDetectExtractionMode(tt.model), "model=%s", tt.model)
+	}
+}
+
+func TestFilterByConfidence(t *testing.T) {
+	facts := []ExtractedFact{
+		{Type: MemoryTypeSemantic, Content: "high", Confidence: 0.9},
+		{Type: MemoryTypeSemantic, Content: "low", Confidence: 0.1},
+	}
+	kept := filterByConfidence(facts, 0.5)
+	require.Len(t, kept, 1)
+	assert.Equal(t, "high", kept[0].Content)
+}
+
+func TestFilterByConfidence_ZeroThresholdKeepsAll(t *testing.T) {
+	facts := []ExtractedFact{
+		{Type: MemoryTypeSemantic, Content: "low", Confidence: 0.01},
+	}
+	assert.Len(t, filterByConfidence(facts, 0), 1)
+}
+
+func TestParseFactsForMode_ToolCall(t *testing.T) {
+	args, err := json.Marshal(extractedFactsEnvelope{
+		Facts: []ExtractedFact{{Type: MemoryTypeSemantic, Content: "User likes Go", Confidence: 0.8}},
+	})
+	require.NoError(t, err)
+
+	raw, err := json.Marshal(map[string]any{
+		"tool_calls": []map[string]any{
+			{"function": map[string]string{"name": extractionFactsToolName, "arguments": string(args)}},
+		},
+	})
+	require.NoError(t, err)
+	var message llmChatResponseMessage
+	require.NoError(t, json.Unmarshal(raw, &message))
+
+	facts, err := parseFactsForMode(ExtractionModeToolCall, message)
+	require.NoError(t, err)
+	require.Len(t, facts, 1)
+	assert.Equal(t, "User likes Go", facts[0].Content)
+}
+
+func TestParseFactsForMode_ToolCall_MissingToolErrors(t *testing.T) {
+	_, err := parseFactsForMode(ExtractionModeToolCall, llmChatResponseMessage{})
+	assert.Error(t, err)
+}
+
+func TestParseFactsForMode_JSONSchema(t *testing.T) {
+	content, err := json.Marshal(extractedFactsEnvelope{
+		Facts: []ExtractedFact{{Type: MemoryTypeProcedural, Content: "User deploys via CI", Confidence: 0.95}},
+	})
+	require.NoError(t, err)
+
+	facts, err := parseFactsForMode(ExtractionModeJSONSchema, llmChatResponseMessage{Content: string(content)})
+	require.NoError(t, err)
+	require.Len(t, facts, 1)
+	assert.Equal(t, "User deploys via CI", facts[0].Content)
+}
+
+func TestExtractFacts_JSONSchemaMode_SkipsMarkdownStrippingAndFiltersLowConfidence(t *testing.T) {
+	envelope := extractedFactsEnvelope{
+		Facts: []ExtractedFact{
+			{Type: MemoryTypeSemantic, Content: "User's budget is $10,000", Confidence: 0.9},
+			{Type: MemoryTypeSemantic, Content: "maybe likes hiking", Confidence: 0.2},
+		},
+	}
+	content, err := json.Marshal(envelope)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llmChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.NotNil(t, req.ResponseFormat, "json_schema mode must set response_format")
+		assert.Equal(t, "json_schema", req.ResponseFormat.Type)
+
+		resp := map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]string{"content": string(content)}},
+			},
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer server.Close()
+
+	extractor := NewMemoryExtractor(&config.ExtractionConfig{
+		Enabled:  true,
+		Endpoint: server.URL,
+		Model:    "gpt-4o",
+	})
+
+	facts, err := extractor.ExtractFacts(context.Background(), []Message{{Role: RoleUser, Content: "hi"}})
+	require.NoError(t, err)
+	require.Len(t, facts, 1, "the low-confidence fact should have been filtered out")
+	assert.Equal(t, "User's budget is $10,000", facts[0].Content)
+}
+
+func TestSendExtractionRequest_VLLMBackendUsesGuidedJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llmChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Nil(t, req.ResponseFormat, "vllm backend must not set response_format")
+		assert.NotNil(t, req.GuidedJSON, "vllm backend must set guided_json")
+
+		resp := map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]string{"content": `{"facts":[{"type":"semantic","content":"User likes Go"}]}`}},
+			},
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer server.Close()
+
+	extractor := NewMemoryExtractor(&config.ExtractionConfig{
+		Enabled:  true,
+		Endpoint: server.URL,
+		Model:    "custom-model",
+		Backend:  "vllm",
+	})
+
+	facts, err := extractor.ExtractFacts(context.Background(), []Message{{Role: RoleUser, Content: "hi"}})
+	require.NoError(t, err)
+	require.Len(t, facts, 1)
+}
+
+func TestSendExtractionRequest_OllamaBackendUsesFormat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llmChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Nil(t, req.ResponseFormat, "ollama backend must not set response_format")
+		assert.NotNil(t, req.Format, "ollama backend must set format")
+
+		resp := map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]string{"content": `{"facts":[{"type":"semantic","content":"User likes Go"}]}`}},
+			},
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer server.Close()
+
+	extractor := NewMemoryExtractor(&config.ExtractionConfig{
+		Enabled:  true,
+		Endpoint: server.URL,
+		Model:    "custom-model",
+		Backend:  "ollama",
+	})
+
+	facts, err := extractor.ExtractFacts(context.Background(), []Message{{Role: RoleUser, Content: "hi"}})
+	require.NoError(t, err)
+	require.Len(t, facts, 1)
+}
+
+func TestExtractFacts_FallsBackToTextWhenServerRejectsStructuredOutput(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req llmChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		if req.ResponseFormat != nil {
+			// First attempt uses structured output - reject it like a server
+			// that doesn't understand response_format would.
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// Retry came in as plain-text mode - succeed with best-effort JSON.
+		resp := map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]string{"content": `[{"type": "semantic", "content": "User likes Go"}]`}},
+			},
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer server.Close()
+
+	extractor := NewMemoryExtractor(&config.ExtractionConfig{
+		Enabled:  true,
+		Endpoint: server.URL,
+		Model:    "gpt-4o",
+	})
+
+	facts, err := extractor.ExtractFacts(context.Background(), []Message{{Role: RoleUser, Content: "hi"}})
+	require.NoError(t, err)
+	require.Len(t, facts, 1)
+	assert.Equal(t, "User likes Go", facts[0].Content)
+	assert.Equal(t, 2, calls, "expected one rejected structured-output attempt plus one text-mode retry")
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
+)
+
+// panickingRoundTripper simulates a misbehaving LLM client: every round
+// trip panics instead of returning an error.
+type panickingRoundTripper struct{}
+
+func (panickingRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	panic("boom")
+}
+
+func TestExtractFacts_RecoversFromPanic(t *testing.T) {
+	extractor := NewMemoryExtractor(&config.ExtractionConfig{Enabled: true, Endpoint: "http://unused"})
+	extractor.client.Transport = panickingRoundTripper{}
+
+	facts, err := extractor.ExtractFacts(context.Background(), []Message{{Role: RoleUser, Content: "hi"}})
+	require.Error(t, err)
+	assert.Equal(t, ErrExtractionPanicked, err)
+	assert.Nil(t, facts)
+}
+
+func TestExtractFacts_RecoverFuncInvoked(t *testing.T) {
+	extractor := NewMemoryExtractor(&config.ExtractionConfig{Enabled: true, Endpoint: "http://unused"})
+	extractor.client.Transport = panickingRoundTripper{}
+
+	var captured any
+	extractor.SetRecoverFunc(func(ctx context.Context, recovered any) error {
+		captured = recovered
+		return nil
+	})
+
+	_, err := extractor.ExtractFacts(context.Background(), []Message{{Role: RoleUser, Content: "hi"}})
+	require.Error(t, err)
+	assert.Equal(t, "boom", captured)
+}
+
+func TestProcessResponse_RecoversFromPanic(t *testing.T) {
+	store := NewInMemoryStore()
+	extractor := NewMemoryExtractorWithStore(&config.ExtractionConfig{
+		Enabled:   true,
+		Endpoint:  "http://unused",
+		BatchSize: 1,
+	}, store)
+	extractor.client.Transport = panickingRoundTripper{}
+
+	err := extractor.ProcessResponse(context.Background(), "session1", "user1", []Message{
+		{Role: RoleUser, Content: "hi"},
+	})
+	require.Error(t, err)
+	assert.Equal(t, ErrExtractionPanicked, err)
+}
+
+func TestExtractFacts_NoPanicDoesNotInvokeRecoverFunc(t *testing.T) {
+	mockResponse := `[]`
+	server := createMockLLMServer(t, mockResponse)
+	defer server.Close()
+
+	extractor := NewMemoryExtractor(&config.ExtractionConfig{Enabled: true, Endpoint: server.URL})
+
+	called := false
+	extractor.SetRecoverFunc(func(ctx context.Context, recovered any) error {
+		called = true
+		return nil
+	})
+
+	_, err := extractor.ExtractFacts(context.Background(), []Message{{Role: RoleUser, Content: "hi"}})
+	require.NoError(t, err)
+	assert.False(t, called)
+}
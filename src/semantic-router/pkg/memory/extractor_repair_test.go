@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
+)
+
+// createSequenceLLMServer returns a server that replies with responses[i] on
+// its i-th request (clamped to the last entry once exhausted), so a test can
+// script a sequence like bad->bad->good. It also reports how many requests
+// it received and echoes each request's message count, so a test can confirm
+// the repair loop actually grows the conversation rather than resending the
+// original request unchanged.
+func createSequenceLLMServer(t *testing.T, responses []string) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llmChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		i := atomic.AddInt32(&calls, 1) - 1
+		content := responses[len(responses)-1]
+		if int(i) < len(responses) {
+			content = responses[i]
+		}
+
+		resp := map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": content}},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	return server, &calls
+}
+
+func TestCallLLMForExtraction_RepairLoop(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []string
+		maxRepair int
+		wantErr   bool
+		wantFacts int
+		wantCalls int32
+	}{
+		{
+			name: "bad then good succeeds on first repair",
+			responses: []string{
+				"not valid json",
+				`[{"type": "semantic", "content": "User likes tea"}]`,
+			},
+			maxRepair: 2,
+			wantFacts: 1,
+			wantCalls: 2,
+		},
+		{
+			name: "bad bad then good succeeds on second repair",
+			responses: []string{
+				"not valid json",
+				`{"facts": "also not an array"}`,
+				`[{"type": "semantic", "content": "User likes tea"}]`,
+			},
+			maxRepair: 2,
+			wantFacts: 1,
+			wantCalls: 3,
+		},
+		{
+			name: "unknown fact type on every attempt gives up",
+			responses: []string{
+				`[{"type": "bogus", "content": "x"}]`,
+				`[{"type": "bogus", "content": "x"}]`,
+				`[{"type": "bogus", "content": "x"}]`,
+			},
+			maxRepair: 2,
+			wantErr:   true,
+			wantCalls: 3, // original request + maxRepair repair attempts, then give up
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := createSequenceLLMServer(t, tt.responses)
+			defer server.Close()
+
+			extractor := NewMemoryExtractor(&config.ExtractionConfig{
+				Enabled:           true,
+				Endpoint:          server.URL,
+				Model:             "test-model",
+				MaxRepairAttempts: tt.maxRepair,
+			})
+
+			facts, err := extractor.ExtractFacts(context.Background(), []Message{
+				{Role: "user", Content: "I really like tea"},
+			})
+
+			if tt.wantErr {
+				// ExtractFacts degrades gracefully rather than surfacing the
+				// LLM error to callers, so the giveup must not leak into the
+				// store as facts or corrupt it with a partial result.
+				require.NoError(t, err)
+				assert.Nil(t, facts)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, facts, tt.wantFacts)
+			}
+			assert.Equal(t, tt.wantCalls, atomic.LoadInt32(calls))
+		})
+	}
+}
+
+// TestCallLLMForExtraction_RepairPromptEchoesBadOutput confirms the repair
+// request is a grown conversation - system/user/assistant(bad)/user(repair) -
+// rather than the original request resent unchanged, and that the bad
+// output is included verbatim so the model can see what it got wrong.
+func TestCallLLMForExtraction_RepairPromptEchoesBadOutput(t *testing.T) {
+	const badOutput = "not valid json at all"
+	var requests []llmChatRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llmChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+
+		content := badOutput
+		if len(requests) > 1 {
+			content = `[{"type": "semantic", "content": "User likes tea"}]`
+		}
+		resp := map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": content}},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	extractor := NewMemoryExtractor(&config.ExtractionConfig{
+		Enabled:           true,
+		Endpoint:          server.URL,
+		Model:             "test-model",
+		MaxRepairAttempts: 1,
+	})
+
+	facts, err := extractor.ExtractFacts(context.Background(), []Message{
+		{Role: "user", Content: "I really like tea"},
+	})
+	require.NoError(t, err)
+	require.Len(t, facts, 1)
+
+	require.Len(t, requests, 2)
+	require.Len(t, requests[0].Messages, 2, "first request is just system+user")
+
+	repair := requests[1].Messages
+	require.Len(t, repair, 4, "repair request appends the bad assistant turn and a repair prompt")
+	assert.Equal(t, "assistant", repair[2].Role)
+	assert.Equal(t, badOutput, repair[2].Content)
+	assert.Equal(t, "user", repair[3].Role)
+	assert.Contains(t, repair[3].Content, badOutput)
+}
@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/config"
+)
+
+// createStatusThenSuccessServer fails the first `failures` requests with
+// status, optionally setting a Retry-After header, then replies with a valid
+// extraction on every subsequent request.
+func createStatusThenSuccessServer(t *testing.T, failures int, status int, retryAfter string) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := atomic.AddInt32(&calls, 1) - 1
+		if int(i) < failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		resp := map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"content": `[{"type": "semantic", "content": "User likes tea"}]`}},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	return server, &calls
+}
+
+func TestCallLLMForExtraction_RetriesTransientStatuses(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			server, calls := createStatusThenSuccessServer(t, 2, status, "")
+			defer server.Close()
+
+			extractor := NewMemoryExtractor(&config.ExtractionConfig{
+				Enabled:  true,
+				Endpoint: server.URL,
+				Model:    "test-model",
+				RetryConfig: config.RetryConfig{
+					MaxRetries:       3,
+					InitialBackoffMs: 1,
+					MaxBackoffMs:     2,
+				},
+			})
+
+			facts, err := extractor.ExtractFacts(context.Background(), []Message{
+				{Role: "user", Content: "I really like tea"},
+			})
+			require.NoError(t, err)
+			require.Len(t, facts, 1)
+			assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+		})
+	}
+}
+
+func TestCallLLMForExtraction_GivesUpAfterMaxRetries(t *testing.T) {
+	server, calls := createStatusThenSuccessServer(t, 100, http.StatusServiceUnavailable, "")
+	defer server.Close()
+
+	extractor := NewMemoryExtractor(&config.ExtractionConfig{
+		Enabled:  true,
+		Endpoint: server.URL,
+		Model:    "test-model",
+		RetryConfig: config.RetryConfig{
+			MaxRetries:       2,
+			InitialBackoffMs: 1,
+			MaxBackoffMs:     2,
+		},
+	})
+
+	facts, err := extractor.ExtractFacts(context.Background(), []Message{
+		{Role: "user", Content: "I really like tea"},
+	})
+	// ExtractFacts degrades gracefully rather than surfacing the LLM error.
+	require.NoError(t, err)
+	assert.Nil(t, facts)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls), "original request + MaxRetries retries")
+}
+
+func TestCallLLMForExtraction_HonorsRetryAfterHeader(t *testing.T) {
+	server, calls := createStatusThenSuccessServer(t, 1, http.StatusTooManyRequests, "1")
+	defer server.Close()
+
+	extractor := NewMemoryExtractor(&config.ExtractionConfig{
+		Enabled:  true,
+		Endpoint: server.URL,
+		Model:    "test-model",
+		RetryConfig: config.RetryConfig{
+			MaxRetries:       1,
+			InitialBackoffMs: 1,
+			MaxBackoffMs:     1,
+		},
+	})
+
+	start := time.Now()
+	facts, err := extractor.ExtractFacts(context.Background(), []Message{
+		{Role: "user", Content: "I really like tea"},
+	})
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	require.Len(t, facts, 1)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+	assert.GreaterOrEqual(t, elapsed, 1*time.Second, "should have waited the Retry-After duration, not the 1ms configured backoff")
+}
+
+func TestCallLLMForExtraction_ContextCancellationStopsRetries(t *testing.T) {
+	server, calls := createStatusThenSuccessServer(t, 100, http.StatusServiceUnavailable, "")
+	defer server.Close()
+
+	extractor := NewMemoryExtractor(&config.ExtractionConfig{
+		Enabled:  true,
+		Endpoint: server.URL,
+		Model:    "test-model",
+		RetryConfig: config.RetryConfig{
+			MaxRetries:       5,
+			InitialBackoffMs: 50,
+			MaxBackoffMs:     100,
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := extractor.ExtractFacts(ctx, []Message{
+		{Role: "user", Content: "I really like tea"},
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, atomic.LoadInt32(calls), int32(6), "canceled context should cut the retry loop short")
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -177,6 +178,86 @@ func TestExtractFacts_LLMError(t *testing.T) {
 	assert.Nil(t, facts, "should return nil on LLM failure")
 }
 
+// TestExtractFacts_ContextCancellation covers both ways a caller's ctx can
+// end mid-extraction: an explicit cancel() and a deadline that elapses
+// while the LLM handler is still blocked. Both must unblock the call
+// promptly and surface ctx.Err(), not the graceful-degradation nil/nil
+// TestExtractFacts_LLMError exercises for an ordinary LLM failure.
+func TestExtractFacts_ContextCancellation(t *testing.T) {
+	tests := []struct {
+		name    string
+		makeCtx func() (context.Context, context.CancelFunc)
+		wantErr error
+	}{
+		{
+			name: "explicit cancel",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "deadline exceeded",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// blockUntilCanceled holds the handler open until the request's
+			// context is done, so the test proves the outbound HTTP call is
+			// actually aborted rather than completing before ctx ends.
+			blockUntilCanceled := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-blockUntilCanceled:
+				}
+			}))
+			defer server.Close()
+			defer close(blockUntilCanceled)
+
+			extractor := NewMemoryExtractor(&config.ExtractionConfig{
+				Enabled:  true,
+				Endpoint: server.URL,
+				Model:    "test-model",
+			})
+
+			ctx, cancel := tt.makeCtx()
+			defer cancel()
+
+			if tt.name == "explicit cancel" {
+				go func() {
+					time.Sleep(20 * time.Millisecond)
+					cancel()
+				}()
+			}
+
+			messages := []Message{{Role: "user", Content: "My budget is $10,000"}}
+
+			done := make(chan struct{})
+			var facts []ExtractedFact
+			var err error
+			go func() {
+				facts, err = extractor.ExtractFacts(ctx, messages)
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				t.Fatal("ExtractFacts did not unblock after context was done")
+			}
+
+			assert.Nil(t, facts)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
 func TestExtractFacts_InvalidJSON(t *testing.T) {
 	// LLM returns invalid JSON
 	server := createMockLLMServer(t, "not valid json at all")
@@ -280,6 +361,51 @@ func TestExtractFacts_AllMemoryTypes(t *testing.T) {
 	assert.Equal(t, MemoryTypeEpisodic, facts[2].Type)
 }
 
+func TestExtractFacts_EpisodicOccurredAtAndLocation(t *testing.T) {
+	mockResponse := `[
+		{"type": "episodic", "content": "User visited Kyoto", "occurred_at": "2024-04-01", "location": "Kyoto"}
+	]`
+	server := createMockLLMServer(t, mockResponse)
+	defer server.Close()
+
+	extractor := NewMemoryExtractor(&config.ExtractionConfig{
+		Enabled:  true,
+		Endpoint: server.URL,
+		Model:    "test-model",
+	})
+
+	facts, err := extractor.ExtractFacts(context.Background(), []Message{
+		{Role: "user", Content: "I visited Kyoto in April 2024"},
+	})
+	require.NoError(t, err)
+	require.Len(t, facts, 1)
+
+	assert.Equal(t, MemoryTypeEpisodic, facts[0].Type)
+	assert.Equal(t, "Kyoto", facts[0].Location)
+	assert.True(t, facts[0].OccurredAt.Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestExtractFacts_UnparseableOccurredAtLeftZero(t *testing.T) {
+	mockResponse := `[
+		{"type": "episodic", "content": "User visited Kyoto", "occurred_at": "sometime last spring"}
+	]`
+	server := createMockLLMServer(t, mockResponse)
+	defer server.Close()
+
+	extractor := NewMemoryExtractor(&config.ExtractionConfig{
+		Enabled:  true,
+		Endpoint: server.URL,
+		Model:    "test-model",
+	})
+
+	facts, err := extractor.ExtractFacts(context.Background(), []Message{
+		{Role: "user", Content: "I visited Kyoto last spring"},
+	})
+	require.NoError(t, err)
+	require.Len(t, facts, 1)
+	assert.True(t, facts[0].OccurredAt.IsZero())
+}
+
 // =============================================================================
 // parseExtractedFacts Tests
 // =============================================================================
@@ -576,6 +702,7 @@ func TestProcessResponse_DefaultBatchSize(t *testing.T) {
 	// Turn 10: should extract (10 % 10 == 0)
 	err := extractor.ProcessResponse(ctx, "session1", "user1", history)
 	require.NoError(t, err)
+	waitForExtraction(t, extractor)
 
 	// Verify extraction happened
 	results, err := store.Retrieve(ctx, RetrieveOptions{
@@ -615,6 +742,7 @@ func TestProcessResponse_DefaultBatchSizeBoundary(t *testing.T) {
 	// Turn 10: SHOULD extract (10 % 10 == 0)
 	err = extractor.ProcessResponse(ctx, "session_boundary", "user1", history)
 	require.NoError(t, err)
+	waitForExtraction(t, extractor)
 
 	// Verify extraction happened - use the exact content from mock response
 	results, _ = store.Retrieve(ctx, RetrieveOptions{
@@ -694,6 +822,7 @@ func TestProcessResponse_CustomBatchSize(t *testing.T) {
 	// Turn 5: should extract (5 % 5 == 0)
 	err := extractor.ProcessResponse(ctx, "session_custom", "user1", history)
 	require.NoError(t, err)
+	waitForExtraction(t, extractor)
 
 	results, _ := store.Retrieve(ctx, RetrieveOptions{Query: "window", UserID: "user1"})
 	assert.NotEmpty(t, results, "Turn 5 should extract with batch size 5")
@@ -729,6 +858,7 @@ func TestProcessResponse_BatchSizeZeroUsesDefault(t *testing.T) {
 
 	// Turn 10: should extract
 	extractor.ProcessResponse(ctx, "session_zero", "user1", history)
+	waitForExtraction(t, extractor)
 
 	results, _ = store.Retrieve(ctx, RetrieveOptions{Query: "coffee", UserID: "user1"})
 	assert.NotEmpty(t, results, "Turn 10 should extract with default batch size")
@@ -758,6 +888,7 @@ func TestProcessResponse_BatchSizeNegativeUsesDefault(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		extractor.ProcessResponse(ctx, "session_negative", "user1", history)
 	}
+	waitForExtraction(t, extractor)
 
 	results, _ := store.Retrieve(ctx, RetrieveOptions{Query: "tea", UserID: "user1"})
 	assert.NotEmpty(t, results, "Should extract with default batch size when negative")
@@ -791,6 +922,7 @@ func TestProcessResponse_MultipleSessionsIndependent(t *testing.T) {
 	for i := 0; i < 5; i++ {
 		extractor.ProcessResponse(ctx, "session2", "user1", history)
 	}
+	waitForExtraction(t, extractor)
 
 	// Session 1: should not have extracted
 	results1, _ := store.Retrieve(ctx, RetrieveOptions{Query: "Session-specific", UserID: "user1"})
@@ -943,6 +1075,7 @@ func TestProcessResponse_TurnCountTracking(t *testing.T) {
 
 	// Turn 3: SHOULD extract (3 % 3 == 0)
 	extractor.ProcessResponse(ctx, "session_turns", "user1", history)
+	waitForExtraction(t, extractor)
 	results, _ = store.Retrieve(ctx, RetrieveOptions{Query: "Turn count", UserID: "user1"})
 	if len(results) > extractionCount {
 		extractionCount = len(results)
@@ -960,6 +1093,7 @@ func TestProcessResponse_TurnCountTracking(t *testing.T) {
 
 	// Turn 6: SHOULD extract (6 % 3 == 0)
 	extractor.ProcessResponse(ctx, "session_turns", "user1", history)
+	waitForExtraction(t, extractor)
 	results, _ = store.Retrieve(ctx, RetrieveOptions{Query: "Turn count", UserID: "user1", Threshold: 0.3})
 	finalCount := len(results)
 
@@ -1096,6 +1230,12 @@ func TestProcessResponse_MultipleBatchSizes(t *testing.T) {
 			for turn := 1; turn <= maxTurn; turn++ {
 				extractor.ProcessResponse(ctx, sessionID, "user1", history)
 
+				// This turn triggers a batch: wait for the worker to finish
+				// it before checking the store, instead of racing it.
+				if contains(tc.extractAt, turn) {
+					waitForExtraction(t, extractor)
+				}
+
 				// Check if extraction happened
 				results, _ := store.Retrieve(ctx, RetrieveOptions{
 					Query:  "Batch size",
@@ -1131,6 +1271,23 @@ func contains(slice []int, val int) bool {
 // Helper Functions
 // =============================================================================
 
+// waitForExtraction blocks until extractor publishes its next
+// ExtractionEvent (ProcessResponse's extraction and storage now run off a
+// worker queue, asynchronously from ProcessResponse's own return), failing
+// the test if none arrives within a few seconds. Tests use it instead of
+// sleeping or polling the store immediately after a ProcessResponse call
+// that's expected to trigger extraction.
+func waitForExtraction(t *testing.T, extractor *MemoryExtractor) ExtractionEvent {
+	t.Helper()
+	select {
+	case ev := <-extractor.Notifications():
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for extraction notification")
+		return ExtractionEvent{}
+	}
+}
+
 // createMockLLMServer creates a test server that returns the specified response
 func createMockLLMServer(t *testing.T, factsJSON string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
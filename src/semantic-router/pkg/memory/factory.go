@@ -3,6 +3,7 @@
 package memory
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
@@ -12,17 +13,58 @@ import (
 type StoreType string
 
 const (
-	StoreTypeMemory StoreType = "memory"
-	StoreTypeMilvus StoreType = "milvus"
+	StoreTypeMemory   StoreType = "memory"
+	StoreTypeMilvus   StoreType = "milvus"
+	StoreTypeSQLite   StoreType = "sqlite"
+	StoreTypePostgres StoreType = "postgres"
+	StoreTypeMongo    StoreType = "mongo"
+	StoreTypeRedis    StoreType = "redis"
+	StoreTypePgVector StoreType = "pgvector"
+	StoreTypeWeaviate StoreType = "weaviate"
 )
 
 // StoreConfig configures the memory store
 type StoreConfig struct {
-	// Type is the backend type: "memory" or "milvus"
+	// Type is the backend type: "memory", "milvus", "sqlite", "postgres",
+	// "mongo", "redis", "pgvector", or "weaviate"
 	Type StoreType `yaml:"type" json:"type"`
 
 	// Milvus configuration (when Type is "milvus")
 	Milvus *MilvusStoreConfig `yaml:"milvus,omitempty" json:"milvus,omitempty"`
+
+	// SQLite configuration (when Type is "sqlite")
+	SQLite *SQLiteStoreConfig `yaml:"sqlite,omitempty" json:"sqlite,omitempty"`
+
+	// Postgres configuration (when Type is "postgres" or "pgvector" - both
+	// share PostgresStore, which already provisions the pgvector extension)
+	Postgres *PostgresStoreConfig `yaml:"postgres,omitempty" json:"postgres,omitempty"`
+
+	// Mongo configuration (when Type is "mongo")
+	Mongo *MongoStoreConfig `yaml:"mongo,omitempty" json:"mongo,omitempty"`
+
+	// Redis configuration (when Type is "redis")
+	Redis *RedisStoreConfig `yaml:"redis,omitempty" json:"redis,omitempty"`
+
+	// Weaviate configuration (when Type is "weaviate")
+	Weaviate *WeaviateStoreConfig `yaml:"weaviate,omitempty" json:"weaviate,omitempty"`
+
+	// Quota bounds per-user memory growth (memory count, total bytes,
+	// writes/sec). Enforced by pkg/extproc's MemoryFilter, not by the Store
+	// itself - see QuotaConfig. Zero value disables all quota enforcement.
+	Quota QuotaConfig `yaml:"quota,omitempty" json:"quota,omitempty"`
+
+	// Consolidation configures the background MemoryConsolidator that
+	// decays, evicts, and merges memories in this store over time. Zero
+	// value leaves Interval at 0, so no background worker runs unless the
+	// caller wires one up (see NewMemoryConsolidator, DefaultConsolidationConfig).
+	Consolidation ConsolidationConfig `yaml:"consolidation,omitempty" json:"consolidation,omitempty"`
+
+	// Encryption, if set, wraps the backend selected by Type in an
+	// encryptedStore that seals every Memory's Content at rest with a
+	// Cipher built from this config (see NewEnvelopeEncryptor). Embedding is
+	// left in plaintext - see encryptedStore's doc comment for why. Nil
+	// disables encryption entirely.
+	Encryption *EnvelopeEncryptionConfig `yaml:"encryption,omitempty" json:"encryption,omitempty"`
 }
 
 // DefaultStoreConfig returns sensible defaults for development
@@ -32,12 +74,29 @@ func DefaultStoreConfig() *StoreConfig {
 	}
 }
 
-// NewStore creates a memory store based on configuration
-func NewStore(config *StoreConfig) (Store, error) {
+// NewStore creates a memory store based on configuration. Backends
+// registered via RegisterStoreBackend (e.g. sqlite, postgres) are tried
+// before the built-in switch, so a registered factory can override any
+// StoreType, including the built-in ones.
+func NewStore(ctx context.Context, config *StoreConfig) (Store, error) {
 	if config == nil {
 		config = DefaultStoreConfig()
 	}
 
+	store, err := newRawStore(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return maybeEncrypt(store, config)
+}
+
+// newRawStore builds the backend selected by config.Type, with no
+// encryption wrapping applied yet.
+func newRawStore(ctx context.Context, config *StoreConfig) (Store, error) {
+	if factory, ok := storeFactories[config.Type]; ok {
+		return factory(ctx, config)
+	}
+
 	switch config.Type {
 	case StoreTypeMemory, "":
 		logging.Infof("MemoryStore: using in-memory backend")
@@ -55,6 +114,20 @@ func NewStore(config *StoreConfig) (Store, error) {
 	}
 }
 
+// maybeEncrypt wraps store in an encryptedStore when config.Encryption is
+// set, otherwise returns store unchanged.
+func maybeEncrypt(store Store, config *StoreConfig) (Store, error) {
+	if config.Encryption == nil {
+		return store, nil
+	}
+	cipher, err := NewEnvelopeEncryptor(config.Encryption)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build encryption cipher: %w", err)
+	}
+	logging.Infof("MemoryStore: wrapping %s backend with content encryption at rest", config.Type)
+	return NewEncryptedStore(store, cipher), nil
+}
+
 // NewMemoryStore is a convenience function for creating an in-memory store
 func NewMemoryStore() Store {
 	return NewInMemoryStore()
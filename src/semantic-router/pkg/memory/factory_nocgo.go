@@ -3,6 +3,7 @@
 package memory
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -10,8 +11,14 @@ import (
 type StoreType string
 
 const (
-	StoreTypeMemory StoreType = "memory"
-	StoreTypeMilvus StoreType = "milvus"
+	StoreTypeMemory   StoreType = "memory"
+	StoreTypeMilvus   StoreType = "milvus"
+	StoreTypeSQLite   StoreType = "sqlite"
+	StoreTypePostgres StoreType = "postgres"
+	StoreTypeMongo    StoreType = "mongo"
+	StoreTypeRedis    StoreType = "redis"
+	StoreTypePgVector StoreType = "pgvector"
+	StoreTypeWeaviate StoreType = "weaviate"
 )
 
 // MilvusStoreConfig is a stub for non-CGO builds
@@ -28,8 +35,27 @@ type MilvusStoreConfig struct {
 
 // StoreConfig configures the memory store
 type StoreConfig struct {
-	Type   StoreType          `yaml:"type" json:"type"`
-	Milvus *MilvusStoreConfig `yaml:"milvus,omitempty" json:"milvus,omitempty"`
+	Type     StoreType            `yaml:"type" json:"type"`
+	Milvus   *MilvusStoreConfig   `yaml:"milvus,omitempty" json:"milvus,omitempty"`
+	SQLite   *SQLiteStoreConfig   `yaml:"sqlite,omitempty" json:"sqlite,omitempty"`
+	Postgres *PostgresStoreConfig `yaml:"postgres,omitempty" json:"postgres,omitempty"`
+	Mongo    *MongoStoreConfig    `yaml:"mongo,omitempty" json:"mongo,omitempty"`
+	Redis    *RedisStoreConfig    `yaml:"redis,omitempty" json:"redis,omitempty"`
+	Weaviate *WeaviateStoreConfig `yaml:"weaviate,omitempty" json:"weaviate,omitempty"`
+	Quota    QuotaConfig          `yaml:"quota,omitempty" json:"quota,omitempty"`
+
+	// Consolidation configures the background MemoryConsolidator that
+	// decays, evicts, and merges memories in this store over time. Zero
+	// value leaves Interval at 0, so no background worker runs unless the
+	// caller wires one up (see NewMemoryConsolidator, DefaultConsolidationConfig).
+	Consolidation ConsolidationConfig `yaml:"consolidation,omitempty" json:"consolidation,omitempty"`
+
+	// Encryption, if set, wraps the backend selected by Type in an
+	// encryptedStore that seals every Memory's Content at rest with a
+	// Cipher built from this config (see NewEnvelopeEncryptor). Embedding is
+	// left in plaintext - see encryptedStore's doc comment for why. Nil
+	// disables encryption entirely.
+	Encryption *EnvelopeEncryptionConfig `yaml:"encryption,omitempty" json:"encryption,omitempty"`
 }
 
 // DefaultStoreConfig returns sensible defaults for development
@@ -39,13 +65,28 @@ func DefaultStoreConfig() *StoreConfig {
 	}
 }
 
-// NewStore creates a memory store based on configuration
-// Note: Milvus is not available without CGO
-func NewStore(config *StoreConfig) (Store, error) {
+// NewStore creates a memory store based on configuration.
+// Note: Milvus is not available without CGO. Backends registered via
+// RegisterStoreBackend (sqlite, postgres) don't need CGO and work here too.
+func NewStore(ctx context.Context, config *StoreConfig) (Store, error) {
 	if config == nil {
 		config = DefaultStoreConfig()
 	}
 
+	store, err := newRawStore(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return maybeEncrypt(store, config)
+}
+
+// newRawStore builds the backend selected by config.Type, with no
+// encryption wrapping applied yet.
+func newRawStore(ctx context.Context, config *StoreConfig) (Store, error) {
+	if factory, ok := storeFactories[config.Type]; ok {
+		return factory(ctx, config)
+	}
+
 	switch config.Type {
 	case StoreTypeMemory, "":
 		return NewInMemoryStore(), nil
@@ -58,6 +99,19 @@ func NewStore(config *StoreConfig) (Store, error) {
 	}
 }
 
+// maybeEncrypt wraps store in an encryptedStore when config.Encryption is
+// set, otherwise returns store unchanged.
+func maybeEncrypt(store Store, config *StoreConfig) (Store, error) {
+	if config.Encryption == nil {
+		return store, nil
+	}
+	cipher, err := NewEnvelopeEncryptor(config.Encryption)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build encryption cipher: %w", err)
+	}
+	return NewEncryptedStore(store, cipher), nil
+}
+
 // NewMemoryStore is a convenience function for creating an in-memory store
 func NewMemoryStore() Store {
 	return NewInMemoryStore()
@@ -0,0 +1,150 @@
+// Package grpcstore lets an external process back memory.Store over gRPC,
+// so operators can plug in a vector-DB backend (Weaviate, Qdrant, pgvector,
+// LanceDB, an in-house service, ...) without recompiling semantic-router -
+// the same pattern LocalAI uses for its local-store gRPC vector backend.
+// memory.proto defines the wire contract; Client and Server implement it
+// today by hand over jsonCodec (codec.go) rather than generated protobuf
+// code, so memory.proto is the spec a future protoc-gen-go pass would
+// generate from, not something this package's Go code depends on yet.
+package grpcstore
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+)
+
+// Client implements memory.Store by calling a MemoryStore gRPC server,
+// letting an external process - a vector-DB service not in-tree, or any
+// store implementation an operator doesn't want to recompile semantic-router
+// to add - back memory retrieval over the network. See server.go for the
+// matching server-side adapter.
+type Client struct {
+	conn *grpc.ClientConn
+	cc   grpc.ClientConnInterface
+}
+
+// Dial connects to a MemoryStore server at target, negotiating the jsonCodec
+// (codec.go) on every call so the peer doesn't need protoc-generated code.
+func Dial(target string, opts ...grpc.DialOption) (*Client, error) {
+	opts = append(opts, grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName)))
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("grpcstore: dial %s: %w", target, err)
+	}
+	return &Client{conn: conn, cc: conn}, nil
+}
+
+// NewClient wraps an already-established connection (e.g. one dialed
+// against a bufconn listener in tests) as a memory.Store.
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{cc: cc}
+}
+
+func (c *Client) invoke(ctx context.Context, method string, in, out any) error {
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/"+method, in, out); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return memory.ErrNotFound
+		}
+		return fmt.Errorf("grpcstore: %s: %w", method, err)
+	}
+	return nil
+}
+
+// Store saves mem via the remote server, which assigns its ID and
+// timestamps; the response is copied back into mem in place, matching
+// every other Store implementation's contract.
+func (c *Client) Store(ctx context.Context, mem *memory.Memory) error {
+	wm, err := toWireMemory(mem)
+	if err != nil {
+		return err
+	}
+	resp := new(storeResponse)
+	if err := c.invoke(ctx, "Store", &storeRequest{Memory: wm}, resp); err != nil {
+		return err
+	}
+	got, err := fromWireMemory(resp.Memory)
+	if err != nil {
+		return err
+	}
+	*mem = *got
+	return nil
+}
+
+func (c *Client) Retrieve(ctx context.Context, opts memory.RetrieveOptions) ([]*memory.RetrieveResult, error) {
+	resp := new(retrieveResponse)
+	if err := c.invoke(ctx, "Retrieve", &retrieveRequest{Options: toWireOptions(opts)}, resp); err != nil {
+		return nil, err
+	}
+	results := make([]*memory.RetrieveResult, len(resp.Results))
+	for i, r := range resp.Results {
+		mem, err := fromWireMemory(r.Memory)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = &memory.RetrieveResult{Memory: mem, Relevance: r.Relevance, RawScore: r.RawScore}
+	}
+	return results, nil
+}
+
+func (c *Client) Get(ctx context.Context, id string) (*memory.Memory, error) {
+	resp := new(getResponse)
+	if err := c.invoke(ctx, "Get", &getRequest{ID: id}, resp); err != nil {
+		return nil, err
+	}
+	return fromWireMemory(resp.Memory)
+}
+
+func (c *Client) Update(ctx context.Context, id string, mem *memory.Memory) error {
+	wm, err := toWireMemory(mem)
+	if err != nil {
+		return err
+	}
+	return c.invoke(ctx, "Update", &updateRequest{ID: id, Memory: wm}, new(updateResponse))
+}
+
+func (c *Client) Forget(ctx context.Context, id string) error {
+	return c.invoke(ctx, "Forget", &forgetRequest{ID: id}, new(forgetResponse))
+}
+
+func (c *Client) ForgetByScope(ctx context.Context, scope memory.MemoryScope) error {
+	return c.invoke(ctx, "ForgetByScope", &forgetByScopeRequest{Scope: toWireScope(scope)}, new(forgetByScopeResponse))
+}
+
+func (c *Client) UpdateScore(ctx context.Context, id string, score float64) error {
+	return c.invoke(ctx, "UpdateScore", &updateScoreRequest{ID: id, Score: score}, new(updateScoreResponse))
+}
+
+// BulkDelete calls Forget once per ID, skipping ones the server reports as
+// already gone - memory.proto has no batch-delete RPC, so this is the same
+// tradeoff MongoStore's predecessor made before it got a native $in delete.
+func (c *Client) BulkDelete(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		if err := c.Forget(ctx, id); err != nil && err != memory.ErrNotFound {
+			return fmt.Errorf("grpcstore: bulk delete %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// IsEnabled reports whether the underlying connection is usable. Unlike
+// MongoStore's ping, this only checks transport state: memory.proto has no
+// health RPC, so a server whose handlers are wired to a dead backing store
+// still reports enabled here.
+func (c *Client) IsEnabled() bool {
+	return c.cc != nil
+}
+
+// Close tears down the client connection, if Dial (rather than NewClient)
+// established it.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
@@ -0,0 +1,35 @@
+package grpcstore
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// codecName is the grpc content-subtype ("application/grpc+json") this
+// package's client and server negotiate, so that neither side needs
+// protoc-gen-go's generated marshalers for the messages in memory.proto.
+const codecName = "json"
+
+// jsonCodec implements encoding.Codec by delegating to encoding/json. It's
+// registered globally under codecName so any grpc.ClientConn that sets
+// grpc.CallContentSubtype(codecName) - see Dial in client.go - and any
+// grpc.Server, which picks the codec from the incoming content-type header,
+// use it automatically.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
@@ -0,0 +1,161 @@
+package grpcstore
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+)
+
+// newTestClient starts a MemoryStore server backed by a fresh
+// memory.InMemoryStore on an in-process bufconn listener and returns a
+// connected Client, so these tests exercise the real wire path (jsonCodec
+// marshal/unmarshal, grpc request dispatch) without a TCP port.
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	RegisterMemoryStoreServer(srv, NewServer(memory.NewInMemoryStore()))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName)),
+	)
+	if err != nil {
+		t.Fatalf("dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return NewClient(conn)
+}
+
+func TestClient_StoreGetForget(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	mem := &memory.Memory{Type: memory.MemoryTypeSemantic, Content: "User prefers vegetarian food", UserID: "user_1"}
+	if err := client.Store(ctx, mem); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if mem.ID == "" {
+		t.Fatal("Store did not assign an ID")
+	}
+
+	got, err := client.Get(ctx, mem.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Content != mem.Content {
+		t.Fatalf("Get content = %q, want %q", got.Content, mem.Content)
+	}
+
+	if _, err := client.Get(ctx, "mem_does_not_exist"); err != memory.ErrNotFound {
+		t.Fatalf("Get missing ID: got %v, want ErrNotFound", err)
+	}
+
+	if err := client.Forget(ctx, mem.ID); err != nil {
+		t.Fatalf("Forget: %v", err)
+	}
+	if _, err := client.Get(ctx, mem.ID); err != memory.ErrNotFound {
+		t.Fatalf("Get after Forget: got %v, want ErrNotFound", err)
+	}
+}
+
+// TestClient_UserIsolation checks that Retrieve never leaks a memory across
+// the user_id boundary, the same guarantee store_conformance_test.go checks
+// for the in-tree backends.
+func TestClient_UserIsolation(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	for _, mem := range []*memory.Memory{
+		{Type: memory.MemoryTypeSemantic, Content: "User one likes hiking", UserID: "user_1"},
+		{Type: memory.MemoryTypeSemantic, Content: "User two likes hiking", UserID: "user_2"},
+	} {
+		if err := client.Store(ctx, mem); err != nil {
+			t.Fatalf("Store: %v", err)
+		}
+	}
+
+	results, err := client.Retrieve(ctx, memory.RetrieveOptions{UserID: "user_1", Query: "hiking"})
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	for _, r := range results {
+		if r.Memory.UserID != "user_1" {
+			t.Fatalf("Retrieve leaked memory for %s into user_1's results: %+v", r.Memory.UserID, r.Memory)
+		}
+	}
+}
+
+// TestClient_TypeIsolation checks that RetrieveOptions.Types filters
+// correctly across the wire.
+func TestClient_TypeIsolation(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	for _, mem := range []*memory.Memory{
+		{Type: memory.MemoryTypeSemantic, Content: "User prefers tea over coffee", UserID: "user_1"},
+		{Type: memory.MemoryTypeEpisodic, Content: "User mentioned tea during onboarding", UserID: "user_1"},
+	} {
+		if err := client.Store(ctx, mem); err != nil {
+			t.Fatalf("Store: %v", err)
+		}
+	}
+
+	results, err := client.Retrieve(ctx, memory.RetrieveOptions{
+		UserID: "user_1",
+		Query:  "tea",
+		Types:  []memory.MemoryType{memory.MemoryTypeSemantic},
+	})
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	for _, r := range results {
+		if r.Memory.Type != memory.MemoryTypeSemantic {
+			t.Fatalf("Retrieve with Types filter returned a %s memory: %+v", r.Memory.Type, r.Memory)
+		}
+	}
+}
+
+// TestClient_ForgetByScope checks that scoped bulk deletion over the wire
+// matches the semantics store_conformance_test.go checks in-process.
+func TestClient_ForgetByScope(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	for _, mem := range []*memory.Memory{
+		{Type: memory.MemoryTypeSemantic, Content: "a", UserID: "user_1", ProjectID: "proj_a"},
+		{Type: memory.MemoryTypeEpisodic, Content: "b", UserID: "user_1", ProjectID: "proj_b"},
+		{Type: memory.MemoryTypeSemantic, Content: "c", UserID: "user_2", ProjectID: "proj_a"},
+	} {
+		if err := client.Store(ctx, mem); err != nil {
+			t.Fatalf("Store: %v", err)
+		}
+	}
+
+	if err := client.ForgetByScope(ctx, memory.MemoryScope{UserID: "user_1", ProjectID: "proj_a"}); err != nil {
+		t.Fatalf("ForgetByScope: %v", err)
+	}
+
+	remaining, err := client.Retrieve(ctx, memory.RetrieveOptions{UserID: "user_1", Query: "a b"})
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	for _, r := range remaining {
+		if r.Memory.ProjectID == "proj_a" {
+			t.Fatalf("ForgetByScope left a memory it should have removed: %+v", r.Memory)
+		}
+	}
+}
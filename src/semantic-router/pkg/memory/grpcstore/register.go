@@ -0,0 +1,25 @@
+package grpcstore
+
+import (
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+)
+
+// init registers the "grpc" DSN scheme with the memory package's URL
+// registry (memory.Register), mirroring how mongodb and milvus claim their
+// own schemes. Wiring up a grpc-backed store therefore only needs a blank
+// import of this package, e.g.:
+//
+//	import _ "github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory/grpcstore"
+//	store, err := memory.New("grpc://vector-store.internal:9000")
+func init() {
+	memory.Register("grpc", func(logf memory.Logf, dsn string) (memory.Store, error) {
+		target := strings.TrimPrefix(dsn, "grpc://")
+		logf("memory: using grpcstore backend (target=%s)", target)
+		return Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	})
+}
@@ -0,0 +1,103 @@
+package grpcstore
+
+import (
+	"context"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+)
+
+// Server adapts any memory.Store to the MemoryStore gRPC service, so an
+// operator can expose a store that already lives in this process (or one
+// they've written themselves) to an external caller over the network. Pair
+// it with RegisterMemoryStoreServer and a grpc.Server:
+//
+//	srv := grpc.NewServer()
+//	grpcstore.RegisterMemoryStoreServer(srv, grpcstore.NewServer(backingStore))
+//	srv.Serve(listener)
+//
+// This is the reference harness; operators running their own vector-DB
+// service (Weaviate, Qdrant, pgvector, LanceDB, ...) implement the same
+// seven RPCs directly instead of wrapping a memory.Store.
+type Server struct {
+	store memory.Store
+}
+
+// NewServer wraps store for serving over MemoryStore.
+func NewServer(store memory.Store) *Server {
+	return &Server{store: store}
+}
+
+func (s *Server) Store(ctx context.Context, req *storeRequest) (*storeResponse, error) {
+	mem, err := fromWireMemory(req.Memory)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Store(ctx, mem); err != nil {
+		return nil, err
+	}
+	wm, err := toWireMemory(mem)
+	if err != nil {
+		return nil, err
+	}
+	return &storeResponse{Memory: wm}, nil
+}
+
+func (s *Server) Retrieve(ctx context.Context, req *retrieveRequest) (*retrieveResponse, error) {
+	results, err := s.store.Retrieve(ctx, fromWireOptions(req.Options))
+	if err != nil {
+		return nil, err
+	}
+	wireResults := make([]*wireRetrieveResult, len(results))
+	for i, r := range results {
+		wm, err := toWireMemory(r.Memory)
+		if err != nil {
+			return nil, err
+		}
+		wireResults[i] = &wireRetrieveResult{Memory: wm, Relevance: r.Relevance, RawScore: r.RawScore}
+	}
+	return &retrieveResponse{Results: wireResults}, nil
+}
+
+func (s *Server) Get(ctx context.Context, req *getRequest) (*getResponse, error) {
+	mem, err := s.store.Get(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	wm, err := toWireMemory(mem)
+	if err != nil {
+		return nil, err
+	}
+	return &getResponse{Memory: wm}, nil
+}
+
+func (s *Server) Update(ctx context.Context, req *updateRequest) (*updateResponse, error) {
+	mem, err := fromWireMemory(req.Memory)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Update(ctx, req.ID, mem); err != nil {
+		return nil, err
+	}
+	return &updateResponse{}, nil
+}
+
+func (s *Server) Forget(ctx context.Context, req *forgetRequest) (*forgetResponse, error) {
+	if err := s.store.Forget(ctx, req.ID); err != nil {
+		return nil, err
+	}
+	return &forgetResponse{}, nil
+}
+
+func (s *Server) ForgetByScope(ctx context.Context, req *forgetByScopeRequest) (*forgetByScopeResponse, error) {
+	if err := s.store.ForgetByScope(ctx, fromWireScope(req.Scope)); err != nil {
+		return nil, err
+	}
+	return &forgetByScopeResponse{}, nil
+}
+
+func (s *Server) UpdateScore(ctx context.Context, req *updateScoreRequest) (*updateScoreResponse, error) {
+	if err := s.store.UpdateScore(ctx, req.ID, req.Score); err != nil {
+		return nil, err
+	}
+	return &updateScoreResponse{}, nil
+}
@@ -0,0 +1,150 @@
+package grpcstore
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// serviceName matches the "MemoryStore" service in memory.proto.
+const serviceName = "grpcstore.MemoryStore"
+
+// memoryStoreServer is implemented by Server (server.go). It's the
+// hand-written equivalent of what protoc-gen-go-grpc would emit for
+// memory.proto's MemoryStore service.
+type memoryStoreServer interface {
+	Store(context.Context, *storeRequest) (*storeResponse, error)
+	Retrieve(context.Context, *retrieveRequest) (*retrieveResponse, error)
+	Get(context.Context, *getRequest) (*getResponse, error)
+	Update(context.Context, *updateRequest) (*updateResponse, error)
+	Forget(context.Context, *forgetRequest) (*forgetResponse, error)
+	ForgetByScope(context.Context, *forgetByScopeRequest) (*forgetByScopeResponse, error)
+	UpdateScore(context.Context, *updateScoreRequest) (*updateScoreResponse, error)
+}
+
+// RegisterMemoryStoreServer registers srv (normally a *Server wrapping a
+// memory.Store) against the MemoryStore service on s, mirroring the
+// *_grpc.pb.go RegisterXxxServer function protoc-gen-go-grpc would emit.
+func RegisterMemoryStoreServer(s grpc.ServiceRegistrar, srv memoryStoreServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*memoryStoreServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Store", Handler: storeHandler},
+		{MethodName: "Retrieve", Handler: retrieveHandler},
+		{MethodName: "Get", Handler: getHandler},
+		{MethodName: "Update", Handler: updateHandler},
+		{MethodName: "Forget", Handler: forgetHandler},
+		{MethodName: "ForgetByScope", Handler: forgetByScopeHandler},
+		{MethodName: "UpdateScore", Handler: updateScoreHandler},
+	},
+	Metadata: "memory.proto",
+}
+
+func storeHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(storeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(memoryStoreServer).Store(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Store"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(memoryStoreServer).Store(ctx, req.(*storeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func retrieveHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(retrieveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(memoryStoreServer).Retrieve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Retrieve"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(memoryStoreServer).Retrieve(ctx, req.(*retrieveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func getHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(getRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(memoryStoreServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Get"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(memoryStoreServer).Get(ctx, req.(*getRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func updateHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(updateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(memoryStoreServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Update"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(memoryStoreServer).Update(ctx, req.(*updateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func forgetHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(forgetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(memoryStoreServer).Forget(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Forget"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(memoryStoreServer).Forget(ctx, req.(*forgetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func forgetByScopeHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(forgetByScopeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(memoryStoreServer).ForgetByScope(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/ForgetByScope"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(memoryStoreServer).ForgetByScope(ctx, req.(*forgetByScopeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func updateScoreHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(updateScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(memoryStoreServer).UpdateScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/UpdateScore"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(memoryStoreServer).UpdateScore(ctx, req.(*updateScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
@@ -0,0 +1,199 @@
+package grpcstore
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+)
+
+// The types below mirror memory.proto's messages. They're hand-written
+// rather than protoc-generated: this package transports them with jsonCodec
+// (see codec.go) instead of the protobuf wire format, so memory.proto is the
+// contract a future protoc-gen-go pass would generate from, not something
+// this package's Go code depends on yet.
+
+type wireMemory struct {
+	ID             string  `json:"id"`
+	Type           string  `json:"type"`
+	Content        string  `json:"content"`
+	UserID         string  `json:"user_id"`
+	ProjectID      string  `json:"project_id"`
+	MetadataJSON   string  `json:"metadata_json"`
+	Source         string  `json:"source"`
+	Confidence     float64 `json:"confidence"`
+	Importance     float64 `json:"importance"`
+	AccessCount    int64   `json:"access_count"`
+	HitCount       int64   `json:"hit_count"`
+	CreatedAtUnix  int64   `json:"created_at_unix"`
+	AccessedAtUnix int64   `json:"accessed_at_unix"`
+}
+
+func toWireMemory(mem *memory.Memory) (*wireMemory, error) {
+	if mem == nil {
+		return nil, nil
+	}
+	metadataJSON, err := json.Marshal(mem.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return &wireMemory{
+		ID:             mem.ID,
+		Type:           string(mem.Type),
+		Content:        mem.Content,
+		UserID:         mem.UserID,
+		ProjectID:      mem.ProjectID,
+		MetadataJSON:   string(metadataJSON),
+		Source:         mem.Source,
+		Confidence:     mem.Confidence,
+		Importance:     mem.Importance,
+		AccessCount:    int64(mem.AccessCount),
+		HitCount:       int64(mem.HitCount),
+		CreatedAtUnix:  mem.CreatedAt.Unix(),
+		AccessedAtUnix: mem.AccessedAt.Unix(),
+	}, nil
+}
+
+func fromWireMemory(w *wireMemory) (*memory.Memory, error) {
+	if w == nil {
+		return nil, nil
+	}
+	var metadata map[string]any
+	if w.MetadataJSON != "" {
+		if err := json.Unmarshal([]byte(w.MetadataJSON), &metadata); err != nil {
+			return nil, err
+		}
+	}
+	return &memory.Memory{
+		ID:          w.ID,
+		Type:        memory.MemoryType(w.Type),
+		Content:     w.Content,
+		UserID:      w.UserID,
+		ProjectID:   w.ProjectID,
+		Metadata:    metadata,
+		Source:      w.Source,
+		Confidence:  w.Confidence,
+		Importance:  w.Importance,
+		AccessCount: int(w.AccessCount),
+		HitCount:    int(w.HitCount),
+		CreatedAt:   time.Unix(w.CreatedAtUnix, 0).UTC(),
+		AccessedAt:  time.Unix(w.AccessedAtUnix, 0).UTC(),
+	}, nil
+}
+
+type wireMemoryScope struct {
+	UserID    string `json:"user_id"`
+	ProjectID string `json:"project_id"`
+	Type      string `json:"type"`
+}
+
+func toWireScope(scope memory.MemoryScope) wireMemoryScope {
+	return wireMemoryScope{UserID: scope.UserID, ProjectID: scope.ProjectID, Type: string(scope.Type)}
+}
+
+func fromWireScope(w wireMemoryScope) memory.MemoryScope {
+	return memory.MemoryScope{UserID: w.UserID, ProjectID: w.ProjectID, Type: memory.MemoryType(w.Type)}
+}
+
+type wireRetrieveOptions struct {
+	Query       string   `json:"query"`
+	UserID      string   `json:"user_id"`
+	ProjectID   string   `json:"project_id"`
+	Types       []string `json:"types"`
+	Limit       int32    `json:"limit"`
+	Threshold   float32  `json:"threshold"`
+	Consistency string   `json:"consistency"`
+	Diversity   float32  `json:"diversity"`
+}
+
+func toWireOptions(opts memory.RetrieveOptions) wireRetrieveOptions {
+	types := make([]string, len(opts.Types))
+	for i, t := range opts.Types {
+		types[i] = string(t)
+	}
+	return wireRetrieveOptions{
+		Query:       opts.Query,
+		UserID:      opts.UserID,
+		ProjectID:   opts.ProjectID,
+		Types:       types,
+		Limit:       int32(opts.Limit),
+		Threshold:   opts.Threshold,
+		Consistency: string(opts.Consistency),
+		Diversity:   opts.Diversity,
+	}
+}
+
+func fromWireOptions(w wireRetrieveOptions) memory.RetrieveOptions {
+	types := make([]memory.MemoryType, len(w.Types))
+	for i, t := range w.Types {
+		types[i] = memory.MemoryType(t)
+	}
+	return memory.RetrieveOptions{
+		Query:       w.Query,
+		UserID:      w.UserID,
+		ProjectID:   w.ProjectID,
+		Types:       types,
+		Limit:       int(w.Limit),
+		Threshold:   w.Threshold,
+		Consistency: memory.ConsistencyLevel(w.Consistency),
+		Diversity:   w.Diversity,
+	}
+}
+
+type wireRetrieveResult struct {
+	Memory    *wireMemory `json:"memory"`
+	Relevance float64     `json:"relevance"`
+	RawScore  float64     `json:"raw_score"`
+}
+
+// Request/response envelopes, one pair per memory.proto RPC.
+
+type storeRequest struct {
+	Memory *wireMemory `json:"memory"`
+}
+
+type storeResponse struct {
+	Memory *wireMemory `json:"memory"`
+}
+
+type retrieveRequest struct {
+	Options wireRetrieveOptions `json:"options"`
+}
+
+type retrieveResponse struct {
+	Results []*wireRetrieveResult `json:"results"`
+}
+
+type getRequest struct {
+	ID string `json:"id"`
+}
+
+type getResponse struct {
+	Memory *wireMemory `json:"memory"`
+}
+
+type updateRequest struct {
+	ID     string      `json:"id"`
+	Memory *wireMemory `json:"memory"`
+}
+
+type updateResponse struct{}
+
+type forgetRequest struct {
+	ID string `json:"id"`
+}
+
+type forgetResponse struct{}
+
+type forgetByScopeRequest struct {
+	Scope wireMemoryScope `json:"scope"`
+}
+
+type forgetByScopeResponse struct{}
+
+type updateScoreRequest struct {
+	ID    string  `json:"id"`
+	Score float64 `json:"score"`
+}
+
+type updateScoreResponse struct{}
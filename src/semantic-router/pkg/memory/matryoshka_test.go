@@ -0,0 +1,57 @@
+//go:build !windows && cgo
+
+package memory
+
+import "testing"
+
+func TestMatryoshkaConfig_CoarseDim(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  MatryoshkaConfig
+		want int
+	}{
+		{"disabled", MatryoshkaConfig{}, 0},
+		{"defaults to smallest", MatryoshkaConfig{Dims: []int{512, 128, 256}}, 128},
+		{"explicit override", MatryoshkaConfig{Dims: []int{128, 256, 512}, CoarseDim: 256}, 256},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.cfg.coarseDim(); got != c.want {
+				t.Errorf("coarseDim() = %d, want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestMatryoshkaConfig_CandidatePool(t *testing.T) {
+	if got := (MatryoshkaConfig{}).candidatePool(); got != 100 {
+		t.Errorf("candidatePool() default = %d, want 100", got)
+	}
+	if got := (MatryoshkaConfig{CandidatePool: 50}).candidatePool(); got != 50 {
+		t.Errorf("candidatePool() override = %d, want 50", got)
+	}
+}
+
+func TestMatryoshkaFieldName(t *testing.T) {
+	if got := matryoshkaFieldName(128); got != "embedding_128" {
+		t.Errorf("matryoshkaFieldName(128) = %q, want %q", got, "embedding_128")
+	}
+}
+
+func TestTruncateEmbedding(t *testing.T) {
+	full := []float32{1, 2, 3, 4, 5, 6, 7, 8}
+
+	truncated := truncateEmbedding(full, 4)
+	if len(truncated) != 4 {
+		t.Fatalf("truncateEmbedding to 4 = %v, want length 4", truncated)
+	}
+	for i, v := range []float32{1, 2, 3, 4} {
+		if truncated[i] != v {
+			t.Errorf("truncateEmbedding[%d] = %v, want %v", i, truncated[i], v)
+		}
+	}
+
+	if got := truncateEmbedding(full, len(full)+10); len(got) != len(full) {
+		t.Errorf("truncateEmbedding past full length = %v, want the original embedding unchanged", got)
+	}
+}
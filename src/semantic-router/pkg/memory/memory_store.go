@@ -2,25 +2,94 @@ package memory
 
 import (
 	"context"
-	"strings"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
 )
 
+// defaultConsolidateThreshold is the cosine-similarity cutoff
+// InMemoryStore.Consolidate uses when ConsolidateOptions.SimilarityThreshold
+// is unset - slightly looser than ConsolidationConfig.MergeThreshold's 0.9,
+// since Consolidate clusters across a whole scope in one pass rather than
+// matching one new fact against existing memories at a time.
+const defaultConsolidateThreshold = 0.85
+
+// maxTombstoneLog bounds InMemoryStore's tombstone log (see Tombstones), so
+// a store that never restarts doesn't grow its forgotten-ID history forever.
+// A sync client whose cursor falls outside this window must fall back to a
+// full resync via UpdatedSince with a zero timestamp.
+const maxTombstoneLog = 10000
+
+// tombstone records a forgotten memory's scope so Tombstones can filter the
+// log the same way UpdatedSince filters live memories.
+type tombstone struct {
+	id          string
+	userID      string
+	projectID   string
+	memType     MemoryType
+	forgottenAt time.Time
+}
+
 // InMemoryStore is a simple in-memory implementation of the Store interface.
 // This is useful for development, testing, and as a POC before integrating Milvus.
-// Note: This uses simple text matching, not semantic/vector search.
+// Retrieve's ranking is pluggable via InMemoryStoreConfig.Scorer - the
+// default is still simple keyword matching, not semantic/vector search.
 type InMemoryStore struct {
-	mu       sync.RWMutex
-	memories map[string]*Memory // key: memory ID
+	mu          sync.RWMutex
+	memories    map[string]*Memory // key: memory ID
+	tombstones  []tombstone        // bounded log backing Tombstones, oldest first
+	scorer      Scorer
+	decayPolicy DecayPolicy
+	maintenance MaintenanceOptions
+
+	maintStop chan struct{}
+	maintDone chan struct{}
+}
+
+// InMemoryStoreConfig configures an InMemoryStore's relevance scoring,
+// decay, and background maintenance. The zero value matches
+// NewInMemoryStore's behavior exactly.
+type InMemoryStoreConfig struct {
+	// Scorer ranks Retrieve candidates against a query. Nil defaults to
+	// NewKeywordScorer(), InMemoryStore's original substring-overlap
+	// heuristic. Pass NewBM25Scorer() or NewHybridScorer(embedder, weight)
+	// for better ranking at the cost of an index/embedder to maintain.
+	Scorer Scorer
+
+	// Decay folds Importance/AccessCount into Retrieve's ranking score. The
+	// zero value is a no-op - Retrieve ranks purely on Scorer's output, as
+	// before DecayPolicy existed.
+	Decay DecayPolicy
+
+	// Maintenance configures the background loop StartMaintenance drives.
+	// Unused unless StartMaintenance is called.
+	Maintenance MaintenanceOptions
 }
 
-// NewInMemoryStore creates a new in-memory store
+// NewInMemoryStore creates a new in-memory store using the default keyword
+// Scorer. Equivalent to NewInMemoryStoreWithConfig(InMemoryStoreConfig{}).
 func NewInMemoryStore() *InMemoryStore {
+	return NewInMemoryStoreWithConfig(InMemoryStoreConfig{})
+}
+
+// NewInMemoryStoreWithConfig creates a new in-memory store using cfg's
+// Scorer (or NewKeywordScorer() if unset), DecayPolicy, and
+// MaintenanceOptions.
+func NewInMemoryStoreWithConfig(cfg InMemoryStoreConfig) *InMemoryStore {
+	scorer := cfg.Scorer
+	if scorer == nil {
+		scorer = NewKeywordScorer()
+	}
 	return &InMemoryStore{
-		memories: make(map[string]*Memory),
+		memories:    make(map[string]*Memory),
+		scorer:      scorer,
+		decayPolicy: cfg.Decay,
+		maintenance: cfg.Maintenance,
 	}
 }
 
@@ -33,6 +102,13 @@ func (s *InMemoryStore) Store(ctx context.Context, memory *Memory) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.storeLocked(memory)
+	return nil
+}
+
+// storeLocked is Store's body, split out so Apply can reuse it while already
+// holding s.mu for writing. Callers must hold s.mu for writing.
+func (s *InMemoryStore) storeLocked(memory *Memory) {
 	// Generate ID if not provided
 	if memory.ID == "" {
 		memory.ID = "mem_" + uuid.New().String()[:8]
@@ -44,34 +120,82 @@ func (s *InMemoryStore) Store(ctx context.Context, memory *Memory) error {
 		memory.CreatedAt = now
 	}
 	memory.AccessedAt = now
+	memory.UpdatedAt = now
+	memory.Version = 1
 
 	// Store a copy
 	stored := *memory
 	s.memories[memory.ID] = &stored
 
-	return nil
+	if m, ok := s.scorer.(scorerMaintainer); ok {
+		m.onStore(&stored)
+	}
 }
 
 // Retrieve finds memories matching the query and options.
 // POC: Uses simple keyword matching. Production should use vector similarity.
+//
+// Retrieve honors ctx's deadline (or opts.MaxLatency, if ctx has none of its
+// own): scoring runs in a goroutine while this call selects on ctx.Done()
+// vs. its completion, so a slow Scorer (hybridScorer's embedding call, in
+// particular) can't block the caller past the deadline. ctx is passed
+// through to the Scorer either way, so the same deadline cancels the
+// in-flight Embedder call too, not just this call's wait.
 func (s *InMemoryStore) Retrieve(ctx context.Context, opts RetrieveOptions) ([]*RetrieveResult, error) {
 	if opts.UserID == "" {
 		return nil, ErrInvalidUserID
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	ctx, cancel := opts.withDeadline(ctx)
+	defer cancel()
+
+	results := make(chan []*RetrieveResult, 1)
+	go func() {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		results <- s.retrieveLocked(ctx, opts)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, newError(CodeDeadlineExceeded, "Retrieve", "", ctx.Err())
+	case r := <-results:
+		return r, nil
+	}
+}
 
-	var results []*RetrieveResult
-	queryLower := strings.ToLower(opts.Query)
-	queryWords := strings.Fields(queryLower)
+// retrieveLocked is Retrieve's body, split out so Apply can reuse it while
+// already holding s.mu for writing - Retrieve itself can't be called there,
+// since sync.RWMutex isn't reentrant. Callers must hold s.mu (either lock).
+func (s *InMemoryStore) retrieveLocked(ctx context.Context, opts RetrieveOptions) []*RetrieveResult {
+	now := time.Now()
 
+	var candidates []*Memory
 	for _, mem := range s.memories {
 		// Filter by user
 		if mem.UserID != opts.UserID {
 			continue
 		}
 
+		// Skip expired working memory; ExpireDue reclaims it on its own
+		// schedule, but it must never surface as a live result in the meantime.
+		if isExpired(mem, now) {
+			continue
+		}
+
+		// Skip memories a later edit-and-resend turn has superseded, unless
+		// the caller explicitly asked to see the full branch history.
+		if excludeSuperseded(mem, opts) {
+			continue
+		}
+
+		// Filter by conversation branch - the default (opts.BranchID == "")
+		// matches only mainline memories, so a ForkBranch copy never
+		// surfaces outside its own branch's Retrieve calls.
+		if mem.BranchID != opts.BranchID {
+			continue
+		}
+
 		// Filter by project (if specified)
 		if opts.ProjectID != "" && mem.ProjectID != opts.ProjectID {
 			continue
@@ -91,28 +215,54 @@ func (s *InMemoryStore) Retrieve(ctx context.Context, opts RetrieveOptions) ([]*
 			}
 		}
 
-		// Simple relevance scoring based on keyword matching
-		// POC: Count how many query words appear in the content
-		contentLower := strings.ToLower(mem.Content)
-		matchCount := 0
-		for _, word := range queryWords {
-			if strings.Contains(contentLower, word) {
-				matchCount++
-			}
+		// Filter by time window (if specified)
+		if !inTimeWindow(mem.CreatedAt, opts.TimeWindow) {
+			continue
+		}
+
+		candidates = append(candidates, mem)
+	}
+
+	scores := s.scorer.Score(ctx, opts.Query, candidates)
+
+	var results []*RetrieveResult
+	var decayFactors []float64 // parallel to results; snapshotted before access tracking below bumps AccessedAt/AccessCount
+	for i, mem := range candidates {
+		relevance := scores[i]
+		if relevance <= 0 {
+			continue
 		}
 
-		// Only include if there's some match
-		if matchCount > 0 {
-			relevance := float64(matchCount) / float64(len(queryWords))
+		decayFactor := 1.0
+		if !s.decayPolicy.isZero() {
+			decayFactor = s.decayPolicy.score(mem, now)
+		}
 
-			// Update access tracking
-			mem.AccessCount++
-			mem.AccessedAt = time.Now()
+		// Update access tracking
+		mem.AccessCount++
+		mem.AccessedAt = time.Now()
 
-			results = append(results, &RetrieveResult{
-				Memory:    mem,
-				Relevance: relevance,
-			})
+		results = append(results, &RetrieveResult{
+			Memory:    mem,
+			Relevance: relevance,
+			RawScore:  relevance,
+		})
+		decayFactors = append(decayFactors, decayFactor)
+	}
+
+	if opts.RecencyWeight > 0 {
+		anchor := time.Now()
+		if opts.TimeAnchor != nil {
+			anchor = *opts.TimeAnchor
+		}
+		for _, r := range results {
+			r.Relevance = blendRecency(r.RawScore, r.Memory.CreatedAt, anchor, opts.RecencyWeight)
+		}
+	}
+
+	if !s.decayPolicy.isZero() {
+		for i, r := range results {
+			r.Relevance *= decayFactors[i]
 		}
 	}
 
@@ -134,7 +284,7 @@ func (s *InMemoryStore) Retrieve(ctx context.Context, opts RetrieveOptions) ([]*
 		results = results[:limit]
 	}
 
-	return results, nil
+	return results
 }
 
 // Get retrieves a specific memory by ID
@@ -143,7 +293,7 @@ func (s *InMemoryStore) Get(ctx context.Context, id string) (*Memory, error) {
 	defer s.mu.RUnlock()
 
 	mem, ok := s.memories[id]
-	if !ok {
+	if !ok || isExpired(mem, time.Now()) {
 		return nil, ErrNotFound
 	}
 
@@ -152,30 +302,108 @@ func (s *InMemoryStore) Get(ctx context.Context, id string) (*Memory, error) {
 	return &result, nil
 }
 
-// Update modifies an existing memory
+// Update modifies an existing memory. If memory.Version is non-zero, it's
+// checked against the stored memory's current Version and rejected with a
+// CodeConflict *Error on a mismatch instead of overwriting it - the
+// caller's observed memory was stale, e.g. because the consolidation worker
+// touched it first. A zero Version skips the check, matching Update's
+// original last-writer-wins behavior. Either way, the stored memory's
+// Version is bumped by one on success; see CompareAndSwap for a
+// read-modify-write helper that handles the retry loop this implies.
 func (s *InMemoryStore) Update(ctx context.Context, id string, memory *Memory) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, ok := s.memories[id]; !ok {
+	return s.updateLocked(id, memory)
+}
+
+// updateLocked is Update's body, split out so Apply can reuse it while
+// already holding s.mu for writing. Callers must hold s.mu for writing.
+func (s *InMemoryStore) updateLocked(id string, memory *Memory) error {
+	existing, ok := s.memories[id]
+	if !ok {
 		return ErrNotFound
 	}
+	if memory.Version != 0 && memory.Version != existing.Version {
+		return newError(CodeConflict, "Update", id, nil)
+	}
 
 	memory.ID = id
+	memory.Version = existing.Version + 1
+	memory.UpdatedAt = time.Now()
 	s.memories[id] = memory
+
+	if m, ok := s.scorer.(scorerMaintainer); ok {
+		m.onUpdate(memory)
+	}
 	return nil
 }
 
+// CompareAndSwap performs a read-modify-write on the memory at id: mutate
+// receives a copy of the currently-stored memory and edits it in place, and
+// CompareAndSwap writes it back with the same version check Update uses,
+// retrying from a fresh read whenever it loses the race to a concurrent
+// writer. Unlike a manual Get-then-Update, the caller never has to handle
+// CodeConflict itself - mutate may be called more than once, so it must be
+// free of side effects beyond editing its argument. Returns ErrNotFound if
+// id doesn't exist, or ctx's error wrapped in a CodeDeadlineExceeded *Error
+// if ctx is done before a write succeeds.
+func (s *InMemoryStore) CompareAndSwap(ctx context.Context, id string, mutate func(*Memory) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return newError(CodeDeadlineExceeded, "CompareAndSwap", id, ctx.Err())
+		default:
+		}
+
+		s.mu.RLock()
+		existing, ok := s.memories[id]
+		var candidate Memory
+		if ok {
+			candidate = *existing
+		}
+		s.mu.RUnlock()
+		if !ok {
+			return ErrNotFound
+		}
+
+		if err := mutate(&candidate); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		err := s.updateLocked(id, &candidate)
+		s.mu.Unlock()
+
+		if err == nil || !IsCode(err, CodeConflict) {
+			return err
+		}
+		// Lost the race to a concurrent writer - reload and retry.
+	}
+}
+
 // Forget removes a memory by ID
 func (s *InMemoryStore) Forget(ctx context.Context, id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, ok := s.memories[id]; !ok {
+	return s.forgetLocked(id)
+}
+
+// forgetLocked is Forget's body, split out so Apply can reuse it while
+// already holding s.mu for writing. Callers must hold s.mu for writing.
+func (s *InMemoryStore) forgetLocked(id string) error {
+	mem, ok := s.memories[id]
+	if !ok {
 		return ErrNotFound
 	}
 
 	delete(s.memories, id)
+	s.addTombstone(tombstone{id: id, userID: mem.UserID, projectID: mem.ProjectID, memType: mem.Type, forgottenAt: time.Now()})
+
+	if m, ok := s.scorer.(scorerMaintainer); ok {
+		m.onForget(id)
+	}
 	return nil
 }
 
@@ -184,6 +412,7 @@ func (s *InMemoryStore) ForgetByScope(ctx context.Context, scope MemoryScope) er
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	now := time.Now()
 	for id, mem := range s.memories {
 		match := true
 
@@ -202,8 +431,47 @@ func (s *InMemoryStore) ForgetByScope(ctx context.Context, scope MemoryScope) er
 			match = false
 		}
 
+		// Check age bounds (retention policies, e.g. "older than 90 days")
+		if scope.Before != nil && !mem.CreatedAt.Before(*scope.Before) {
+			match = false
+		}
+		if scope.After != nil && !mem.CreatedAt.After(*scope.After) {
+			match = false
+		}
+
 		if match {
 			delete(s.memories, id)
+			s.addTombstone(tombstone{id: id, userID: mem.UserID, projectID: mem.ProjectID, memType: mem.Type, forgottenAt: now})
+			if m, ok := s.scorer.(scorerMaintainer); ok {
+				m.onForget(id)
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateScore overwrites a memory's Importance with score.
+func (s *InMemoryStore) UpdateScore(ctx context.Context, id string, score float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mem, ok := s.memories[id]
+	if !ok {
+		return ErrNotFound
+	}
+	mem.Importance = score
+	return nil
+}
+
+// BulkDelete removes every memory in ids, skipping IDs that don't exist.
+func (s *InMemoryStore) BulkDelete(ctx context.Context, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		delete(s.memories, id)
+		if m, ok := s.scorer.(scorerMaintainer); ok {
+			m.onForget(id)
 		}
 	}
 	return nil
@@ -225,3 +493,511 @@ func (s *InMemoryStore) Count() int {
 	defer s.mu.RUnlock()
 	return len(s.memories)
 }
+
+// Iterate implements StoreIterator, visiting every stored memory. It holds
+// the read lock for the duration of the scan, so fn must not call back into
+// the store.
+func (s *InMemoryStore) Iterate(ctx context.Context, fn func(*Memory) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, mem := range s.memories {
+		copied := *mem
+		if err := fn(&copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addTombstone appends t to the log, trimming the oldest entry if it would
+// exceed maxTombstoneLog. Callers must hold s.mu for writing.
+func (s *InMemoryStore) addTombstone(t tombstone) {
+	s.tombstones = append(s.tombstones, t)
+	if len(s.tombstones) > maxTombstoneLog {
+		s.tombstones = s.tombstones[len(s.tombstones)-maxTombstoneLog:]
+	}
+}
+
+func tombstoneInScope(t tombstone, scope MemoryScope) bool {
+	if scope.UserID != "" && t.userID != scope.UserID {
+		return false
+	}
+	if scope.ProjectID != "" && t.projectID != scope.ProjectID {
+		return false
+	}
+	if scope.Type != "" && t.memType != scope.Type {
+		return false
+	}
+	return true
+}
+
+// UpdatedSince implements StoreSync, returning every memory in scope whose
+// UpdatedAt is strictly after ts, most-recently-updated first.
+func (s *InMemoryStore) UpdatedSince(ctx context.Context, scope MemoryScope, ts time.Time) ([]*Memory, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var results []*Memory
+	for _, mem := range s.memories {
+		if scope.UserID != "" && mem.UserID != scope.UserID {
+			continue
+		}
+		if scope.ProjectID != "" && mem.ProjectID != scope.ProjectID {
+			continue
+		}
+		if scope.Type != "" && mem.Type != scope.Type {
+			continue
+		}
+		if !mem.UpdatedAt.After(ts) {
+			continue
+		}
+		copied := *mem
+		results = append(results, &copied)
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].UpdatedAt.After(results[j].UpdatedAt) })
+	return results, nil
+}
+
+// Tombstones implements StoreSync, returning the IDs forgotten within scope
+// since ts, most-recently-forgotten first. The log is bounded by
+// maxTombstoneLog; a cursor older than the oldest retained entry will miss
+// tombstones and the caller should fall back to a full resync.
+func (s *InMemoryStore) Tombstones(ctx context.Context, scope MemoryScope, ts time.Time) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type idAt struct {
+		id string
+		at time.Time
+	}
+	var matched []idAt
+	for _, t := range s.tombstones {
+		if !t.forgottenAt.After(ts) {
+			continue
+		}
+		if !tombstoneInScope(t, scope) {
+			continue
+		}
+		matched = append(matched, idAt{id: t.id, at: t.forgottenAt})
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].at.After(matched[j].at) })
+	ids := make([]string, len(matched))
+	for i, m := range matched {
+		ids[i] = m.id
+	}
+	return ids, nil
+}
+
+// ExpireDue implements StoreExpirer, deleting every memory whose TTL has
+// passed. Retrieve/Get already filter expired rows out of their own
+// results, so this is purely reclaiming storage - typically driven by an
+// ExpiryReaper on a ticker rather than called inline.
+func (s *InMemoryStore) ExpireDue(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var expiredIDs []string
+	for id, mem := range s.memories {
+		if isExpired(mem, now) {
+			expiredIDs = append(expiredIDs, id)
+		}
+	}
+
+	for _, id := range expiredIDs {
+		mem := s.memories[id]
+		delete(s.memories, id)
+		s.addTombstone(tombstone{id: id, userID: mem.UserID, projectID: mem.ProjectID, memType: mem.Type, forgottenAt: now})
+		if m, ok := s.scorer.(scorerMaintainer); ok {
+			m.onForget(id)
+		}
+	}
+	return len(expiredIDs), nil
+}
+
+// Apply implements StoreApply under a single mutex: every op runs against a
+// snapshot of s.memories/s.tombstones, and if any op fails the snapshot is
+// restored before returning, so a caller never observes a partially-applied
+// batch.
+func (s *InMemoryStore) Apply(ctx context.Context, ops []MemoryOp) (ApplyResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	memSnapshot := make(map[string]*Memory, len(s.memories))
+	for id, mem := range s.memories {
+		memSnapshot[id] = mem
+	}
+	tombSnapshot := append([]tombstone(nil), s.tombstones...)
+
+	result, err := s.applyLocked(ctx, ops)
+	if err != nil {
+		s.memories = memSnapshot
+		s.tombstones = tombSnapshot
+		return ApplyResult{}, err
+	}
+	return result, nil
+}
+
+// applyLocked runs every op in ops against s.memories/s.tombstones. Callers
+// must hold s.mu for writing and be prepared to roll back on error - it
+// mutates the store in place rather than building up a result to apply
+// atomically at the end, since a Put's dedup check needs to see the effects
+// of earlier ops in the same batch.
+func (s *InMemoryStore) applyLocked(ctx context.Context, ops []MemoryOp) (ApplyResult, error) {
+	var result ApplyResult
+
+	for _, op := range ops {
+		switch op.Kind {
+		case MemoryOpPut:
+			if op.Dedup {
+				config := DefaultDeduplicationConfig()
+				if op.DedupConfig != nil {
+					config = *op.DedupConfig
+				}
+				candidates := s.retrieveLocked(ctx, RetrieveOptions{
+					UserID: op.Memory.UserID,
+					Query:  op.Memory.Content,
+					Types:  []MemoryType{op.Memory.Type},
+					Limit:  config.CandidateLimit,
+				})
+				dedup := bestDeduplicationMatch(op.Memory.Content, op.Memory.OccurredAt, candidates, config)
+				if dedup.Action == "update" && dedup.ExistingMemory != nil {
+					dedup.ExistingMemory.Content = op.Memory.Content
+					if err := s.updateLocked(dedup.ExistingMemory.ID, dedup.ExistingMemory); err != nil {
+						return ApplyResult{}, fmt.Errorf("memory: apply put (deduplicated) %s: %w", dedup.ExistingMemory.ID, err)
+					}
+					result.Updated = append(result.Updated, dedup.ExistingMemory.ID)
+					result.Deduplicated++
+					continue
+				}
+			}
+
+			if op.Memory.UserID == "" {
+				return ApplyResult{}, ErrInvalidUserID
+			}
+			s.storeLocked(op.Memory)
+			result.Created = append(result.Created, op.Memory.ID)
+
+		case MemoryOpUpdate:
+			if err := s.updateLocked(op.ID, op.Memory); err != nil {
+				return ApplyResult{}, fmt.Errorf("memory: apply update %s: %w", op.ID, err)
+			}
+			result.Updated = append(result.Updated, op.ID)
+
+		case MemoryOpForget:
+			if err := s.forgetLocked(op.ID); err != nil {
+				return ApplyResult{}, fmt.Errorf("memory: apply forget %s: %w", op.ID, err)
+			}
+			result.Forgotten = append(result.Forgotten, op.ID)
+
+		default:
+			return ApplyResult{}, fmt.Errorf("memory: apply: unknown MemoryOp kind %q", op.Kind)
+		}
+	}
+
+	return result, nil
+}
+
+// =============================================================================
+// Consolidate, ForgetStale, and background maintenance
+// =============================================================================
+
+// Consolidate implements StoreConsolidator: it clusters memories scoped to
+// (opts.UserID, opts.ProjectID) whose embeddings are at least
+// opts.SimilarityThreshold similar, and replaces each multi-memory cluster
+// with one new MemoryTypeSemantic memory produced by opts.Summarizer
+// (joinSummarizer if nil) from the cluster's contents. Clustering is
+// greedy - each memory joins the first cluster it's similar enough to, not
+// a globally optimal grouping - the same heuristic-over-optimal tradeoff
+// bestDeduplicationMatch already makes for single-memory matches.
+func (s *InMemoryStore) Consolidate(ctx context.Context, opts ConsolidateOptions) (ConsolidateResult, error) {
+	if opts.UserID == "" {
+		return ConsolidateResult{}, ErrInvalidUserID
+	}
+	if opts.Embedder == nil {
+		return ConsolidateResult{}, fmt.Errorf("memory: consolidate requires an Embedder")
+	}
+	summarize := opts.Summarizer
+	if summarize == nil {
+		summarize = joinSummarizer
+	}
+	threshold := opts.SimilarityThreshold
+	if threshold <= 0 {
+		threshold = defaultConsolidateThreshold
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var candidates []*Memory
+	for _, mem := range s.memories {
+		if mem.UserID != opts.UserID || isExpired(mem, now) {
+			continue
+		}
+		if opts.ProjectID != "" && mem.ProjectID != opts.ProjectID {
+			continue
+		}
+		candidates = append(candidates, mem)
+	}
+
+	embeddings, err := embeddingsFor(ctx, opts.Embedder, candidates)
+	if err != nil {
+		return ConsolidateResult{}, fmt.Errorf("memory: consolidate embed: %w", err)
+	}
+
+	var result ConsolidateResult
+	for _, cluster := range clusterBySimilarity(candidates, embeddings, threshold) {
+		if len(cluster) < 2 {
+			continue
+		}
+
+		variants := make([]string, len(cluster))
+		for i, mem := range cluster {
+			variants[i] = mem.Content
+		}
+		content, err := summarize(ctx, variants)
+		if err != nil {
+			return ConsolidateResult{}, fmt.Errorf("memory: consolidate summarize: %w", err)
+		}
+
+		merged := &Memory{
+			UserID:     opts.UserID,
+			ProjectID:  opts.ProjectID,
+			Type:       MemoryTypeSemantic,
+			Content:    content,
+			Importance: maxImportance(cluster),
+			Source:     "consolidation",
+		}
+		s.storeLocked(merged)
+		result.Created = append(result.Created, merged.ID)
+
+		for _, mem := range cluster {
+			if err := s.forgetLocked(mem.ID); err != nil && err != ErrNotFound {
+				return ConsolidateResult{}, fmt.Errorf("memory: consolidate forget %s: %w", mem.ID, err)
+			}
+		}
+		result.ClustersMerged++
+		result.MemoriesRemoved += len(cluster)
+	}
+
+	return result, nil
+}
+
+// embeddingsFor returns one embedding per candidates[i], reusing
+// mem.Embedding where already populated and batching a single Embed call
+// for the rest.
+func embeddingsFor(ctx context.Context, embedder Embedder, candidates []*Memory) ([][]float32, error) {
+	embeddings := make([][]float32, len(candidates))
+	var missingIdx []int
+	var missingText []string
+	for i, mem := range candidates {
+		if len(mem.Embedding) > 0 {
+			embeddings[i] = mem.Embedding
+			continue
+		}
+		missingIdx = append(missingIdx, i)
+		missingText = append(missingText, mem.Content)
+	}
+	if len(missingText) == 0 {
+		return embeddings, nil
+	}
+
+	computed, err := embedder.Embed(ctx, missingText)
+	if err != nil {
+		return nil, err
+	}
+	for i, idx := range missingIdx {
+		if i < len(computed) {
+			embeddings[idx] = computed[i]
+		}
+	}
+	return embeddings, nil
+}
+
+// clusterBySimilarity greedily groups candidates whose embeddings are at
+// least threshold similar: each not-yet-assigned memory seeds a new
+// cluster, and every later memory joins the first cluster whose seed it's
+// similar enough to.
+func clusterBySimilarity(candidates []*Memory, embeddings [][]float32, threshold float64) [][]*Memory {
+	assigned := make([]bool, len(candidates))
+	var clusters [][]*Memory
+
+	for i := range candidates {
+		if assigned[i] {
+			continue
+		}
+		cluster := []*Memory{candidates[i]}
+		assigned[i] = true
+		for j := i + 1; j < len(candidates); j++ {
+			if assigned[j] {
+				continue
+			}
+			if cosineSimilarity(embeddings[i], embeddings[j]) >= threshold {
+				cluster = append(cluster, candidates[j])
+				assigned[j] = true
+			}
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
+
+// maxImportance returns the highest Importance across cluster, so a merged
+// memory doesn't lose whichever variant the user engaged with most.
+func maxImportance(cluster []*Memory) float64 {
+	var max float64
+	for _, mem := range cluster {
+		if mem.Importance > max {
+			max = mem.Importance
+		}
+	}
+	return max
+}
+
+// ForgetStale prunes every memory last accessed more than olderThan ago
+// whose DecayPolicy-scored importance has fallen below minImportance, in
+// one pass. Unlike ExpireDue (TTL-bound working memory) or
+// MemoryConsolidator.RunOnce (Lambda-based decay, works against any Store),
+// ForgetStale is specific to InMemoryStore's own DecayPolicy, so a memory is
+// only pruned once it would already be ranking near zero at retrieval time.
+func (s *InMemoryStore) ForgetStale(ctx context.Context, olderThan time.Duration, minImportance float64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var staleIDs []string
+	for id, mem := range s.memories {
+		if now.Sub(mem.AccessedAt) < olderThan {
+			continue
+		}
+		if s.decayPolicy.score(mem, now) >= minImportance {
+			continue
+		}
+		staleIDs = append(staleIDs, id)
+	}
+
+	for _, id := range staleIDs {
+		if err := s.forgetLocked(id); err != nil && err != ErrNotFound {
+			return 0, fmt.Errorf("memory: forget stale %s: %w", id, err)
+		}
+	}
+	return len(staleIDs), nil
+}
+
+// MaintenanceOptions configures the background loop StartMaintenance
+// drives, each pass consolidating and then pruning every (UserID,
+// ProjectID) scope currently present in the store.
+type MaintenanceOptions struct {
+	// Embedder is passed to Consolidate. Nil skips the consolidation pass
+	// entirely - DecayPolicy and ForgetStale work fine without one.
+	Embedder Embedder
+
+	// SimilarityThreshold is passed to Consolidate (<= 0 uses its own
+	// default).
+	SimilarityThreshold float64
+
+	// Summarizer is passed to Consolidate (nil uses joinSummarizer).
+	Summarizer Summarizer
+
+	// StaleAfter and MinImportance are passed to ForgetStale every pass.
+	// MinImportance <= 0 never matches (a DecayPolicy score is never
+	// negative), so ForgetStale is effectively disabled until it's set.
+	StaleAfter    time.Duration
+	MinImportance float64
+}
+
+// userProjectScope identifies one (UserID, ProjectID) pair for
+// activeScopes/runMaintenancePass to Consolidate independently.
+type userProjectScope struct {
+	userID    string
+	projectID string
+}
+
+// activeScopes returns the distinct (UserID, ProjectID) pairs currently
+// present in the store, so runMaintenancePass can Consolidate one scope at
+// a time rather than mixing every user's memories into one pass.
+func (s *InMemoryStore) activeScopes() []userProjectScope {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[userProjectScope]bool)
+	var scopes []userProjectScope
+	for _, mem := range s.memories {
+		scope := userProjectScope{userID: mem.UserID, projectID: mem.ProjectID}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// runMaintenancePass runs one Consolidate (per active scope, if
+// s.maintenance.Embedder is set) plus one ForgetStale pass. Errors are
+// logged, not returned - maintenance is best-effort background work, the
+// same contract MemoryConsolidator.Start's ticker loop has.
+func (s *InMemoryStore) runMaintenancePass(ctx context.Context) {
+	if s.maintenance.Embedder != nil {
+		for _, scope := range s.activeScopes() {
+			if _, err := s.Consolidate(ctx, ConsolidateOptions{
+				UserID:              scope.userID,
+				ProjectID:           scope.projectID,
+				SimilarityThreshold: s.maintenance.SimilarityThreshold,
+				Embedder:            s.maintenance.Embedder,
+				Summarizer:          s.maintenance.Summarizer,
+			}); err != nil {
+				logging.Warnf("memory: background consolidate failed for user %s: %v", scope.userID, err)
+			}
+		}
+	}
+
+	if _, err := s.ForgetStale(ctx, s.maintenance.StaleAfter, s.maintenance.MinImportance); err != nil {
+		logging.Warnf("memory: background forget-stale failed: %v", err)
+	}
+}
+
+// StartMaintenance runs one runMaintenancePass (Consolidate, if
+// Maintenance.Embedder is set, then ForgetStale) every interval until
+// StopMaintenance is called. A no-op if interval <= 0. Mirrors
+// MemoryConsolidator.Start/Stop's ticker-and-stop-channel pattern; unlike
+// MemoryConsolidator (Lambda decay, works against any Store),
+// StartMaintenance is InMemoryStore-specific, driving its DecayPolicy-based
+// pruning and embedding-cluster consolidation.
+func (s *InMemoryStore) StartMaintenance(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	s.maintStop = make(chan struct{})
+	s.maintDone = make(chan struct{})
+
+	go func() {
+		defer close(s.maintDone)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.runMaintenancePass(context.Background())
+			case <-s.maintStop:
+				return
+			}
+		}
+	}()
+}
+
+// StopMaintenance ends the loop started by StartMaintenance and waits for
+// an in-flight pass to finish. A no-op if StartMaintenance was never
+// called.
+func (s *InMemoryStore) StopMaintenance() {
+	if s.maintStop == nil {
+		return
+	}
+	close(s.maintStop)
+	<-s.maintDone
+}
@@ -215,3 +215,49 @@ func TestInMemoryStore_RequiresUserID(t *testing.T) {
 		t.Errorf("Expected ErrInvalidUserID, got %v", err)
 	}
 }
+
+func TestInMemoryStore_RetrieveExcludesSupersededByDefault(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	if err := store.Store(ctx, &Memory{
+		Type: MemoryTypeEpisodic, Content: "User asked about Paris trip", UserID: "user_1",
+	}); err != nil {
+		t.Fatalf("seed memory: %v", err)
+	}
+	if err := store.Store(ctx, &Memory{
+		Type: MemoryTypeEpisodic, Content: "User asked about Paris trip (edited)", UserID: "user_1",
+	}); err != nil {
+		t.Fatalf("seed memory: %v", err)
+	}
+
+	results, err := store.Retrieve(ctx, RetrieveOptions{Query: "Paris", UserID: "user_1", Limit: 5})
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 live results before superseding, got %d", len(results))
+	}
+
+	superseded := *results[0].Memory
+	superseded.SupersededBy = results[1].Memory.ID
+	if err := store.Update(ctx, superseded.ID, &superseded); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	results, err = store.Retrieve(ctx, RetrieveOptions{Query: "Paris", UserID: "user_1", Limit: 5})
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected superseded memory to be excluded by default, got %d results", len(results))
+	}
+
+	results, err = store.Retrieve(ctx, RetrieveOptions{Query: "Paris", UserID: "user_1", Limit: 5, IncludeAllBranches: true})
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected IncludeAllBranches to surface the superseded memory too, got %d results", len(results))
+	}
+}
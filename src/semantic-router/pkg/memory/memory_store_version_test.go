@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryStore_Store_SetsInitialVersion(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	mem := &Memory{UserID: "user_1", Content: "first fact"}
+	require.NoError(t, store.Store(ctx, mem))
+	assert.Equal(t, uint64(1), mem.Version)
+}
+
+func TestInMemoryStore_Update_BumpsVersionOnSuccess(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	mem := &Memory{UserID: "user_1", Content: "first fact"}
+	require.NoError(t, store.Store(ctx, mem))
+
+	update := &Memory{UserID: "user_1", Content: "updated fact", Version: mem.Version}
+	require.NoError(t, store.Update(ctx, mem.ID, update))
+	assert.Equal(t, uint64(2), update.Version)
+}
+
+func TestInMemoryStore_Update_StaleVersionReturnsConflict(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	mem := &Memory{UserID: "user_1", Content: "first fact"}
+	require.NoError(t, store.Store(ctx, mem))
+
+	// A concurrent writer updates first, bumping the stored version to 2.
+	require.NoError(t, store.Update(ctx, mem.ID, &Memory{UserID: "user_1", Content: "winner", Version: mem.Version}))
+
+	// This caller is still holding the version it originally observed (1).
+	err := store.Update(ctx, mem.ID, &Memory{UserID: "user_1", Content: "loser", Version: mem.Version})
+	require.Error(t, err)
+	assert.True(t, IsCode(err, CodeConflict))
+
+	got, err := store.Get(ctx, mem.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "winner", got.Content, "the stale write must not have overwritten the winner")
+}
+
+func TestInMemoryStore_Update_ZeroVersionSkipsCheck(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	mem := &Memory{UserID: "user_1", Content: "first fact"}
+	require.NoError(t, store.Store(ctx, mem))
+
+	// A caller that builds a *Memory from scratch, rather than round-tripping
+	// through Get, leaves Version at its zero value - Update must still
+	// accept it, matching pre-version last-writer-wins behavior.
+	err := store.Update(ctx, mem.ID, &Memory{UserID: "user_1", Content: "overwritten"})
+	require.NoError(t, err)
+
+	got, err := store.Get(ctx, mem.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "overwritten", got.Content)
+}
+
+func TestInMemoryStore_CompareAndSwap_AppliesMutation(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	mem := &Memory{UserID: "user_1", Content: "count: 0"}
+	require.NoError(t, store.Store(ctx, mem))
+
+	err := store.CompareAndSwap(ctx, mem.ID, func(m *Memory) error {
+		m.Content = "count: 1"
+		return nil
+	})
+	require.NoError(t, err)
+
+	got, err := store.Get(ctx, mem.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "count: 1", got.Content)
+	assert.Equal(t, uint64(2), got.Version)
+}
+
+func TestInMemoryStore_CompareAndSwap_RetriesOnConcurrentWrite(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	mem := &Memory{UserID: "user_1", Content: "count: 0"}
+	require.NoError(t, store.Store(ctx, mem))
+
+	attempts := 0
+	err := store.CompareAndSwap(ctx, mem.ID, func(m *Memory) error {
+		attempts++
+		if attempts == 1 {
+			// Simulate a concurrent writer winning the race between our read
+			// and our write, forcing this mutate call to run a second time
+			// against the fresh version.
+			require.NoError(t, store.Update(ctx, mem.ID, &Memory{UserID: "user_1", Content: "count: 1", Version: m.Version}))
+		}
+		m.Content = "count: 2"
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts, "CompareAndSwap should retry once after losing the race")
+
+	got, err := store.Get(ctx, mem.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "count: 2", got.Content)
+}
+
+func TestInMemoryStore_CompareAndSwap_NotFound(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	err := store.CompareAndSwap(ctx, "mem_missing", func(m *Memory) error { return nil })
+	assert.Same(t, ErrNotFound, err)
+}
+
+func TestInMemoryStore_CompareAndSwap_MutateErrorPropagates(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	mem := &Memory{UserID: "user_1", Content: "count: 0"}
+	require.NoError(t, store.Store(ctx, mem))
+
+	mutateErr := errors.New("mutate failed")
+	err := store.CompareAndSwap(ctx, mem.ID, func(m *Memory) error { return mutateErr })
+	assert.Same(t, mutateErr, err)
+}
+
+// slowScorer blocks until unblock is closed, letting tests force Retrieve's
+// deadline path without a real slow Embedder.
+type slowScorer struct {
+	unblock chan struct{}
+}
+
+func (s *slowScorer) Score(ctx context.Context, query string, memories []*Memory) []float64 {
+	select {
+	case <-s.unblock:
+	case <-ctx.Done():
+	}
+	return make([]float64, len(memories))
+}
+
+func TestInMemoryStore_Retrieve_ContextDeadlineExceeded(t *testing.T) {
+	scorer := &slowScorer{unblock: make(chan struct{})}
+	defer close(scorer.unblock)
+	store := NewInMemoryStoreWithConfig(InMemoryStoreConfig{Scorer: scorer})
+	ctx := context.Background()
+
+	require.NoError(t, store.Store(ctx, &Memory{UserID: "user_1", Content: "fact"}))
+
+	deadlineCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	_, err := store.Retrieve(deadlineCtx, RetrieveOptions{UserID: "user_1", Query: "fact"})
+	require.Error(t, err)
+	assert.True(t, IsCode(err, CodeDeadlineExceeded))
+}
+
+func TestInMemoryStore_Retrieve_MaxLatencyAppliesWhenCtxHasNoDeadline(t *testing.T) {
+	scorer := &slowScorer{unblock: make(chan struct{})}
+	defer close(scorer.unblock)
+	store := NewInMemoryStoreWithConfig(InMemoryStoreConfig{Scorer: scorer})
+	ctx := context.Background()
+
+	require.NoError(t, store.Store(ctx, &Memory{UserID: "user_1", Content: "fact"}))
+
+	_, err := store.Retrieve(ctx, RetrieveOptions{UserID: "user_1", Query: "fact", MaxLatency: 10 * time.Millisecond})
+	require.Error(t, err)
+	assert.True(t, IsCode(err, CodeDeadlineExceeded))
+}
+
+func TestInMemoryStore_Retrieve_SucceedsWithinDeadline(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	require.NoError(t, store.Store(ctx, &Memory{UserID: "user_1", Content: "fact about tea"}))
+
+	deadlineCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	results, err := store.Retrieve(deadlineCtx, RetrieveOptions{UserID: "user_1", Query: "tea"})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
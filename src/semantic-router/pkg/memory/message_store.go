@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// MemoryStore persists the flat, per-session turn history that backs
+// extractMemoryInfo's Chat Completions path. Unlike ConversationStore (which
+// persists the Response API's StoredResponse chain, keyed by conversation
+// and response ID), MemoryStore deals directly in Messages and is keyed by
+// sessionID alone - sessionID is already derived by deriveSessionIDFromMessages
+// from (tenant, userID, first message), so it's the canonical per-caller key
+// without a separate userID parameter.
+//
+// Implementations:
+//   - LRUMemoryStore: in-process, capped by session count (message_store_lru.go)
+//   - RedisMemoryStore: a stream per session plus a metadata hash (message_store_redis.go)
+//   - MongoMemoryStore: one collection per tenant, TTL-indexed on last_accessed (message_store_mongo.go)
+type MemoryStore interface {
+	// Get returns a session's stored turn history, oldest first. Returns
+	// ErrConversationNotFound if the session is unknown.
+	Get(ctx context.Context, sessionID string) ([]Message, error)
+
+	// Append adds turns to a session's history, creating the session if it
+	// doesn't exist yet.
+	Append(ctx context.Context, sessionID string, messages []Message) error
+
+	// Delete removes a session's entire history.
+	Delete(ctx context.Context, sessionID string) error
+
+	// List returns the IDs of every session currently stored. Intended for
+	// admin/debugging use, not the request hot path.
+	List(ctx context.Context) ([]string, error)
+
+	// Close releases any underlying resources (connections, etc.).
+	Close() error
+}
+
+// MemoryStoreBackend selects the MemoryStore implementation.
+type MemoryStoreBackend string
+
+const (
+	// MemoryStoreBackendLRU keeps history in an in-process, capped LRU
+	// cache. Needs no external dependency, but history is lost on restart
+	// and isn't shared across router replicas.
+	MemoryStoreBackendLRU MemoryStoreBackend = "lru"
+
+	// MemoryStoreBackendRedis persists history in Redis, shared across
+	// replicas and durable across restarts.
+	MemoryStoreBackendRedis MemoryStoreBackend = "redis"
+
+	// MemoryStoreBackendMongo persists history in MongoDB, one collection
+	// per tenant, with a TTL index to age out stale sessions automatically.
+	MemoryStoreBackendMongo MemoryStoreBackend = "mongo"
+)
+
+// MemoryStoreFactory builds a MemoryStore from a backend's options map.
+// Registered factories let third parties plug in a backend beyond the
+// built-ins without forking this package.
+type MemoryStoreFactory func(options map[string]interface{}) (MemoryStore, error)
+
+var memoryStoreFactories = map[MemoryStoreBackend]MemoryStoreFactory{
+	MemoryStoreBackendLRU: func(options map[string]interface{}) (MemoryStore, error) {
+		return NewLRUMemoryStore(lruMemoryStoreConfigFromOptions(options)), nil
+	},
+	MemoryStoreBackendRedis: func(options map[string]interface{}) (MemoryStore, error) {
+		return NewRedisMemoryStore(redisMemoryStoreConfigFromOptions(options))
+	},
+	MemoryStoreBackendMongo: func(options map[string]interface{}) (MemoryStore, error) {
+		return NewMongoMemoryStore(mongoMemoryStoreConfigFromOptions(options))
+	},
+}
+
+// RegisterMemoryStoreBackend adds (or replaces) the factory used for
+// backend by NewMemoryStoreFromConfig. Intended to be called from an init()
+// in a third-party package that wants its own MemoryStore backend selectable
+// via the same "memory.store.type" config as the built-ins.
+func RegisterMemoryStoreBackend(backend MemoryStoreBackend, factory MemoryStoreFactory) {
+	memoryStoreFactories[backend] = factory
+}
+
+// MemoryStoreConfig configures a MemoryStore built by NewMemoryStoreFromConfig.
+// It mirrors the memory.store.{type,dsn,options} plugin config block: DSN is
+// a backend-specific connection string (Redis address, Mongo URI, ...) and
+// Options carries any remaining backend-specific settings verbatim.
+type MemoryStoreConfig struct {
+	Type    MemoryStoreBackend
+	DSN     string
+	Options map[string]interface{}
+}
+
+// NewMemoryStoreFromConfig builds a MemoryStore from config via the
+// registered factory for config.Type, defaulting to MemoryStoreBackendLRU
+// when Type is unset. DSN is folded into Options under "dsn" so backend
+// factories only need to read one map.
+func NewMemoryStoreFromConfig(config MemoryStoreConfig) (MemoryStore, error) {
+	backend := config.Type
+	if backend == "" {
+		backend = MemoryStoreBackendLRU
+	}
+
+	factory, ok := memoryStoreFactories[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown memory store backend: %s", backend)
+	}
+
+	options := make(map[string]interface{}, len(config.Options)+1)
+	for k, v := range config.Options {
+		options[k] = v
+	}
+	if config.DSN != "" {
+		options["dsn"] = config.DSN
+	}
+
+	logging.Infof("MemoryStore: using %s backend", backend)
+	return factory(options)
+}
+
+// MemoryStoreConfigFromPluginConfig reads the "store" sub-block out of a
+// decision's "memory" plugin Configuration map:
+//
+//	type: memory
+//	configuration:
+//	  store:
+//	    type: redis
+//	    dsn: redis.internal:6379
+//	    options:
+//	      password: ${REDIS_PASSWORD}
+//
+// Returns nil if the plugin config has no "store" block, so callers can
+// treat a nil config as "no MemoryStore configured" and fall back to
+// whatever history the request itself carries.
+func MemoryStoreConfigFromPluginConfig(pluginConfig map[string]interface{}) *MemoryStoreConfig {
+	store, ok := pluginConfig["store"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	cfg := &MemoryStoreConfig{Type: MemoryStoreBackendLRU}
+	if t, ok := store["type"].(string); ok && t != "" {
+		cfg.Type = MemoryStoreBackend(t)
+	}
+	if dsn, ok := store["dsn"].(string); ok {
+		cfg.DSN = dsn
+	}
+	if options, ok := store["options"].(map[string]interface{}); ok {
+		cfg.Options = options
+	}
+	return cfg
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// LRUMemoryStoreConfig configures LRUMemoryStore.
+type LRUMemoryStoreConfig struct {
+	// MaxSessions bounds how many sessions are kept in memory; the
+	// least-recently-used session is evicted once the cap is hit.
+	MaxSessions int
+}
+
+// DefaultLRUMemoryStoreConfig returns sensible defaults for development.
+func DefaultLRUMemoryStoreConfig() LRUMemoryStoreConfig {
+	return LRUMemoryStoreConfig{MaxSessions: 1000}
+}
+
+// lruMemoryStoreConfigFromOptions reads "max_sessions" out of a backend
+// options map, falling back to DefaultLRUMemoryStoreConfig when absent.
+func lruMemoryStoreConfigFromOptions(options map[string]interface{}) LRUMemoryStoreConfig {
+	cfg := DefaultLRUMemoryStoreConfig()
+	if n := intFromConfig(options, "max_sessions"); n > 0 {
+		cfg.MaxSessions = n
+	}
+	return cfg
+}
+
+// LRUMemoryStore is an in-process MemoryStore capped by session count,
+// evicting the least-recently-used session once full. This is the default
+// backend and needs no external dependency.
+type LRUMemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List               // front = most recently used session ID
+	elements map[string]*list.Element // sessionID -> element in order
+	history  map[string][]Message
+}
+
+// NewLRUMemoryStore creates an LRUMemoryStore. A non-positive
+// config.MaxSessions falls back to DefaultLRUMemoryStoreConfig.
+func NewLRUMemoryStore(config LRUMemoryStoreConfig) *LRUMemoryStore {
+	capacity := config.MaxSessions
+	if capacity <= 0 {
+		capacity = DefaultLRUMemoryStoreConfig().MaxSessions
+	}
+	return &LRUMemoryStore{
+		capacity: capacity,
+		order:    list.New(),
+		elements: make(map[string]*list.Element),
+		history:  make(map[string][]Message),
+	}
+}
+
+// Get implements MemoryStore.
+func (s *LRUMemoryStore) Get(ctx context.Context, sessionID string) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages, ok := s.history[sessionID]
+	if !ok {
+		return nil, ErrConversationNotFound
+	}
+	s.touch(sessionID)
+
+	out := make([]Message, len(messages))
+	copy(out, messages)
+	return out, nil
+}
+
+// Append implements MemoryStore.
+func (s *LRUMemoryStore) Append(ctx context.Context, sessionID string, messages []Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history[sessionID] = append(s.history[sessionID], messages...)
+	s.touch(sessionID)
+	s.evictIfNeeded()
+	return nil
+}
+
+// Delete implements MemoryStore.
+func (s *LRUMemoryStore) Delete(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.history, sessionID)
+	if elem, ok := s.elements[sessionID]; ok {
+		s.order.Remove(elem)
+		delete(s.elements, sessionID)
+	}
+	return nil
+}
+
+// List implements MemoryStore.
+func (s *LRUMemoryStore) List(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessionIDs := make([]string, 0, len(s.history))
+	for sessionID := range s.history {
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	return sessionIDs, nil
+}
+
+// Close implements MemoryStore. There are no resources to release.
+func (s *LRUMemoryStore) Close() error { return nil }
+
+// touch marks sessionID as most-recently-used. Caller must hold s.mu.
+func (s *LRUMemoryStore) touch(sessionID string) {
+	if elem, ok := s.elements[sessionID]; ok {
+		s.order.MoveToFront(elem)
+		return
+	}
+	s.elements[sessionID] = s.order.PushFront(sessionID)
+}
+
+// evictIfNeeded drops the least-recently-used session(s) once the store is
+// over capacity. Caller must hold s.mu.
+func (s *LRUMemoryStore) evictIfNeeded() {
+	for len(s.history) > s.capacity {
+		oldest := s.order.Back()
+		if oldest == nil {
+			return
+		}
+		sessionID := oldest.Value.(string)
+		s.order.Remove(oldest)
+		delete(s.elements, sessionID)
+		delete(s.history, sessionID)
+	}
+}
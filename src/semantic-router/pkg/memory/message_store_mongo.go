@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoMemoryStoreConfig configures MongoMemoryStore.
+type MongoMemoryStoreConfig struct {
+	// URI is the MongoDB connection string.
+	URI string
+
+	// Database names the database holding one collection per tenant.
+	Database string
+
+	// Tenant scopes this store instance to a single collection,
+	// "<CollectionPrefix>_<Tenant>" (or just CollectionPrefix when Tenant is
+	// empty), so different tenants' history never share a collection.
+	Tenant string
+
+	// CollectionPrefix names the collection family; defaults to "sessions".
+	CollectionPrefix string
+
+	// TTLSeconds bounds how long a session is retained after its last
+	// access, enforced by a TTL index on last_accessed. Zero disables
+	// expiry (documents live until explicitly Deleted).
+	TTLSeconds int32
+}
+
+// mongoMemoryStoreConfigFromOptions reads MongoMemoryStoreConfig fields out
+// of a backend options map (as produced by memoryStoreConfigFromPluginConfig,
+// with "dsn" standing in for URI).
+func mongoMemoryStoreConfigFromOptions(options map[string]interface{}) MongoMemoryStoreConfig {
+	cfg := MongoMemoryStoreConfig{CollectionPrefix: "sessions"}
+	if dsn, ok := options["dsn"].(string); ok {
+		cfg.URI = dsn
+	}
+	if database, ok := options["database"].(string); ok {
+		cfg.Database = database
+	}
+	if tenant, ok := options["tenant"].(string); ok {
+		cfg.Tenant = tenant
+	}
+	if prefix, ok := options["collection_prefix"].(string); ok && prefix != "" {
+		cfg.CollectionPrefix = prefix
+	}
+	cfg.TTLSeconds = int32(intFromConfig(options, "ttl_seconds"))
+	return cfg
+}
+
+// mongoSessionDocument is the on-disk shape of a stored session.
+type mongoSessionDocument struct {
+	SessionID    string    `bson:"_id"`
+	Messages     []Message `bson:"messages"`
+	LastAccessed time.Time `bson:"last_accessed"`
+}
+
+// MongoMemoryStore is a MongoDB-backed MemoryStore. Each tenant gets its own
+// collection (collection-per-tenant), and a TTL index on last_accessed
+// expires idle sessions automatically so retention doesn't need a separate
+// reaper job.
+type MongoMemoryStore struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+}
+
+// NewMongoMemoryStore connects to MongoDB per config, ensures the
+// collection's TTL index exists, and returns a MongoMemoryStore.
+func NewMongoMemoryStore(config MongoMemoryStoreConfig) (*MongoMemoryStore, error) {
+	if config.URI == "" {
+		return nil, fmt.Errorf("mongo memory store requires a dsn (connection URI)")
+	}
+	if config.Database == "" {
+		return nil, fmt.Errorf("mongo memory store requires a database name")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(config.URI))
+	if err != nil {
+		return nil, fmt.Errorf("mongo connect failed: %w", err)
+	}
+
+	collectionName := config.CollectionPrefix
+	if config.Tenant != "" {
+		collectionName = collectionName + "_" + config.Tenant
+	}
+	collection := client.Database(config.Database).Collection(collectionName)
+
+	if config.TTLSeconds > 0 {
+		_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "last_accessed", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(config.TTLSeconds),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("mongo TTL index creation failed: %w", err)
+		}
+	}
+
+	return &MongoMemoryStore{client: client, collection: collection}, nil
+}
+
+// Get implements MemoryStore.
+func (s *MongoMemoryStore) Get(ctx context.Context, sessionID string) ([]Message, error) {
+	var doc mongoSessionDocument
+	err := s.collection.FindOne(ctx, bson.M{"_id": sessionID}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mongo FindOne failed: %w", err)
+	}
+	return doc.Messages, nil
+}
+
+// Append implements MemoryStore.
+func (s *MongoMemoryStore) Append(ctx context.Context, sessionID string, messages []Message) error {
+	if sessionID == "" {
+		return fmt.Errorf("sessionID is required")
+	}
+
+	_, err := s.collection.UpdateOne(ctx,
+		bson.M{"_id": sessionID},
+		bson.M{
+			"$push": bson.M{"messages": bson.M{"$each": messages}},
+			"$set":  bson.M{"last_accessed": time.Now()},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("mongo UpdateOne failed: %w", err)
+	}
+	return nil
+}
+
+// Delete implements MemoryStore.
+func (s *MongoMemoryStore) Delete(ctx context.Context, sessionID string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"_id": sessionID})
+	if err != nil {
+		return fmt.Errorf("mongo DeleteOne failed: %w", err)
+	}
+	return nil
+}
+
+// List implements MemoryStore. It's meant for admin/debugging use, not the
+// request hot path.
+func (s *MongoMemoryStore) List(ctx context.Context) ([]string, error) {
+	cursor, err := s.collection.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("mongo Find failed: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var sessionIDs []string
+	for cursor.Next(ctx) {
+		var doc struct {
+			SessionID string `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("mongo cursor decode failed: %w", err)
+		}
+		sessionIDs = append(sessionIDs, doc.SessionID)
+	}
+	return sessionIDs, cursor.Err()
+}
+
+// Close implements MemoryStore.
+func (s *MongoMemoryStore) Close() error {
+	return s.client.Disconnect(context.Background())
+}
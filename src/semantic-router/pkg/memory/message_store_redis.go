@@ -0,0 +1,180 @@
+package memory
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// RedisMemoryStoreConfig configures RedisMemoryStore.
+type RedisMemoryStoreConfig struct {
+	// Addr is the Redis address ("host:port"). When Cluster is true, it may
+	// be a comma-separated list of cluster node addresses.
+	Addr string
+
+	// Password authenticates with Redis AUTH / ACL. Empty disables auth.
+	Password string
+
+	// TLS enables TLS for the connection.
+	TLS bool
+
+	// Cluster switches to a Redis Cluster client instead of a single-node one.
+	Cluster bool
+}
+
+// redisMemoryStoreConfigFromOptions reads RedisMemoryStoreConfig fields out
+// of a backend options map (as produced by memoryStoreConfigFromPluginConfig,
+// with "dsn" standing in for Addr).
+func redisMemoryStoreConfigFromOptions(options map[string]interface{}) RedisMemoryStoreConfig {
+	var cfg RedisMemoryStoreConfig
+	if dsn, ok := options["dsn"].(string); ok {
+		cfg.Addr = dsn
+	}
+	if password, ok := options["password"].(string); ok {
+		cfg.Password = password
+	}
+	if tlsEnabled, ok := options["tls"].(bool); ok {
+		cfg.TLS = tlsEnabled
+	}
+	if cluster, ok := options["cluster"].(bool); ok {
+		cfg.Cluster = cluster
+	}
+	return cfg
+}
+
+// redisStreamClient is the subset of go-redis's Cmdable RedisMemoryStore
+// needs, plus Close.
+type redisStreamClient interface {
+	XAdd(ctx context.Context, a *goredis.XAddArgs) *goredis.StringCmd
+	XRange(ctx context.Context, stream, start, stop string) *goredis.XMessageSliceCmd
+	Del(ctx context.Context, keys ...string) *goredis.IntCmd
+	Keys(ctx context.Context, pattern string) *goredis.StringSliceCmd
+	HSet(ctx context.Context, key string, values ...interface{}) *goredis.IntCmd
+	Close() error
+}
+
+// RedisMemoryStore is a Redis-backed MemoryStore, shared across router
+// replicas and durable across restarts. Each session's turns live in a
+// Redis Stream (append-only, ordered by entry ID), with a companion hash
+// tracking per-session metadata (currently just last_appended_at, bumped on
+// every Append so an external reaper can age out idle sessions).
+type RedisMemoryStore struct {
+	client redisStreamClient
+}
+
+// NewRedisMemoryStore connects to Redis per config and returns a
+// RedisMemoryStore. Supports password auth, TLS, and cluster mode.
+func NewRedisMemoryStore(config RedisMemoryStoreConfig) (*RedisMemoryStore, error) {
+	if config.Addr == "" {
+		return nil, fmt.Errorf("redis memory store requires addr")
+	}
+
+	var tlsConfig *tls.Config
+	if config.TLS {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	var client redisStreamClient
+	if config.Cluster {
+		client = goredis.NewClusterClient(&goredis.ClusterOptions{
+			Addrs:     strings.Split(config.Addr, ","),
+			Password:  config.Password,
+			TLSConfig: tlsConfig,
+		})
+	} else {
+		client = goredis.NewClient(&goredis.Options{
+			Addr:      config.Addr,
+			Password:  config.Password,
+			TLSConfig: tlsConfig,
+		})
+	}
+
+	return &RedisMemoryStore{client: client}, nil
+}
+
+func memoryStreamKey(sessionID string) string   { return "semantic-router:mem:stream:" + sessionID }
+func memoryMetadataKey(sessionID string) string { return "semantic-router:mem:meta:" + sessionID }
+
+// Get implements MemoryStore.
+func (s *RedisMemoryStore) Get(ctx context.Context, sessionID string) ([]Message, error) {
+	entries, err := s.client.XRange(ctx, memoryStreamKey(sessionID), "-", "+").Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis XRange failed: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, ErrConversationNotFound
+	}
+
+	messages := make([]Message, 0, len(entries))
+	for _, entry := range entries {
+		raw, ok := entry.Values["message"].(string)
+		if !ok {
+			continue
+		}
+		var msg Message
+		if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+			return nil, fmt.Errorf("malformed stored message: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// Append implements MemoryStore.
+func (s *RedisMemoryStore) Append(ctx context.Context, sessionID string, messages []Message) error {
+	if sessionID == "" {
+		return fmt.Errorf("sessionID is required")
+	}
+
+	for _, msg := range messages {
+		encoded, err := json.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("failed to marshal message: %w", err)
+		}
+		if err := s.client.XAdd(ctx, &goredis.XAddArgs{
+			Stream: memoryStreamKey(sessionID),
+			Values: map[string]interface{}{"message": encoded},
+		}).Err(); err != nil {
+			return fmt.Errorf("redis XAdd failed: %w", err)
+		}
+	}
+
+	if err := s.client.HSet(ctx, memoryMetadataKey(sessionID), "last_appended_at", time.Now().Unix()).Err(); err != nil {
+		return fmt.Errorf("redis HSet failed: %w", err)
+	}
+	return nil
+}
+
+// Delete implements MemoryStore.
+func (s *RedisMemoryStore) Delete(ctx context.Context, sessionID string) error {
+	if err := s.client.Del(ctx, memoryStreamKey(sessionID), memoryMetadataKey(sessionID)).Err(); err != nil {
+		return fmt.Errorf("redis Del failed: %w", err)
+	}
+	return nil
+}
+
+// List implements MemoryStore. It scans for stream keys, so it's meant for
+// admin/debugging use against a single node, not a request-path hot loop.
+func (s *RedisMemoryStore) List(ctx context.Context) ([]string, error) {
+	keys, err := s.client.Keys(ctx, memoryStreamKey("*")).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis Keys failed: %w", err)
+	}
+
+	prefix := memoryStreamKey("")
+	sessionIDs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		sessionIDs = append(sessionIDs, strings.TrimPrefix(key, prefix))
+	}
+	return sessionIDs, nil
+}
+
+// Close implements MemoryStore.
+func (s *RedisMemoryStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLRUMemoryStore_AppendAndGet(t *testing.T) {
+	store := NewLRUMemoryStore(DefaultLRUMemoryStoreConfig())
+	ctx := context.Background()
+
+	if err := store.Append(ctx, "sess_1", []Message{{Role: RoleUser, Content: "hi"}}); err != nil {
+		t.Fatalf("Append returned error: %v", err)
+	}
+
+	got, err := store.Get(ctx, "sess_1")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if len(got) != 1 || got[0].Content != "hi" {
+		t.Fatalf("Get returned %+v, want one message with content 'hi'", got)
+	}
+}
+
+func TestLRUMemoryStore_GetUnknownSession(t *testing.T) {
+	store := NewLRUMemoryStore(DefaultLRUMemoryStoreConfig())
+
+	_, err := store.Get(context.Background(), "missing")
+	if err != ErrConversationNotFound {
+		t.Fatalf("Get(missing) error = %v, want ErrConversationNotFound", err)
+	}
+}
+
+func TestLRUMemoryStore_DeleteAndList(t *testing.T) {
+	store := NewLRUMemoryStore(DefaultLRUMemoryStoreConfig())
+	ctx := context.Background()
+
+	_ = store.Append(ctx, "sess_1", []Message{{Role: RoleUser, Content: "hi"}})
+	_ = store.Append(ctx, "sess_2", []Message{{Role: RoleUser, Content: "hey"}})
+
+	ids, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("List returned %d sessions, want 2", len(ids))
+	}
+
+	if err := store.Delete(ctx, "sess_1"); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if _, err := store.Get(ctx, "sess_1"); err != ErrConversationNotFound {
+		t.Fatalf("Get after Delete error = %v, want ErrConversationNotFound", err)
+	}
+}
+
+func TestLRUMemoryStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	store := NewLRUMemoryStore(LRUMemoryStoreConfig{MaxSessions: 1})
+	ctx := context.Background()
+
+	_ = store.Append(ctx, "sess_1", []Message{{Role: RoleUser, Content: "first"}})
+	_ = store.Append(ctx, "sess_2", []Message{{Role: RoleUser, Content: "second"}})
+
+	if _, err := store.Get(ctx, "sess_1"); err != ErrConversationNotFound {
+		t.Fatalf("sess_1 should have been evicted, got err=%v", err)
+	}
+	if _, err := store.Get(ctx, "sess_2"); err != nil {
+		t.Fatalf("sess_2 should still be present, got err=%v", err)
+	}
+}
+
+func TestMemoryStoreConfigFromPluginConfig_NoStoreBlock(t *testing.T) {
+	if cfg := MemoryStoreConfigFromPluginConfig(map[string]interface{}{}); cfg != nil {
+		t.Fatalf("expected nil config without a store block, got %+v", cfg)
+	}
+	if cfg := MemoryStoreConfigFromPluginConfig(nil); cfg != nil {
+		t.Fatalf("expected nil config for nil plugin config, got %+v", cfg)
+	}
+}
+
+func TestMemoryStoreConfigFromPluginConfig_ParsesStoreBlock(t *testing.T) {
+	cfg := MemoryStoreConfigFromPluginConfig(map[string]interface{}{
+		"store": map[string]interface{}{
+			"type": "redis",
+			"dsn":  "redis.internal:6379",
+			"options": map[string]interface{}{
+				"password": "secret",
+			},
+		},
+	})
+	if cfg == nil {
+		t.Fatal("expected a non-nil config")
+	}
+	if cfg.Type != MemoryStoreBackendRedis {
+		t.Errorf("Type = %q, want %q", cfg.Type, MemoryStoreBackendRedis)
+	}
+	if cfg.DSN != "redis.internal:6379" {
+		t.Errorf("DSN = %q, want redis.internal:6379", cfg.DSN)
+	}
+	if cfg.Options["password"] != "secret" {
+		t.Errorf("Options[password] = %v, want secret", cfg.Options["password"])
+	}
+}
+
+func TestNewMemoryStoreFromConfig_DefaultsToLRU(t *testing.T) {
+	store, err := NewMemoryStoreFromConfig(MemoryStoreConfig{})
+	if err != nil {
+		t.Fatalf("NewMemoryStoreFromConfig returned error: %v", err)
+	}
+	if _, ok := store.(*LRUMemoryStore); !ok {
+		t.Fatalf("store = %T, want *LRUMemoryStore", store)
+	}
+}
+
+func TestNewMemoryStoreFromConfig_UnknownBackend(t *testing.T) {
+	_, err := NewMemoryStoreFromConfig(MemoryStoreConfig{Type: "nope"})
+	if err == nil {
+		t.Fatal("expected an error for an unknown backend")
+	}
+}
+
+func TestRegisterMemoryStoreBackend(t *testing.T) {
+	called := false
+	RegisterMemoryStoreBackend("custom", func(options map[string]interface{}) (MemoryStore, error) {
+		called = true
+		return NewLRUMemoryStore(DefaultLRUMemoryStoreConfig()), nil
+	})
+
+	if _, err := NewMemoryStoreFromConfig(MemoryStoreConfig{Type: "custom"}); err != nil {
+		t.Fatalf("NewMemoryStoreFromConfig returned error: %v", err)
+	}
+	if !called {
+		t.Fatal("expected the registered factory to be invoked")
+	}
+}
@@ -0,0 +1,103 @@
+//go:build !windows && cgo
+
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// accessFlushInterval bounds how long an accessed_at bump can sit pending
+// before it's written back, so RecencyLastAccess scoring doesn't drift too far
+// from reality without a round-trip on every single Get/Retrieve hit.
+const accessFlushInterval = 10 * time.Second
+
+// accessRecorder batches accessed_at bumps from Get/Retrieve hits and
+// periodically upserts them, so hot-path reads don't pay for a write on every
+// call. It's best-effort: a failed flush just logs and drops the batch.
+type accessRecorder struct {
+	client         client.Client
+	collectionName string
+
+	mu      sync.Mutex
+	pending map[string]int64 // memory ID -> last accessed unix timestamp
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newAccessRecorder(c client.Client, collectionName string) *accessRecorder {
+	a := &accessRecorder{
+		client:         c,
+		collectionName: collectionName,
+		pending:        make(map[string]int64),
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// Record marks a memory as accessed at the given time, to be flushed on the
+// next tick.
+func (a *accessRecorder) Record(id string, at time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.pending[id] = at.Unix()
+}
+
+func (a *accessRecorder) run() {
+	defer close(a.done)
+	ticker := time.NewTicker(accessFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.flush()
+		case <-a.stop:
+			a.flush()
+			return
+		}
+	}
+}
+
+func (a *accessRecorder) flush() {
+	a.mu.Lock()
+	if len(a.pending) == 0 {
+		a.mu.Unlock()
+		return
+	}
+	batch := a.pending
+	a.pending = make(map[string]int64)
+	a.mu.Unlock()
+
+	ids := make([]string, 0, len(batch))
+	accessedAts := make([]int64, 0, len(batch))
+	for id, at := range batch {
+		ids = append(ids, id)
+		accessedAts = append(accessedAts, at)
+	}
+
+	// Partial-column upsert (id + accessed_at only) requires Milvus 2.4+.
+	columns := []entity.Column{
+		entity.NewColumnVarChar("id", ids),
+		entity.NewColumnInt64("accessed_at", accessedAts),
+	}
+
+	if _, err := a.client.Upsert(context.Background(), a.collectionName, "", columns...); err != nil {
+		logging.Warnf("MilvusMemoryStore: failed to flush %d accessed_at updates: %v", len(ids), err)
+	}
+}
+
+// Stop flushes any pending updates and stops the background goroutine.
+func (a *accessRecorder) Stop() {
+	close(a.stop)
+	<-a.done
+}
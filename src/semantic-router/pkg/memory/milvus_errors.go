@@ -0,0 +1,197 @@
+//go:build !windows && cgo
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// DefaultMaxRetries bounds how many times a read operation is retried after a
+// transient failure before giving up.
+const DefaultMaxRetries = 3
+
+// retryBackoff is the delay between retry attempts. Kept small and fixed
+// rather than exponential since Milvus transient errors (load shedding,
+// brief unavailability) tend to clear in well under a second.
+const retryBackoff = 50 * time.Millisecond
+
+// ErrCollectionNotExists indicates the target collection doesn't exist yet.
+// Unlike a network blip, this isn't fixed by retrying - callers should treat
+// it as a signal to (re)run initializeCollection.
+var ErrCollectionNotExists = errors.New("milvus: collection does not exist")
+
+// ErrIndexNotExist indicates the collection exists but lacks the index a
+// search needs.
+var ErrIndexNotExist = errors.New("milvus: index does not exist")
+
+// StatusError wraps a Milvus/gRPC error with the status code it was
+// classified from, so callers can use errors.Is/errors.As instead of matching
+// on error text.
+type StatusError struct {
+	Code   codes.Code
+	Reason error // one of ErrCollectionNotExists, ErrIndexNotExist, or nil
+	Err    error
+}
+
+func (e *StatusError) Error() string {
+	if e.Reason != nil {
+		return fmt.Sprintf("milvus: %s (%s)", e.Reason, e.Code)
+	}
+	return fmt.Sprintf("milvus: %s (%s)", e.Err, e.Code)
+}
+
+func (e *StatusError) Unwrap() error {
+	if e.Reason != nil {
+		return e.Reason
+	}
+	return e.Err
+}
+
+// classifyError turns a raw error from the Milvus client into a *StatusError
+// when it carries a gRPC status or a recognizable Milvus error reason,
+// returning nil if err is nil.
+func classifyError(err error) *StatusError {
+	if err == nil {
+		return nil
+	}
+
+	se := &StatusError{Code: codes.Unknown, Err: err}
+
+	if st, ok := status.FromError(err); ok {
+		se.Code = st.Code()
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "collection not exist") || strings.Contains(msg, "collectionnotexists"):
+		se.Reason = ErrCollectionNotExists
+		se.Code = codes.NotFound
+	case strings.Contains(msg, "index not exist") || strings.Contains(msg, "indexnotexist"):
+		se.Reason = ErrIndexNotExist
+		se.Code = codes.NotFound
+	}
+
+	return se
+}
+
+// isTransientError reports whether err is worth retrying. Milvus client
+// errors are classified by gRPC status code; plain errors (e.g. from a mock
+// or a bare network failure) fall back to matching on common transient
+// phrasing so callers without a gRPC status still get sensible behavior.
+func isTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	se := classifyError(err)
+	if se.Reason != nil {
+		return false // collection/index missing is never transient
+	}
+
+	switch se.Code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	case codes.NotFound, codes.InvalidArgument, codes.PermissionDenied, codes.FailedPrecondition:
+		return false
+	}
+
+	// No usable gRPC status (e.g. a plain error) - fall back to text matching.
+	msg := strings.ToLower(err.Error())
+	for _, phrase := range []string{"connection refused", "deadline exceeded", "timeout", "unavailable", "connection reset", "eof"} {
+		if strings.Contains(msg, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// withRetry calls fn up to DefaultMaxRetries times, retrying only on
+// transient errors and aborting immediately if ctx is done. fn is expected to
+// assign its result to a variable captured in the closure.
+func withRetry(ctx context.Context, fn func() error) error {
+	var lastErr error
+
+	for attempt := 0; attempt < DefaultMaxRetries; attempt++ {
+		if ctx.Err() != nil {
+			return fmt.Errorf("context cancelled: %w", ctx.Err())
+		}
+
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !isTransientError(err) {
+			return err
+		}
+		if attempt < DefaultMaxRetries-1 {
+			time.Sleep(retryBackoff)
+		}
+	}
+
+	return lastErr
+}
+
+// connectWithRetry dials config.Address with exponential backoff, so a
+// startup race between Milvus and the router (common when both come up
+// together in Kubernetes) doesn't permanently disable memory just because
+// the first dial landed before Milvus was ready. ConnectRetryAttempts <= 1
+// makes a single attempt, preserving the original one-shot behavior for
+// configs that don't opt in. apiKey, when non-empty, authenticates the
+// connection - the current value of config.CredentialSource's credential,
+// if one is configured.
+func connectWithRetry(ctx context.Context, config *MilvusStoreConfig, apiKey string) (client.Client, error) {
+	attempts := config.ConnectRetryAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	backoff := config.ConnectRetryInitialBackoff
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+	maxBackoff := config.ConnectRetryMaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 10 * time.Second
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("context cancelled: %w", ctx.Err())
+		}
+
+		var c client.Client
+		var err error
+		if apiKey != "" {
+			c, err = client.NewDefaultGrpcClientWithAPIKey(ctx, config.Address, apiKey)
+		} else {
+			c, err = client.NewGrpcClient(ctx, config.Address)
+		}
+		if err == nil {
+			return c, nil
+		}
+		lastErr = err
+
+		if attempt < attempts-1 {
+			logging.Warnf("MilvusMemoryStore: connect attempt %d/%d to %s failed: %v, retrying in %s",
+				attempt+1, attempts, config.Address, err, backoff)
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+	}
+
+	return nil, lastErr
+}
@@ -0,0 +1,54 @@
+//go:build !windows && cgo
+
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsTransientError_GRPCCodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{"unavailable", status.Error(codes.Unavailable, "server down"), true},
+		{"deadline exceeded", status.Error(codes.DeadlineExceeded, "timed out"), true},
+		{"resource exhausted", status.Error(codes.ResourceExhausted, "rate limited"), true},
+		{"aborted", status.Error(codes.Aborted, "conflict"), true},
+		{"not found", status.Error(codes.NotFound, "no such row"), false},
+		{"invalid argument", status.Error(codes.InvalidArgument, "bad filter"), false},
+		{"permission denied", status.Error(codes.PermissionDenied, "no access"), false},
+		{"failed precondition", status.Error(codes.FailedPrecondition, "not loaded"), false},
+		{"collection not exist", errors.New("CollectionNotExists: collection not exist"), false},
+		{"index not exist", errors.New("IndexNotExist: index not exist"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientError(tt.err); got != tt.transient {
+				t.Errorf("isTransientError(%v) = %v, want %v", tt.err, got, tt.transient)
+			}
+		})
+	}
+}
+
+func TestClassifyError_CollectionNotExists(t *testing.T) {
+	err := errors.New("rpc error: code = Unknown desc = CollectionNotExists: collection foo not exist")
+	se := classifyError(err)
+
+	if !errors.Is(se, ErrCollectionNotExists) {
+		t.Errorf("expected classifyError to unwrap to ErrCollectionNotExists, got %v", se.Unwrap())
+	}
+}
+
+func TestStatusError_Unwrap(t *testing.T) {
+	se := &StatusError{Code: codes.Unavailable, Err: errors.New("boom")}
+	if !errors.Is(se, se.Err) {
+		t.Errorf("expected StatusError to unwrap to its underlying error")
+	}
+}
@@ -0,0 +1,148 @@
+//go:build !windows && cgo
+
+package memory
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// defaultPartitionCacheSize bounds the LRU cache of known partitions when the
+// config does not specify one.
+const defaultPartitionCacheSize = 1024
+
+// partitionCache is a simple thread-safe LRU of partition names known to exist,
+// so EnsurePartition doesn't need a HasPartition round-trip on every call.
+type partitionCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func newPartitionCache(size int) *partitionCache {
+	if size <= 0 {
+		size = defaultPartitionCacheSize
+	}
+	return &partitionCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Has returns true if the partition is known to already exist.
+func (c *partitionCache) Has(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[name]
+	if !ok {
+		return false
+	}
+	c.order.MoveToFront(elem)
+	return true
+}
+
+// Add records a partition as known to exist, evicting the least-recently-used
+// entry if the cache is full.
+func (c *partitionCache) Add(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[name]; ok {
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(name)
+	c.entries[name] = elem
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(string))
+		}
+	}
+}
+
+// Remove forgets a partition, e.g. after it has been dropped.
+func (c *partitionCache) Remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[name]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, name)
+	}
+}
+
+// partitionKeyFor resolves the Milvus partition name for a memory scope from
+// MilvusStoreConfig.PartitionKey, e.g. "{user_id}" or "{user_id}:{project_id}".
+// Returns "" (meaning: default/unpartitioned) when partitioning is disabled or
+// Milvus 2.3+ partition-key fields are used instead (Milvus routes those itself).
+func (s *MilvusStore) partitionKeyFor(userID, projectID string) string {
+	if !s.config.Partitioned || s.config.UsePartitionKeyField {
+		return ""
+	}
+
+	template := s.config.PartitionKey
+	if template == "" {
+		template = "{user_id}"
+	}
+
+	key := strings.NewReplacer(
+		"{user_id}", sanitizePartitionComponent(userID),
+		"{project_id}", sanitizePartitionComponent(projectID),
+	).Replace(template)
+
+	return key
+}
+
+// sanitizePartitionComponent strips characters Milvus partition names disallow
+// (they must be valid identifiers) so a raw user_id/project_id can be used directly.
+func sanitizePartitionComponent(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// EnsurePartition creates the given partition if it doesn't already exist,
+// checking the local LRU cache first to avoid a HasPartition round-trip.
+func (s *MilvusStore) EnsurePartition(ctx context.Context, partition string) error {
+	if partition == "" {
+		return nil
+	}
+
+	if s.partitions.Has(partition) {
+		return nil
+	}
+
+	exists, err := s.client.HasPartition(ctx, s.collectionName, partition)
+	if err != nil {
+		return fmt.Errorf("failed to check partition %q: %w", partition, err)
+	}
+
+	if !exists {
+		if err := s.client.CreatePartition(ctx, s.collectionName, partition); err != nil {
+			return fmt.Errorf("failed to create partition %q: %w", partition, err)
+		}
+		logging.Infof("MilvusMemoryStore: created partition %q", partition)
+	}
+
+	s.partitions.Add(partition)
+	return nil
+}
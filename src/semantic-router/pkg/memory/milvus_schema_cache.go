@@ -0,0 +1,160 @@
+//go:build !windows && cgo
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// defaultSchemaCacheTTL bounds how long a collection's existence/schema is
+// trusted before the next call re-verifies it with Milvus. Mirrors the
+// Milvus proxy's MetaCache, sized for a control-plane value that rarely
+// changes mid-process.
+const defaultSchemaCacheTTL = 5 * time.Minute
+
+// schemaCacheEntry is what schemaCache memoizes per collection.
+type schemaCacheEntry struct {
+	exists    bool
+	collInfo  *entity.Collection
+	loadState entity.LoadState
+	expiresAt time.Time
+}
+
+// schemaCache memoizes HasCollection/DescribeCollection/GetLoadState results
+// per collection name, with a TTL and a single-flight guard so concurrent
+// cache misses collapse into one set of RPCs instead of one each.
+type schemaCache struct {
+	ttl time.Duration
+
+	mu       sync.Mutex
+	entries  map[string]schemaCacheEntry
+	inflight map[string]chan struct{}
+}
+
+func newSchemaCache(ttl time.Duration) *schemaCache {
+	if ttl <= 0 {
+		ttl = defaultSchemaCacheTTL
+	}
+	return &schemaCache{
+		ttl:      ttl,
+		entries:  make(map[string]schemaCacheEntry),
+		inflight: make(map[string]chan struct{}),
+	}
+}
+
+// invalidate drops a cached entry, forcing the next lookup to re-describe the
+// collection. Called when Search/Insert return a CollectionNotExists or
+// schema-mismatch error, so migrations don't require a process restart.
+func (c *schemaCache) invalidate(name string) {
+	c.mu.Lock()
+	delete(c.entries, name)
+	c.mu.Unlock()
+}
+
+// lookup returns the cached entry for name if fresh, fetching (and caching)
+// it otherwise. Concurrent misses for the same name collapse into a single
+// describe, with every caller waiting on the same in-flight fetch.
+func (c *schemaCache) lookup(ctx context.Context, name string, describe func(ctx context.Context) (schemaCacheEntry, error)) (schemaCacheEntry, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[name]; ok && time.Now().Before(entry.expiresAt) {
+		c.mu.Unlock()
+		return entry, nil
+	}
+
+	if wait, ok := c.inflight[name]; ok {
+		c.mu.Unlock()
+		<-wait
+		c.mu.Lock()
+		entry, ok := c.entries[name]
+		c.mu.Unlock()
+		if !ok {
+			return schemaCacheEntry{}, fmt.Errorf("schema cache: describe for %q failed in another goroutine", name)
+		}
+		return entry, nil
+	}
+
+	done := make(chan struct{})
+	c.inflight[name] = done
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, name)
+		c.mu.Unlock()
+		close(done)
+	}()
+
+	entry, err := describe(ctx)
+	if err != nil {
+		return schemaCacheEntry{}, err
+	}
+	entry.expiresAt = time.Now().Add(c.ttl)
+
+	c.mu.Lock()
+	c.entries[name] = entry
+	c.mu.Unlock()
+
+	return entry, nil
+}
+
+// describeCollection populates a schemaCacheEntry for the store's collection,
+// consulting the cache first (see schemaCache.lookup) and only falling back
+// to HasCollection/DescribeCollection/GetLoadState on a cold or expired entry.
+func (s *MilvusStore) describeCollection(ctx context.Context) (schemaCacheEntry, error) {
+	return s.schema.lookup(ctx, s.collectionName, func(ctx context.Context) (schemaCacheEntry, error) {
+		exists, err := s.client.HasCollection(ctx, s.collectionName)
+		if err != nil {
+			return schemaCacheEntry{}, fmt.Errorf("failed to check collection: %w", err)
+		}
+		if !exists {
+			return schemaCacheEntry{exists: false}, nil
+		}
+
+		collInfo, err := s.client.DescribeCollection(ctx, s.collectionName)
+		if err != nil {
+			return schemaCacheEntry{}, fmt.Errorf("failed to describe collection: %w", err)
+		}
+
+		loadState, err := s.client.GetLoadState(ctx, s.collectionName, nil)
+		if err != nil {
+			return schemaCacheEntry{}, fmt.Errorf("failed to get load state: %w", err)
+		}
+
+		return schemaCacheEntry{exists: true, collInfo: collInfo, loadState: loadState}, nil
+	})
+}
+
+// withSchemaRetry runs fn, and if it fails with a collection-missing or
+// schema-mismatch error, invalidates the cached schema and retries fn exactly
+// once with a freshly-described collection - so a migration applied out of
+// band is picked up without restarting the process.
+func (s *MilvusStore) withSchemaRetry(ctx context.Context, fn func() error) error {
+	err := fn()
+	if err == nil || !isSchemaError(err) {
+		return err
+	}
+
+	s.schema.invalidate(s.collectionName)
+	if _, descErr := s.describeCollection(ctx); descErr != nil {
+		return err // surface the original error; the re-describe itself failed
+	}
+	return fn()
+}
+
+// isSchemaError reports whether err indicates the cached schema is stale -
+// the collection was dropped/recreated or its fields changed underneath us.
+func isSchemaError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if se := classifyError(err); se.Reason != nil {
+		return true // ErrCollectionNotExists or ErrIndexNotExist
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "schema mismatch")
+}
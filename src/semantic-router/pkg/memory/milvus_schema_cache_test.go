@@ -0,0 +1,66 @@
+//go:build !windows && cgo
+
+package memory
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSchemaCache_CollapsesConcurrentMisses(t *testing.T) {
+	cache := newSchemaCache(time.Minute)
+	var describeCalls int32
+
+	describe := func(ctx context.Context) (schemaCacheEntry, error) {
+		atomic.AddInt32(&describeCalls, 1)
+		time.Sleep(10 * time.Millisecond) // widen the race window
+		return schemaCacheEntry{exists: true}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.lookup(context.Background(), "coll", describe); err != nil {
+				t.Errorf("lookup failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&describeCalls); got != 1 {
+		t.Errorf("expected exactly 1 describe call across concurrent misses, got %d", got)
+	}
+}
+
+func TestSchemaCache_InvalidateForcesRedescribe(t *testing.T) {
+	cache := newSchemaCache(time.Minute)
+	var describeCalls int32
+
+	describe := func(ctx context.Context) (schemaCacheEntry, error) {
+		atomic.AddInt32(&describeCalls, 1)
+		return schemaCacheEntry{exists: true}, nil
+	}
+
+	if _, err := cache.lookup(context.Background(), "coll", describe); err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	if _, err := cache.lookup(context.Background(), "coll", describe); err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&describeCalls); got != 1 {
+		t.Errorf("expected cached second lookup to skip describe, got %d calls", got)
+	}
+
+	cache.invalidate("coll")
+	if _, err := cache.lookup(context.Background(), "coll", describe); err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&describeCalls); got != 2 {
+		t.Errorf("expected invalidate to force a re-describe, got %d calls", got)
+	}
+}
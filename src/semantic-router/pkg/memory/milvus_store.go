@@ -5,6 +5,10 @@ package memory
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +20,67 @@ import (
 	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
 )
 
+// HybridSearchMode selects how dense and sparse retrieval are combined.
+type HybridSearchMode string
+
+const (
+	// HybridModeOff uses dense-only search (current behavior).
+	HybridModeOff HybridSearchMode = "off"
+	// HybridModeDense forces dense-only search even if a sparse encoder is configured.
+	HybridModeDense HybridSearchMode = "dense"
+	// HybridModeSparse uses sparse-only search.
+	HybridModeSparse HybridSearchMode = "sparse"
+	// HybridModeRRF fuses dense and sparse results with Reciprocal Rank Fusion.
+	HybridModeRRF HybridSearchMode = "rrf"
+)
+
+// rrfK is the RRF smoothing constant (see Cormack et al., "Reciprocal Rank Fusion").
+const rrfK = 60.0
+
+// mmrOverfetchFactor controls how many extra candidates are pulled from Milvus
+// when MMR re-ranking is requested, so there's a pool to diversify over.
+const mmrOverfetchFactor = 4
+
+// SparseEncoder produces a sparse (BM25-style) vector for a piece of text.
+// The returned map is keyed by token hash (a uint32 bucket) to a TF weight.
+// Callers can provide their own implementation (e.g. a real BM25 index) via
+// MilvusStoreConfig.SparseEncoder; DefaultSparseEncoder is used otherwise.
+type SparseEncoder interface {
+	Encode(text string) map[uint32]float32
+}
+
+// hashedTokenEncoder is a simple hashed-token TF encoder used as the default
+// SparseEncoder. It is deliberately simple (no IDF, no stemming) - good enough
+// to catch rare-term queries (names, IDs, code snippets) that dense embeddings miss.
+type hashedTokenEncoder struct{}
+
+// DefaultSparseEncoder returns the built-in hashed-token TF SparseEncoder.
+func DefaultSparseEncoder() SparseEncoder {
+	return hashedTokenEncoder{}
+}
+
+func (hashedTokenEncoder) Encode(text string) map[uint32]float32 {
+	tokens := strings.Fields(strings.ToLower(text))
+	weights := make(map[uint32]float32, len(tokens))
+	for _, tok := range tokens {
+		h := hashToken(tok)
+		weights[h]++
+	}
+	// Normalize by token count so longer content doesn't dominate the fusion score.
+	if len(tokens) > 0 {
+		for k, v := range weights {
+			weights[k] = v / float32(len(tokens))
+		}
+	}
+	return weights
+}
+
+func hashToken(tok string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(tok))
+	return h.Sum32()
+}
+
 // MilvusStoreConfig configures the Milvus memory store
 type MilvusStoreConfig struct {
 	// Connection
@@ -33,18 +98,244 @@ type MilvusStoreConfig struct {
 	EfConstruction int // HNSW build parameter (default: 256)
 	M              int // HNSW connections (default: 16)
 	Ef             int // HNSW search parameter (default: 64)
+
+	// HybridMode selects dense-only, sparse-only, or RRF fusion search.
+	// Defaults to HybridModeOff (dense-only, current behavior).
+	HybridMode HybridSearchMode
+
+	// SparseEncoder builds the sparse vector stored alongside the dense embedding.
+	// Defaults to DefaultSparseEncoder() (hashed-token TF) when HybridMode != off.
+	SparseEncoder SparseEncoder
+
+	// SparseWeight/DenseWeight configure a linear-combination fusion alternative
+	// to RRF. Only used when HybridMode == HybridModeRRF and both are non-zero.
+	SparseWeight float32
+	DenseWeight  float32
+
+	// UseServerSideHybrid fuses the dense and sparse branches with Milvus's
+	// native HybridSearch API (one round-trip, server-side reranking) instead
+	// of issuing two separate Search calls and fusing them in Go. Only used
+	// when HybridMode == HybridModeRRF.
+	UseServerSideHybrid bool
+
+	// PartitionKey is a template for routing memories into Milvus partitions,
+	// e.g. "{user_id}" (default when Partitioned is true) or "{user_id}:{project_id}".
+	PartitionKey string
+
+	// Partitioned enables per-tenant partition routing. When true, Store/Retrieve/
+	// Forget operate against the partition derived from PartitionKey instead of
+	// scanning the whole collection.
+	Partitioned bool
+
+	// UsePartitionKeyField uses Milvus 2.3+ partition-key fields instead of
+	// application-managed partitions: user_id is marked IsPartitionKey in the
+	// schema and Milvus routes rows to partitions automatically.
+	UsePartitionKeyField bool
+
+	// PartitionCacheSize bounds the LRU cache of known partition names, used to
+	// avoid HasPartition round-trips. Defaults to 1024.
+	PartitionCacheSize int
+
+	// FlushPolicy controls when inserted data becomes searchable. Defaults to
+	// FlushImmediate (current behavior: flush after every Store call).
+	FlushPolicy FlushPolicy
+
+	// FlushInterval is the period between background flushes when
+	// FlushPolicy == FlushPolicyInterval. Defaults to 5s.
+	FlushInterval time.Duration
+
+	// FlushCount is the number of pending inserts that triggers a flush when
+	// FlushPolicy == FlushPolicyCount. Defaults to 100.
+	FlushCount int
+
+	// ConsistencyLevel controls the staleness bound for Search/Query calls.
+	// Defaults to the Milvus client default (Bounded) when unset.
+	ConsistencyLevel ConsistencyLevel
+
+	// GuaranteeTimestamp, when set, guarantees reads observe all writes up to
+	// this timestamp (used with ConsistencyStrong-like custom guarantees).
+	GuaranteeTimestamp uint64
+
+	// GracefulTime bounds how far behind a Bounded-consistency read may lag,
+	// in milliseconds. 0 uses the Milvus server default.
+	GracefulTime int64
+
+	// Scoring configures post-search re-ranking by recency and importance.
+	// Zero value disables it (pure cosine similarity, current behavior).
+	Scoring ScoringConfig
+
+	// TenantResolver extracts a tenant ID from the request context. When set,
+	// every Store/Retrieve/Forget call is scoped to "tenant_id == <resolved>"
+	// in addition to the usual user_id scoping, and RBACEnabled additionally
+	// provisions per-tenant Milvus roles/credentials on first use.
+	TenantResolver TenantResolver
+
+	// RBACEnabled provisions a Milvus role, grants, and credential for each
+	// new tenant ID seen (see TenantResolver), so tenants are isolated at the
+	// Milvus access-control layer, not just by query filter.
+	RBACEnabled bool
+
+	// Matryoshka enables coarse-to-fine retrieval over Matryoshka embedding
+	// truncations. Zero value (nil Dims) disables it: Retrieve searches only
+	// the full-dimension "embedding" field, the current behavior.
+	Matryoshka MatryoshkaConfig
+
+	// ConnectRetryAttempts bounds how many times NewMilvusStore retries a
+	// failed initial connection (NewGrpcClient + initializeCollection)
+	// before giving up. 0 or 1 means a single attempt, the previous
+	// behavior - startup races between Milvus and the router (common in
+	// Kubernetes, where both come up together) need at least a few retries
+	// to ride out.
+	ConnectRetryAttempts int
+
+	// ConnectRetryInitialBackoff is the delay before the first connection
+	// retry. Defaults to 500ms. Doubles on each subsequent attempt up to
+	// ConnectRetryMaxBackoff.
+	ConnectRetryInitialBackoff time.Duration
+
+	// ConnectRetryMaxBackoff caps the exponential backoff between connection
+	// attempts. Defaults to 10s.
+	ConnectRetryMaxBackoff time.Duration
+
+	// CredentialSource, when set, mints the API key NewMilvusStore dials with
+	// and feeds a CredentialRenewer that re-authenticates on a ~2/3-TTL
+	// schedule - for deployments fronting Milvus with short-lived RBAC
+	// tokens (Vault-issued, cloud IAM, etc.) instead of a static credential.
+	// Renewal failures are logged and retried rather than torn down
+	// (RenewBehaviorIgnoreErrors semantics); the renewer stops cleanly in
+	// Close.
+	CredentialSource CredentialSource
+}
+
+// MatryoshkaConfig configures per-document storage of multiple Matryoshka
+// embedding truncations (see GenerateMatryoshkaEmbeddings) and the
+// coarse-to-fine Retrieve strategy built on top of them: an initial search
+// against a low-dimensional field returns a candidate pool cheaply, and the
+// pool is re-ranked against the full-dimension embedding for accuracy.
+type MatryoshkaConfig struct {
+	// Dims lists the additional truncation dimensions to store and index,
+	// e.g. []int{128, 256, 512}. Each gets its own Milvus float-vector field
+	// ("embedding_<dim>") and HNSW index, populated from a prefix of the
+	// document's full embedding. Nil/empty disables Matryoshka retrieval.
+	Dims []int
+
+	// CoarseDim selects which of Dims the first-pass search runs against.
+	// Must be one of Dims; 0 defaults to the smallest entry.
+	CoarseDim int
+
+	// CandidatePool is how many coarse-search hits are re-ranked against the
+	// full embedding before Limit/Diversity are applied. Defaults to 100.
+	CandidatePool int
+}
+
+// matryoshkaFieldName is the Milvus schema field name storing a document's
+// embedding truncated to dim dimensions.
+func matryoshkaFieldName(dim int) string {
+	return fmt.Sprintf("embedding_%d", dim)
+}
+
+// coarseDim returns the dimension MatryoshkaConfig's first-pass search runs
+// against: CoarseDim if set, otherwise the smallest of Dims. Returns 0 if
+// Dims is empty (Matryoshka retrieval disabled).
+func (c MatryoshkaConfig) coarseDim() int {
+	if len(c.Dims) == 0 {
+		return 0
+	}
+	if c.CoarseDim != 0 {
+		return c.CoarseDim
+	}
+	smallest := c.Dims[0]
+	for _, d := range c.Dims {
+		if d < smallest {
+			smallest = d
+		}
+	}
+	return smallest
+}
+
+// candidatePool returns CandidatePool, defaulting to 100.
+func (c MatryoshkaConfig) candidatePool() int {
+	if c.CandidatePool > 0 {
+		return c.CandidatePool
+	}
+	return 100
+}
+
+// ScoringConfig controls how raw cosine similarity is adjusted before ranking,
+// so a "working memory" retrieval can prefer recent, important memories over
+// stale, low-importance ones with a similar embedding.
+type ScoringConfig struct {
+	// DecayHalfLife is the age at which a memory's contribution to its final
+	// score has halved. 0 disables time decay.
+	DecayHalfLife time.Duration
+
+	// ImportanceWeight scales how much Memory.Importance boosts the final
+	// score: final = sim * decay * (1 + ImportanceWeight*importance). 0 disables it.
+	ImportanceWeight float64
+
+	// RecencyMode selects which timestamp DecayHalfLife measures age against.
+	RecencyMode RecencyMode
+}
+
+// RecencyMode selects the timestamp used for time-decay scoring.
+type RecencyMode string
+
+const (
+	// RecencyCreated decays from Memory.CreatedAt (default).
+	RecencyCreated RecencyMode = "created"
+	// RecencyLastAccess decays from the memory's last accessed_at timestamp.
+	RecencyLastAccess RecencyMode = "last_access"
+)
+
+// toMilvusConsistencyLevel maps our backend-agnostic ConsistencyLevel onto the
+// Milvus SDK's entity.ConsistencyLevel, defaulting to Bounded when unset/unknown.
+func toMilvusConsistencyLevel(level ConsistencyLevel) entity.ConsistencyLevel {
+	switch level {
+	case ConsistencyStrong:
+		return entity.ClStrong
+	case ConsistencySession:
+		return entity.ClSession
+	case ConsistencyEventually:
+		return entity.ClEventually
+	case ConsistencyBounded, ConsistencyDefault:
+		return entity.ClBounded
+	default:
+		return entity.ClBounded
+	}
 }
 
+// FlushPolicy selects when MilvusStore flushes inserted data to make it searchable.
+type FlushPolicy string
+
+const (
+	// FlushPolicyImmediate flushes after every single Store/StoreBatch call
+	// (default, matches the historical behavior of this store).
+	FlushPolicyImmediate FlushPolicy = "immediate"
+	// FlushPolicyInterval flushes on a fixed background timer.
+	FlushPolicyInterval FlushPolicy = "interval"
+	// FlushPolicyCount flushes once a threshold of pending inserts is reached.
+	FlushPolicyCount FlushPolicy = "count"
+	// FlushPolicyManual never flushes automatically; callers must call Flush() explicitly.
+	FlushPolicyManual FlushPolicy = "manual"
+)
+
 // DefaultMilvusConfig returns sensible defaults
 func DefaultMilvusConfig() *MilvusStoreConfig {
 	return &MilvusStoreConfig{
-		Address:             "localhost:19530",
-		CollectionName:      "agentic_memory",
-		SimilarityThreshold: 0.7,
-		TopK:                10,
-		EfConstruction:      256,
-		M:                   16,
-		Ef:                  64,
+		Address:                    "localhost:19530",
+		CollectionName:             "agentic_memory",
+		SimilarityThreshold:        0.7,
+		TopK:                       10,
+		EfConstruction:             256,
+		M:                          16,
+		Ef:                         64,
+		HybridMode:                 HybridModeOff,
+		FlushPolicy:                FlushPolicyImmediate,
+		FlushInterval:              5 * time.Second,
+		FlushCount:                 100,
+		ConnectRetryAttempts:       5,
+		ConnectRetryInitialBackoff: 500 * time.Millisecond,
+		ConnectRetryMaxBackoff:     10 * time.Second,
 	}
 }
 
@@ -56,6 +347,24 @@ type MilvusStore struct {
 	collectionName string
 	dimension      int // embedding dimension (auto-detected)
 	mu             sync.RWMutex
+	partitions     *partitionCache // known partitions, avoids HasPartition round-trips
+
+	pendingMu    sync.Mutex
+	pendingCount int // inserts since the last flush, used by FlushPolicyCount
+	stopFlusher  chan struct{}
+	flusherDone  chan struct{}
+
+	access *accessRecorder // batches accessed_at bumps from Get/Retrieve hits
+
+	tenants *partitionCache // tenant IDs already provisioned via ensureTenant
+
+	schema *schemaCache // memoized HasCollection/DescribeCollection/GetLoadState
+
+	tombMu     sync.Mutex
+	tombstones []tombstone // bounded log backing Tombstones; reset on process restart
+
+	credMu            sync.Mutex         // serializes RotateCredential against itself
+	credentialRenewer *CredentialRenewer // nil unless config.CredentialSource is set
 }
 
 // NewMilvusStore creates a new Milvus-backed memory store
@@ -70,7 +379,18 @@ func NewMilvusStore(config *MilvusStoreConfig) (*MilvusStore, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	milvusClient, err := client.NewGrpcClient(ctx, config.Address)
+	var apiKey string
+	var renewer *CredentialRenewer
+	if config.CredentialSource != nil {
+		var err error
+		renewer, err = NewCredentialRenewer(ctx, config.CredentialSource, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch initial Milvus credential: %w", err)
+		}
+		apiKey = renewer.Current().Value
+	}
+
+	milvusClient, err := connectWithRetry(ctx, config, apiKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Milvus: %w", err)
 	}
@@ -79,7 +399,11 @@ func NewMilvusStore(config *MilvusStoreConfig) (*MilvusStore, error) {
 		client:         milvusClient,
 		config:         config,
 		collectionName: config.CollectionName,
+		partitions:     newPartitionCache(config.PartitionCacheSize),
+		tenants:        newPartitionCache(0),
+		schema:         newSchemaCache(defaultSchemaCacheTTL),
 	}
+	store.access = newAccessRecorder(milvusClient, config.CollectionName)
 
 	// Initialize collection
 	if err := store.initializeCollection(ctx); err != nil {
@@ -87,19 +411,101 @@ func NewMilvusStore(config *MilvusStoreConfig) (*MilvusStore, error) {
 		return nil, fmt.Errorf("failed to initialize collection: %w", err)
 	}
 
+	if config.FlushPolicy == FlushPolicyInterval {
+		store.startBackgroundFlusher()
+	}
+
+	if renewer != nil {
+		renewer.onRenew = store.renewCredential
+		renewer.Start(context.Background())
+		store.credentialRenewer = renewer
+	}
+
 	logging.Infof("MilvusMemoryStore: initialized successfully with collection '%s'", config.CollectionName)
 	return store, nil
 }
 
+// startBackgroundFlusher runs a goroutine that flushes on config.FlushInterval.
+// It is only started for FlushPolicyInterval and stopped in Close().
+func (s *MilvusStore) startBackgroundFlusher() {
+	interval := s.config.FlushInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	s.stopFlusher = make(chan struct{})
+	s.flusherDone = make(chan struct{})
+
+	go func() {
+		defer close(s.flusherDone)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Flush(context.Background()); err != nil {
+					logging.Warnf("MilvusMemoryStore: background flush failed: %v", err)
+				}
+			case <-s.stopFlusher:
+				return
+			}
+		}
+	}()
+}
+
+// Flush forces pending inserts to be persisted and made searchable, for
+// callers who want explicit control (or are using FlushPolicyManual).
+func (s *MilvusStore) Flush(ctx context.Context) error {
+	if err := s.client.Flush(ctx, s.collectionName, false); err != nil {
+		return fmt.Errorf("flush failed: %w", err)
+	}
+	s.pendingMu.Lock()
+	s.pendingCount = 0
+	s.pendingMu.Unlock()
+	return nil
+}
+
+// maybeFlush implements FlushPolicyImmediate/FlushPolicyCount after an insert of
+// n rows. FlushPolicyInterval/FlushPolicyManual are handled elsewhere (background
+// goroutine / explicit Flush calls).
+func (s *MilvusStore) maybeFlush(ctx context.Context, n int) {
+	switch s.config.FlushPolicy {
+	case "", FlushPolicyImmediate:
+		if err := s.client.Flush(ctx, s.collectionName, false); err != nil {
+			logging.Warnf("MilvusMemoryStore: flush warning: %v", err)
+		}
+
+	case FlushPolicyCount:
+		threshold := s.config.FlushCount
+		if threshold <= 0 {
+			threshold = 100
+		}
+		s.pendingMu.Lock()
+		s.pendingCount += n
+		shouldFlush := s.pendingCount >= threshold
+		s.pendingMu.Unlock()
+		if shouldFlush {
+			if err := s.Flush(ctx); err != nil {
+				logging.Warnf("MilvusMemoryStore: count-triggered flush failed: %v", err)
+			}
+		}
+
+	case FlushPolicyInterval, FlushPolicyManual:
+		// No-op here: interval mode flushes on its own ticker, manual mode
+		// flushes only when the caller invokes Flush() explicitly.
+	}
+}
+
 // initializeCollection creates the memory collection if it doesn't exist
 func (s *MilvusStore) initializeCollection(ctx context.Context) error {
-	// Check if collection exists
-	exists, err := s.client.HasCollection(ctx, s.collectionName)
+	// Check if collection exists, via the schema cache so repeated Retrieve/
+	// Store calls don't round-trip HasCollection every time.
+	entry, err := s.describeCollection(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to check collection: %w", err)
 	}
 
-	if exists {
+	if entry.exists {
 		logging.Infof("MilvusMemoryStore: collection '%s' already exists", s.collectionName)
 		// Load collection for searching
 		if err := s.client.LoadCollection(ctx, s.collectionName, false); err != nil {
@@ -129,9 +535,10 @@ func (s *MilvusStore) initializeCollection(ctx context.Context) error {
 				TypeParams: map[string]string{"max_length": "64"},
 			},
 			{
-				Name:       "user_id",
-				DataType:   entity.FieldTypeVarChar,
-				TypeParams: map[string]string{"max_length": "128"},
+				Name:           "user_id",
+				DataType:       entity.FieldTypeVarChar,
+				TypeParams:     map[string]string{"max_length": "128"},
+				IsPartitionKey: s.config.UsePartitionKeyField,
 			},
 			{
 				Name:       "project_id",
@@ -156,6 +563,18 @@ func (s *MilvusStore) initializeCollection(ctx context.Context) error {
 				Name:     "created_at",
 				DataType: entity.FieldTypeInt64,
 			},
+			{
+				Name:     "accessed_at",
+				DataType: entity.FieldTypeInt64,
+			},
+			{
+				Name:     "updated_at",
+				DataType: entity.FieldTypeInt64,
+			},
+			{
+				Name:     "ttl",
+				DataType: entity.FieldTypeInt64, // 0 = never expires; see StoreExpirer.ExpireDue
+			},
 			{
 				Name:     "embedding",
 				DataType: entity.FieldTypeFloatVector,
@@ -166,8 +585,34 @@ func (s *MilvusStore) initializeCollection(ctx context.Context) error {
 		},
 	}
 
-	// Create collection
-	if err := s.client.CreateCollection(ctx, schema, 2); err != nil {
+	for _, dim := range s.config.Matryoshka.Dims {
+		schema.Fields = append(schema.Fields, &entity.Field{
+			Name:     matryoshkaFieldName(dim),
+			DataType: entity.FieldTypeFloatVector,
+			TypeParams: map[string]string{
+				"dim": fmt.Sprintf("%d", dim),
+			},
+		})
+	}
+
+	if s.hybridEnabled() {
+		schema.Fields = append(schema.Fields, &entity.Field{
+			Name:     "sparse_embedding",
+			DataType: entity.FieldTypeSparseVector,
+		})
+	}
+
+	if s.config.TenantResolver != nil {
+		schema.Fields = append(schema.Fields, &entity.Field{
+			Name:       "tenant_id",
+			DataType:   entity.FieldTypeVarChar,
+			TypeParams: map[string]string{"max_length": "128"},
+		})
+	}
+
+	// Create collection with the configured default consistency level
+	if err := s.client.CreateCollection(ctx, schema, 2,
+		client.WithConsistencyLevel(toMilvusConsistencyLevel(s.config.ConsistencyLevel))); err != nil {
 		return fmt.Errorf("failed to create collection: %w", err)
 	}
 
@@ -181,6 +626,29 @@ func (s *MilvusStore) initializeCollection(ctx context.Context) error {
 		return fmt.Errorf("failed to create index: %w", err)
 	}
 
+	// Each Matryoshka truncation dimension gets its own independently indexed
+	// field, so a coarse-to-fine Retrieve can search the cheapest one without
+	// scanning the full-dimension index.
+	for _, dim := range s.config.Matryoshka.Dims {
+		mIdx, err := entity.NewIndexHNSW(entity.COSINE, s.config.M, s.config.EfConstruction)
+		if err != nil {
+			return fmt.Errorf("failed to create matryoshka index for dim %d: %w", dim, err)
+		}
+		if err := s.client.CreateIndex(ctx, s.collectionName, matryoshkaFieldName(dim), mIdx, false); err != nil {
+			return fmt.Errorf("failed to create matryoshka index for dim %d: %w", dim, err)
+		}
+	}
+
+	if s.hybridEnabled() {
+		sparseIdx, err := entity.NewIndexSparseInverted(entity.IP, 0.2)
+		if err != nil {
+			return fmt.Errorf("failed to create sparse index: %w", err)
+		}
+		if err := s.client.CreateIndex(ctx, s.collectionName, "sparse_embedding", sparseIdx, false); err != nil {
+			return fmt.Errorf("failed to create sparse index: %w", err)
+		}
+	}
+
 	// Load collection for searching
 	if err := s.client.LoadCollection(ctx, s.collectionName, false); err != nil {
 		return fmt.Errorf("failed to load collection: %w", err)
@@ -190,6 +658,32 @@ func (s *MilvusStore) initializeCollection(ctx context.Context) error {
 	return nil
 }
 
+// hybridEnabled reports whether sparse vectors should be stored/searched.
+func (s *MilvusStore) hybridEnabled() bool {
+	return s.config.HybridMode != "" && s.config.HybridMode != HybridModeOff
+}
+
+// sparseEncoder returns the configured SparseEncoder, falling back to the default.
+func (s *MilvusStore) sparseEncoder() SparseEncoder {
+	if s.config.SparseEncoder != nil {
+		return s.config.SparseEncoder
+	}
+	return DefaultSparseEncoder()
+}
+
+// encodeSparseColumn builds a Milvus sparse vector column from raw text.
+func (s *MilvusStore) encodeSparseColumn(text string) entity.SparseEmbedding {
+	weights := s.sparseEncoder().Encode(text)
+	positions := make([]uint32, 0, len(weights))
+	values := make([]float32, 0, len(weights))
+	for pos, val := range weights {
+		positions = append(positions, pos)
+		values = append(values, val)
+	}
+	vec, _ := entity.NewSliceSparseEmbedding(positions, values)
+	return vec
+}
+
 // Store saves a new memory with its embedding
 func (s *MilvusStore) Store(ctx context.Context, memory *Memory) error {
 	if memory.UserID == "" {
@@ -210,6 +704,7 @@ func (s *MilvusStore) Store(ctx context.Context, memory *Memory) error {
 		memory.CreatedAt = now
 	}
 	memory.AccessedAt = now
+	memory.UpdatedAt = now
 
 	// Generate embedding
 	logging.Debugf("MilvusMemoryStore: generating embedding for content length %d", len(memory.Content))
@@ -235,10 +730,12 @@ func (s *MilvusStore) Store(ctx context.Context, memory *Memory) error {
 	contents := []string{memory.Content}
 	importances := []float32{float32(memory.Importance)}
 	createdAts := []int64{memory.CreatedAt.Unix()}
+	accessedAts := []int64{memory.AccessedAt.Unix()}
+	updatedAts := []int64{memory.UpdatedAt.Unix()}
+	ttls := []int64{ttlUnix(memory.TTL)}
 	embeddings := [][]float32{embedding}
 
-	// Insert into Milvus
-	_, err = s.client.Insert(ctx, s.collectionName, "",
+	columns := []entity.Column{
 		entity.NewColumnVarChar("id", ids),
 		entity.NewColumnVarChar("user_id", userIDs),
 		entity.NewColumnVarChar("project_id", projectIDs),
@@ -246,21 +743,201 @@ func (s *MilvusStore) Store(ctx context.Context, memory *Memory) error {
 		entity.NewColumnVarChar("content", contents),
 		entity.NewColumnFloat("importance", importances),
 		entity.NewColumnInt64("created_at", createdAts),
+		entity.NewColumnInt64("accessed_at", accessedAts),
+		entity.NewColumnInt64("updated_at", updatedAts),
+		entity.NewColumnInt64("ttl", ttls),
 		entity.NewColumnFloatVector("embedding", s.dimension, embeddings),
-	)
+	}
+	for _, dim := range s.config.Matryoshka.Dims {
+		columns = append(columns, entity.NewColumnFloatVector(matryoshkaFieldName(dim), dim, [][]float32{truncateEmbedding(embedding, dim)}))
+	}
+	if s.hybridEnabled() {
+		columns = append(columns, entity.NewColumnSparseVectors("sparse_embedding", []entity.SparseEmbedding{s.encodeSparseColumn(memory.Content)}))
+	}
+
+	tenantID := s.resolveTenant(ctx)
+	if tenantID != "" {
+		columns = append(columns, entity.NewColumnVarChar("tenant_id", []string{tenantID}))
+		if err := s.ensureTenant(ctx, tenantID); err != nil {
+			return fmt.Errorf("failed to provision tenant %q: %w", tenantID, err)
+		}
+	}
+
+	// Route into the caller's partition, creating it lazily if needed.
+	partition := s.partitionKeyFor(memory.UserID, memory.ProjectID)
+	if partition != "" {
+		if err := s.EnsurePartition(ctx, partition); err != nil {
+			return fmt.Errorf("failed to ensure partition: %w", err)
+		}
+	}
+
+	// Insert into Milvus, re-describing the collection once if a stale schema
+	// cache entry is the reason the insert failed.
+	err = s.withSchemaRetry(ctx, func() error {
+		_, insertErr := s.client.Insert(ctx, s.collectionName, partition, columns...)
+		return insertErr
+	})
 	if err != nil {
 		return fmt.Errorf("failed to insert memory: %w", err)
 	}
 
-	// Flush to ensure data is persisted
-	if err := s.client.Flush(ctx, s.collectionName, false); err != nil {
-		logging.Warnf("MilvusMemoryStore: flush warning: %v", err)
-	}
+	s.maybeFlush(ctx, 1)
 
 	logging.Debugf("MilvusMemoryStore: stored memory %s for user %s", memory.ID, memory.UserID)
 	return nil
 }
 
+// StoreBatch stores multiple memories in a single Milvus insert. Embeddings are
+// generated concurrently over a bounded worker pool, then all rows are inserted
+// together, avoiding the per-call Insert+Flush overhead of calling Store in a loop.
+func (s *MilvusStore) StoreBatch(ctx context.Context, memories []*Memory) error {
+	if len(memories) == 0 {
+		return nil
+	}
+	for _, m := range memories {
+		if m.UserID == "" {
+			return ErrInvalidUserID
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	embeddings := make([][]float32, len(memories))
+
+	const maxWorkers = 8
+	workers := maxWorkers
+	if len(memories) < workers {
+		workers = len(memories)
+	}
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(memories))
+	jobs := make(chan int, len(memories))
+	for i := range memories {
+		jobs <- i
+	}
+	close(jobs)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				embedding, err := candle_binding.GetEmbedding(memories[i].Content, 0)
+				if err != nil {
+					errCh <- fmt.Errorf("failed to generate embedding for memory %d: %w", i, err)
+					continue
+				}
+				embeddings[i] = embedding
+			}
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+	if err := <-errCh; err != nil {
+		return err
+	}
+
+	ids := make([]string, len(memories))
+	userIDs := make([]string, len(memories))
+	projectIDs := make([]string, len(memories))
+	memoryTypes := make([]string, len(memories))
+	contents := make([]string, len(memories))
+	importances := make([]float32, len(memories))
+	createdAts := make([]int64, len(memories))
+	accessedAts := make([]int64, len(memories))
+	updatedAts := make([]int64, len(memories))
+	ttls := make([]int64, len(memories))
+	sparseEmbeddings := make([]entity.SparseEmbedding, 0, len(memories))
+
+	for i, m := range memories {
+		if m.ID == "" {
+			m.ID = "mem_" + uuid.New().String()[:8]
+		}
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = now
+		}
+		m.AccessedAt = now
+		m.UpdatedAt = now
+		m.Embedding = embeddings[i]
+
+		ids[i] = m.ID
+		userIDs[i] = m.UserID
+		projectIDs[i] = m.ProjectID
+		memoryTypes[i] = string(m.Type)
+		contents[i] = m.Content
+		importances[i] = float32(m.Importance)
+		createdAts[i] = m.CreatedAt.Unix()
+		accessedAts[i] = m.AccessedAt.Unix()
+		updatedAts[i] = m.UpdatedAt.Unix()
+		ttls[i] = ttlUnix(m.TTL)
+		if s.hybridEnabled() {
+			sparseEmbeddings = append(sparseEmbeddings, s.encodeSparseColumn(m.Content))
+		}
+	}
+
+	columns := []entity.Column{
+		entity.NewColumnVarChar("id", ids),
+		entity.NewColumnVarChar("user_id", userIDs),
+		entity.NewColumnVarChar("project_id", projectIDs),
+		entity.NewColumnVarChar("memory_type", memoryTypes),
+		entity.NewColumnVarChar("content", contents),
+		entity.NewColumnFloat("importance", importances),
+		entity.NewColumnInt64("created_at", createdAts),
+		entity.NewColumnInt64("accessed_at", accessedAts),
+		entity.NewColumnInt64("updated_at", updatedAts),
+		entity.NewColumnInt64("ttl", ttls),
+		entity.NewColumnFloatVector("embedding", s.dimension, embeddings),
+	}
+	for _, dim := range s.config.Matryoshka.Dims {
+		truncated := make([][]float32, len(embeddings))
+		for i, emb := range embeddings {
+			truncated[i] = truncateEmbedding(emb, dim)
+		}
+		columns = append(columns, entity.NewColumnFloatVector(matryoshkaFieldName(dim), dim, truncated))
+	}
+	if s.hybridEnabled() {
+		columns = append(columns, entity.NewColumnSparseVectors("sparse_embedding", sparseEmbeddings))
+	}
+
+	if tenantID := s.resolveTenant(ctx); tenantID != "" {
+		tenantIDs := make([]string, len(memories))
+		for i := range memories {
+			tenantIDs[i] = tenantID
+		}
+		columns = append(columns, entity.NewColumnVarChar("tenant_id", tenantIDs))
+		if err := s.ensureTenant(ctx, tenantID); err != nil {
+			return fmt.Errorf("failed to provision tenant %q: %w", tenantID, err)
+		}
+	}
+
+	// A batch may span multiple users/partitions; partition-key fields (when
+	// configured) still route each row correctly, so we insert into the
+	// default partition and let Milvus route by the partition-key column.
+	// Application-managed partitioning is incompatible with mixed-tenant
+	// batches, so callers using Partitioned=true should batch per user.
+	partition := ""
+	if s.config.Partitioned && !s.config.UsePartitionKeyField && len(memories) > 0 {
+		partition = s.partitionKeyFor(memories[0].UserID, memories[0].ProjectID)
+		if partition != "" {
+			if err := s.EnsurePartition(ctx, partition); err != nil {
+				return fmt.Errorf("failed to ensure partition: %w", err)
+			}
+		}
+	}
+
+	if _, err := s.client.Insert(ctx, s.collectionName, partition, columns...); err != nil {
+		return fmt.Errorf("failed to batch insert memories: %w", err)
+	}
+
+	s.maybeFlush(ctx, len(memories))
+
+	logging.Infof("MilvusMemoryStore: batch-stored %d memories", len(memories))
+	return nil
+}
+
 // Retrieve finds memories matching the query using semantic similarity
 func (s *MilvusStore) Retrieve(ctx context.Context, opts RetrieveOptions) ([]*RetrieveResult, error) {
 	if opts.UserID == "" {
@@ -291,6 +968,13 @@ func (s *MilvusStore) Retrieve(ctx context.Context, opts RetrieveOptions) ([]*Re
 		}
 		filter += fmt.Sprintf(" && (%s)", typeFilter)
 	}
+	if tenantID := s.resolveTenant(ctx); tenantID != "" {
+		filter += fmt.Sprintf(" && tenant_id == \"%s\"", tenantID)
+	}
+	if opts.TimeWindow != nil {
+		filter += fmt.Sprintf(" && created_at >= %d && created_at <= %d", opts.TimeWindow[0].Unix(), opts.TimeWindow[1].Unix())
+	}
+	filter += fmt.Sprintf(" && (ttl == 0 || ttl > %d)", time.Now().Unix())
 
 	// Set search parameters
 	topK := opts.Limit
@@ -303,89 +987,547 @@ func (s *MilvusStore) Retrieve(ctx context.Context, opts RetrieveOptions) ([]*Re
 		return nil, fmt.Errorf("failed to create search param: %w", err)
 	}
 
-	// Execute semantic search
-	searchResult, err := s.client.Search(
-		ctx,
-		s.collectionName,
-		[]string{},
-		filter,
-		[]string{"id", "user_id", "project_id", "memory_type", "content", "importance", "created_at"},
-		[]entity.Vector{entity.FloatVector(queryEmbedding)},
-		"embedding",
-		entity.COSINE,
-		topK,
-		searchParam,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("search failed: %w", err)
+	outputFields := []string{"id", "user_id", "project_id", "memory_type", "content", "importance", "created_at", "accessed_at"}
+
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = s.config.SimilarityThreshold
 	}
 
+	// MMR re-ranking needs the stored vectors and a larger candidate pool to
+	// diversify over, so over-fetch and ask Milvus to return embeddings too.
+	fetchK := topK
+	if opts.Diversity > 0 {
+		fetchK = topK * mmrOverfetchFactor
+		outputFields = append(outputFields, "embedding")
+	}
+
+	// Scope the search to the caller's partition so we only scan their shard.
+	var partitions []string
+	if partition := s.partitionKeyFor(opts.UserID, opts.ProjectID); partition != "" {
+		partitions = []string{partition}
+	}
+
+	// Per-call consistency override (falls back to the store's config default).
+	level := s.config.ConsistencyLevel
+	if opts.Consistency != "" {
+		level = opts.Consistency
+	}
+	searchOpts := []client.SearchQueryOptionFunc{client.WithSearchQueryConsistencyLevel(toMilvusConsistencyLevel(level))}
+	if s.config.GuaranteeTimestamp > 0 {
+		searchOpts = append(searchOpts, client.WithGuaranteeTimestamp(s.config.GuaranteeTimestamp))
+	}
+
+	var results []*RetrieveResult
+	if s.hybridEnabled() && s.config.HybridMode != HybridModeDense {
+		results, err = s.hybridRetrieve(ctx, opts, queryEmbedding, filter, outputFields, fetchK, searchParam, threshold, partitions, searchOpts)
+		if err != nil {
+			return nil, err
+		}
+	} else if dim := s.config.Matryoshka.coarseDim(); dim > 0 {
+		results, err = s.coarseToFineRetrieve(ctx, queryEmbedding, filter, outputFields, dim, searchParam, threshold, partitions, searchOpts)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Execute dense-only semantic search, retrying transient failures and
+		// re-describing the collection once if the schema looks stale.
+		var searchResult []client.SearchResult
+		err = s.withSchemaRetry(ctx, func() error {
+			return withRetry(ctx, func() error {
+				var searchErr error
+				searchResult, searchErr = s.client.Search(
+					ctx,
+					s.collectionName,
+					partitions,
+					filter,
+					outputFields,
+					[]entity.Vector{entity.FloatVector(queryEmbedding)},
+					"embedding",
+					entity.COSINE,
+					fetchK,
+					searchParam,
+					searchOpts...,
+				)
+				return searchErr
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+
+		if len(searchResult) == 0 || searchResult[0].ResultCount == 0 {
+			logging.Debugf("MilvusMemoryStore: search returned 0 results")
+			return []*RetrieveResult{}, nil
+		}
+
+		results = memoriesFromSearchResult(searchResult[0], threshold)
+	}
+
+	results = applyScoring(results, s.config.Scoring, threshold)
+	results = applyRecencyWeight(results, opts)
+
+	if opts.Diversity > 0 {
+		results = mmrRerank(results, queryEmbedding, topK, opts.Diversity)
+	} else if len(results) > topK {
+		results = results[:topK]
+	}
+
+	if s.access != nil {
+		now := time.Now()
+		for _, r := range results {
+			s.access.Record(r.Memory.ID, now)
+		}
+	}
+
+	logging.Debugf("MilvusMemoryStore: found %d memories for query '%s'", len(results), opts.Query)
+	return results, nil
+}
+
+// applyScoring re-ranks results by combining their raw similarity with time
+// decay and an importance boost, per cfg:
+//
+//	final = sim * 2^(-age/halfLife) * (1 + importanceWeight*importance)
+//
+// A zero-value ScoringConfig (no DecayHalfLife, no ImportanceWeight) is a
+// no-op: results pass through in their original order. The threshold is
+// re-applied against the adjusted score, since decay/importance can push a
+// result below (or above) the original cosine cutoff.
+func applyScoring(results []*RetrieveResult, cfg ScoringConfig, threshold float32) []*RetrieveResult {
+	if cfg.DecayHalfLife <= 0 && cfg.ImportanceWeight == 0 {
+		return results
+	}
+
+	now := time.Now()
+	rescored := make([]*RetrieveResult, 0, len(results))
+	for _, r := range results {
+		decay := 1.0
+		if cfg.DecayHalfLife > 0 {
+			age := now.Sub(recencyTimestamp(r.Memory, cfg.RecencyMode))
+			if age < 0 {
+				age = 0
+			}
+			decay = math.Pow(2, -age.Seconds()/cfg.DecayHalfLife.Seconds())
+		}
+
+		final := r.RawScore * decay * (1 + cfg.ImportanceWeight*r.Memory.Importance)
+		if final < float64(threshold) {
+			continue
+		}
+		r.Relevance = final
+		rescored = append(rescored, r)
+	}
+
+	sort.Slice(rescored, func(i, j int) bool { return rescored[i].Relevance > rescored[j].Relevance })
+	return rescored
+}
+
+// ttlUnix converts a Memory's TTL to the int64 Unix seconds stored in
+// Milvus's ttl column, where 0 means "never expires" (time.Time's zero
+// value happens to be far enough in the past/negative that it must be
+// mapped explicitly rather than relying on .Unix() returning 0).
+func ttlUnix(ttl time.Time) int64 {
+	if ttl.IsZero() {
+		return 0
+	}
+	return ttl.Unix()
+}
+
+// containsField reports whether fields already includes name.
+func containsField(fields []string, name string) bool {
+	for _, f := range fields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateEmbedding returns the first dim components of emb, or emb
+// unchanged if it's already no longer than dim. Matryoshka Representation
+// Learning guarantees a prefix of a full embedding is itself a valid,
+// coarser embedding, so this is all a document's low-dimensional fields
+// need beyond the already-computed full embedding.
+func truncateEmbedding(emb []float32, dim int) []float32 {
+	if dim <= 0 || dim >= len(emb) {
+		return emb
+	}
+	return emb[:dim]
+}
+
+// recencyTimestamp picks the timestamp a decay calculation measures age
+// against, per ScoringConfig.RecencyMode.
+func recencyTimestamp(m *Memory, mode RecencyMode) time.Time {
+	if mode == RecencyLastAccess && !m.AccessedAt.IsZero() {
+		return m.AccessedAt
+	}
+	return m.CreatedAt
+}
+
+// applyRecencyWeight re-ranks results by blending each result's RawScore with
+// an exponential time decay anchored at opts.TimeAnchor (defaulting to
+// time.Now()), per opts.RecencyWeight and the shared blendRecency helper -
+// the same ranking InMemoryStore.Retrieve applies, so a time-anchored query
+// ranks the same regardless of backend. A zero RecencyWeight is a no-op.
+func applyRecencyWeight(results []*RetrieveResult, opts RetrieveOptions) []*RetrieveResult {
+	if opts.RecencyWeight <= 0 {
+		return results
+	}
+	anchor := time.Now()
+	if opts.TimeAnchor != nil {
+		anchor = *opts.TimeAnchor
+	}
+	for _, r := range results {
+		r.Relevance = blendRecency(r.RawScore, r.Memory.CreatedAt, anchor, opts.RecencyWeight)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Relevance > results[j].Relevance })
+	return results
+}
+
+// coarseToFineRetrieve implements Matryoshka coarse-to-fine Retrieve: a
+// first-pass search against the cheap, low-dimensional dim field returns a
+// candidate pool (s.config.Matryoshka.candidatePool()), which is then
+// re-scored by cosine similarity against the already-computed full
+// queryEmbedding so RawScore/Relevance reflect full-dimension accuracy, not
+// the coarse pass's approximation. outputFields must not already include
+// "embedding"; this adds it for the candidates.
+func (s *MilvusStore) coarseToFineRetrieve(
+	ctx context.Context,
+	queryEmbedding []float32,
+	filter string,
+	outputFields []string,
+	dim int,
+	searchParam entity.SearchParam,
+	threshold float32,
+	partitions []string,
+	searchOpts []client.SearchQueryOptionFunc,
+) ([]*RetrieveResult, error) {
+	coarseQuery := truncateEmbedding(queryEmbedding, dim)
+	candidateFields := append([]string{}, outputFields...)
+	if !containsField(candidateFields, "embedding") {
+		candidateFields = append(candidateFields, "embedding")
+	}
+	pool := s.config.Matryoshka.candidatePool()
+
+	var searchResult []client.SearchResult
+	err := s.withSchemaRetry(ctx, func() error {
+		return withRetry(ctx, func() error {
+			var searchErr error
+			searchResult, searchErr = s.client.Search(
+				ctx,
+				s.collectionName,
+				partitions,
+				filter,
+				candidateFields,
+				[]entity.Vector{entity.FloatVector(coarseQuery)},
+				matryoshkaFieldName(dim),
+				entity.COSINE,
+				pool,
+				searchParam,
+				searchOpts...,
+			)
+			return searchErr
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("matryoshka coarse search failed: %w", err)
+	}
 	if len(searchResult) == 0 || searchResult[0].ResultCount == 0 {
-		logging.Debugf("MilvusMemoryStore: search returned 0 results")
 		return []*RetrieveResult{}, nil
 	}
 
-	// Extract results
-	var results []*RetrieveResult
-	result := searchResult[0]
+	candidates := memoriesFromSearchResultUnfiltered(searchResult)
+	results := make([]*RetrieveResult, 0, len(candidates))
+	for _, mem := range candidates {
+		if len(mem.Embedding) == 0 {
+			continue
+		}
+		sim := cosineSimilarity(mem.Embedding, queryEmbedding)
+		if sim < float64(threshold) {
+			continue
+		}
+		results = append(results, &RetrieveResult{Memory: mem, Relevance: sim, RawScore: sim})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Relevance > results[j].Relevance })
+	return results, nil
+}
 
-	logging.Infof("MilvusMemoryStore: search returned %d raw results", result.ResultCount)
+// hybridRetrieve runs the dense and sparse branches of a hybrid search and fuses
+// them according to s.config.HybridMode (RRF or sparse-only).
+func (s *MilvusStore) hybridRetrieve(
+	ctx context.Context,
+	opts RetrieveOptions,
+	queryEmbedding []float32,
+	filter string,
+	outputFields []string,
+	topK int,
+	searchParam entity.SearchParam,
+	threshold float32,
+	partitions []string,
+	searchOpts []client.SearchQueryOptionFunc,
+) ([]*RetrieveResult, error) {
+	sparseVec := s.encodeSparseColumn(opts.Query)
+
+	if s.config.HybridMode == HybridModeSparse {
+		sparseResult, err := s.client.Search(ctx, s.collectionName, partitions, filter, outputFields,
+			[]entity.Vector{sparseVec}, "sparse_embedding", entity.IP, topK, searchParam, searchOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("sparse search failed: %w", err)
+		}
+		if len(sparseResult) == 0 || sparseResult[0].ResultCount == 0 {
+			return []*RetrieveResult{}, nil
+		}
+		return memoriesFromSearchResult(sparseResult[0], threshold), nil
+	}
 
-	for i := 0; i < result.ResultCount; i++ {
-		score := result.Scores[i]
+	if s.config.UseServerSideHybrid {
+		return s.serverSideHybridRetrieve(ctx, queryEmbedding, sparseVec, filter, outputFields, topK, searchParam, threshold, partitions, searchOpts)
+	}
 
-		// Filter by threshold
-		threshold := opts.Threshold
-		if threshold <= 0 {
-			threshold = s.config.SimilarityThreshold
+	// RRF mode: issue a dense and a sparse branch, then fuse.
+	denseResult, err := s.client.Search(ctx, s.collectionName, partitions, filter, outputFields,
+		[]entity.Vector{entity.FloatVector(queryEmbedding)}, "embedding", entity.COSINE, topK, searchParam, searchOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("dense search failed: %w", err)
+	}
+	sparseResult, err := s.client.Search(ctx, s.collectionName, partitions, filter, outputFields,
+		[]entity.Vector{sparseVec}, "sparse_embedding", entity.IP, topK, searchParam, searchOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("sparse search failed: %w", err)
+	}
+
+	denseMems := memoriesFromSearchResultUnfiltered(denseResult)
+	sparseMems := memoriesFromSearchResultUnfiltered(sparseResult)
+
+	fused := fuseRRF(denseMems, sparseMems, s.config.DenseWeight, s.config.SparseWeight)
+
+	results := make([]*RetrieveResult, 0, len(fused))
+	for _, f := range fused {
+		if f.score < float64(threshold) {
+			continue
+		}
+		results = append(results, &RetrieveResult{Memory: f.mem, Relevance: f.score, RawScore: f.score})
+	}
+	if len(results) > topK {
+		results = results[:topK]
+	}
+	return results, nil
+}
+
+// serverSideHybridRetrieve fuses the dense and sparse branches in a single
+// Milvus HybridSearch call instead of two Search round-trips fused in Go.
+// Used when s.config.UseServerSideHybrid is set. The reranker is a weighted
+// sum when both DenseWeight and SparseWeight are configured, otherwise RRF.
+func (s *MilvusStore) serverSideHybridRetrieve(
+	ctx context.Context,
+	queryEmbedding []float32,
+	sparseVec entity.Vector,
+	filter string,
+	outputFields []string,
+	topK int,
+	searchParam entity.SearchParam,
+	threshold float32,
+	partitions []string,
+	searchOpts []client.SearchQueryOptionFunc,
+) ([]*RetrieveResult, error) {
+	denseReq := client.NewANNSearchRequest("embedding", entity.COSINE, filter,
+		[]entity.Vector{entity.FloatVector(queryEmbedding)}, searchParam, topK)
+	sparseReq := client.NewANNSearchRequest("sparse_embedding", entity.IP, filter,
+		[]entity.Vector{sparseVec}, searchParam, topK)
+
+	var reranker client.Reranker
+	if s.config.DenseWeight > 0 && s.config.SparseWeight > 0 {
+		reranker = client.NewWeightedReranker([]float64{float64(s.config.DenseWeight), float64(s.config.SparseWeight)})
+	} else {
+		reranker = client.NewRRFReranker(rrfK)
+	}
+
+	result, err := s.client.HybridSearch(ctx, s.collectionName, partitions, topK, outputFields,
+		reranker, []*client.ANNSearchRequest{denseReq, sparseReq}, searchOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("hybrid search failed: %w", err)
+	}
+	if len(result) == 0 || result[0].ResultCount == 0 {
+		return []*RetrieveResult{}, nil
+	}
+	return memoriesFromSearchResult(result[0], threshold), nil
+}
+
+// rankedMemory pairs a decoded Memory with its rank-fusion score.
+type rankedMemory struct {
+	mem   *Memory
+	score float64
+}
+
+// fuseRRF combines two ranked result lists using Reciprocal Rank Fusion:
+// score(m) = Σ 1/(k + rank_i(m)). If both denseWeight and sparseWeight are
+// non-zero, a linear combination of the raw per-branch scores is used instead.
+func fuseRRF(dense, sparse []*Memory, denseWeight, sparseWeight float32) []rankedMemory {
+	scores := make(map[string]float64)
+	mems := make(map[string]*Memory)
+
+	useLinear := denseWeight > 0 && sparseWeight > 0
+
+	for rank, m := range dense {
+		mems[m.ID] = m
+		if useLinear {
+			scores[m.ID] += float64(denseWeight) * float64(len(dense)-rank)
+		} else {
+			scores[m.ID] += 1.0 / (rrfK + float64(rank+1))
+		}
+	}
+	for rank, m := range sparse {
+		mems[m.ID] = m
+		if useLinear {
+			scores[m.ID] += float64(sparseWeight) * float64(len(sparse)-rank)
+		} else {
+			scores[m.ID] += 1.0 / (rrfK + float64(rank+1))
 		}
+	}
 
-		logging.Infof("MilvusMemoryStore: result %d score=%.4f, threshold=%.4f", i, score, threshold)
+	fused := make([]rankedMemory, 0, len(mems))
+	for id, m := range mems {
+		fused = append(fused, rankedMemory{mem: m, score: scores[id]})
+	}
+	sort.Slice(fused, func(i, j int) bool { return fused[i].score > fused[j].score })
+	return fused
+}
 
+// memoriesFromSearchResult decodes a single Milvus SearchResult into RetrieveResults,
+// applying the similarity threshold.
+func memoriesFromSearchResult(result client.SearchResult, threshold float32) []*RetrieveResult {
+	var results []*RetrieveResult
+	for i := 0; i < result.ResultCount; i++ {
+		score := result.Scores[i]
 		if score < threshold {
-			logging.Infof("MilvusMemoryStore: skipping result %d (score %.4f < threshold %.4f)", i, score, threshold)
 			continue
 		}
+		mem := decodeMemoryField(result.Fields, i)
+		results = append(results, &RetrieveResult{Memory: mem, Relevance: float64(score), RawScore: float64(score)})
+	}
+	return results
+}
 
-		// Extract fields
-		var mem Memory
-		for _, field := range result.Fields {
-			switch field.Name() {
-			case "id":
-				col := field.(*entity.ColumnVarChar)
-				mem.ID, _ = col.ValueByIdx(i)
-			case "user_id":
-				col := field.(*entity.ColumnVarChar)
-				mem.UserID, _ = col.ValueByIdx(i)
-			case "project_id":
-				col := field.(*entity.ColumnVarChar)
-				mem.ProjectID, _ = col.ValueByIdx(i)
-			case "memory_type":
-				col := field.(*entity.ColumnVarChar)
-				typeStr, _ := col.ValueByIdx(i)
-				mem.Type = MemoryType(typeStr)
-			case "content":
-				col := field.(*entity.ColumnVarChar)
-				mem.Content, _ = col.ValueByIdx(i)
-			case "importance":
-				col := field.(*entity.ColumnFloat)
-				imp, _ := col.ValueByIdx(i)
-				mem.Importance = float64(imp)
-			case "created_at":
-				col := field.(*entity.ColumnInt64)
-				ts, _ := col.ValueByIdx(i)
-				mem.CreatedAt = time.Unix(ts, 0)
+// memoriesFromSearchResultUnfiltered decodes every hit from a Milvus search response
+// (without threshold filtering), preserving the original rank order for RRF fusion.
+func memoriesFromSearchResultUnfiltered(searchResult []client.SearchResult) []*Memory {
+	if len(searchResult) == 0 {
+		return nil
+	}
+	result := searchResult[0]
+	mems := make([]*Memory, 0, result.ResultCount)
+	for i := 0; i < result.ResultCount; i++ {
+		mems = append(mems, decodeMemoryField(result.Fields, i))
+	}
+	return mems
+}
+
+// decodeMemoryField extracts a Memory from the i-th row of a Milvus field set.
+func decodeMemoryField(fields []entity.Column, i int) *Memory {
+	var mem Memory
+	for _, field := range fields {
+		switch field.Name() {
+		case "id":
+			col := field.(*entity.ColumnVarChar)
+			mem.ID, _ = col.ValueByIdx(i)
+		case "user_id":
+			col := field.(*entity.ColumnVarChar)
+			mem.UserID, _ = col.ValueByIdx(i)
+		case "project_id":
+			col := field.(*entity.ColumnVarChar)
+			mem.ProjectID, _ = col.ValueByIdx(i)
+		case "memory_type":
+			col := field.(*entity.ColumnVarChar)
+			typeStr, _ := col.ValueByIdx(i)
+			mem.Type = MemoryType(typeStr)
+		case "content":
+			col := field.(*entity.ColumnVarChar)
+			mem.Content, _ = col.ValueByIdx(i)
+		case "importance":
+			col := field.(*entity.ColumnFloat)
+			imp, _ := col.ValueByIdx(i)
+			mem.Importance = float64(imp)
+		case "created_at":
+			col := field.(*entity.ColumnInt64)
+			ts, _ := col.ValueByIdx(i)
+			mem.CreatedAt = time.Unix(ts, 0)
+		case "accessed_at":
+			col := field.(*entity.ColumnInt64)
+			ts, _ := col.ValueByIdx(i)
+			mem.AccessedAt = time.Unix(ts, 0)
+		case "embedding":
+			col := field.(*entity.ColumnFloatVector)
+			vec, _ := col.ValueByIdx(i)
+			mem.Embedding = vec
+		}
+	}
+	return &mem
+}
+
+// mmrRerank re-orders results using Maximal Marginal Relevance so near-duplicate
+// memories don't crowd out diverse ones:
+//
+//	m* = argmax_{m in R\S} [ lambda*sim(m,q) - (1-lambda)*max_{s in S} sim(m,s) ]
+//
+// where lambda = 1-diversity. Candidates whose embedding wasn't returned (e.g.
+// decoding failure) are kept at the end in their original relevance order.
+// RawScore (the original similarity) is preserved; Relevance is overwritten with
+// each pick's MMR score so callers can see the diversity-adjusted ranking.
+func mmrRerank(candidates []*RetrieveResult, queryEmbedding []float32, topK int, diversity float32) []*RetrieveResult {
+	lambda := float64(1 - diversity)
+
+	usable := make([]*RetrieveResult, 0, len(candidates))
+	skipped := make([]*RetrieveResult, 0)
+	for _, c := range candidates {
+		if len(c.Memory.Embedding) == 0 {
+			skipped = append(skipped, c)
+			continue
+		}
+		usable = append(usable, c)
+	}
+
+	selected := make([]*RetrieveResult, 0, topK)
+	chosen := make([]bool, len(usable))
+
+	for len(selected) < topK && len(selected) < len(usable) {
+		bestIdx := -1
+		bestScore := math.Inf(-1)
+
+		for i, cand := range usable {
+			if chosen[i] {
+				continue
+			}
+
+			simToQuery := cosineSimilarity(cand.Memory.Embedding, queryEmbedding)
+			maxSimToSelected := 0.0
+			for _, s := range selected {
+				if sim := cosineSimilarity(cand.Memory.Embedding, s.Memory.Embedding); sim > maxSimToSelected {
+					maxSimToSelected = sim
+				}
+			}
+
+			score := lambda*simToQuery - (1-lambda)*maxSimToSelected
+			if score > bestScore {
+				bestScore = score
+				bestIdx = i
 			}
 		}
 
-		results = append(results, &RetrieveResult{
-			Memory:    &mem,
-			Relevance: float64(score),
-		})
+		chosen[bestIdx] = true
+		usable[bestIdx].Relevance = bestScore
+		selected = append(selected, usable[bestIdx])
 	}
 
-	logging.Debugf("MilvusMemoryStore: found %d memories for query '%s'", len(results), opts.Query)
-	return results, nil
+	if len(selected) < topK {
+		for _, c := range skipped {
+			if len(selected) >= topK {
+				break
+			}
+			selected = append(selected, c)
+		}
+	}
+
+	return selected
 }
 
 // Get retrieves a specific memory by ID
@@ -393,14 +1535,20 @@ func (s *MilvusStore) Get(ctx context.Context, id string) (*Memory, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	// Query by ID
-	result, err := s.client.Query(
-		ctx,
-		s.collectionName,
-		[]string{},
-		fmt.Sprintf("id == \"%s\"", id),
-		[]string{"id", "user_id", "project_id", "memory_type", "content", "importance", "created_at"},
-	)
+	// Query by ID, retrying transient failures.
+	var result []entity.Column
+	err := withRetry(ctx, func() error {
+		var queryErr error
+		result, queryErr = s.client.Query(
+			ctx,
+			s.collectionName,
+			[]string{},
+			fmt.Sprintf("id == \"%s\"", id),
+			[]string{"id", "user_id", "project_id", "memory_type", "content", "importance", "created_at", "accessed_at", "updated_at", "ttl"},
+			client.WithSearchQueryConsistencyLevel(toMilvusConsistencyLevel(s.config.ConsistencyLevel)),
+		)
+		return queryErr
+	})
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
@@ -437,28 +1585,135 @@ func (s *MilvusStore) Get(ctx context.Context, id string) (*Memory, error) {
 			c := col.(*entity.ColumnInt64)
 			ts, _ := c.ValueByIdx(0)
 			mem.CreatedAt = time.Unix(ts, 0)
+		case "accessed_at":
+			c := col.(*entity.ColumnInt64)
+			ts, _ := c.ValueByIdx(0)
+			mem.AccessedAt = time.Unix(ts, 0)
+		case "ttl":
+			c := col.(*entity.ColumnInt64)
+			ts, _ := c.ValueByIdx(0)
+			if ts != 0 {
+				mem.TTL = time.Unix(ts, 0)
+			}
 		}
 	}
 
+	if isExpired(&mem, time.Now()) {
+		return nil, ErrNotFound
+	}
+
+	if s.access != nil {
+		s.access.Record(mem.ID, time.Now())
+	}
+
 	return &mem, nil
 }
 
 // Update modifies an existing memory
 func (s *MilvusStore) Update(ctx context.Context, id string, memory *Memory) error {
-	// Milvus doesn't support direct updates - delete and re-insert
-	if err := s.Forget(ctx, id); err != nil && err != ErrNotFound {
+	// Milvus doesn't support direct updates - delete and re-insert. This goes
+	// through deleteByID rather than Forget so the re-inserted row isn't
+	// logged as a tombstone: to a StoreSync caller this is an update, not a
+	// removal.
+	if err := s.deleteByID(ctx, id); err != nil && err != ErrNotFound {
 		return err
 	}
 	memory.ID = id
 	return s.Store(ctx, memory)
 }
 
+// scopeRowsForExpr queries id/user_id/project_id/memory_type for every row
+// matching expr, so a delete path can record an accurate tombstone (see
+// addTombstones) for rows it's about to remove. Query failures are
+// swallowed: a missing tombstone only degrades sync, it must never block
+// the delete itself.
+func (s *MilvusStore) scopeRowsForExpr(ctx context.Context, expr string) []tombstone {
+	cols, err := s.client.Query(ctx, s.collectionName, []string{}, expr,
+		[]string{"id", "user_id", "project_id", "memory_type"})
+	if err != nil {
+		return nil
+	}
+
+	var ids, userIDs, projectIDs, memTypes []string
+	for _, col := range cols {
+		switch c := col.(type) {
+		case *entity.ColumnVarChar:
+			switch col.Name() {
+			case "id":
+				ids = c.Data()
+			case "user_id":
+				userIDs = c.Data()
+			case "project_id":
+				projectIDs = c.Data()
+			case "memory_type":
+				memTypes = c.Data()
+			}
+		}
+	}
+
+	rows := make([]tombstone, 0, len(ids))
+	now := time.Now()
+	for i, id := range ids {
+		t := tombstone{id: id, forgottenAt: now}
+		if i < len(userIDs) {
+			t.userID = userIDs[i]
+		}
+		if i < len(projectIDs) {
+			t.projectID = projectIDs[i]
+		}
+		if i < len(memTypes) {
+			t.memType = MemoryType(memTypes[i])
+		}
+		rows = append(rows, t)
+	}
+	return rows
+}
+
+// addTombstones appends rows to the bounded tombstone log, trimming the
+// oldest entries if the log would exceed maxTombstoneLog.
+func (s *MilvusStore) addTombstones(rows []tombstone) {
+	if len(rows) == 0 {
+		return
+	}
+	s.tombMu.Lock()
+	defer s.tombMu.Unlock()
+	s.tombstones = append(s.tombstones, rows...)
+	if len(s.tombstones) > maxTombstoneLog {
+		s.tombstones = s.tombstones[len(s.tombstones)-maxTombstoneLog:]
+	}
+}
+
 // Forget removes a memory by ID
 func (s *MilvusStore) Forget(ctx context.Context, id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if err := s.client.Delete(ctx, s.collectionName, "", fmt.Sprintf("id == \"%s\"", id)); err != nil {
+	expr := fmt.Sprintf("id == \"%s\"", id)
+	if tenantID := s.resolveTenant(ctx); tenantID != "" {
+		expr += fmt.Sprintf(" && tenant_id == \"%s\"", tenantID)
+	}
+
+	rows := s.scopeRowsForExpr(ctx, expr)
+	if err := s.client.Delete(ctx, s.collectionName, "", expr); err != nil {
+		return fmt.Errorf("forget failed: %w", err)
+	}
+	s.addTombstones(rows)
+	return nil
+}
+
+// deleteByID removes a row by ID without recording a tombstone, for Update's
+// internal delete-then-reinsert path. Callers that hold s.mu should not use
+// this directly; it takes the lock itself like Forget does.
+func (s *MilvusStore) deleteByID(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expr := fmt.Sprintf("id == \"%s\"", id)
+	if tenantID := s.resolveTenant(ctx); tenantID != "" {
+		expr += fmt.Sprintf(" && tenant_id == \"%s\"", tenantID)
+	}
+
+	if err := s.client.Delete(ctx, s.collectionName, "", expr); err != nil {
 		return fmt.Errorf("forget failed: %w", err)
 	}
 	return nil
@@ -469,6 +1724,27 @@ func (s *MilvusStore) ForgetByScope(ctx context.Context, scope MemoryScope) erro
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	tenantID := s.resolveTenant(ctx)
+
+	// A user-only scope maps exactly to one partition: dropping it is far
+	// cheaper than a filtered delete that has to scan every row. Skipped under
+	// tenant scoping since a partition isn't guaranteed to belong to one tenant.
+	// Trade-off: this path doesn't know which IDs it dropped, so it can't add
+	// tombstones - a StoreSync client covering this scope must periodically
+	// fall back to a full resync rather than relying solely on Tombstones.
+	if tenantID == "" && s.config.Partitioned && !s.config.UsePartitionKeyField &&
+		scope.UserID != "" && scope.ProjectID == "" && scope.Type == "" &&
+		scope.Before == nil && scope.After == nil {
+		partition := s.partitionKeyFor(scope.UserID, "")
+		if partition != "" {
+			if err := s.client.DropPartition(ctx, s.collectionName, partition); err != nil {
+				return fmt.Errorf("forget by scope (drop partition %q) failed: %w", partition, err)
+			}
+			s.partitions.Remove(partition)
+			return nil
+		}
+	}
+
 	// Build filter expression
 	var filters []string
 	if scope.UserID != "" {
@@ -480,6 +1756,15 @@ func (s *MilvusStore) ForgetByScope(ctx context.Context, scope MemoryScope) erro
 	if scope.Type != "" {
 		filters = append(filters, fmt.Sprintf("memory_type == \"%s\"", scope.Type))
 	}
+	if tenantID != "" {
+		filters = append(filters, fmt.Sprintf("tenant_id == \"%s\"", tenantID))
+	}
+	if scope.Before != nil {
+		filters = append(filters, fmt.Sprintf("created_at < %d", scope.Before.Unix()))
+	}
+	if scope.After != nil {
+		filters = append(filters, fmt.Sprintf("created_at > %d", scope.After.Unix()))
+	}
 
 	if len(filters) == 0 {
 		return nil // No scope specified, don't delete anything
@@ -490,9 +1775,51 @@ func (s *MilvusStore) ForgetByScope(ctx context.Context, scope MemoryScope) erro
 		filter += " && " + filters[i]
 	}
 
-	if err := s.client.Delete(ctx, s.collectionName, "", filter); err != nil {
+	partition := s.partitionKeyFor(scope.UserID, scope.ProjectID)
+	rows := s.scopeRowsForExpr(ctx, filter)
+	if err := s.client.Delete(ctx, s.collectionName, partition, filter); err != nil {
 		return fmt.Errorf("forget by scope failed: %w", err)
 	}
+	s.addTombstones(rows)
+	return nil
+}
+
+// UpdateScore overwrites a memory's Importance with score. Milvus has no
+// in-place column update, so this fetches the memory and re-inserts it
+// through Update - the same delete-then-Store path any other field change
+// already takes.
+func (s *MilvusStore) UpdateScore(ctx context.Context, id string, score float64) error {
+	mem, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	mem.Importance = score
+	return s.Update(ctx, id, mem)
+}
+
+// BulkDelete removes every memory in ids with a single Milvus delete call.
+func (s *MilvusStore) BulkDelete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = fmt.Sprintf("%q", id)
+	}
+	expr := fmt.Sprintf("id in [%s]", strings.Join(quoted, ", "))
+	if tenantID := s.resolveTenant(ctx); tenantID != "" {
+		expr += fmt.Sprintf(" && tenant_id == \"%s\"", tenantID)
+	}
+
+	rows := s.scopeRowsForExpr(ctx, expr)
+	if err := s.client.Delete(ctx, s.collectionName, "", expr); err != nil {
+		return fmt.Errorf("bulk delete failed: %w", err)
+	}
+	s.addTombstones(rows)
 	return nil
 }
 
@@ -501,7 +1828,267 @@ func (s *MilvusStore) IsEnabled() bool {
 	return s.client != nil
 }
 
+// HealthCheck implements StoreHealthChecker by re-checking the gRPC
+// connection's health, the same signal NewMilvusStore's initial dial relies
+// on, without touching the collection or running a query.
+func (s *MilvusStore) HealthCheck(ctx context.Context) error {
+	if s.client == nil {
+		return fmt.Errorf("milvus: not connected")
+	}
+	state, err := s.client.CheckHealth(ctx)
+	if err != nil {
+		return fmt.Errorf("milvus: health check failed: %w", err)
+	}
+	if state == nil || !state.IsHealthy {
+		return fmt.Errorf("milvus: connection is not healthy")
+	}
+	return nil
+}
+
 // Close releases resources
 func (s *MilvusStore) Close() error {
+	if s.credentialRenewer != nil {
+		s.credentialRenewer.Stop()
+	}
+	if s.stopFlusher != nil {
+		close(s.stopFlusher)
+		<-s.flusherDone
+	}
+	if s.access != nil {
+		s.access.Stop()
+	}
 	return s.client.Close()
 }
+
+// renewCredential adapts CredentialRenewer's onRenew callback to
+// RotateCredential, logging rather than propagating a failure - the renewer
+// keeps retrying on its own schedule (RenewBehaviorIgnoreErrors semantics),
+// and the existing connection stays in use with its last-known-good
+// credential until a rotation succeeds.
+func (s *MilvusStore) renewCredential(ctx context.Context, cred Credential) {
+	if err := s.RotateCredential(ctx, cred); err != nil {
+		logging.Warnf("MilvusMemoryStore: credential rotation failed, keeping existing connection: %v", err)
+	}
+}
+
+// RotateCredential implements CredentialRotator: it dials a fresh gRPC
+// client authenticated with cred and, once that succeeds, swaps it in for
+// the live connection and closes the old one. The collection is already
+// initialized, so rotation is just a reconnect, not a full NewMilvusStore.
+func (s *MilvusStore) RotateCredential(ctx context.Context, cred Credential) error {
+	s.credMu.Lock()
+	defer s.credMu.Unlock()
+
+	newClient, err := client.NewDefaultGrpcClientWithAPIKey(ctx, s.config.Address, cred.Value)
+	if err != nil {
+		return fmt.Errorf("milvus: reconnect with renewed credential: %w", err)
+	}
+
+	s.mu.Lock()
+	oldClient := s.client
+	s.client = newClient
+	s.mu.Unlock()
+
+	if err := oldClient.Close(); err != nil {
+		logging.Warnf("MilvusMemoryStore: closing pre-rotation connection: %v", err)
+	}
+	logging.Infof("MilvusMemoryStore: rotated connection credential")
+	return nil
+}
+
+// UpdatedSince implements StoreSync, querying the updated_at index column
+// for every row in scope newer than ts, most-recently-updated first.
+func (s *MilvusStore) UpdatedSince(ctx context.Context, scope MemoryScope, ts time.Time) ([]*Memory, error) {
+	var filters []string
+	if scope.UserID != "" {
+		filters = append(filters, fmt.Sprintf("user_id == \"%s\"", scope.UserID))
+	}
+	if scope.ProjectID != "" {
+		filters = append(filters, fmt.Sprintf("project_id == \"%s\"", scope.ProjectID))
+	}
+	if scope.Type != "" {
+		filters = append(filters, fmt.Sprintf("memory_type == \"%s\"", scope.Type))
+	}
+	filters = append(filters, fmt.Sprintf("updated_at > %d", ts.Unix()))
+	if tenantID := s.resolveTenant(ctx); tenantID != "" {
+		filters = append(filters, fmt.Sprintf("tenant_id == \"%s\"", tenantID))
+	}
+	filter := filters[0]
+	for i := 1; i < len(filters); i++ {
+		filter += " && " + filters[i]
+	}
+
+	cols, err := s.client.Query(ctx, s.collectionName, []string{}, filter,
+		[]string{"id", "user_id", "project_id", "memory_type", "content", "importance", "created_at", "accessed_at", "updated_at"},
+		client.WithSearchQueryConsistencyLevel(toMilvusConsistencyLevel(s.config.ConsistencyLevel)))
+	if err != nil {
+		return nil, fmt.Errorf("updated since query failed: %w", err)
+	}
+
+	rowCount := 0
+	for _, col := range cols {
+		if col.Len() > rowCount {
+			rowCount = col.Len()
+		}
+	}
+
+	results := make([]*Memory, rowCount)
+	for i := range results {
+		results[i] = &Memory{}
+	}
+	for _, col := range cols {
+		switch c := col.(type) {
+		case *entity.ColumnVarChar:
+			for i, v := range c.Data() {
+				switch col.Name() {
+				case "id":
+					results[i].ID = v
+				case "user_id":
+					results[i].UserID = v
+				case "project_id":
+					results[i].ProjectID = v
+				case "memory_type":
+					results[i].Type = MemoryType(v)
+				case "content":
+					results[i].Content = v
+				}
+			}
+		case *entity.ColumnFloat:
+			for i, v := range c.Data() {
+				if col.Name() == "importance" {
+					results[i].Importance = float64(v)
+				}
+			}
+		case *entity.ColumnInt64:
+			for i, v := range c.Data() {
+				switch col.Name() {
+				case "created_at":
+					results[i].CreatedAt = time.Unix(v, 0)
+				case "accessed_at":
+					results[i].AccessedAt = time.Unix(v, 0)
+				case "updated_at":
+					results[i].UpdatedAt = time.Unix(v, 0)
+				}
+			}
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].UpdatedAt.After(results[j].UpdatedAt) })
+	return results, nil
+}
+
+// Tombstones implements StoreSync from the in-process bounded log populated
+// by Forget/ForgetByScope/BulkDelete. It doesn't survive a process restart
+// and misses deletes from ForgetByScope's drop-partition fast path (see the
+// comment there) - a sync client should periodically reconcile with a full
+// UpdatedSince-based diff rather than relying on this alone.
+func (s *MilvusStore) Tombstones(ctx context.Context, scope MemoryScope, ts time.Time) ([]string, error) {
+	s.tombMu.Lock()
+	defer s.tombMu.Unlock()
+
+	type idAt struct {
+		id string
+		at time.Time
+	}
+	var matched []idAt
+	for _, t := range s.tombstones {
+		if !t.forgottenAt.After(ts) {
+			continue
+		}
+		if !tombstoneInScope(t, scope) {
+			continue
+		}
+		matched = append(matched, idAt{id: t.id, at: t.forgottenAt})
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].at.After(matched[j].at) })
+	ids := make([]string, len(matched))
+	for i, m := range matched {
+		ids[i] = m.id
+	}
+	return ids, nil
+}
+
+// ExpireDue implements StoreExpirer, deleting every row whose ttl is set
+// and has passed. Retrieve and Get already filter expired rows out of
+// their own results (see isExpired), so this is purely reclaiming storage,
+// typically driven by an ExpiryReaper on a ticker rather than called inline.
+func (s *MilvusStore) ExpireDue(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filter := fmt.Sprintf("ttl > 0 && ttl <= %d", time.Now().Unix())
+	if tenantID := s.resolveTenant(ctx); tenantID != "" {
+		filter += fmt.Sprintf(" && tenant_id == \"%s\"", tenantID)
+	}
+
+	rows := s.scopeRowsForExpr(ctx, filter)
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if err := s.client.Delete(ctx, s.collectionName, "", filter); err != nil {
+		return 0, fmt.Errorf("expire due failed: %w", err)
+	}
+	s.addTombstones(rows)
+	return len(rows), nil
+}
+
+// Apply implements StoreApply using StoreBatch for Put ops and BulkDelete
+// for Forget ops, so a caller ingesting a page of conversation turns pays
+// for one insert and one delete round-trip instead of one per fact. Unlike
+// InMemoryStore.Apply, this is best-effort, not atomic: Milvus has no
+// multi-statement transaction, so a failure partway through (e.g. the batch
+// insert succeeds but a later Update fails) leaves the earlier ops applied.
+func (s *MilvusStore) Apply(ctx context.Context, ops []MemoryOp) (ApplyResult, error) {
+	var result ApplyResult
+	var puts []*Memory
+
+	for _, op := range ops {
+		switch op.Kind {
+		case MemoryOpPut:
+			if op.Dedup {
+				config := DefaultDeduplicationConfig()
+				if op.DedupConfig != nil {
+					config = *op.DedupConfig
+				}
+				dedup := CheckDeduplication(ctx, s, op.Memory.UserID, op.Memory.Content, op.Memory.Type, op.Memory.OccurredAt, config)
+				if dedup.Action == "update" && dedup.ExistingMemory != nil {
+					dedup.ExistingMemory.Content = op.Memory.Content
+					if err := s.Update(ctx, dedup.ExistingMemory.ID, dedup.ExistingMemory); err != nil {
+						return result, fmt.Errorf("memory: apply put (deduplicated) %s: %w", dedup.ExistingMemory.ID, err)
+					}
+					result.Updated = append(result.Updated, dedup.ExistingMemory.ID)
+					result.Deduplicated++
+					continue
+				}
+			}
+			puts = append(puts, op.Memory)
+
+		case MemoryOpUpdate:
+			if err := s.Update(ctx, op.ID, op.Memory); err != nil {
+				return result, fmt.Errorf("memory: apply update %s: %w", op.ID, err)
+			}
+			result.Updated = append(result.Updated, op.ID)
+
+		case MemoryOpForget:
+			if err := s.Forget(ctx, op.ID); err != nil {
+				return result, fmt.Errorf("memory: apply forget %s: %w", op.ID, err)
+			}
+			result.Forgotten = append(result.Forgotten, op.ID)
+
+		default:
+			return result, fmt.Errorf("memory: apply: unknown MemoryOp kind %q", op.Kind)
+		}
+	}
+
+	if len(puts) > 0 {
+		if err := s.StoreBatch(ctx, puts); err != nil {
+			return result, fmt.Errorf("memory: apply batch put: %w", err)
+		}
+		for _, m := range puts {
+			result.Created = append(result.Created, m.ID)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,119 @@
+//go:build !windows && cgo
+
+package memory
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// TenantResolver extracts a tenant ID from a request context, e.g. reading it
+// off of an auth claim set earlier in the request pipeline. A nil/empty
+// result disables tenant scoping for that call.
+type TenantResolver func(ctx context.Context) string
+
+// tenantRole and tenantUser derive the Milvus role/credential names for a
+// tenant ID. Kept deterministic so DropTenant can reconstruct them without
+// needing to track tenant metadata separately.
+func tenantRole(tenantID string) string { return "tenant_" + sanitizePartitionComponent(tenantID) }
+func tenantUser(tenantID string) string { return "tenant_user_" + sanitizePartitionComponent(tenantID) }
+
+// tenantPrivileges are the operations a tenant's role is granted on the
+// collection, scoped to its own rows by the tenant_id filter every query
+// already injects.
+var tenantPrivileges = []string{"Search", "Query", "Insert", "Delete"}
+
+// resolveTenant runs the configured TenantResolver, returning "" if none is
+// configured or it yields no tenant.
+func (s *MilvusStore) resolveTenant(ctx context.Context) string {
+	if s.config.TenantResolver == nil {
+		return ""
+	}
+	return s.config.TenantResolver(ctx)
+}
+
+// ensureTenant provisions a Milvus role, collection grants, and a credential
+// for tenantID the first time it's seen, so tenants are isolated at the
+// access-control layer and not just by query filter. No-op when RBACEnabled
+// is false or the tenant was already provisioned.
+func (s *MilvusStore) ensureTenant(ctx context.Context, tenantID string) error {
+	if !s.config.RBACEnabled || tenantID == "" {
+		return nil
+	}
+	if s.tenants.Has(tenantID) {
+		return nil
+	}
+
+	role := tenantRole(tenantID)
+	if err := s.client.CreateRole(ctx, role); err != nil {
+		return fmt.Errorf("failed to create role %q: %w", role, err)
+	}
+
+	for _, privilege := range tenantPrivileges {
+		if err := s.client.Grant(ctx, role, entity.PriviledgeObjectCollection, s.collectionName, privilege); err != nil {
+			return fmt.Errorf("failed to grant %q on %q to role %q: %w", privilege, s.collectionName, role, err)
+		}
+	}
+
+	username := tenantUser(tenantID)
+	password, err := randomPassword()
+	if err != nil {
+		return fmt.Errorf("failed to generate credential for tenant %q: %w", tenantID, err)
+	}
+	if err := s.client.CreateCredential(ctx, username, password); err != nil {
+		return fmt.Errorf("failed to create credential %q: %w", username, err)
+	}
+	if err := s.client.AddUserRole(ctx, username, role); err != nil {
+		return fmt.Errorf("failed to bind role %q to user %q: %w", role, username, err)
+	}
+
+	s.tenants.Add(tenantID)
+	logging.Infof("MilvusMemoryStore: provisioned tenant %q (role=%q user=%q)", tenantID, role, username)
+	return nil
+}
+
+// DropTenant tears down a tenant's Milvus identity and deletes its rows:
+// revokes grants, drops the role, deletes the credential, then issues a
+// Delete scoped to tenant_id. Mirrors the "delete user-role mapping when
+// deleting user" cleanup pattern used elsewhere for partitions.
+func (s *MilvusStore) DropTenant(ctx context.Context, tenantID string) error {
+	if tenantID == "" {
+		return fmt.Errorf("tenant ID is required")
+	}
+
+	role := tenantRole(tenantID)
+	username := tenantUser(tenantID)
+
+	for _, privilege := range tenantPrivileges {
+		if err := s.client.Revoke(ctx, role, entity.PriviledgeObjectCollection, s.collectionName, privilege); err != nil {
+			logging.Warnf("MilvusMemoryStore: failed to revoke %q from role %q: %v", privilege, role, err)
+		}
+	}
+	if err := s.client.DeleteCredential(ctx, username); err != nil {
+		logging.Warnf("MilvusMemoryStore: failed to delete credential %q: %v", username, err)
+	}
+	if err := s.client.DropRole(ctx, role); err != nil {
+		logging.Warnf("MilvusMemoryStore: failed to drop role %q: %v", role, err)
+	}
+	s.tenants.Remove(tenantID)
+
+	if err := s.client.Delete(ctx, s.collectionName, "", fmt.Sprintf("tenant_id == \"%s\"", tenantID)); err != nil {
+		return fmt.Errorf("failed to delete rows for tenant %q: %w", tenantID, err)
+	}
+	return nil
+}
+
+// randomPassword generates a credential for a newly-provisioned tenant user.
+func randomPassword() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
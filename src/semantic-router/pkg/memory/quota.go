@@ -0,0 +1,56 @@
+package memory
+
+// EvictionPolicy selects which of a user's existing memories a quota
+// enforcer removes first once that user is over MaxMemoriesPerUser or
+// MaxBytesPerUser.
+type EvictionPolicy string
+
+const (
+	// EvictionPolicyLRU evicts the least-recently-accessed memory first
+	// (lowest Memory.AccessedAt).
+	EvictionPolicyLRU EvictionPolicy = "lru"
+
+	// EvictionPolicyLFU evicts the least-frequently-accessed memory first
+	// (lowest Memory.AccessCount).
+	EvictionPolicyLFU EvictionPolicy = "lfu"
+
+	// EvictionPolicyImportance evicts the lowest-Importance memory first,
+	// regardless of how recently or often it was accessed.
+	EvictionPolicyImportance EvictionPolicy = "importance"
+)
+
+// QuotaConfig bounds per-user memory growth. It lives alongside StoreConfig
+// (see StoreConfig.Quota in factory.go/factory_nocgo.go) rather than inside
+// any one backend's config, since enforcement happens above the Store
+// interface - in pkg/extproc's MemoryFilter - not inside a specific
+// backend: a quota is a policy about callers, not a property of where the
+// bytes end up.
+type QuotaConfig struct {
+	// MaxMemoriesPerUser caps how many memories a single user may have
+	// stored at once. 0 means unlimited.
+	MaxMemoriesPerUser int
+
+	// MaxBytesPerUser caps the total size, in UTF-8 bytes of Content, of a
+	// single user's memories. 0 means unlimited.
+	MaxBytesPerUser int64
+
+	// MaxWritesPerSecond caps how often a single user may call Store,
+	// enforced with the same token-bucket algorithm pkg/ratelimit uses for
+	// request admission. 0 means unlimited.
+	MaxWritesPerSecond float64
+
+	// EvictionPolicy selects which memory to remove when a new Store call
+	// would push a user over MaxMemoriesPerUser or MaxBytesPerUser. Defaults
+	// to EvictionPolicyLRU if unset.
+	EvictionPolicy EvictionPolicy
+}
+
+// DefaultQuotaConfig returns a QuotaConfig with every limit disabled.
+func DefaultQuotaConfig() QuotaConfig {
+	return QuotaConfig{EvictionPolicy: EvictionPolicyLRU}
+}
+
+// Enabled reports whether any limit in c is active.
+func (c QuotaConfig) Enabled() bool {
+	return c.MaxMemoriesPerUser > 0 || c.MaxBytesPerUser > 0 || c.MaxWritesPerSecond > 0
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/metrics"
+)
+
+// Role identifies the speaker of a single conversation turn. It replaces the
+// plain `Role string` field previously carried by ChatCompletionMessage and
+// Message, so callers can no longer typo a role name past the compiler.
+type Role string
+
+const (
+	RoleSystem    Role = "system"
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+	RoleTool      Role = "tool"
+	RoleDeveloper Role = "developer"
+)
+
+// validRoles is used to validate roles parsed off the wire.
+var validRoles = map[Role]struct{}{
+	RoleSystem:    {},
+	RoleUser:      {},
+	RoleAssistant: {},
+	RoleTool:      {},
+	RoleDeveloper: {},
+}
+
+// IsValid reports whether r is one of the known roles.
+func (r Role) IsValid() bool {
+	_, ok := validRoles[r]
+	return ok
+}
+
+// MarshalJSON implements json.Marshaler. Unknown or empty roles are not
+// rejected here - they're normalized to RoleUser by UnmarshalJSON on the way
+// in, so by the time a Role is marshaled back out it's always one we know.
+func (r Role) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(r))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. This is the migration shim: it
+// accepts any legacy string a client may still be sending (including values
+// that predate this enum), and defaults anything empty or unrecognized to
+// RoleUser rather than failing the request, so older clients keep working
+// while we roll this out. The fallback is logged so operators can track how
+// much traffic is still hitting it and retire the shim once it's quiet.
+func (r *Role) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("memory: role must be a string: %w", err)
+	}
+
+	role := Role(s)
+	if !role.IsValid() {
+		logging.Warnf("memory: unrecognized role %q, defaulting to %q (legacy client migration shim)", s, RoleUser)
+		metrics.RecordMemoryRoleFallback(s)
+		role = RoleUser
+	}
+	*r = role
+	return nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRole_IsValid(t *testing.T) {
+	tests := []struct {
+		name  string
+		role  Role
+		valid bool
+	}{
+		{"system", RoleSystem, true},
+		{"user", RoleUser, true},
+		{"assistant", RoleAssistant, true},
+		{"tool", RoleTool, true},
+		{"developer", RoleDeveloper, true},
+		{"empty", Role(""), false},
+		{"unknown", Role("narrator"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.role.IsValid(); got != tt.valid {
+				t.Errorf("Role(%q).IsValid() = %v, want %v", tt.role, got, tt.valid)
+			}
+		})
+	}
+}
+
+func TestRole_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want Role
+	}{
+		{"known role", `"assistant"`, RoleAssistant},
+		{"empty string defaults to user", `""`, RoleUser},
+		{"unknown legacy value defaults to user", `"narrator"`, RoleUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Role
+			if err := json.Unmarshal([]byte(tt.in), &r); err != nil {
+				t.Fatalf("UnmarshalJSON(%s) returned error: %v", tt.in, err)
+			}
+			if r != tt.want {
+				t.Errorf("UnmarshalJSON(%s) = %q, want %q", tt.in, r, tt.want)
+			}
+		})
+	}
+}
+
+func TestRole_UnmarshalJSON_NotAString(t *testing.T) {
+	var r Role
+	if err := json.Unmarshal([]byte(`42`), &r); err == nil {
+		t.Fatal("expected error unmarshaling a non-string role, got nil")
+	}
+}
+
+func TestRole_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(RoleUser)
+	if err != nil {
+		t.Fatalf("MarshalJSON returned error: %v", err)
+	}
+	if string(data) != `"user"` {
+		t.Errorf("MarshalJSON(RoleUser) = %s, want %q", data, "user")
+	}
+}
@@ -0,0 +1,348 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+)
+
+// Scorer ranks memories against a query for InMemoryStore.Retrieve (and any
+// other caller that wants pluggable relevance scoring instead of the fixed
+// keyword heuristic). Pass one via InMemoryStoreConfig.Scorer; the zero
+// value defaults to NewKeywordScorer().
+type Scorer interface {
+	// Score returns one relevance score per memories[i], in the same order.
+	// Scores are only meaningful relative to each other within a single
+	// call - callers should not assume any particular range or compare
+	// scores across calls. Implementations that call out to an Embedder
+	// (hybridScorer) must pass ctx through to it, so Retrieve's deadline
+	// actually cancels in-flight embedding calls instead of only cancelling
+	// the caller's wait.
+	Score(ctx context.Context, query string, memories []*Memory) []float64
+}
+
+// Embedder produces vector embeddings for text. NewHybridScorer uses one to
+// compute query embeddings at retrieve time; InMemoryStore's embeddingCache
+// (see hybridScorer) keeps it from being asked to re-embed stored content on
+// every call.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// scorerMaintainer is implemented by scorers that keep state derived from
+// the live memory set - an inverted index, a cached embedding - rather than
+// recomputing everything from scratch on each Score call. InMemoryStore
+// notifies them on every Store/Update/Forget so that state never drifts
+// from what's actually stored. NewKeywordScorer doesn't implement this: it
+// has no state to keep current.
+type scorerMaintainer interface {
+	onStore(mem *Memory)
+	onUpdate(mem *Memory)
+	onForget(id string)
+}
+
+// =============================================================================
+// Keyword scorer (default)
+// =============================================================================
+
+// keywordScorer scores with keywordRelevanceScore, the original substring-
+// overlap heuristic InMemoryStore.Retrieve used inline before Scorer
+// existed. No setup cost, no external dependency, but misses paraphrases
+// and over-counts stopwords.
+type keywordScorer struct{}
+
+// NewKeywordScorer returns the default Scorer: InMemoryStore's original
+// lowercase substring-overlap heuristic.
+func NewKeywordScorer() Scorer {
+	return keywordScorer{}
+}
+
+func (keywordScorer) Score(ctx context.Context, query string, memories []*Memory) []float64 {
+	scores := make([]float64, len(memories))
+	for i, mem := range memories {
+		scores[i] = keywordRelevanceScore(mem.Content, query)
+	}
+	return scores
+}
+
+// =============================================================================
+// BM25 scorer
+// =============================================================================
+
+// BM25 parameters from Robertson/Spärck Jones' Okapi BM25: k1 controls term
+// frequency saturation, b controls document-length normalization strength.
+const (
+	bm25K1 = 1.5
+	bm25B  = 0.75
+)
+
+// bm25Scorer ranks memories with Okapi BM25 over an inverted index
+// maintained as memories are stored/updated/forgotten, rather than
+// recomputed per query:
+//
+//	score(q, d) = sum_{t in q} IDF(t) * tf(t,d)*(k1+1) / (tf(t,d) + k1*(1 - b + b*|d|/avgdl))
+//	IDF(t) = ln(1 + (N - df(t) + 0.5) / (df(t) + 0.5))
+//
+// where N is the indexed document count, df(t) the number of documents
+// containing t, and avgdl the mean document length across the index.
+type bm25Scorer struct {
+	mu       sync.RWMutex
+	termFreq map[string]map[string]int // memory ID -> term -> count in that memory
+	docLen   map[string]int            // memory ID -> token count
+	docFreq  map[string]int            // term -> number of memories containing it
+	totalLen int                       // sum of docLen, for avgdl
+}
+
+// NewBM25Scorer returns a Scorer that ranks by Okapi BM25 over content
+// tokenized with strings.Fields. It must be passed to InMemoryStoreConfig.Scorer
+// (not reused across stores) since its index is tied to one store's
+// Store/Update/Forget calls.
+func NewBM25Scorer() Scorer {
+	return &bm25Scorer{
+		termFreq: make(map[string]map[string]int),
+		docLen:   make(map[string]int),
+		docFreq:  make(map[string]int),
+	}
+}
+
+func bm25Tokenize(text string) []string {
+	return strings.Fields(strings.ToLower(text))
+}
+
+func (s *bm25Scorer) onStore(mem *Memory)  { s.index(mem) }
+func (s *bm25Scorer) onUpdate(mem *Memory) { s.index(mem) }
+
+// index (re)computes mem's entry in the inverted index, first removing any
+// prior entry for the same ID so Update doesn't double-count terms from the
+// old content.
+func (s *bm25Scorer) index(mem *Memory) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeLocked(mem.ID)
+
+	tokens := bm25Tokenize(mem.Content)
+	tf := make(map[string]int, len(tokens))
+	for _, tok := range tokens {
+		tf[tok]++
+	}
+	s.termFreq[mem.ID] = tf
+	s.docLen[mem.ID] = len(tokens)
+	s.totalLen += len(tokens)
+	for term := range tf {
+		s.docFreq[term]++
+	}
+}
+
+func (s *bm25Scorer) onForget(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeLocked(id)
+}
+
+// removeLocked drops id from the index if present. Callers must hold s.mu.
+func (s *bm25Scorer) removeLocked(id string) {
+	tf, ok := s.termFreq[id]
+	if !ok {
+		return
+	}
+	for term := range tf {
+		s.docFreq[term]--
+		if s.docFreq[term] <= 0 {
+			delete(s.docFreq, term)
+		}
+	}
+	s.totalLen -= s.docLen[id]
+	delete(s.termFreq, id)
+	delete(s.docLen, id)
+}
+
+func (s *bm25Scorer) Score(ctx context.Context, query string, memories []*Memory) []float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	scores := make([]float64, len(memories))
+	docCount := len(s.termFreq)
+	if docCount == 0 {
+		return scores
+	}
+	avgdl := float64(s.totalLen) / float64(docCount)
+	queryTerms := bm25Tokenize(query)
+
+	for i, mem := range memories {
+		tf, ok := s.termFreq[mem.ID]
+		if !ok {
+			continue
+		}
+		dl := float64(s.docLen[mem.ID])
+		var score float64
+		for _, term := range queryTerms {
+			freq := tf[term]
+			if freq == 0 {
+				continue
+			}
+			df := float64(s.docFreq[term])
+			idf := math.Log(1 + (float64(docCount)-df+0.5)/(df+0.5))
+			numerator := float64(freq) * (bm25K1 + 1)
+			denominator := float64(freq) + bm25K1*(1-bm25B+bm25B*dl/avgdl)
+			score += idf * numerator / denominator
+		}
+		scores[i] = score
+	}
+	return scores
+}
+
+// =============================================================================
+// Hybrid scorer (BM25 + embedding cosine similarity)
+// =============================================================================
+
+// defaultHybridWeight is the weight hybridScorer puts on the (normalized)
+// BM25 term when NewHybridScorer is given a non-positive weight.
+const defaultHybridWeight = 0.5
+
+// embeddingCache caches an Embedder's output per memory ID so hybridScorer
+// doesn't re-embed stored content on every Retrieve call. Entries are
+// invalidated on Update (content may have changed) and Forget.
+type embeddingCache struct {
+	mu   sync.RWMutex
+	byID map[string][]float32
+}
+
+func newEmbeddingCache() *embeddingCache {
+	return &embeddingCache{byID: make(map[string][]float32)}
+}
+
+func (c *embeddingCache) get(id string) ([]float32, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	emb, ok := c.byID[id]
+	return emb, ok
+}
+
+func (c *embeddingCache) set(id string, emb []float32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byID[id] = emb
+}
+
+func (c *embeddingCache) invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.byID, id)
+}
+
+// hybridScorer linearly blends a min-max-normalized BM25 score with cosine
+// similarity against cached embeddings: weight*bm25_norm + (1-weight)*cosine.
+// BM25 is normalized into [0,1] per call (cosine is already bounded) so the
+// two terms are comparable regardless of corpus size. Memories missing a
+// cached embedding are embedded on demand and cached for next time; a
+// memory whose embedding can't be produced (Embedder error) scores on BM25
+// alone.
+type hybridScorer struct {
+	bm25     *bm25Scorer
+	embedder Embedder
+	cache    *embeddingCache
+	weight   float64
+}
+
+// NewHybridScorer returns a Scorer blending Okapi BM25 with cosine
+// similarity over embedder's output, weight favoring BM25 (0 to 1; a
+// non-positive value defaults to 0.5, an even split).
+func NewHybridScorer(embedder Embedder, weight float64) Scorer {
+	if weight <= 0 {
+		weight = defaultHybridWeight
+	}
+	return &hybridScorer{
+		bm25:     NewBM25Scorer().(*bm25Scorer),
+		embedder: embedder,
+		cache:    newEmbeddingCache(),
+		weight:   weight,
+	}
+}
+
+func (s *hybridScorer) onStore(mem *Memory) { s.bm25.onStore(mem) }
+
+func (s *hybridScorer) onUpdate(mem *Memory) {
+	s.bm25.onUpdate(mem)
+	s.cache.invalidate(mem.ID)
+}
+
+func (s *hybridScorer) onForget(id string) {
+	s.bm25.onForget(id)
+	s.cache.invalidate(id)
+}
+
+func (s *hybridScorer) Score(ctx context.Context, query string, memories []*Memory) []float64 {
+	bm25Scores := s.bm25.Score(ctx, query, memories)
+	normalized := minMaxNormalize(bm25Scores)
+
+	queryEmbedding, err := s.embedOne(ctx, query)
+	if err != nil {
+		return normalized
+	}
+
+	scores := make([]float64, len(memories))
+	for i, mem := range memories {
+		cosine := 0.0
+		if emb, ok := s.memoryEmbedding(ctx, mem); ok {
+			cosine = cosineSimilarity(queryEmbedding, emb)
+		}
+		scores[i] = s.weight*normalized[i] + (1-s.weight)*cosine
+	}
+	return scores
+}
+
+// memoryEmbedding returns mem's cached embedding, computing and caching it
+// first if this is the first time mem has been scored.
+func (s *hybridScorer) memoryEmbedding(ctx context.Context, mem *Memory) ([]float32, bool) {
+	if emb, ok := s.cache.get(mem.ID); ok {
+		return emb, true
+	}
+	if len(mem.Embedding) > 0 {
+		s.cache.set(mem.ID, mem.Embedding)
+		return mem.Embedding, true
+	}
+	emb, err := s.embedOne(ctx, mem.Content)
+	if err != nil {
+		return nil, false
+	}
+	s.cache.set(mem.ID, emb)
+	return emb, true
+}
+
+func (s *hybridScorer) embedOne(ctx context.Context, text string) ([]float32, error) {
+	out, err := s.embedder.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return out[0], nil
+}
+
+// minMaxNormalize rescales scores into [0,1]; all-equal input (including
+// all-zero, e.g. no BM25 match) maps to all zeros rather than dividing by
+// zero.
+func minMaxNormalize(scores []float64) []float64 {
+	if len(scores) == 0 {
+		return scores
+	}
+	min, max := scores[0], scores[0]
+	for _, s := range scores {
+		if s < min {
+			min = s
+		}
+		if s > max {
+			max = s
+		}
+	}
+	out := make([]float64, len(scores))
+	if max == min {
+		return out
+	}
+	for i, s := range scores {
+		out[i] = (s - min) / (max - min)
+	}
+	return out
+}
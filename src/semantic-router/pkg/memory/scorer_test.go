@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeywordScorer_MatchesKeywordRelevanceScore(t *testing.T) {
+	memories := []*Memory{
+		{ID: "a", Content: "User likes tea and coffee"},
+		{ID: "b", Content: "User dislikes spicy food"},
+	}
+	scores := NewKeywordScorer().Score(context.Background(), "tea coffee", memories)
+	require.Len(t, scores, 2)
+	assert.Equal(t, keywordRelevanceScore(memories[0].Content, "tea coffee"), scores[0])
+	assert.Equal(t, keywordRelevanceScore(memories[1].Content, "tea coffee"), scores[1])
+}
+
+func TestBM25Scorer_RanksMoreFrequentTermHigher(t *testing.T) {
+	scorer := NewBM25Scorer().(*bm25Scorer)
+	docs := []*Memory{
+		{ID: "a", Content: "tea tea tea coffee"},
+		{ID: "b", Content: "coffee and biscuits"},
+	}
+	for _, d := range docs {
+		scorer.onStore(d)
+	}
+
+	scores := scorer.Score(context.Background(), "tea", docs)
+	require.Len(t, scores, 2)
+	assert.Greater(t, scores[0], scores[1], "doc with more occurrences of the query term should score higher")
+}
+
+func TestBM25Scorer_UnknownTermScoresZero(t *testing.T) {
+	scorer := NewBM25Scorer().(*bm25Scorer)
+	doc := &Memory{ID: "a", Content: "User likes tea"}
+	scorer.onStore(doc)
+
+	scores := scorer.Score(context.Background(), "spaceship", []*Memory{doc})
+	assert.Equal(t, 0.0, scores[0])
+}
+
+func TestBM25Scorer_OnForgetRemovesFromIndex(t *testing.T) {
+	scorer := NewBM25Scorer().(*bm25Scorer)
+	doc := &Memory{ID: "a", Content: "User likes tea"}
+	scorer.onStore(doc)
+	scorer.onForget(doc.ID)
+
+	scores := scorer.Score(context.Background(), "tea", []*Memory{doc})
+	assert.Equal(t, 0.0, scores[0], "forgotten memory should no longer be in the index")
+}
+
+func TestBM25Scorer_OnUpdateReplacesOldContent(t *testing.T) {
+	scorer := NewBM25Scorer().(*bm25Scorer)
+	doc := &Memory{ID: "a", Content: "User likes tea"}
+	scorer.onStore(doc)
+
+	updated := &Memory{ID: "a", Content: "User likes coffee"}
+	scorer.onUpdate(updated)
+
+	scores := scorer.Score(context.Background(), "tea", []*Memory{updated})
+	assert.Equal(t, 0.0, scores[0], "old content's terms should no longer match after an update")
+
+	scores = scorer.Score(context.Background(), "coffee", []*Memory{updated})
+	assert.Greater(t, scores[0], 0.0)
+}
+
+func TestBM25Scorer_EmptyIndexScoresZero(t *testing.T) {
+	scorer := NewBM25Scorer().(*bm25Scorer)
+	scores := scorer.Score(context.Background(), "tea", []*Memory{{ID: "a", Content: "anything"}})
+	assert.Equal(t, []float64{0}, scores)
+}
+
+// fakeEmbedder maps each input text to a 1-dimensional "embedding" given by
+// vectors[text], so hybridScorer's cosine-similarity term is deterministic
+// without needing the real candle-binding model.
+type fakeEmbedder struct {
+	vectors map[string][]float32
+	calls   int
+}
+
+func (f *fakeEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	f.calls++
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i] = f.vectors[text]
+	}
+	return out, nil
+}
+
+func TestHybridScorer_BlendsBM25AndCosineSimilarity(t *testing.T) {
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"tea":                  {1, 0},
+		"User likes tea":       {1, 0},
+		"User dislikes coffee": {0, 1},
+	}}
+	scorer := NewHybridScorer(embedder, 0.5).(*hybridScorer)
+
+	docs := []*Memory{
+		{ID: "a", Content: "User likes tea"},
+		{ID: "b", Content: "User dislikes coffee"},
+	}
+	for _, d := range docs {
+		scorer.onStore(d)
+	}
+
+	scores := scorer.Score(context.Background(), "tea", docs)
+	require.Len(t, scores, 2)
+	assert.Greater(t, scores[0], scores[1], "doc matching both BM25 and embedding similarity should outrank the other")
+}
+
+func TestHybridScorer_CachesEmbeddingsAcrossRetrieves(t *testing.T) {
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"tea":            {1, 0},
+		"User likes tea": {1, 0},
+	}}
+	scorer := NewHybridScorer(embedder, 0.5).(*hybridScorer)
+
+	doc := &Memory{ID: "a", Content: "User likes tea"}
+	scorer.onStore(doc)
+
+	scorer.Score(context.Background(), "tea", []*Memory{doc})
+	scorer.Score(context.Background(), "tea", []*Memory{doc})
+
+	// One Embed call per Score call for the query itself, plus exactly one
+	// for the document the first time it's scored - not re-embedded the
+	// second time.
+	assert.Equal(t, 3, embedder.calls)
+}
+
+func TestHybridScorer_OnUpdateInvalidatesCachedEmbedding(t *testing.T) {
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"tea":               {1, 0},
+		"User likes tea":    {1, 0},
+		"User likes coffee": {0, 1},
+		"query irrelevant":  {0, 0},
+	}}
+	scorer := NewHybridScorer(embedder, 0.5).(*hybridScorer)
+
+	doc := &Memory{ID: "a", Content: "User likes tea"}
+	scorer.onStore(doc)
+	scorer.Score(context.Background(), "tea", []*Memory{doc}) // populate the cache
+
+	updated := &Memory{ID: "a", Content: "User likes coffee"}
+	scorer.onUpdate(updated)
+
+	_, cached := scorer.cache.get("a")
+	assert.False(t, cached, "Update should invalidate the cached embedding")
+}
+
+func TestMinMaxNormalize(t *testing.T) {
+	assert.Equal(t, []float64{0, 0.5, 1}, minMaxNormalize([]float64{1, 2, 3}))
+	assert.Equal(t, []float64{0, 0, 0}, minMaxNormalize([]float64{5, 5, 5}), "all-equal input should not divide by zero")
+	assert.Empty(t, minMaxNormalize(nil))
+}
@@ -2,13 +2,20 @@ package memory
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
 )
 
-// Common errors
+// Common errors. Both are *Error instances (see errors.go) rather than
+// plain errors.New values, so existing `err == ErrNotFound`/`err ==
+// ErrInvalidUserID` comparisons throughout this package keep working
+// unchanged - each is still a single shared pointer - while callers that
+// want the typed Code (IsCode, GRPCStatus, HTTPStatus) get it too.
 var (
-	ErrNotFound      = errors.New("memory not found")
-	ErrInvalidUserID = errors.New("user_id is required")
+	ErrNotFound      = newError(CodeNotFound, "", "", nil)
+	ErrInvalidUserID = newError(CodeInvalidUserID, "", "", nil)
 )
 
 // Store defines the interface for agentic memory storage.
@@ -17,6 +24,11 @@ var (
 // Implementations:
 //   - InMemoryStore: Development/testing (memory_store.go)
 //   - MilvusStore: Production with vector search (milvus_store.go)
+//   - SQLiteStore: Production, single-node persistence (store_sqlite.go)
+//   - PostgresStore: Production, shared across router replicas (store_postgres.go)
+//   - MongoStore: Production, shared across router replicas (store_mongo.go)
+//   - RedisStore: Production, for operators already running Redis (store_redis_vector.go)
+//   - WeaviateStore: Production, for operators running Weaviate (store_weaviate.go)
 type Store interface {
 	// Store saves a new memory with its embedding
 	Store(ctx context.Context, memory *Memory) error
@@ -36,6 +48,19 @@ type Store interface {
 	// ForgetByScope removes all memories matching the scope
 	ForgetByScope(ctx context.Context, scope MemoryScope) error
 
+	// UpdateScore overwrites a memory's Importance with score, without
+	// touching its content, access tracking, or any other field. Used by
+	// MemoryConsolidator to persist decay/reinforcement scoring between
+	// retrievals, where a full Update would be both a larger write and a
+	// race against any concurrent content edit.
+	UpdateScore(ctx context.Context, id string, score float64) error
+
+	// BulkDelete removes every memory in ids in one call, skipping IDs that
+	// don't exist rather than failing the whole batch over them. Used by
+	// MemoryConsolidator to evict memories in one round-trip instead of one
+	// Forget call per eviction.
+	BulkDelete(ctx context.Context, ids []string) error
+
 	// IsEnabled returns whether the store is active
 	IsEnabled() bool
 
@@ -50,3 +75,271 @@ type MemoryUpdate struct {
 	Metadata   map[string]any
 	Importance *float64
 }
+
+// StoreIterator is an optional capability a Store backend may implement to
+// support full-table scans, e.g. for Migrate. It is kept separate from Store
+// so that backends without a cheap way to enumerate every record (MilvusStore)
+// are not forced to implement it.
+type StoreIterator interface {
+	// Iterate calls fn once per stored memory. Iteration stops and returns
+	// fn's error as soon as fn returns a non-nil error.
+	Iterate(ctx context.Context, fn func(*Memory) error) error
+}
+
+// StoreSync is an optional capability a Store backend may implement to let a
+// caller enumerate changes instead of the whole table, for an external
+// agent, mobile client, or peer router pulling only what changed since its
+// last sync cursor. It is kept separate from Store, the same way
+// StoreIterator is, so a backend without time-ordered enumeration is not
+// forced to implement it.
+type StoreSync interface {
+	// UpdatedSince returns every memory in scope whose UpdatedAt is strictly
+	// after ts, most-recently-updated first. A caller should persist the
+	// UpdatedAt of the last memory it processed as its next cursor.
+	UpdatedSince(ctx context.Context, scope MemoryScope, ts time.Time) ([]*Memory, error)
+
+	// Tombstones returns the IDs forgotten (via Forget or ForgetByScope)
+	// within scope since ts, so a sync client can delete its local copies of
+	// memories it already pulled. The tombstone log is bounded; a client
+	// whose cursor falls outside the retained window must fall back to a
+	// full resync.
+	Tombstones(ctx context.Context, scope MemoryScope, ts time.Time) ([]string, error)
+}
+
+// MemoryOpKind identifies what a MemoryOp does within an Apply batch.
+type MemoryOpKind string
+
+const (
+	MemoryOpPut    MemoryOpKind = "put"    // insert Memory, deduplicating first if Dedup is set
+	MemoryOpUpdate MemoryOpKind = "update" // overwrite the memory at ID with Memory
+	MemoryOpForget MemoryOpKind = "forget" // remove the memory at ID
+)
+
+// MemoryOp is one operation within a StoreApply.Apply batch.
+type MemoryOp struct {
+	Kind   MemoryOpKind
+	Memory *Memory // required for Put/Update
+	ID     string  // required for Update/Forget
+
+	// Dedup, for a Put op, runs CheckDeduplication against Memory.UserID/
+	// Content/Type before inserting: a sufficiently similar existing memory
+	// is updated in place instead of creating a near-duplicate. Ignored for
+	// Update/Forget ops.
+	Dedup bool
+
+	// DedupConfig overrides DefaultDeduplicationConfig for this op's Dedup
+	// check. Ignored unless Dedup is true.
+	DedupConfig *DeduplicationConfig
+}
+
+// ApplyResult summarizes the outcome of one StoreApply.Apply batch.
+type ApplyResult struct {
+	Created      []string // IDs of memories created by a Put op
+	Updated      []string // IDs of memories overwritten by an Update op, or folded into by a deduplicated Put
+	Forgotten    []string // IDs removed by a Forget op
+	Deduplicated int      // Put ops that matched an existing memory and became an update instead of a create
+}
+
+// StoreApply is an optional capability a Store backend may implement to
+// apply several Put/Update/Forget operations as one batch instead of one
+// round-trip per op - e.g. ingesting a page of conversation turns that each
+// extract a fact, some of which should dedupe against each other. It is kept
+// separate from Store, the same way StoreIterator and StoreSync are, so a
+// backend without a cheap way to batch writes is not forced to implement it.
+//
+// Apply either applies every op or, for backends that can make that
+// guarantee, none of them; see each implementation's doc comment for its
+// actual atomicity (InMemoryStore commits under a single mutex and rolls
+// back in full on error; MilvusStore has no multi-statement transaction and
+// is best-effort - a failure partway through can leave earlier ops applied).
+type StoreApply interface {
+	Apply(ctx context.Context, ops []MemoryOp) (ApplyResult, error)
+}
+
+// StoreExpirer is an optional capability a Store backend may implement for
+// TTL-bound memories - MemoryTypeWorking session scratchpads, tool-call
+// state - that should be evicted once their TTL passes instead of living
+// forever like semantic/episodic memories. It is kept separate from Store,
+// the same way StoreIterator/StoreSync/StoreApply are, so a backend with no
+// cheap way to scan for expired rows is not forced to implement it.
+//
+// Retrieve and Get already exclude expired memories on every backend (see
+// isExpired), so ExpireDue is purely about reclaiming storage on a
+// schedule; a caller never observes an expired memory just because the
+// reaper hasn't run yet. See ExpiryReaper for a Start/Stop-on-a-ticker
+// driver, the TTL-eviction counterpart to MemoryConsolidator.
+type StoreExpirer interface {
+	// ExpireDue deletes every memory whose TTL is non-zero and has passed,
+	// returning the number removed.
+	ExpireDue(ctx context.Context) (int, error)
+}
+
+// StoreHealthChecker is an optional capability a Store backend may implement
+// to report liveness beyond "did the constructor succeed" - e.g. MilvusStore
+// re-checking its gRPC connection. It is kept separate from Store, the same
+// way StoreIterator/StoreSync/StoreApply/StoreExpirer are, so a backend with
+// no meaningful health signal of its own (InMemoryStore) is not forced to
+// implement it; callers that want to gate on health fall back to treating a
+// Store without this capability as always healthy.
+type StoreHealthChecker interface {
+	// HealthCheck returns nil if the backend is reachable and serving,
+	// otherwise an error describing why. It is meant to be polled on a
+	// schedule (see extproc's background health re-check), not called on
+	// every request.
+	HealthCheck(ctx context.Context) error
+}
+
+// ConsolidateOptions scopes and tunes one StoreConsolidator.Consolidate
+// pass.
+type ConsolidateOptions struct {
+	UserID    string // Required
+	ProjectID string // Optional: further scope to a project
+
+	// SimilarityThreshold is the minimum cosine similarity (over Embedder's
+	// output) above which two memories are treated as the same underlying
+	// fact and merged into one cluster. <= 0 uses defaultConsolidateThreshold.
+	SimilarityThreshold float64
+
+	// Embedder produces embeddings for memories that don't already have one
+	// cached (Memory.Embedding). Required.
+	Embedder Embedder
+
+	// Summarizer condenses a cluster's contents into the merged memory's
+	// content. Nil defaults to joinSummarizer, which just joins them.
+	Summarizer Summarizer
+}
+
+// ConsolidateResult summarizes one Consolidate pass.
+type ConsolidateResult struct {
+	ClustersMerged  int      // clusters of 2+ similar memories folded into one
+	MemoriesRemoved int      // original memories deleted across all clusters
+	Created         []string // IDs of the new merged memories, one per cluster
+}
+
+// StoreConsolidator is an optional capability a Store backend may implement
+// to fold near-duplicate memories together: Consolidate clusters memories
+// scoped to (UserID, ProjectID) whose embeddings are similar enough to be
+// the same underlying fact, and replaces each cluster with a single
+// semantic memory. It is kept separate from Store, the same way
+// StoreIterator/StoreSync/StoreApply/StoreExpirer/StoreHealthChecker are,
+// so a backend with no cheap way to cluster over embeddings (MilvusStore's
+// clustering, if any, belongs in Milvus itself, not duplicated here) is not
+// forced to implement it.
+type StoreConsolidator interface {
+	Consolidate(ctx context.Context, opts ConsolidateOptions) (ConsolidateResult, error)
+}
+
+// Migrate copies every memory from src to dst, preserving each memory's
+// UserID/ProjectID/Type scoping as stored. It returns the number of memories
+// successfully copied. src must implement StoreIterator; dst only needs Store.
+func Migrate(ctx context.Context, src, dst Store) (int, error) {
+	iterable, ok := src.(StoreIterator)
+	if !ok {
+		return 0, fmt.Errorf("memory: migrate source %T does not support iteration", src)
+	}
+
+	copied := 0
+	err := iterable.Iterate(ctx, func(mem *Memory) error {
+		clone := *mem
+		if err := dst.Store(ctx, &clone); err != nil {
+			return fmt.Errorf("memory: migrate failed copying memory %s: %w", mem.ID, err)
+		}
+		copied++
+		return nil
+	})
+	if err != nil {
+		return copied, err
+	}
+	return copied, nil
+}
+
+// keywordRelevanceScore scores content against a query using the same
+// lowercase keyword-overlap heuristic InMemoryStore uses, so
+// persistent-backend Retrieve implementations stay consistent with it until
+// real embedding-based similarity search is wired in.
+func keywordRelevanceScore(content, query string) float64 {
+	queryWords := strings.Fields(strings.ToLower(query))
+	if len(queryWords) == 0 {
+		return 0
+	}
+	lowerContent := strings.ToLower(content)
+	matchCount := 0
+	for _, word := range queryWords {
+		if strings.Contains(lowerContent, word) {
+			matchCount++
+		}
+	}
+	return float64(matchCount) / float64(len(queryWords))
+}
+
+// cosineSimilarity computes cosine similarity between two equal-length
+// vectors, returning 0 if either is empty or they differ in length. Shared
+// by MilvusStore's MMR re-ranking and MemoryConsolidator's reinforcement
+// merge check, so it lives here rather than behind the cgo build tag.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(b) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// recencyHalfLife is the age at which RetrieveOptions.RecencyWeight's decay
+// term has halved - the same two-week half-life ConsolidationConfig defaults
+// to, so a time-anchored query and MemoryConsolidator's eviction agree on
+// what "recent" means.
+const recencyHalfLife = 14 * 24 * time.Hour
+
+// recencyLambda is the exponential-decay rate implied by recencyHalfLife:
+// exp(-recencyLambda*age) == 0.5 at age == recencyHalfLife.
+var recencyLambda = math.Ln2 / recencyHalfLife.Seconds()
+
+// blendRecency combines a raw similarity score with an exponential decay of
+// createdAt's age relative to anchor, weighted by weight (0 = pure
+// similarity, 1 = pure recency). Shared by InMemoryStore.Retrieve and
+// MilvusStore.Retrieve so RetrieveOptions.RecencyWeight ranks the same way
+// regardless of backend.
+func blendRecency(sim float64, createdAt, anchor time.Time, weight float64) float64 {
+	if weight <= 0 {
+		return sim
+	}
+	age := anchor.Sub(createdAt).Seconds()
+	if age < 0 {
+		age = 0
+	}
+	decay := math.Exp(-recencyLambda * age)
+	return (1-weight)*sim + weight*decay
+}
+
+// inTimeWindow reports whether t falls within [window[0], window[1]]
+// inclusive, or true if window is nil (unbounded).
+func inTimeWindow(t time.Time, window *[2]time.Time) bool {
+	if window == nil {
+		return true
+	}
+	return !t.Before(window[0]) && !t.After(window[1])
+}
+
+// isExpired reports whether mem's TTL is set and has passed as of now. A
+// zero TTL means the memory never expires. Shared by every backend's
+// Retrieve/Get (so a not-yet-reaped row never leaks out as a live result)
+// and by StoreExpirer.ExpireDue implementations.
+func isExpired(mem *Memory, now time.Time) bool {
+	return !mem.TTL.IsZero() && !mem.TTL.After(now)
+}
+
+// excludeSuperseded reports whether Retrieve should skip mem because a newer
+// memory has superseded it (see Memory.SupersededBy, StoreFromResponse),
+// unless opts.IncludeAllBranches asks to see the full branch history anyway.
+func excludeSuperseded(mem *Memory, opts RetrieveOptions) bool {
+	return mem.SupersededBy != "" && !opts.IncludeAllBranches
+}
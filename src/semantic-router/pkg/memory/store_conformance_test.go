@@ -0,0 +1,283 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// storeFactories under test. Each entry builds a fresh, empty Store; the
+// returned cleanup func releases any resources it holds.
+func conformanceBackends(t *testing.T) map[string]func() (Store, func()) {
+	backends := map[string]func() (Store, func()){
+		"InMemoryStore": func() (Store, func()) {
+			return NewInMemoryStore(), func() {}
+		},
+		"SQLiteStore": func() (Store, func()) {
+			path := filepath.Join(t.TempDir(), "conformance.db")
+			store, err := NewSQLiteStore(SQLiteStoreConfig{Path: path})
+			if err != nil {
+				t.Fatalf("NewSQLiteStore: %v", err)
+			}
+			return store, func() { store.Close() }
+		},
+	}
+
+	if dsn := os.Getenv("TEST_POSTGRES_DSN"); dsn != "" {
+		backends["PostgresStore"] = func() (Store, func()) {
+			store, err := NewPostgresStore(context.Background(), PostgresStoreConfig{DSN: dsn})
+			if err != nil {
+				t.Fatalf("NewPostgresStore: %v", err)
+			}
+			return store, func() { store.Close() }
+		}
+	}
+
+	if uri := os.Getenv("TEST_MONGO_URI"); uri != "" {
+		backends["MongoStore"] = func() (Store, func()) {
+			ctx := context.Background()
+			store, err := NewMongoStore(ctx, MongoStoreConfig{URI: uri, Database: "semantic_router_test", Collection: "memories_conformance"})
+			if err != nil {
+				t.Fatalf("NewMongoStore: %v", err)
+			}
+			return store, func() {
+				store.collection.Drop(ctx)
+				store.Close()
+			}
+		}
+	}
+
+	if addr := os.Getenv("TEST_REDIS_ADDR"); addr != "" {
+		backends["RedisStore"] = func() (Store, func()) {
+			store, err := NewRedisStore(RedisStoreConfig{Addr: addr})
+			if err != nil {
+				t.Fatalf("NewRedisStore: %v", err)
+			}
+			return store, func() { store.Close() }
+		}
+	}
+
+	if url := os.Getenv("TEST_WEAVIATE_URL"); url != "" {
+		backends["WeaviateStore"] = func() (Store, func()) {
+			store, err := NewWeaviateStore(context.Background(), WeaviateStoreConfig{BaseURL: url, ClassName: "SemanticRouterMemoryConformance"})
+			if err != nil {
+				t.Fatalf("NewWeaviateStore: %v", err)
+			}
+			return store, func() { store.Close() }
+		}
+	}
+
+	return backends
+}
+
+// TestStoreConformance runs the same behavioral contract against every
+// production Store backend, so a backend added later only needs an entry in
+// conformanceBackends to be covered here.
+func TestStoreConformance(t *testing.T) {
+	for name, newBackend := range conformanceBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			store, cleanup := newBackend()
+			defer cleanup()
+			ctx := context.Background()
+
+			if err := store.Store(ctx, &Memory{Type: MemoryTypeSemantic, Content: "", UserID: ""}); err != ErrInvalidUserID {
+				t.Fatalf("Store with empty UserID: got %v, want ErrInvalidUserID", err)
+			}
+
+			mem := &Memory{Type: MemoryTypeSemantic, Content: "User prefers vegetarian food", UserID: "user_1"}
+			if err := store.Store(ctx, mem); err != nil {
+				t.Fatalf("Store: %v", err)
+			}
+			if mem.ID == "" {
+				t.Fatal("Store did not assign an ID")
+			}
+
+			got, err := store.Get(ctx, mem.ID)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if got.Content != mem.Content {
+				t.Fatalf("Get content = %q, want %q", got.Content, mem.Content)
+			}
+
+			if _, err := store.Get(ctx, "mem_does_not_exist"); err != ErrNotFound {
+				t.Fatalf("Get missing ID: got %v, want ErrNotFound", err)
+			}
+
+			results, err := store.Retrieve(ctx, RetrieveOptions{UserID: "user_1", Query: "vegetarian"})
+			if err != nil {
+				t.Fatalf("Retrieve: %v", err)
+			}
+			if len(results) != 1 || results[0].Memory.ID != mem.ID {
+				t.Fatalf("Retrieve = %+v, want a single match on %s", results, mem.ID)
+			}
+
+			if err := store.Update(ctx, mem.ID, &Memory{Type: MemoryTypeSemantic, Content: "User prefers vegan food", UserID: "user_1"}); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			got, err = store.Get(ctx, mem.ID)
+			if err != nil {
+				t.Fatalf("Get after Update: %v", err)
+			}
+			if got.Content != "User prefers vegan food" {
+				t.Fatalf("Get after Update content = %q, want updated content", got.Content)
+			}
+
+			if err := store.Forget(ctx, mem.ID); err != nil {
+				t.Fatalf("Forget: %v", err)
+			}
+			if _, err := store.Get(ctx, mem.ID); err != ErrNotFound {
+				t.Fatalf("Get after Forget: got %v, want ErrNotFound", err)
+			}
+			if err := store.Forget(ctx, mem.ID); err != ErrNotFound {
+				t.Fatalf("Forget already-forgotten ID: got %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+// TestStoreConformance_ForgetByScope exercises scoped bulk deletion, which
+// several callers (retention policies, tenant offboarding) rely on across
+// every backend.
+func TestStoreConformance_ForgetByScope(t *testing.T) {
+	for name, newBackend := range conformanceBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			store, cleanup := newBackend()
+			defer cleanup()
+			ctx := context.Background()
+
+			for _, mem := range []*Memory{
+				{Type: MemoryTypeSemantic, Content: "a", UserID: "user_1", ProjectID: "proj_a"},
+				{Type: MemoryTypeEpisodic, Content: "b", UserID: "user_1", ProjectID: "proj_b"},
+				{Type: MemoryTypeSemantic, Content: "c", UserID: "user_2", ProjectID: "proj_a"},
+			} {
+				if err := store.Store(ctx, mem); err != nil {
+					t.Fatalf("Store: %v", err)
+				}
+			}
+
+			if err := store.ForgetByScope(ctx, MemoryScope{UserID: "user_1", ProjectID: "proj_a"}); err != nil {
+				t.Fatalf("ForgetByScope: %v", err)
+			}
+
+			remaining, err := store.Retrieve(ctx, RetrieveOptions{UserID: "user_1", Query: "a b"})
+			if err != nil {
+				t.Fatalf("Retrieve: %v", err)
+			}
+			for _, r := range remaining {
+				if r.Memory.ProjectID == "proj_a" {
+					t.Fatalf("ForgetByScope left a memory it should have removed: %+v", r.Memory)
+				}
+			}
+		})
+	}
+}
+
+// TestStoreConformance_Apply exercises StoreApply's batch Put/Update/Forget
+// semantics, skipping any backend that doesn't implement the optional
+// interface.
+func TestStoreConformance_Apply(t *testing.T) {
+	for name, newBackend := range conformanceBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			store, cleanup := newBackend()
+			defer cleanup()
+
+			applier, ok := store.(StoreApply)
+			if !ok {
+				t.Skipf("%s does not implement StoreApply", name)
+			}
+			ctx := context.Background()
+
+			existing := &Memory{Type: MemoryTypeSemantic, Content: "User prefers window seats", UserID: "user_1"}
+			if err := store.Store(ctx, existing); err != nil {
+				t.Fatalf("seed Store: %v", err)
+			}
+
+			result, err := applier.Apply(ctx, []MemoryOp{
+				{Kind: MemoryOpPut, Memory: &Memory{Type: MemoryTypeSemantic, Content: "User prefers aisle seats on long flights", UserID: "user_1"}},
+				{Kind: MemoryOpUpdate, ID: existing.ID, Memory: &Memory{Type: MemoryTypeSemantic, Content: "User prefers aisle seats", UserID: "user_1"}},
+			})
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if len(result.Created) != 1 {
+				t.Fatalf("Created = %v, want 1 entry", result.Created)
+			}
+			if len(result.Updated) != 1 || result.Updated[0] != existing.ID {
+				t.Fatalf("Updated = %v, want [%s]", result.Updated, existing.ID)
+			}
+
+			got, err := store.Get(ctx, existing.ID)
+			if err != nil {
+				t.Fatalf("Get after Apply: %v", err)
+			}
+			if got.Content != "User prefers aisle seats" {
+				t.Fatalf("Get content = %q, want the Apply update's content", got.Content)
+			}
+
+			if _, err := applier.Apply(ctx, []MemoryOp{{Kind: MemoryOpForget, ID: result.Created[0]}}); err != nil {
+				t.Fatalf("Apply forget: %v", err)
+			}
+			if _, err := store.Get(ctx, result.Created[0]); err != ErrNotFound {
+				t.Fatalf("Get after Apply forget: got %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+// TestMigrate_Conformance moves a fixed set of memories from InMemoryStore
+// into every backend that supports StoreIterator and checks that
+// UserID/ProjectID/Type partitioning survives the hop.
+func TestMigrate_Conformance(t *testing.T) {
+	seed := []*Memory{
+		{Type: MemoryTypeSemantic, Content: "User's budget is $50,000", UserID: "user_1", ProjectID: "proj_a"},
+		{Type: MemoryTypeEpisodic, Content: "Discussed vacation plans", UserID: "user_1", ProjectID: ""},
+		{Type: MemoryTypeProcedural, Content: "Deploy with npm build", UserID: "user_2", ProjectID: "proj_b"},
+	}
+
+	for name, newBackend := range conformanceBackends(t) {
+		if name == "InMemoryStore" {
+			continue // migration source, not a migration target worth testing against itself
+		}
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			src := NewInMemoryStore()
+			for _, mem := range seed {
+				clone := *mem
+				if err := src.Store(ctx, &clone); err != nil {
+					t.Fatalf("seed Store: %v", err)
+				}
+			}
+
+			dst, cleanup := newBackend()
+			defer cleanup()
+
+			copied, err := Migrate(ctx, src, dst)
+			if err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+			if copied != len(seed) {
+				t.Fatalf("Migrate copied = %d, want %d", copied, len(seed))
+			}
+
+			for _, mem := range seed {
+				results, err := dst.Retrieve(ctx, RetrieveOptions{UserID: mem.UserID, Query: mem.Content})
+				if err != nil {
+					t.Fatalf("Retrieve after Migrate: %v", err)
+				}
+				found := false
+				for _, r := range results {
+					if r.Memory.Content == mem.Content && r.Memory.ProjectID == mem.ProjectID && r.Memory.Type == mem.Type {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Fatalf("Migrate lost scoping for memory %q (user=%s project=%s type=%s)", mem.Content, mem.UserID, mem.ProjectID, mem.Type)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/google/uuid"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// MongoStoreConfig configures MongoStore.
+type MongoStoreConfig struct {
+	// URI is the MongoDB connection string.
+	URI string
+
+	// Database names the database holding the memories collection.
+	Database string
+
+	// Collection names the collection memories are stored in. Defaults to
+	// "memories".
+	Collection string
+}
+
+// DefaultMongoStoreConfig returns a config with the default collection name;
+// URI and Database must be set by the caller before NewMongoStore is called.
+func DefaultMongoStoreConfig() MongoStoreConfig {
+	return MongoStoreConfig{Collection: "memories"}
+}
+
+// mongoMemoryDocument is the on-disk shape of a stored memory.
+type mongoMemoryDocument struct {
+	ID          string         `bson:"_id"`
+	Type        string         `bson:"type"`
+	Content     string         `bson:"content"`
+	UserID      string         `bson:"user_id"`
+	ProjectID   string         `bson:"project_id"`
+	Metadata    map[string]any `bson:"metadata,omitempty"`
+	Source      string         `bson:"source"`
+	Confidence  float64        `bson:"confidence"`
+	Importance  float64        `bson:"importance"`
+	AccessCount int            `bson:"access_count"`
+	CreatedAt   time.Time      `bson:"created_at"`
+	AccessedAt  time.Time      `bson:"accessed_at"`
+}
+
+func (d *mongoMemoryDocument) toMemory() *Memory {
+	return &Memory{
+		ID:          d.ID,
+		Type:        MemoryType(d.Type),
+		Content:     d.Content,
+		UserID:      d.UserID,
+		ProjectID:   d.ProjectID,
+		Metadata:    d.Metadata,
+		Source:      d.Source,
+		Confidence:  d.Confidence,
+		Importance:  d.Importance,
+		AccessCount: d.AccessCount,
+		CreatedAt:   d.CreatedAt,
+		AccessedAt:  d.AccessedAt,
+	}
+}
+
+func mongoDocumentFromMemory(mem *Memory) mongoMemoryDocument {
+	return mongoMemoryDocument{
+		ID:          mem.ID,
+		Type:        string(mem.Type),
+		Content:     mem.Content,
+		UserID:      mem.UserID,
+		ProjectID:   mem.ProjectID,
+		Metadata:    mem.Metadata,
+		Source:      mem.Source,
+		Confidence:  mem.Confidence,
+		Importance:  mem.Importance,
+		AccessCount: mem.AccessCount,
+		CreatedAt:   mem.CreatedAt,
+		AccessedAt:  mem.AccessedAt,
+	}
+}
+
+// MongoStore is a MongoDB-backed Store implementation shared across router
+// replicas, built on the same mongo-driver conventions as MongoMemoryStore
+// (message_store_mongo.go). Like SQLiteStore and PostgresStore, it scores
+// candidates with keywordRelevanceScore rather than a vector index, since
+// RetrieveOptions carries no query embedding yet; Mongo's native vector
+// search (Atlas Search $vectorSearch) can slot in here once that's wired up.
+type MongoStore struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+}
+
+// NewMongoStore connects to MongoDB per config, ensures the collection's
+// query indexes exist, and returns a MongoStore.
+func NewMongoStore(ctx context.Context, config MongoStoreConfig) (*MongoStore, error) {
+	if config.URI == "" {
+		return nil, fmt.Errorf("mongo store requires a uri")
+	}
+	if config.Database == "" {
+		return nil, fmt.Errorf("mongo store requires a database name")
+	}
+	if config.Collection == "" {
+		config.Collection = "memories"
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(connectCtx, options.Client().ApplyURI(config.URI))
+	if err != nil {
+		return nil, fmt.Errorf("mongo store: connect: %w", err)
+	}
+
+	collection := client.Database(config.Database).Collection(config.Collection)
+	if _, err := collection.Indexes().CreateOne(connectCtx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "project_id", Value: 1}},
+	}); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("mongo store: create index: %w", err)
+	}
+
+	logging.Infof("MemoryStore: using MongoDB backend (database=%s, collection=%s)", config.Database, config.Collection)
+	return &MongoStore{client: client, collection: collection}, nil
+}
+
+// Store saves a new memory, upserting by ID.
+func (s *MongoStore) Store(ctx context.Context, mem *Memory) error {
+	if mem.UserID == "" {
+		return ErrInvalidUserID
+	}
+	if mem.ID == "" {
+		mem.ID = "mem_" + uuid.New().String()[:8]
+	}
+	now := time.Now()
+	if mem.CreatedAt.IsZero() {
+		mem.CreatedAt = now
+	}
+	mem.AccessedAt = now
+
+	doc := mongoDocumentFromMemory(mem)
+	_, err := s.collection.ReplaceOne(ctx, bson.M{"_id": mem.ID}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("mongo store: upsert memory %s: %w", mem.ID, err)
+	}
+	return nil
+}
+
+// Retrieve finds memories matching the query and options, scored with
+// keywordRelevanceScore over every row scoped to the user/project/types.
+func (s *MongoStore) Retrieve(ctx context.Context, opts RetrieveOptions) ([]*RetrieveResult, error) {
+	if opts.UserID == "" {
+		return nil, ErrInvalidUserID
+	}
+
+	filter := bson.M{"user_id": opts.UserID}
+	if opts.ProjectID != "" {
+		filter["project_id"] = opts.ProjectID
+	}
+	if len(opts.Types) > 0 {
+		types := make([]string, len(opts.Types))
+		for i, t := range opts.Types {
+			types[i] = string(t)
+		}
+		filter["type"] = bson.M{"$in": types}
+	}
+
+	cursor, err := s.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("mongo store: find memories: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []*RetrieveResult
+	for cursor.Next(ctx) {
+		var doc mongoMemoryDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("mongo store: decode memory: %w", err)
+		}
+		mem := doc.toMemory()
+		relevance := keywordRelevanceScore(mem.Content, opts.Query)
+		if relevance <= 0 {
+			continue
+		}
+		results = append(results, &RetrieveResult{Memory: mem, Relevance: relevance})
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("mongo store: iterate memories: %w", err)
+	}
+
+	for i := 0; i < len(results); i++ {
+		for j := i + 1; j < len(results); j++ {
+			if results[j].Relevance > results[i].Relevance {
+				results[i], results[j] = results[j], results[i]
+			}
+		}
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	for _, r := range results {
+		_, err := s.collection.UpdateOne(ctx,
+			bson.M{"_id": r.Memory.ID},
+			bson.M{"$inc": bson.M{"access_count": 1}, "$set": bson.M{"accessed_at": time.Now()}},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("mongo store: update access tracking for %s: %w", r.Memory.ID, err)
+		}
+	}
+
+	return results, nil
+}
+
+// Get retrieves a specific memory by ID.
+func (s *MongoStore) Get(ctx context.Context, id string) (*Memory, error) {
+	var doc mongoMemoryDocument
+	err := s.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mongo store: get memory %s: %w", id, err)
+	}
+	return doc.toMemory(), nil
+}
+
+// Update modifies an existing memory.
+func (s *MongoStore) Update(ctx context.Context, id string, mem *Memory) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	mem.ID = id
+	return s.Store(ctx, mem)
+}
+
+// Forget removes a memory by ID.
+func (s *MongoStore) Forget(ctx context.Context, id string) error {
+	res, err := s.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("mongo store: forget %s: %w", id, err)
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ForgetByScope removes all memories matching the scope.
+func (s *MongoStore) ForgetByScope(ctx context.Context, scope MemoryScope) error {
+	filter := bson.M{}
+	if scope.UserID != "" {
+		filter["user_id"] = scope.UserID
+	}
+	if scope.ProjectID != "" {
+		filter["project_id"] = scope.ProjectID
+	}
+	if scope.Type != "" {
+		filter["type"] = string(scope.Type)
+	}
+	if scope.Before != nil || scope.After != nil {
+		createdAt := bson.M{}
+		if scope.Before != nil {
+			createdAt["$lt"] = *scope.Before
+		}
+		if scope.After != nil {
+			createdAt["$gt"] = *scope.After
+		}
+		filter["created_at"] = createdAt
+	}
+	if _, err := s.collection.DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("mongo store: forget by scope: %w", err)
+	}
+	return nil
+}
+
+// UpdateScore overwrites a memory's Importance with score.
+func (s *MongoStore) UpdateScore(ctx context.Context, id string, score float64) error {
+	res, err := s.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"importance": score}})
+	if err != nil {
+		return fmt.Errorf("mongo store: update score for %s: %w", id, err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// BulkDelete removes every memory in ids in one call.
+func (s *MongoStore) BulkDelete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := s.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return fmt.Errorf("mongo store: bulk delete: %w", err)
+	}
+	return nil
+}
+
+// IsEnabled returns whether the underlying MongoDB connection is reachable.
+func (s *MongoStore) IsEnabled() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return s.client.Ping(ctx, nil) == nil
+}
+
+// Close disconnects from MongoDB.
+func (s *MongoStore) Close() error {
+	return s.client.Disconnect(context.Background())
+}
+
+// Iterate implements StoreIterator for use by Migrate.
+func (s *MongoStore) Iterate(ctx context.Context, fn func(*Memory) error) error {
+	cursor, err := s.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("mongo store: iterate: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc mongoMemoryDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return fmt.Errorf("mongo store: decode memory: %w", err)
+		}
+		if err := fn(doc.toMemory()); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
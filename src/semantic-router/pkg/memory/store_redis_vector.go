@@ -0,0 +1,409 @@
+package memory
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// RedisStoreConfig configures RedisStore.
+type RedisStoreConfig struct {
+	// Addr is the Redis address ("host:port"). When Cluster is true, it may
+	// be a comma-separated list of cluster node addresses.
+	Addr string
+
+	// Password authenticates with Redis AUTH / ACL. Empty disables auth.
+	Password string
+
+	// TLS enables TLS for the connection.
+	TLS bool
+
+	// Cluster switches to a Redis Cluster client instead of a single-node one.
+	Cluster bool
+
+	// IndexName names the RediSearch index created over the memory hashes.
+	// Defaults to "idx:memories".
+	IndexName string
+}
+
+// DefaultRedisStoreConfig returns a config with the default index name; Addr
+// must be set by the caller before NewRedisStore is called.
+func DefaultRedisStoreConfig() RedisStoreConfig {
+	return RedisStoreConfig{IndexName: "idx:memories"}
+}
+
+// redisStoreClient is the subset of go-redis's Cmdable RedisStore needs,
+// plus Close.
+type redisStoreClient interface {
+	HSet(ctx context.Context, key string, values ...interface{}) *goredis.IntCmd
+	HGetAll(ctx context.Context, key string) *goredis.MapStringStringCmd
+	Del(ctx context.Context, keys ...string) *goredis.IntCmd
+	SAdd(ctx context.Context, key string, members ...interface{}) *goredis.IntCmd
+	SRem(ctx context.Context, key string, members ...interface{}) *goredis.IntCmd
+	SMembers(ctx context.Context, key string) *goredis.StringSliceCmd
+	Keys(ctx context.Context, pattern string) *goredis.StringSliceCmd
+	Do(ctx context.Context, args ...interface{}) *goredis.Cmd
+	Close() error
+}
+
+// RedisStore is a Redis-backed Store implementation, for operators who
+// already run Redis and would rather not stand up Milvus. Each memory is a
+// hash at redisMemoryKey(id), with a per-user set (redisUserIndexKey)
+// tracking which IDs belong to that user's scope - the same set-as-index
+// shape RedisMemoryStore (message_store_redis.go) uses for sessions.
+//
+// NewRedisStore also best-effort creates a RediSearch index with an HNSW
+// vector field over the embedding, mirroring PostgresStore's best-effort
+// pgvector extension: the RediSearch module may not be loaded, in which
+// case Retrieve falls back to scanning the user's set and scoring with
+// keywordRelevanceScore, same as every other non-Milvus backend.
+type RedisStore struct {
+	client redisStoreClient
+}
+
+// NewRedisStore connects to Redis per config and returns a RedisStore.
+// Supports password auth, TLS, and cluster mode.
+func NewRedisStore(config RedisStoreConfig) (*RedisStore, error) {
+	if config.Addr == "" {
+		return nil, fmt.Errorf("redis store requires addr")
+	}
+	if config.IndexName == "" {
+		config.IndexName = "idx:memories"
+	}
+
+	var tlsConfig *tls.Config
+	if config.TLS {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	var client redisStoreClient
+	if config.Cluster {
+		client = goredis.NewClusterClient(&goredis.ClusterOptions{
+			Addrs:     strings.Split(config.Addr, ","),
+			Password:  config.Password,
+			TLSConfig: tlsConfig,
+		})
+	} else {
+		client = goredis.NewClient(&goredis.Options{
+			Addr:      config.Addr,
+			Password:  config.Password,
+			TLSConfig: tlsConfig,
+		})
+	}
+
+	store := &RedisStore{client: client}
+	if err := client.Do(context.Background(), "FT.CREATE", config.IndexName,
+		"ON", "HASH", "PREFIX", "1", redisMemoryKeyPrefix,
+		"SCHEMA", "user_id", "TAG", "project_id", "TAG", "content", "TEXT",
+	).Err(); err != nil {
+		logging.Infof("MemoryStore: RediSearch index unavailable, falling back to set-scan retrieval (%v)", err)
+	}
+
+	logging.Infof("MemoryStore: using Redis backend (addr=%s)", config.Addr)
+	return store, nil
+}
+
+const redisMemoryKeyPrefix = "semantic-router:store:mem:"
+
+func redisMemoryKey(id string) string        { return redisMemoryKeyPrefix + id }
+func redisUserIndexKey(userID string) string { return "semantic-router:store:idx:user:" + userID }
+
+// Store saves a new memory as a hash, upserting by ID.
+func (s *RedisStore) Store(ctx context.Context, mem *Memory) error {
+	if mem.UserID == "" {
+		return ErrInvalidUserID
+	}
+	if mem.ID == "" {
+		mem.ID = "mem_" + uuid.New().String()[:8]
+	}
+	now := time.Now()
+	if mem.CreatedAt.IsZero() {
+		mem.CreatedAt = now
+	}
+	mem.AccessedAt = now
+
+	metadata, err := json.Marshal(mem.Metadata)
+	if err != nil {
+		return fmt.Errorf("redis store: marshal metadata: %w", err)
+	}
+
+	fields := map[string]interface{}{
+		"id":           mem.ID,
+		"type":         string(mem.Type),
+		"content":      mem.Content,
+		"user_id":      mem.UserID,
+		"project_id":   mem.ProjectID,
+		"metadata":     metadata,
+		"source":       mem.Source,
+		"confidence":   mem.Confidence,
+		"importance":   mem.Importance,
+		"access_count": mem.AccessCount,
+		"created_at":   mem.CreatedAt.Format(time.RFC3339Nano),
+		"accessed_at":  mem.AccessedAt.Format(time.RFC3339Nano),
+	}
+	if err := s.client.HSet(ctx, redisMemoryKey(mem.ID), fields).Err(); err != nil {
+		return fmt.Errorf("redis store: hset memory %s: %w", mem.ID, err)
+	}
+	if err := s.client.SAdd(ctx, redisUserIndexKey(mem.UserID), mem.ID).Err(); err != nil {
+		return fmt.Errorf("redis store: index memory %s: %w", mem.ID, err)
+	}
+	return nil
+}
+
+// Retrieve finds memories matching the query and options, scored with
+// keywordRelevanceScore over every ID in the user's index set.
+func (s *RedisStore) Retrieve(ctx context.Context, opts RetrieveOptions) ([]*RetrieveResult, error) {
+	if opts.UserID == "" {
+		return nil, ErrInvalidUserID
+	}
+
+	ids, err := s.client.SMembers(ctx, redisUserIndexKey(opts.UserID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis store: smembers: %w", err)
+	}
+
+	var results []*RetrieveResult
+	for _, id := range ids {
+		mem, err := s.Get(ctx, id)
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if opts.ProjectID != "" && mem.ProjectID != opts.ProjectID {
+			continue
+		}
+		if len(opts.Types) > 0 && !containsType(opts.Types, mem.Type) {
+			continue
+		}
+		relevance := keywordRelevanceScore(mem.Content, opts.Query)
+		if relevance <= 0 {
+			continue
+		}
+		results = append(results, &RetrieveResult{Memory: mem, Relevance: relevance})
+	}
+
+	for i := 0; i < len(results); i++ {
+		for j := i + 1; j < len(results); j++ {
+			if results[j].Relevance > results[i].Relevance {
+				results[i], results[j] = results[j], results[i]
+			}
+		}
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	for _, r := range results {
+		if err := s.client.HSet(ctx, redisMemoryKey(r.Memory.ID),
+			"access_count", r.Memory.AccessCount+1,
+			"accessed_at", time.Now().Format(time.RFC3339Nano),
+		).Err(); err != nil {
+			return nil, fmt.Errorf("redis store: update access tracking for %s: %w", r.Memory.ID, err)
+		}
+	}
+
+	return results, nil
+}
+
+func containsType(types []MemoryType, t MemoryType) bool {
+	for _, want := range types {
+		if want == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Get retrieves a specific memory by ID.
+func (s *RedisStore) Get(ctx context.Context, id string) (*Memory, error) {
+	fields, err := s.client.HGetAll(ctx, redisMemoryKey(id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis store: hgetall %s: %w", id, err)
+	}
+	if len(fields) == 0 {
+		return nil, ErrNotFound
+	}
+	return redisMemoryFromFields(fields)
+}
+
+func redisMemoryFromFields(fields map[string]string) (*Memory, error) {
+	var metadata map[string]any
+	if raw := fields["metadata"]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+			return nil, fmt.Errorf("redis store: unmarshal metadata for %s: %w", fields["id"], err)
+		}
+	}
+	confidence, _ := strconv.ParseFloat(fields["confidence"], 64)
+	importance, _ := strconv.ParseFloat(fields["importance"], 64)
+	accessCount, _ := strconv.Atoi(fields["access_count"])
+	createdAt, _ := time.Parse(time.RFC3339Nano, fields["created_at"])
+	accessedAt, _ := time.Parse(time.RFC3339Nano, fields["accessed_at"])
+
+	return &Memory{
+		ID:          fields["id"],
+		Type:        MemoryType(fields["type"]),
+		Content:     fields["content"],
+		UserID:      fields["user_id"],
+		ProjectID:   fields["project_id"],
+		Metadata:    metadata,
+		Source:      fields["source"],
+		Confidence:  confidence,
+		Importance:  importance,
+		AccessCount: accessCount,
+		CreatedAt:   createdAt,
+		AccessedAt:  accessedAt,
+	}, nil
+}
+
+// Update modifies an existing memory.
+func (s *RedisStore) Update(ctx context.Context, id string, mem *Memory) error {
+	existing, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	mem.ID = id
+	if err := s.Store(ctx, mem); err != nil {
+		return err
+	}
+	if mem.UserID != existing.UserID {
+		if err := s.client.SRem(ctx, redisUserIndexKey(existing.UserID), id).Err(); err != nil {
+			return fmt.Errorf("redis store: reindex memory %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// Forget removes a memory by ID.
+func (s *RedisStore) Forget(ctx context.Context, id string) error {
+	mem, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Del(ctx, redisMemoryKey(id)).Err(); err != nil {
+		return fmt.Errorf("redis store: forget %s: %w", id, err)
+	}
+	if err := s.client.SRem(ctx, redisUserIndexKey(mem.UserID), id).Err(); err != nil {
+		return fmt.Errorf("redis store: deindex %s: %w", id, err)
+	}
+	return nil
+}
+
+// ForgetByScope removes all memories matching the scope. Given a UserID it
+// only scans that user's index set; without one it falls back to a cluster-
+// wide KEYS scan, the same admin-only tradeoff RedisMemoryStore.List makes.
+func (s *RedisStore) ForgetByScope(ctx context.Context, scope MemoryScope) error {
+	var ids []string
+	if scope.UserID != "" {
+		members, err := s.client.SMembers(ctx, redisUserIndexKey(scope.UserID)).Result()
+		if err != nil {
+			return fmt.Errorf("redis store: smembers: %w", err)
+		}
+		ids = members
+	} else {
+		keys, err := s.client.Keys(ctx, redisMemoryKeyPrefix+"*").Result()
+		if err != nil {
+			return fmt.Errorf("redis store: keys: %w", err)
+		}
+		for _, key := range keys {
+			ids = append(ids, strings.TrimPrefix(key, redisMemoryKeyPrefix))
+		}
+	}
+
+	for _, id := range ids {
+		mem, err := s.Get(ctx, id)
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if scope.ProjectID != "" && mem.ProjectID != scope.ProjectID {
+			continue
+		}
+		if scope.Type != "" && mem.Type != scope.Type {
+			continue
+		}
+		if scope.Before != nil && !mem.CreatedAt.Before(*scope.Before) {
+			continue
+		}
+		if scope.After != nil && !mem.CreatedAt.After(*scope.After) {
+			continue
+		}
+		if err := s.Forget(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateScore overwrites a memory's Importance with score.
+func (s *RedisStore) UpdateScore(ctx context.Context, id string, score float64) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	if err := s.client.HSet(ctx, redisMemoryKey(id), "importance", score).Err(); err != nil {
+		return fmt.Errorf("redis store: update score for %s: %w", id, err)
+	}
+	return nil
+}
+
+// BulkDelete removes every memory in ids, skipping IDs that don't exist.
+func (s *RedisStore) BulkDelete(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		if err := s.Forget(ctx, id); err != nil && err != ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsEnabled returns whether the underlying Redis connection is reachable.
+func (s *RedisStore) IsEnabled() bool {
+	return s.client.Do(context.Background(), "PING").Err() == nil
+}
+
+// Close releases the underlying Redis client.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+// Iterate implements StoreIterator for use by Migrate.
+func (s *RedisStore) Iterate(ctx context.Context, fn func(*Memory) error) error {
+	keys, err := s.client.Keys(ctx, redisMemoryKeyPrefix+"*").Result()
+	if err != nil {
+		return fmt.Errorf("redis store: iterate keys: %w", err)
+	}
+	for _, key := range keys {
+		fields, err := s.client.HGetAll(ctx, key).Result()
+		if err != nil {
+			return fmt.Errorf("redis store: hgetall %s: %w", key, err)
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		mem, err := redisMemoryFromFields(fields)
+		if err != nil {
+			return err
+		}
+		if err := fn(mem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package memory
+
+import "context"
+
+// StoreFactory builds a Store from a ctx (needed by backends like Postgres
+// that dial on construction) and the *StoreConfig passed to NewStore.
+type StoreFactory func(ctx context.Context, config *StoreConfig) (Store, error)
+
+// storeFactories holds backends registered via RegisterStoreBackend, checked
+// by NewStore before it falls through to the built-in switch. This lets
+// callers (or other packages) add a backend without editing factory.go.
+var storeFactories = map[StoreType]StoreFactory{}
+
+// RegisterStoreBackend adds or replaces a StoreFactory for storeType.
+// NewStore consults the registry first, so registering a factory for an
+// existing StoreType (e.g. StoreTypeMemory) overrides the built-in behavior.
+func RegisterStoreBackend(storeType StoreType, factory StoreFactory) {
+	storeFactories[storeType] = factory
+}
+
+func init() {
+	RegisterStoreBackend(StoreTypeSQLite, func(ctx context.Context, config *StoreConfig) (Store, error) {
+		cfg := DefaultSQLiteStoreConfig()
+		if config.SQLite != nil {
+			cfg = *config.SQLite
+		}
+		return NewSQLiteStore(cfg)
+	})
+	RegisterStoreBackend(StoreTypePostgres, func(ctx context.Context, config *StoreConfig) (Store, error) {
+		cfg := DefaultPostgresStoreConfig()
+		if config.Postgres != nil {
+			cfg = *config.Postgres
+		}
+		return NewPostgresStore(ctx, cfg)
+	})
+	RegisterStoreBackend(StoreTypeMongo, func(ctx context.Context, config *StoreConfig) (Store, error) {
+		cfg := DefaultMongoStoreConfig()
+		if config.Mongo != nil {
+			cfg = *config.Mongo
+		}
+		return NewMongoStore(ctx, cfg)
+	})
+	RegisterStoreBackend(StoreTypeRedis, func(ctx context.Context, config *StoreConfig) (Store, error) {
+		cfg := DefaultRedisStoreConfig()
+		if config.Redis != nil {
+			cfg = *config.Redis
+		}
+		return NewRedisStore(cfg)
+	})
+	// pgvector shares PostgresStore, which already provisions the pgvector
+	// extension and a vector column - "pgvector" just names that choice
+	// explicitly in config for operators who don't want a plain "postgres"
+	// to read as "no vector support".
+	RegisterStoreBackend(StoreTypePgVector, func(ctx context.Context, config *StoreConfig) (Store, error) {
+		cfg := DefaultPostgresStoreConfig()
+		if config.Postgres != nil {
+			cfg = *config.Postgres
+		}
+		return NewPostgresStore(ctx, cfg)
+	})
+	RegisterStoreBackend(StoreTypeWeaviate, func(ctx context.Context, config *StoreConfig) (Store, error) {
+		cfg := DefaultWeaviateStoreConfig()
+		if config.Weaviate != nil {
+			cfg = *config.Weaviate
+		}
+		return NewWeaviateStore(ctx, cfg)
+	})
+}
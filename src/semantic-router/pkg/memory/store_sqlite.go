@@ -0,0 +1,351 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // pure-Go driver, no CGO required
+
+	"github.com/google/uuid"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// SQLiteStoreConfig configures SQLiteStore.
+type SQLiteStoreConfig struct {
+	// Path is the SQLite database file path, e.g. "/var/lib/semantic-router/memory.db".
+	// Use ":memory:" for an ephemeral, process-local database.
+	Path string
+}
+
+// DefaultSQLiteStoreConfig returns a config pointing at a local file in the
+// current working directory, suitable for a single-node development deployment.
+func DefaultSQLiteStoreConfig() SQLiteStoreConfig {
+	return SQLiteStoreConfig{Path: "semantic_router_memory.db"}
+}
+
+// SQLiteStore is a database/sql-backed Store implementation for single-node
+// persistence across restarts. It keeps InMemoryStore's keyword-matching
+// relevance scoring (see keywordRelevanceScore) rather than real vector
+// similarity - RetrieveOptions has no embedding field yet, so there is
+// nothing to compare against an ANN index.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if necessary) the SQLite database at
+// config.Path and ensures the memories table exists.
+func NewSQLiteStore(config SQLiteStoreConfig) (*SQLiteStore, error) {
+	if config.Path == "" {
+		config = DefaultSQLiteStoreConfig()
+	}
+
+	db, err := sql.Open("sqlite", config.Path)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite store: open %s: %w", config.Path, err)
+	}
+	// SQLite only supports one writer at a time; cap the pool so
+	// database/sql doesn't hand out concurrent connections that would
+	// otherwise serialize on SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite store: create schema: %w", err)
+	}
+
+	logging.Infof("MemoryStore: using SQLite backend (path=%s)", config.Path)
+	return &SQLiteStore{db: db}, nil
+}
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS memories (
+	id           TEXT PRIMARY KEY,
+	type         TEXT NOT NULL,
+	content      TEXT NOT NULL,
+	user_id      TEXT NOT NULL,
+	project_id   TEXT NOT NULL DEFAULT '',
+	metadata     TEXT NOT NULL DEFAULT '{}',
+	source       TEXT NOT NULL DEFAULT '',
+	confidence   REAL NOT NULL DEFAULT 0,
+	importance   REAL NOT NULL DEFAULT 0,
+	access_count INTEGER NOT NULL DEFAULT 0,
+	created_at   INTEGER NOT NULL,
+	accessed_at  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_memories_user ON memories(user_id, project_id);
+`
+
+// Store saves a new memory.
+func (s *SQLiteStore) Store(ctx context.Context, mem *Memory) error {
+	if mem.UserID == "" {
+		return ErrInvalidUserID
+	}
+	if mem.ID == "" {
+		mem.ID = "mem_" + uuid.New().String()[:8]
+	}
+	now := time.Now()
+	if mem.CreatedAt.IsZero() {
+		mem.CreatedAt = now
+	}
+	mem.AccessedAt = now
+
+	metadata, err := json.Marshal(mem.Metadata)
+	if err != nil {
+		return fmt.Errorf("sqlite store: marshal metadata: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO memories (id, type, content, user_id, project_id, metadata, source, confidence, importance, access_count, created_at, accessed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			type=excluded.type, content=excluded.content, user_id=excluded.user_id,
+			project_id=excluded.project_id, metadata=excluded.metadata, source=excluded.source,
+			confidence=excluded.confidence, importance=excluded.importance,
+			access_count=excluded.access_count, accessed_at=excluded.accessed_at`,
+		mem.ID, string(mem.Type), mem.Content, mem.UserID, mem.ProjectID, string(metadata),
+		mem.Source, mem.Confidence, mem.Importance, mem.AccessCount, mem.CreatedAt.Unix(), mem.AccessedAt.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("sqlite store: insert memory %s: %w", mem.ID, err)
+	}
+	return nil
+}
+
+// Retrieve finds memories matching the query and options, scored with
+// keywordRelevanceScore over every row scoped to the user/project/types.
+func (s *SQLiteStore) Retrieve(ctx context.Context, opts RetrieveOptions) ([]*RetrieveResult, error) {
+	if opts.UserID == "" {
+		return nil, ErrInvalidUserID
+	}
+
+	query := "SELECT " + sqliteMemoryColumns + " FROM memories WHERE user_id = ?"
+	args := []any{opts.UserID}
+	if opts.ProjectID != "" {
+		query += " AND project_id = ?"
+		args = append(args, opts.ProjectID)
+	}
+	if len(opts.Types) > 0 {
+		query += " AND type IN (" + placeholders(len(opts.Types)) + ")"
+		for _, t := range opts.Types {
+			args = append(args, string(t))
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite store: query memories: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*RetrieveResult
+	for rows.Next() {
+		mem, err := scanSQLiteMemory(rows)
+		if err != nil {
+			return nil, err
+		}
+		relevance := keywordRelevanceScore(mem.Content, opts.Query)
+		if relevance <= 0 {
+			continue
+		}
+		results = append(results, &RetrieveResult{Memory: mem, Relevance: relevance})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sqlite store: scan memories: %w", err)
+	}
+
+	for i := 0; i < len(results); i++ {
+		for j := i + 1; j < len(results); j++ {
+			if results[j].Relevance > results[i].Relevance {
+				results[i], results[j] = results[j], results[i]
+			}
+		}
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	for _, r := range results {
+		if _, err := s.db.ExecContext(ctx, `UPDATE memories SET access_count = access_count + 1, accessed_at = ? WHERE id = ?`, time.Now().Unix(), r.Memory.ID); err != nil {
+			return nil, fmt.Errorf("sqlite store: update access tracking for %s: %w", r.Memory.ID, err)
+		}
+	}
+
+	return results, nil
+}
+
+// Get retrieves a specific memory by ID.
+func (s *SQLiteStore) Get(ctx context.Context, id string) (*Memory, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+sqliteMemoryColumns+" FROM memories WHERE id = ?", id)
+	mem, err := scanSQLiteMemory(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sqlite store: get memory %s: %w", id, err)
+	}
+	return mem, nil
+}
+
+// Update modifies an existing memory.
+func (s *SQLiteStore) Update(ctx context.Context, id string, mem *Memory) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	mem.ID = id
+	return s.Store(ctx, mem)
+}
+
+// Forget removes a memory by ID.
+func (s *SQLiteStore) Forget(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM memories WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("sqlite store: forget %s: %w", id, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("sqlite store: forget %s: %w", id, err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ForgetByScope removes all memories matching the scope.
+func (s *SQLiteStore) ForgetByScope(ctx context.Context, scope MemoryScope) error {
+	query := "DELETE FROM memories WHERE 1=1"
+	var args []any
+	if scope.UserID != "" {
+		query += " AND user_id = ?"
+		args = append(args, scope.UserID)
+	}
+	if scope.ProjectID != "" {
+		query += " AND project_id = ?"
+		args = append(args, scope.ProjectID)
+	}
+	if scope.Type != "" {
+		query += " AND type = ?"
+		args = append(args, string(scope.Type))
+	}
+	if scope.Before != nil {
+		query += " AND created_at < ?"
+		args = append(args, scope.Before.Unix())
+	}
+	if scope.After != nil {
+		query += " AND created_at > ?"
+		args = append(args, scope.After.Unix())
+	}
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("sqlite store: forget by scope: %w", err)
+	}
+	return nil
+}
+
+// UpdateScore overwrites a memory's Importance with score.
+func (s *SQLiteStore) UpdateScore(ctx context.Context, id string, score float64) error {
+	res, err := s.db.ExecContext(ctx, "UPDATE memories SET importance = ? WHERE id = ?", score, id)
+	if err != nil {
+		return fmt.Errorf("sqlite store: update score for %s: %w", id, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("sqlite store: update score for %s: %w", id, err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// BulkDelete removes every memory in ids in one statement.
+func (s *SQLiteStore) BulkDelete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	query := "DELETE FROM memories WHERE id IN (" + placeholders(len(ids)) + ")"
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("sqlite store: bulk delete: %w", err)
+	}
+	return nil
+}
+
+// IsEnabled returns whether the underlying database connection is reachable.
+func (s *SQLiteStore) IsEnabled() bool {
+	return s.db.Ping() == nil
+}
+
+// Close releases the underlying database connection.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Iterate implements StoreIterator for use by Migrate.
+func (s *SQLiteStore) Iterate(ctx context.Context, fn func(*Memory) error) error {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+sqliteMemoryColumns+" FROM memories")
+	if err != nil {
+		return fmt.Errorf("sqlite store: iterate: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		mem, err := scanSQLiteMemory(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(mem); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+const sqliteMemoryColumns = "id, type, content, user_id, project_id, metadata, source, confidence, importance, access_count, created_at, accessed_at"
+
+// sqliteRowScanner is satisfied by both *sql.Row and *sql.Rows.
+type sqliteRowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSQLiteMemory(row sqliteRowScanner) (*Memory, error) {
+	var (
+		mem        Memory
+		memType    string
+		metadata   string
+		createdAt  int64
+		accessedAt int64
+	)
+	if err := row.Scan(&mem.ID, &memType, &mem.Content, &mem.UserID, &mem.ProjectID, &metadata,
+		&mem.Source, &mem.Confidence, &mem.Importance, &mem.AccessCount, &createdAt, &accessedAt); err != nil {
+		return nil, err
+	}
+	mem.Type = MemoryType(memType)
+	mem.CreatedAt = time.Unix(createdAt, 0)
+	mem.AccessedAt = time.Unix(accessedAt, 0)
+	if err := json.Unmarshal([]byte(metadata), &mem.Metadata); err != nil {
+		return nil, fmt.Errorf("sqlite store: unmarshal metadata for %s: %w", mem.ID, err)
+	}
+	return &mem, nil
+}
+
+func placeholders(n int) string {
+	out := ""
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			out += ", "
+		}
+		out += "?"
+	}
+	return out
+}
@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// Logf matches logging.Infof's signature, letting a Provider log through
+// the same channel the rest of this package uses without importing
+// logging directly - handy for a third-party backend or a test double.
+type Logf func(format string, args ...any)
+
+// Provider constructs a Store from a DSN whose scheme (the part before
+// "://", or before the first ":" for an authority-less scheme like "mem:")
+// it was registered for under Register.
+type Provider func(logf Logf, dsn string) (Store, error)
+
+var (
+	regMu     sync.Mutex
+	providers = map[string]Provider{}
+	regOnce   sync.Once
+)
+
+// Register adds or replaces the Provider for scheme. Safe to call from a
+// package's init() func - a third-party backend never needs memory to
+// import it, only a blank import (`_ "github.com/you/memstore"`) from
+// whatever wires up the router, mirroring Tailscale's ipn/store registry.
+func Register(scheme string, p Provider) {
+	regMu.Lock()
+	defer regMu.Unlock()
+	providers[scheme] = p
+}
+
+// registerDefaultStores registers the backends this package ships with
+// itself, the first time New resolves a scheme. It only fills in schemes
+// nobody has claimed yet, so a backend that already registered its own
+// scheme - milvus's cgo-gated init() below, or a caller overriding "mem"
+// before the first New call - is left alone rather than raced or clobbered.
+func registerDefaultStores() {
+	registerIfAbsent("mem", func(logf Logf, dsn string) (Store, error) {
+		logf("memory: using in-memory backend (dsn=%s)", dsn)
+		return NewInMemoryStore(), nil
+	})
+	registerIfAbsent("sqlite", func(logf Logf, dsn string) (Store, error) {
+		cfg := DefaultSQLiteStoreConfig()
+		if path := schemeOpaque(dsn); path != "" {
+			cfg.Path = path
+		}
+		return NewSQLiteStore(cfg)
+	})
+	registerIfAbsent("postgres", func(logf Logf, dsn string) (Store, error) {
+		cfg := DefaultPostgresStoreConfig()
+		cfg.DSN = dsn
+		return NewPostgresStore(context.Background(), cfg)
+	})
+	registerIfAbsent("mongodb", func(logf Logf, dsn string) (Store, error) {
+		cfg := DefaultMongoStoreConfig()
+		cfg.URI = dsn
+		if u, err := url.Parse(dsn); err == nil {
+			cfg.Database = strings.TrimPrefix(u.Path, "/")
+		}
+		return NewMongoStore(context.Background(), cfg)
+	})
+	registerIfAbsent("pgvector", func(logf Logf, dsn string) (Store, error) {
+		cfg := DefaultPostgresStoreConfig()
+		cfg.DSN = strings.Replace(dsn, "pgvector://", "postgres://", 1)
+		return NewPostgresStore(context.Background(), cfg)
+	})
+	registerIfAbsent("redis", func(logf Logf, dsn string) (Store, error) {
+		cfg := DefaultRedisStoreConfig()
+		cfg.Addr = schemeOpaque(dsn)
+		return NewRedisStore(cfg)
+	})
+	registerIfAbsent("weaviate", func(logf Logf, dsn string) (Store, error) {
+		cfg := DefaultWeaviateStoreConfig()
+		cfg.BaseURL = strings.Replace(dsn, "weaviate://", "http://", 1)
+		return NewWeaviateStore(context.Background(), cfg)
+	})
+}
+
+func registerIfAbsent(scheme string, p Provider) {
+	regMu.Lock()
+	defer regMu.Unlock()
+	if _, exists := providers[scheme]; !exists {
+		providers[scheme] = p
+	}
+}
+
+// New builds a Store from dsn, dispatching on its URL scheme to the
+// Provider registered for it under Register. The built-in backends (mem,
+// sqlite, postgres, pgvector, mongodb, redis, weaviate, and milvus when
+// built with CGO) register themselves the first time New is called, so a
+// caller constructing one backend never pays for importing the others'
+// client libraries.
+func New(dsn string) (Store, error) {
+	regOnce.Do(registerDefaultStores)
+
+	scheme := schemeOf(dsn)
+	if scheme == "" {
+		return nil, fmt.Errorf("memory: dsn %q has no scheme", dsn)
+	}
+
+	regMu.Lock()
+	p, ok := providers[scheme]
+	regMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("memory: no store registered for scheme %q (dsn=%q)", scheme, dsn)
+	}
+	return p(logging.Infof, dsn)
+}
+
+// schemeOf returns dsn's scheme: the part before "://" if present (e.g.
+// "milvus" from "milvus://host:9091/collection"), otherwise the part before
+// the first ":" (e.g. "mem" from "mem:"). Empty if dsn has neither.
+func schemeOf(dsn string) string {
+	if i := strings.Index(dsn, "://"); i >= 0 {
+		return dsn[:i]
+	}
+	if i := strings.Index(dsn, ":"); i >= 0 {
+		return dsn[:i]
+	}
+	return ""
+}
+
+// schemeOpaque returns what follows dsn's scheme prefix: the part after
+// "://" for an authority DSN, or after the first ":" for an opaque one like
+// "sqlite:/var/lib/memory.db". Empty for a bare "scheme:" DSN.
+func schemeOpaque(dsn string) string {
+	if i := strings.Index(dsn, "://"); i >= 0 {
+		return dsn[i+3:]
+	}
+	if i := strings.Index(dsn, ":"); i >= 0 {
+		return dsn[i+1:]
+	}
+	return ""
+}
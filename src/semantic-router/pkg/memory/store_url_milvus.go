@@ -0,0 +1,38 @@
+//go:build !windows && cgo
+
+package memory
+
+import (
+	"net/url"
+	"strings"
+)
+
+// init registers the "milvus" scheme so New("milvus://host:port/collection",
+// ...) works without memory's non-cgo callers paying for MilvusStoreConfig.
+// Registering from init() rather than registerDefaultStores means this
+// scheme is claimed before New's regOnce ever runs, so a nocgo build simply
+// never sees "milvus" in the registry instead of registering a Provider that
+// would fail every call.
+func init() {
+	Register("milvus", func(logf Logf, dsn string) (Store, error) {
+		cfg := DefaultMilvusConfig()
+		if address, collection, ok := parseMilvusDSN(dsn); ok {
+			cfg.Address = address
+			if collection != "" {
+				cfg.CollectionName = collection
+			}
+		}
+		return NewMilvusStore(cfg)
+	})
+}
+
+// parseMilvusDSN splits a "milvus://host:port/collection" DSN into its host:port
+// address and collection name. The collection is empty, and ok is true as long
+// as a host is present, if the DSN has no path component.
+func parseMilvusDSN(dsn string) (address, collection string, ok bool) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.Host == "" {
+		return "", "", false
+	}
+	return u.Host, strings.TrimPrefix(u.Path, "/"), true
+}
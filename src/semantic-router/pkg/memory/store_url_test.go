@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemeOf(t *testing.T) {
+	tests := []struct {
+		dsn  string
+		want string
+	}{
+		{"mem:", "mem"},
+		{"milvus://localhost:19530/agentic_memory", "milvus"},
+		{"sqlite:/var/lib/memory.db", "sqlite"},
+		{"no-scheme-here", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, schemeOf(tt.dsn), "dsn=%q", tt.dsn)
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	sqlitePath := filepath.Join(t.TempDir(), "new_store.db")
+
+	tests := []struct {
+		name    string
+		dsn     string
+		wantErr bool
+	}{
+		{name: "mem", dsn: "mem:"},
+		{name: "sqlite", dsn: "sqlite:" + sqlitePath},
+		{name: "no scheme", dsn: "garbage", wantErr: true},
+		{name: "unregistered scheme", dsn: "dynamodb://table", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := New(tt.dsn)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, store)
+			t.Cleanup(func() { store.Close() })
+			assert.True(t, store.IsEnabled())
+		})
+	}
+}
+
+func TestNewStore_PostgresAndMongoWhenConfigured(t *testing.T) {
+	if dsn := os.Getenv("TEST_POSTGRES_DSN"); dsn != "" {
+		t.Run("postgres", func(t *testing.T) {
+			store, err := New(dsn)
+			require.NoError(t, err)
+			t.Cleanup(func() { store.Close() })
+		})
+	}
+	if uri := os.Getenv("TEST_MONGO_URI"); uri != "" {
+		t.Run("mongodb", func(t *testing.T) {
+			store, err := New(uri)
+			require.NoError(t, err)
+			t.Cleanup(func() { store.Close() })
+		})
+	}
+	if addr := os.Getenv("TEST_REDIS_ADDR"); addr != "" {
+		t.Run("redis", func(t *testing.T) {
+			store, err := New("redis://" + addr)
+			require.NoError(t, err)
+			t.Cleanup(func() { store.Close() })
+		})
+	}
+	if url := os.Getenv("TEST_WEAVIATE_URL"); url != "" {
+		t.Run("weaviate", func(t *testing.T) {
+			store, err := New("weaviate://" + strings.TrimPrefix(url, "http://"))
+			require.NoError(t, err)
+			t.Cleanup(func() { store.Close() })
+		})
+	}
+}
+
+func TestRegister_OverridesScheme(t *testing.T) {
+	called := false
+	Register("test-override", func(logf Logf, dsn string) (Store, error) {
+		called = true
+		return NewInMemoryStore(), nil
+	})
+
+	store, err := New("test-override://whatever")
+	require.NoError(t, err)
+	require.NotNil(t, store)
+	assert.True(t, called, "Register's provider should have been called instead of erroring")
+}
@@ -0,0 +1,453 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/observability/logging"
+)
+
+// WeaviateStoreConfig configures WeaviateStore.
+type WeaviateStoreConfig struct {
+	// BaseURL is the Weaviate REST endpoint, e.g. "http://localhost:8080".
+	BaseURL string
+
+	// APIKey authenticates with Weaviate's API-key auth. Empty disables auth.
+	APIKey string
+
+	// ClassName is the Weaviate class memories are stored under. Defaults to
+	// "SemanticRouterMemory".
+	ClassName string
+
+	// HTTPClient is the client used for requests. Defaults to a client with
+	// a 10s timeout.
+	HTTPClient *http.Client
+}
+
+// DefaultWeaviateStoreConfig returns a config with the default class name
+// and HTTP client; BaseURL must be set by the caller before NewWeaviateStore
+// is called.
+func DefaultWeaviateStoreConfig() WeaviateStoreConfig {
+	return WeaviateStoreConfig{
+		ClassName:  "SemanticRouterMemory",
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// WeaviateStore is a Weaviate-backed Store implementation, for operators who
+// run Weaviate as their vector database instead of Milvus. It talks to
+// Weaviate's REST objects API for single-object reads/writes and its
+// GraphQL endpoint for scoped listing, the same split Weaviate's own docs
+// recommend: REST for CRUD-by-ID, GraphQL for anything filtered.
+//
+// Like PostgresStore and MongoStore, Retrieve scores candidates with
+// keywordRelevanceScore rather than Weaviate's native nearVector search,
+// since RetrieveOptions carries no query embedding yet; swapping the
+// GraphQL query's sort for a nearVector clause can slot in once that's
+// wired up.
+type WeaviateStore struct {
+	baseURL   string
+	apiKey    string
+	className string
+	client    *http.Client
+}
+
+// NewWeaviateStore returns a WeaviateStore pointed at config.BaseURL, best-
+// effort ensuring config.ClassName exists as a schema class (it may already
+// have been provisioned by an operator with a custom vectorizer, in which
+// case the create call's 422 "already exists" is ignored).
+func NewWeaviateStore(ctx context.Context, config WeaviateStoreConfig) (*WeaviateStore, error) {
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("weaviate store requires a base url")
+	}
+	if config.ClassName == "" {
+		config.ClassName = "SemanticRouterMemory"
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	store := &WeaviateStore{
+		baseURL:   strings.TrimSuffix(config.BaseURL, "/"),
+		apiKey:    config.APIKey,
+		className: config.ClassName,
+		client:    config.HTTPClient,
+	}
+
+	schema := map[string]any{
+		"class":      config.ClassName,
+		"vectorizer": "none",
+		"properties": []map[string]any{
+			{"name": "memId", "dataType": []string{"text"}},
+			{"name": "type", "dataType": []string{"text"}},
+			{"name": "content", "dataType": []string{"text"}},
+			{"name": "userId", "dataType": []string{"text"}},
+			{"name": "projectId", "dataType": []string{"text"}},
+			{"name": "metadata", "dataType": []string{"text"}},
+			{"name": "source", "dataType": []string{"text"}},
+			{"name": "confidence", "dataType": []string{"number"}},
+			{"name": "importance", "dataType": []string{"number"}},
+			{"name": "accessCount", "dataType": []string{"int"}},
+			{"name": "createdAt", "dataType": []string{"date"}},
+			{"name": "accessedAt", "dataType": []string{"date"}},
+		},
+	}
+	if _, err := store.do(ctx, http.MethodPost, "/v1/schema", schema); err != nil {
+		logging.Infof("MemoryStore: weaviate schema class %q not created, assuming it already exists (%v)", config.ClassName, err)
+	}
+
+	logging.Infof("MemoryStore: using Weaviate backend (url=%s, class=%s)", config.BaseURL, config.ClassName)
+	return store, nil
+}
+
+func (s *WeaviateStore) do(ctx context.Context, method, path string, body any) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("weaviate store: marshal request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("weaviate store: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("weaviate store: request %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("weaviate store: read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("weaviate store: %s %s returned %d: %s", method, path, resp.StatusCode, string(data))
+	}
+	return data, nil
+}
+
+// weaviateObject is a memory's on-the-wire shape for the objects API.
+type weaviateObject struct {
+	ID         string         `json:"id,omitempty"`
+	Class      string         `json:"class"`
+	Properties map[string]any `json:"properties"`
+}
+
+func weaviateObjectFromMemory(className string, mem *Memory) (weaviateObject, error) {
+	metadata, err := json.Marshal(mem.Metadata)
+	if err != nil {
+		return weaviateObject{}, fmt.Errorf("weaviate store: marshal metadata: %w", err)
+	}
+	return weaviateObject{
+		ID:    mem.ID,
+		Class: className,
+		Properties: map[string]any{
+			"memId":       mem.ID,
+			"type":        string(mem.Type),
+			"content":     mem.Content,
+			"userId":      mem.UserID,
+			"projectId":   mem.ProjectID,
+			"metadata":    string(metadata),
+			"source":      mem.Source,
+			"confidence":  mem.Confidence,
+			"importance":  mem.Importance,
+			"accessCount": mem.AccessCount,
+			"createdAt":   mem.CreatedAt.Format(time.RFC3339Nano),
+			"accessedAt":  mem.AccessedAt.Format(time.RFC3339Nano),
+		},
+	}, nil
+}
+
+func memoryFromWeaviateObject(obj weaviateObject) (*Memory, error) {
+	props := obj.Properties
+	var metadata map[string]any
+	if raw, _ := props["metadata"].(string); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+			return nil, fmt.Errorf("weaviate store: unmarshal metadata for %s: %w", obj.ID, err)
+		}
+	}
+	createdAt, _ := time.Parse(time.RFC3339Nano, stringProp(props, "createdAt"))
+	accessedAt, _ := time.Parse(time.RFC3339Nano, stringProp(props, "accessedAt"))
+	accessCount, _ := props["accessCount"].(float64)
+	confidence, _ := props["confidence"].(float64)
+	importance, _ := props["importance"].(float64)
+
+	return &Memory{
+		ID:          stringProp(props, "memId"),
+		Type:        MemoryType(stringProp(props, "type")),
+		Content:     stringProp(props, "content"),
+		UserID:      stringProp(props, "userId"),
+		ProjectID:   stringProp(props, "projectId"),
+		Metadata:    metadata,
+		Source:      stringProp(props, "source"),
+		Confidence:  confidence,
+		Importance:  importance,
+		AccessCount: int(accessCount),
+		CreatedAt:   createdAt,
+		AccessedAt:  accessedAt,
+	}, nil
+}
+
+func stringProp(props map[string]any, key string) string {
+	v, _ := props[key].(string)
+	return v
+}
+
+// Store saves a new memory, upserting by ID.
+func (s *WeaviateStore) Store(ctx context.Context, mem *Memory) error {
+	if mem.UserID == "" {
+		return ErrInvalidUserID
+	}
+	if mem.ID == "" {
+		mem.ID = "mem_" + uuid.New().String()[:8]
+	}
+	now := time.Now()
+	if mem.CreatedAt.IsZero() {
+		mem.CreatedAt = now
+	}
+	mem.AccessedAt = now
+
+	obj, err := weaviateObjectFromMemory(s.className, mem)
+	if err != nil {
+		return err
+	}
+	if _, err := s.do(ctx, http.MethodPut, "/v1/objects/"+weaviateObjectID(mem.ID), obj); err != nil {
+		if err == ErrNotFound {
+			_, err = s.do(ctx, http.MethodPost, "/v1/objects", obj)
+		}
+		if err != nil {
+			return fmt.Errorf("weaviate store: upsert memory %s: %w", mem.ID, err)
+		}
+	}
+	return nil
+}
+
+// weaviateObjectID derives a deterministic Weaviate UUID from a memory ID,
+// since Weaviate requires object IDs to be UUIDs but this package's memory
+// IDs are "mem_xxxx" strings.
+func weaviateObjectID(memID string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(memID)).String()
+}
+
+// Retrieve finds memories matching the query and options, scored with
+// keywordRelevanceScore over every object scoped to the user/project/types.
+func (s *WeaviateStore) Retrieve(ctx context.Context, opts RetrieveOptions) ([]*RetrieveResult, error) {
+	if opts.UserID == "" {
+		return nil, ErrInvalidUserID
+	}
+
+	objs, err := s.listByUser(ctx, opts.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*RetrieveResult
+	for _, mem := range objs {
+		if opts.ProjectID != "" && mem.ProjectID != opts.ProjectID {
+			continue
+		}
+		if len(opts.Types) > 0 && !containsType(opts.Types, mem.Type) {
+			continue
+		}
+		relevance := keywordRelevanceScore(mem.Content, opts.Query)
+		if relevance <= 0 {
+			continue
+		}
+		results = append(results, &RetrieveResult{Memory: mem, Relevance: relevance})
+	}
+
+	for i := 0; i < len(results); i++ {
+		for j := i + 1; j < len(results); j++ {
+			if results[j].Relevance > results[i].Relevance {
+				results[i], results[j] = results[j], results[i]
+			}
+		}
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	for _, r := range results {
+		r.Memory.AccessCount++
+		r.Memory.AccessedAt = time.Now()
+		if err := s.patchAccessTracking(ctx, r.Memory); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+func (s *WeaviateStore) patchAccessTracking(ctx context.Context, mem *Memory) error {
+	patch := weaviateObject{Class: s.className, Properties: map[string]any{
+		"accessCount": mem.AccessCount,
+		"accessedAt":  mem.AccessedAt.Format(time.RFC3339Nano),
+	}}
+	if _, err := s.do(ctx, http.MethodPatch, "/v1/objects/"+weaviateObjectID(mem.ID), patch); err != nil {
+		return fmt.Errorf("weaviate store: update access tracking for %s: %w", mem.ID, err)
+	}
+	return nil
+}
+
+// listByUser runs a GraphQL Get query filtered to userId, the only way to
+// list-with-a-filter against Weaviate (the REST objects endpoint supports
+// no "where" query param).
+func (s *WeaviateStore) listByUser(ctx context.Context, userID string) ([]*Memory, error) {
+	query := fmt.Sprintf(`{
+		Get {
+			%s(where: {path: ["userId"], operator: Equal, valueText: %q}, limit: 1000) {
+				memId type content userId projectId metadata source confidence importance accessCount createdAt accessedAt
+			}
+		}
+	}`, s.className, userID)
+
+	data, err := s.do(ctx, http.MethodPost, "/v1/graphql", map[string]string{"query": query})
+	if err != nil {
+		return nil, fmt.Errorf("weaviate store: graphql list: %w", err)
+	}
+
+	var resp struct {
+		Data struct {
+			Get map[string][]map[string]any `json:"Get"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("weaviate store: decode graphql response: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("weaviate store: graphql errors: %s", resp.Errors[0].Message)
+	}
+
+	var memories []*Memory
+	for _, props := range resp.Data.Get[s.className] {
+		mem, err := memoryFromWeaviateObject(weaviateObject{Properties: props})
+		if err != nil {
+			return nil, err
+		}
+		memories = append(memories, mem)
+	}
+	return memories, nil
+}
+
+// Get retrieves a specific memory by ID.
+func (s *WeaviateStore) Get(ctx context.Context, id string) (*Memory, error) {
+	data, err := s.do(ctx, http.MethodGet, "/v1/objects/"+weaviateObjectID(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	var obj weaviateObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("weaviate store: decode object %s: %w", id, err)
+	}
+	return memoryFromWeaviateObject(obj)
+}
+
+// Update modifies an existing memory.
+func (s *WeaviateStore) Update(ctx context.Context, id string, mem *Memory) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	mem.ID = id
+	return s.Store(ctx, mem)
+}
+
+// Forget removes a memory by ID.
+func (s *WeaviateStore) Forget(ctx context.Context, id string) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	if _, err := s.do(ctx, http.MethodDelete, "/v1/objects/"+weaviateObjectID(id), nil); err != nil {
+		return fmt.Errorf("weaviate store: forget %s: %w", id, err)
+	}
+	return nil
+}
+
+// ForgetByScope removes all memories matching the scope.
+func (s *WeaviateStore) ForgetByScope(ctx context.Context, scope MemoryScope) error {
+	if scope.UserID == "" {
+		return fmt.Errorf("weaviate store: forget by scope requires a user id")
+	}
+	memories, err := s.listByUser(ctx, scope.UserID)
+	if err != nil {
+		return err
+	}
+	for _, mem := range memories {
+		if scope.ProjectID != "" && mem.ProjectID != scope.ProjectID {
+			continue
+		}
+		if scope.Type != "" && mem.Type != scope.Type {
+			continue
+		}
+		if scope.Before != nil && !mem.CreatedAt.Before(*scope.Before) {
+			continue
+		}
+		if scope.After != nil && !mem.CreatedAt.After(*scope.After) {
+			continue
+		}
+		if err := s.Forget(ctx, mem.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateScore overwrites a memory's Importance with score.
+func (s *WeaviateStore) UpdateScore(ctx context.Context, id string, score float64) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	patch := weaviateObject{Class: s.className, Properties: map[string]any{"importance": score}}
+	if _, err := s.do(ctx, http.MethodPatch, "/v1/objects/"+weaviateObjectID(id), patch); err != nil {
+		return fmt.Errorf("weaviate store: update score for %s: %w", id, err)
+	}
+	return nil
+}
+
+// BulkDelete removes every memory in ids, skipping IDs that don't exist.
+func (s *WeaviateStore) BulkDelete(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		if err := s.Forget(ctx, id); err != nil && err != ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsEnabled returns whether the Weaviate REST endpoint is reachable.
+func (s *WeaviateStore) IsEnabled() bool {
+	_, err := s.do(context.Background(), http.MethodGet, "/v1/.well-known/ready", nil)
+	return err == nil
+}
+
+// Close is a no-op; WeaviateStore holds no persistent connection beyond its
+// http.Client.
+func (s *WeaviateStore) Close() error {
+	return nil
+}
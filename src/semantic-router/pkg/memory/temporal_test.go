@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInMemoryStore_Retrieve_TimeWindow(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	march15 := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	july := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	store.Store(ctx, &Memory{
+		Type:      MemoryTypeEpisodic,
+		Content:   "On March 15, we discussed the vacation plans to Hawaii",
+		UserID:    "user_1",
+		CreatedAt: march15,
+	})
+	store.Store(ctx, &Memory{
+		Type:      MemoryTypeEpisodic,
+		Content:   "We discussed the vacation plans to Hawaii again",
+		UserID:    "user_1",
+		CreatedAt: july,
+	})
+
+	results, err := store.Retrieve(ctx, RetrieveOptions{
+		UserID: "user_1",
+		Query:  "vacation Hawaii",
+		Limit:  5,
+		TimeWindow: &[2]time.Time{
+			time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if len(results) != 1 || !results[0].Memory.CreatedAt.Equal(march15) {
+		t.Fatalf("Retrieve with TimeWindow = %+v, want only the March 15 memory", results)
+	}
+}
+
+func TestInMemoryStore_Retrieve_RecencyWeight(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	anchor := time.Date(2026, 7, 29, 0, 0, 0, 0, time.UTC)
+	old := anchor.Add(-90 * 24 * time.Hour)
+	fresh := anchor.Add(-1 * time.Hour)
+
+	store.Store(ctx, &Memory{
+		Type:      MemoryTypeEpisodic,
+		Content:   "On March 15, we discussed the vacation plans to Hawaii",
+		UserID:    "user_1",
+		CreatedAt: old,
+	})
+	store.Store(ctx, &Memory{
+		Type:      MemoryTypeEpisodic,
+		Content:   "We discussed the vacation plans to Hawaii",
+		UserID:    "user_1",
+		CreatedAt: fresh,
+	})
+
+	results, err := store.Retrieve(ctx, RetrieveOptions{
+		UserID:        "user_1",
+		Query:         "vacation Hawaii",
+		Limit:         5,
+		RecencyWeight: 0.8,
+		TimeAnchor:    &anchor,
+	})
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Retrieve = %d results, want 2", len(results))
+	}
+
+	// Both memories match the query about equally well on keyword overlap, so
+	// a heavy RecencyWeight should rank the near-duplicate fresh memory above
+	// the stale one and give it a lower blended score than its raw similarity.
+	if !results[0].Memory.CreatedAt.Equal(fresh) {
+		t.Fatalf("top result CreatedAt = %v, want the fresh memory ranked first", results[0].Memory.CreatedAt)
+	}
+	var oldResult, freshResult *RetrieveResult
+	for _, r := range results {
+		if r.Memory.CreatedAt.Equal(old) {
+			oldResult = r
+		} else {
+			freshResult = r
+		}
+	}
+	if freshResult.Relevance <= oldResult.Relevance {
+		t.Fatalf("fresh Relevance = %.3f, want higher than old Relevance = %.3f", freshResult.Relevance, oldResult.Relevance)
+	}
+	if oldResult.Relevance >= oldResult.RawScore {
+		t.Fatalf("old Relevance = %.3f, want lower than its RawScore = %.3f once heavily recency-weighted", oldResult.Relevance, oldResult.RawScore)
+	}
+}
+
+func TestInMemoryStore_ForgetByScope_AgeBounds(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	anchor := time.Date(2026, 7, 29, 0, 0, 0, 0, time.UTC)
+	old := anchor.Add(-120 * 24 * time.Hour)
+	recent := anchor.Add(-1 * 24 * time.Hour)
+
+	store.Store(ctx, &Memory{Type: MemoryTypeEpisodic, Content: "stale note", UserID: "user_1", CreatedAt: old})
+	store.Store(ctx, &Memory{Type: MemoryTypeEpisodic, Content: "recent note", UserID: "user_1", CreatedAt: recent})
+
+	cutoff := anchor.Add(-90 * 24 * time.Hour)
+	if err := store.ForgetByScope(ctx, MemoryScope{UserID: "user_1", Before: &cutoff}); err != nil {
+		t.Fatalf("ForgetByScope: %v", err)
+	}
+
+	if store.Count() != 1 {
+		t.Fatalf("Count after ForgetByScope(Before) = %d, want 1", store.Count())
+	}
+	results, err := store.Retrieve(ctx, RetrieveOptions{UserID: "user_1", Query: "note", Limit: 5})
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if len(results) != 1 || results[0].Memory.Content != "recent note" {
+		t.Fatalf("Retrieve after ForgetByScope(Before) = %+v, want only the recent note", results)
+	}
+}
@@ -0,0 +1,240 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+)
+
+// Executor runs memory tool calls against a Store, scoped to one user and
+// (optionally) project. Scope comes from the calling request's identity, not
+// from a tool call's own arguments - a tool call can never name a different
+// user_id/project_id to search, store into, or delete from, the same
+// guarantee MemoryFilter's auto-retrieve/auto-store path already makes.
+type Executor struct {
+	store     memory.Store
+	userID    string
+	projectID string
+	readOnly  bool
+}
+
+// NewExecutor builds an Executor scoped to userID/projectID. readOnly, when
+// true, refuses memory_forget/memory_update calls with an error result
+// rather than executing them - for a caller/agent allowed to advertise and
+// use memory_search/memory_store only (see Specs).
+func NewExecutor(store memory.Store, userID, projectID string, readOnly bool) *Executor {
+	return &Executor{store: store, userID: userID, projectID: projectID, readOnly: readOnly}
+}
+
+// toolResult is the JSON shape Execute returns on both success and handled
+// failure - a tool message's content should always be valid JSON the model
+// can reason about, rather than a bare error string on one path and a
+// structured object on the other.
+type toolResult struct {
+	Error  string      `json:"error,omitempty"`
+	Result interface{} `json:"result,omitempty"`
+}
+
+// Execute runs the named memory tool with argsJSON (the tool_call's
+// function.arguments string) and returns the JSON-encoded result to feed
+// back as a role:"tool" message's content. Execute itself only returns a Go
+// error for a name outside this package's namespace - callers should check
+// IsMemoryTool before calling Execute; every in-namespace failure (bad
+// arguments, a missing memory, a read-only refusal) comes back as a
+// toolResult.Error so the model can see what went wrong and adjust.
+func (e *Executor) Execute(ctx context.Context, name string, argsJSON string) (string, error) {
+	switch name {
+	case ToolMemorySearch:
+		return e.search(ctx, argsJSON), nil
+	case ToolMemoryStore:
+		return e.storeMemory(ctx, argsJSON), nil
+	case ToolMemoryForget:
+		return e.forget(ctx, argsJSON), nil
+	case ToolMemoryUpdate:
+		return e.update(ctx, argsJSON), nil
+	default:
+		return "", fmt.Errorf("memory/tools: %q is not a memory tool", name)
+	}
+}
+
+func marshalResult(r toolResult) string {
+	data, err := json.Marshal(r)
+	if err != nil {
+		// toolResult's fields are all JSON-safe primitives/maps built by
+		// this package, so Marshal failing here would be a bug, not a
+		// runtime condition to recover from gracefully.
+		return fmt.Sprintf(`{"error":"memory/tools: failed to encode result: %s"}`, err)
+	}
+	return string(data)
+}
+
+func errorResult(format string, args ...interface{}) string {
+	return marshalResult(toolResult{Error: fmt.Sprintf(format, args...)})
+}
+
+type memorySearchArgs struct {
+	Query string   `json:"query"`
+	Types []string `json:"types"`
+	Limit int      `json:"limit"`
+}
+
+type memorySearchResultItem struct {
+	ID        string  `json:"id"`
+	Type      string  `json:"type"`
+	Content   string  `json:"content"`
+	Relevance float64 `json:"relevance"`
+}
+
+func (e *Executor) search(ctx context.Context, argsJSON string) string {
+	var args memorySearchArgs
+	if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+		return errorResult("invalid memory_search arguments: %v", err)
+	}
+	if args.Query == "" {
+		return errorResult("memory_search requires a non-empty query")
+	}
+
+	opts := memory.RetrieveOptions{
+		Query:     args.Query,
+		UserID:    e.userID,
+		ProjectID: e.projectID,
+		Limit:     args.Limit,
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 5
+	}
+	for _, t := range args.Types {
+		opts.Types = append(opts.Types, memory.MemoryType(t))
+	}
+
+	results, err := e.store.Retrieve(ctx, opts)
+	if err != nil {
+		return errorResult("memory_search failed: %v", err)
+	}
+
+	items := make([]memorySearchResultItem, 0, len(results))
+	for _, r := range results {
+		items = append(items, memorySearchResultItem{
+			ID:        r.Memory.ID,
+			Type:      string(r.Memory.Type),
+			Content:   r.Memory.Content,
+			Relevance: r.Relevance,
+		})
+	}
+	return marshalResult(toolResult{Result: items})
+}
+
+type memoryStoreArgs struct {
+	Content    string  `json:"content"`
+	Type       string  `json:"type"`
+	Importance float64 `json:"importance"`
+}
+
+func (e *Executor) storeMemory(ctx context.Context, argsJSON string) string {
+	var args memoryStoreArgs
+	if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+		return errorResult("invalid memory_store arguments: %v", err)
+	}
+	if args.Content == "" {
+		return errorResult("memory_store requires non-empty content")
+	}
+
+	memType := memory.MemoryType(args.Type)
+	if memType == "" {
+		memType = memory.MemoryTypeSemantic
+	}
+	importance := args.Importance
+	if importance <= 0 {
+		importance = 0.5
+	}
+
+	mem := &memory.Memory{
+		Type:       memType,
+		Content:    args.Content,
+		UserID:     e.userID,
+		ProjectID:  e.projectID,
+		Importance: importance,
+		Metadata:   map[string]interface{}{"source": "tool_call"},
+	}
+	if err := e.store.Store(ctx, mem); err != nil {
+		return errorResult("memory_store failed: %v", err)
+	}
+	return marshalResult(toolResult{Result: map[string]string{"id": mem.ID}})
+}
+
+type memoryForgetArgs struct {
+	ID string `json:"id"`
+}
+
+func (e *Executor) forget(ctx context.Context, argsJSON string) string {
+	if e.readOnly {
+		return errorResult("memory_forget is disabled for this caller (read-only memory access)")
+	}
+	var args memoryForgetArgs
+	if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+		return errorResult("invalid memory_forget arguments: %v", err)
+	}
+	if args.ID == "" {
+		return errorResult("memory_forget requires an id")
+	}
+
+	mem, err := e.store.Get(ctx, args.ID)
+	if err != nil {
+		return errorResult("memory_forget failed to load %s: %v", args.ID, err)
+	}
+	if !e.owns(mem) {
+		return errorResult("memory_forget failed: %s does not belong to this caller", args.ID)
+	}
+
+	if err := e.store.Forget(ctx, args.ID); err != nil {
+		return errorResult("memory_forget failed: %v", err)
+	}
+	return marshalResult(toolResult{Result: map[string]string{"id": args.ID, "status": "forgotten"}})
+}
+
+// owns reports whether mem belongs to e's scope, so forget/update can reject
+// a tool call that names another tenant's memory ID - unlike search/
+// storeMemory, which never accept an ID and so can't be pointed at another
+// tenant's data in the first place (see the package doc comment).
+func (e *Executor) owns(mem *memory.Memory) bool {
+	return mem.UserID == e.userID && mem.ProjectID == e.projectID
+}
+
+type memoryUpdateArgs struct {
+	ID         string   `json:"id"`
+	Content    *string  `json:"content"`
+	Importance *float64 `json:"importance"`
+}
+
+func (e *Executor) update(ctx context.Context, argsJSON string) string {
+	if e.readOnly {
+		return errorResult("memory_update is disabled for this caller (read-only memory access)")
+	}
+	var args memoryUpdateArgs
+	if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+		return errorResult("invalid memory_update arguments: %v", err)
+	}
+	if args.ID == "" {
+		return errorResult("memory_update requires an id")
+	}
+
+	mem, err := e.store.Get(ctx, args.ID)
+	if err != nil {
+		return errorResult("memory_update failed to load %s: %v", args.ID, err)
+	}
+	if !e.owns(mem) {
+		return errorResult("memory_update failed: %s does not belong to this caller", args.ID)
+	}
+	if args.Content != nil {
+		mem.Content = *args.Content
+	}
+	if args.Importance != nil {
+		mem.Importance = *args.Importance
+	}
+	if err := e.store.Update(ctx, args.ID, mem); err != nil {
+		return errorResult("memory_update failed: %v", err)
+	}
+	return marshalResult(toolResult{Result: map[string]string{"id": args.ID, "status": "updated"}})
+}
@@ -0,0 +1,216 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/vllm-project/semantic-router/src/semantic-router/pkg/memory"
+)
+
+func TestExecutor_StoreThenSearch(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryStore()
+	exec := NewExecutor(store, "user_1", "", false)
+
+	storeResult, err := exec.Execute(ctx, ToolMemoryStore, `{"content": "likes hiking in Yosemite"}`)
+	if err != nil {
+		t.Fatalf("Execute(memory_store) failed: %v", err)
+	}
+	var stored toolResult
+	if err := json.Unmarshal([]byte(storeResult), &stored); err != nil {
+		t.Fatalf("failed to parse memory_store result: %v", err)
+	}
+	if stored.Error != "" {
+		t.Fatalf("memory_store returned an error: %s", stored.Error)
+	}
+
+	searchResult, err := exec.Execute(ctx, ToolMemorySearch, `{"query": "hiking"}`)
+	if err != nil {
+		t.Fatalf("Execute(memory_search) failed: %v", err)
+	}
+	var found toolResult
+	if err := json.Unmarshal([]byte(searchResult), &found); err != nil {
+		t.Fatalf("failed to parse memory_search result: %v", err)
+	}
+	if found.Error != "" {
+		t.Fatalf("memory_search returned an error: %s", found.Error)
+	}
+
+	items, ok := found.Result.([]interface{})
+	if !ok || len(items) != 1 {
+		t.Fatalf("expected 1 search result, got %+v", found.Result)
+	}
+}
+
+func TestExecutor_SearchIsScopedToUser(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryStore()
+
+	if _, err := NewExecutor(store, "user_a", "", false).Execute(ctx, ToolMemoryStore, `{"content": "user a's secret"}`); err != nil {
+		t.Fatalf("Execute(memory_store) failed: %v", err)
+	}
+
+	searchResult, err := NewExecutor(store, "user_b", "", false).Execute(ctx, ToolMemorySearch, `{"query": "secret"}`)
+	if err != nil {
+		t.Fatalf("Execute(memory_search) failed: %v", err)
+	}
+	var found toolResult
+	if err := json.Unmarshal([]byte(searchResult), &found); err != nil {
+		t.Fatalf("failed to parse memory_search result: %v", err)
+	}
+	if items, ok := found.Result.([]interface{}); ok && len(items) > 0 {
+		t.Fatalf("user_b's search should not see user_a's memory, got %+v", items)
+	}
+}
+
+func TestExecutor_ForgetRejectsAnotherTenantsMemory(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryStore()
+
+	storeResult, _ := NewExecutor(store, "user_a", "", false).Execute(ctx, ToolMemoryStore, `{"content": "user a's secret"}`)
+	var stored toolResult
+	_ = json.Unmarshal([]byte(storeResult), &stored)
+	id := stored.Result.(map[string]interface{})["id"].(string)
+
+	forgetResult, err := NewExecutor(store, "user_b", "", false).Execute(ctx, ToolMemoryForget, `{"id": "`+id+`"}`)
+	if err != nil {
+		t.Fatalf("Execute(memory_forget) failed: %v", err)
+	}
+	var result toolResult
+	if err := json.Unmarshal([]byte(forgetResult), &result); err != nil {
+		t.Fatalf("failed to parse memory_forget result: %v", err)
+	}
+	if result.Error == "" {
+		t.Fatal("expected memory_forget to reject a memory that belongs to another tenant")
+	}
+
+	if _, err := store.Get(ctx, id); err != nil {
+		t.Fatalf("user_a's memory should still exist after the rejected forget, Get failed: %v", err)
+	}
+}
+
+func TestExecutor_UpdateRejectsAnotherTenantsMemory(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryStore()
+
+	storeResult, _ := NewExecutor(store, "user_a", "", false).Execute(ctx, ToolMemoryStore, `{"content": "original content"}`)
+	var stored toolResult
+	_ = json.Unmarshal([]byte(storeResult), &stored)
+	id := stored.Result.(map[string]interface{})["id"].(string)
+
+	updateResult, err := NewExecutor(store, "user_b", "", false).Execute(ctx, ToolMemoryUpdate, `{"id": "`+id+`", "content": "tampered content"}`)
+	if err != nil {
+		t.Fatalf("Execute(memory_update) failed: %v", err)
+	}
+	var result toolResult
+	if err := json.Unmarshal([]byte(updateResult), &result); err != nil {
+		t.Fatalf("failed to parse memory_update result: %v", err)
+	}
+	if result.Error == "" {
+		t.Fatal("expected memory_update to reject a memory that belongs to another tenant")
+	}
+
+	mem, err := store.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if mem.Content != "original content" {
+		t.Errorf("content should be unchanged after the rejected update, got %q", mem.Content)
+	}
+}
+
+func TestExecutor_ReadOnlyRefusesWrites(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryStore()
+	exec := NewExecutor(store, "user_1", "", true)
+
+	forgetResult, err := exec.Execute(ctx, ToolMemoryForget, `{"id": "mem_does_not_matter"}`)
+	if err != nil {
+		t.Fatalf("Execute(memory_forget) failed: %v", err)
+	}
+	var result toolResult
+	if err := json.Unmarshal([]byte(forgetResult), &result); err != nil {
+		t.Fatalf("failed to parse memory_forget result: %v", err)
+	}
+	if result.Error == "" {
+		t.Fatal("expected memory_forget to be refused for a read-only executor")
+	}
+
+	updateResult, err := exec.Execute(ctx, ToolMemoryUpdate, `{"id": "mem_does_not_matter"}`)
+	if err != nil {
+		t.Fatalf("Execute(memory_update) failed: %v", err)
+	}
+	if err := json.Unmarshal([]byte(updateResult), &result); err != nil {
+		t.Fatalf("failed to parse memory_update result: %v", err)
+	}
+	if result.Error == "" {
+		t.Fatal("expected memory_update to be refused for a read-only executor")
+	}
+}
+
+func TestExecutor_UpdateAppliesPartialChanges(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewInMemoryStore()
+	exec := NewExecutor(store, "user_1", "", false)
+
+	storeResult, _ := exec.Execute(ctx, ToolMemoryStore, `{"content": "original content", "importance": 0.3}`)
+	var stored toolResult
+	_ = json.Unmarshal([]byte(storeResult), &stored)
+	id := stored.Result.(map[string]interface{})["id"].(string)
+
+	updateResult, err := exec.Execute(ctx, ToolMemoryUpdate, `{"id": "`+id+`", "content": "corrected content"}`)
+	if err != nil {
+		t.Fatalf("Execute(memory_update) failed: %v", err)
+	}
+	var updated toolResult
+	if err := json.Unmarshal([]byte(updateResult), &updated); err != nil {
+		t.Fatalf("failed to parse memory_update result: %v", err)
+	}
+	if updated.Error != "" {
+		t.Fatalf("memory_update returned an error: %s", updated.Error)
+	}
+
+	mem, err := store.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if mem.Content != "corrected content" {
+		t.Errorf("got Content %q, want %q", mem.Content, "corrected content")
+	}
+	if mem.Importance != 0.3 {
+		t.Errorf("importance should be unchanged at 0.3, got %v", mem.Importance)
+	}
+}
+
+func TestExecutor_UnknownToolReturnsError(t *testing.T) {
+	store := memory.NewInMemoryStore()
+	exec := NewExecutor(store, "user_1", "", false)
+
+	if _, err := exec.Execute(context.Background(), "not_a_memory_tool", "{}"); err == nil {
+		t.Fatal("expected an error for a tool name outside this package's namespace")
+	}
+}
+
+func TestIsMemoryTool(t *testing.T) {
+	for _, name := range []string{ToolMemorySearch, ToolMemoryStore, ToolMemoryForget, ToolMemoryUpdate} {
+		if !IsMemoryTool(name) {
+			t.Errorf("IsMemoryTool(%q) = false, want true", name)
+		}
+	}
+	if IsMemoryTool("get_weather") {
+		t.Error("IsMemoryTool(\"get_weather\") = true, want false")
+	}
+}
+
+func TestSpecs_WritesGatedByIncludeWrites(t *testing.T) {
+	readOnly := Specs(false)
+	if len(readOnly) != 2 {
+		t.Fatalf("Specs(false) returned %d specs, want 2", len(readOnly))
+	}
+
+	withWrites := Specs(true)
+	if len(withWrites) != 4 {
+		t.Fatalf("Specs(true) returned %d specs, want 4", len(withWrites))
+	}
+}
@@ -0,0 +1,169 @@
+// Package tools exposes the memory store as a namespace of OpenAI-style
+// function/tool specs an LLM can call directly - memory_search,
+// memory_store, memory_forget, memory_update - instead of relying solely
+// on MemoryFilter's opaque auto-retrieve/auto-store path. See Executor for
+// the side that actually runs a call against a memory.Store.
+package tools
+
+// Tool name constants. A caller (MemoryFilter) uses these to recognize
+// which assistant tool_calls belong to this namespace versus the caller's
+// own tools.
+const (
+	ToolMemorySearch = "memory_search"
+	ToolMemoryStore  = "memory_store"
+	ToolMemoryForget = "memory_forget"
+	ToolMemoryUpdate = "memory_update"
+)
+
+// FunctionSpec is the "function" object inside a Chat Completions tool
+// spec: {"type": "function", "function": {...}}.
+type FunctionSpec struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+// ToolSpec is one entry of a Chat Completions request's "tools" array. It
+// mirrors the wire JSON shape directly (the same wire-format-over-SDK-types
+// choice pkg/extproc's wireToolCall makes) rather than a vendored SDK type,
+// since all a spec needs to do is marshal into bytes a Chat Completions
+// client can parse.
+type ToolSpec struct {
+	Type     string       `json:"type"`
+	Function FunctionSpec `json:"function"`
+}
+
+// IsMemoryTool reports whether name belongs to this package's namespace, so
+// a caller fielding a mixed tool_calls array (the model's own tools plus
+// these) knows which ones to route to an Executor.
+func IsMemoryTool(name string) bool {
+	switch name {
+	case ToolMemorySearch, ToolMemoryStore, ToolMemoryForget, ToolMemoryUpdate:
+		return true
+	default:
+		return false
+	}
+}
+
+// Specs returns the tool specs for the full memory namespace: memory_search
+// and memory_store always, memory_forget/memory_update only when
+// includeWrites is true. A caller enforcing a read-only policy for an
+// agent/caller advertises a smaller toolset rather than advertising
+// memory_forget/memory_update and then refusing the call at execution time -
+// the model shouldn't be offered a button it can't press.
+func Specs(includeWrites bool) []ToolSpec {
+	specs := []ToolSpec{memorySearchSpec(), memoryStoreSpec()}
+	if includeWrites {
+		specs = append(specs, memoryForgetSpec(), memoryUpdateSpec())
+	}
+	return specs
+}
+
+func memorySearchSpec() ToolSpec {
+	return ToolSpec{
+		Type: "function",
+		Function: FunctionSpec{
+			Name:        ToolMemorySearch,
+			Description: "Search the user's stored memories for information relevant to a query. Returns the matching memories with their IDs, types, content, and relevance scores.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "The text to search for in the user's memories.",
+					},
+					"types": map[string]interface{}{
+						"type":        "array",
+						"description": "Restrict the search to these memory types. Omit to search all types.",
+						"items": map[string]interface{}{
+							"type": "string",
+							"enum": []string{"episodic", "semantic", "procedural", "working"},
+						},
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of memories to return. Defaults to 5.",
+					},
+				},
+				"required": []string{"query"},
+			},
+		},
+	}
+}
+
+func memoryStoreSpec() ToolSpec {
+	return ToolSpec{
+		Type: "function",
+		Function: FunctionSpec{
+			Name:        ToolMemoryStore,
+			Description: "Store a new memory about the user for future recall. Use this to remember facts, preferences, or procedures the user explicitly shares or that are clearly worth retaining.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"content": map[string]interface{}{
+						"type":        "string",
+						"description": "The information to remember, written as a standalone statement.",
+					},
+					"type": map[string]interface{}{
+						"type":        "string",
+						"description": "The kind of memory this is. Defaults to \"semantic\".",
+						"enum":        []string{"episodic", "semantic", "procedural", "working"},
+					},
+					"importance": map[string]interface{}{
+						"type":        "number",
+						"description": "How important this memory is, from 0 (trivial) to 1 (critical). Defaults to 0.5.",
+					},
+				},
+				"required": []string{"content"},
+			},
+		},
+	}
+}
+
+func memoryForgetSpec() ToolSpec {
+	return ToolSpec{
+		Type: "function",
+		Function: FunctionSpec{
+			Name:        ToolMemoryForget,
+			Description: "Permanently delete a stored memory by ID, e.g. because the user asked to forget it or it's been superseded. This cannot be undone.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "The ID of the memory to delete, as returned by memory_search.",
+					},
+				},
+				"required": []string{"id"},
+			},
+		},
+	}
+}
+
+func memoryUpdateSpec() ToolSpec {
+	return ToolSpec{
+		Type: "function",
+		Function: FunctionSpec{
+			Name:        ToolMemoryUpdate,
+			Description: "Update an existing memory's content and/or importance, e.g. to correct or refine a previously stored fact without creating a duplicate.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "The ID of the memory to update, as returned by memory_search.",
+					},
+					"content": map[string]interface{}{
+						"type":        "string",
+						"description": "The memory's new content. Omit to leave content unchanged.",
+					},
+					"importance": map[string]interface{}{
+						"type":        "number",
+						"description": "The memory's new importance, from 0 to 1. Omit to leave importance unchanged.",
+					},
+				},
+				"required": []string{"id"},
+			},
+		},
+	}
+}
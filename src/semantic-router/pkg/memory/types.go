@@ -3,6 +3,7 @@
 package memory
 
 import (
+	"context"
 	"time"
 )
 
@@ -22,8 +23,11 @@ const (
 	// Example: "To deploy: run 'npm build' then 'docker push'"
 	MemoryTypeProcedural MemoryType = "procedural"
 
-	// MemoryTypeWorking represents temporary session context
-	// Only type that uses session_id, not persisted across sessions
+	// MemoryTypeWorking represents temporary session context: tool call
+	// scratchpads, chain-of-thought summaries, session tokens. Meant to be
+	// stored with a TTL (see Memory.TTL) so it expires with the session
+	// instead of polluting long-term semantic/episodic memory; see
+	// StoreExpirer.ExpireDue.
 	MemoryTypeWorking MemoryType = "working"
 )
 
@@ -40,6 +44,13 @@ type Memory struct {
 	Content   string    `json:"content"` // Human-readable content
 	Embedding []float32 `json:"-"`       // Vector embedding (not serialized to JSON)
 
+	// OccurredAt and Location are set on MemoryTypeEpisodic memories to
+	// anchor them to a real-world time/place ("User visited Kyoto in April
+	// 2024"), as opposed to CreatedAt, which only records when the memory
+	// was written to the store. Left zero/empty for other memory types.
+	OccurredAt time.Time `json:"occurred_at,omitempty"`
+	Location   string    `json:"location,omitempty"`
+
 	// Scoping - determines who can access this memory
 	UserID    string `json:"user_id"`              // Required: memory owner
 	ProjectID string `json:"project_id,omitempty"` // Optional: project scope
@@ -50,11 +61,52 @@ type Memory struct {
 	Confidence float64        `json:"confidence"`       // Extraction confidence (0-1)
 
 	// Access tracking for importance scoring
-	Importance  float64   `json:"importance"`   // Computed importance score
-	AccessCount int       `json:"access_count"` // Times retrieved
+	Importance  float64   `json:"importance"`          // Computed importance score
+	AccessCount int       `json:"access_count"`        // Times retrieved
+	HitCount    int       `json:"hit_count,omitempty"` // Times reinforced by a merged duplicate fact
 	CreatedAt   time.Time `json:"created_at"`
 	AccessedAt  time.Time `json:"accessed_at"`
-	TTL         time.Time `json:"ttl,omitempty"` // Optional expiration
+	UpdatedAt   time.Time `json:"updated_at"`    // Last Store/Update write; drives StoreSync.UpdatedSince
+	TTL         time.Time `json:"ttl,omitempty"` // Absolute expiration time; zero means it never expires. See StoreExpirer.
+
+	// BranchID identifies which conversation branch this memory belongs to,
+	// e.g. "" (mainline) vs. a branch ID returned by a
+	// MemoryFilter.ForkBranch call made when the caller edited a prior turn
+	// and re-sent it. Retrieve scopes to exactly one branch at a time (see
+	// RetrieveOptions.BranchID, default "" i.e. mainline only) - a
+	// ForkBranch copy never surfaces in a mainline Retrieve, independent of
+	// SupersededBy. NOTE: this filtering is implemented only in
+	// InMemoryStore today; the other Store backends (MilvusStore,
+	// PostgresStore, MongoStore, RedisVectorStore, SQLiteStore,
+	// WeaviateStore) ignore BranchID entirely and treat every memory as
+	// mainline.
+	BranchID string `json:"branch_id,omitempty"`
+
+	// ParentMemoryID is the ID of the memory this one was copied or derived
+	// from - the mainline memory a ForkBranch copy was made from, or the
+	// memory this one superseded (see SupersededBy for the reverse
+	// pointer). Empty for memories with no such lineage.
+	ParentMemoryID string `json:"parent_memory_id,omitempty"`
+
+	// SupersededBy is the ID of the memory that replaced this one - set
+	// when a later turn edits-and-resends a conversation turn this memory
+	// was stored from, so the two no longer both belong on the mainline
+	// (see StoreFromResponse). A non-empty SupersededBy excludes this
+	// memory from Retrieve by default; set RetrieveOptions.IncludeAllBranches
+	// to see it anyway (debugging/audit).
+	SupersededBy string `json:"superseded_by,omitempty"`
+
+	// Version is an optimistic-concurrency counter: InMemoryStore sets it to
+	// 1 when a memory is first stored and bumps it by one on every
+	// successful Update. A non-zero Version passed into Update is checked
+	// against the stored memory's current Version, failing with
+	// CodeConflict on a mismatch instead of silently overwriting a
+	// concurrent write; a zero Version (the default for a *Memory a caller
+	// builds from scratch rather than round-tripping through Get) skips the
+	// check, preserving Update's original last-writer-wins behavior for
+	// callers that don't care. See InMemoryStore.CompareAndSwap for a
+	// read-modify-write helper built on this.
+	Version uint64 `json:"version,omitempty"`
 }
 
 // MemoryScope defines filtering criteria for memory operations.
@@ -63,20 +115,101 @@ type MemoryScope struct {
 	UserID    string     // Required
 	ProjectID string     // Optional
 	Type      MemoryType // Optional: filter by type
+
+	// Before/After bound a memory's CreatedAt for ForgetByScope, e.g. a
+	// retention policy that forgets episodic memories older than 90 days
+	// ("Before: time.Now().Add(-90*24*time.Hour)"). Nil means unbounded on
+	// that side; both may be set to scope to a window.
+	Before *time.Time
+	After  *time.Time
 }
 
+// ConsistencyLevel overrides a backend's default read consistency/staleness
+// bound for a single call. Only backends that support tunable consistency
+// (currently MilvusStore) honor it; others ignore it.
+type ConsistencyLevel string
+
+const (
+	ConsistencyDefault    ConsistencyLevel = ""           // use the backend's configured default
+	ConsistencyStrong     ConsistencyLevel = "strong"     // always read the latest write
+	ConsistencyBounded    ConsistencyLevel = "bounded"    // bounded staleness (fastest safe default)
+	ConsistencySession    ConsistencyLevel = "session"    // read-your-writes within a session
+	ConsistencyEventually ConsistencyLevel = "eventually" // lowest latency, no staleness guarantee
+)
+
 // RetrieveOptions configures memory retrieval.
 type RetrieveOptions struct {
-	Query     string       // Text query to embed and search
-	UserID    string       // Required: scope to user
-	ProjectID string       // Optional: scope to project
-	Types     []MemoryType // Filter by memory types (empty = all)
-	Limit     int          // Max results (default: 5)
-	Threshold float32      // Min similarity score (default: 0.75)
+	Query       string           // Text query to embed and search
+	UserID      string           // Required: scope to user
+	ProjectID   string           // Optional: scope to project
+	Types       []MemoryType     // Filter by memory types (empty = all)
+	Limit       int              // Max results (default: 5)
+	Threshold   float32          // Min similarity score (default: 0.75)
+	Consistency ConsistencyLevel // Per-call override of the store's consistency level
+
+	// Diversity enables Maximal Marginal Relevance re-ranking to cut down on
+	// near-duplicate results (0 = off, 1 = maximize diversity over relevance).
+	Diversity float32
+
+	// TimeWindow, if set, restricts results to memories whose CreatedAt falls
+	// within [TimeWindow[0], TimeWindow[1]] inclusive - a time-anchored
+	// predicate for queries like "what did we discuss about Hawaii in March".
+	TimeWindow *[2]time.Time
+
+	// TimeAnchor is the reference time RecencyWeight's decay term measures
+	// age against. Nil (with RecencyWeight > 0) defaults to time.Now(), i.e.
+	// "how recent is this relative to right now"; set it explicitly to rank
+	// as of some other point in time.
+	TimeAnchor *time.Time
+
+	// RecencyWeight blends raw similarity with an exponential time decay
+	// anchored at TimeAnchor when ranking results: 0 (default) ranks purely
+	// on similarity; 1 ranks purely on recency. See blendRecency.
+	RecencyWeight float64
+
+	// MaxLatency bounds how long Retrieve may take: if the caller's ctx has
+	// no deadline of its own, Retrieve derives a child context with this
+	// timeout via context.WithTimeout, so a slow Scorer/Embedder call (e.g.
+	// hybridScorer's query embedding) can't hang the request indefinitely.
+	// <= 0 leaves ctx as given. Ignored if ctx already carries a deadline -
+	// that deadline wins either way.
+	MaxLatency time.Duration
+
+	// IncludeAllBranches disables the default exclusion of superseded
+	// memories (see Memory.SupersededBy) from results. Meant for
+	// debugging/audit call sites that need to see a conversation branch's
+	// full history, not normal retrieval - a superseded memory almost
+	// always duplicates content a newer, non-superseded memory also
+	// covers.
+	IncludeAllBranches bool
+
+	// BranchID scopes Retrieve to one conversation branch (see
+	// Memory.BranchID, MemoryFilter.ForkBranch). The default, "", matches
+	// only mainline memories - a ForkBranch copy's non-empty BranchID
+	// never surfaces in a mainline Retrieve, so exploring a branch never
+	// leaks near-duplicates back into it. Set this to a branch ID to
+	// retrieve that branch's own copies instead.
+	BranchID string
+}
+
+// withDeadline derives a child context bounded by o.MaxLatency for Retrieve,
+// unless ctx already has a deadline of its own (in which case that deadline
+// takes precedence) or o.MaxLatency is unset. The returned cancel func must
+// always be called to release the context's resources, even when it's a
+// no-op.
+func (o RetrieveOptions) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.MaxLatency <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, o.MaxLatency)
 }
 
 // RetrieveResult represents a retrieved memory with its relevance score.
 type RetrieveResult struct {
 	Memory    *Memory `json:"memory"`
-	Relevance float64 `json:"relevance"` // Similarity score (0-1)
+	Relevance float64 `json:"relevance"` // Final ranking score (MMR-adjusted when Diversity > 0)
+	RawScore  float64 `json:"raw_score"` // Original similarity score, unaffected by MMR/decay re-ranking
 }
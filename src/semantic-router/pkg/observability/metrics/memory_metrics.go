@@ -0,0 +1,119 @@
+// Package metrics provides minimal, dependency-free Prometheus-style
+// histogram instrumentation for the router, alongside pkg/observability/logging
+// for textual logs. It exposes exactly enough to back
+// memory_stage_duration_seconds (see RecordMemoryStageDuration) - translating
+// a Snapshot into an actual Prometheus text-exposition /metrics response is
+// left to the deployment's existing metrics endpoint/exporter.
+package metrics
+
+import "sync"
+
+// MemoryStageDurationMetric is the metric name memory subsystem stage
+// latency is recorded under - embed, retrieve, rerank, inject, store (see
+// pkg/extproc's MemoryFilterContext.Timings, which populates these phases).
+const MemoryStageDurationMetric = "memory_stage_duration_seconds"
+
+// memoryStageDurationBucketBoundsSeconds are the histogram bucket upper
+// bounds (in seconds) memory_stage_duration_seconds observations are
+// counted against, chosen to cover everything from a cache-hit lookup
+// (sub-millisecond) to a slow vector search (multi-second) - the kind of
+// regression this metric exists to catch.
+var memoryStageDurationBucketBoundsSeconds = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2}
+
+// histogram is a Prometheus-style cumulative histogram: bucket counts are
+// cumulative (each bucket also counts every observation in a smaller
+// bucket), matching the wire format Prometheus itself exposes.
+type histogram struct {
+	mu      sync.Mutex
+	buckets []float64 // cumulative counts, parallel to memoryStageDurationBucketBoundsSeconds
+	count   uint64
+	sum     float64
+}
+
+func newHistogram() *histogram {
+	return &histogram{buckets: make([]float64, len(memoryStageDurationBucketBoundsSeconds))}
+}
+
+func (h *histogram) observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.count++
+	h.sum += v
+	for i, bound := range memoryStageDurationBucketBoundsSeconds {
+		if v <= bound {
+			h.buckets[i]++
+		}
+	}
+}
+
+func (h *histogram) snapshot(stage string) Snapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	counts := make([]float64, len(h.buckets))
+	copy(counts, h.buckets)
+	return Snapshot{
+		Stage:            stage,
+		Count:            h.count,
+		SumSeconds:       h.sum,
+		BucketBounds:     memoryStageDurationBucketBoundsSeconds,
+		CumulativeCounts: counts,
+	}
+}
+
+// Snapshot is a read-only view of one stage's accumulated
+// memory_stage_duration_seconds histogram, suitable for an operator's
+// existing metrics exporter to translate into whatever wire format it
+// emits (Prometheus text exposition, OpenMetrics, ...).
+type Snapshot struct {
+	Stage            string
+	Count            uint64
+	SumSeconds       float64
+	BucketBounds     []float64
+	CumulativeCounts []float64
+}
+
+// memoryStageDurations holds one histogram per stage label, created lazily
+// on first observation.
+var (
+	memoryStageDurationsMu sync.Mutex
+	memoryStageDurations   = make(map[string]*histogram)
+)
+
+// RecordMemoryStageDuration observes durationMs (milliseconds) against the
+// memory_stage_duration_seconds histogram for stage. Non-positive durations
+// (a stage that didn't run, or isn't measurable at the caller's layer) are
+// skipped rather than recorded as a spurious zero-time sample.
+func RecordMemoryStageDuration(stage string, durationMs int64) {
+	if durationMs <= 0 {
+		return
+	}
+	memoryStageDurationsMu.Lock()
+	h, ok := memoryStageDurations[stage]
+	if !ok {
+		h = newHistogram()
+		memoryStageDurations[stage] = h
+	}
+	memoryStageDurationsMu.Unlock()
+	h.observe(float64(durationMs) / 1000)
+}
+
+// MemoryStageDurationSnapshot returns a Snapshot of stage's accumulated
+// histogram, or ok=false if no observation has been recorded for it yet.
+func MemoryStageDurationSnapshot(stage string) (snap Snapshot, ok bool) {
+	memoryStageDurationsMu.Lock()
+	h, found := memoryStageDurations[stage]
+	memoryStageDurationsMu.Unlock()
+	if !found {
+		return Snapshot{}, false
+	}
+	return h.snapshot(stage), true
+}
+
+// resetMemoryStageDurationsForTest clears all recorded histograms. Test-only
+// (unexported), so each test starts from a clean slate regardless of
+// execution order.
+func resetMemoryStageDurationsForTest() {
+	memoryStageDurationsMu.Lock()
+	defer memoryStageDurationsMu.Unlock()
+	memoryStageDurations = make(map[string]*histogram)
+}
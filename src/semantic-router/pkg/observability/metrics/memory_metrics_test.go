@@ -0,0 +1,55 @@
+package metrics
+
+import "testing"
+
+func TestRecordMemoryStageDuration_AccumulatesAndBuckets(t *testing.T) {
+	resetMemoryStageDurationsForTest()
+
+	RecordMemoryStageDuration("retrieve", 5)   // 0.005s
+	RecordMemoryStageDuration("retrieve", 120) // 0.12s
+
+	snap, ok := MemoryStageDurationSnapshot("retrieve")
+	if !ok {
+		t.Fatal("expected a snapshot for stage \"retrieve\"")
+	}
+	if snap.Count != 2 {
+		t.Errorf("Count = %d, want 2", snap.Count)
+	}
+	wantSum := 0.005 + 0.12
+	if diff := snap.SumSeconds - wantSum; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("SumSeconds = %v, want %v", snap.SumSeconds, wantSum)
+	}
+
+	// 0.005s falls in every bucket >= 0.005; 0.12s falls in every bucket >= 0.25.
+	for i, bound := range snap.BucketBounds {
+		want := 0.0
+		if bound >= 0.005 {
+			want++
+		}
+		if bound >= 0.25 {
+			want++
+		}
+		if snap.CumulativeCounts[i] != want {
+			t.Errorf("bucket %v: got %v, want %v", bound, snap.CumulativeCounts[i], want)
+		}
+	}
+}
+
+func TestRecordMemoryStageDuration_SkipsNonPositiveDurations(t *testing.T) {
+	resetMemoryStageDurationsForTest()
+
+	RecordMemoryStageDuration("inject", 0)
+	RecordMemoryStageDuration("inject", -5)
+
+	if _, ok := MemoryStageDurationSnapshot("inject"); ok {
+		t.Error("expected no snapshot when only non-positive durations were recorded")
+	}
+}
+
+func TestMemoryStageDurationSnapshot_UnknownStage(t *testing.T) {
+	resetMemoryStageDurationsForTest()
+
+	if _, ok := MemoryStageDurationSnapshot("does-not-exist"); ok {
+		t.Error("expected ok=false for a stage with no recorded observations")
+	}
+}
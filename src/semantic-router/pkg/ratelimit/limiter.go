@@ -0,0 +1,167 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CostFunc computes how many tokens a request should be charged against
+// its bucket. The default, DefaultCostFunc, charges a flat 1 per request;
+// callers that want usage-proportional limiting (cost = prompt tokens +
+// estimated completion tokens) supply one that reads ctx.TokenCount.
+type CostFunc func(ctx Context) float64
+
+// DefaultCostFunc charges exactly one token per request, independent of
+// size - the simplest admission policy, equivalent to a plain
+// requests-per-second limiter.
+func DefaultCostFunc(Context) float64 { return 1 }
+
+// TokenCountCostFunc charges ctx.TokenCount tokens per request (falling
+// back to 1 if the caller didn't populate a token estimate), so a bucket
+// sized in "tokens/sec" throttles by actual usage rather than request
+// count.
+func TokenCountCostFunc(ctx Context) float64 {
+	if ctx.TokenCount <= 0 {
+		return 1
+	}
+	return float64(ctx.TokenCount)
+}
+
+// ProviderQuota configures the rate and burst for one provider's bucket.
+// Rate is in tokens/sec (or requests/sec under DefaultCostFunc); Burst is
+// the bucket's capacity, i.e. the largest instantaneous spike it absorbs.
+type ProviderQuota struct {
+	Rate  float64
+	Burst float64
+}
+
+// ModelProviderFunc maps a model name to the provider bucket it should be
+// charged against (e.g. "gpt-4o" -> "openai"). Context has no Provider
+// field of its own - the limiter owns this mapping, the same way it owns
+// quotas, so config changes to model routing don't require touching
+// every RateLimiter caller.
+type ModelProviderFunc func(model string) string
+
+// TokenBucketLimiter is the first-class RateLimiter implementation: one
+// token bucket per (user_id, provider, model), backed by a pluggable
+// Store so the same logic works for a single instance (InMemoryStore) or
+// a fleet sharing state (RedisStore).
+type TokenBucketLimiter struct {
+	store     Store
+	quotas    map[string]ProviderQuota
+	defQuot   ProviderQuota
+	costFn    CostFunc
+	providerF ModelProviderFunc
+
+	// ShadowMode, when true, never blocks a request: Check still runs the
+	// full bucket logic and returns the decision it would have made, but
+	// a caller wired for shadow mode logs the would-block decisions
+	// instead of enforcing them. This lets a new quota config be rolled
+	// out safely before it starts rejecting traffic.
+	ShadowMode bool
+}
+
+// NewTokenBucketLimiter builds a limiter against store, with defaultQuota
+// applied to any provider not present in perProvider. A nil costFn
+// defaults to DefaultCostFunc; a nil providerFn treats the model name
+// itself as the provider bucket key.
+func NewTokenBucketLimiter(store Store, defaultQuota ProviderQuota, perProvider map[string]ProviderQuota, costFn CostFunc, providerFn ModelProviderFunc) *TokenBucketLimiter {
+	if costFn == nil {
+		costFn = DefaultCostFunc
+	}
+	if providerFn == nil {
+		providerFn = func(model string) string { return model }
+	}
+	quotas := make(map[string]ProviderQuota, len(perProvider))
+	for provider, quota := range perProvider {
+		quotas[provider] = quota
+	}
+	return &TokenBucketLimiter{store: store, quotas: quotas, defQuot: defaultQuota, costFn: costFn, providerF: providerFn}
+}
+
+// quotaFor returns the configured ProviderQuota for provider, falling
+// back to the limiter's default.
+func (l *TokenBucketLimiter) quotaFor(provider string) ProviderQuota {
+	if quota, ok := l.quotas[provider]; ok {
+		return quota
+	}
+	return l.defQuot
+}
+
+// bucketKey identifies one (user, provider, model) bucket.
+func bucketKey(userID, provider, model string) string {
+	return fmt.Sprintf("%s:%s:%s", userID, provider, model)
+}
+
+// Check implements RateLimiter. It derives the provider from ctx.Model
+// via the limiter's ModelProviderFunc, charges l.costFn's cost against
+// that provider's bucket, and returns the resulting Decision. In
+// ShadowMode, a would-block Decision is returned with Allowed forced to
+// true and ShadowBlocked set, so the request proceeds but the caller can
+// still log the decision it would have enforced.
+func (l *TokenBucketLimiter) Check(ctx Context) (*Decision, error) {
+	decision, err := l.evaluate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if l.ShadowMode && !decision.Allowed {
+		shadowed := *decision
+		shadowed.Allowed = true
+		shadowed.ShadowBlocked = true
+		return &shadowed, nil
+	}
+	return decision, nil
+}
+
+// evaluate runs the token-bucket check without applying ShadowMode,
+// returning the true allow/deny verdict.
+func (l *TokenBucketLimiter) evaluate(ctx Context) (*Decision, error) {
+	provider := l.providerF(ctx.Model)
+	quota := l.quotaFor(provider)
+	cost := l.costFn(ctx)
+	key := bucketKey(ctx.UserID, provider, ctx.Model)
+
+	allowed, remaining, resetAt, err := l.store.TryConsume(context.Background(), key, cost, quota.Rate, quota.Burst, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("rate limit check for %s: %w", key, err)
+	}
+
+	decision := &Decision{
+		Allowed:   allowed,
+		Provider:  provider,
+		Limit:     int(quota.Burst),
+		Remaining: int(remaining),
+		ResetAt:   resetAt,
+	}
+	if !allowed {
+		decision.RetryAfter = time.Until(resetAt)
+		if decision.RetryAfter < 0 {
+			decision.RetryAfter = 0
+		}
+	}
+	return decision, nil
+}
+
+// Reconcile adjusts a bucket after the fact, once the actual token usage
+// for a request (from the provider response's usage.total_tokens) is
+// known. If the estimate l.costFn charged at Check time overstated the
+// real cost, the difference is refunded (capped at the provider's burst,
+// via Store.Refund); an understatement is not clawed back; the bucket
+// simply runs a little ahead next cycle. There is currently no caller
+// wired to invoke this automatically - the response-phase pipeline this
+// router speaks to does not yet thread usage data back to the request
+// path - but TokenBucketLimiter implements the Reconciler interface so a
+// future response-phase caller can reach it via a type assertion without
+// this package changing.
+func (l *TokenBucketLimiter) Reconcile(ctx Context, estimatedCost, actualTotalTokens int) error {
+	if actualTotalTokens < 0 {
+		return fmt.Errorf("reconcile: actualTotalTokens must be non-negative, got %d", actualTotalTokens)
+	}
+	if actualTotalTokens >= estimatedCost {
+		return nil
+	}
+	provider := l.providerF(ctx.Model)
+	key := bucketKey(ctx.UserID, provider, ctx.Model)
+	return l.store.Refund(context.Background(), key, float64(estimatedCost-actualTotalTokens), l.quotaFor(provider).Burst)
+}
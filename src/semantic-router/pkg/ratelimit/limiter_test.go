@@ -0,0 +1,247 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucketLimiter_AllowsWithinBurstThenBlocks(t *testing.T) {
+	store := NewInMemoryStore()
+	limiter := NewTokenBucketLimiter(store, ProviderQuota{Rate: 1, Burst: 2}, nil, nil, nil)
+
+	ctx := Context{UserID: "alice", Model: "gpt-4o"}
+
+	d1, err := limiter.Check(ctx)
+	require.NoError(t, err)
+	assert.True(t, d1.Allowed)
+
+	d2, err := limiter.Check(ctx)
+	require.NoError(t, err)
+	assert.True(t, d2.Allowed)
+
+	d3, err := limiter.Check(ctx)
+	require.NoError(t, err)
+	assert.False(t, d3.Allowed)
+	assert.Equal(t, "gpt-4o", d3.Provider)
+	assert.GreaterOrEqual(t, d3.RetryAfter, time.Duration(0))
+}
+
+func TestTokenBucketLimiter_PerProviderQuotaOverride(t *testing.T) {
+	store := NewInMemoryStore()
+	limiter := NewTokenBucketLimiter(store, ProviderQuota{Rate: 1, Burst: 1},
+		map[string]ProviderQuota{"openai": {Rate: 1, Burst: 5}}, nil,
+		func(model string) string { return "openai" })
+
+	ctx := Context{UserID: "bob", Model: "gpt-4o"}
+	for i := 0; i < 5; i++ {
+		d, err := limiter.Check(ctx)
+		require.NoError(t, err)
+		assert.Truef(t, d.Allowed, "request %d should be allowed under the openai override burst", i)
+	}
+	d, err := limiter.Check(ctx)
+	require.NoError(t, err)
+	assert.False(t, d.Allowed)
+}
+
+func TestTokenBucketLimiter_TokenCountCostFunc(t *testing.T) {
+	store := NewInMemoryStore()
+	limiter := NewTokenBucketLimiter(store, ProviderQuota{Rate: 10, Burst: 100}, nil, TokenCountCostFunc, nil)
+
+	d, err := limiter.Check(Context{UserID: "carol", Model: "gpt-4o", TokenCount: 40})
+	require.NoError(t, err)
+	assert.True(t, d.Allowed)
+	assert.Equal(t, 60, d.Remaining)
+}
+
+func TestTokenBucketLimiter_ShadowModeNeverBlocks(t *testing.T) {
+	store := NewInMemoryStore()
+	limiter := NewTokenBucketLimiter(store, ProviderQuota{Rate: 1, Burst: 1}, nil, nil, nil)
+	limiter.ShadowMode = true
+
+	ctx := Context{UserID: "dave", Model: "gpt-4o"}
+	require.NoError(t, requireAllowed(t, limiter, ctx))
+
+	d, err := limiter.Check(ctx)
+	require.NoError(t, err)
+	assert.True(t, d.Allowed, "shadow mode must not enforce the block")
+}
+
+func requireAllowed(t *testing.T, limiter *TokenBucketLimiter, ctx Context) error {
+	t.Helper()
+	d, err := limiter.Check(ctx)
+	if err != nil {
+		return err
+	}
+	assert.True(t, d.Allowed)
+	return nil
+}
+
+func TestTokenBucketLimiter_ReconcileRefundsUnusedEstimate(t *testing.T) {
+	store := NewInMemoryStore()
+	limiter := NewTokenBucketLimiter(store, ProviderQuota{Rate: 1, Burst: 10}, nil, TokenCountCostFunc, nil)
+
+	ctx := Context{UserID: "erin", Model: "gpt-4o", TokenCount: 8}
+	d, err := limiter.Check(ctx)
+	require.NoError(t, err)
+	require.True(t, d.Allowed)
+	require.Equal(t, 2, d.Remaining)
+
+	require.NoError(t, limiter.Reconcile(ctx, 8, 3))
+
+	d2, err := limiter.Check(Context{UserID: "erin", Model: "gpt-4o", TokenCount: 0})
+	require.NoError(t, err)
+	// 2 remaining + 5 refunded (8 estimated - 3 actual) - 1 charged for this
+	// probe (TokenCountCostFunc falls back to 1 when TokenCount is unset) = 6
+	assert.Equal(t, 6, d2.Remaining)
+}
+
+func TestInMemoryStore_RefillOverTime(t *testing.T) {
+	store := NewInMemoryStore()
+	now := time.Now()
+
+	allowed, remaining, _, err := store.TryConsume(context.Background(), "k", 1, 1, 1, now)
+	require.NoError(t, err)
+	require.True(t, allowed)
+	require.Equal(t, float64(0), remaining)
+
+	allowed, _, _, err = store.TryConsume(context.Background(), "k", 1, 1, 1, now)
+	require.NoError(t, err)
+	assert.False(t, allowed, "no time has elapsed, bucket should still be empty")
+
+	allowed, remaining, _, err = store.TryConsume(context.Background(), "k", 1, 1, 1, now.Add(time.Second))
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, float64(0), remaining)
+}
+
+type fakeRedisClient struct {
+	data map[string]map[string]interface{}
+}
+
+func newFakeRedisClient() *fakeRedisClient {
+	return &fakeRedisClient{data: map[string]map[string]interface{}{}}
+}
+
+// Eval is a minimal, non-Lua-executing stand-in: it special-cases the two
+// scripts RedisStore issues so the CAS behavior can be exercised without a
+// real Redis server or Lua interpreter in this test binary.
+func (f *fakeRedisClient) Eval(_ context.Context, script string, keys []string, args []interface{}) (interface{}, error) {
+	key := keys[0]
+	hash, ok := f.data[key]
+	if !ok {
+		hash = map[string]interface{}{}
+		f.data[key] = hash
+	}
+
+	if len(args) == 2 {
+		// Mirrors tokenBucketRefundScript's guard: refund only applies to an
+		// already-initialized bucket (both fields present); otherwise it's a
+		// no-op, the same as InMemoryStore.Refund against an expired/never-
+		// charged key.
+		tokens, hasTokens := hash["tokens"].(float64)
+		_, hasLastMs := hash["last_ms"].(int64)
+		if !hasTokens || !hasLastMs {
+			return int64(0), nil
+		}
+		cost := args[0].(float64)
+		burst := args[1].(float64)
+		tokens += cost
+		if tokens > burst {
+			tokens = burst
+		}
+		hash["tokens"] = tokens
+		return int64(1), nil
+	}
+
+	cost := args[0].(float64)
+	rate := args[1].(float64)
+	burst := args[2].(float64)
+	nowMs := args[3].(int64)
+
+	tokens, hasTokens := hash["tokens"].(float64)
+	lastMs, _ := hash["last_ms"].(int64)
+	if !hasTokens {
+		tokens = burst
+		lastMs = nowMs
+	}
+
+	elapsedSeconds := float64(nowMs-lastMs) / 1000.0
+	if elapsedSeconds < 0 {
+		elapsedSeconds = 0
+	}
+	tokens += elapsedSeconds * rate
+	if tokens > burst {
+		tokens = burst
+	}
+
+	allowed := int64(0)
+	if tokens >= cost {
+		tokens -= cost
+		allowed = 1
+	}
+
+	hash["tokens"] = tokens
+	hash["last_ms"] = nowMs
+
+	_ = script
+	return []interface{}{allowed, tokens, nowMs}, nil
+}
+
+func TestRedisStore_TryConsumeViaFakeClient(t *testing.T) {
+	client := newFakeRedisClient()
+	store := NewRedisStore(client)
+
+	allowed, remaining, _, err := store.TryConsume(context.Background(), "k", 1, 1, 2, time.Now())
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, float64(1), remaining)
+}
+
+func TestInMemoryStore_RefundCapsAtBurst(t *testing.T) {
+	store := NewInMemoryStore()
+	now := time.Now()
+
+	_, _, _, err := store.TryConsume(context.Background(), "k", 1, 0, 5, now)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Refund(context.Background(), "k", 100, 5))
+
+	_, remaining, _, err := store.TryConsume(context.Background(), "k", 0, 0, 5, now)
+	require.NoError(t, err)
+	assert.Equal(t, float64(5), remaining, "a refund must never grow a bucket past its burst capacity")
+}
+
+func TestRedisStore_RefundCapsAtBurst(t *testing.T) {
+	client := newFakeRedisClient()
+	store := NewRedisStore(client)
+
+	_, _, _, err := store.TryConsume(context.Background(), "k", 1, 0, 5, time.Now())
+	require.NoError(t, err)
+
+	require.NoError(t, store.Refund(context.Background(), "k", 100, 5))
+
+	_, remaining, _, err := store.TryConsume(context.Background(), "k", 0, 0, 5, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, float64(5), remaining, "a refund must never grow a bucket past its burst capacity")
+}
+
+func TestRedisStore_RefundAgainstUninitializedKeyIsNoOp(t *testing.T) {
+	client := newFakeRedisClient()
+	store := NewRedisStore(client)
+
+	// No TryConsume has ever touched "k" - this mirrors both a key that
+	// expired via tokenBucketCASScript's PEXPIRE and one that was never
+	// checked in the first place.
+	require.NoError(t, store.Refund(context.Background(), "k", 100, 5))
+
+	// A subsequent TryConsume must see a fresh bucket, not an error from
+	// tokens being set without last_refill_ms.
+	allowed, remaining, _, err := store.TryConsume(context.Background(), "k", 1, 0, 5, time.Now())
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, float64(4), remaining, "refund against an uninitialized key must be a no-op, leaving the bucket to start fresh at burst")
+}
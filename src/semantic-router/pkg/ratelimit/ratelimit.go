@@ -0,0 +1,59 @@
+// Package ratelimit provides request-admission rate limiting for the
+// router, keyed by (user, provider, model), with a pluggable token-bucket
+// backend (in-memory or Redis) behind a small RateLimiter interface.
+package ratelimit
+
+import "time"
+
+// Context is everything a RateLimiter needs to evaluate one request. It's
+// built once per request (see pkg/extproc's buildRateLimitContext) and
+// passed to Check, then stashed on the request context so the response
+// phase can later reconcile estimated vs. actual token cost.
+type Context struct {
+	UserID     string
+	Groups     []string
+	Model      string
+	Headers    map[string]string
+	TokenCount int
+}
+
+// Decision is the outcome of a Check call: whether the request is allowed,
+// which provider bucket it was charged against, and the RateLimit-*
+// headers createRateLimitResponse surfaces to the client.
+type Decision struct {
+	Allowed    bool
+	Provider   string
+	Limit      int
+	Remaining  int
+	ResetAt    time.Time
+	RetryAfter time.Duration
+
+	// ShadowBlocked is true when a RateLimiter running in shadow mode
+	// would have set Allowed to false but forced it to true so the
+	// request proceeds unenforced. Callers should log this case so the
+	// configured quota's real-world impact is visible before it's
+	// switched to enforcing.
+	ShadowBlocked bool
+}
+
+// RateLimiter is the interface pkg/extproc's OpenAIRouter.RateLimiter field
+// is declared against - Check is called once per request, before routing,
+// and must be safe for concurrent use.
+type RateLimiter interface {
+	Check(ctx Context) (*Decision, error)
+}
+
+// Reconciler is an optional capability a RateLimiter may implement to
+// correct a bucket after the fact, once a request's actual cost (e.g. a
+// provider response's usage.total_tokens) is known - see
+// TokenBucketLimiter.Reconcile. Kept separate from RateLimiter, the same
+// way pkg/memory's StoreSync/StoreApply/StoreExpirer are kept separate
+// from Store, since not every RateLimiter tracks per-request cost
+// estimates to reconcile against. A response-phase caller should type-
+// assert r.RateLimiter.(Reconciler) and call it when both a usage total
+// and the Context it was estimated under are available.
+type Reconciler interface {
+	Reconcile(ctx Context, estimatedCost, actualTotalTokens int) error
+}
+
+var _ Reconciler = (*TokenBucketLimiter)(nil)
@@ -0,0 +1,231 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Store is the pluggable persistence backend a TokenBucketLimiter draws
+// on for each bucket's state. TryConsume must be atomic with respect to
+// concurrent callers sharing the same key (across goroutines for
+// InMemoryStore, across process instances for RedisStore).
+type Store interface {
+	// TryConsume attempts to deduct cost tokens from key's bucket, which
+	// refills continuously at rate tokens/sec up to burst capacity. It
+	// returns whether the deduction succeeded, the bucket's remaining
+	// tokens afterward (floored to the nearest whole token for header
+	// display), and the time the bucket will next be full.
+	TryConsume(ctx context.Context, key string, cost, rate, burst float64, now time.Time) (allowed bool, remaining float64, resetAt time.Time, err error)
+
+	// Refund returns cost tokens to key's bucket, capped at burst, used
+	// by TokenBucketLimiter.Reconcile when the actual cost of a request
+	// turned out lower than the estimate it was charged at checkout. burst
+	// must be the same bucket capacity TryConsume was called with, so a
+	// refund can never grow a bucket past what it could ever have held.
+	Refund(ctx context.Context, key string, cost, burst float64) error
+}
+
+// bucketState is one key's token bucket: the token count as of lastRefill,
+// refilled lazily on each access rather than via a background ticker.
+type bucketState struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// InMemoryStore is a process-local Store backed by a mutex-protected map.
+// It's the default for single-instance deployments; RedisStore is the
+// multi-instance equivalent.
+type InMemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucketState
+}
+
+// NewInMemoryStore returns an empty InMemoryStore.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{buckets: make(map[string]*bucketState)}
+}
+
+func (s *InMemoryStore) TryConsume(_ context.Context, key string, cost, rate, burst float64, now time.Time) (bool, float64, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucketState{tokens: burst, lastRefill: now}
+		s.buckets[key] = b
+	}
+	refill(b, rate, burst, now)
+
+	resetAt := now
+	if b.tokens < burst && rate > 0 {
+		resetAt = now.Add(time.Duration((burst - b.tokens) / rate * float64(time.Second)))
+	}
+
+	if b.tokens < cost {
+		return false, b.tokens, resetAt, nil
+	}
+	b.tokens -= cost
+	return true, b.tokens, resetAt, nil
+}
+
+func (s *InMemoryStore) Refund(_ context.Context, key string, cost, burst float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		return nil // nothing to refund against - the bucket expired or was never charged
+	}
+	b.tokens = math.Min(burst, b.tokens+cost)
+	return nil
+}
+
+// refill advances b's token count to reflect elapsed time since
+// b.lastRefill, capped at burst.
+func refill(b *bucketState, rate, burst float64, now time.Time) {
+	if elapsed := now.Sub(b.lastRefill).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*rate)
+		b.lastRefill = now
+	}
+}
+
+// RedisClient abstracts the single Redis call RedisStore needs: running a
+// Lua script with the given keys/args and returning its result. This lets
+// RedisStore's CAS logic be exercised in tests with a fake, without this
+// package depending on a concrete Redis client library.
+type RedisClient interface {
+	Eval(ctx context.Context, script string, keys []string, args []interface{}) (interface{}, error)
+}
+
+// tokenBucketCASScript atomically refills and attempts to consume from a
+// Redis hash {tokens, last_refill_ms}, returning {allowed (0/1), remaining,
+// reset_at_ms}. Run via EVAL so the refill-then-consume read-modify-write
+// can't race across replicas/clients the way a plain GET+SET would.
+const tokenBucketCASScript = `
+local key = KEYS[1]
+local cost = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local burst = tonumber(ARGV[3])
+local now_ms = tonumber(ARGV[4])
+
+local tokens = tonumber(redis.call('HGET', key, 'tokens'))
+local last_ms = tonumber(redis.call('HGET', key, 'last_refill_ms'))
+if tokens == nil then
+  tokens = burst
+  last_ms = now_ms
+end
+
+local elapsed = math.max(0, now_ms - last_ms) / 1000.0
+tokens = math.min(burst, tokens + elapsed * rate)
+
+local allowed = 0
+if tokens >= cost then
+  tokens = tokens - cost
+  allowed = 1
+end
+
+redis.call('HSET', key, 'tokens', tokens, 'last_refill_ms', now_ms)
+redis.call('PEXPIRE', key, 3600000)
+
+local reset_ms = now_ms
+if rate > 0 and tokens < burst then
+  reset_ms = now_ms + math.floor((burst - tokens) / rate * 1000)
+end
+
+return {allowed, tostring(tokens), reset_ms}
+`
+
+// RedisStore is a Store backed by Redis, using tokenBucketCASScript so the
+// refill-and-consume check is atomic across every router instance sharing
+// the same Redis deployment.
+type RedisStore struct {
+	client RedisClient
+}
+
+// NewRedisStore wraps client in a Store.
+func NewRedisStore(client RedisClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) TryConsume(ctx context.Context, key string, cost, rate, burst float64, now time.Time) (bool, float64, time.Time, error) {
+	result, err := s.client.Eval(ctx, tokenBucketCASScript, []string{key}, []interface{}{cost, rate, burst, now.UnixMilli()})
+	if err != nil {
+		return false, 0, time.Time{}, fmt.Errorf("redis token bucket CAS for %s: %w", key, err)
+	}
+
+	fields, ok := result.([]interface{})
+	if !ok || len(fields) != 3 {
+		return false, 0, time.Time{}, fmt.Errorf("redis token bucket CAS for %s: unexpected result shape %#v", key, result)
+	}
+
+	allowed := toInt64(fields[0]) == 1
+	remaining := toFloat64(fields[1])
+	resetAt := time.UnixMilli(toInt64(fields[2]))
+	return allowed, remaining, resetAt, nil
+}
+
+// tokenBucketRefundScript refunds cost tokens (capped at burst) into an
+// already-initialized bucket, mirroring InMemoryStore.Refund's "nothing to
+// refund against - the bucket expired or was never charged" no-op: if
+// 'tokens' or 'last_refill_ms' is missing, the bucket either never existed
+// or expired via tokenBucketCASScript's PEXPIRE, and this returns without
+// writing anything. Refunding into a bucket that does exist rewrites
+// last_refill_ms (unchanged) alongside tokens and re-applies PEXPIRE, so a
+// refund never leaves a hash with 'tokens' set but 'last_refill_ms'
+// missing - tokenBucketCASScript's nil check at HGET time treats "tokens
+// present, last_ms absent" as corrupt state, not "uninitialized", and
+// errors instead of reinitializing.
+const tokenBucketRefundScript = `
+local existing_tokens = redis.call('HGET', KEYS[1], 'tokens')
+local existing_last_ms = redis.call('HGET', KEYS[1], 'last_refill_ms')
+if existing_tokens == false or existing_last_ms == false then
+  return 0
+end
+
+local tokens = tonumber(existing_tokens)
+local burst = tonumber(ARGV[2])
+tokens = math.min(burst, tokens + tonumber(ARGV[1]))
+
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'last_refill_ms', existing_last_ms)
+redis.call('PEXPIRE', KEYS[1], 3600000)
+return 1
+`
+
+func (s *RedisStore) Refund(ctx context.Context, key string, cost, burst float64) error {
+	_, err := s.client.Eval(ctx, tokenBucketRefundScript, []string{key}, []interface{}{cost, burst})
+	if err != nil {
+		return fmt.Errorf("redis token bucket refund for %s: %w", key, err)
+	}
+	return nil
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case string:
+		var parsed int64
+		_, _ = fmt.Sscanf(n, "%d", &parsed)
+		return parsed
+	default:
+		return 0
+	}
+}
+
+func toFloat64(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case string:
+		var parsed float64
+		_, _ = fmt.Sscanf(n, "%f", &parsed)
+		return parsed
+	default:
+		return 0
+	}
+}
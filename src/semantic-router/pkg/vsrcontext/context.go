@@ -0,0 +1,146 @@
+// Package vsrcontext signs and verifies the router's request-context
+// envelope: a small claim set (user, groups, decision, model, expiry) the
+// router attaches to every upstream request as x-vsr-context/
+// x-vsr-context-sig, so an upstream sidecar or audit tool can trust those
+// values came from the router's own routing/authz decision rather than
+// from a client that happened to set x-authz-user-id.
+//
+// This is a narrower trust problem than pkg/auth/identity's: identity
+// verifies a client-presented bearer token, while vsrcontext lets the
+// router assert (to something downstream of itself) facts it has already
+// established about the request. Signing uses a rotating set of HMAC keys
+// keyed by ID, the same rotation shape config.Authz is expected to load
+// from (current key first, previous keys kept around only so tokens they
+// signed keep verifying until they expire).
+package vsrcontext
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ContextHeader and SignatureHeader are the header names the envelope is
+// carried in. Both must be in CredentialResolver.HeadersToStrip() (or the
+// equivalent inbound-header scrub a caller applies before signing) so a
+// client cannot forge them by simply setting the headers itself.
+const (
+	ContextHeader   = "x-vsr-context"
+	SignatureHeader = "x-vsr-context-sig"
+)
+
+// Claims is the envelope's payload: what the router asserts about a
+// request it is forwarding upstream.
+type Claims struct {
+	UserID   string    `json:"user_id,omitempty"`
+	Groups   []string  `json:"groups,omitempty"`
+	Decision string    `json:"decision,omitempty"`
+	Model    string    `json:"model,omitempty"`
+	Expiry   time.Time `json:"exp"`
+}
+
+// Key is one HMAC signing/verification key in a rotation set.
+type Key struct {
+	// ID identifies the key in SignatureHeader, so a Verifier holding
+	// multiple keys (current plus recently-retired) knows which one to use
+	// without trial-and-error.
+	ID string
+	// Secret is the HMAC-SHA256 key material.
+	Secret []byte
+}
+
+// Signer signs Claims envelopes with the first key in Keys - by
+// convention, the active/current key. Additional entries are carried only
+// so a Verifier built from the same Keys can still verify envelopes signed
+// before a rotation.
+type Signer struct {
+	Keys []Key
+}
+
+// NewSigner builds a Signer from keys. keys must be non-empty, with the
+// currently-active key first.
+func NewSigner(keys []Key) (*Signer, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("vsrcontext: at least one signing key is required")
+	}
+	return &Signer{Keys: keys}, nil
+}
+
+// Sign encodes claims and returns the ContextHeader/SignatureHeader values
+// to attach to the outgoing request.
+func (s *Signer) Sign(claims Claims) (context string, signature string, err error) {
+	if len(s.Keys) == 0 {
+		return "", "", fmt.Errorf("vsrcontext: signer has no keys")
+	}
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return "", "", fmt.Errorf("vsrcontext: marshal claims: %w", err)
+	}
+	context = base64.RawURLEncoding.EncodeToString(body)
+
+	key := s.Keys[0]
+	mac := hmac.New(sha256.New, key.Secret)
+	mac.Write([]byte(context))
+	signature = key.ID + "." + hex.EncodeToString(mac.Sum(nil))
+	return context, signature, nil
+}
+
+// Verifier checks a Signer-produced envelope's signature and expiry.
+type Verifier struct {
+	keysByID map[string][]byte
+}
+
+// NewVerifier builds a Verifier trusting every key in keys, matched by ID.
+func NewVerifier(keys []Key) (*Verifier, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("vsrcontext: at least one verification key is required")
+	}
+	keysByID := make(map[string][]byte, len(keys))
+	for _, k := range keys {
+		keysByID[k.ID] = k.Secret
+	}
+	return &Verifier{keysByID: keysByID}, nil
+}
+
+// Verify checks context/signature (the ContextHeader/SignatureHeader
+// values from an inbound request) and returns the Claims they carry. It
+// rejects an envelope whose signature doesn't match a known key, or whose
+// claims have expired.
+func (v *Verifier) Verify(context, signature string) (Claims, error) {
+	kid, mac, ok := strings.Cut(signature, ".")
+	if !ok {
+		return Claims{}, fmt.Errorf("vsrcontext: malformed signature")
+	}
+	secret, ok := v.keysByID[kid]
+	if !ok {
+		return Claims{}, fmt.Errorf("vsrcontext: unknown signing key %q", kid)
+	}
+	wantMAC, err := hex.DecodeString(mac)
+	if err != nil {
+		return Claims{}, fmt.Errorf("vsrcontext: malformed signature: %w", err)
+	}
+
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(context))
+	if !hmac.Equal(wantMAC, h.Sum(nil)) {
+		return Claims{}, fmt.Errorf("vsrcontext: signature mismatch")
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(context)
+	if err != nil {
+		return Claims{}, fmt.Errorf("vsrcontext: malformed context: %w", err)
+	}
+	var claims Claims
+	if err := json.Unmarshal(body, &claims); err != nil {
+		return Claims{}, fmt.Errorf("vsrcontext: unmarshal claims: %w", err)
+	}
+	if !claims.Expiry.IsZero() && time.Now().After(claims.Expiry) {
+		return Claims{}, fmt.Errorf("vsrcontext: context expired at %s", claims.Expiry)
+	}
+	return claims, nil
+}
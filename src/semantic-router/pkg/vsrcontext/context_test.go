@@ -0,0 +1,85 @@
+package vsrcontext
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify_RoundTrips(t *testing.T) {
+	keys := []Key{{ID: "k1", Secret: []byte("secret-1")}}
+	signer, err := NewSigner(keys)
+	require.NoError(t, err)
+	verifier, err := NewVerifier(keys)
+	require.NoError(t, err)
+
+	claims := Claims{UserID: "alice", Groups: []string{"eng"}, Decision: "fast-path", Model: "gpt-4o", Expiry: time.Now().Add(time.Minute)}
+	context, sig, err := signer.Sign(claims)
+	require.NoError(t, err)
+
+	got, err := verifier.Verify(context, sig)
+	require.NoError(t, err)
+	assert.Equal(t, claims.UserID, got.UserID)
+	assert.Equal(t, claims.Groups, got.Groups)
+	assert.Equal(t, claims.Decision, got.Decision)
+	assert.Equal(t, claims.Model, got.Model)
+}
+
+func TestVerify_RejectsTamperedContext(t *testing.T) {
+	keys := []Key{{ID: "k1", Secret: []byte("secret-1")}}
+	signer, err := NewSigner(keys)
+	require.NoError(t, err)
+	verifier, err := NewVerifier(keys)
+	require.NoError(t, err)
+
+	context, sig, err := signer.Sign(Claims{UserID: "alice", Expiry: time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+
+	_, err = verifier.Verify(context+"tampered", sig)
+	assert.Error(t, err)
+}
+
+func TestVerify_RejectsUnknownKey(t *testing.T) {
+	signer, err := NewSigner([]Key{{ID: "k1", Secret: []byte("secret-1")}})
+	require.NoError(t, err)
+	verifier, err := NewVerifier([]Key{{ID: "k2", Secret: []byte("secret-2")}})
+	require.NoError(t, err)
+
+	context, sig, err := signer.Sign(Claims{UserID: "alice", Expiry: time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+
+	_, err = verifier.Verify(context, sig)
+	assert.Error(t, err)
+}
+
+func TestVerify_RejectsExpiredClaims(t *testing.T) {
+	keys := []Key{{ID: "k1", Secret: []byte("secret-1")}}
+	signer, err := NewSigner(keys)
+	require.NoError(t, err)
+	verifier, err := NewVerifier(keys)
+	require.NoError(t, err)
+
+	context, sig, err := signer.Sign(Claims{UserID: "alice", Expiry: time.Now().Add(-time.Minute)})
+	require.NoError(t, err)
+
+	_, err = verifier.Verify(context, sig)
+	assert.Error(t, err)
+}
+
+func TestVerifier_AcceptsPreviousKeyDuringRotation(t *testing.T) {
+	oldKey := Key{ID: "k1", Secret: []byte("secret-1")}
+	newKey := Key{ID: "k2", Secret: []byte("secret-2")}
+
+	oldSigner, err := NewSigner([]Key{oldKey})
+	require.NoError(t, err)
+	rotatedVerifier, err := NewVerifier([]Key{newKey, oldKey})
+	require.NoError(t, err)
+
+	context, sig, err := oldSigner.Sign(Claims{UserID: "alice", Expiry: time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+
+	_, err = rotatedVerifier.Verify(context, sig)
+	assert.NoError(t, err)
+}
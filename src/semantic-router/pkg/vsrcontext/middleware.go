@@ -0,0 +1,41 @@
+package vsrcontext
+
+import (
+	"context"
+	"net/http"
+)
+
+// claimsContextKey is the context.Context key ClaimsFromContext looks up.
+type claimsContextKey struct{}
+
+// Middleware wraps an upstream HTTP handler (a vLLM sidecar or Envoy
+// ext_authz-style filter written in Go) with verification of the router's
+// x-vsr-context/x-vsr-context-sig envelope: requests without a valid
+// envelope are rejected with 401 before reaching next, and requests with
+// one have their Claims attached to the request context for next to read
+// via ClaimsFromContext.
+func Middleware(v *Verifier, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxHeader := r.Header.Get(ContextHeader)
+		sigHeader := r.Header.Get(SignatureHeader)
+		if ctxHeader == "" || sigHeader == "" {
+			http.Error(w, "missing vsr context envelope", http.StatusUnauthorized)
+			return
+		}
+
+		claims, err := v.Verify(ctxHeader, sigHeader)
+		if err != nil {
+			http.Error(w, "invalid vsr context envelope: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsContextKey{}, claims)))
+	})
+}
+
+// ClaimsFromContext returns the Claims Middleware attached to ctx, and
+// whether one was present.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(Claims)
+	return claims, ok
+}
@@ -0,0 +1,53 @@
+package vsrcontext
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware_RejectsMissingEnvelope(t *testing.T) {
+	verifier, err := NewVerifier([]Key{{ID: "k1", Secret: []byte("secret")}})
+	require.NoError(t, err)
+
+	handler := Middleware(verifier, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("next should not be called without a valid envelope")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestMiddleware_AttachesClaimsForValidEnvelope(t *testing.T) {
+	keys := []Key{{ID: "k1", Secret: []byte("secret")}}
+	signer, err := NewSigner(keys)
+	require.NoError(t, err)
+	verifier, err := NewVerifier(keys)
+	require.NoError(t, err)
+
+	context, sig, err := signer.Sign(Claims{UserID: "alice", Expiry: time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+
+	var gotClaims Claims
+	var gotOK bool
+	handler := Middleware(verifier, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotClaims, gotOK = ClaimsFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	req.Header.Set(ContextHeader, context)
+	req.Header.Set(SignatureHeader, sig)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, gotOK)
+	assert.Equal(t, "alice", gotClaims.UserID)
+}